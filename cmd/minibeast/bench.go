@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/bench"
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/inference"
+	"github.com/minibeast/usb-agent/src/core/summarizer"
+)
+
+// runBench times collection, inference and crypto on this machine
+// Inference uses the configured model even when llm.enabled is false, so a
+// candidate model can be qualified before it is switched on.
+func runBench(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	configPath := fs.String("config", defaultConfigPath, "agent config file")
+	runs := fs.Int("n", 10, "runs per benchmark")
+	noModel := fs.Bool("no-model", false, "skip the inference benchmarks")
+	asJSON := fs.Bool("json", false, "print results as JSON")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *runs < 1 {
+		return fmt.Errorf("%w: -n must be at least 1", errUsage)
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	defer applyResourceLimits(cfg)()
+
+	ctx, stop := shutdownContext()
+	defer stop()
+
+	collect, facts := bench.Collection(ctx, cfg, *runs)
+	results := []bench.Result{collect}
+	if !*noModel && facts != nil {
+		engine, err := summarizer.NewEngine(cfg)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errModel, err)
+		}
+		timings, err := bench.Inference(ctx, engine, facts, *runs)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errModel, err)
+		}
+		results = append(results, timings...)
+	}
+	crypto, err := bench.Crypto(*runs)
+	if err != nil {
+		return err
+	}
+	results = append(results, crypto...)
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+	model := cfg.LLM.ModelPath
+	if cfg.LLM.Backend == config.LLMBackendOpenAI {
+		model = cfg.LLM.Remote.Model + " at " + cfg.LLM.Remote.URL
+	} else if !inference.Native {
+		model = "template engine (build with -tags llama to time a real model)"
+	}
+	fmt.Printf("%s/%s, %d CPUs, GOMAXPROCS %d, model %s\n\n",
+		runtime.GOOS, runtime.GOARCH, runtime.NumCPU(), runtime.GOMAXPROCS(0), model)
+	fmt.Printf("%-16s %5s %5s %10s %10s %10s %10s %8s\n", "benchmark", "runs", "errs", "p50", "p90", "p99", "max", "tok/s")
+	for _, r := range results {
+		tokens := "-"
+		if r.TokensPerSec > 0 {
+			tokens = fmt.Sprintf("%.1f", r.TokensPerSec)
+		}
+		fmt.Printf("%-16s %5d %5d %10s %10s %10s %10s %8s\n", r.Name, r.Runs, r.Errors,
+			roundLatency(r.P50), roundLatency(r.P90), roundLatency(r.P99), roundLatency(r.Max), tokens)
+	}
+	return nil
+}
+
+// roundLatency keeps three significant digits of a latency for the table
+func roundLatency(d time.Duration) time.Duration {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond)
+	case d >= time.Millisecond:
+		return d.Round(time.Microsecond)
+	default:
+		return d.Round(10 * time.Nanosecond)
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/i18n"
+	"github.com/minibeast/usb-agent/src/core/platform"
+	"github.com/minibeast/usb-agent/src/core/progress"
+)
+
+// runCollect runs the pipeline once into output.directory, or with -daemon
+// hands over to daemon mode
+func runCollect(args []string) error {
+	fs := flag.NewFlagSet("collect", flag.ContinueOnError)
+	configPath := fs.String("config", defaultConfigPath, "agent config file")
+	assumeYes := fs.Bool("assume-yes", false, "record consent without prompting (requires -operator or consent.operator)")
+	operator := fs.String("operator", "", "operator name or initials recorded with the consent")
+	quiet := fs.Bool("quiet", false, "print errors only")
+	verbose := fs.Bool("verbose", false, "print every stage transition and artifact path")
+	elevateRun := fs.Bool("elevate", false, "rerun elevated (sudo, macOS authorization, UAC) when a category needs it")
+	explainOnly := fs.Bool("explain", false, "print what the run would touch, write and send, then exit without collecting")
+	explainOS := fs.String("explain-os", runtime.GOOS, "platform for -explain: linux, darwin, windows or all")
+	outputRoot := fs.String("output-root", "", "base of a relative output.directory (overrides output.root)")
+	daemon := fs.Bool("daemon", false, "keep collecting into service.daemon.runs_directory until SIGTERM (see daemon)")
+	fs.Duration("interval", 0, "with -daemon: run every DURATION instead of on service.daemon.schedule")
+	fs.Duration("jitter", 0, "with -daemon: maximum random delay added to each run")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *daemon {
+		return runDaemon(daemonArgs(fs))
+	}
+	level := verbosityFrom(*quiet, *verbose)
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	if err := resolveOutputDir(cfg, *outputRoot); err != nil {
+		return err
+	}
+	if *explainOnly {
+		return explainRun(os.Stdout, cfg, *explainOS)
+	}
+	if *elevateRun {
+		// The elevated rerun takes the locks and prompts for consent itself
+		if relaunched, err := elevateIfNeeded(cfg, os.Args[1:]); relaunched {
+			return err
+		}
+	}
+	release, err := acquireRunLocks(cfg.Output.Directory)
+	if err != nil {
+		return err
+	}
+	defer release()
+	defer applyResourceLimits(cfg)()
+
+	record, err := obtainConsent(cfg, *assumeYes, *operator)
+	if err != nil {
+		return err
+	}
+	p, err := newPipeline(cfg)
+	if err != nil {
+		return err
+	}
+	defer p.close()
+	p.consent = record
+
+	ctx, stop := shutdownContext()
+	defer stop()
+
+	var view *progressView
+	if level != verbosityQuiet {
+		view = newProgressView(os.Stdout, level == verbosityVerbose)
+		ctx = progress.WithReporter(ctx, view.Report)
+	}
+	payload, paths, err := p.execute(ctx, cfg.Output.Directory)
+	if view != nil {
+		view.Close()
+	}
+
+	// Artifacts of a degraded run are listed even in quiet mode so they are
+	// not mistaken for a full run's
+	switch {
+	case err != nil:
+		for _, path := range paths {
+			fmt.Fprintln(os.Stderr, path)
+		}
+		return err
+	case level == verbosityVerbose:
+		fmt.Printf("run %s\n", payload.RunID)
+		for _, path := range paths {
+			fmt.Println(path)
+		}
+	case level == verbosityNormal:
+		fmt.Println(i18n.T("collect.done", payload.RunID, len(paths), filepath.Clean(cfg.Output.Directory)))
+	}
+	return nil
+}
+
+// daemonArgs passes the daemon flags given to collect -daemon on to runDaemon
+func daemonArgs(fs *flag.FlagSet) []string {
+	var args []string
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "config", "interval", "jitter":
+			args = append(args, "-"+f.Name, f.Value.String())
+		}
+	})
+	return args
+}
+
+// resolveOutputDir makes output.directory absolute, below the USB drive the
+// binary runs from unless override or output.root name another base
+// Paths the run prints and records then point at the stick even when the
+// agent was started from another working directory.
+func resolveOutputDir(cfg *config.Config, override string) error {
+	if override == "" {
+		override = cfg.Output.Root
+	}
+	dir, _, err := platform.NewUSBLocator().ResolveOutput(cfg.Output.Directory, override)
+	if err != nil {
+		return fmt.Errorf("%w: output.directory: %w", errConfig, err)
+	}
+	cfg.Output.Directory = dir
+	return nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/minibeast/usb-agent/src/core/i18n"
+	"github.com/minibeast/usb-agent/src/core/privacy"
+	"github.com/minibeast/usb-agent/src/core/redact"
+	"github.com/minibeast/usb-agent/src/core/remediation"
+)
+
+// runConfig dispatches config subcommands (only "validate" for now)
+func runConfig(args []string) error {
+	if len(args) == 0 || args[0] != "validate" {
+		return i18n.Errorf("config.usage", errUsage)
+	}
+	fs := flag.NewFlagSet("config validate", flag.ContinueOnError)
+	configPath := fs.String("config", defaultConfigPath, "path to config file")
+	if err := parseFlags(fs, args[1:]); err != nil {
+		return err
+	}
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	// Settings a run compiles before collecting, so typos fail here too
+	if _, err := redact.New(cfg.Output.Redact); err != nil {
+		return fmt.Errorf("%w: %w", errConfig, err)
+	}
+	if _, err := privacy.ParseKinds(cfg.Privacy.Kinds()); err != nil {
+		return fmt.Errorf("%w: privacy.pseudonymize: %w", errConfig, err)
+	}
+	if _, err := remediation.LoadOrDefault(cfg.Output.RemediationPath); err != nil {
+		return fmt.Errorf("%w: output.remediation_path: %w", errConfig, err)
+	}
+	fmt.Println(i18n.T("config.valid", *configPath))
+	return nil
+}
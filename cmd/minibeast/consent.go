@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/consent"
+	"github.com/minibeast/usb-agent/src/core/i18n"
+)
+
+// obtainConsent shows the consent banner for an attended run
+// With assumeYes the banner is printed and the acknowledgment recorded
+// without prompting; operator (or consent.operator) must then name the
+// operator. Without a terminal to prompt on, -assume-yes is required.
+func obtainConsent(cfg *config.Config, assumeYes bool, operator string) (*consent.Record, error) {
+	cats := consent.Categories(cfg)
+	if operator == "" {
+		operator = cfg.Consent.Operator
+	}
+
+	if assumeYes {
+		record, err := consent.New(operator, consent.MethodAssumeYes, cats, time.Now())
+		if err != nil {
+			return nil, i18n.Errorf("consent.assume_yes_invalid", errUsage, err)
+		}
+		fmt.Fprint(os.Stderr, consent.Banner(cats))
+		fmt.Fprintf(os.Stderr, "%s\n\n", i18n.T("consent.acknowledged", record.Operator))
+		return record, nil
+	}
+	if !isTerminal(os.Stdin) {
+		return nil, i18n.Errorf("consent.required", errUsage)
+	}
+	return consent.Prompt(os.Stdin, os.Stderr, cats, operator, time.Now)
+}
+
+// preauthorizedConsent records consent.operator for unattended runs
+// Returns nil when no operator is configured.
+func preauthorizedConsent(cfg *config.Config) *consent.Record {
+	record, err := consent.New(cfg.Consent.Operator, consent.MethodPreauthorized, consent.Categories(cfg), time.Now())
+	if err != nil {
+		return nil
+	}
+	return record
+}
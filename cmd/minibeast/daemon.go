@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/i18n"
+	"github.com/minibeast/usb-agent/src/core/scheduler"
+	"github.com/minibeast/usb-agent/src/core/service"
+)
+
+// runDaemon runs the pipeline on service.daemon.schedule (or every
+// -interval) until interrupted (or until the Service Control Manager stops
+// it, when installed as a Windows service)
+// Edits to the config file are picked up without a restart: the next run
+// uses a pipeline built from the re-validated config.
+func runDaemon(args []string) error {
+	fs := flag.NewFlagSet("daemon", flag.ContinueOnError)
+	configPath := fs.String("config", defaultConfigPath, "agent config file")
+	dir := fs.String("dir", "", "agent root to run in (relative config paths resolve against it)")
+	interval := fs.Duration("interval", 0, "run every DURATION (at least 1m) instead of on service.daemon.schedule")
+	jitter := fs.Duration("jitter", 0, "maximum random delay added to each run (overrides service.daemon.jitter_ms)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	jitterSet := false
+	fs.Visit(func(f *flag.Flag) { jitterSet = jitterSet || f.Name == "jitter" })
+	if *dir != "" {
+		if err := os.Chdir(*dir); err != nil {
+			return fmt.Errorf("%w: %w", errUsage, err)
+		}
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	dc := cfg.Service.Daemon
+	if err := overrideSchedule(&dc, *interval, *jitter, jitterSet); err != nil {
+		return err
+	}
+	schedule, err := scheduler.Parse(dc.Schedule)
+	if err != nil {
+		if *interval != 0 {
+			return fmt.Errorf("%w: -interval: %w", errUsage, err)
+		}
+		return fmt.Errorf("service.daemon.schedule: %w", err)
+	}
+	defer applyResourceLimits(cfg)()
+	p, err := newPipeline(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() { p.close() }()
+	p.consent = preauthorizedConsent(cfg)
+
+	var updates <-chan *config.Config
+	watcher, err := config.NewWatcher(*configPath, cfg, func(err error) {
+		fmt.Fprintln(os.Stderr, i18n.T("daemon.reload_rejected", err))
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("daemon.reload_unwatched", err))
+	} else {
+		updates = watcher.Updates()
+	}
+
+	// Runs never overlap, so the pipeline is swapped between them
+	job := func(ctx context.Context, run scheduler.Run) error {
+		select {
+		case next := <-updates:
+			p = reloadPipeline(p, next, cfg)
+		default:
+		}
+
+		release, err := acquireRunLocks(dc.RunsDirectory)
+		if err != nil {
+			return err
+		}
+		defer release()
+
+		payload, paths, err := p.execute(ctx, run.Dir)
+		if payload != nil {
+			fmt.Println(i18n.T("run.id", "daemon", run.Dir, payload.RunID))
+		}
+		for _, path := range paths {
+			fmt.Println(path)
+		}
+		return err
+	}
+	s, err := scheduler.New(schedule, job, scheduler.Options{
+		Jitter:  time.Duration(dc.JitterMs) * time.Millisecond,
+		RunsDir: dc.RunsDirectory,
+		Report:  reportRun("daemon"),
+	})
+	if err != nil {
+		return err
+	}
+
+	runAll := func(ctx context.Context) error {
+		if watcher == nil {
+			return s.Run(ctx)
+		}
+		ctx, cancel := context.WithCancel(ctx)
+		watched := make(chan error, 1)
+		go func() { watched <- watcher.Run(ctx) }()
+		err := s.Run(ctx)
+		cancel()
+		<-watched
+		return err
+	}
+
+	fmt.Println(i18n.T("daemon.schedule", dc.Schedule, schedule.Next(time.Now()).Format(time.RFC3339)))
+	if ok, err := service.Run(runAll); ok {
+		return err
+	}
+	ctx, stop := shutdownContext()
+	defer stop()
+	return runAll(ctx)
+}
+
+// reloadPipeline returns a pipeline built from next, closing p, or p itself
+// when the new pipeline cannot be built (a missing model or key file)
+// The schedule, jitter, runs directory and resource limits are fixed at
+// startup; changes to them are reported as needing a restart.
+func reloadPipeline(p *pipeline, next, started *config.Config) *pipeline {
+	resolveStickPaths(next)
+	if !reflect.DeepEqual(next.Service.Daemon, started.Service.Daemon) || !reflect.DeepEqual(next.Resources, started.Resources) {
+		fmt.Fprintln(os.Stderr, i18n.T("daemon.reload_restart"))
+	}
+	np, err := newPipeline(next)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("daemon.reload_rejected", err))
+		return p
+	}
+	np.consent = preauthorizedConsent(next)
+	i18n.Use(i18n.Detect(next.Locale, os.Getenv))
+	p.close()
+	fmt.Println(i18n.T("daemon.reloaded"))
+	return np
+}
+
+// overrideSchedule applies the -interval and -jitter flags to the daemon
+// settings from the config
+func overrideSchedule(dc *config.DaemonConfig, interval, jitter time.Duration, jitterSet bool) error {
+	if interval < 0 {
+		return fmt.Errorf("%w: -interval must not be negative", errUsage)
+	}
+	if interval > 0 {
+		dc.Schedule = "@every " + interval.String()
+	}
+	if jitterSet {
+		if jitter < 0 {
+			return fmt.Errorf("%w: -jitter must not be negative", errUsage)
+		}
+		dc.JitterMs = int(jitter.Milliseconds())
+	}
+	return nil
+}
+
+// reportRun prints the outcome of a scheduled or on-demand run
+func reportRun(mode string) func(scheduler.Result) {
+	return func(r scheduler.Result) {
+		switch {
+		case r.Skipped:
+			fmt.Fprintln(os.Stderr, i18n.T("run.skipped", mode, r.Run.Scheduled.Format(time.RFC3339)))
+		case r.Err != nil:
+			fmt.Fprintln(os.Stderr, i18n.T("run.failed", mode, r.Run.Dir, r.Duration.Round(time.Millisecond), r.Err))
+		default:
+			fmt.Println(i18n.T("run.completed", mode, r.Run.Dir, r.Duration.Round(time.Millisecond)))
+		}
+	}
+}
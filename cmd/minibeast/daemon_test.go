@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+)
+
+// TestOverrideSchedule verifies -interval and -jitter replace the configured
+// schedule and jitter only when given
+func TestOverrideSchedule(t *testing.T) {
+	dc := config.DaemonConfig{Schedule: "0 2 * * *", JitterMs: 600000}
+	if err := overrideSchedule(&dc, 0, 0, false); err != nil || dc.Schedule != "0 2 * * *" || dc.JitterMs != 600000 {
+		t.Errorf("no flags = %+v, %v", dc, err)
+	}
+	if err := overrideSchedule(&dc, 15*time.Minute, 0, true); err != nil || dc.Schedule != "@every 15m0s" || dc.JitterMs != 0 {
+		t.Errorf("-interval 15m -jitter 0 = %+v, %v", dc, err)
+	}
+	for _, bad := range []struct{ interval, jitter time.Duration }{{-time.Hour, 0}, {time.Hour, -time.Second}} {
+		if err := overrideSchedule(&dc, bad.interval, bad.jitter, true); !errors.Is(err, errUsage) {
+			t.Errorf("overrideSchedule(%v, %v) = %v, want a usage error", bad.interval, bad.jitter, err)
+		}
+	}
+}
+
+// TestDaemonArgs verifies collect -daemon forwards only the flags daemon mode takes
+func TestDaemonArgs(t *testing.T) {
+	fs := flag.NewFlagSet("collect", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.String("config", defaultConfigPath, "")
+	fs.Bool("daemon", false, "")
+	fs.Bool("quiet", false, "")
+	fs.Duration("interval", 0, "")
+	fs.Duration("jitter", 0, "")
+	if err := fs.Parse([]string{"-daemon", "-quiet", "-config", "site.yaml", "-interval", "30m"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(daemonArgs(fs), " "); got != "-config site.yaml -interval 30m0s" {
+		t.Errorf("daemonArgs() = %q", got)
+	}
+}
+
+// TestReloadPipeline verifies a reload swaps in a pipeline for the new
+// config, and keeps the current one when the new pipeline cannot be built
+func TestReloadPipeline(t *testing.T) {
+	started := config.Default()
+	started.LLM.Enabled = false
+	p := &pipeline{cfg: started}
+
+	next := config.Default()
+	next.LLM.Enabled = false
+	next.Collect.Processes = true
+	np := reloadPipeline(p, next, started)
+	if np == p || np.cfg != next || np.consent != nil {
+		t.Errorf("reloadPipeline() = %+v, want a pipeline for the new config", np)
+	}
+
+	broken := config.Default()
+	broken.LLM.Enabled = false
+	broken.Audit.SigningKey = filepath.Join(t.TempDir(), "missing.key")
+	if got := reloadPipeline(np, broken, started); got != np {
+		t.Errorf("reloadPipeline(missing key) = %+v, want the current pipeline", got)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/minibeast/usb-agent/src/core/crypto"
+	"github.com/minibeast/usb-agent/src/core/export"
+	"github.com/minibeast/usb-agent/src/core/i18n"
+	coreio "github.com/minibeast/usb-agent/src/core/io"
+)
+
+// runDecrypt opens encrypted artifacts (".mbe") with a recipient private key,
+// writing each next to its input without the suffix (or into -out)
+func runDecrypt(args []string) error {
+	fs := flag.NewFlagSet("decrypt", flag.ContinueOnError)
+	keyPath := fs.String("key", "", "X25519 recipient private key (PEM)")
+	outDir := fs.String("out", "", "directory for the plaintext files (default: next to each input)")
+	force := fs.Bool("force", false, "overwrite existing plaintext files")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *keyPath == "" || fs.NArg() == 0 {
+		return i18n.Errorf("decrypt.usage", errUsage, export.EncryptedSuffix)
+	}
+	key, err := crypto.LoadRecipientPrivateKey(*keyPath)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errConfig, err)
+	}
+
+	writer := coreio.NewWriter()
+	for _, path := range fs.Args() {
+		if !strings.HasSuffix(path, export.EncryptedSuffix) {
+			return i18n.Errorf("decrypt.suffix", errUsage, path, export.EncryptedSuffix)
+		}
+		out := strings.TrimSuffix(path, export.EncryptedSuffix)
+		if *outDir != "" {
+			out = filepath.Join(*outDir, filepath.Base(out))
+		}
+		if _, err := os.Stat(out); err == nil && !*force {
+			return i18n.Errorf("file.exists", out)
+		} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+
+		envelope, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		plaintext, err := crypto.Decrypt(envelope, key)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if err := writer.WriteAtomic(out, plaintext, 0600); err != nil {
+			return err
+		}
+		fmt.Println(out)
+	}
+	return nil
+}
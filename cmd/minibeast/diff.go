@@ -0,0 +1,41 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+)
+
+// runDiff prints what changed between two runs' facts: users and SSIDs
+// added or removed, OS upgrades, changed MAC addresses and so on
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the changeset as JSON")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("%w: usage: minibeast diff [-json] OLD_FACTS NEW_FACTS", errUsage)
+	}
+
+	old, err := readFacts(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	new, err := readFacts(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	d := collection.Diff(old, new)
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(d)
+	}
+	fmt.Print(d.RenderText())
+	return nil
+}
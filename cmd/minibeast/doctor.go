@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/minibeast/usb-agent/src/core/doctor"
+	"github.com/minibeast/usb-agent/src/core/i18n"
+)
+
+// runDoctor prints the pre-engagement checklist and fails if any check fails
+func runDoctor(args []string) error {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	configPath := fs.String("config", defaultConfigPath, "agent config file")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		fmt.Printf("[%s] %-20s %v\n", doctor.Fail, "config", err)
+		return fmt.Errorf("%w: config check failed", errConfig)
+	}
+	fmt.Printf("[%s] %-20s %s\n", doctor.Pass, "config", *configPath)
+
+	results := doctor.Run(context.Background(), cfg)
+	failed := 0
+	for _, r := range results {
+		fmt.Printf("[%s] %-20s %s\n", r.Status, r.Name, r.Detail)
+		if r.Status == doctor.Fail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return i18n.Errorf("doctor.failed", failed, len(results)+1)
+	}
+	fmt.Println(i18n.T("doctor.passed"))
+	return nil
+}
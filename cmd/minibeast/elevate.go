@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/consent"
+	"github.com/minibeast/usb-agent/src/core/elevate"
+	"github.com/minibeast/usb-agent/src/core/i18n"
+)
+
+// requestedCategories returns the names of the categories cfg collects
+func requestedCategories(cfg *config.Config) []string {
+	cats := consent.Categories(cfg)
+	names := make([]string, len(cats))
+	for i, c := range cats {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// elevateIfNeeded reruns the command elevated when a requested category
+// needs privileges the process lacks
+// It returns the child's exitStatus once it has run (nil for a clean exit)
+// and false when this process should collect itself: nothing was missing,
+// or elevation was declined or unavailable, in which case the run goes on
+// with the shortfall recorded as coverage notes.
+func elevateIfNeeded(cfg *config.Config, args []string) (bool, error) {
+	missing := elevate.Missing(requestedCategories(cfg), elevate.Elevated())
+	if len(missing) == 0 {
+		return false, nil
+	}
+	for _, r := range missing {
+		fmt.Fprintln(os.Stderr, i18n.T("elevate.needed", r.Note()))
+	}
+
+	code, err := elevate.Relaunch(args)
+	switch {
+	case errors.Is(err, elevate.ErrDeclined), errors.Is(err, elevate.ErrUnavailable):
+		fmt.Fprintln(os.Stderr, i18n.T("elevate.degraded", err))
+		return false, nil
+	case err != nil:
+		return true, err
+	case code != 0:
+		return true, exitStatus(code)
+	}
+	return true, nil
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/consent"
+	"github.com/minibeast/usb-agent/src/core/crash"
+	"github.com/minibeast/usb-agent/src/core/i18n"
+	"github.com/minibeast/usb-agent/src/core/platform"
+	"github.com/minibeast/usb-agent/src/core/remediation"
+)
+
+// Process exit codes (stable; wrapper scripts and RMM tools branch on them)
+const (
+	exitOK         = 0  // Run completed
+	exitFailure    = 1  // Unclassified error
+	exitUsage      = 2  // Unknown command or invalid flags
+	exitPartial    = 3  // Artifacts written, but interrupted or a category failed
+	exitValidation = 4  // Collected facts violate their invariants
+	exitSigning    = 5  // Signing key unusable or signature failed
+	exitModel      = 6  // Model failed to load, generate or parse (facts still written)
+	exitConfig     = 7  // Config missing, unparsable or invalid
+	exitDeclined   = 8  // Operator declined the consent prompt
+	exitLocked     = 9  // Another run holds the host or output directory lock
+	exitCrash      = 10 // Run panicked; crash report written with whatever was collected
+)
+
+// exitMessages explains each failing exit code to the operator, in their
+// language (i18n message IDs); the technical error stays in English for support
+var exitMessages = map[int]string{
+	exitFailure:    "exit.failure",
+	exitUsage:      "exit.usage",
+	exitPartial:    "exit.partial",
+	exitValidation: "exit.validation",
+	exitSigning:    "exit.signing",
+	exitModel:      "exit.model",
+	exitConfig:     "exit.config",
+	exitDeclined:   "exit.declined",
+	exitLocked:     "exit.locked",
+	exitCrash:      "exit.crash",
+}
+
+// exitClasses names each exit code's failure class for usage statistics
+var exitClasses = map[int]string{
+	exitOK:         "ok",
+	exitFailure:    "failure",
+	exitUsage:      "usage",
+	exitPartial:    "partial",
+	exitValidation: "validation",
+	exitSigning:    "signing",
+	exitModel:      "model",
+	exitConfig:     "config",
+	exitDeclined:   "declined",
+	exitLocked:     "locked",
+	exitCrash:      "crash",
+}
+
+// Error classes wrapped by commands so main can map them to exit codes
+var (
+	errUsage   = errors.New("usage")
+	errPartial = errors.New("partial collection")
+	errSigning = errors.New("signing failure")
+	errModel   = errors.New("model failure")
+	errConfig  = errors.New("config error")
+	errLocked  = errors.New("already running")
+)
+
+// exitStatus is the exit code of an elevated rerun of the command, which
+// has already reported its own errors
+type exitStatus int
+
+func (e exitStatus) Error() string {
+	return fmt.Sprintf("elevated run exited with status %d", int(e))
+}
+
+// exitCode maps a command error to its exit code
+// The most severe class wins when an error wraps several (e.g., a degraded
+// collection whose summarization also failed exits with exitModel).
+func exitCode(err error) int {
+	var factsErr *collection.ValidationError
+	var panicErr *crash.PanicError
+	var status exitStatus
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.As(err, &status):
+		return int(status)
+	case errors.Is(err, errUsage):
+		return exitUsage
+	case errors.Is(err, consent.ErrDeclined):
+		return exitDeclined
+	case errors.Is(err, errLocked):
+		return exitLocked
+	case errors.As(err, &panicErr):
+		return exitCrash
+	case errors.Is(err, errConfig):
+		return exitConfig
+	case errors.As(err, &factsErr):
+		return exitValidation
+	case errors.Is(err, errSigning):
+		return exitSigning
+	case errors.Is(err, errModel):
+		return exitModel
+	case errors.Is(err, errPartial):
+		return exitPartial
+	default:
+		return exitFailure
+	}
+}
+
+// parseFlags parses command flags, classifying failures as usage errors
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %w", errUsage, err)
+	}
+	return nil
+}
+
+// loadConfig loads the agent config, classifying failures as config errors
+// The config's locale then selects the language of operator messages.
+func loadConfig(path string) (*config.Config, error) {
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errConfig, err)
+	}
+	resolveStickPaths(cfg)
+	i18n.Use(i18n.Detect(cfg.Locale, os.Getenv))
+	return cfg, nil
+}
+
+// resolveStickPaths anchors config paths naming files shipped on the stick
+// to the stick root instead of the working directory
+func resolveStickPaths(cfg *config.Config) {
+	cfg.Output.RemediationPath = platform.NewUSBLocator().ResolvePath(cfg.Output.RemediationPath)
+}
+
+// summarizerError classifies a failure to build the summarizer: a broken
+// remediation file is a config error, anything else a model error
+func summarizerError(err error) error {
+	if errors.Is(err, remediation.ErrInvalid) {
+		return fmt.Errorf("%w: %w", errConfig, err)
+	}
+	return fmt.Errorf("%w: %w", errModel, err)
+}
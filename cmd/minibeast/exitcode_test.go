@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"testing"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/consent"
+	"github.com/minibeast/usb-agent/src/core/crash"
+	"github.com/minibeast/usb-agent/src/core/i18n"
+)
+
+func TestExitCode(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"ok", nil, exitOK},
+		{"help", fmt.Errorf("%w: %w", errUsage, flag.ErrHelp), exitOK},
+		{"usage", fmt.Errorf("%w: flag provided but not defined: -x", errUsage), exitUsage},
+		{"config", fmt.Errorf("%w: config validation failed", errConfig), exitConfig},
+		{"validation", fmt.Errorf("collection failed: %w", &collection.ValidationError{Field: "hostname", Reason: "must not be empty"}), exitValidation},
+		{"signing", fmt.Errorf("%w: no private key available", errSigning), exitSigning},
+		{"model", fmt.Errorf("%w: model load failed", errModel), exitModel},
+		{"interrupted", errInterrupted, exitPartial},
+		{"category", fmt.Errorf("%w: pii_info failed", errPartial), exitPartial},
+		{"model wins over partial", errors.Join(fmt.Errorf("%w: inference failed", errModel), errInterrupted), exitModel},
+		{"declined", consent.ErrDeclined, exitDeclined},
+		{"locked", fmt.Errorf("%w: another minibeast run holds /tmp/minibeast.lock", errLocked), exitLocked},
+		{"crash wins over model", errors.Join(&crash.PanicError{Where: "inference", Value: "boom"}, fmt.Errorf("%w: parse", errModel)), exitCrash},
+		{"elevated rerun", exitStatus(exitLocked), exitLocked},
+		{"other", errors.New("disk full"), exitFailure},
+	}
+	for _, tc := range cases {
+		if got := exitCode(tc.err); got != tc.want {
+			t.Errorf("%s: exitCode(%v) = %d, want %d", tc.name, tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestExitMessages_CoverEveryFailure(t *testing.T) {
+	for code := exitFailure; code <= exitCrash; code++ {
+		id, ok := exitMessages[code]
+		if !ok {
+			t.Errorf("exit code %d has no operator message", code)
+			continue
+		}
+		if msg := i18n.T(id); msg == id {
+			t.Errorf("exit code %d: message %s is not in the catalog", code, id)
+		}
+	}
+}
+
+func TestExitClasses_CoverEveryCode(t *testing.T) {
+	for code := exitOK; code <= exitCrash; code++ {
+		if exitClasses[code] == "" {
+			t.Errorf("exit code %d has no usage statistics class", code)
+		}
+	}
+}
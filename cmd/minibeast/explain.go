@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/explain"
+	"github.com/minibeast/usb-agent/src/core/plugin"
+)
+
+// explainRun writes the plan for a run of cfg on goos ("all" for every
+// platform) without collecting, locking or asking for consent
+func explainRun(w io.Writer, cfg *config.Config, goos string) error {
+	plugins, err := plugin.Load(cfg.Plugins)
+	if err != nil {
+		return fmt.Errorf("%w: plugins: %w", errConfig, err)
+	}
+	platforms := []string{goos}
+	if goos == "all" {
+		platforms = explain.Platforms
+	}
+	for i, platform := range platforms {
+		plan, err := explain.Build(cfg, platform, requestedCategories(cfg), plugins)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errUsage, err)
+		}
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		if err := plan.Render(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"crypto/ecdh"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/minibeast/usb-agent/src/core/crypto"
+	"github.com/minibeast/usb-agent/src/core/export"
+	"github.com/minibeast/usb-agent/src/core/i18n"
+)
+
+// runFlush drains the offline spool once, or until interrupted with -daemon
+func runFlush(args []string) error {
+	fs := flag.NewFlagSet("flush", flag.ContinueOnError)
+	configPath := fs.String("config", defaultConfigPath, "agent config file")
+	daemon := fs.Bool("daemon", false, "keep flushing with backoff until interrupted")
+	keyPath := fs.String("key", "", "X25519 recipient private key for payloads spooled under output.encrypt")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	var key *ecdh.PrivateKey
+	if *keyPath != "" {
+		if key, err = crypto.LoadRecipientPrivateKey(*keyPath); err != nil {
+			return fmt.Errorf("%w: %w", errConfig, err)
+		}
+	}
+	flusher, err := export.FlusherFor(cfg, key)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := shutdownContext()
+	defer stop()
+
+	if *daemon {
+		return flusher.Run(ctx, func(result export.FlushResult, err error) {
+			if result.Sent > 0 || err != nil {
+				fmt.Println(i18n.T("flush.result", result.Sent, result.Pending))
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "flush: %v\n", err)
+			}
+		})
+	}
+
+	result, err := flusher.Flush(ctx)
+	fmt.Println(i18n.T("flush.result", result.Sent, result.Pending))
+	return err
+}
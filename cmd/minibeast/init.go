@@ -0,0 +1,46 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/minibeast/usb-agent/src/core/provision"
+)
+
+// runInit walks the operator through provisioning a stick, then runs doctor on it
+func runInit(args []string) error {
+	fs := flag.NewFlagSet("init", flag.ContinueOnError)
+	target := fs.String("target", ".", "stick root directory offered as the default")
+	force := fs.Bool("force", false, "replace an existing config on the stick")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if !isTerminal(os.Stdin) {
+		return fmt.Errorf("%w: init is interactive; run it from a terminal", errUsage)
+	}
+
+	defaults := provision.Defaults()
+	defaults.Target = *target
+	plan, err := provision.Ask(os.Stdin, os.Stderr, defaults)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := shutdownContext()
+	defer stop()
+	if _, err := provision.Apply(ctx, plan, *force, os.Stderr); err != nil {
+		if errors.Is(err, provision.ErrExists) {
+			return fmt.Errorf("%w; pass -force to replace it", err)
+		}
+		return err
+	}
+
+	// Doctor resolves config paths against the stick root, as the agent does
+	if err := os.Chdir(plan.Target); err != nil {
+		return err
+	}
+	fmt.Println()
+	return runDoctor(nil)
+}
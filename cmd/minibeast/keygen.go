@@ -0,0 +1,136 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/minibeast/usb-agent/src/core/audit"
+	"github.com/minibeast/usb-agent/src/core/crypto"
+	"github.com/minibeast/usb-agent/src/core/i18n"
+)
+
+// runKeygen generates an Ed25519 signing keypair (audit.signing_key,
+// webhook and provision keys) or an X25519 recipient keypair
+// (output.recipient_keys); with -backend it creates (or opens) this
+// machine's hardware signing key and exports only its public key
+func runKeygen(args []string) error {
+	fs := flag.NewFlagSet("keygen", flag.ContinueOnError)
+	kind := fs.String("type", "ed25519", "ed25519 (signing) or x25519 (encryption recipient)")
+	out := fs.String("out", "keys/minibeast", "path prefix: writes PREFIX.pem (ed25519) or PREFIX.key (x25519), and PREFIX.pub (plus PREFIX.minisign.pub for ed25519)")
+	force := fs.Bool("force", false, "replace existing key files")
+	protect := fs.Bool("encrypt", false, "encrypt the ed25519 private key with the passphrase in "+crypto.PassphraseEnv)
+	backend := fs.String("backend", "", "tpm or secure_enclave: use the hardware key named -name and write PREFIX.pub only")
+	name := fs.String("name", "minibeast-signing", "hardware key name (output.key_name)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *backend != "" {
+		return exportHardwareKey(*backend, *name, *out+".pub", *force)
+	}
+
+	privPath, pubPath := *out+".pem", *out+".pub"
+	if *kind == "x25519" {
+		privPath = *out + ".key"
+	} else if *kind != "ed25519" {
+		return fmt.Errorf("%w: -type must be ed25519 or x25519, got %q", errUsage, *kind)
+	}
+	passphrase := os.Getenv(crypto.PassphraseEnv)
+	if *protect && *kind != "ed25519" {
+		return fmt.Errorf("%w: -encrypt applies to ed25519 signing keys only", errUsage)
+	}
+	if *protect && passphrase == "" {
+		return fmt.Errorf("%w: -encrypt needs %s to be set", errUsage, crypto.PassphraseEnv)
+	}
+	paths := []string{privPath, pubPath}
+	minisignPath := ""
+	if *kind == "ed25519" {
+		minisignPath = *out + ".minisign.pub"
+		paths = append(paths, minisignPath)
+	}
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil && !*force {
+			return i18n.Errorf("file.exists", path)
+		} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(*out), 0700); err != nil {
+		return err
+	}
+
+	var id string
+	if *kind == "x25519" {
+		key, err := crypto.GenerateRecipientKey()
+		if err != nil {
+			return err
+		}
+		if err := crypto.SaveRecipientPrivateKey(key, privPath); err != nil {
+			return err
+		}
+		if err := crypto.SaveRecipientPublicKey(key.PublicKey(), pubPath); err != nil {
+			return err
+		}
+		id = crypto.RecipientKeyID(key.PublicKey())
+	} else {
+		pair, err := crypto.GenerateKeyPair()
+		if err != nil {
+			return err
+		}
+		save := crypto.SavePrivateKey
+		if *protect {
+			save = func(key ed25519.PrivateKey, path string) error {
+				return crypto.SavePrivateKeyWithPassphrase(key, path, passphrase)
+			}
+		}
+		if err := save(pair.PrivateKey, privPath); err != nil {
+			return err
+		}
+		if err := crypto.SavePublicKey(pair.PublicKey, pubPath); err != nil {
+			return err
+		}
+		// For minisign/signify verification of output.signature_format: minisign
+		if err := os.WriteFile(minisignPath, crypto.EncodeMinisignPublicKey(pair.PublicKey), 0644); err != nil {
+			return err
+		}
+		pubPath += " (" + filepath.Base(minisignPath) + ")"
+		id = audit.KeyID(pair.PublicKey)
+	}
+	fmt.Println(i18n.T("keygen.wrote", privPath, pubPath, id, privPath))
+	return nil
+}
+
+// exportHardwareKey writes the public half of the hardware key name to pubPath
+func exportHardwareKey(backend, name, pubPath string, force bool) error {
+	if backend != crypto.KeyBackendTPM && backend != crypto.KeyBackendSecureEnclave {
+		return fmt.Errorf("%w: -backend must be tpm or secure_enclave, got %q", errUsage, backend)
+	}
+	if _, err := os.Stat(pubPath); err == nil && !force {
+		return i18n.Errorf("file.exists", pubPath)
+	}
+	key, err := crypto.OpenHardwareKey(backend, name)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errSigning, err)
+	}
+	defer key.Close()
+	pub, ok := key.Public().(*ecdsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("%w: %s key is not P-256", errSigning, backend)
+	}
+	encoded, err := crypto.EncodePublicKey(pub)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errSigning, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(pubPath), 0700); err != nil {
+		return err
+	}
+	if err := crypto.SaveECDSAPublicKey(pub, pubPath); err != nil {
+		return err
+	}
+	fmt.Println(i18n.T("keygen.wrote_hardware", pubPath, backend, name, crypto.Fingerprint(encoded)))
+	return nil
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/limits"
+)
+
+// applyResourceLimits enforces cfg.Resources for the rest of a run
+// Limits that cannot be applied are reported on stderr without failing the run;
+// the returned function restores the runtime limits.
+func applyResourceLimits(cfg *config.Config) func() {
+	applied, warnings := limits.Apply(cfg.Resources)
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "minibeast: resources: %v\n", w)
+	}
+	return func() { applied.Release() }
+}
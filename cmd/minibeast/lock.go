@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/i18n"
+	"github.com/minibeast/usb-agent/src/core/runlock"
+)
+
+// acquireRunLocks takes the per-host lock, then the lock on the output directory
+// The returned release function frees both.
+func acquireRunLocks(dir string) (func(), error) {
+	host, err := acquireLock(runlock.HostPath())
+	if err != nil {
+		return nil, err
+	}
+	out, err := acquireLock(runlock.DirPath(dir))
+	if err != nil {
+		host.Release()
+		return nil, err
+	}
+	return func() {
+		out.Release()
+		host.Release()
+	}, nil
+}
+
+// acquireLock takes one lock, classifying contention as errLocked
+func acquireLock(path string) (*runlock.Lock, error) {
+	l, err := runlock.Acquire(path)
+	var held *runlock.HeldError
+	if errors.As(err, &held) {
+		return nil, fmt.Errorf("%w: %w; wait for it to finish", errLocked, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if prev := l.Previous; prev != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("lock.stale", path, prev.PID, prev.Started.Local().Format(time.RFC3339)))
+	}
+	return l, nil
+}
@@ -0,0 +1,96 @@
+// Command minibeast is the MiniBeast USB agent
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/minibeast/usb-agent/src/core/crash"
+	"github.com/minibeast/usb-agent/src/core/i18n"
+)
+
+// defaultConfigPath is the agent config relative to the USB root
+const defaultConfigPath = "config/default.yaml"
+
+// commands maps subcommand names to their entry points
+var commands = map[string]func(args []string) error{
+	"bench":             runBench,
+	"collect":           runCollect,
+	"config":            runConfig,
+	"daemon":            runDaemon,
+	"decrypt":           runDecrypt,
+	"diff":              runDiff,
+	"doctor":            runDoctor,
+	"flush":             runFlush,
+	"init":              runInit,
+	"install-service":   runInstallService,
+	"keygen":            runKeygen,
+	"provision":         runProvision,
+	"salt":              runSalt,
+	"schema":            runSchema,
+	"serve":             runServe,
+	"summarize":         runSummarize,
+	"tui":               runTUI,
+	"uninstall-service": runUninstallService,
+	"verify":            runVerify,
+	"watch":             runWatch,
+}
+
+func main() {
+	i18n.Use(i18n.Detect("", os.Getenv)) // Refined by the locale config key in loadConfig
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(exitUsage)
+	}
+
+	run, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintln(os.Stderr, i18n.T("main.unknown_command", os.Args[1]))
+		usage()
+		os.Exit(exitUsage)
+	}
+	err := runCommand(run, os.Args[2:])
+	var status exitStatus
+	if errors.As(err, &status) {
+		os.Exit(int(status)) // The elevated run printed its own errors
+	}
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		code := exitCode(err)
+		fmt.Fprintln(os.Stderr, i18n.T("main.error", os.Args[1], err))
+		fmt.Fprintln(os.Stderr, i18n.T(exitMessages[code]))
+		os.Exit(code)
+	}
+}
+
+// runCommand calls run, turning a panic outside a run's pipeline (which
+// writes its own crash report) into an error with its stack on stderr
+func runCommand(run func(args []string) error, args []string) (err error) {
+	defer func() {
+		if pe := crash.Recover(recover(), os.Args[1]); pe != nil {
+			os.Stderr.Write(pe.Stack)
+			err = pe
+		}
+	}()
+	return run(args)
+}
+
+// usage prints the command summary
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(os.Stderr, i18n.T("usage.header"))
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, i18n.T("usage.commands"))
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-17s %s\n", name, i18n.T("usage."+name))
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, i18n.T("usage.language", i18n.T("language.name")))
+}
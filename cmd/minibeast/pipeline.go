@@ -0,0 +1,746 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/analysis"
+	"github.com/minibeast/usb-agent/src/core/audit"
+	"github.com/minibeast/usb-agent/src/core/bundle"
+	"github.com/minibeast/usb-agent/src/core/clock"
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/consent"
+	"github.com/minibeast/usb-agent/src/core/crash"
+	"github.com/minibeast/usb-agent/src/core/crypto"
+	"github.com/minibeast/usb-agent/src/core/elevate"
+	"github.com/minibeast/usb-agent/src/core/export"
+	"github.com/minibeast/usb-agent/src/core/inference"
+	coreio "github.com/minibeast/usb-agent/src/core/io"
+	"github.com/minibeast/usb-agent/src/core/logging"
+	"github.com/minibeast/usb-agent/src/core/plugin"
+	"github.com/minibeast/usb-agent/src/core/privacy"
+	"github.com/minibeast/usb-agent/src/core/progress"
+	"github.com/minibeast/usb-agent/src/core/redact"
+	"github.com/minibeast/usb-agent/src/core/report"
+	"github.com/minibeast/usb-agent/src/core/runid"
+	"github.com/minibeast/usb-agent/src/core/storage"
+	"github.com/minibeast/usb-agent/src/core/summarizer"
+	"github.com/minibeast/usb-agent/src/core/telemetry"
+	"github.com/minibeast/usb-agent/src/core/usagestats"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// pipeline runs collection, redaction, summarization (when enabled),
+// artifact output and exporter delivery, recording each run's audit file
+type pipeline struct {
+	cfg        *config.Config
+	collector  *collection.Collector
+	redactors  []*plugin.Plugin
+	pseudonyms *privacy.Pseudonymizer // nil when privacy.pseudonymize is empty
+	fields     *redact.Redactor       // output.redact selectors (nil when none)
+	builder    *summarizer.Summarizer // nil when llm.enabled is false
+	analyzer   *analysis.Analyzer     // Reports without a model (nil when analysis.enabled is false)
+	encoders   []export.Encoder
+	exporters  []export.Exporter
+	uploaders  []export.Uploader
+	spool      *export.Spool        // Holds payloads exporters could not take
+	recipients []*ecdh.PublicKey    // Seal spooled payloads under output.encrypt (nil = plaintext)
+	consent    *consent.Record      // Attached to every run's payload (nil = none given)
+	auditKey   *crypto.KeyPair      // Signs audit files, manifests and artifacts (nil = fresh key per run)
+	hwKey      crypto.HardwareKey   // Signs artifacts when output.key_backend is tpm or secure_enclave
+	keys       []usedKey            // Keys each run's output uses, for the audit file
+	stats      *usagestats.Reporter // Opt-in usage statistics (nil = disabled)
+	clock      clock.Clock          // Stamps and times runs (nil = clock.System)
+}
+
+// usedKey identifies a configured key in the audit file
+type usedKey struct {
+	role string // e.g., "recipient"
+	id   string // Key ID
+}
+
+// newPipeline wires the pipeline stages from config
+func newPipeline(cfg *config.Config) (*pipeline, error) {
+	collector, err := collection.NewCollector(cfg)
+	if err != nil {
+		return nil, err
+	}
+	encoders, err := export.EncodersForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errConfig, err)
+	}
+	exporters, err := export.ExportersFor(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errConfig, err)
+	}
+	uploaders, err := export.UploadersFor(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errConfig, err)
+	}
+	plugins, err := plugin.Load(cfg.Plugins)
+	if err != nil {
+		return nil, fmt.Errorf("%w: plugins: %w", errConfig, err)
+	}
+
+	p := &pipeline{
+		cfg:       cfg,
+		collector: collector,
+		redactors: plugin.OfKind(plugins, plugin.KindRedactor),
+		encoders:  encoders,
+		exporters: exporters,
+		uploaders: uploaders,
+		spool:     export.NewSpool(cfg.Output.Spool.Directory),
+		stats:     usagestats.New(cfg.UsageStats),
+	}
+	if err := p.loadKeys(); err != nil {
+		return nil, err
+	}
+	if err := p.loadSalt(); err != nil {
+		return nil, err
+	}
+	if len(cfg.Output.Redact) > 0 {
+		if p.fields, err = redact.New(cfg.Output.Redact); err != nil {
+			return nil, fmt.Errorf("%w: %w", errConfig, err)
+		}
+	}
+	if cfg.Analysis.Enabled {
+		if p.analyzer, err = analysis.New(cfg); err != nil {
+			return nil, fmt.Errorf("%w: %w", errConfig, err)
+		}
+	}
+	if cfg.LLM.Enabled {
+		engine, err := summarizer.NewEngine(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errModel, err)
+		}
+		if p.builder, err = summarizer.NewSummarizer(cfg, engine); err != nil {
+			return nil, summarizerError(err)
+		}
+		if rules := plugin.OfKind(plugins, plugin.KindRules); len(rules) > 0 {
+			p.builder.AddRules(plugin.Rules(rules))
+		}
+	}
+	return p, nil
+}
+
+// loadKeys loads the audit signing key and identifies the keys runs use
+func (p *pipeline) loadKeys() error {
+	if path := p.cfg.Audit.SigningKey; path != "" {
+		priv, err := crypto.LoadPrivateKeyFromEnv(path)
+		if err != nil {
+			return fmt.Errorf("%w: audit.signing_key: %w", errSigning, err)
+		}
+		p.auditKey = &crypto.KeyPair{PrivateKey: priv, PublicKey: priv.Public().(ed25519.PublicKey)}
+	}
+
+	recipients, err := export.RecipientsFor(p.cfg)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errConfig, err)
+	}
+	p.recipients = recipients
+	for _, r := range recipients {
+		p.keys = append(p.keys, usedKey{"recipient", crypto.RecipientKeyID(r)})
+	}
+	if w := p.cfg.Output.Exporters.Webhook; w.Enabled {
+		priv, err := crypto.LoadPrivateKeyFromEnv(w.PrivateKeyPath)
+		if err != nil {
+			return fmt.Errorf("%w: webhook key: %w", errConfig, err)
+		}
+		p.keys = append(p.keys, usedKey{"webhook signing", audit.KeyID(priv.Public().(ed25519.PublicKey))})
+	}
+	if backend := p.cfg.Output.KeyBackend; backend != "" && backend != crypto.KeyBackendFile {
+		key, err := crypto.OpenHardwareKey(backend, p.cfg.Output.KeyName)
+		if err != nil {
+			return fmt.Errorf("%w: output.key_backend: %w", errSigning, err)
+		}
+		p.hwKey = key
+		pub, err := crypto.EncodePublicKey(key.Public())
+		if err != nil {
+			return fmt.Errorf("%w: output.key_backend: %w", errSigning, err)
+		}
+		p.keys = append(p.keys, usedKey{backend + " signing", crypto.Fingerprint(pub)})
+	}
+	return nil
+}
+
+// loadSalt opens the engagement salt when identifiers are pseudonymized
+func (p *pipeline) loadSalt() error {
+	names := p.cfg.Privacy.Kinds()
+	if len(names) == 0 {
+		return nil
+	}
+	kinds, err := privacy.ParseKinds(names)
+	if err != nil {
+		return fmt.Errorf("%w: privacy.pseudonymize: %w", errConfig, err)
+	}
+	salt, err := privacy.LoadSalt(p.cfg.Privacy.SaltPath, os.Getenv(privacy.PassphraseEnv), p.cfg.Session.Engagement)
+	if err != nil {
+		return fmt.Errorf("%w: privacy.salt_path: %w", errConfig, err)
+	}
+	p.pseudonyms, err = privacy.New(salt, kinds)
+	if err != nil {
+		return fmt.Errorf("%w: privacy.salt_path: %w", errConfig, err)
+	}
+	return nil
+}
+
+// errInterrupted marks a run cut short by a shutdown signal
+var errInterrupted = fmt.Errorf("%w: interrupted", errPartial)
+
+// partialSuffix marks the base name of artifacts from an interrupted run
+const partialSuffix = "_partial"
+
+// run executes one collection and writes its artifacts to dir
+// Artifacts are written whenever facts exist; the returned error then
+// classifies the run for the exit code (see analyze).
+func (p *pipeline) run(ctx context.Context, dir string) ([]string, error) {
+	_, paths, err := p.execute(ctx, dir)
+	return paths, err
+}
+
+// execute is run, also returning the payload (nil when nothing was collected)
+// Every run gets a fresh run ID, carried by its facts, exporter records,
+// audit file, crash report, ledger entry and trace. A panic anywhere in the
+// run is recovered and written to a crash report alongside whatever was
+// already produced. With audit.enabled the run's audit file is written to
+// dir even when collection or redaction failed; failing to write either
+// file fails the run. With logging.file the run's diagnostic log is written
+// to dir as well, from the start of the run, and is listed in the audit file.
+// With output.sign every file the run wrote, the audit file included, gets a
+// chained "FILE.mbsig" signature and is listed last in the signed
+// "<run>.manifest.json".
+func (p *pipeline) execute(ctx context.Context, dir string) (*export.Payload, []string, error) {
+	clk := p.clock
+	if clk == nil {
+		clk = clock.System
+	}
+	started, timer := clk.Now(), clock.Start(clk)
+	runID, err := runid.New(started)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx, span := telemetry.Tracer().Start(ctx, "run", trace.WithAttributes(attribute.String("run.id", runID)))
+	defer span.End()
+
+	var runLog *logging.File
+	var logErr error
+	if p.cfg.Logging.File {
+		if runLog, logErr = logging.Create(dir, runID); logErr != nil {
+			logErr = fmt.Errorf("log: %w", logErr)
+		}
+	}
+	if l := p.runLogger(runLog); l != nil {
+		ctx = logging.With(ctx, l.With("run_id", runID))
+	}
+	log := logging.From(ctx)
+	log.Info("run started", "version", collection.Version, "os", runtime.GOOS, "arch", runtime.GOARCH,
+		"elevated", elevate.Elevated(), "output", dir)
+
+	var trail *audit.Log
+	if p.cfg.Audit.Enabled {
+		trail = audit.New(runID, clk.Now)
+		ctx = audit.WithLog(ctx, trail)
+	}
+	stage := &stageTracker{}
+	ctx = progress.AddReporter(ctx, stage.observe)
+	var manifest *coreio.ManifestWriter
+	if p.cfg.Output.Sign {
+		manifest = coreio.NewManifestWriter(dir)
+	}
+
+	var payload *export.Payload
+	var paths []string
+	var runErr error
+	func() {
+		defer func() {
+			if pe := crash.Recover(recover(), stage.last()); pe != nil {
+				runErr = errors.Join(runErr, pe)
+			}
+		}()
+		payload, runErr = p.analyze(ctx, runID)
+		if payload != nil {
+			var err error
+			if paths, err = p.write(ctx, dir, payload, manifest); err != nil {
+				runErr = err
+			}
+		}
+	}()
+
+	base, hostname := "unknown_"+started.UTC().Format("20060102T150405Z"), ""
+	if payload != nil {
+		base, hostname = artifactBase(payload.Facts), payload.Facts.Hostname
+	}
+	if len(crash.All(runErr)) > 0 {
+		path, err := p.writeCrash(dir, runID, base, runErr, payload, paths, stage.last(), clk.Now())
+		if err == nil {
+			paths = append(paths, path)
+			trail.AddFile(path)
+			runErr = errors.Join(runErr, manifest.AddFile(path))
+		} else {
+			runErr = errors.Join(runErr, fmt.Errorf("crash report: %w", err))
+		}
+	}
+	if payload != nil && len(paths) > 0 && p.cfg.Output.LedgerPath != "" {
+		if err := p.appendLedger(payload, stage.inferenceElapsed(), timer.Elapsed()); err != nil {
+			runErr = errors.Join(runErr, fmt.Errorf("ledger: %w", err))
+		}
+	}
+
+	if runErr != nil {
+		log.Warn("run finished", "elapsed_ms", timer.Elapsed().Milliseconds(), "files", len(paths), "error", runErr)
+	} else {
+		log.Info("run finished", "elapsed_ms", timer.Elapsed().Milliseconds(), "files", len(paths))
+	}
+	if runLog != nil {
+		path, err := runLog.Close(base)
+		paths = append(paths, path)
+		trail.AddFile(path)
+		if err == nil {
+			err = manifest.AddFile(path)
+		}
+		if err != nil {
+			runErr = errors.Join(runErr, fmt.Errorf("log: %w", err))
+		}
+	}
+	runErr = errors.Join(runErr, logErr)
+
+	if trail != nil {
+		record := p.consent
+		if payload != nil {
+			record = payload.Consent
+		}
+		written, err := trail.Write(dir, base, hostname, record, p.auditKey, p.cfg.Output.SignatureFormat)
+		paths = append(paths, written...)
+		for _, path := range written {
+			err = errors.Join(err, manifest.AddFile(path))
+		}
+		if err != nil {
+			runErr = errors.Join(runErr, fmt.Errorf("audit: %w", err))
+		}
+	}
+	if manifest != nil && len(paths) > 0 {
+		chain, err := p.chainSigner(hostname, clk.Now)
+		var signed []string
+		if err == nil {
+			signed, err = signChain(chain, paths)
+		}
+		paths = append(paths, signed...)
+		for _, path := range signed {
+			err = errors.Join(err, manifest.AddFile(path))
+		}
+		if err != nil {
+			runErr = errors.Join(runErr, fmt.Errorf("signatures: %w", err))
+		}
+		written, err := manifest.Write(base, runID, p.auditKey, p.cfg.Output.SignatureFormat)
+		paths = append(paths, written...)
+		if err != nil {
+			runErr = errors.Join(runErr, fmt.Errorf("manifest: %w", err))
+		}
+		if p.hwKey != nil && chain != nil {
+			// The manifest's own key is per-run here; chain it to the hardware key
+			signed, err := signChain(chain, written)
+			paths = append(paths, signed...)
+			if err != nil {
+				runErr = errors.Join(runErr, fmt.Errorf("signatures: %w", err))
+			}
+		}
+	}
+	if p.stats != nil {
+		rec := usagestats.NewRecord(collection.Version, exitClasses[exitCode(runErr)], stage.categories(),
+			stage.inferenceElapsed(), timer.Elapsed(), started)
+		// Best effort: statistics never change a run's outcome
+		_ = p.stats.Send(context.WithoutCancel(ctx), rec)
+	}
+	if runErr != nil {
+		span.SetStatus(codes.Error, runErr.Error())
+	}
+	return payload, paths, runErr
+}
+
+// runLogger returns the run's logger, writing to file (when non-nil) and,
+// with logging.stderr, to stderr; nil when neither is configured
+func (p *pipeline) runLogger(file *logging.File) *slog.Logger {
+	var ws []io.Writer
+	if file != nil {
+		ws = append(ws, file)
+	}
+	if p.cfg.Logging.Stderr {
+		ws = append(ws, os.Stderr)
+	}
+	if len(ws) == 0 {
+		return nil
+	}
+	level, _ := logging.ParseLevel(p.cfg.Logging.Level) // Checked by config validation
+	return logging.New(io.MultiWriter(ws...), p.cfg.Logging.Format, level)
+}
+
+// appendLedger records the run in output.ledger_path
+func (p *pipeline) appendLedger(payload *export.Payload, inference, total time.Duration) error {
+	run := &storage.Run{ID: payload.RunID, Facts: payload.Facts, Report: payload.Report}
+	entry, err := storage.NewLedgerEntry(run, inference, total)
+	if err != nil {
+		return err
+	}
+	return storage.NewLedger(p.cfg.Output.LedgerPath).Append(entry)
+}
+
+// artifactBase returns the file name stem of a run's artifacts:
+// "<hostname>_<UTC timestamp>", with a "_partial" suffix for interrupted runs
+func artifactBase(facts *collection.Facts) string {
+	base := facts.Hostname + "_" + facts.Timestamp.UTC().Format("20060102T150405Z")
+	if facts.Partial {
+		if facts.Hostname == "" {
+			base = "unknown" + base
+		}
+		base += partialSuffix
+	}
+	return base
+}
+
+// chainSigner returns the run's artifact signer: the hardware key when
+// output.key_backend names one, else the audit key (nil = a fresh key per run)
+func (p *pipeline) chainSigner(hostname string, now func() time.Time) (*crypto.ChainSigner, error) {
+	if p.hwKey != nil {
+		return crypto.NewKeyChainSigner(p.hwKey, collection.Version, hostname, now)
+	}
+	return crypto.NewChainSigner(p.auditKey, collection.Version, hostname, now)
+}
+
+// signChain writes a chained "FILE.mbsig" for every path, in order
+// Returns the signature paths written.
+func signChain(chain *crypto.ChainSigner, paths []string) ([]string, error) {
+	var signed []string
+	for _, path := range paths {
+		sigPath, err := chain.SignArtifact(path)
+		if err != nil {
+			return signed, err
+		}
+		signed = append(signed, sigPath)
+	}
+	return signed, nil
+}
+
+// writeCrash writes the crash report for the panics in runErr
+func (p *pipeline) writeCrash(dir, runID, base string, runErr error, payload *export.Payload, written []string, stage string, now time.Time) (string, error) {
+	state := crash.State{Stage: stage, Written: written}
+	if payload != nil {
+		state.FactsCollected = true
+		state.FailedCategories = payload.Facts.FailedCategories
+		state.ReportGenerated = payload.Report != nil
+	}
+	r := crash.NewReport(runID, runErr, p.cfg, crash.CurrentPlatform(inference.Native), state, now)
+	return r.Write(dir, base)
+}
+
+// stageTracker remembers the last pipeline stage started, for crash
+// reports, the time spent in inference, for the run ledger, and each
+// collection category's outcome, for usage statistics
+type stageTracker struct {
+	mu        sync.Mutex
+	stage     string
+	inference time.Duration
+	collected []usagestats.Category
+}
+
+func (t *stageTracker) observe(ev progress.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch {
+	case ev.State == progress.Started:
+		t.stage = ev.Stage
+	case !ev.Ended():
+	case strings.HasPrefix(ev.Stage, "inference."):
+		t.inference += ev.Elapsed
+	case strings.HasPrefix(ev.Stage, "collect."):
+		t.collected = append(t.collected, usagestats.Category{
+			Name:       strings.TrimPrefix(ev.Stage, "collect."),
+			DurationMs: ev.Elapsed.Milliseconds(),
+			Failed:     ev.State == progress.Failed,
+		})
+	}
+}
+
+// categories returns the finished collection categories
+func (t *stageTracker) categories() []usagestats.Category {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]usagestats.Category{}, t.collected...)
+}
+
+// inferenceElapsed returns the total time of the finished inference stages
+func (t *stageTracker) inferenceElapsed() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.inference
+}
+
+// last returns the last stage started ("run" before any)
+func (t *stageTracker) last() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stage == "" {
+		return "run"
+	}
+	return t.stage
+}
+
+// analyze collects, redacts and summarizes facts without writing anything
+// A nil payload means collection or redaction failed outright (facts that
+// could not be redacted are never emitted). Otherwise the error is
+// errModel when summarization failed (facts only), errPartial when a
+// category failed, and errInterrupted when ctx was cancelled mid-run
+// (the artifact base name then carries a "_partial" suffix); a category or
+// the model panicking adds a *crash.PanicError. Facts are stamped with
+// runID, the session metadata and the coverage notes after redaction, so
+// redactors cannot alter them.
+func (p *pipeline) analyze(ctx context.Context, runID string) (*export.Payload, error) {
+	log := logging.From(ctx)
+	facts, err := p.collector.CollectAll(ctx)
+	var crashErr error // Recovered panics; the run continues without their output
+	switch {
+	case facts != nil && len(crash.All(err)) > 0:
+		crashErr = err
+		log.Error("category panicked", "phase", "collect", "error", err)
+	case err != nil && (facts == nil || !facts.Partial):
+		log.Error("collection failed, nothing to write", "phase", "collect", "error", err)
+		return nil, fmt.Errorf("collection failed: %w", err)
+	}
+	if p.pseudonyms != nil {
+		// Before the redactors, which then only ever see pseudonyms
+		step := progress.Start(ctx, "pseudonymize")
+		p.pseudonyms.Facts(facts)
+		step.End(nil)
+	}
+	if p.fields != nil {
+		step := progress.Start(ctx, "redact.output.redact")
+		p.fields.Facts(facts)
+		step.End(nil)
+	}
+	if len(p.redactors) > 0 {
+		// Not cancelled with ctx, so interrupted runs can still flush partial facts
+		step := progress.Start(ctx, "redact")
+		facts, err = plugin.Redact(context.WithoutCancel(ctx), p.redactors, facts)
+		step.End(err)
+		if err != nil {
+			log.Error("redaction failed, facts withheld", "phase", "redact", "error", err)
+			return nil, fmt.Errorf("redaction failed, facts withheld: %w", err)
+		}
+	}
+	facts.RunID = runID
+	facts.Session = p.session()
+	facts.CoverageNotes = elevate.Notes(elevate.Missing(requestedCategories(p.cfg), elevate.Elevated()))
+
+	var rpt *report.Report
+	var modelErr error
+	fallback := p.builder == nil // Report from the built-in rules instead
+	if p.builder != nil && !facts.Partial {
+		if rpt, err = p.summarize(ctx, facts); err != nil {
+			if len(crash.All(err)) > 0 {
+				crashErr = errors.Join(crashErr, err)
+			} else if ctx.Err() != nil {
+				facts.Partial = true // Interrupted during inference
+			} else if p.analyzer != nil && errors.Is(err, summarizer.ErrModelUnavailable) {
+				fallback = true
+			} else {
+				modelErr = fmt.Errorf("%w: %w (facts written without a report)", errModel, err)
+			}
+			if fallback {
+				log.Warn("model unavailable, reporting from the built-in rules", "phase", "summarize", "error", err)
+			} else {
+				log.Warn("no report, writing facts only", "phase", "summarize", "error", err)
+			}
+			rpt = nil
+		}
+	}
+	if fallback && p.analyzer != nil && !facts.Partial {
+		rpt, _ = p.analyzer.BuildReport(ctx, facts) // Fails only without facts
+	}
+
+	var collectErr error
+	switch {
+	case facts.Partial:
+		collectErr = fmt.Errorf("%w, partial results written", errInterrupted)
+	case len(facts.FailedCategories) > 0:
+		collectErr = fmt.Errorf("%w: %s failed", errPartial, strings.Join(facts.FailedCategories, ", "))
+	}
+	payload := &export.Payload{RunID: runID, Facts: facts, Report: rpt, Consent: p.consent}
+	return payload, errors.Join(crashErr, modelErr, collectErr)
+}
+
+// session returns the configured engagement metadata and consenting
+// operator (nil when there are none)
+func (p *pipeline) session() *collection.Session {
+	s := &collection.Session{Engagement: p.cfg.Session.Engagement, Tags: p.cfg.Session.Tags}
+	if p.consent != nil {
+		s.Operator = p.consent.Operator
+	}
+	if s.Engagement == "" && s.Operator == "" && len(s.Tags) == 0 {
+		return nil
+	}
+	return s
+}
+
+// summarize builds the report, recovering a panic in the model (e.g., in a
+// cgo inference call) so the collected facts are still written
+func (p *pipeline) summarize(ctx context.Context, facts *collection.Facts) (rpt *report.Report, err error) {
+	defer func() {
+		if pe := crash.Recover(recover(), "inference"); pe != nil {
+			rpt, err = nil, pe
+		}
+	}()
+	return p.builder.BuildReport(ctx, facts)
+}
+
+// write encodes payload into dir under its artifact base name, listing each
+// file in manifest (nil = none), then hands it to every exporter and the
+// written files to every uploader; payloads and files that cannot be
+// delivered now are spooled for flush
+// Writes are not cancelled with ctx, so an interrupted run still flushes;
+// deliveries are, which spools them.
+func (p *pipeline) write(ctx context.Context, dir string, payload *export.Payload, manifest *coreio.ManifestWriter) ([]string, error) {
+	trail, log := audit.From(ctx), logging.From(ctx)
+	for _, k := range p.keys {
+		trail.AddKey(k.role, k.id)
+		log.Debug("key in use", "phase", "crypto", "role", k.role, "key_id", k.id)
+	}
+	writeCtx := context.WithoutCancel(ctx) // Finish writing an interrupted run
+	writer := coreio.NewWriter()
+	if p.cfg.Output.Fsync == "batch" {
+		writer = coreio.NewBatchWriter()
+	}
+	files, err := export.WriteArtifactFilesWith(writeCtx, writer, dir, artifactBase(payload.Facts), payload, p.encoders)
+	err = errors.Join(err, writer.Flush(writeCtx))
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.Path
+		trail.AddWritten(f)
+		err = errors.Join(err, manifest.AddWritten(f))
+	}
+	if err != nil {
+		trail.Add(audit.ActionWrite, dir, audit.Failed, "", err)
+		log.Error("writing artifacts failed", "phase", "output", "dir", dir, "error", err)
+		return paths, err
+	}
+	if p.cfg.Output.Bundle {
+		written, b, err := p.writeBundle(dir, payload)
+		paths = append(paths, written...)
+		for _, path := range written {
+			trail.AddFile(path)
+			err = errors.Join(err, manifest.AddFile(path))
+		}
+		if err != nil {
+			trail.Add(audit.ActionWrite, dir, audit.Failed, "", err)
+			log.Error("writing bundle failed", "phase", "output", "dir", dir, "error", err)
+			return paths, err
+		}
+		payload.Bundle = b // Attached by exporters such as smtp.attach_bundle
+	}
+	var errs []error
+	for _, e := range p.exporters {
+		spooled, err := p.spool.DeliverPayload(ctx, e, payload, p.recipients)
+		outcome := audit.OK
+		if spooled {
+			outcome = audit.Spooled
+		}
+		trail.Add(audit.ActionExport, e.Name(), outcome, "", err)
+		if err != nil {
+			log.Warn("export failed", "phase", "export", "exporter", e.Name(), "spooled", spooled, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", e.Name(), err))
+		}
+	}
+	if len(p.uploaders) > 0 {
+		b, err := uploadBundle(payload, files)
+		if err != nil {
+			return paths, errors.Join(append(errs, err)...)
+		}
+		for _, u := range p.uploaders {
+			spooled, err := p.spool.Deliver(ctx, u, b)
+			outcome := audit.OK
+			if spooled {
+				outcome = audit.Spooled
+			}
+			trail.Add(audit.ActionUpload, u.Name(), outcome, "", err)
+			if err != nil {
+				log.Warn("upload failed", "phase", "export", "uploader", u.Name(), "error", err)
+				errs = append(errs, fmt.Errorf("%s: %w", u.Name(), err))
+			} else if spooled {
+				log.Info("upload spooled", "phase", "export", "uploader", u.Name())
+			}
+		}
+	}
+	return paths, errors.Join(errs...)
+}
+
+// uploadBundle returns the run files uploaders receive: the signed .mbz
+// and its signature with output.bundle, else every artifact written
+// Complexity: O(|artifacts|)
+func uploadBundle(payload *export.Payload, files []*coreio.Written) (*export.Bundle, error) {
+	if payload.Bundle != nil {
+		return payload.Bundle, nil
+	}
+	b := &export.Bundle{Name: artifactBase(payload.Facts)}
+	for _, f := range files {
+		data, err := os.ReadFile(f.Path)
+		if err != nil {
+			return nil, fmt.Errorf("upload: %w", err)
+		}
+		b.Add(filepath.Base(f.Path), data)
+	}
+	return b, nil
+}
+
+// writeBundle writes payload as "<base>.mbz" with its detached signature
+// to dir, returning the paths written and the files as an export bundle
+// The bundle is signed with audit.signing_key, or a fresh per-run key when
+// that is unset or a hardware backend holds the signing key.
+func (p *pipeline) writeBundle(dir string, payload *export.Payload) ([]string, *export.Bundle, error) {
+	keyPair := p.auditKey
+	if keyPair == nil {
+		var err error
+		if keyPair, err = crypto.GenerateKeyPair(); err != nil {
+			return nil, nil, fmt.Errorf("bundle: %w", err)
+		}
+	}
+	base := artifactBase(payload.Facts)
+	path := filepath.Join(dir, base+bundle.Extension)
+	data, sig, err := bundle.WriteSigned(path, &bundle.Contents{
+		RunID:   payload.RunID,
+		Facts:   payload.Facts,
+		Report:  payload.Report,
+		Consent: payload.Consent,
+	}, keyPair, p.cfg.Output.SignatureFormat)
+	if err != nil {
+		return nil, nil, fmt.Errorf("bundle: %w", err)
+	}
+	b := &export.Bundle{Name: base}
+	b.Add(filepath.Base(path), data)
+	b.Add(filepath.Base(path)+bundle.SignatureSuffix, sig)
+	return []string{path, path + bundle.SignatureSuffix}, b, nil
+}
+
+// close unloads the model so cgo inference state is released before exit,
+// and releases the hardware signing key
+func (p *pipeline) close() error {
+	var err error
+	if p.hwKey != nil {
+		err = p.hwKey.Close()
+	}
+	if p.builder != nil {
+		err = errors.Join(err, p.builder.Close())
+	}
+	return err
+}
@@ -0,0 +1,540 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/analysis"
+	"github.com/minibeast/usb-agent/src/core/audit"
+	"github.com/minibeast/usb-agent/src/core/bundle"
+	"github.com/minibeast/usb-agent/src/core/clock"
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/crash"
+	"github.com/minibeast/usb-agent/src/core/crypto"
+	"github.com/minibeast/usb-agent/src/core/export"
+	"github.com/minibeast/usb-agent/src/core/inference"
+	coreio "github.com/minibeast/usb-agent/src/core/io"
+	"github.com/minibeast/usb-agent/src/core/platform/platformtest"
+	"github.com/minibeast/usb-agent/src/core/privacy"
+	"github.com/minibeast/usb-agent/src/core/runid"
+	"github.com/minibeast/usb-agent/src/core/storage"
+	"github.com/minibeast/usb-agent/src/core/summarizer"
+	"github.com/minibeast/usb-agent/src/core/usagestats"
+)
+
+// panickingEngine loads, then panics in generation like a faulting cgo wrapper
+type panickingEngine struct{}
+
+func (panickingEngine) Load(ctx context.Context) error { return nil }
+func (panickingEngine) Unload() error                  { return nil }
+func (panickingEngine) Generate(ctx context.Context, prompt string) (*inference.InferenceResult, error) {
+	var tokens []int
+	_ = tokens[0] // Index out of range
+	return nil, nil
+}
+
+func TestExecute_InferencePanicKeepsFacts(t *testing.T) {
+	cfg := config.Default()
+	dir := t.TempDir()
+	cfg.Output.LedgerPath = filepath.Join(dir, "runs.ndjson")
+	encoders, err := export.EncodersFor([]string{"json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	builder, err := summarizer.NewSummarizer(cfg, panickingEngine{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &pipeline{
+		cfg:       cfg,
+		collector: collection.NewCollectorFrom(cfg, platformtest.New()),
+		builder:   builder,
+		encoders:  encoders,
+		spool:     export.NewSpool(filepath.Join(dir, "spool")),
+	}
+
+	payload, paths, err := p.execute(context.Background(), dir)
+	if exitCode(err) != exitCrash {
+		t.Fatalf("exit code = %d (%v), want %d", exitCode(err), err, exitCrash)
+	}
+	if payload == nil || payload.Facts.Hostname != "bench-host" || payload.Report != nil {
+		t.Fatalf("payload = %+v, want facts without a report", payload)
+	}
+	if _, err := runid.Parse(payload.RunID); err != nil || payload.Facts.RunID != payload.RunID {
+		t.Fatalf("run ID = %q, facts run ID = %q", payload.RunID, payload.Facts.RunID)
+	}
+	base := artifactBase(payload.Facts)
+
+	var factsFile, crashFile, auditFile bool
+	for _, path := range paths {
+		switch {
+		case strings.HasSuffix(path, crash.Suffix):
+			crashFile = true
+		case strings.HasSuffix(path, audit.Suffix):
+			auditFile = true
+		case strings.HasSuffix(path, base+".json"):
+			factsFile = true
+		}
+	}
+	if !factsFile || !crashFile || !auditFile {
+		t.Fatalf("paths = %v, want facts, crash report and audit file", paths)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, base+crash.Suffix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"run_id": "` + payload.RunID + `"`, `"where": "inference"`, `"stage": "inference.generate"`, `"facts_collected": true`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("crash report missing %s:\n%s", want, data)
+		}
+	}
+
+	entries, err := storage.NewLedger(cfg.Output.LedgerPath).Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].RunID != payload.RunID || entries[0].ReportSHA256 != "" {
+		t.Errorf("ledger = %+v, want one facts-only entry for the run", entries)
+	}
+}
+
+func TestExecute_UsageStatsAreAnonymous(t *testing.T) {
+	cfg := config.Default()
+	dir := t.TempDir()
+	cfg.Output.LedgerPath = filepath.Join(dir, "runs.ndjson")
+	cfg.UsageStats = config.UsageStatsConfig{Enabled: true, FilePath: filepath.Join(dir, "usage.jsonl")}
+	p := &pipeline{
+		cfg:       cfg,
+		collector: collection.NewCollectorFrom(cfg, platformtest.New()),
+		spool:     export.NewSpool(filepath.Join(dir, "spool")),
+		stats:     usagestats.New(cfg.UsageStats),
+	}
+
+	payload, _, err := p.execute(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("execute() failed: %v", err)
+	}
+	data, err := os.ReadFile(cfg.UsageStats.FilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rec usagestats.Record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		t.Fatalf("usage file is not one record: %v\n%s", err, data)
+	}
+	if rec.Outcome != "ok" || rec.Version != collection.Version || len(rec.Categories) != len(requestedCategories(cfg)) {
+		t.Errorf("record = %+v, want an ok run over every requested category", rec)
+	}
+	for _, secret := range []string{payload.Facts.Hostname, payload.Facts.HardwareUUID, payload.RunID} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("usage record contains %q:\n%s", secret, data)
+		}
+	}
+}
+
+// TestExecute_RuleFallback verifies the built-in rules report when there is
+// no model, and when the model fails to load
+func TestExecute_RuleFallback(t *testing.T) {
+	cfg := config.Default()
+	dir := t.TempDir()
+	cfg.Output.LedgerPath = filepath.Join(dir, "runs.ndjson")
+	encoders, err := export.EncodersFor([]string{"json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	analyzer, err := analysis.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	missing := inference.NewFakeEngine()
+	missing.LoadErr = errors.New("models/tinyllama.gguf: no such file")
+	builder, err := summarizer.NewSummarizer(cfg, missing)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, builder := range map[string]*summarizer.Summarizer{"llm disabled": nil, "model missing": builder} {
+		p := &pipeline{
+			cfg:       cfg,
+			collector: collection.NewCollectorFrom(cfg, platformtest.New()),
+			builder:   builder,
+			analyzer:  analyzer,
+			encoders:  encoders,
+			spool:     export.NewSpool(filepath.Join(dir, "spool")),
+		}
+		payload, _, err := p.execute(context.Background(), filepath.Join(dir, strings.ReplaceAll(name, " ", "-")))
+		if err != nil {
+			t.Fatalf("%s: execute() failed: %v", name, err)
+		}
+		if payload.Report == nil || !strings.Contains(payload.Report.RenderText(), "built-in rules (no model)") {
+			t.Errorf("%s: report = %+v, want the rule-based report", name, payload.Report)
+		}
+	}
+}
+
+// TestExecute_Bundle verifies output.bundle writes a verifiable .mbz with
+// its detached signature, listed in the run manifest and attached to the payload
+func TestExecute_Bundle(t *testing.T) {
+	cfg := config.Default()
+	cfg.Output.Bundle = true
+	cfg.Output.LedgerPath = ""
+	cfg.Audit.Enabled = false
+	encoders, err := export.EncodersFor([]string{"json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	analyzer, err := analysis.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &pipeline{
+		cfg:       cfg,
+		collector: collection.NewCollectorFrom(cfg, platformtest.New()),
+		analyzer:  analyzer,
+		encoders:  encoders,
+		spool:     export.NewSpool(filepath.Join(t.TempDir(), "spool")),
+	}
+	dir := t.TempDir()
+	payload, paths, err := p.execute(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("execute() failed: %v", err)
+	}
+
+	var archive string
+	for _, path := range paths {
+		if strings.HasSuffix(path, bundle.Extension) {
+			archive = path
+		}
+	}
+	if archive == "" || !slices.Contains(paths, archive+bundle.SignatureSuffix) {
+		t.Fatalf("paths = %v, want the bundle and its signature", paths)
+	}
+	v, err := bundle.VerifyBundle(archive, nil)
+	if err != nil {
+		t.Fatalf("VerifyBundle() failed: %v", err)
+	}
+	if v.ReportText == nil || v.Metadata.RunID != payload.RunID {
+		t.Errorf("bundle = %+v, want the run's report", v.Metadata)
+	}
+	if payload.Bundle == nil || len(payload.Bundle.Files) != 2 {
+		t.Errorf("payload.Bundle = %+v", payload.Bundle)
+	}
+	if err := verifyFile(archive, nil); err != nil {
+		t.Errorf("verify %s: %v", archive, err)
+	}
+}
+
+// TestExecute_Upload verifies the run's artifacts reach an uploader, and are
+// spooled for flush while the server is unreachable
+func TestExecute_Upload(t *testing.T) {
+	var mu sync.Mutex
+	received := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, r.URL.Path)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	cfg := config.Default()
+	cfg.Output.LedgerPath = ""
+	cfg.Output.Upload.HTTPS = config.HTTPSUploadConfig{Enabled: true, URL: server.URL, AllowHTTP: true, TimeoutMs: 1000}
+	encoders, err := export.EncodersFor([]string{"json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	uploaders, err := export.UploadersFor(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spool := export.NewSpool(filepath.Join(t.TempDir(), "spool"))
+	p := &pipeline{
+		cfg:       cfg,
+		collector: collection.NewCollectorFrom(cfg, platformtest.New()),
+		encoders:  encoders,
+		uploaders: uploaders,
+		spool:     spool,
+	}
+	payload, _, err := p.execute(context.Background(), t.TempDir())
+	if err != nil {
+		t.Fatalf("execute() failed: %v", err)
+	}
+	base := artifactBase(payload.Facts)
+	if len(received) != 1 || received[0] != "/"+base+"/"+base+".json" {
+		t.Errorf("received = %v", received)
+	}
+
+	server.Close()
+	if _, _, err := p.execute(context.Background(), t.TempDir()); err != nil {
+		t.Fatalf("offline execute() failed: %v", err)
+	}
+	if pending, _ := spool.Pending("https"); len(pending) != 1 {
+		t.Errorf("pending = %v, want the offline run spooled", pending)
+	}
+}
+
+// TestExecute_BatchFsync verifies batched syncing still leaves every artifact in place
+func TestExecute_BatchFsync(t *testing.T) {
+	cfg := config.Default()
+	cfg.LLM.Enabled = false
+	cfg.Output.Fsync = "batch"
+	dir := t.TempDir()
+	cfg.Output.LedgerPath = filepath.Join(dir, "runs.ndjson")
+	encoders, err := export.EncodersFor([]string{"json", "jsonl", "csv"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &pipeline{
+		cfg:       cfg,
+		collector: collection.NewCollectorFrom(cfg, platformtest.New()),
+		encoders:  encoders,
+		spool:     export.NewSpool(filepath.Join(dir, "spool")),
+	}
+
+	_, paths, err := p.execute(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("execute() failed: %v", err)
+	}
+	if len(paths) < 3 {
+		t.Fatalf("paths = %v, want at least the three artifacts", paths)
+	}
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("artifact missing after the run: %v", err)
+		}
+	}
+	if tmp, _ := filepath.Glob(filepath.Join(dir, "*.tmp")); len(tmp) > 0 {
+		t.Errorf("temp files left behind: %v", tmp)
+	}
+}
+
+// TestExecute_RunLog verifies the run log names failed categories and is
+// listed in the audit file under the artifact base name
+func TestExecute_RunLog(t *testing.T) {
+	cfg := config.Default()
+	cfg.LLM.Enabled = false
+	cfg.Logging.Format = "json"
+	dir := t.TempDir()
+	cfg.Output.LedgerPath = filepath.Join(dir, "runs.ndjson")
+	encoders, err := export.EncodersFor([]string{"json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake := platformtest.New()
+	fake.Err = map[platformtest.Category]error{platformtest.DiskInfo: errors.New("manage-bde: permission denied")}
+	p := &pipeline{
+		cfg:       cfg,
+		collector: collection.NewCollectorFrom(cfg, fake),
+		encoders:  encoders,
+		spool:     export.NewSpool(filepath.Join(dir, "spool")),
+	}
+
+	payload, paths, err := p.execute(context.Background(), dir)
+	if exitCode(err) != exitPartial {
+		t.Fatalf("execute() = %v, want a partial run", err)
+	}
+	logPath := filepath.Join(dir, artifactBase(payload.Facts)+".log")
+	if !slices.Contains(paths, logPath) {
+		t.Errorf("paths = %v, want %s", paths, logPath)
+	}
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var failed bool
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("log line %q: %v", line, err)
+		}
+		if rec["run_id"] != payload.RunID {
+			t.Errorf("record without the run ID: %v", rec)
+		}
+		if rec["stage"] == "collect.disk_info" && rec["level"] == "WARN" {
+			failed = strings.Contains(rec["error"].(string), "permission denied")
+		}
+	}
+	if !failed {
+		t.Errorf("log does not name the failed category:\n%s", data)
+	}
+
+	trail, err := os.ReadFile(filepath.Join(dir, artifactBase(payload.Facts)+audit.Suffix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(trail), filepath.Base(logPath)) {
+		t.Error("audit file does not list the run log")
+	}
+}
+
+// TestExecute_Manifest verifies the signed manifest lists every other file
+// the run wrote and verifies against them
+func TestExecute_Manifest(t *testing.T) {
+	cfg := config.Default()
+	cfg.LLM.Enabled = false
+	dir := t.TempDir()
+	cfg.Output.LedgerPath = filepath.Join(dir, "runs.ndjson")
+	encoders, err := export.EncodersFor([]string{"json", "csv"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &pipeline{
+		cfg:       cfg,
+		collector: collection.NewCollectorFrom(cfg, platformtest.New()),
+		encoders:  encoders,
+		spool:     export.NewSpool(filepath.Join(dir, "spool")),
+	}
+
+	_, paths, err := p.execute(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("execute() failed: %v", err)
+	}
+	if len(paths) < 2 || !strings.HasSuffix(paths[len(paths)-2], coreio.ManifestSuffix) {
+		t.Fatalf("paths = %v, want the manifest last", paths)
+	}
+	manifest, err := coreio.VerifyManifest(paths[len(paths)-2], nil)
+	if err != nil {
+		t.Fatalf("VerifyManifest() failed: %v", err)
+	}
+	if len(manifest.Files) != len(paths)-2 {
+		t.Errorf("manifest lists %d files, want %d", len(manifest.Files), len(paths)-2)
+	}
+	for i, f := range manifest.Files {
+		if f.Name != filepath.Base(paths[i]) {
+			t.Errorf("files[%d] = %s, want %s", i, f.Name, filepath.Base(paths[i]))
+		}
+	}
+	headers, err := crypto.VerifyBundle(dir, nil)
+	if err != nil {
+		t.Fatalf("VerifyBundle() failed: %v", err)
+	}
+	if len(headers) != len(manifest.Files)/2 || headers[0].Hostname != "bench-host" {
+		t.Errorf("VerifyBundle() = %d headers (%+v), want %d", len(headers), headers[0], len(manifest.Files)/2)
+	}
+
+	cfg.Output.Sign = false
+	_, paths, err = p.execute(context.Background(), t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range paths {
+		if strings.Contains(path, coreio.ManifestSuffix) {
+			t.Errorf("manifest written with output.sign false: %s", path)
+		}
+	}
+}
+
+// TestExecute_Pseudonymize verifies identifiers are replaced before anything is written
+func TestExecute_Pseudonymize(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.Default()
+	cfg.LLM.Enabled = false
+	cfg.Session.Engagement = "ENG-1"
+	cfg.Privacy.Pseudonymize = []string{"hostname"}
+	cfg.Privacy.SaltPath = filepath.Join(dir, "engagement.salt")
+	cfg.Output.LedgerPath = filepath.Join(dir, "runs.ndjson")
+	t.Setenv(privacy.PassphraseEnv, "passphrase")
+	salt, err := privacy.NewSalt()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := privacy.SaveSalt(cfg.Privacy.SaltPath, salt, "passphrase", "ENG-1"); err != nil {
+		t.Fatal(err)
+	}
+	p := &pipeline{
+		cfg:       cfg,
+		collector: collection.NewCollectorFrom(cfg, platformtest.New()),
+		spool:     export.NewSpool(filepath.Join(dir, "spool")),
+	}
+	if err := p.loadSalt(); err != nil {
+		t.Fatal(err)
+	}
+
+	payload, _, err := p.execute(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("execute() failed: %v", err)
+	}
+	if got := payload.Facts.Hostname; got == "bench-host" || !strings.HasPrefix(got, "host-") {
+		t.Errorf("Hostname = %q, want a pseudonym", got)
+	}
+
+	t.Setenv(privacy.PassphraseEnv, "wrong")
+	if err := p.loadSalt(); !errors.Is(err, errConfig) {
+		t.Errorf("wrong passphrase: %v, want a config error", err)
+	}
+}
+
+// TestLoadKeys_ProtectedSigningKey verifies a passphrase-protected audit
+// key opens with the passphrase from the environment and fails without it
+func TestLoadKeys_ProtectedSigningKey(t *testing.T) {
+	cfg := config.Default()
+	cfg.Audit.SigningKey = filepath.Join(t.TempDir(), "audit.pem")
+	pair, err := crypto.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := crypto.SavePrivateKeyWithPassphrase(pair.PrivateKey, cfg.Audit.SigningKey, "passphrase"); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(crypto.PassphraseEnv, "passphrase")
+	p := &pipeline{cfg: cfg}
+	if err := p.loadKeys(); err != nil {
+		t.Fatal(err)
+	}
+	if !p.auditKey.PublicKey.Equal(pair.PublicKey) {
+		t.Error("loaded a different audit key")
+	}
+
+	t.Setenv(crypto.PassphraseEnv, "")
+	if err := (&pipeline{cfg: cfg}).loadKeys(); !errors.Is(err, errSigning) || !errors.Is(err, crypto.ErrKeyEncrypted) {
+		t.Errorf("no passphrase: %v, want a signing error", err)
+	}
+}
+
+// TestExecute_InjectedClock verifies the run ID and artifact names come from the pipeline clock
+func TestExecute_InjectedClock(t *testing.T) {
+	cfg := config.Default()
+	cfg.LLM.Enabled = false
+	dir := t.TempDir()
+	cfg.Output.LedgerPath = filepath.Join(dir, "runs.ndjson")
+	encoders, err := export.EncodersFor([]string{"json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	collector := collection.NewCollectorFrom(cfg, platformtest.New())
+	collector.SetClock(fake)
+	p := &pipeline{
+		cfg:       cfg,
+		collector: collector,
+		encoders:  encoders,
+		spool:     export.NewSpool(filepath.Join(dir, "spool")),
+		clock:     fake,
+	}
+
+	payload, paths, err := p.execute(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("execute() failed: %v", err)
+	}
+	if at, err := runid.Parse(payload.RunID); err != nil || !at.Equal(start) {
+		t.Errorf("run ID time = %v (%v), want %v", at, err, start)
+	}
+	if want := filepath.Join(dir, "bench-host_20260302T093000Z.json"); len(paths) == 0 || paths[0] != want {
+		t.Errorf("paths = %v, want %s first", paths, want)
+	}
+}
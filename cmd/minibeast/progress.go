@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattn/go-isatty"
+
+	"github.com/minibeast/usb-agent/src/core/i18n"
+	"github.com/minibeast/usb-agent/src/core/progress"
+)
+
+// spinnerFrames animate running stages on a terminal
+var spinnerFrames = []string{"|", "/", "-", `\`}
+
+// stageLabel returns the operator-readable name of stage
+// Labels are i18n messages "stage.<stage>"; unknown stages show as is.
+func stageLabel(stage string) string {
+	if label := i18n.T("stage." + stage); label != "stage."+stage {
+		return label
+	}
+	if name, ok := strings.CutPrefix(stage, "redact."); ok {
+		return "  " + name // One redaction plugin, under "Redaction plugins"
+	}
+	return stage
+}
+
+// verbosity selects how much the CLI prints
+type verbosity int
+
+const (
+	verbosityQuiet verbosity = iota
+	verbosityNormal
+	verbosityVerbose
+)
+
+// verbosityFrom resolves -quiet/-verbose (quiet wins)
+func verbosityFrom(quiet, verbose bool) verbosity {
+	switch {
+	case quiet:
+		return verbosityQuiet
+	case verbose:
+		return verbosityVerbose
+	default:
+		return verbosityNormal
+	}
+}
+
+// stageLine is the display state of one stage
+type stageLine struct {
+	stage string
+	start time.Time
+	event progress.Event
+	ended bool
+}
+
+// progressView renders progress events as a live checklist
+// On a terminal every stage keeps one line that is redrawn in place; on a
+// pipe or file only completed stages are printed, one line each.
+type progressView struct {
+	out     io.Writer
+	tty     bool
+	verbose bool
+
+	mu    sync.Mutex
+	lines []*stageLine
+	drawn int
+	frame int
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// newProgressView starts the renderer; call Close to stop it
+func newProgressView(out *os.File, verbose bool) *progressView {
+	v := &progressView{out: out, tty: isTerminal(out), verbose: verbose}
+	if v.tty {
+		v.stop, v.done = make(chan struct{}), make(chan struct{})
+		go v.animate()
+	}
+	return v
+}
+
+// isTerminal reports whether f is an interactive terminal
+// A character-device check is not enough: /dev/null and NUL are devices too.
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// Report is the progress.Reporter
+func (v *progressView) Report(ev progress.Event) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	var line *stageLine
+	for _, l := range v.lines {
+		if l.stage == ev.Stage {
+			line = l
+		}
+	}
+	if line == nil {
+		line = &stageLine{stage: ev.Stage, start: time.Now()}
+		v.lines = append(v.lines, line)
+	}
+	line.event = ev
+	line.ended = ev.Ended()
+
+	switch {
+	case v.tty:
+		v.redraw()
+	case line.ended || v.verbose && ev.State == progress.Started:
+		fmt.Fprintln(v.out, v.render(line))
+	}
+}
+
+// Close stops the animation and leaves the final state on screen
+func (v *progressView) Close() {
+	if v.tty {
+		close(v.stop)
+		<-v.done
+	}
+}
+
+// animate advances spinners and elapsed timers while stages run
+func (v *progressView) animate() {
+	defer close(v.done)
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-v.stop:
+			return
+		case <-ticker.C:
+			v.mu.Lock()
+			v.frame++
+			v.redraw()
+			v.mu.Unlock()
+		}
+	}
+}
+
+// redraw rewrites every stage line in place (caller holds mu)
+func (v *progressView) redraw() {
+	var b strings.Builder
+	if v.drawn > 0 {
+		fmt.Fprintf(&b, "\x1b[%dA", v.drawn) // Cursor up to the first stage line
+	}
+	for _, line := range v.lines {
+		b.WriteString("\x1b[2K" + v.render(line) + "\n")
+	}
+	v.drawn = len(v.lines)
+	io.WriteString(v.out, b.String())
+}
+
+// render formats one stage line
+func (v *progressView) render(line *stageLine) string {
+	label := stageLabel(line.stage)
+
+	ev := line.event
+	switch ev.State {
+	case progress.Started, progress.Running:
+		mark := ">"
+		if v.tty {
+			mark = spinnerFrames[v.frame%len(spinnerFrames)]
+		}
+		text := fmt.Sprintf("  %s %-24s %s", mark, label, time.Since(line.start).Round(100*time.Millisecond))
+		if ev.State == progress.Running && ev.Tokens > 0 {
+			text += "  " + i18n.T("progress.generating", ev.Tokens)
+		}
+		return text
+	case progress.Failed:
+		return fmt.Sprintf("  x %-24s %s: %v", label, ev.Elapsed.Round(time.Millisecond), ev.Err)
+	default:
+		text := fmt.Sprintf("  + %-24s %s", label, ev.Elapsed.Round(time.Millisecond))
+		if ev.Tokens > 0 && ev.Elapsed > 0 {
+			text += "  " + i18n.T("progress.tokens", ev.Tokens, float64(ev.Tokens)/ev.Elapsed.Seconds())
+		}
+		return text
+	}
+}
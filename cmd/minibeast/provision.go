@@ -0,0 +1,94 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/minibeast/usb-agent/src/core/crypto"
+	"github.com/minibeast/usb-agent/src/core/i18n"
+	"github.com/minibeast/usb-agent/src/core/provision"
+)
+
+// runProvision builds identical sticks from a manifest, or verifies built ones
+func runProvision(args []string) error {
+	fs := flag.NewFlagSet("provision", flag.ContinueOnError)
+	manifestPath := fs.String("manifest", "provision.yaml", "provisioning manifest")
+	signKey := fs.String("sign-key", "", "Ed25519 private key signing "+provision.SumsFile)
+	pubKey := fs.String("pubkey", "", "with -verify, Ed25519 public key "+provision.SignatureFile+" must verify against")
+	verify := fs.Bool("verify", false, "only verify the given sticks against their "+provision.SumsFile)
+	force := fs.Bool("force", false, "replace existing sticks")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, i18n.T("provision.usage"))
+		fs.PrintDefaults()
+	}
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	targets := fs.Args()
+	if len(targets) == 0 {
+		fs.Usage()
+		return i18n.Errorf("provision.no_target", errUsage)
+	}
+
+	if *verify {
+		return verifySticks(targets, *pubKey)
+	}
+
+	m, err := provision.LoadManifest(*manifestPath)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errConfig, err)
+	}
+	var signer *crypto.KeyPair
+	if *signKey != "" {
+		priv, err := crypto.LoadPrivateKeyFromEnv(*signKey)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errSigning, err)
+		}
+		signer = &crypto.KeyPair{PrivateKey: priv, PublicKey: priv.Public().(ed25519.PublicKey)}
+	}
+
+	ctx, stop := shutdownContext()
+	defer stop()
+	for i, target := range targets {
+		fmt.Fprintln(os.Stderr, i18n.T("provision.building", target))
+		if err := provision.Build(ctx, m, target, signer, *force, os.Stderr); err != nil {
+			return fmt.Errorf("%s: %w", target, err)
+		}
+		// Later sticks copy the verified model instead of downloading it again
+		if i == 0 && m.Model != nil {
+			m.Model.Source = filepath.Join(target, filepath.FromSlash(m.Model.StickPath()))
+		}
+	}
+	fmt.Println(i18n.T("provision.built", len(targets)))
+	return nil
+}
+
+// verifySticks checks each target against its sums file
+func verifySticks(targets []string, pubKeyPath string) error {
+	var trusted ed25519.PublicKey
+	if pubKeyPath != "" {
+		pub, err := crypto.LoadPublicKey(pubKeyPath)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errSigning, err)
+		}
+		trusted = pub
+	}
+
+	failed := 0
+	for _, target := range targets {
+		n, err := provision.Verify(target, trusted)
+		if err != nil {
+			fmt.Println(i18n.T("provision.verify_fail", target, err))
+			failed++
+			continue
+		}
+		fmt.Println(i18n.T("provision.verify_ok", target, n))
+	}
+	if failed > 0 {
+		return i18n.Errorf("provision.verify_failed", failed, len(targets))
+	}
+	return nil
+}
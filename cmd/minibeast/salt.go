@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/minibeast/usb-agent/src/core/i18n"
+	"github.com/minibeast/usb-agent/src/core/privacy"
+)
+
+// runSalt creates the engagement's sealed pseudonymization salt, or looks
+// up the pseudonym of one identifier under it
+func runSalt(args []string) error {
+	fs := flag.NewFlagSet("salt", flag.ContinueOnError)
+	configPath := fs.String("config", defaultConfigPath, "path to config file")
+	out := fs.String("out", "", "salt file to create (default privacy.salt_path)")
+	force := fs.Bool("force", false, "replace an existing salt file (pseudonyms from it stop joining)")
+	lookup := fs.String("lookup", "", "print the pseudonym of KIND:VALUE, e.g. username:alice")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	path := *out
+	if path == "" {
+		path = cfg.Privacy.SaltPath
+	}
+	if path == "" {
+		return fmt.Errorf("%w: no salt file (set privacy.salt_path or -out)", errUsage)
+	}
+	passphrase := os.Getenv(privacy.PassphraseEnv)
+	if passphrase == "" {
+		return fmt.Errorf("%w: %s is not set", errConfig, privacy.PassphraseEnv)
+	}
+	engagement := cfg.Session.Engagement
+
+	if *lookup != "" {
+		name, value, ok := strings.Cut(*lookup, ":")
+		kinds, err := privacy.ParseKinds([]string{name})
+		if !ok || err != nil {
+			return fmt.Errorf("%w: -lookup wants KIND:VALUE with KIND one of username, hostname, mac, serial", errUsage)
+		}
+		salt, err := privacy.LoadSalt(path, passphrase, engagement)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errConfig, err)
+		}
+		p, err := privacy.New(salt, kinds)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errConfig, err)
+		}
+		fmt.Println(p.Pseudonym(kinds[0], value))
+		return nil
+	}
+
+	if _, err := os.Stat(path); err == nil && !*force {
+		return i18n.Errorf("file.exists", path)
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	salt, err := privacy.NewSalt()
+	if err != nil {
+		return err
+	}
+	if err := privacy.SaveSalt(path, salt, passphrase, engagement); err != nil {
+		return err
+	}
+	fmt.Println(i18n.T("salt.wrote", path, engagement))
+	return nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/minibeast/usb-agent/src/core/i18n"
+	coreio "github.com/minibeast/usb-agent/src/core/io"
+	"github.com/minibeast/usb-agent/src/core/schema"
+)
+
+// runSchema prints one published JSON Schema, or writes all of them to a directory
+func runSchema(args []string) error {
+	fs := flag.NewFlagSet("schema", flag.ContinueOnError)
+	name := fs.String("name", "", "print a single schema to stdout (facts, report, bundle-metadata, ...)")
+	outDir := fs.String("out", "schemas", "directory to write every schema into")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *name != "" {
+		s, err := schema.Lookup(*name)
+		if err != nil {
+			return err
+		}
+		data, err := schema.Marshal(s)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(append(data, '\n'))
+		return err
+	}
+
+	writer := coreio.NewWriter()
+	for _, doc := range schema.All() {
+		data, err := schema.Marshal(doc.Schema)
+		if err != nil {
+			return fmt.Errorf("%s: %w", doc.Name, err)
+		}
+		path := filepath.Join(*outDir, doc.FileName())
+		if err := writer.WriteJSON(path, data); err != nil {
+			return err
+		}
+		fmt.Println(path)
+	}
+	fmt.Println(i18n.T("schema.wrote", len(schema.All()), schema.SchemaVersion))
+	return nil
+}
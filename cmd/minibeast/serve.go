@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/minibeast/usb-agent/src/core/export"
+	"github.com/minibeast/usb-agent/src/core/i18n"
+	"github.com/minibeast/usb-agent/src/core/server"
+	"github.com/minibeast/usb-agent/src/core/service"
+)
+
+// runServe runs the resident agent: the pipeline on service.daemon.schedule,
+// with the latest facts and report served over the local REST API (and gRPC
+// with service.server.grpc) until interrupted
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	configPath := fs.String("config", defaultConfigPath, "agent config file")
+	dir := fs.String("dir", "", "agent root to run in (relative config paths resolve against it)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *dir != "" {
+		if err := os.Chdir(*dir); err != nil {
+			return fmt.Errorf("%w: %w", errUsage, err)
+		}
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	sc := cfg.Service
+	if sc.REST.AuthToken == "" {
+		return fmt.Errorf("%w: service.rest.auth_token must be set to serve", errConfig)
+	}
+	defer applyResourceLimits(cfg)()
+	p, err := newPipeline(cfg)
+	if err != nil {
+		return err
+	}
+	defer p.close()
+	p.consent = preauthorizedConsent(cfg)
+
+	job := func(ctx context.Context, dir string) (*export.Payload, error) {
+		release, err := acquireRunLocks(sc.Daemon.RunsDirectory)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		payload, paths, err := p.execute(ctx, dir)
+		if payload != nil {
+			fmt.Println(i18n.T("run.id", "serve", dir, payload.RunID))
+		}
+		for _, path := range paths {
+			fmt.Println(path)
+		}
+		return payload, err
+	}
+	opts := server.Options{Report: reportRun("serve")}
+	if p.builder != nil {
+		opts.Builder = p.builder // Only when set: a nil *Summarizer is a non-nil interface
+	} else if p.analyzer != nil {
+		opts.Builder = p.analyzer
+	}
+	agent, err := server.New(sc, job, opts)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errConfig, err)
+	}
+
+	fmt.Println(i18n.T("serve.rest", sc.REST.Address, sc.Daemon.Schedule))
+	if sc.Server.GRPC {
+		fmt.Println(i18n.T("serve.grpc", sc.GRPC.Address))
+	}
+	if ok, err := service.Run(agent.Run); ok {
+		return err
+	}
+	ctx, stop := shutdownContext()
+	defer stop()
+	return agent.Run(ctx)
+}
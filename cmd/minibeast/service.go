@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/minibeast/usb-agent/src/core/scheduler"
+	"github.com/minibeast/usb-agent/src/core/service"
+)
+
+// runInstallService registers daemon mode with the OS service manager
+// The config is loaded first so a bad schedule fails here, not in a restart loop.
+func runInstallService(args []string) error {
+	fs := flag.NewFlagSet("install-service", flag.ContinueOnError)
+	configPath := fs.String("config", defaultConfigPath, "agent config file, relative to -dir")
+	dir := fs.String("dir", ".", "agent root the service runs in")
+	name := fs.String("name", service.DefaultName, "service name")
+	user := fs.String("user", "", "account to run as (systemd and launchd; default root)")
+	dryRun := fs.Bool("dry-run", false, "print the service definition and commands without changing anything")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	root, err := filepath.Abs(*dir)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errUsage, err)
+	}
+	cfgPath := *configPath
+	if !filepath.IsAbs(cfgPath) {
+		cfgPath = filepath.Join(root, cfgPath)
+	}
+	cfg, err := loadConfig(cfgPath)
+	if err != nil {
+		return err
+	}
+	if _, err := scheduler.Parse(cfg.Service.Daemon.Schedule); err != nil {
+		return fmt.Errorf("%w: service.daemon.schedule: %w", errConfig, err)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the agent binary: %w", err)
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return fmt.Errorf("failed to locate the agent binary: %w", err)
+	}
+
+	spec := &service.Spec{
+		Name:        *name,
+		DisplayName: "MiniBeast agent (" + *name + ")",
+		Executable:  exe,
+		Args:        []string{"daemon", "-dir", root, "-config", *configPath},
+		WorkingDir:  root,
+		User:        *user,
+	}
+	if err := spec.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", errUsage, err)
+	}
+	if err := service.Install(spec, os.Stdout, *dryRun); err != nil {
+		return err
+	}
+	if !*dryRun {
+		fmt.Printf("install-service: %s installed; runs %q from %s\n", *name, cfg.Service.Daemon.Schedule, root)
+	}
+	return nil
+}
+
+// runUninstallService stops daemon mode and removes it from the OS service manager
+func runUninstallService(args []string) error {
+	fs := flag.NewFlagSet("uninstall-service", flag.ContinueOnError)
+	name := fs.String("name", service.DefaultName, "service name")
+	dryRun := fs.Bool("dry-run", false, "print the commands without changing anything")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if err := service.Uninstall(*name, os.Stdout, *dryRun); err != nil {
+		return err
+	}
+	if !*dryRun {
+		fmt.Printf("uninstall-service: %s removed\n", *name)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// shutdownContext is cancelled on SIGINT or SIGTERM
+// On Windows, Ctrl+C and Ctrl+Break arrive as os.Interrupt and console close,
+// logoff and shutdown events as syscall.SIGTERM. The first signal cancels the
+// context so commands can flush partial results; default handling is then
+// restored, so a second signal terminates immediately.
+func shutdownContext() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	return ctx, stop
+}
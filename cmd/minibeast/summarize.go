@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+	coreio "github.com/minibeast/usb-agent/src/core/io"
+	"github.com/minibeast/usb-agent/src/core/plugin"
+	"github.com/minibeast/usb-agent/src/core/summarizer"
+)
+
+// runSummarize runs the LLM phase on facts written by an earlier run,
+// writing <base>.report.txt and <base>.report.json next to them (or to -out)
+func runSummarize(args []string) error {
+	fs := flag.NewFlagSet("summarize", flag.ContinueOnError)
+	configPath := fs.String("config", defaultConfigPath, "path to config file")
+	factsPath := fs.String("facts", "", "facts JSON written by collect (<hostname>_<time>.json)")
+	outDir := fs.String("out", "", "directory for the reports (default: next to the facts)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *factsPath == "" {
+		return fmt.Errorf("%w: -facts is required", errUsage)
+	}
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	facts, err := readFacts(*factsPath)
+	if err != nil {
+		return err
+	}
+	if err := facts.Validate(); err != nil {
+		return fmt.Errorf("%s: %w", *factsPath, err)
+	}
+
+	engine, err := summarizer.NewEngine(cfg)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errModel, err)
+	}
+	builder, err := summarizer.NewSummarizer(cfg, engine)
+	if err != nil {
+		return summarizerError(err)
+	}
+	defer builder.Close()
+	plugins, err := plugin.Load(cfg.Plugins)
+	if err != nil {
+		return fmt.Errorf("%w: plugins: %w", errConfig, err)
+	}
+	if rules := plugin.OfKind(plugins, plugin.KindRules); len(rules) > 0 {
+		builder.AddRules(plugin.Rules(rules))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	rpt, err := builder.BuildReport(ctx, facts)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errModel, err)
+	}
+	reportJSON, err := rpt.RenderJSON()
+	if err != nil {
+		return err
+	}
+
+	dir := *outDir
+	if dir == "" {
+		dir = filepath.Dir(*factsPath)
+	}
+	base := filepath.Join(dir, strings.TrimSuffix(filepath.Base(*factsPath), ".json"))
+	writer := coreio.NewWriter()
+	if err := writer.WriteAtomic(base+".report.txt", []byte(rpt.RenderText()), 0644); err != nil {
+		return err
+	}
+	if err := writer.WriteJSON(base+".report.json", reportJSON); err != nil {
+		return err
+	}
+	fmt.Printf("summarize: wrote %s.report.txt and %s.report.json\n", base, base)
+	return nil
+}
+
+// readFacts decodes a facts JSON document written by collect
+func readFacts(path string) (*collection.Facts, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var facts collection.Facts
+	if err := json.Unmarshal(data, &facts); err != nil {
+		return nil, fmt.Errorf("%w: %s is not a facts document: %w", errUsage, path, err)
+	}
+	return &facts, nil
+}
@@ -0,0 +1,461 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/minibeast/usb-agent/src/core/export"
+	"github.com/minibeast/usb-agent/src/core/progress"
+)
+
+// runTUI runs one collection in a full-screen terminal UI for attended use
+// Artifacts are written exactly as by collect; the UI adds live progress, the
+// rendered report, a facts browser and follow-up questions to the model.
+func runTUI(args []string) error {
+	fs := flag.NewFlagSet("tui", flag.ContinueOnError)
+	configPath := fs.String("config", defaultConfigPath, "agent config file")
+	assumeYes := fs.Bool("assume-yes", false, "record consent without prompting (requires -operator or consent.operator)")
+	operator := fs.String("operator", "", "operator name or initials recorded with the consent")
+	outputRoot := fs.String("output-root", "", "base of a relative output.directory (overrides output.root)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if !isTerminal(os.Stdout) || !isTerminal(os.Stdin) {
+		return fmt.Errorf("%w: tui needs an interactive terminal; use collect instead", errUsage)
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	if err := resolveOutputDir(cfg, *outputRoot); err != nil {
+		return err
+	}
+	release, err := acquireRunLocks(cfg.Output.Directory)
+	if err != nil {
+		return err
+	}
+	defer release()
+	defer applyResourceLimits(cfg)()
+
+	record, err := obtainConsent(cfg, *assumeYes, *operator)
+	if err != nil {
+		return err
+	}
+	p, err := newPipeline(cfg)
+	if err != nil {
+		return err
+	}
+	defer p.close()
+	p.consent = record
+
+	ctx, stop := shutdownContext()
+	defer stop()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	m := &tuiModel{pipeline: p, dir: cfg.Output.Directory, cancel: cancel}
+	program := tea.NewProgram(m, tea.WithAltScreen())
+	m.ctx = progress.WithReporter(ctx, func(ev progress.Event) { program.Send(ev) })
+
+	if _, err := program.Run(); err != nil {
+		return err
+	}
+	for _, path := range m.paths {
+		fmt.Println(path)
+	}
+	return m.runErr
+}
+
+// tuiTab is a view selectable once the run has finished
+type tuiTab int
+
+const (
+	tabReport tuiTab = iota
+	tabFacts
+	tabAsk
+)
+
+// tuiTabNames label the tab bar
+var tuiTabNames = []string{"1 Report", "2 Facts", "3 Ask"}
+
+// analyzedMsg carries the finished run
+type analyzedMsg struct {
+	payload *export.Payload
+	paths   []string
+	err     error
+}
+
+// answerMsg carries a follow-up answer
+type answerMsg struct {
+	answer string
+	err    error
+}
+
+// exchange is one follow-up question and its answer
+type exchange struct {
+	question string
+	answer   string
+}
+
+// tuiModel is the bubbletea model for runTUI
+type tuiModel struct {
+	pipeline *pipeline
+	dir      string
+	ctx      context.Context
+	cancel   context.CancelFunc
+
+	width, height int
+	frame         int
+	stages        []*stageLine
+	quitting      bool
+
+	// Set when the run finishes
+	done    bool
+	payload *export.Payload
+	paths   []string
+	runErr  error
+
+	tab       tuiTab
+	report    []string
+	facts     []string
+	offsets   [2]int // Scroll position of the report and facts tabs
+	input     string
+	asking    bool
+	exchanges []exchange
+}
+
+// tickMsg advances spinners
+type tickMsg time.Time
+
+// tick schedules the next spinner frame
+func tick() tea.Cmd {
+	return tea.Tick(100*time.Millisecond, func(t time.Time) tea.Msg { return tickMsg(t) })
+}
+
+// Init starts the run and the spinner
+func (m *tuiModel) Init() tea.Cmd {
+	return tea.Batch(tick(), m.analyze)
+}
+
+// analyze runs the pipeline and writes its artifacts (a tea.Cmd)
+func (m *tuiModel) analyze() tea.Msg {
+	payload, paths, err := m.pipeline.execute(m.ctx, m.dir)
+	if payload == nil {
+		return analyzedMsg{err: err}
+	}
+	return analyzedMsg{payload: payload, paths: paths, err: err}
+}
+
+// ask sends question to the model (a tea.Cmd factory)
+func (m *tuiModel) ask(question string) tea.Cmd {
+	builder, facts := m.pipeline.builder, m.payload.Facts
+	return func() tea.Msg {
+		answer, err := builder.Ask(m.ctx, facts, question)
+		return answerMsg{answer: answer, err: err}
+	}
+}
+
+// Update handles one message
+func (m *tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+	case tickMsg:
+		m.frame++
+		if !m.done {
+			return m, tick()
+		}
+	case progress.Event:
+		m.track(msg)
+	case analyzedMsg:
+		m.finish(msg)
+		if m.quitting {
+			return m, tea.Quit
+		}
+	case answerMsg:
+		last := &m.exchanges[len(m.exchanges)-1]
+		last.answer = msg.answer
+		if msg.err != nil {
+			last.answer = "error: " + msg.err.Error()
+		}
+		m.asking = false
+	case tea.KeyMsg:
+		return m.key(msg)
+	}
+	return m, nil
+}
+
+// track records a progress event against its stage line
+func (m *tuiModel) track(ev progress.Event) {
+	for _, line := range m.stages {
+		if line.stage == ev.Stage {
+			line.event, line.ended = ev, ev.Ended()
+			if ev.State == progress.Started {
+				line.start = time.Now() // Stages repeat (e.g., generate for each question)
+			}
+			return
+		}
+	}
+	m.stages = append(m.stages, &stageLine{stage: ev.Stage, start: time.Now(), event: ev, ended: ev.Ended()})
+}
+
+// finish stores the run result and prepares the browsable views
+func (m *tuiModel) finish(msg analyzedMsg) {
+	m.done, m.paths, m.runErr, m.payload = true, msg.paths, msg.err, msg.payload
+	if m.payload == nil {
+		return
+	}
+	if m.payload.Report != nil {
+		m.report = strings.Split(m.payload.Report.RenderText(), "\n")
+	} else {
+		m.report = []string{"No report: summarization is disabled or failed.", "", "Browse the collected facts in the Facts tab."}
+	}
+	m.facts = flattenFacts(m.payload.Facts)
+}
+
+// key handles keyboard input
+func (m *tuiModel) key(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.Type == tea.KeyCtrlC {
+		if !m.done {
+			// Let the pipeline flush partial results before exiting
+			m.quitting = true
+			m.cancel()
+			return m, nil
+		}
+		return m, tea.Quit
+	}
+	if !m.done || m.payload == nil {
+		if msg.String() == "q" && m.done {
+			return m, tea.Quit
+		}
+		return m, nil
+	}
+
+	if m.tab == tabAsk {
+		switch msg.Type {
+		case tea.KeyEsc:
+			m.tab = tabReport
+		case tea.KeyEnter:
+			question := strings.TrimSpace(m.input)
+			if question == "" || m.asking || m.pipeline.builder == nil {
+				return m, nil
+			}
+			m.input, m.asking = "", true
+			m.exchanges = append(m.exchanges, exchange{question: question})
+			return m, m.ask(question)
+		case tea.KeyBackspace:
+			if r := []rune(m.input); len(r) > 0 {
+				m.input = string(r[:len(r)-1])
+			}
+		case tea.KeyRunes, tea.KeySpace:
+			m.input += string(msg.Runes)
+		case tea.KeyTab:
+			m.tab = tabReport
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "1":
+		m.tab = tabReport
+	case "2":
+		m.tab = tabFacts
+	case "3", "/":
+		m.tab = tabAsk
+	case "tab":
+		m.tab = (m.tab + 1) % tuiTab(len(tuiTabNames))
+	case "up", "k":
+		m.scroll(-1)
+	case "down", "j":
+		m.scroll(1)
+	case "pgup":
+		m.scroll(-m.bodyHeight())
+	case "pgdown", " ":
+		m.scroll(m.bodyHeight())
+	case "home", "g":
+		m.scroll(-1 << 30)
+	case "end", "G":
+		m.scroll(1 << 30)
+	}
+	return m, nil
+}
+
+// scroll moves the current tab's viewport by n lines
+func (m *tuiModel) scroll(n int) {
+	lines := m.report
+	if m.tab == tabFacts {
+		lines = m.facts
+	}
+	off := m.offsets[m.tab] + n
+	off = min(off, len(lines)-m.bodyHeight())
+	m.offsets[m.tab] = max(off, 0)
+}
+
+// bodyHeight is the number of content lines between header and footer
+func (m *tuiModel) bodyHeight() int {
+	return max(m.height-4, 1)
+}
+
+// View renders the screen
+func (m *tuiModel) View() string {
+	var b strings.Builder
+	b.WriteString("MiniBeast  ")
+	if m.done {
+		for i, name := range tuiTabNames {
+			if tuiTab(i) == m.tab {
+				b.WriteString("\x1b[7m " + name + " \x1b[0m ")
+			} else {
+				b.WriteString(" " + name + "  ")
+			}
+		}
+	} else {
+		b.WriteString("collecting...")
+	}
+	b.WriteString("\n\n")
+
+	switch {
+	case !m.done || m.payload == nil:
+		b.WriteString(m.viewProgress())
+	case m.tab == tabAsk:
+		b.WriteString(m.viewAsk())
+	case m.tab == tabFacts:
+		b.WriteString(m.viewLines(m.facts))
+	default:
+		b.WriteString(m.viewLines(m.report))
+	}
+
+	b.WriteString("\n" + m.viewFooter())
+	return b.String()
+}
+
+// viewProgress renders the stage checklist and, once done, the outcome
+func (m *tuiModel) viewProgress() string {
+	view := progressView{tty: true, frame: m.frame}
+	var b strings.Builder
+	for _, line := range m.stages {
+		b.WriteString(view.render(line) + "\n")
+	}
+	if m.done {
+		fmt.Fprintf(&b, "\n  run failed: %v\n", m.runErr)
+	}
+	return b.String()
+}
+
+// viewLines renders the visible window of a scrollable tab
+func (m *tuiModel) viewLines(lines []string) string {
+	off := m.offsets[m.tab]
+	end := min(off+m.bodyHeight(), len(lines))
+	var b strings.Builder
+	for _, line := range lines[min(off, end):end] {
+		b.WriteString(m.clip(line) + "\n")
+	}
+	return b.String()
+}
+
+// viewAsk renders the follow-up transcript and input line
+func (m *tuiModel) viewAsk() string {
+	if m.pipeline.builder == nil {
+		return "  Follow-up questions need llm.enabled: true.\n"
+	}
+	lines := []string{}
+	for _, ex := range m.exchanges {
+		lines = append(lines, "> "+ex.question)
+		answer := ex.answer
+		if answer == "" {
+			answer = "..."
+		}
+		for _, line := range strings.Split(answer, "\n") {
+			lines = append(lines, "  "+line)
+		}
+		lines = append(lines, "")
+	}
+	// Keep the latest exchange visible above the input line
+	if over := len(lines) - (m.bodyHeight() - 2); over > 0 {
+		lines = lines[over:]
+	}
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(m.clip(line) + "\n")
+	}
+	b.WriteString("\n? " + m.input + "\x1b[7m \x1b[0m\n")
+	return b.String()
+}
+
+// viewFooter renders key help and the run outcome
+func (m *tuiModel) viewFooter() string {
+	switch {
+	case !m.done && m.quitting:
+		return "stopping, writing partial results..."
+	case !m.done:
+		return "ctrl+c stop"
+	case m.payload == nil:
+		return "q quit"
+	case m.tab == tabAsk:
+		return "enter ask  esc back  ctrl+c quit"
+	}
+	status := fmt.Sprintf("%d artifacts in %s", len(m.paths), m.dir)
+	if m.runErr != nil {
+		status = m.runErr.Error()
+	}
+	return "tab/1-3 switch  up/down/pgup/pgdn scroll  / ask  q quit   " + status
+}
+
+// clip truncates line to the terminal width
+func (m *tuiModel) clip(line string) string {
+	if r := []rune(line); m.width > 0 && len(r) > m.width {
+		return string(r[:m.width-1]) + "~"
+	}
+	return line
+}
+
+// flattenFacts renders facts as sorted "path: value" lines for browsing
+// Complexity: O(n log n) where n = number of leaf values
+func flattenFacts(facts any) []string {
+	data, err := json.Marshal(facts)
+	if err != nil {
+		return []string{"error: " + err.Error()}
+	}
+	var tree any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return []string{"error: " + err.Error()}
+	}
+
+	lines := []string{}
+	var walk func(prefix string, v any)
+	walk = func(prefix string, v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				walk(strings.TrimPrefix(prefix+"."+k, "."), v[k])
+			}
+		case []any:
+			if len(v) == 0 {
+				lines = append(lines, prefix+": []")
+			}
+			for i, item := range v {
+				walk(fmt.Sprintf("%s[%d]", prefix, i), item)
+			}
+		default:
+			value, _ := json.Marshal(v)
+			lines = append(lines, prefix+": "+string(value))
+		}
+	}
+	walk("", tree)
+	return lines
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFlattenFacts(t *testing.T) {
+	facts := map[string]any{
+		"hostname": "host",
+		"users":    []map[string]string{{"username": "alice"}, {"username": "bob"}},
+		"wifi":     []string{},
+	}
+	want := []string{
+		`hostname: "host"`,
+		`users[0].username: "alice"`,
+		`users[1].username: "bob"`,
+		`wifi: []`,
+	}
+	if got := flattenFacts(facts); !reflect.DeepEqual(got, want) {
+		t.Errorf("flattenFacts() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/minibeast/usb-agent/src/core/audit"
+	"github.com/minibeast/usb-agent/src/core/bundle"
+	"github.com/minibeast/usb-agent/src/core/crypto"
+	coreio "github.com/minibeast/usb-agent/src/core/io"
+)
+
+// runVerify checks the signatures of bundles (.mbz, and their detached
+// .mbz.sig when present), audit files (.audit.json), run manifests
+// (.manifest.json, with every file they list), any file with a detached FILE.sig (raw or minisign), and run directories (every chained
+// FILE.mbsig below them)
+// Bundles, audit files and manifests verify against their embedded key when
+// -pubkey is not given, which proves integrity but not origin. A P-256
+// -pubkey (exported by keygen -backend) checks run directories only.
+func runVerify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	pubPath := fs.String("pubkey", "", "Ed25519 or P-256 public key the signatures must verify against (required for FILE.sig)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("%w: usage: minibeast verify [-pubkey KEY] FILE|DIR...", errUsage)
+	}
+	var trusted []byte
+	if *pubPath != "" {
+		key, err := crypto.LoadVerifyingKey(*pubPath)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errConfig, err)
+		}
+		trusted = key
+	}
+
+	failed := 0
+	for _, path := range fs.Args() {
+		if err := verifyFile(path, trusted); err != nil {
+			fmt.Printf("FAIL %s: %v\n", path, err)
+			failed++
+			continue
+		}
+		origin := "embedded key"
+		if trusted != nil {
+			origin = "key " + crypto.Fingerprint(trusted)
+		}
+		fmt.Printf("OK   %s (%s)\n", path, origin)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d files did not verify", errSigning, failed, fs.NArg())
+	}
+	return nil
+}
+
+// verifyFile checks one file by its kind
+func verifyFile(path string, key []byte) error {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		var keys [][]byte
+		if key != nil {
+			keys = append(keys, key)
+		}
+		_, err := crypto.VerifyBundle(path, keys)
+		return err
+	}
+	var trusted ed25519.PublicKey
+	if key != nil {
+		if len(key) != crypto.PublicKeySize {
+			return fmt.Errorf("P-256 keys verify run directories only")
+		}
+		trusted = ed25519.PublicKey(key)
+	}
+	switch {
+	case strings.HasSuffix(path, bundle.Extension):
+		_, err := bundle.VerifyBundle(path, trusted)
+		return err
+	case strings.HasSuffix(path, audit.Suffix):
+		_, err := audit.Verify(path, trusted)
+		return err
+	case strings.HasSuffix(path, coreio.ManifestSuffix):
+		_, err := coreio.VerifyManifest(path, trusted)
+		return err
+	case trusted == nil:
+		return fmt.Errorf("detached signatures need -pubkey")
+	}
+	sig, err := os.ReadFile(path + ".sig")
+	if err != nil {
+		return fmt.Errorf("failed to read signature file: %w", err)
+	}
+	if len(sig) != crypto.SignatureSize { // output.signature_format: minisign
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		_, err = crypto.VerifyMinisign(trusted, data, sig)
+		return err
+	}
+	ok, err := crypto.VerifyFile(trusted, path, crypto.Signature(sig))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("signature does not verify")
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/i18n"
+	"github.com/minibeast/usb-agent/src/core/usbwatch"
+)
+
+// runWatch collects onto the stick as soon as it is inserted
+// When the binary itself runs from the stick, it collects once and exits.
+func runWatch(args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	configPath := fs.String("config", defaultConfigPath, "host config file (defaults are used if missing)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	ctx, stop := shutdownContext()
+	defer stop()
+
+	if root, ok := usbwatch.StartedFromRemovable(); ok {
+		fmt.Println(i18n.T("watch.volume", root))
+		return collectOnStick(ctx, root)
+	}
+
+	cfg := config.LoadOrDefault(*configPath)
+	w, err := usbwatch.New(time.Duration(cfg.Service.Watch.PollIntervalMs)*time.Millisecond, collectOnStick,
+		func(root string, err error) {
+			if err != nil {
+				fmt.Fprintln(os.Stderr, i18n.T("watch.failed", root, err))
+				return
+			}
+			fmt.Println(i18n.T("watch.complete", root))
+		})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(i18n.T("watch.waiting"))
+	return w.Run(ctx)
+}
+
+// collectOnStick runs the pipeline with the stick's own config and signals completion
+// Config paths are relative to the stick root, so the run happens inside it.
+func collectOnStick(ctx context.Context, root string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if err := os.Chdir(root); err != nil {
+		return err
+	}
+	defer os.Chdir(cwd)
+
+	cfg, err := loadConfig(defaultConfigPath)
+	if err != nil {
+		return err
+	}
+	done := filepath.Join(root, cfg.Service.Watch.DoneFile)
+	if err := usbwatch.ClearDone(done); err != nil {
+		return err
+	}
+
+	paths, runErr := func() ([]string, error) {
+		release, err := acquireRunLocks(cfg.Output.Directory)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		defer applyResourceLimits(cfg)()
+
+		p, err := newPipeline(cfg)
+		if err != nil {
+			return nil, err
+		}
+		defer p.close()
+		p.consent = preauthorizedConsent(cfg)
+		return p.run(ctx, cfg.Output.Directory)
+	}()
+	for _, path := range paths {
+		fmt.Println(filepath.Join(root, path))
+	}
+
+	if err := usbwatch.WriteDone(done, time.Now(), len(paths), runErr); err != nil {
+		return fmt.Errorf("failed to write %s: %w", done, err)
+	}
+	if cfg.Service.Watch.Beep {
+		fmt.Print("\a")
+	}
+	return runErr
+}
@@ -0,0 +1,296 @@
+// Package analysis evaluates Facts against built-in risk rules, so a run
+// still gets a report when there is no model (llm.enabled is false or the
+// model cannot be loaded)
+// Rule findings use the IDs of the remediation knowledge base, and the
+// result has the shape of parsed model output, so reports look the same
+// with and without a model.
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/inference"
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+	"github.com/minibeast/usb-agent/src/core/progress"
+	"github.com/minibeast/usb-agent/src/core/remediation"
+	"github.com/minibeast/usb-agent/src/core/report"
+	"github.com/minibeast/usb-agent/src/core/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Rule IDs (each has an entry in remediation.yaml)
+const (
+	RuleOSEOL           = "MB-OS-EOL"
+	RuleAdminExcess     = "MB-ADMIN-EXCESS"
+	RuleSerialUnknown   = "MB-SERIAL-UNKNOWN"
+	RuleDiskUnencrypted = "MB-DISK-UNENCRYPTED"
+	RuleAVMissing       = "MB-AV-MISSING"
+	RuleUpdatesStale    = "MB-UPDATES-STALE"
+)
+
+// rule is one check; it returns the risk text, or "" when the facts pass or
+// lack what the rule needs
+type rule struct {
+	id    string
+	check func(cfg *config.AnalysisConfig, facts *collection.Facts) string
+	needs collection.Category // Skips the rule when this category was not collected ("" for none)
+}
+
+// rules in report order
+var rules = []rule{
+	{RuleOSEOL, checkOSEOL, ""},
+	{RuleDiskUnencrypted, checkDiskEncryption, ""},
+	{RuleAdminExcess, checkAdmins, ""},
+	{RuleSerialUnknown, checkSerial, ""},
+	{RuleAVMissing, checkSecurityProducts, collection.CategorySecurityProducts},
+	{RuleUpdatesStale, checkUpdates, collection.CategoryPatchLevel},
+}
+
+// Analyzer evaluates the built-in rules against facts
+// Implements summarizer.RuleSource and rpc.ReportBuilder. Safe for
+// concurrent use.
+type Analyzer struct {
+	cfg         *config.Config
+	remediation *remediation.KnowledgeBase
+}
+
+// New creates an analyzer with the rule settings in cfg.Analysis
+// Complexity: O(1)
+func New(cfg *config.Config) (*Analyzer, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+	kb, err := remediation.LoadOrDefault(cfg.Output.RemediationPath)
+	if err != nil {
+		return nil, fmt.Errorf("output.remediation_path: %w", err)
+	}
+	return &Analyzer{cfg: cfg, remediation: kb}, nil
+}
+
+// Findings returns a grounded finding for each enabled rule facts fail, in
+// rule order
+// Complexity: O(|facts|)
+func (a *Analyzer) Findings(ctx context.Context, facts *collection.Facts) ([]inference.Finding, error) {
+	return a.findings(facts), nil
+}
+
+// findings evaluates the enabled rules
+func (a *Analyzer) findings(facts *collection.Facts) []inference.Finding {
+	cfg := &a.cfg.Analysis
+	findings := []inference.Finding{}
+	for _, r := range rules {
+		if slices.Contains(cfg.Disabled, r.id) || (r.needs != "" && !a.collected(facts, r.needs)) {
+			continue
+		}
+		if text := r.check(cfg, facts); text != "" {
+			findings = append(findings, inference.Finding{ID: r.id, Description: text, Confidence: inference.ConfidenceGrounded})
+		}
+	}
+	return findings
+}
+
+// collected reports whether facts hold category c: it is enabled in
+// collect and did not fail
+// Rules that flag an absence need this, since an empty list is also what a
+// disabled or failed category leaves.
+func (a *Analyzer) collected(facts *collection.Facts, c collection.Category) bool {
+	if slices.Contains(facts.FailedCategories, string(c)) {
+		return false
+	}
+	switch c {
+	case collection.CategorySecurityProducts:
+		return a.cfg.Collect.SecurityProducts
+	case collection.CategoryPatchLevel:
+		return a.cfg.Collect.PatchLevel
+	}
+	return true
+}
+
+// Analyze returns the rules' verdict as the parser returns model output:
+// a summary, one risk per finding and the curated remediation as actions
+// Complexity: O(|facts|)
+func (a *Analyzer) Analyze(facts *collection.Facts) *inference.ParsedOutput {
+	findings := a.findings(facts)
+	parsed := &inference.ParsedOutput{
+		Summary: []string{
+			fmt.Sprintf("Rule-based analysis of %s (%s %s): %d of %d built-in checks flagged a risk.",
+				facts.Hostname, facts.OSName, facts.OSVersion, len(findings), a.enabledRules()),
+			"No model was used; every risk is derived directly from the collected facts.",
+		},
+		Risks:    []string{},
+		Findings: findings,
+	}
+	for _, f := range findings {
+		parsed.Risks = append(parsed.Risks, f.Description)
+	}
+	parsed.Actions = a.remediation.MergeActions(nil, parsed.FindingIDs())
+	return parsed
+}
+
+// enabledRules counts the rules not listed in analysis.disabled
+func (a *Analyzer) enabledRules() int {
+	n := 0
+	for _, r := range rules {
+		if !slices.Contains(a.cfg.Analysis.Disabled, r.id) {
+			n++
+		}
+	}
+	return n
+}
+
+// BuildReport builds the report from the rules alone
+// Like summarizer.BuildReport, the result is fitted to
+// output.max_report_bytes.
+// Complexity: O(|facts|)
+func (a *Analyzer) BuildReport(ctx context.Context, facts *collection.Facts) (*report.Report, error) {
+	if facts == nil {
+		return nil, fmt.Errorf("facts cannot be nil")
+	}
+	ctx, span := telemetry.Tracer().Start(ctx, "analysis")
+	defer span.End()
+	step := progress.Start(ctx, "analysis")
+
+	parsed := a.Analyze(facts)
+	rpt := report.Build(facts, parsed)
+	rpt.Header = append(rpt.Header,
+		report.Field{Label: "Collection Time", Value: fmt.Sprintf("%dms", facts.CollectionDurationMs)},
+		report.Field{Label: "Analysis", Value: "built-in rules (no model)"},
+	)
+	span.SetAttributes(attribute.Int("analysis.findings", len(parsed.Findings)))
+	step.End(nil)
+	return rpt.Fit(a.cfg.Output.MaxReportBytes, a.cfg.Output.ReportTopRisks), nil
+}
+
+// checkOSEOL flags an os_version matching analysis.eol_versions[os_name]
+func checkOSEOL(cfg *config.AnalysisConfig, facts *collection.Facts) string {
+	if facts.OSVersion == "" || facts.OSVersion == "unknown" {
+		return ""
+	}
+	for _, pattern := range cfg.EOLVersions[facts.OSName] {
+		if ok, _ := path.Match(pattern, facts.OSVersion); ok {
+			return fmt.Sprintf("End-of-life operating system %s %s no longer receives security updates (Evidence: %s)",
+				facts.OSName, facts.OSVersion, facts.OSVersion)
+		}
+	}
+	return ""
+}
+
+// removableRoots are where removable media (including this stick) mount
+var removableRoots = []string{"/media/", "/run/media/", "/mnt/", "/Volumes/"}
+
+// checkDiskEncryption flags fixed volumes reporting no full-disk encryption
+// Volumes whose state is unknown (no privileges to query) are not flagged.
+func checkDiskEncryption(_ *config.AnalysisConfig, facts *collection.Facts) string {
+	var plain []string
+	for _, v := range facts.Volumes {
+		if v.Encryption == types.EncryptionNone && !exempt(v) {
+			plain = append(plain, v.MountPoint)
+		}
+	}
+	if len(plain) == 0 {
+		return ""
+	}
+	list := strings.Join(plain, ", ")
+	return fmt.Sprintf("Unencrypted fixed volumes without full-disk encryption: %s (Evidence: %s)", list, list)
+}
+
+// exempt reports whether v is expected to be unencrypted: removable media,
+// the boot partition and FAT volumes (EFI system partitions, USB sticks)
+func exempt(v types.Volume) bool {
+	if v.MountPoint == "/boot" || strings.HasPrefix(v.MountPoint, "/boot/") {
+		return true
+	}
+	switch strings.ToLower(v.FileSystem) {
+	case "vfat", "fat", "fat32", "exfat", "msdos":
+		return true
+	}
+	for _, root := range removableRoots {
+		if strings.HasPrefix(v.MountPoint, root) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAdmins flags more administrator accounts than analysis.max_admins
+func checkAdmins(cfg *config.AnalysisConfig, facts *collection.Facts) string {
+	var admins []string
+	for _, u := range facts.Users {
+		if u.Admin {
+			admins = append(admins, u.Username)
+		}
+	}
+	if len(admins) <= cfg.MaxAdmins {
+		return ""
+	}
+	return fmt.Sprintf("%d local admin accounts exceed the limit of %d (Evidence: %s)",
+		len(admins), cfg.MaxAdmins, strings.Join(admins, ", "))
+}
+
+// placeholderSerials are values firmware reports when no serial was set
+var placeholderSerials = []string{
+	"unknown", "0", "none", "default string", "to be filled by o.e.m.", "system serial number", "not specified",
+}
+
+// checkSerial flags a hardware serial that is missing or a firmware
+// placeholder (not flagged when hardware facts were not collected)
+func checkSerial(_ *config.AnalysisConfig, facts *collection.Facts) string {
+	serial := strings.TrimSpace(facts.SerialNumber)
+	if facts.SerialNumber == "" || !slices.Contains(placeholderSerials, strings.ToLower(serial)) {
+		return ""
+	}
+	return fmt.Sprintf("Hardware serial number unknown; asset cannot be matched to inventory (Evidence: %s)", serial)
+}
+
+// checkSecurityProducts flags a host where no antivirus or EDR product has
+// real-time protection on
+// Products in an unknown state are given the benefit of the doubt; facts
+// from agents predating security_products (nil) are not flagged.
+func checkSecurityProducts(_ *config.AnalysisConfig, facts *collection.Facts) string {
+	if facts.SecurityProducts == nil {
+		return ""
+	}
+	var inactive []string
+	for _, p := range facts.SecurityProducts {
+		if p.RealTime != types.RealTimeOff {
+			return ""
+		}
+		inactive = append(inactive, p.Name+" ("+p.RealTime+")")
+	}
+	if len(inactive) == 0 {
+		return "Endpoint protection missing: no antivirus or EDR product is installed (Evidence: security_products is empty)"
+	}
+	return fmt.Sprintf("Endpoint protection missing: no antivirus or EDR product has real-time protection on (Evidence: %s)",
+		strings.Join(inactive, ", "))
+}
+
+// checkUpdates flags a host whose last OS update was installed more than
+// max_update_age_days before collection
+// Facts without a last update date (none recorded, or agents predating
+// patch_level) are not flagged.
+func checkUpdates(cfg *config.AnalysisConfig, facts *collection.Facts) string {
+	if cfg.MaxUpdateAgeDays == 0 || facts.LastUpdate == "" || facts.Timestamp.IsZero() {
+		return ""
+	}
+	last, err := time.Parse("2006-01-02", facts.LastUpdate)
+	if err != nil {
+		return ""
+	}
+	days := int(facts.Timestamp.Sub(last).Hours() / 24)
+	if days <= cfg.MaxUpdateAgeDays {
+		return ""
+	}
+	evidence := "last_update " + facts.LastUpdate
+	if facts.PendingUpdates != nil {
+		evidence += fmt.Sprintf(", %d pending", *facts.PendingUpdates)
+	}
+	return fmt.Sprintf("Operating system updates outdated: last update installed %d days ago, over the %d-day limit (Evidence: %s)",
+		days, cfg.MaxUpdateAgeDays, evidence)
+}
@@ -0,0 +1,159 @@
+package analysis
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/inference"
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+	"github.com/minibeast/usb-agent/src/core/progress"
+	"github.com/minibeast/usb-agent/src/core/report"
+)
+
+// riskyFacts fails every built-in rule under the default config
+func riskyFacts() *collection.Facts {
+	pending := 3
+	return &collection.Facts{
+		Timestamp:    time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC),
+		Hostname:     "old-host",
+		OSName:       "Windows",
+		OSVersion:    "10.0.19045.5011",
+		SerialNumber: "To be filled by O.E.M.",
+		Users: []types.User{
+			{Username: "Administrator", Admin: true},
+			{Username: "alice", Admin: true},
+			{Username: "bob", Admin: true},
+			{Username: "guest"},
+		},
+		Volumes: []types.Volume{
+			{MountPoint: "C:", FileSystem: "NTFS", Encryption: types.EncryptionNone},
+			{MountPoint: "D:", FileSystem: "NTFS", Encryption: types.EncryptionBitLocker},
+			{MountPoint: "E:", FileSystem: "FAT32", Encryption: types.EncryptionNone},
+		},
+		SecurityProducts: []types.SecurityProduct{
+			{Name: "Windows Defender", Kind: types.ProductAntivirus, RealTime: types.RealTimeOff},
+		},
+		LastUpdate:     "2026-01-10",
+		PendingUpdates: &pending,
+	}
+}
+
+// TestFindings verifies each rule fires on failing facts, in rule order
+func TestFindings(t *testing.T) {
+	a, err := New(config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings, err := a.Findings(context.Background(), riskyFacts())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{RuleOSEOL, RuleDiskUnencrypted, RuleAdminExcess, RuleSerialUnknown, RuleAVMissing, RuleUpdatesStale}
+	if len(findings) != len(want) {
+		t.Fatalf("findings = %+v, want %v", findings, want)
+	}
+	for i, f := range findings {
+		if f.ID != want[i] || f.Confidence != inference.ConfidenceGrounded {
+			t.Errorf("finding %d = %+v, want grounded %s", i, f, want[i])
+		}
+	}
+	if d := findings[1].Description; !strings.Contains(d, "C:") || strings.Contains(d, "E:") {
+		t.Errorf("disk finding = %q, want C: only", d)
+	}
+	if d := findings[2].Description; !strings.Contains(d, "Administrator, alice, bob") {
+		t.Errorf("admin finding = %q", d)
+	}
+	if d := findings[4].Description; !strings.Contains(d, "Windows Defender (off)") {
+		t.Errorf("antivirus finding = %q", d)
+	}
+	if d := findings[5].Description; !strings.Contains(d, "142 days ago") || !strings.Contains(d, "3 pending") {
+		t.Errorf("updates finding = %q", d)
+	}
+}
+
+// TestFindings_Pass verifies facts meeting the rules, or lacking what they
+// check, raise nothing
+func TestFindings_Pass(t *testing.T) {
+	a, err := New(config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, facts := range map[string]*collection.Facts{
+		"current": {
+			OSName: "Linux", OSVersion: "24.04", SerialNumber: "PF3ABCDE",
+			Users:   []types.User{{Username: "root", Admin: true}, {Username: "alice", Admin: true}},
+			Volumes: []types.Volume{{MountPoint: "/", Encryption: types.EncryptionLUKS}, {MountPoint: "/boot", Encryption: types.EncryptionNone}},
+			SecurityProducts: []types.SecurityProduct{
+				{Name: "ClamAV", RealTime: types.RealTimeOff},
+				{Name: "CrowdStrike Falcon", RealTime: types.RealTimeOn},
+			},
+			Timestamp:  time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC),
+			LastUpdate: "2026-05-20",
+		},
+		"not collected": {OSName: "Darwin", OSVersion: "unknown"},
+		"unknown state": {Volumes: []types.Volume{{MountPoint: "C:", Encryption: types.EncryptionUnknown}}},
+	} {
+		if findings, _ := a.Findings(context.Background(), facts); len(findings) != 0 {
+			t.Errorf("%s: findings = %+v", name, findings)
+		}
+	}
+}
+
+// TestFindings_Config verifies max_admins, eol_versions, max_update_age_days
+// (0 disables), disabled rules and
+// categories turned off in collect
+func TestFindings_Config(t *testing.T) {
+	cfg := config.Default()
+	cfg.Analysis.MaxAdmins = 3
+	cfg.Analysis.EOLVersions = map[string][]string{"Windows": {"6.*"}}
+	cfg.Analysis.Disabled = []string{RuleSerialUnknown}
+	cfg.Collect.SecurityProducts = false // Not collected: the empty list is no evidence
+	cfg.Analysis.MaxUpdateAgeDays = 0
+	a, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	facts := riskyFacts()
+	facts.SecurityProducts = []types.SecurityProduct{}
+	findings, _ := a.Findings(context.Background(), facts)
+	if len(findings) != 1 || findings[0].ID != RuleDiskUnencrypted {
+		t.Errorf("findings = %+v, want only %s", findings, RuleDiskUnencrypted)
+	}
+}
+
+// TestBuildReport verifies the rule report carries grounded risks, curated
+// actions and an analysis stage
+func TestBuildReport(t *testing.T) {
+	a, err := New(config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stages []string
+	ctx := progress.WithReporter(context.Background(), func(ev progress.Event) {
+		stages = append(stages, ev.Stage+" "+ev.State.String())
+	})
+	rpt, err := a.BuildReport(ctx, riskyFacts())
+	if err != nil {
+		t.Fatalf("BuildReport() failed: %v", err)
+	}
+	if len(rpt.Risks) != 6 || rpt.Risks[0].FindingID != RuleOSEOL || rpt.Risks[0].Severity != report.SeverityHigh ||
+		rpt.Risks[0].Confidence != inference.ConfidenceGrounded {
+		t.Errorf("risks = %+v", rpt.Risks)
+	}
+	if len(rpt.Actions) == 0 || !strings.HasPrefix(rpt.Actions[0], "Upgrade end-of-life operating system") {
+		t.Errorf("actions = %q, want curated remediation first", rpt.Actions)
+	}
+	if len(rpt.Summary) != 2 || !strings.Contains(rpt.Summary[0], "6 of 6") {
+		t.Errorf("summary = %q", rpt.Summary)
+	}
+	if strings.Join(stages, ", ") != "analysis started, analysis done" {
+		t.Errorf("stages = %v", stages)
+	}
+	if _, err := a.BuildReport(ctx, nil); err == nil {
+		t.Error("BuildReport(nil) succeeded")
+	}
+}
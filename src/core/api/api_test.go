@@ -0,0 +1,144 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/api"
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/inference"
+	"github.com/minibeast/usb-agent/src/core/summarizer"
+)
+
+// fakeCollector returns fixed facts
+type fakeCollector struct{}
+
+func (fakeCollector) CollectAll(ctx context.Context) (*collection.Facts, error) {
+	return &collection.Facts{
+		Timestamp:    time.Date(2025, 11, 9, 12, 0, 0, 0, time.UTC),
+		Hostname:     "kiosk-01",
+		HardwareUUID: "uuid-123",
+		OSName:       "Linux",
+	}, nil
+}
+
+// newTestServer starts the API with token "secret"
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	s, err := summarizer.NewSummarizer(config.Default(), inference.NewFakeEngine())
+	if err != nil {
+		t.Fatalf("NewSummarizer() failed: %v", err)
+	}
+	cfg := config.Default().Service.REST
+	cfg.AuthToken = "secret"
+
+	srv, err := api.NewServer(cfg, fakeCollector{}, s)
+	if err != nil {
+		t.Fatalf("NewServer() failed: %v", err)
+	}
+	server := httptest.NewServer(srv.Handler())
+	t.Cleanup(server.Close)
+	return server
+}
+
+// do sends a request with an optional bearer token
+func do(t *testing.T, method, url, token string) (int, string) {
+	t.Helper()
+	req, _ := http.NewRequest(method, url, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+// TestNewServer_RequiresToken verifies serve mode refuses to run without auth
+func TestNewServer_RequiresToken(t *testing.T) {
+	if _, err := api.NewServer(config.Default().Service.REST, fakeCollector{}, nil); err == nil {
+		t.Error("NewServer() should fail without auth_token")
+	}
+}
+
+// TestEndpoints verifies the collect → latest flow and authentication
+func TestEndpoints(t *testing.T) {
+	server := newTestServer(t)
+
+	if code, _ := do(t, http.MethodGet, server.URL+"/health", ""); code != http.StatusOK {
+		t.Errorf("/health = %d, want 200 without auth", code)
+	}
+	if code, _ := do(t, http.MethodPost, server.URL+"/collect", "wrong"); code != http.StatusUnauthorized {
+		t.Errorf("/collect with bad token = %d, want 401", code)
+	}
+	if code, _ := do(t, http.MethodGet, server.URL+"/facts/latest", "secret"); code != http.StatusNotFound {
+		t.Errorf("/facts/latest before any run = %d, want 404", code)
+	}
+
+	code, body := do(t, http.MethodPost, server.URL+"/collect", "secret")
+	if code != http.StatusOK {
+		t.Fatalf("/collect = %d: %s", code, body)
+	}
+	var result api.RunResult
+	if err := json.Unmarshal([]byte(body), &result); err != nil || result.Hostname != "kiosk-01" || !result.Report {
+		t.Errorf("Unexpected run result: %s", body)
+	}
+
+	code, body = do(t, http.MethodGet, server.URL+"/facts/latest", "secret")
+	if code != http.StatusOK || !strings.Contains(body, `"hostname": "kiosk-01"`) {
+		t.Errorf("/facts/latest = %d: %s", code, body)
+	}
+
+	code, body = do(t, http.MethodGet, server.URL+"/report/latest?format=text", "secret")
+	if code != http.StatusOK || !strings.Contains(body, "END OF REPORT") {
+		t.Errorf("/report/latest?format=text = %d: %s", code, body)
+	}
+}
+
+// TestDo_SerializesRuns verifies a run started while another is in progress fails with ErrBusy
+func TestDo_SerializesRuns(t *testing.T) {
+	cfg := config.Default().Service.REST
+	cfg.AuthToken = "secret"
+	release := make(chan struct{})
+	started := make(chan struct{})
+	srv, err := api.NewRunServer(cfg, func(ctx context.Context) (*api.Run, error) {
+		close(started)
+		<-release
+		facts, _ := fakeCollector{}.CollectAll(ctx)
+		return &api.Run{Facts: facts}, nil
+	})
+	if err != nil {
+		t.Fatalf("NewRunServer() failed: %v", err)
+	}
+	server := httptest.NewServer(srv.Handler())
+	defer server.Close()
+
+	done := make(chan int, 1)
+	go func() {
+		code, _ := do(t, http.MethodPost, server.URL+"/collect", "secret")
+		done <- code
+	}()
+	<-started
+	if _, err := srv.Do(context.Background(), nil); !errors.Is(err, api.ErrBusy) {
+		t.Errorf("Do() during a run = %v, want ErrBusy", err)
+	}
+	if code, _ := do(t, http.MethodPost, server.URL+"/collect", "secret"); code != http.StatusConflict {
+		t.Errorf("/collect during a run = %d, want 409", code)
+	}
+	close(release)
+	if code := <-done; code != http.StatusOK {
+		t.Errorf("/collect = %d, want 200", code)
+	}
+}
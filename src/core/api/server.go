@@ -0,0 +1,255 @@
+// Package api serves the local REST API used by helpdesk tooling to trigger runs
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/report"
+)
+
+// Collector runs Phase 1 (satisfied by *collection.Collector)
+type Collector interface {
+	CollectAll(ctx context.Context) (*collection.Facts, error)
+}
+
+// ReportBuilder runs Phase 2 (satisfied by *summarizer.Summarizer)
+type ReportBuilder interface {
+	BuildReport(ctx context.Context, facts *collection.Facts) (*report.Report, error)
+}
+
+// RunResult is the POST /collect response
+type RunResult struct {
+	Hostname   string    `json:"hostname"`
+	Timestamp  time.Time `json:"timestamp"`
+	DurationMs int64     `json:"duration_ms"`
+	Report     bool      `json:"report"`                 // A report was produced
+	ReportErr  string    `json:"report_error,omitempty"` // Phase 2 failure (facts are still kept)
+	RunErr     string    `json:"run_error,omitempty"`    // Degraded run, e.g. failed categories (facts are still kept)
+}
+
+// Run is the outcome of one run published by the server
+type Run struct {
+	Facts     *collection.Facts
+	Report    *report.Report // nil when Phase 2 is disabled or failed
+	ReportErr error          // Phase 2 failure (facts are still kept)
+}
+
+// RunFunc performs one run for POST /collect
+// A degraded run returns its Run along with the error; a nil Run means
+// nothing was collected.
+type RunFunc func(ctx context.Context) (*Run, error)
+
+// ErrBusy is returned by Do while another run is in progress
+var ErrBusy = errors.New("another run is in progress")
+
+// Server implements the REST endpoints
+//
+//	POST /collect        run collection (and summarization when available)
+//	GET  /facts/latest   facts.json of the last run
+//	GET  /report/latest  report.json of the last run (?format=text for plain text)
+//	GET  /health         liveness (no auth)
+//
+// Runs are serialized; a POST /collect during a run returns 409.
+type Server struct {
+	run   RunFunc
+	token []byte
+
+	running sync.Mutex
+
+	mu     sync.RWMutex
+	facts  *collection.Facts
+	report *report.Report
+}
+
+// NewServer creates the API server; builder may be nil (no reports)
+// Complexity: O(1)
+func NewServer(cfg config.RESTConfig, collector Collector, builder ReportBuilder) (*Server, error) {
+	if collector == nil {
+		return nil, fmt.Errorf("collector cannot be nil")
+	}
+	return NewRunServer(cfg, func(ctx context.Context) (*Run, error) {
+		facts, err := collector.CollectAll(ctx)
+		if err != nil {
+			return nil, err
+		}
+		run := &Run{Facts: facts}
+		if builder != nil {
+			run.Report, run.ReportErr = builder.BuildReport(ctx, facts)
+		}
+		return run, nil
+	})
+}
+
+// NewRunServer creates the API server around run, which performs each
+// POST /collect (e.g., a full pipeline run with redaction and artifacts)
+// Complexity: O(1)
+func NewRunServer(cfg config.RESTConfig, run RunFunc) (*Server, error) {
+	if run == nil {
+		return nil, fmt.Errorf("run cannot be nil")
+	}
+	if cfg.AuthToken == "" {
+		return nil, fmt.Errorf("service.rest.auth_token must be set to serve")
+	}
+	return &Server{run: run, token: []byte("Bearer " + cfg.AuthToken)}, nil
+}
+
+// Do performs one run and publishes its facts and report as the latest
+// Runs are serialized with POST /collect: Do returns ErrBusy while another
+// run is in progress. Degraded runs are published; a run that collected
+// nothing leaves the previous results in place.
+// Complexity: O(run)
+func (s *Server) Do(ctx context.Context, run RunFunc) (*Run, error) {
+	if !s.running.TryLock() {
+		return nil, ErrBusy
+	}
+	defer s.running.Unlock()
+
+	r, err := run(ctx)
+	if r == nil {
+		if err == nil {
+			err = fmt.Errorf("run produced no facts")
+		}
+		return nil, err
+	}
+	s.Publish(r)
+	return r, err
+}
+
+// Publish makes r's facts and report the latest served
+// Complexity: O(1)
+func (s *Server) Publish(r *Run) {
+	s.mu.Lock()
+	s.facts, s.report = r.Facts, r.Report
+	s.mu.Unlock()
+}
+
+// Handler returns the HTTP handler with authentication applied
+// Complexity: O(1)
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", s.handleHealth)
+	mux.Handle("POST /collect", s.authenticated(s.handleCollect))
+	mux.Handle("GET /facts/latest", s.authenticated(s.handleFacts))
+	mux.Handle("GET /report/latest", s.authenticated(s.handleReport))
+	return mux
+}
+
+// Serve listens on cfg.Address and serves until ctx is cancelled
+// Complexity: O(1) setup, blocks for the server lifetime
+func Serve(ctx context.Context, cfg config.RESTConfig, s *Server) error {
+	listener, err := net.Listen("tcp", cfg.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", cfg.Address, err)
+	}
+
+	server := &http.Server{Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	stop := context.AfterFunc(ctx, func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	})
+	defer stop()
+
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("REST server failed: %w", err)
+	}
+	return nil
+}
+
+// authenticated wraps h with constant-time bearer token checking
+func (s *Server) authenticated(h http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), s.token) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, "invalid or missing bearer token")
+			return
+		}
+		h(w, r)
+	})
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) handleCollect(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	run, err := s.Do(r.Context(), s.run)
+	if errors.Is(err, ErrBusy) {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if run == nil {
+		writeError(w, http.StatusInternalServerError, "collection failed: "+err.Error())
+		return
+	}
+
+	result := RunResult{
+		Hostname:   run.Facts.Hostname,
+		Timestamp:  run.Facts.Timestamp,
+		DurationMs: time.Since(start).Milliseconds(),
+		Report:     run.Report != nil,
+	}
+	if run.ReportErr != nil {
+		result.ReportErr = run.ReportErr.Error()
+	}
+	if err != nil {
+		result.RunErr = err.Error()
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handleFacts(w http.ResponseWriter, _ *http.Request) {
+	s.mu.RLock()
+	facts := s.facts
+	s.mu.RUnlock()
+
+	if facts == nil {
+		writeError(w, http.StatusNotFound, "no run yet")
+		return
+	}
+	writeJSON(w, http.StatusOK, facts)
+}
+
+func (s *Server) handleReport(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	rep := s.report
+	s.mu.RUnlock()
+
+	if rep == nil {
+		writeError(w, http.StatusNotFound, "no report yet")
+		return
+	}
+
+	if strings.EqualFold(r.URL.Query().Get("format"), "text") {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(rep.RenderText()))
+		return
+	}
+	writeJSON(w, http.StatusOK, rep)
+}
+
+// writeJSON writes v as indented JSON
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+// writeError writes {"error": msg}
+func writeError(w http.ResponseWriter, code int, msg string) {
+	writeJSON(w, code, map[string]string{"error": msg})
+}
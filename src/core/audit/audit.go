@@ -0,0 +1,285 @@
+// Package audit keeps a signed record of what the agent did during a run
+// Unlike debug logs and traces, the audit file lists only significant
+// actions (categories collected, redactions applied, files written,
+// exporters invoked, keys used) so an engagement can show exactly what the
+// tool did on a machine. It is written next to the run's artifacts as
+// "<run>.audit.json" with a detached Ed25519 signature in "<run>.audit.json.sig".
+package audit
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/consent"
+	"github.com/minibeast/usb-agent/src/core/crypto"
+	coreio "github.com/minibeast/usb-agent/src/core/io"
+	"github.com/minibeast/usb-agent/src/core/progress"
+)
+
+// Format identifies the audit file layout
+const Format = "minibeast-audit/1"
+
+// File name suffixes after the run's base name
+const (
+	Suffix          = ".audit.json"
+	SignatureSuffix = ".audit.json.sig"
+)
+
+// Action is the kind of thing the agent did
+type Action string
+
+const (
+	ActionCollect      Action = "collect"      // One collection category (target = category)
+	ActionRedact       Action = "redact"       // One redaction plugin (target = plugin)
+	ActionPseudonymize Action = "pseudonymize" // Identifiers replaced with keyed pseudonyms
+	ActionInference    Action = "inference"    // Model load, generate or parse (target = step)
+	ActionRules        Action = "rules"        // Risk rule plugins
+	ActionAnalysis     Action = "analysis"     // Built-in risk rules (report without a model)
+	ActionWrite        Action = "write"        // File written (target = path, detail = SHA-256)
+	ActionExport       Action = "export"       // Exporter invoked (target = exporter)
+	ActionUpload       Action = "upload"       // Run files handed to an uploader (target = uploader)
+	ActionKey          Action = "key"          // Key used (target = role, detail = key ID)
+)
+
+// Outcome is how an action ended
+type Outcome string
+
+const (
+	OK      Outcome = "ok"
+	Failed  Outcome = "failed"
+	Spooled Outcome = "spooled" // Exporter unreachable; payload queued for flush
+)
+
+// Event is one recorded action
+type Event struct {
+	Time      time.Time `json:"time"` // UTC, when the action ended
+	Action    Action    `json:"action"`
+	Target    string    `json:"target,omitempty"`
+	Outcome   Outcome   `json:"outcome"`
+	Detail    string    `json:"detail,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	ElapsedMs int64     `json:"elapsed_ms,omitempty"`
+}
+
+// Record is the content of an audit file
+type Record struct {
+	Format     string          `json:"format"`
+	RunID      string          `json:"run_id"`
+	Hostname   string          `json:"hostname,omitempty"`
+	StartedAt  time.Time       `json:"started_at"`
+	FinishedAt time.Time       `json:"finished_at"`
+	Consent    *consent.Record `json:"consent,omitempty"`
+	Events     []Event         `json:"events"`
+	PublicKey  string          `json:"public_key"` // Base64 Ed25519 key the file is signed with
+	KeyID      string          `json:"key_id"`     // Hex SHA-256 of PublicKey
+	Ephemeral  bool            `json:"ephemeral"`  // Signed with a per-run key (no audit.signing_key)
+}
+
+// Log collects the events of one run; safe for concurrent use
+type Log struct {
+	runID   string
+	now     func() time.Time
+	started time.Time
+
+	mu     sync.Mutex
+	events []Event
+}
+
+// New starts a log for run runID beginning now
+// Complexity: O(1)
+func New(runID string, now func() time.Time) *Log {
+	return &Log{runID: runID, now: now, started: now().UTC()}
+}
+
+// ctxKey carries the Log in a context
+type ctxKey struct{}
+
+// WithLog returns a context whose pipeline stages are recorded in l
+// Completed progress stages become events ("collect.pii_info" is action
+// collect, target pii_info); From(ctx) returns l for explicit records.
+// Complexity: O(1)
+func WithLog(ctx context.Context, l *Log) context.Context {
+	ctx = progress.AddReporter(ctx, l.observe)
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// From returns the Log carried by ctx (nil records nothing)
+func From(ctx context.Context) *Log {
+	l, _ := ctx.Value(ctxKey{}).(*Log)
+	return l
+}
+
+// observe records a finished progress stage
+func (l *Log) observe(ev progress.Event) {
+	if !ev.Ended() {
+		return
+	}
+	action, target, _ := strings.Cut(ev.Stage, ".")
+	e := Event{Action: Action(action), Target: target, Outcome: OK, ElapsedMs: ev.Elapsed.Milliseconds()}
+	if ev.State == progress.Failed {
+		e.Outcome = Failed
+		if ev.Err != nil {
+			e.Error = ev.Err.Error()
+		}
+	}
+	l.add(e)
+}
+
+// Add records an action; a non-nil err marks it failed
+func (l *Log) Add(action Action, target string, outcome Outcome, detail string, err error) {
+	if l == nil {
+		return
+	}
+	e := Event{Action: action, Target: target, Outcome: outcome, Detail: detail}
+	if err != nil {
+		e.Outcome, e.Error = Failed, err.Error()
+	}
+	l.add(e)
+}
+
+// AddFile records a written file with its SHA-256
+func (l *Log) AddFile(path string) {
+	if l == nil {
+		return
+	}
+	digest, err := fileDigest(path)
+	l.Add(ActionWrite, path, OK, digest, err)
+}
+
+// AddWritten records a written file with the SHA-256 computed while writing it
+// Unlike AddFile the file is not read back.
+func (l *Log) AddWritten(w *coreio.Written) {
+	l.Add(ActionWrite, w.Path, OK, w.Hex(), nil)
+}
+
+// AddKey records a key used during the run by role and key ID
+func (l *Log) AddKey(role, keyID string) {
+	l.Add(ActionKey, role, OK, keyID, nil)
+}
+
+// add stamps e and appends it
+func (l *Log) add(e Event) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	e.Time = l.now().UTC()
+	l.events = append(l.events, e)
+}
+
+// Events returns a copy of the recorded events in order
+func (l *Log) Events() []Event {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Event(nil), l.events...)
+}
+
+// Write signs the log and writes "<base>.audit.json" and its signature to dir
+// A nil keyPair signs with a fresh per-run key (Record.Ephemeral); format is
+// the signature's crypto.SignatureFormat*.
+// Returns the paths written.
+// Complexity: O(|events|)
+func (l *Log) Write(dir, base, hostname string, c *consent.Record, keyPair *crypto.KeyPair, format string) ([]string, error) {
+	ephemeral := keyPair == nil
+	if ephemeral {
+		var err error
+		if keyPair, err = crypto.GenerateKeyPair(); err != nil {
+			return nil, err
+		}
+	}
+
+	rec := &Record{
+		Format:     Format,
+		RunID:      l.runID,
+		Hostname:   hostname,
+		StartedAt:  l.started,
+		FinishedAt: l.now().UTC(),
+		Consent:    c,
+		Events:     l.Events(),
+		PublicKey:  base64.StdEncoding.EncodeToString(keyPair.PublicKey),
+		KeyID:      KeyID(keyPair.PublicKey),
+		Ephemeral:  ephemeral,
+	}
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+	sig, err := crypto.NewSigner(keyPair).SignDetached(data, format, crypto.MinisignComment(base+Suffix, l.runID))
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(dir, base+Suffix)
+	if err := coreio.NewWriter().WriteBinary(path, data); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	sigPath := filepath.Join(dir, base+SignatureSuffix)
+	if err := crypto.SaveDetached(sig, sigPath); err != nil {
+		return []string{path}, err
+	}
+	return []string{path, sigPath}, nil
+}
+
+// Verify reads an audit file and checks its detached signature
+// With trusted nil the embedded public key is used, which proves the file
+// is intact but not who signed it; pass the audit.signing_key public key
+// for that.
+// Complexity: O(|file|)
+func Verify(path string, trusted ed25519.PublicKey) (*Record, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := os.ReadFile(strings.TrimSuffix(path, Suffix) + SignatureSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signature file: %w", err)
+	}
+
+	var rec Record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("failed to parse audit record: %w", err)
+	}
+	if rec.Format != Format {
+		return nil, fmt.Errorf("unsupported audit format %q", rec.Format)
+	}
+	key := trusted
+	if key == nil {
+		embedded, err := base64.StdEncoding.DecodeString(rec.PublicKey)
+		if err != nil || len(embedded) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid embedded public key")
+		}
+		key = ed25519.PublicKey(embedded)
+	}
+	if !crypto.VerifyDetached(key, data, sig) {
+		return nil, fmt.Errorf("audit signature does not verify")
+	}
+	return &rec, nil
+}
+
+// KeyID is the hex SHA-256 of an Ed25519 public key (as in bundle metadata)
+func KeyID(pub ed25519.PublicKey) string {
+	return crypto.Fingerprint(pub)
+}
+
+// fileDigest returns the hex SHA-256 of a file
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
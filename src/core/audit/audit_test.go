@@ -0,0 +1,152 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/consent"
+	"github.com/minibeast/usb-agent/src/core/crypto"
+	coreio "github.com/minibeast/usb-agent/src/core/io"
+	"github.com/minibeast/usb-agent/src/core/progress"
+)
+
+// testRunID is the run ID of every test log
+const testRunID = "01JPAX1Z5C8K2M3N4P5Q6R7S8T"
+
+// clock returns a deterministic time source advancing one second per call
+func clock() func() time.Time {
+	t := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		t = t.Add(time.Second)
+		return t
+	}
+}
+
+func TestWithLog_RecordsStagesAndKeepsReporter(t *testing.T) {
+	var shown []string
+	ctx := progress.WithReporter(context.Background(), func(ev progress.Event) { shown = append(shown, ev.Stage) })
+	l := New(testRunID, clock())
+	ctx = WithLog(ctx, l)
+
+	progress.Start(ctx, "collect.system_info").End(nil)
+	progress.Start(ctx, "collect.pii_info").End(errors.New("permission denied"))
+	progress.Start(ctx, "rules").End(nil)
+	From(ctx).AddKey("recipient", "abc123")
+
+	if len(shown) != 6 {
+		t.Errorf("existing reporter saw %d events, want 6", len(shown))
+	}
+	events := l.Events()
+	if len(events) != 4 {
+		t.Fatalf("events = %+v", events)
+	}
+	if e := events[0]; e.Action != ActionCollect || e.Target != "system_info" || e.Outcome != OK {
+		t.Errorf("events[0] = %+v", e)
+	}
+	if e := events[1]; e.Outcome != Failed || e.Error != "permission denied" {
+		t.Errorf("events[1] = %+v", e)
+	}
+	if e := events[2]; e.Action != ActionRules || e.Target != "" {
+		t.Errorf("events[2] = %+v", e)
+	}
+	if e := events[3]; e.Action != ActionKey || e.Target != "recipient" || e.Detail != "abc123" {
+		t.Errorf("events[3] = %+v", e)
+	}
+}
+
+func TestFrom_NilLogIsNoOp(t *testing.T) {
+	l := From(context.Background())
+	l.Add(ActionExport, "webhook", OK, "", nil)
+	l.AddFile("missing")
+	l.AddKey("recipient", "x")
+}
+
+// TestAddWritten_MatchesAddFile verifies the streamed digest equals a re-read
+func TestAddWritten_MatchesAddFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "host_1.json")
+	written, err := coreio.NewWriter().WriteStream(path, strings.NewReader(`{"hostname":"host"}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := New(testRunID, clock())
+	l.AddWritten(written)
+	l.AddFile(path)
+
+	events := l.Events()
+	if len(events) != 2 || events[0].Detail == "" || events[0].Detail != events[1].Detail || events[0].Target != path {
+		t.Errorf("events = %+v, want equal digests", events)
+	}
+}
+
+func TestWrite_SignedAndVerified(t *testing.T) {
+	dir := t.TempDir()
+	artifact := filepath.Join(dir, "host_1.json")
+	if err := os.WriteFile(artifact, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	l := New(testRunID, clock())
+	l.AddFile(artifact)
+	l.Add(ActionExport, "webhook", Spooled, "", nil)
+
+	keyPair, err := crypto.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := &consent.Record{Operator: "JD", Method: consent.MethodInteractive}
+	paths, err := l.Write(dir, "host_1", "host", record, keyPair, crypto.SignatureFormatRaw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 2 || filepath.Base(paths[0]) != "host_1"+Suffix || !strings.HasSuffix(paths[1], SignatureSuffix) {
+		t.Fatalf("paths = %v", paths)
+	}
+
+	rec, err := Verify(paths[0], keyPair.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.RunID != testRunID || rec.Ephemeral || rec.KeyID != KeyID(keyPair.PublicKey) || rec.Consent.Operator != "JD" {
+		t.Errorf("record = %+v", rec)
+	}
+	// SHA-256 of "{}"
+	if e := rec.Events[0]; e.Action != ActionWrite || e.Detail != "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a" {
+		t.Errorf("write event = %+v", e)
+	}
+	if !rec.FinishedAt.After(rec.StartedAt) {
+		t.Errorf("finished %s not after started %s", rec.FinishedAt, rec.StartedAt)
+	}
+
+	// A different trusted key or an edited file fails
+	other, _ := crypto.GenerateKeyPair()
+	if _, err := Verify(paths[0], other.PublicKey); err == nil {
+		t.Error("verified against the wrong key")
+	}
+	data, _ := os.ReadFile(paths[0])
+	edited := strings.Replace(string(data), `"spooled"`, `"ok"`, 1)
+	if err := os.WriteFile(paths[0], []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Verify(paths[0], nil); err == nil {
+		t.Error("edited audit file verified")
+	}
+}
+
+func TestWrite_EphemeralKey(t *testing.T) {
+	dir := t.TempDir()
+	paths, err := New(testRunID, clock()).Write(dir, "run", "", nil, nil, crypto.SignatureFormatMinisign)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec, err := Verify(paths[0], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rec.Ephemeral || rec.PublicKey == "" {
+		t.Errorf("record = %+v", rec)
+	}
+}
@@ -0,0 +1,200 @@
+// Package bench measures collection, inference and crypto latency
+// Used to qualify new models and target hardware before an engagement:
+// collection runs against platform.FakeCollector so only pipeline overhead is
+// timed, inference runs the configured model on the resulting facts, and the
+// crypto operations use fresh keys over a fixed-size artifact.
+package bench
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/crypto"
+	"github.com/minibeast/usb-agent/src/core/inference"
+	"github.com/minibeast/usb-agent/src/core/platform"
+	"github.com/minibeast/usb-agent/src/core/summarizer"
+)
+
+// ArtifactSize is the plaintext size used by the crypto benchmarks
+const ArtifactSize = 64 << 10
+
+// Result summarizes the latency of one benchmarked operation
+type Result struct {
+	Name   string        `json:"name"`
+	Runs   int           `json:"runs"`   // Successful runs measured
+	Errors int           `json:"errors"` // Failed runs (excluded from latencies)
+	Min    time.Duration `json:"min_ns"`
+	P50    time.Duration `json:"p50_ns"`
+	P90    time.Duration `json:"p90_ns"`
+	P99    time.Duration `json:"p99_ns"`
+	Max    time.Duration `json:"max_ns"`
+	Mean   time.Duration `json:"mean_ns"`
+
+	// TokensPerSec is generated tokens over wall-clock generation time
+	// (inference only)
+	TokensPerSec float64 `json:"tokens_per_sec,omitempty"`
+}
+
+// Measure runs op n times and summarizes the latency of successful runs
+// Complexity: O(n log n) for sorting the samples
+func Measure(name string, n int, op func() error) Result {
+	samples := make([]time.Duration, 0, n)
+	failed := 0
+	for i := 0; i < n; i++ {
+		start := time.Now()
+		if err := op(); err != nil {
+			failed++
+			continue
+		}
+		samples = append(samples, time.Since(start))
+	}
+	r := Summarize(name, samples)
+	r.Errors = failed
+	return r
+}
+
+// Summarize computes latency statistics over samples
+// Complexity: O(n log n)
+func Summarize(name string, samples []time.Duration) Result {
+	r := Result{Name: name, Runs: len(samples)}
+	if len(samples) == 0 {
+		return r
+	}
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, s := range sorted {
+		total += s
+	}
+	r.Min, r.Max = sorted[0], sorted[len(sorted)-1]
+	r.P50 = Percentile(sorted, 50)
+	r.P90 = Percentile(sorted, 90)
+	r.P99 = Percentile(sorted, 99)
+	r.Mean = total / time.Duration(len(sorted))
+	return r
+}
+
+// Percentile returns the nearest-rank p-th percentile of sorted samples
+// Mathematical definition: sorted[⌈p/100 · n⌉ - 1]
+// Complexity: O(1)
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(p / 100 * float64(len(sorted)))
+	if float64(rank) < p/100*float64(len(sorted)) {
+		rank++ // Ceiling
+	}
+	return sorted[min(max(rank, 1), len(sorted))-1]
+}
+
+// Collection runs CollectAll n times against platform.FakeCollector
+// Returns the facts of the last successful run for the inference benchmark.
+// Complexity: O(n · |categories|)
+func Collection(ctx context.Context, cfg *config.Config, n int) (Result, *collection.Facts) {
+	collector := collection.NewCollectorFrom(cfg, platform.FakeCollector{})
+	var facts *collection.Facts
+	r := Measure("collect (fake)", n, func() error {
+		f, err := collector.CollectAll(ctx)
+		if err == nil {
+			facts = f
+		}
+		return err
+	})
+	return r, facts
+}
+
+// Inference loads engine once, then generates a report for facts n times
+// Returns the load and generate results; a load failure is returned as error.
+// Complexity: O(n · maxTokens)
+func Inference(ctx context.Context, engine summarizer.Engine, facts *collection.Facts, n int) ([]Result, error) {
+	if facts == nil {
+		return nil, fmt.Errorf("facts cannot be nil")
+	}
+	prompt, err := inference.NewPromptBuilder().BuildPrompt(facts)
+	if err != nil {
+		return nil, fmt.Errorf("prompt build failed: %w", err)
+	}
+
+	start := time.Now()
+	if err := engine.Load(ctx); err != nil {
+		return nil, fmt.Errorf("model load failed: %w", err)
+	}
+	defer engine.Unload()
+	load := Summarize("model load", []time.Duration{time.Since(start)})
+
+	tokens := 0
+	generate := Measure("inference", n, func() error {
+		result, err := engine.Generate(ctx, prompt)
+		if err != nil {
+			return err
+		}
+		tokens += result.TokenCount
+		return nil
+	})
+	if total := generate.Mean * time.Duration(generate.Runs); total > 0 {
+		generate.TokensPerSec = float64(tokens) / total.Seconds()
+	}
+	return []Result{load, generate}, nil
+}
+
+// Crypto times signing, verification, envelope encryption and hashing of an
+// ArtifactSize payload, n runs each
+// Complexity: O(n · ArtifactSize)
+func Crypto(n int) ([]Result, error) {
+	keyPair, err := crypto.GenerateKeyPair()
+	if err != nil {
+		return nil, err
+	}
+	recipient, err := crypto.GenerateRecipientKey()
+	if err != nil {
+		return nil, err
+	}
+	recipients := []*ecdh.PublicKey{recipient.PublicKey()}
+
+	data := make([]byte, ArtifactSize)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	signer := crypto.NewSigner(keyPair)
+	signature, err := signer.Sign(data)
+	if err != nil {
+		return nil, err
+	}
+	envelope, err := crypto.Encrypt(data, recipients)
+	if err != nil {
+		return nil, err
+	}
+
+	return []Result{
+		Measure("sha256 64KiB", n, func() error {
+			sha256.Sum256(data)
+			return nil
+		}),
+		Measure("sign ed25519", n, func() error {
+			_, err := signer.Sign(data)
+			return err
+		}),
+		Measure("verify ed25519", n, func() error {
+			if !crypto.Verify(keyPair.PublicKey, data, signature) {
+				return fmt.Errorf("signature verification failed")
+			}
+			return nil
+		}),
+		Measure("encrypt x25519", n, func() error {
+			_, err := crypto.Encrypt(data, recipients)
+			return err
+		}),
+		Measure("decrypt x25519", n, func() error {
+			_, err := crypto.Decrypt(envelope, recipient)
+			return err
+		}),
+	}, nil
+}
@@ -0,0 +1,116 @@
+package bench
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/inference"
+)
+
+// TestPercentile verifies nearest-rank percentiles
+func TestPercentile(t *testing.T) {
+	sorted := make([]time.Duration, 100)
+	for i := range sorted {
+		sorted[i] = time.Duration(i+1) * time.Millisecond
+	}
+	tests := []struct {
+		p    float64
+		want time.Duration
+	}{
+		{0, 1 * time.Millisecond},
+		{50, 50 * time.Millisecond},
+		{90, 90 * time.Millisecond},
+		{99, 99 * time.Millisecond},
+		{100, 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := Percentile(sorted, tt.p); got != tt.want {
+			t.Errorf("P%v = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+
+	three := []time.Duration{10, 20, 30}
+	if got := Percentile(three, 50); got != 20 {
+		t.Errorf("P50 of 3 samples = %v, want 20", got)
+	}
+	if got := Percentile(nil, 50); got != 0 {
+		t.Errorf("P50 of no samples = %v, want 0", got)
+	}
+}
+
+// TestSummarize verifies the statistics over unsorted samples
+func TestSummarize(t *testing.T) {
+	r := Summarize("op", []time.Duration{40, 10, 30, 20})
+	if r.Runs != 4 || r.Min != 10 || r.Max != 40 || r.P50 != 20 || r.Mean != 25 {
+		t.Errorf("Unexpected summary: %+v", r)
+	}
+	if empty := Summarize("none", nil); empty.Runs != 0 || empty.P50 != 0 {
+		t.Errorf("Unexpected empty summary: %+v", empty)
+	}
+}
+
+// TestMeasure_Errors verifies failed runs are counted but not timed
+func TestMeasure_Errors(t *testing.T) {
+	i := 0
+	r := Measure("flaky", 10, func() error {
+		i++
+		if i%2 == 0 {
+			return errors.New("failed")
+		}
+		return nil
+	})
+	if r.Runs != 5 || r.Errors != 5 {
+		t.Errorf("Runs %d, errors %d; want 5 and 5", r.Runs, r.Errors)
+	}
+}
+
+// TestCollectionAndInference verifies the fake collection feeds inference
+func TestCollectionAndInference(t *testing.T) {
+	ctx := context.Background()
+	r, facts := Collection(ctx, config.Default(), 3)
+	if r.Runs != 3 || r.Errors != 0 || facts == nil {
+		t.Fatalf("Collection: %+v, facts %v", r, facts)
+	}
+	if facts.Hostname != "bench-host" {
+		t.Errorf("Facts not from the fake collector: hostname %q", facts.Hostname)
+	}
+
+	engine := inference.NewFakeEngine()
+	results, err := Inference(ctx, engine, facts, 4)
+	if err != nil {
+		t.Fatalf("Inference failed: %v", err)
+	}
+	if len(results) != 2 || results[0].Name != "model load" || results[1].Runs != 4 {
+		t.Fatalf("Unexpected results: %+v", results)
+	}
+	if results[1].TokensPerSec <= 0 {
+		t.Errorf("Tokens/sec not reported: %+v", results[1])
+	}
+	if engine.IsLoaded() {
+		t.Error("Engine left loaded")
+	}
+
+	engine.LoadErr = errors.New("no model")
+	if _, err := Inference(ctx, engine, facts, 1); err == nil {
+		t.Error("Expected load failure")
+	}
+}
+
+// TestCrypto verifies every crypto operation succeeds
+func TestCrypto(t *testing.T) {
+	results, err := Crypto(2)
+	if err != nil {
+		t.Fatalf("Crypto failed: %v", err)
+	}
+	if len(results) != 5 {
+		t.Fatalf("Got %d results, want 5", len(results))
+	}
+	for _, r := range results {
+		if r.Runs != 2 || r.Errors != 0 {
+			t.Errorf("%s: %d runs, %d errors", r.Name, r.Runs, r.Errors)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/consent"
+	"github.com/minibeast/usb-agent/src/core/crypto"
+	"github.com/minibeast/usb-agent/src/core/report"
+)
+
+// testContents returns a run with a report
+func testContents() *Contents {
+	return &Contents{
+		RunID: "run-1",
+		Facts: &collection.Facts{
+			Timestamp:        time.Date(2025, 11, 9, 12, 0, 0, 0, time.UTC),
+			CollectorVersion: "1.0.0",
+			Hostname:         "test-host",
+			HardwareUUID:     "uuid-1",
+			OSName:           "Linux",
+		},
+		Report: &report.Report{
+			Summary: []string{"System looks healthy"},
+			Risks:   []report.Risk{{Text: "OS is end-of-life", Severity: report.SeverityHigh, FindingID: "MB-OS-EOL"}},
+		},
+	}
+}
+
+func TestWriteVerifyBundle(t *testing.T) {
+	kp, _ := crypto.GenerateKeyPair()
+	path := filepath.Join(t.TempDir(), "out", "run-1"+Extension)
+	if err := WriteBundle(path, testContents(), kp); err != nil {
+		t.Fatalf("WriteBundle() failed: %v", err)
+	}
+
+	v, err := VerifyBundle(path, kp.PublicKey)
+	if err != nil {
+		t.Fatalf("VerifyBundle() failed: %v", err)
+	}
+	if !v.Trusted || v.Metadata.RunID != "run-1" || v.Metadata.Hostname != "test-host" {
+		t.Errorf("Unexpected result: %+v", v.Metadata)
+	}
+	if v.Metadata.KeyID != crypto.Fingerprint(kp.PublicKey) {
+		t.Errorf("KeyID = %s, want the key's fingerprint", v.Metadata.KeyID)
+	}
+	if len(v.Manifest.Files) != 4 || v.ReportJSON == nil || !strings.Contains(string(v.ReportText), "end-of-life") {
+		t.Errorf("Unexpected contents: manifest=%+v", v.Manifest.Files)
+	}
+
+	// Self-consistency check without a trusted key
+	if v, err := VerifyBundle(path, nil); err != nil || v.Trusted {
+		t.Errorf("Untrusted VerifyBundle() = %v, trusted=%v", err, v != nil && v.Trusted)
+	}
+
+	// Another key is rejected
+	other, _ := crypto.GenerateKeyPair()
+	if _, err := VerifyBundle(path, other.PublicKey); err == nil {
+		t.Error("Bundle verified against the wrong key")
+	}
+}
+
+// TestWriteSigned verifies the detached archive signature is written and
+// checked by VerifyBundle when present
+func TestWriteSigned(t *testing.T) {
+	kp, _ := crypto.GenerateKeyPair()
+	path := filepath.Join(t.TempDir(), "run-1"+Extension)
+	data, sig, err := WriteSigned(path, testContents(), kp, crypto.SignatureFormatRaw)
+	if err != nil {
+		t.Fatalf("WriteSigned() failed: %v", err)
+	}
+	if !crypto.Verify(kp.PublicKey, data, sig) {
+		t.Error("Returned signature does not cover the archive")
+	}
+	if _, err := VerifyBundle(path, kp.PublicKey); err != nil {
+		t.Fatalf("VerifyBundle() failed: %v", err)
+	}
+
+	// A bundle replaced by another validly built one fails the detached check
+	c := testContents()
+	c.RunID = "run-2"
+	if err := WriteBundle(path, c, kp); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyBundle(path, nil); err == nil || !strings.Contains(err.Error(), "bundle signature") {
+		t.Errorf("Replaced bundle: err = %v", err)
+	}
+
+	// Without the signature file only the bundle itself is checked
+	if err := os.Remove(path + SignatureSuffix); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyBundle(path, kp.PublicKey); err != nil {
+		t.Errorf("Unsigned bundle: %v", err)
+	}
+
+	// A minisign signature is checked the same way
+	data, sig, err = WriteSigned(path, testContents(), kp, crypto.SignatureFormatMinisign)
+	if err != nil {
+		t.Fatalf("WriteSigned(minisign) failed: %v", err)
+	}
+	if comment, err := crypto.VerifyMinisign(kp.PublicKey, data, sig); err != nil || comment != "file:run-1.mbz\trun:run-1" {
+		t.Errorf("VerifyMinisign() = %q, %v", comment, err)
+	}
+	if _, err := VerifyBundle(path, kp.PublicKey); err != nil {
+		t.Errorf("VerifyBundle(minisign) failed: %v", err)
+	}
+}
+
+func TestBuild_Deterministic(t *testing.T) {
+	kp, _ := crypto.GenerateKeyPair()
+	a, err := Build(testContents(), kp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := Build(testContents(), kp)
+	if !bytes.Equal(a, b) {
+		t.Error("Same contents and key produced different bundles")
+	}
+}
+
+func TestBuild_FactsOnly(t *testing.T) {
+	kp, _ := crypto.GenerateKeyPair()
+	c := testContents()
+	c.Report = nil
+	data, err := Build(c, kp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := Verify(data, kp.PublicKey)
+	if err != nil {
+		t.Fatalf("Verify() failed: %v", err)
+	}
+	if v.ReportJSON != nil || len(v.Manifest.Files) != 2 {
+		t.Errorf("Facts-only bundle has report members: %+v", v.Manifest.Files)
+	}
+}
+
+// rewrite rebuilds a bundle, letting edit change, drop (nil) or keep each member
+func rewrite(t *testing.T, data []byte, edit func(name string, content []byte) []byte, extra map[string][]byte) []byte {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range zr.File {
+		content, _ := readMember(f)
+		if content = edit(f.Name, content); content == nil {
+			continue
+		}
+		w, _ := zw.Create(f.Name)
+		w.Write(content)
+	}
+	for name, content := range extra {
+		w, _ := zw.Create(name)
+		w.Write(content)
+	}
+	zw.Close()
+	return buf.Bytes()
+}
+
+// editMember returns a rewrite edit that applies fn to target only (nil drops it)
+func editMember(target string, fn func([]byte) []byte) func(string, []byte) []byte {
+	return func(name string, content []byte) []byte {
+		if name != target {
+			return content
+		}
+		return fn(content)
+	}
+}
+
+func TestVerify_Tampering(t *testing.T) {
+	kp, _ := crypto.GenerateKeyPair()
+	data, err := Build(testContents(), kp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	drop := func([]byte) []byte { return nil }
+
+	cases := map[string][]byte{
+		"modified report": rewrite(t, data, editMember(ReportTextFile, func([]byte) []byte { return []byte("all clear") }), nil),
+		"modified facts": rewrite(t, data, editMember(FactsFile, func(c []byte) []byte {
+			return bytes.Replace(c, []byte("test-host"), []byte("evil-host"), 1)
+		}), nil),
+		"dropped report": rewrite(t, data, editMember(ReportJSONFile, drop), nil),
+		"missing sig":    rewrite(t, data, editMember(FactsSigFile, drop), nil),
+		"extra member":   rewrite(t, data, editMember("", drop), map[string][]byte{"payload.exe": []byte("MZ")}),
+		"not a zip":      []byte("MBE1 not a zip"),
+	}
+	for name, tampered := range cases {
+		if _, err := Verify(tampered, kp.PublicKey); err == nil {
+			t.Errorf("%s: bundle verified", name)
+		}
+	}
+}
+
+// TestBuild_Consent verifies the operator acknowledgment is signed into metadata.json
+func TestBuild_Consent(t *testing.T) {
+	kp, _ := crypto.GenerateKeyPair()
+	c := testContents()
+	c.Consent = &consent.Record{
+		Operator:       "JD",
+		AcknowledgedAt: time.Date(2025, 11, 9, 11, 59, 0, 0, time.UTC),
+		Method:         consent.MethodInteractive,
+		Categories:     []string{"system_info", "pii_info"},
+	}
+	path := filepath.Join(t.TempDir(), "run-1"+Extension)
+	if err := WriteBundle(path, c, kp); err != nil {
+		t.Fatalf("WriteBundle() failed: %v", err)
+	}
+
+	v, err := VerifyBundle(path, kp.PublicKey)
+	if err != nil {
+		t.Fatalf("VerifyBundle() failed: %v", err)
+	}
+	got := v.Metadata.Consent
+	if got == nil || got.Operator != "JD" || got.Method != consent.MethodInteractive || len(got.Categories) != 2 {
+		t.Errorf("Metadata.Consent = %+v", got)
+	}
+}
@@ -0,0 +1,97 @@
+// Package bundle defines the single-file signed .mbz output bundle
+//
+// An .mbz is a zip archive with a fixed layout:
+//
+//	metadata.json                  run and signing-key metadata
+//	facts.json                     collected Facts (same bytes as the loose facts.json)
+//	report.json, report.txt        structured and text report (when the LLM phase ran)
+//	manifest.json                  name, size and SHA-256 of every file above
+//	signatures/manifest.json.sig   Ed25519 signature over manifest.json
+//	signatures/facts.json.sig      detached facts signature (same as the loose .sig)
+//
+// Signing the manifest transitively covers every listed file, so a single
+// signature check plus per-file hashing verifies the whole bundle.
+// WriteSigned also writes "<bundle>.mbz.sig", a detached Ed25519 signature
+// over the archive bytes, so the file can be checked as one blob before it
+// is opened.
+package bundle
+
+import (
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/consent"
+	"github.com/minibeast/usb-agent/src/core/report"
+)
+
+// Format identifies the bundle layout version in metadata and manifest
+const Format = "mbz/1"
+
+// Extension is the conventional bundle file extension
+const Extension = ".mbz"
+
+// SignatureSuffix names the detached archive signature ("<bundle>.mbz.sig")
+const SignatureSuffix = ".sig"
+
+// Fixed entry names
+const (
+	MetadataFile    = "metadata.json"
+	FactsFile       = "facts.json"
+	ReportJSONFile  = "report.json"
+	ReportTextFile  = "report.txt"
+	ManifestFile    = "manifest.json"
+	ManifestSigFile = "signatures/manifest.json.sig"
+	FactsSigFile    = "signatures/facts.json.sig"
+)
+
+// maxEntrySize bounds each decompressed member (zip-bomb guard for VerifyBundle)
+const maxEntrySize = 64 << 20
+
+// Contents is everything WriteBundle packs for one run
+type Contents struct {
+	RunID   string            // Run identifier
+	Facts   *collection.Facts // Collected facts (required)
+	Report  *report.Report    // Structured report (nil when the LLM phase did not run)
+	Consent *consent.Record   // Operator acknowledgment (nil when none was given)
+}
+
+// Metadata is metadata.json
+type Metadata struct {
+	Format           string    `json:"format"`
+	RunID            string    `json:"run_id"`
+	Hostname         string    `json:"hostname"`
+	HardwareUUID     string    `json:"hardware_uuid"`
+	CollectedAt      time.Time `json:"collected_at"`
+	CollectorVersion string    `json:"collector_version"`
+	PublicKey        string    `json:"public_key"` // Base64 Ed25519 signing key
+	KeyID            string    `json:"key_id"`     // crypto.Fingerprint of the signing key
+
+	// Operator acknowledgment, signed with the rest of the metadata
+	Consent *consent.Record `json:"consent,omitempty"`
+}
+
+// Manifest is manifest.json
+type Manifest struct {
+	Format string          `json:"format"`
+	Files  []ManifestEntry `json:"files"` // In archive order
+}
+
+// ManifestEntry describes one signed file
+type ManifestEntry struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Verified is the result of a successful VerifyBundle
+type Verified struct {
+	Metadata   Metadata
+	Manifest   Manifest
+	Facts      []byte // facts.json bytes
+	ReportJSON []byte // report.json bytes (nil when absent)
+	ReportText []byte // report.txt bytes (nil when absent)
+
+	// Trusted is true when the signing key matched a caller-supplied key;
+	// false means only the embedded key was checked (self-consistent, not authenticated)
+	Trusted bool
+}
@@ -0,0 +1,163 @@
+package bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/minibeast/usb-agent/src/core/crypto"
+)
+
+// VerifyBundle reads and fully verifies the bundle at path
+// With a nil trusted key only internal consistency is checked (Verified.Trusted = false).
+// A detached path+SignatureSuffix, when present, must verify against the
+// same key as the bundle's manifest.
+// Complexity: O(bundle size)
+func VerifyBundle(path string, trusted ed25519.PublicKey) (*Verified, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+	v, err := Verify(data, trusted)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := os.ReadFile(path + SignatureSuffix)
+	if errors.Is(err, fs.ErrNotExist) {
+		return v, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle signature: %w", err)
+	}
+	publicKey, _ := base64.StdEncoding.DecodeString(v.Metadata.PublicKey) // Checked by Verify
+	if !crypto.VerifyDetached(publicKey, data, sig) {
+		return nil, fmt.Errorf("bundle signature verification failed")
+	}
+	return v, nil
+}
+
+// Verify checks a bundle held in memory
+// Verification steps:
+//  1. Layout: only known members, each exactly once, required members present
+//  2. Key: embedded key matches metadata key ID (and trusted, when given)
+//  3. Manifest: Ed25519 signature, then it lists exactly the content members
+//  4. Files: size and SHA-256 of every member match the manifest
+//  5. Facts: detached facts signature verifies
+//
+// Complexity: O(bundle size)
+func Verify(data []byte, trusted ed25519.PublicKey) (*Verified, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("not a zip archive: %w", err)
+	}
+
+	// Step 1: Layout
+	known := map[string]bool{
+		MetadataFile: true, FactsFile: true, ReportJSONFile: true, ReportTextFile: true,
+		ManifestFile: true, ManifestSigFile: true, FactsSigFile: true,
+	}
+	members := make(map[string][]byte, len(zr.File))
+	for _, f := range zr.File {
+		if !known[f.Name] {
+			return nil, fmt.Errorf("unexpected member %q", f.Name)
+		}
+		if _, dup := members[f.Name]; dup {
+			return nil, fmt.Errorf("duplicate member %q", f.Name)
+		}
+		content, err := readMember(f)
+		if err != nil {
+			return nil, err
+		}
+		members[f.Name] = content
+	}
+	for _, required := range []string{MetadataFile, FactsFile, ManifestFile, ManifestSigFile, FactsSigFile} {
+		if _, ok := members[required]; !ok {
+			return nil, fmt.Errorf("missing member %q", required)
+		}
+	}
+
+	// Step 2: Key
+	var v Verified
+	if err := json.Unmarshal(members[MetadataFile], &v.Metadata); err != nil {
+		return nil, fmt.Errorf("invalid metadata: %w", err)
+	}
+	if v.Metadata.Format != Format {
+		return nil, fmt.Errorf("unsupported bundle format %q", v.Metadata.Format)
+	}
+	publicKey, err := base64.StdEncoding.DecodeString(v.Metadata.PublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid embedded public key")
+	}
+	if crypto.Fingerprint(publicKey) != v.Metadata.KeyID {
+		return nil, fmt.Errorf("embedded public key does not match key ID")
+	}
+	if trusted != nil {
+		if !bytes.Equal(publicKey, trusted) {
+			return nil, fmt.Errorf("bundle signed by untrusted key %s", v.Metadata.KeyID)
+		}
+		v.Trusted = true
+	}
+
+	// Step 3: Manifest
+	if !crypto.Verify(publicKey, members[ManifestFile], members[ManifestSigFile]) {
+		return nil, fmt.Errorf("manifest signature verification failed")
+	}
+	if err := json.Unmarshal(members[ManifestFile], &v.Manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	contentMembers := len(members) - 3 // manifest + two signatures
+	if len(v.Manifest.Files) != contentMembers {
+		return nil, fmt.Errorf("manifest lists %d files, bundle has %d", len(v.Manifest.Files), contentMembers)
+	}
+
+	// Step 4: Files (each listed once, so the count check above covers every member)
+	listed := make(map[string]bool, len(v.Manifest.Files))
+	for _, entry := range v.Manifest.Files {
+		content, ok := members[entry.Name]
+		if !ok || listed[entry.Name] || entry.Name == ManifestFile || entry.Name == ManifestSigFile || entry.Name == FactsSigFile {
+			return nil, fmt.Errorf("manifest lists invalid member %q", entry.Name)
+		}
+		listed[entry.Name] = true
+		sum := sha256.Sum256(content)
+		if int64(len(content)) != entry.Size || hex.EncodeToString(sum[:]) != entry.SHA256 {
+			return nil, fmt.Errorf("member %q does not match manifest", entry.Name)
+		}
+	}
+
+	// Step 5: Facts
+	if !crypto.Verify(publicKey, members[FactsFile], members[FactsSigFile]) {
+		return nil, fmt.Errorf("facts signature verification failed")
+	}
+
+	v.Facts = members[FactsFile]
+	v.ReportJSON = members[ReportJSONFile]
+	v.ReportText = members[ReportTextFile]
+	return &v, nil
+}
+
+// readMember decompresses one member with the size guard
+func readMember(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %q: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(io.LimitReader(rc, maxEntrySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %q: %w", f.Name, err)
+	}
+	if len(content) > maxEntrySize {
+		return nil, fmt.Errorf("member %q exceeds %d bytes", f.Name, maxEntrySize)
+	}
+	return content, nil
+}
@@ -0,0 +1,161 @@
+package bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/minibeast/usb-agent/src/core/crypto"
+	"github.com/minibeast/usb-agent/src/core/io"
+)
+
+// entry is one archive member
+type entry struct {
+	name string
+	data []byte
+}
+
+// WriteBundle builds the bundle for c, signs it with keyPair and writes it atomically to path
+// Mathematical guarantee: Either a complete, signed bundle exists at path or nothing changes
+// Complexity: O(|Facts| + |Report|)
+func WriteBundle(path string, c *Contents, keyPair *crypto.KeyPair) error {
+	data, err := Build(c, keyPair)
+	if err != nil {
+		return err
+	}
+	return io.NewWriter().WriteBinary(path, data)
+}
+
+// WriteSigned writes the bundle for c atomically to path and a detached
+// signature over the archive to path+SignatureSuffix
+// format is the signature's crypto.SignatureFormat*. Returns the archive and
+// signature bytes, e.g. for attaching to a mail.
+// The signature is written last, so a run interrupted in between leaves an
+// unsigned bundle rather than a signature without its archive.
+// Complexity: O(|Facts| + |Report|)
+func WriteSigned(path string, c *Contents, keyPair *crypto.KeyPair, format string) ([]byte, []byte, error) {
+	data, err := Build(c, keyPair)
+	if err != nil {
+		return nil, nil, err
+	}
+	sig, err := crypto.NewSigner(keyPair).SignDetached(data, format, crypto.MinisignComment(filepath.Base(path), c.RunID))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to sign bundle: %w", err)
+	}
+	if err := io.NewWriter().WriteBinary(path, data); err != nil {
+		return nil, nil, err
+	}
+	if err := crypto.SaveDetached(sig, path+SignatureSuffix); err != nil {
+		return nil, nil, err
+	}
+	return data, sig, nil
+}
+
+// Build returns the bundle bytes for c
+// Mathematical property: Same Contents and key → Same bytes (Ed25519 is
+// deterministic and every zip timestamp is the collection time)
+// Complexity: O(|Facts| + |Report|)
+func Build(c *Contents, keyPair *crypto.KeyPair) ([]byte, error) {
+	if c == nil || c.Facts == nil {
+		return nil, fmt.Errorf("bundle facts cannot be nil")
+	}
+	if keyPair == nil {
+		return nil, fmt.Errorf("bundle signing key cannot be nil")
+	}
+	signer := crypto.NewSigner(keyPair)
+
+	metadata, err := json.MarshalIndent(Metadata{
+		Format:           Format,
+		RunID:            c.RunID,
+		Hostname:         c.Facts.Hostname,
+		HardwareUUID:     c.Facts.HardwareUUID,
+		CollectedAt:      c.Facts.Timestamp.UTC(),
+		CollectorVersion: c.Facts.CollectorVersion,
+		PublicKey:        base64.StdEncoding.EncodeToString(keyPair.PublicKey),
+		KeyID:            crypto.Fingerprint(keyPair.PublicKey),
+		Consent:          c.Consent,
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	facts, err := c.Facts.CanonicalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	files := []entry{
+		{MetadataFile, metadata},
+		{FactsFile, facts},
+	}
+	if c.Report != nil {
+		reportJSON, err := c.Report.RenderJSON()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files,
+			entry{ReportJSONFile, reportJSON},
+			entry{ReportTextFile, []byte(c.Report.RenderText())},
+		)
+	}
+
+	manifest := Manifest{Format: Format, Files: make([]ManifestEntry, 0, len(files))}
+	for _, f := range files {
+		sum := sha256.Sum256(f.data)
+		manifest.Files = append(manifest.Files, ManifestEntry{
+			Name: f.name, Size: int64(len(f.data)), SHA256: hex.EncodeToString(sum[:]),
+		})
+	}
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+
+	manifestSig, err := signer.Sign(manifestData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign manifest: %w", err)
+	}
+	factsSig, err := signer.Sign(facts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign facts: %w", err)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	add := func(name string, data []byte) error {
+		w, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     name,
+			Method:   zip.Deflate,
+			Modified: c.Facts.Timestamp.UTC(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add %s: %w", name, err)
+		}
+		_, err = w.Write(data)
+		return err
+	}
+
+	for _, f := range files {
+		if err := add(f.name, f.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := add(ManifestFile, manifestData); err != nil {
+		return nil, err
+	}
+	if err := add(ManifestSigFile, manifestSig); err != nil {
+		return nil, err
+	}
+	if err := add(FactsSigFile, factsSig); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finalize bundle: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,97 @@
+// Package clock separates the two kinds of time a run uses
+// Wall time stamps facts, reports and run IDs; monotonic time measures
+// durations, so an NTP step or a manual clock change mid-run cannot shrink,
+// stretch or negate them. Components take a Clock so tests can replace
+// both with a Fake and get byte-identical output.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock supplies wall and monotonic time
+type Clock interface {
+	// Now returns the wall-clock time, for timestamps
+	Now() time.Time
+
+	// Elapsed returns monotonic time since a fixed, arbitrary origin, for
+	// durations; it never goes backwards
+	Elapsed() time.Duration
+}
+
+// System is the operating system clock
+var System Clock = systemClock{}
+
+// origin anchors the system clock's monotonic readings
+var origin = time.Now()
+
+type systemClock struct{}
+
+// Now returns the current wall time without its monotonic reading
+func (systemClock) Now() time.Time { return time.Now().Round(0) }
+
+// Elapsed returns monotonic time since process start
+func (systemClock) Elapsed() time.Duration { return time.Since(origin) }
+
+// Timer measures a duration on a Clock's monotonic time
+type Timer struct {
+	clock Clock
+	start time.Duration
+}
+
+// Start begins timing on c
+// Complexity: O(1)
+func Start(c Clock) Timer {
+	return Timer{clock: c, start: c.Elapsed()}
+}
+
+// Elapsed returns the monotonic time since Start
+// Complexity: O(1)
+func (t Timer) Elapsed() time.Duration {
+	return t.clock.Elapsed() - t.start
+}
+
+// Fake is a manually driven Clock for tests
+// Advance moves both wall and monotonic time; Set steps only the wall
+// clock, as an NTP correction or an operator changing the time would.
+type Fake struct {
+	mu   sync.Mutex
+	wall time.Time
+	mono time.Duration
+}
+
+// NewFake creates a fake clock reading wall
+// Complexity: O(1)
+func NewFake(wall time.Time) *Fake {
+	return &Fake{wall: wall}
+}
+
+// Now returns the fake wall time
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.wall
+}
+
+// Elapsed returns the fake monotonic time
+func (f *Fake) Elapsed() time.Duration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.mono
+}
+
+// Advance moves wall and monotonic time forward by d
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.wall = f.wall.Add(d)
+	f.mono += d
+}
+
+// Set steps the wall clock to wall, leaving monotonic time alone
+func (f *Fake) Set(wall time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.wall = wall
+}
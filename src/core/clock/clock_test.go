@@ -0,0 +1,37 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+// TestFake_WallStepKeepsDurations verifies Set moves timestamps but not timers
+func TestFake_WallStepKeepsDurations(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)
+	fake := NewFake(start)
+	timer := Start(fake)
+
+	fake.Advance(2 * time.Second)
+	fake.Set(start.Add(-time.Hour)) // Clock stepped back mid-run
+	fake.Advance(500 * time.Millisecond)
+
+	if got := timer.Elapsed(); got != 2500*time.Millisecond {
+		t.Errorf("Elapsed() = %v, want 2.5s", got)
+	}
+	if want := start.Add(-time.Hour + 500*time.Millisecond); !fake.Now().Equal(want) {
+		t.Errorf("Now() = %v, want %v", fake.Now(), want)
+	}
+}
+
+// TestSystem verifies the system clock strips monotonic readings from timestamps
+func TestSystem(t *testing.T) {
+	now := System.Now()
+	if now != now.Round(0) {
+		t.Error("Now() carries a monotonic reading")
+	}
+	timer := Start(System)
+	time.Sleep(time.Millisecond)
+	if timer.Elapsed() < time.Millisecond {
+		t.Errorf("Elapsed() = %v, want at least 1ms", timer.Elapsed())
+	}
+}
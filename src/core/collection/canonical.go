@@ -0,0 +1,31 @@
+package collection
+
+import (
+	"fmt"
+
+	"github.com/minibeast/usb-agent/src/core/jsonenc"
+)
+
+// CanonicalJSON returns the canonical encoding of f: the bytes written to
+// facts.json and the only bytes that are hashed or signed
+// Object keys are sorted at every level (extension output included), the
+// timestamp is RFC 3339 in UTC, indentation is two spaces with no trailing
+// newline, and numbers and string escaping are those of encoding/json. Equal
+// Facts therefore give equal bytes whatever their field order, time zone or
+// the whitespace a plugin emitted, and decoding the result then encoding it
+// again is byte-identical. Extension objects repeating a key are rejected.
+// Complexity: O(|Facts| log k) where k = keys per object
+func (f *Facts) CanonicalJSON() ([]byte, error) {
+	return f.AppendCanonicalJSON(nil)
+}
+
+// AppendCanonicalJSON appends the CanonicalJSON bytes of f to dst in one pass
+// Reuse dst (or size it from the previous run) to avoid re-growing the buffer.
+// Complexity: O(|Facts| log k) where k = keys per object
+func (f *Facts) AppendCanonicalJSON(dst []byte) ([]byte, error) {
+	out, err := f.appendJSON(jsonenc.Writer{Buf: dst, Indent: "  ", Sorted: true}, f.Timestamp.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal facts: %w", err)
+	}
+	return out, nil
+}
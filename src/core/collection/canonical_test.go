@@ -0,0 +1,187 @@
+package collection
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// filledFacts returns Facts with every field set (see fill)
+func filledFacts() *Facts {
+	f := &Facts{}
+	n := 0
+	fill(reflect.ValueOf(f).Elem(), &n)
+	return f
+}
+
+// checkSorted fails unless every object in data lists its keys in order
+func checkSorted(t *testing.T, data []byte) {
+	t.Helper()
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var walk func() error
+	walk = func() error {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'):
+			var keys []string
+			for dec.More() {
+				key, _ := dec.Token()
+				keys = append(keys, key.(string))
+				if err := walk(); err != nil {
+					return err
+				}
+			}
+			if !sort.StringsAreSorted(keys) {
+				t.Errorf("keys not sorted: %v", keys)
+			}
+			_, err = dec.Token()
+		case json.Delim('['):
+			for dec.More() {
+				if err := walk(); err != nil {
+					return err
+				}
+			}
+			_, err = dec.Token()
+		}
+		return err
+	}
+	if err := walk(); err != nil && err != io.EOF {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+}
+
+// TestCanonicalJSON_RoundTrip verifies sorted keys, a UTC timestamp and
+// that decoding then re-encoding reproduces the bytes
+func TestCanonicalJSON_RoundTrip(t *testing.T) {
+	f := filledFacts()
+	data, err := f.CanonicalJSON()
+	if err != nil {
+		t.Fatalf("CanonicalJSON() failed: %v", err)
+	}
+	checkSorted(t, data)
+	if !bytes.Contains(data, []byte(`"timestamp": "2025-11-09T06:30:00.123456789Z"`)) {
+		t.Errorf("timestamp not normalized to UTC:\n%s", data)
+	}
+	if bytes.HasSuffix(data, []byte("\n")) || !bytes.HasPrefix(data, []byte("{\n  \"")) {
+		t.Errorf("unexpected layout: %q...", data[:min(len(data), 16)])
+	}
+
+	var decoded Facts
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("decoding canonical facts failed: %v", err)
+	}
+	again, err := decoded.CanonicalJSON()
+	if err != nil {
+		t.Fatalf("CanonicalJSON() after decode failed: %v", err)
+	}
+	if !bytes.Equal(again, data) {
+		t.Errorf("round trip changed the bytes:\n%s\nwant\n%s", again, data)
+	}
+	if !decoded.Timestamp.Equal(f.Timestamp) {
+		t.Errorf("Timestamp = %v, want %v", decoded.Timestamp, f.Timestamp)
+	}
+}
+
+// TestCanonicalJSON_Normalizes verifies time zone and extension whitespace or
+// key order do not change the bytes
+func TestCanonicalJSON_Normalizes(t *testing.T) {
+	a := filledFacts()
+	b := filledFacts()
+	b.Timestamp = a.Timestamp.In(time.FixedZone("Y", -7*3600))
+	for name := range b.Extensions {
+		var v map[string]any
+		if err := json.Unmarshal(b.Extensions[name], &v); err != nil {
+			t.Fatal(err)
+		}
+		// Keys in a different order, no whitespace
+		b.Extensions[name] = json.RawMessage(`{"s":["a<b",{}],"e":[],"n":` + string(mustJSON(t, v["n"])) + `}`)
+	}
+	want, err := a.CanonicalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := b.CanonicalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("equal facts encoded differently:\n%s\nwant\n%s", got, want)
+	}
+}
+
+// TestCanonicalJSON_InvalidExtension verifies malformed plugin output is
+// rejected rather than signed
+func TestCanonicalJSON_InvalidExtension(t *testing.T) {
+	f := &Facts{Timestamp: time.Unix(0, 0), Extensions: map[string]json.RawMessage{"x": json.RawMessage(`{"a":`)}}
+	if _, err := f.CanonicalJSON(); err == nil {
+		t.Error("CanonicalJSON() accepted invalid extension JSON")
+	}
+}
+
+// TestCanonicalJSON_MatchesReference verifies the single pass produces what
+// re-encoding the struct encoding through a sorted tree would
+func TestCanonicalJSON_MatchesReference(t *testing.T) {
+	for name, f := range map[string]*Facts{"filled": filledFacts(), "zero": {}} {
+		got, err := f.CanonicalJSON()
+		if err != nil {
+			t.Fatalf("%s: CanonicalJSON() failed: %v", name, err)
+		}
+
+		utc := *f
+		utc.Timestamp = f.Timestamp.UTC()
+		data, err := json.Marshal(&utc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var tree any
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&tree); err != nil {
+			t.Fatal(err)
+		}
+		want, err := json.MarshalIndent(tree, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s: CanonicalJSON() =\n%s\nwant\n%s", name, got, want)
+		}
+	}
+}
+
+// TestCanonicalJSON_DuplicateExtensionKey verifies an extension repeating a
+// key is rejected rather than signed with one of the values dropped
+func TestCanonicalJSON_DuplicateExtensionKey(t *testing.T) {
+	f := &Facts{Timestamp: time.Unix(0, 0), Extensions: map[string]json.RawMessage{"x": json.RawMessage(`{"a":1,"a":2}`)}}
+	if _, err := f.CanonicalJSON(); err == nil {
+		t.Error("CanonicalJSON() accepted a duplicate extension key")
+	}
+}
+
+// TestAppendCanonicalJSON_Allocs verifies the canonical encoding allocates
+// no more than the struct-order encoding it is built on
+func TestAppendCanonicalJSON_Allocs(t *testing.T) {
+	f := filledFacts()
+	buf, _ := f.AppendJSON(nil, "  ")
+	plain := testing.AllocsPerRun(20, func() { buf, _ = f.AppendJSON(buf[:0], "  ") })
+	canonical := testing.AllocsPerRun(20, func() { buf, _ = f.AppendCanonicalJSON(buf[:0]) })
+	if canonical > plain {
+		t.Errorf("AppendCanonicalJSON() = %v allocs/op, AppendJSON() = %v", canonical, plain)
+	}
+}
+
+func mustJSON(t *testing.T, v any) []byte {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
@@ -2,15 +2,27 @@ package collection
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"sort"
+	"sync"
 	"time"
 
+	"github.com/minibeast/usb-agent/src/core/clock"
 	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/crash"
+	"github.com/minibeast/usb-agent/src/core/logging"
 	"github.com/minibeast/usb-agent/src/core/platform"
 	"github.com/minibeast/usb-agent/src/core/platform/types"
+	"github.com/minibeast/usb-agent/src/core/progress"
+	"github.com/minibeast/usb-agent/src/core/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 )
 
+// Version is the collector version recorded in every Facts
+const Version = "1.0.0"
+
 // Collector orchestrates parallel data collection
 // Mathematical complexity: O(max(|categories|/N) * T) where N=poolSize, T=timeout
 type Collector struct {
@@ -18,6 +30,7 @@ type Collector struct {
 	platformCollector platform.Collector
 	timeout           time.Duration
 	poolSize          int
+	clock             clock.Clock // nil = clock.System
 }
 
 // NewCollector creates a new collector
@@ -27,25 +40,50 @@ func NewCollector(cfg *config.Config) (*Collector, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create platform collector: %w", err)
 	}
+	return NewCollectorFrom(cfg, platformCollector), nil
+}
 
+// NewCollectorFrom creates a collector around an injected platform collector
+// (e.g., platform.FakeCollector for benchmarks, platformtest.Collector for tests)
+// Complexity: O(1)
+func NewCollectorFrom(cfg *config.Config, platformCollector platform.Collector) *Collector {
 	return &Collector{
 		config:            cfg,
 		platformCollector: platformCollector,
 		timeout:           cfg.GetCategoryTimeout(),
 		poolSize:          cfg.Performance.MaxGoroutines,
-	}, nil
+	}
+}
+
+// SetClock replaces the clock stamping Timestamp and timing the collection
+// Complexity: O(1)
+func (c *Collector) SetClock(clk clock.Clock) {
+	c.clock = clk
 }
 
 // CollectAll performs parallel data collection with timeout guards
-// Mathematical guarantee: Returns complete Facts or error (never partial Facts)
+// Mathematical guarantee: Returns complete Facts or error; the only partial
+// Facts returned are marked Partial and accompany the ctx cancellation error.
+// Valid Facts may accompany *crash.PanicError values for categories that
+// panicked (those are listed in FailedCategories).
 // Complexity: O(|categories|) with bounded parallelism
 func (c *Collector) CollectAll(ctx context.Context) (*Facts, error) {
-	startTime := time.Now()
+	clk := c.clock
+	if clk == nil {
+		clk = clock.System
+	}
+	timer := clock.Start(clk)
+
+	ctx, span := telemetry.Tracer().Start(ctx, "collect")
+	defer span.End()
+	ctx = logging.WithPhase(ctx, "collect")
+	log := logging.From(ctx)
 
 	// Initialize results
 	facts := &Facts{
-		Timestamp:        time.Now().UTC(),
-		CollectorVersion: "1.0.0",
+		Timestamp:        clk.Now().UTC(),
+		CollectorVersion: Version,
+		SchemaVersion:    SchemaVersion,
 		Users:            []types.User{},
 		LoggedInUsers:    []string{},
 		HomeDirs:         []string{},
@@ -53,6 +91,12 @@ func (c *Collector) CollectAll(ctx context.Context) (*Facts, error) {
 		LocalIPs:         []types.NetworkInterface{},
 		MACAddresses:     []types.NetworkInterface{},
 		WiFiSSIDs:        []string{},
+		Software:         []types.Software{},
+		Processes:        []types.Process{},
+		Volumes:          []types.Volume{},
+		ListeningPorts:   []types.ListeningPort{},
+		SecurityProducts: []types.SecurityProduct{},
+		InstalledUpdates: []types.Update{},
 	}
 
 	// Create bounded pool
@@ -63,81 +107,252 @@ func (c *Collector) CollectAll(ctx context.Context) (*Facts, error) {
 	networkChan := make(chan *types.NetworkInfo, 1)
 	hardwareChan := make(chan *types.HardwareInfo, 1)
 	piiChan := make(chan *types.PIIInfo, 1)
-
-	// Error channel
-	errChan := make(chan error, 4)
+	softwareChan := make(chan *types.SoftwareInfo, 1)
+	processChan := make(chan *types.ProcessInfo, 1)
+	diskChan := make(chan *types.DiskInfo, 1)
+	portChan := make(chan *types.PortInfo, 1)
+	securityChan := make(chan *types.SecurityInfo, 1)
+	patchChan := make(chan *types.PatchInfo, 1)
+	var extMu sync.Mutex
+	extensions := map[string]json.RawMessage{}
 
 	// Submit collection tasks
 	categories := []struct {
 		name string
-		task func()
+		task func() error
 	}{
 		{
 			name: "system_info",
-			task: func() {
+			task: func() error {
 				catCtx, cancel := context.WithTimeout(ctx, c.timeout)
 				defer cancel()
 
 				info, err := c.platformCollector.GetSystemInfo(catCtx)
+				if info != nil && keepFacts(err) {
+					systemChan <- info
+				}
 				if err != nil {
-					errChan <- fmt.Errorf("system_info: %w", err)
-					return
+					return fmt.Errorf("system_info: %w", err)
 				}
-				systemChan <- info
+				return nil
 			},
 		},
 		{
 			name: "network_info",
-			task: func() {
+			task: func() error {
 				catCtx, cancel := context.WithTimeout(ctx, c.timeout)
 				defer cancel()
 
 				info, err := c.platformCollector.GetNetworkInfo(catCtx)
+				if info != nil && keepFacts(err) {
+					networkChan <- info
+				}
 				if err != nil {
-					errChan <- fmt.Errorf("network_info: %w", err)
-					return
+					return fmt.Errorf("network_info: %w", err)
 				}
-				networkChan <- info
+				return nil
 			},
 		},
 		{
 			name: "hardware_info",
-			task: func() {
+			task: func() error {
 				catCtx, cancel := context.WithTimeout(ctx, c.timeout)
 				defer cancel()
 
 				info, err := c.platformCollector.GetHardwareInfo(catCtx)
+				if info != nil && keepFacts(err) {
+					hardwareChan <- info
+				}
 				if err != nil {
-					errChan <- fmt.Errorf("hardware_info: %w", err)
-					return
+					return fmt.Errorf("hardware_info: %w", err)
 				}
-				hardwareChan <- info
+				return nil
 			},
 		},
 		{
 			name: "pii_info",
-			task: func() {
-				if !c.config.PII {
-					return // Skip if PII collection disabled
+			task: func() error {
+				catCtx, cancel := context.WithTimeout(ctx, c.timeout)
+				defer cancel()
+
+				info, err := c.platformCollector.GetPIIInfo(catCtx)
+				if info != nil && keepFacts(err) {
+					piiChan <- info
 				}
+				if err != nil {
+					return fmt.Errorf("pii_info: %w", err)
+				}
+				return nil
+			},
+		},
+		{
+			name: "software_inventory",
+			task: func() error {
+				catCtx, cancel := context.WithTimeout(ctx, c.config.GetSoftwareTimeout())
+				defer cancel()
 
+				info, err := c.platformCollector.GetSoftwareInventory(catCtx)
+				if info != nil && keepFacts(err) {
+					softwareChan <- info
+				}
+				if err != nil {
+					return fmt.Errorf("software_inventory: %w", err)
+				}
+				return nil
+			},
+		},
+		{
+			name: "process_info",
+			task: func() error {
 				catCtx, cancel := context.WithTimeout(ctx, c.timeout)
 				defer cancel()
 
-				info, err := c.platformCollector.GetPIIInfo(catCtx)
+				info, err := c.platformCollector.GetProcessInfo(catCtx)
+				if info != nil && keepFacts(err) {
+					processChan <- info
+				}
+				if err != nil {
+					return fmt.Errorf("process_info: %w", err)
+				}
+				return nil
+			},
+		},
+		{
+			name: "disk_info",
+			task: func() error {
+				catCtx, cancel := context.WithTimeout(ctx, c.timeout)
+				defer cancel()
+
+				info, err := c.platformCollector.GetDiskInfo(catCtx)
+				if info != nil && keepFacts(err) {
+					diskChan <- info
+				}
+				if err != nil {
+					return fmt.Errorf("disk_info: %w", err)
+				}
+				return nil
+			},
+		},
+		{
+			name: "listening_ports",
+			task: func() error {
+				catCtx, cancel := context.WithTimeout(ctx, c.timeout)
+				defer cancel()
+
+				info, err := c.platformCollector.GetListeningPorts(catCtx)
+				if info != nil && keepFacts(err) {
+					portChan <- info
+				}
+				if err != nil {
+					return fmt.Errorf("listening_ports: %w", err)
+				}
+				return nil
+			},
+		},
+		{
+			name: "security_products",
+			task: func() error {
+				catCtx, cancel := context.WithTimeout(ctx, c.config.GetSoftwareTimeout())
+				defer cancel()
+
+				info, err := c.platformCollector.GetSecurityProducts(catCtx)
+				if info != nil && keepFacts(err) {
+					securityChan <- info
+				}
+				if err != nil {
+					return fmt.Errorf("security_products: %w", err)
+				}
+				return nil
+			},
+		},
+		{
+			name: "patch_level",
+			task: func() error {
+				catCtx, cancel := context.WithTimeout(ctx, c.config.GetSoftwareTimeout())
+				defer cancel()
+
+				info, err := c.platformCollector.GetPatchInfo(catCtx)
+				if info != nil && keepFacts(err) {
+					patchChan <- info
+				}
 				if err != nil {
-					errChan <- fmt.Errorf("pii_info: %w", err)
-					return
+					return fmt.Errorf("patch_level: %w", err)
 				}
-				piiChan <- info
+				return nil
 			},
 		},
 	}
+	for _, reg := range snapshotRegistry() {
+		reg := reg
+		categories = append(categories, struct {
+			name string
+			task func() error
+		}{
+			name: reg.name,
+			task: func() error {
+				catCtx, cancel := context.WithTimeout(ctx, c.timeout)
+				defer cancel()
 
-	// Submit all tasks
+				data, err := runRegistered(catCtx, reg.fn)
+				if data != nil {
+					extMu.Lock()
+					extensions[reg.name] = data
+					extMu.Unlock()
+				}
+				if err != nil {
+					return fmt.Errorf("%s: %w", reg.name, err)
+				}
+				return nil
+			},
+		})
+	}
+
+	// Error channels (the failed category names are recorded in Facts)
+	errChan := make(chan error, len(categories))
+	failedChan := make(chan string, len(categories))
+
+	// Submit all tasks, each under its own span and progress step
 	for _, cat := range categories {
-		if err := pool.Submit(ctx, cat.task); err != nil {
-			return nil, fmt.Errorf("failed to submit %s: %w", cat.name, err)
+		cat := cat
+		if cat.name == "pii_info" && !c.config.PII {
+			continue // PII collection disabled: not run, so not reported as collected
+		}
+		if cat.name == "software_inventory" && !c.config.Collect.SoftwareInventory {
+			continue
+		}
+		if cat.name == "process_info" && !c.config.Collect.Processes {
+			continue
+		}
+		if cat.name == "disk_info" && !c.config.Collect.Disks {
+			continue
+		}
+		if cat.name == "listening_ports" && !c.config.Collect.ListeningPorts {
+			continue
+		}
+		if cat.name == "security_products" && !c.config.Collect.SecurityProducts {
+			continue
+		}
+		if cat.name == "patch_level" && !c.config.Collect.PatchLevel {
+			continue
+		}
+		traced := func() {
+			_, catSpan := telemetry.Tracer().Start(ctx, "collect."+cat.name)
+			defer catSpan.End()
+			step := progress.Start(ctx, "collect."+cat.name)
+
+			err := runCategory(cat.name, cat.task)
+			if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+				log.Warn("category timed out", "category", cat.name, "error", err)
+			}
+			if err != nil {
+				errChan <- err
+				failedChan <- cat.name
+			}
+			step.End(err)
+		}
+		if err := pool.Submit(ctx, traced); err != nil {
+			// Interrupted: keep whatever the submitted categories produce
+			break
 		}
 	}
 
@@ -147,12 +362,24 @@ func (c *Collector) CollectAll(ctx context.Context) (*Facts, error) {
 	close(networkChan)
 	close(hardwareChan)
 	close(piiChan)
+	close(softwareChan)
+	close(processChan)
+	close(diskChan)
+	close(portChan)
+	close(securityChan)
+	close(patchChan)
 	close(errChan)
+	close(failedChan)
 
 	// Collect errors (non-fatal, graceful degradation)
 	var collectionErrors []error
 	for err := range errChan {
 		collectionErrors = append(collectionErrors, err)
+		span.RecordError(err)
+	}
+	span.SetAttributes(attribute.Int("collect.errors", len(collectionErrors)))
+	for name := range failedChan {
+		facts.FailedCategories = append(facts.FailedCategories, name)
 	}
 
 	// Aggregate results
@@ -189,50 +416,85 @@ func (c *Collector) CollectAll(ctx context.Context) (*Facts, error) {
 		}
 	}
 
-	// Ensure deterministic ordering (critical for hash consistency)
-	c.sortFacts(facts)
+	if softwareInfo := <-softwareChan; softwareInfo != nil {
+		facts.Software = softwareInfo.Packages
+	}
+
+	if processInfo := <-processChan; processInfo != nil {
+		facts.Processes = processInfo.Processes
+	}
 
-	// Calculate collection duration
-	facts.CollectionDurationMs = time.Since(startTime).Milliseconds()
+	if diskInfo := <-diskChan; diskInfo != nil {
+		facts.Volumes = diskInfo.Volumes
+	}
+
+	if portInfo := <-portChan; portInfo != nil {
+		facts.ListeningPorts = portInfo.Ports
+	}
+
+	if securityInfo := <-securityChan; securityInfo != nil {
+		facts.SecurityProducts = securityInfo.Products
+	}
+
+	if patchInfo := <-patchChan; patchInfo != nil {
+		facts.InstalledUpdates = patchInfo.Updates
+		facts.LastUpdate = patchInfo.LastUpdate
+		facts.PendingUpdates = patchInfo.Pending
+	}
+
+	if len(extensions) > 0 {
+		facts.Extensions = extensions
+	}
+
+	// Ensure deterministic ordering (critical for hash consistency)
+	facts.Sort()
+
+	// Calculate collection duration (monotonic, immune to clock steps)
+	facts.CollectionDurationMs = timer.Elapsed().Milliseconds()
+	log.Info("collection finished", "duration_ms", facts.CollectionDurationMs,
+		"failed", facts.FailedCategories, "users", len(facts.Users), "interfaces", len(facts.LocalIPs),
+		"software", len(facts.Software), "processes", len(facts.Processes), "volumes", len(facts.Volumes),
+		"listening_ports", len(facts.ListeningPorts), "security_products", len(facts.SecurityProducts),
+		"installed_updates", len(facts.InstalledUpdates))
+
+	// An interrupted run returns its partial facts unvalidated so callers can
+	// flush them; missing categories are expected
+	if err := ctx.Err(); err != nil {
+		facts.Partial = true
+		span.SetStatus(codes.Error, err.Error())
+		log.Warn("collection interrupted, facts partial", "error", err)
+		return facts, fmt.Errorf("collection interrupted: %w", err)
+	}
 
 	// Validate mathematical invariants
 	if err := facts.Validate(); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		log.Error("facts validation failed", "error", err)
 		return nil, fmt.Errorf("facts validation failed: %w", err)
 	}
 
-	return facts, nil
+	// Panicked categories are failed like any other, but the panics travel
+	// with the facts so the caller can write a crash report
+	var panics []error
+	for _, p := range crash.All(errors.Join(collectionErrors...)) {
+		panics = append(panics, p)
+	}
+	return facts, errors.Join(panics...)
+}
+
+// keepFacts reports whether facts returned with err are aggregated
+// A category cut short by its timeout keeps what it collected (and is still
+// listed as failed); any other error discards the facts.
+func keepFacts(err error) bool {
+	return err == nil || errors.Is(err, context.DeadlineExceeded)
 }
 
-// sortFacts ensures deterministic ordering of all arrays
-// Complexity: O(n log n) where n = max array size
-func (c *Collector) sortFacts(facts *Facts) {
-	// Sort users by username
-	sort.Slice(facts.Users, func(i, j int) bool {
-		return facts.Users[i].Username < facts.Users[j].Username
-	})
-
-	// Sort logged-in users
-	sort.Strings(facts.LoggedInUsers)
-
-	// Sort home directories
-	sort.Strings(facts.HomeDirs)
-
-	// Sort WiFi SSIDs
-	sort.Strings(facts.WiFiSSIDs)
-
-	// Sort network interfaces by name
-	sort.Slice(facts.LocalIPs, func(i, j int) bool {
-		return facts.LocalIPs[i].Name < facts.LocalIPs[j].Name
-	})
-	sort.Slice(facts.MACAddresses, func(i, j int) bool {
-		return facts.MACAddresses[i].Name < facts.MACAddresses[j].Name
-	})
-
-	// Sort recent profiles by username (timestamp secondary)
-	sort.Slice(facts.RecentProfiles, func(i, j int) bool {
-		if facts.RecentProfiles[i].Username == facts.RecentProfiles[j].Username {
-			return facts.RecentProfiles[i].LastLogon > facts.RecentProfiles[j].LastLogon
+// runCategory runs one category task, turning a panic into a *crash.PanicError
+func runCategory(name string, task func() error) (err error) {
+	defer func() {
+		if p := crash.Recover(recover(), "collect."+name); p != nil {
+			err = p
 		}
-		return facts.RecentProfiles[i].Username < facts.RecentProfiles[j].Username
-	})
+	}()
+	return task()
 }
@@ -0,0 +1,175 @@
+package collection
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/clock"
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/crash"
+	"github.com/minibeast/usb-agent/src/core/platform/platformtest"
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// stallingCollector answers every category except PII, which blocks until cancelled
+func stallingCollector(stalled chan struct{}) *platformtest.Collector {
+	c := platformtest.New()
+	c.System = &types.SystemInfo{Hostname: "host", OSName: "Linux"}
+	c.Hardware = &types.HardwareInfo{HardwareUUID: "uuid"}
+	c.Before = func(ctx context.Context, cat platformtest.Category) error {
+		if cat != platformtest.PIIInfo {
+			return nil
+		}
+		close(stalled)
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return c
+}
+
+// TestCollectAll_InterruptedReturnsPartialFacts verifies cancellation keeps finished categories
+func TestCollectAll_InterruptedReturnsPartialFacts(t *testing.T) {
+	cfg := config.Default()
+	cfg.PII = true
+	stalled := make(chan struct{})
+	c := &Collector{config: cfg, platformCollector: stallingCollector(stalled), timeout: time.Minute, poolSize: 4}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-stalled
+		cancel() // SIGINT arrives while PII collection is running
+	}()
+
+	facts, err := c.CollectAll(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("CollectAll() error = %v, want context.Canceled", err)
+	}
+	if facts == nil || !facts.Partial {
+		t.Fatalf("CollectAll() facts = %+v, want Partial facts", facts)
+	}
+	if facts.Hostname != "host" || facts.HardwareUUID != "uuid" {
+		t.Errorf("finished categories were dropped: %+v", facts)
+	}
+}
+
+// TestCollectAll_CompleteRunIsNotPartial verifies the marker is only set on interruption
+func TestCollectAll_CompleteRunIsNotPartial(t *testing.T) {
+	cfg := config.Default()
+	cfg.PII = false
+	c := &Collector{config: cfg, platformCollector: stallingCollector(make(chan struct{})), timeout: time.Minute, poolSize: 4}
+
+	facts, err := c.CollectAll(context.Background())
+	if err != nil {
+		t.Fatalf("CollectAll() failed: %v", err)
+	}
+	if facts.Partial {
+		t.Error("complete run marked Partial")
+	}
+}
+
+// TestCollectAll_PanicKeepsOtherCategories verifies a panicking category fails alone
+func TestCollectAll_PanicKeepsOtherCategories(t *testing.T) {
+	cfg := config.Default()
+	cfg.PII = true
+	panicking := platformtest.New()
+	panicking.Before = func(ctx context.Context, cat platformtest.Category) error {
+		if cat == platformtest.PIIInfo {
+			var users []types.User
+			_ = users[3] // Index out of range
+		}
+		return nil
+	}
+	c := &Collector{config: cfg, platformCollector: panicking, timeout: time.Minute, poolSize: 4}
+
+	facts, err := c.CollectAll(context.Background())
+	panics := crash.All(err)
+	if len(panics) != 1 || panics[0].Where != "collect.pii_info" || len(panics[0].Stack) == 0 {
+		t.Fatalf("CollectAll() error = %v, want one pii_info panic", err)
+	}
+	if facts == nil || facts.Partial || facts.Hostname != "bench-host" {
+		t.Fatalf("CollectAll() facts = %+v, want the other categories", facts)
+	}
+	if len(facts.FailedCategories) != 1 || facts.FailedCategories[0] != "pii_info" {
+		t.Errorf("FailedCategories = %v", facts.FailedCategories)
+	}
+}
+
+// TestCollectAll_ClockStepKeepsDuration verifies the timestamp comes from the
+// injected clock and a wall-clock step mid-run does not corrupt the duration
+func TestCollectAll_ClockStepKeepsDuration(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	pc := platformtest.New()
+	pc.Before = func(ctx context.Context, cat platformtest.Category) error {
+		fake.Set(start.Add(-time.Hour)) // NTP steps the clock back
+		fake.Advance(50 * time.Millisecond)
+		return nil
+	}
+	c := NewCollectorFrom(config.Default(), pc)
+	c.SetClock(fake)
+
+	facts, err := c.CollectAll(context.Background())
+	if err != nil {
+		t.Fatalf("CollectAll() failed: %v", err)
+	}
+	if !facts.Timestamp.Equal(start) {
+		t.Errorf("Timestamp = %v, want %v", facts.Timestamp, start)
+	}
+	if want := int64(9 * 50); facts.CollectionDurationMs != want {
+		t.Errorf("CollectionDurationMs = %d, want %d", facts.CollectionDurationMs, want)
+	}
+}
+
+// TestCollectAll_TimeoutKeepsPartialFacts verifies a category cut short by
+// its timeout keeps what it collected and is still reported as failed
+func TestCollectAll_TimeoutKeepsPartialFacts(t *testing.T) {
+	pc := platformtest.New()
+	pc.Network = &types.NetworkInfo{Interfaces: []types.NetworkInterface{{Name: "eth0", IPAddress: "10.0.0.5"}}}
+	pc.Delay = map[platformtest.Category]time.Duration{
+		platformtest.NetworkInfo: time.Minute,
+		platformtest.Ports:       time.Minute,
+	}
+	pc.Partial = map[platformtest.Category]bool{platformtest.NetworkInfo: true}
+	c := &Collector{config: config.Default(), platformCollector: pc, timeout: 20 * time.Millisecond, poolSize: 4}
+
+	facts, err := c.CollectAll(context.Background())
+	if err != nil {
+		t.Fatalf("CollectAll() failed: %v", err)
+	}
+	if facts.Partial {
+		t.Error("a category timeout should not mark the run interrupted")
+	}
+	if len(facts.LocalIPs) != 1 || facts.LocalIPs[0].Name != "eth0" {
+		t.Errorf("LocalIPs = %+v, want the interfaces read before the timeout", facts.LocalIPs)
+	}
+	if len(facts.ListeningPorts) != 0 {
+		t.Errorf("ListeningPorts = %+v, want none (no partial facts)", facts.ListeningPorts)
+	}
+	if len(facts.FailedCategories) != 2 || facts.FailedCategories[0] != "listening_ports" || facts.FailedCategories[1] != "network_info" {
+		t.Errorf("FailedCategories = %v", facts.FailedCategories)
+	}
+}
+
+// TestCollectAll_ProcessesFollowConfig verifies process_info only runs when collect.processes is set
+func TestCollectAll_ProcessesFollowConfig(t *testing.T) {
+	cfg := config.Default()
+	pc := platformtest.New()
+	facts, err := NewCollectorFrom(cfg, pc).CollectAll(context.Background())
+	if err != nil {
+		t.Fatalf("CollectAll() failed: %v", err)
+	}
+	if pc.Calls(platformtest.ProcessInfo) != 0 || len(facts.Processes) != 0 {
+		t.Errorf("processes collected while disabled: %+v", facts.Processes)
+	}
+
+	cfg.Collect.Processes = true
+	pc.Process = &types.ProcessInfo{Processes: []types.Process{{PID: 900, Name: "b"}, {PID: 7, Name: "a"}}}
+	if facts, err = NewCollectorFrom(cfg, pc).CollectAll(context.Background()); err != nil {
+		t.Fatalf("CollectAll() failed: %v", err)
+	}
+	if len(facts.Processes) != 2 || facts.Processes[0].PID != 7 {
+		t.Errorf("Processes = %+v, want sorted by PID", facts.Processes)
+	}
+}
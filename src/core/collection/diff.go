@@ -0,0 +1,351 @@
+package collection
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// Change is one field whose value differs between two runs
+type Change struct {
+	Field string `json:"field"` // JSON name, with the item key for list members ("software[openssl].version")
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// SetDiff lists the members of a collection added and removed between two runs
+type SetDiff struct {
+	Added   []string `json:"added,omitempty"`   // Sorted
+	Removed []string `json:"removed,omitempty"` // Sorted
+}
+
+// Empty reports whether the set is unchanged
+// Complexity: O(1)
+func (s SetDiff) Empty() bool {
+	return len(s.Added) == 0 && len(s.Removed) == 0
+}
+
+// FactsDiff is the changeset from one run's Facts to a later one's
+// Identity fields compare by value; collections compare by key (username,
+// interface name, package name, mount point, process name, protocol and
+// address:port, security product name, update ID) and report the
+// keyed members' changed fields in Changed.
+type FactsDiff struct {
+	OldRunID     string    `json:"old_run_id,omitempty"`
+	NewRunID     string    `json:"new_run_id,omitempty"`
+	OldTimestamp time.Time `json:"old_timestamp"`
+	NewTimestamp time.Time `json:"new_timestamp"`
+
+	// Scalar and per-member changes, in Facts field order
+	Changed []Change `json:"changed,omitempty"`
+
+	Users            SetDiff `json:"users"`
+	LoggedInUsers    SetDiff `json:"logged_in_users"`
+	HomeDirs         SetDiff `json:"home_dirs"`
+	Interfaces       SetDiff `json:"interfaces"`
+	WiFiSSIDs        SetDiff `json:"wifi_known_ssids"`
+	ListeningPorts   SetDiff `json:"listening_ports"`
+	SecurityProducts SetDiff `json:"security_products"`
+	InstalledUpdates SetDiff `json:"installed_updates"`
+	Software         SetDiff `json:"software"`
+	Processes        SetDiff `json:"processes"`
+	Volumes          SetDiff `json:"volumes"`
+	Extensions       SetDiff `json:"extensions"` // Registered category names
+	FailedCategories SetDiff `json:"failed_categories"`
+}
+
+// Empty reports whether nothing changed between the runs
+// Complexity: O(1)
+func (d *FactsDiff) Empty() bool {
+	return len(d.Changed) == 0 && d.Users.Empty() && d.LoggedInUsers.Empty() && d.HomeDirs.Empty() &&
+		d.Interfaces.Empty() && d.WiFiSSIDs.Empty() && d.ListeningPorts.Empty() && d.SecurityProducts.Empty() &&
+		d.InstalledUpdates.Empty() && d.Software.Empty() && d.Processes.Empty() && d.Volumes.Empty() && d.Extensions.Empty() && d.FailedCategories.Empty()
+}
+
+// SameMachine reports whether both runs come from the same hardware: neither
+// the hardware UUID nor the serial number changed (an empty one, e.g. from a
+// failed category, does not count as a change)
+// Complexity: O(1)
+func (d *FactsDiff) SameMachine() bool {
+	for _, c := range d.Changed {
+		switch c.Field {
+		case "hardware_uuid", "serial_number":
+			if c.Old != "" && c.New != "" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Diff computes the changeset from old to new
+// Complexity: O(n log n) where n = largest collection
+func Diff(old, new *Facts) *FactsDiff {
+	d := &FactsDiff{
+		OldRunID:     old.RunID,
+		NewRunID:     new.RunID,
+		OldTimestamp: old.Timestamp,
+		NewTimestamp: new.Timestamp,
+	}
+	scalar := func(field, o, n string) {
+		if o != n {
+			d.Changed = append(d.Changed, Change{Field: field, Old: o, New: n})
+		}
+	}
+
+	scalar("hostname", old.Hostname, new.Hostname)
+	scalar("machine_owner", old.MachineOwner, new.MachineOwner)
+	scalar("computer_name", old.ComputerName, new.ComputerName)
+
+	oldUsers, newUsers := map[string]string{}, map[string]string{}
+	for _, u := range old.Users {
+		oldUsers[u.Username] = u.FullName + "\x00" + u.UID
+	}
+	for _, u := range new.Users {
+		newUsers[u.Username] = u.FullName + "\x00" + u.UID
+	}
+	d.Users = keyed(oldUsers, newUsers, func(key, o, n string) {
+		oName, oUID, _ := strings.Cut(o, "\x00")
+		nName, nUID, _ := strings.Cut(n, "\x00")
+		scalar("users["+key+"].full_name", oName, nName)
+		scalar("users["+key+"].uid", oUID, nUID)
+	})
+	d.LoggedInUsers = members(old.LoggedInUsers, new.LoggedInUsers)
+	d.HomeDirs = members(old.HomeDirs, new.HomeDirs)
+	scalar("primary_user_email", old.PrimaryEmail, new.PrimaryEmail)
+
+	oldIfaces, newIfaces := map[string]string{}, map[string]string{}
+	for _, i := range old.LocalIPs {
+		oldIfaces[i.Name] = i.IPAddress + "\x00" + i.MACAddress
+	}
+	for _, i := range new.LocalIPs {
+		newIfaces[i.Name] = i.IPAddress + "\x00" + i.MACAddress
+	}
+	d.Interfaces = keyed(oldIfaces, newIfaces, func(key, o, n string) {
+		oIP, oMAC, _ := strings.Cut(o, "\x00")
+		nIP, nMAC, _ := strings.Cut(n, "\x00")
+		scalar("local_ips["+key+"].ip_address", oIP, nIP)
+		scalar("local_ips["+key+"].mac_address", oMAC, nMAC)
+	})
+	d.WiFiSSIDs = members(old.WiFiSSIDs, new.WiFiSSIDs)
+	d.ListeningPorts = keyed(listeners(old.ListeningPorts), listeners(new.ListeningPorts), func(key, o, n string) {
+		scalar("listening_ports["+key+"].process", o, n)
+	})
+	d.SecurityProducts = keyed(securityStates(old.SecurityProducts), securityStates(new.SecurityProducts), func(key, o, n string) {
+		oVer, oRT, _ := strings.Cut(o, "\x00")
+		nVer, nRT, _ := strings.Cut(n, "\x00")
+		scalar("security_products["+key+"].version", oVer, nVer)
+		scalar("security_products["+key+"].real_time_protection", oRT, nRT)
+	})
+	d.InstalledUpdates = members(updateIDs(old.InstalledUpdates), updateIDs(new.InstalledUpdates))
+	scalar("last_update", old.LastUpdate, new.LastUpdate)
+	scalar("pending_updates", pendingCount(old.PendingUpdates), pendingCount(new.PendingUpdates))
+
+	d.Software = keyed(softwareVersions(old.Software), softwareVersions(new.Software), func(key, o, n string) {
+		scalar("software["+key+"].version", o, n)
+	})
+
+	oldProcs, newProcs := map[string]string{}, map[string]string{}
+	for _, p := range old.Processes {
+		oldProcs[p.Name] = ""
+	}
+	for _, p := range new.Processes {
+		newProcs[p.Name] = ""
+	}
+	d.Processes = keyed(oldProcs, newProcs, nil)
+
+	oldVols, newVols := map[string]string{}, map[string]string{}
+	for _, v := range old.Volumes {
+		oldVols[v.MountPoint] = v.Device + "\x00" + v.Encryption
+	}
+	for _, v := range new.Volumes {
+		newVols[v.MountPoint] = v.Device + "\x00" + v.Encryption
+	}
+	d.Volumes = keyed(oldVols, newVols, func(key, o, n string) {
+		oDev, oEnc, _ := strings.Cut(o, "\x00")
+		nDev, nEnc, _ := strings.Cut(n, "\x00")
+		scalar("volumes["+key+"].device", oDev, nDev)
+		scalar("volumes["+key+"].encryption", oEnc, nEnc)
+	})
+
+	d.Extensions = keyed(extensionValues(old.Extensions), extensionValues(new.Extensions), func(name, o, n string) {
+		scalar("extensions["+name+"]", o, n)
+	})
+
+	scalar("serial_number", old.SerialNumber, new.SerialNumber)
+	scalar("hardware_uuid", old.HardwareUUID, new.HardwareUUID)
+	scalar("os_name", old.OSName, new.OSName)
+	scalar("os_version", old.OSVersion, new.OSVersion)
+	scalar("os_build", old.OSBuild, new.OSBuild)
+	scalar("timezone", old.Timezone, new.Timezone)
+
+	d.FailedCategories = members(old.FailedCategories, new.FailedCategories)
+	return d
+}
+
+// listeners keys ports by "protocol address:port", valued by owning process
+func listeners(ports []types.ListeningPort) map[string]string {
+	m := make(map[string]string, len(ports))
+	for _, p := range ports {
+		m[p.Protocol+" "+net.JoinHostPort(p.Address, strconv.Itoa(p.Port))] = p.Process
+	}
+	return m
+}
+
+// securityStates keys products by name, valued by version and real-time
+// protection state
+func securityStates(products []types.SecurityProduct) map[string]string {
+	m := make(map[string]string, len(products))
+	for _, p := range products {
+		m[p.Name] = p.Version + "\x00" + p.RealTime
+	}
+	return m
+}
+
+// updateIDs lists the installed updates' IDs
+func updateIDs(updates []types.Update) []string {
+	ids := make([]string, len(updates))
+	for i, u := range updates {
+		ids[i] = u.ID
+	}
+	return ids
+}
+
+// pendingCount formats a pending update count, empty when unknown
+func pendingCount(n *int) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.Itoa(*n)
+}
+
+// members diffs two string collections
+func members(old, new []string) SetDiff {
+	o, n := make(map[string]string, len(old)), make(map[string]string, len(new))
+	for _, s := range old {
+		o[s] = ""
+	}
+	for _, s := range new {
+		n[s] = ""
+	}
+	return keyed(o, n, nil)
+}
+
+// keyed diffs two keyed collections, calling changed (when non-nil) in key
+// order for every key present in both with a different value
+func keyed(old, new map[string]string, changed func(key, o, n string)) SetDiff {
+	var d SetDiff
+	var common []string
+	for k := range old {
+		if _, ok := new[k]; ok {
+			common = append(common, k)
+		} else {
+			d.Removed = append(d.Removed, k)
+		}
+	}
+	for k := range new {
+		if _, ok := old[k]; !ok {
+			d.Added = append(d.Added, k)
+		}
+	}
+	sort.Strings(d.Added)
+	sort.Strings(d.Removed)
+	if changed != nil {
+		sort.Strings(common)
+		for _, k := range common {
+			if old[k] != new[k] {
+				changed(k, old[k], new[k])
+			}
+		}
+	}
+	return d
+}
+
+// extensionValues maps registered category names to their compact JSON
+func extensionValues(ext map[string]json.RawMessage) map[string]string {
+	m := make(map[string]string, len(ext))
+	for name, raw := range ext {
+		var b bytes.Buffer
+		if json.Compact(&b, raw) != nil {
+			b.Reset()
+			b.Write(raw)
+		}
+		m[name] = b.String()
+	}
+	return m
+}
+
+// softwareVersions maps package name to its installed versions
+// ("1.0, 2.0" when several are installed side by side)
+func softwareVersions(sw []types.Software) map[string]string {
+	byName := make(map[string][]string, len(sw))
+	for _, s := range sw {
+		byName[s.Name] = append(byName[s.Name], s.Version)
+	}
+	out := make(map[string]string, len(byName))
+	for name, versions := range byName {
+		sort.Strings(versions)
+		out[name] = strings.Join(versions, ", ")
+	}
+	return out
+}
+
+// RenderText formats the changeset for a terminal, one change per line:
+// "~ field: old -> new", "+ collection: member", "- collection: member"
+// Complexity: O(|changes|)
+func (d *FactsDiff) RenderText() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Run %s (%s) -> run %s (%s)\n", runLabel(d.OldRunID), d.OldTimestamp.UTC().Format(time.RFC3339),
+		runLabel(d.NewRunID), d.NewTimestamp.UTC().Format(time.RFC3339))
+	if !d.SameMachine() {
+		b.WriteString("WARNING: hardware identifiers differ; these runs are from different machines\n")
+	}
+	if d.Empty() {
+		b.WriteString("No changes\n")
+		return b.String()
+	}
+	for _, c := range d.Changed {
+		fmt.Fprintf(&b, "~ %s: %q -> %q\n", c.Field, c.Old, c.New)
+	}
+	for _, s := range []struct {
+		name string
+		diff SetDiff
+	}{
+		{"users", d.Users},
+		{"logged_in_users", d.LoggedInUsers},
+		{"home_dirs", d.HomeDirs},
+		{"interfaces", d.Interfaces},
+		{"wifi_known_ssids", d.WiFiSSIDs},
+		{"listening_ports", d.ListeningPorts},
+		{"security_products", d.SecurityProducts},
+		{"installed_updates", d.InstalledUpdates},
+		{"software", d.Software},
+		{"processes", d.Processes},
+		{"volumes", d.Volumes},
+		{"extensions", d.Extensions},
+		{"failed_categories", d.FailedCategories},
+	} {
+		for _, m := range s.diff.Added {
+			fmt.Fprintf(&b, "+ %s: %s\n", s.name, m)
+		}
+		for _, m := range s.diff.Removed {
+			fmt.Fprintf(&b, "- %s: %s\n", s.name, m)
+		}
+	}
+	return b.String()
+}
+
+// runLabel names a run in RenderText
+func runLabel(runID string) string {
+	if runID == "" {
+		return "(no run ID)"
+	}
+	return runID
+}
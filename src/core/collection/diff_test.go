@@ -0,0 +1,130 @@
+package collection
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// diffBase returns the earlier run of TestDiff
+func diffBase() *Facts {
+	return &Facts{
+		RunID:         "01JPAX1Z5C8K2M3N4P5Q6R7S8T",
+		Hostname:      "kiosk-01",
+		OSName:        "Linux",
+		OSVersion:     "Ubuntu 22.04.4 LTS",
+		HardwareUUID:  "ACME-0001",
+		Users:         []types.User{{Username: "alice", UID: "1000"}, {Username: "bob", UID: "1001"}},
+		LoggedInUsers: []string{"alice"},
+		LocalIPs:      []types.NetworkInterface{{Name: "eth0", IPAddress: "10.0.0.5", MACAddress: "00:11:22:33:44:55"}},
+		WiFiSSIDs:     []string{"Corp"},
+		ListeningPorts: []types.ListeningPort{
+			{Protocol: "tcp", Address: "0.0.0.0", Port: 22, Process: "sshd"},
+			{Protocol: "tcp", Address: "::1", Port: 631, Process: "cupsd"},
+		},
+		SecurityProducts: []types.SecurityProduct{
+			{Name: "ClamAV", Kind: types.ProductAntivirus, Version: "1.0.7", RealTime: types.RealTimeOn},
+			{Name: "Wazuh Agent", Kind: types.ProductEDR, Version: "4.9.0", RealTime: types.RealTimeOn},
+		},
+		InstalledUpdates: []types.Update{{ID: "KB5034123", InstalledOn: "2026-01-10"}},
+		LastUpdate:       "2026-01-10",
+		Software:         []types.Software{{Name: "openssl", Version: "3.0.2"}},
+		Processes:        []types.Process{{PID: 1, Name: "systemd"}, {PID: 200, Name: "sshd"}},
+		Volumes:          []types.Volume{{MountPoint: "/", Encryption: types.EncryptionLUKS}},
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old := diffBase()
+	new := diffBase()
+	new.RunID = "01JPB00000000000000000000"
+	new.OSVersion = "Ubuntu 24.04 LTS"
+	new.Users = []types.User{{Username: "alice", UID: "1000"}, {Username: "mallory", UID: "1002"}}
+	new.LocalIPs = []types.NetworkInterface{{Name: "eth0", IPAddress: "10.0.0.5", MACAddress: "de:ad:be:ef:00:01"}}
+	new.WiFiSSIDs = []string{"Corp", "FreeAirportWiFi"}
+	new.ListeningPorts = []types.ListeningPort{
+		{Protocol: "tcp", Address: "0.0.0.0", Port: 22, Process: "dropbear"},
+		{Protocol: "tcp", Address: "::1", Port: 631, Process: "cupsd"},
+		{Protocol: "tcp", Address: "0.0.0.0", Port: 4444, Process: "nc"},
+	}
+	new.SecurityProducts = []types.SecurityProduct{{Name: "ClamAV", Kind: types.ProductAntivirus, Version: "1.0.7", RealTime: types.RealTimeOff}}
+	new.InstalledUpdates = []types.Update{{ID: "KB5034441", InstalledOn: "2026-02-14"}, {ID: "KB5034123", InstalledOn: "2026-01-10"}}
+	new.LastUpdate = "2026-02-14"
+	new.Software = []types.Software{{Name: "openssl", Version: "3.0.13"}, {Name: "nmap", Version: "7.94"}}
+	new.Processes = []types.Process{{PID: 1, Name: "systemd"}, {PID: 300, Name: "sshd"}, {PID: 301, Name: "nc"}}
+	new.Volumes = []types.Volume{{MountPoint: "/", Encryption: types.EncryptionNone}}
+	new.FailedCategories = []string{"disk_info"}
+
+	d := Diff(old, new)
+	if d.Empty() {
+		t.Fatal("Diff() reported no changes")
+	}
+	wantChanged := []Change{
+		{Field: "local_ips[eth0].mac_address", Old: "00:11:22:33:44:55", New: "de:ad:be:ef:00:01"},
+		{Field: "listening_ports[tcp 0.0.0.0:22].process", Old: "sshd", New: "dropbear"},
+		{Field: "security_products[ClamAV].real_time_protection", Old: types.RealTimeOn, New: types.RealTimeOff},
+		{Field: "last_update", Old: "2026-01-10", New: "2026-02-14"},
+		{Field: "software[openssl].version", Old: "3.0.2", New: "3.0.13"},
+		{Field: "volumes[/].encryption", Old: types.EncryptionLUKS, New: types.EncryptionNone},
+		{Field: "os_version", Old: "Ubuntu 22.04.4 LTS", New: "Ubuntu 24.04 LTS"},
+	}
+	if !reflect.DeepEqual(d.Changed, wantChanged) {
+		t.Errorf("Changed = %+v, want %+v", d.Changed, wantChanged)
+	}
+	for name, got := range map[string]SetDiff{"users": d.Users, "wifi": d.WiFiSSIDs, "software": d.Software, "processes": d.Processes, "ports": d.ListeningPorts, "security": d.SecurityProducts, "updates": d.InstalledUpdates, "failed": d.FailedCategories} {
+		want := map[string]SetDiff{
+			"users":     {Added: []string{"mallory"}, Removed: []string{"bob"}},
+			"wifi":      {Added: []string{"FreeAirportWiFi"}},
+			"software":  {Added: []string{"nmap"}},
+			"processes": {Added: []string{"nc"}}, // sshd restarted under a new PID: unchanged
+			"ports":     {Added: []string{"tcp 0.0.0.0:4444"}},
+			"security":  {Removed: []string{"Wazuh Agent"}},
+			"updates":   {Added: []string{"KB5034441"}},
+			"failed":    {Added: []string{"disk_info"}},
+		}[name]
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s = %+v, want %+v", name, got, want)
+		}
+	}
+	if !d.Interfaces.Empty() || !d.LoggedInUsers.Empty() || !d.Volumes.Empty() {
+		t.Errorf("unexpected membership changes: %+v", d)
+	}
+	if !d.SameMachine() {
+		t.Error("SameMachine() = false for the same hardware UUID")
+	}
+}
+
+func TestDiff_Identical(t *testing.T) {
+	if d := Diff(diffBase(), diffBase()); !d.Empty() {
+		t.Errorf("Diff() of identical facts = %+v", d)
+	}
+}
+
+func TestDiff_OtherMachine(t *testing.T) {
+	other := diffBase()
+	other.HardwareUUID = "ACME-0002"
+	if Diff(diffBase(), other).SameMachine() {
+		t.Error("SameMachine() = true across hardware UUIDs")
+	}
+	other.HardwareUUID = "" // hardware_info failed: not evidence of another machine
+	if !Diff(diffBase(), other).SameMachine() {
+		t.Error("SameMachine() = false for a missing hardware UUID")
+	}
+}
+
+func TestFactsDiff_RenderText(t *testing.T) {
+	new := diffBase()
+	new.Users = append(new.Users, types.User{Username: "mallory"})
+	new.OSVersion = "Ubuntu 24.04 LTS"
+	text := Diff(diffBase(), new).RenderText()
+	for _, want := range []string{`~ os_version: "Ubuntu 22.04.4 LTS" -> "Ubuntu 24.04 LTS"`, "+ users: mallory"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("RenderText() missing %q:\n%s", want, text)
+		}
+	}
+	if text := Diff(diffBase(), diffBase()).RenderText(); !strings.Contains(text, "No changes") {
+		t.Errorf("RenderText() of identical facts:\n%s", text)
+	}
+}
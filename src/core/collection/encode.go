@@ -0,0 +1,221 @@
+package collection
+
+import (
+	"sort"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/jsonenc"
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// AppendJSON appends f as JSON to dst without reflection
+// The bytes equal json.MarshalIndent(f, "", indent), or json.Marshal(f)
+// when indent is empty, in struct field order; use AppendCanonicalJSON for
+// bytes that are hashed or signed. Reuse dst across runs to avoid re-growing
+// the buffer. A field added to
+// Facts must be added here too (TestAppendJSON_MatchesEncodingJSON fails
+// until it is).
+// Complexity: O(|Facts|)
+func (f *Facts) AppendJSON(dst []byte, indent string) ([]byte, error) {
+	return f.appendJSON(jsonenc.Writer{Buf: dst, Indent: indent}, f.Timestamp)
+}
+
+// appendJSON writes f with w, stamping it with timestamp
+func (f *Facts) appendJSON(w jsonenc.Writer, timestamp time.Time) ([]byte, error) {
+	w.BeginObject()
+	w.Key("timestamp")
+	w.Time(timestamp)
+	w.Key("collection_duration_ms")
+	w.Int(f.CollectionDurationMs)
+	w.StringField("collector_version", f.CollectorVersion)
+	w.StringField("schema_version", f.SchemaVersion)
+	if f.Partial {
+		w.Key("partial")
+		w.Bool(true)
+	}
+	if len(f.FailedCategories) > 0 {
+		w.Key("failed_categories")
+		w.Strings(f.FailedCategories)
+	}
+	if f.RunID != "" {
+		w.StringField("run_id", f.RunID)
+	}
+	if f.Session != nil {
+		w.Key("session")
+		appendSession(&w, f.Session)
+	}
+	if len(f.CoverageNotes) > 0 {
+		w.Key("coverage_notes")
+		w.Strings(f.CoverageNotes)
+	}
+
+	w.StringField("hostname", f.Hostname)
+	if f.MachineOwner != "" {
+		w.StringField("machine_owner", f.MachineOwner)
+	}
+	w.StringField("computer_name", f.ComputerName)
+
+	w.Key("users")
+	if f.Users == nil {
+		w.Null()
+	} else {
+		w.BeginArray()
+		for i := range f.Users {
+			f.Users[i].WriteJSON(&w)
+		}
+		w.EndArray()
+	}
+	w.Key("logged_in_users")
+	w.Strings(f.LoggedInUsers)
+	w.Key("home_dirs")
+	w.Strings(f.HomeDirs)
+	w.Key("recent_profiles")
+	if f.RecentProfiles == nil {
+		w.Null()
+	} else {
+		w.BeginArray()
+		for i := range f.RecentProfiles {
+			f.RecentProfiles[i].WriteJSON(&w)
+		}
+		w.EndArray()
+	}
+	if f.PrimaryEmail != "" {
+		w.StringField("primary_user_email", f.PrimaryEmail)
+	}
+
+	w.Key("local_ips")
+	appendInterfaces(&w, f.LocalIPs)
+	w.Key("mac_addresses")
+	appendInterfaces(&w, f.MACAddresses)
+	w.Key("wifi_known_ssids")
+	w.Strings(f.WiFiSSIDs)
+	w.Key("listening_ports")
+	if f.ListeningPorts == nil {
+		w.Null()
+	} else {
+		w.BeginArray()
+		for i := range f.ListeningPorts {
+			f.ListeningPorts[i].WriteJSON(&w)
+		}
+		w.EndArray()
+	}
+	w.Key("security_products")
+	if f.SecurityProducts == nil {
+		w.Null()
+	} else {
+		w.BeginArray()
+		for i := range f.SecurityProducts {
+			f.SecurityProducts[i].WriteJSON(&w)
+		}
+		w.EndArray()
+	}
+	w.Key("installed_updates")
+	if f.InstalledUpdates == nil {
+		w.Null()
+	} else {
+		w.BeginArray()
+		for i := range f.InstalledUpdates {
+			f.InstalledUpdates[i].WriteJSON(&w)
+		}
+		w.EndArray()
+	}
+	if f.LastUpdate != "" {
+		w.StringField("last_update", f.LastUpdate)
+	}
+	if f.PendingUpdates != nil {
+		w.Key("pending_updates")
+		w.Int(int64(*f.PendingUpdates))
+	}
+
+	w.Key("software")
+	if f.Software == nil {
+		w.Null()
+	} else {
+		w.BeginArray()
+		for i := range f.Software {
+			f.Software[i].WriteJSON(&w)
+		}
+		w.EndArray()
+	}
+	w.Key("processes")
+	if f.Processes == nil {
+		w.Null()
+	} else {
+		w.BeginArray()
+		for i := range f.Processes {
+			f.Processes[i].WriteJSON(&w)
+		}
+		w.EndArray()
+	}
+	w.Key("volumes")
+	if f.Volumes == nil {
+		w.Null()
+	} else {
+		w.BeginArray()
+		for i := range f.Volumes {
+			f.Volumes[i].WriteJSON(&w)
+		}
+		w.EndArray()
+	}
+	if len(f.Extensions) > 0 {
+		names := make([]string, 0, len(f.Extensions))
+		for name := range f.Extensions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		w.Key("extensions")
+		w.BeginObject()
+		for _, name := range names {
+			w.Key(name)
+			w.Raw(f.Extensions[name])
+		}
+		w.EndObject()
+	}
+
+	w.StringField("serial_number", f.SerialNumber)
+	w.StringField("hardware_uuid", f.HardwareUUID)
+	w.StringField("os_name", f.OSName)
+	w.StringField("os_version", f.OSVersion)
+	w.StringField("os_build", f.OSBuild)
+	w.StringField("timezone", f.Timezone)
+	w.EndObject()
+	return w.Buf, w.Err()
+}
+
+// appendSession writes the engagement metadata, tags in key order
+func appendSession(w *jsonenc.Writer, s *Session) {
+	w.BeginObject()
+	if s.Engagement != "" {
+		w.StringField("engagement", s.Engagement)
+	}
+	if s.Operator != "" {
+		w.StringField("operator", s.Operator)
+	}
+	if len(s.Tags) > 0 {
+		keys := make([]string, 0, len(s.Tags))
+		for k := range s.Tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		w.Key("tags")
+		w.BeginObject()
+		for _, k := range keys {
+			w.StringField(k, s.Tags[k])
+		}
+		w.EndObject()
+	}
+	w.EndObject()
+}
+
+// appendInterfaces writes a network interface list (null when nil)
+func appendInterfaces(w *jsonenc.Writer, ifaces []types.NetworkInterface) {
+	if ifaces == nil {
+		w.Null()
+		return
+	}
+	w.BeginArray()
+	for i := range ifaces {
+		ifaces[i].WriteJSON(w)
+	}
+	w.EndArray()
+}
@@ -0,0 +1,141 @@
+package collection
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// awkward exercises the escaping rules of encoding/json
+// Invalid UTF-8 is left out: Go versions differ in how they write U+FFFD.
+const awkward = "a\"b\\c<d>&e\u2028f\x01\tg\nh"
+
+// fill sets every field reachable from v to a non-zero value
+func fill(v reflect.Value, n *int) {
+	*n++
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(fmt.Sprintf("%s-%d", awkward, *n))
+	case reflect.Int, reflect.Int64:
+		v.SetInt(int64(*n))
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Slice:
+		if v.Type() == reflect.TypeOf(json.RawMessage{}) {
+			v.SetBytes([]byte(fmt.Sprintf(`{"n": %d, "s": ["a<b", {}], "e": []}`, *n)))
+			return
+		}
+		v.Set(reflect.MakeSlice(v.Type(), 2, 2))
+		for i := 0; i < 2; i++ {
+			fill(v.Index(i), n)
+		}
+	case reflect.Pointer:
+		v.Set(reflect.New(v.Type().Elem()))
+		fill(v.Elem(), n)
+	case reflect.Map:
+		v.Set(reflect.MakeMap(v.Type()))
+		for _, k := range []string{"zeta", "alpha", "<mid>"} {
+			val := reflect.New(v.Type().Elem()).Elem()
+			fill(val, n)
+			v.SetMapIndex(reflect.ValueOf(k), val)
+		}
+	case reflect.Struct:
+		if v.Type() == reflect.TypeOf(time.Time{}) {
+			v.Set(reflect.ValueOf(time.Date(2025, 11, 9, 12, 0, 0, 123456789, time.FixedZone("X", 5*3600+30*60))))
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			fill(v.Field(i), n)
+		}
+	default:
+		panic("fill: unhandled kind " + v.Kind().String())
+	}
+}
+
+// TestAppendJSON_MatchesEncodingJSON verifies byte equality with encoding/json
+func TestAppendJSON_MatchesEncodingJSON(t *testing.T) {
+	var full Facts
+	n := 0
+	fill(reflect.ValueOf(&full).Elem(), &n)
+
+	empty := Facts{Users: []types.User{}, Session: &Session{}, LocalIPs: []types.NetworkInterface{}}
+
+	for name, f := range map[string]*Facts{"zero": {}, "empty slices": &empty, "every field": &full} {
+		for _, indent := range []string{"", "  "} {
+			var want []byte
+			var err error
+			if indent == "" {
+				want, err = json.Marshal(f)
+			} else {
+				want, err = json.MarshalIndent(f, "", indent)
+			}
+			if err != nil {
+				t.Fatalf("%s: encoding/json failed: %v", name, err)
+			}
+			got, err := f.AppendJSON(nil, indent)
+			if err != nil {
+				t.Fatalf("%s: AppendJSON() failed: %v", name, err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s (indent %q): AppendJSON() =\n%s\nwant\n%s", name, indent, got, want)
+			}
+		}
+	}
+}
+
+// TestAppendJSON_ReusesBuffer verifies output is appended after existing bytes
+func TestAppendJSON_ReusesBuffer(t *testing.T) {
+	f := &Facts{Hostname: "host"}
+	buf := make([]byte, 0, 4096)
+	buf = append(buf, "prefix"...)
+	out, err := f.AppendJSON(buf, "")
+	if err != nil || !bytes.HasPrefix(out, []byte("prefix{")) || &out[0] != &buf[:1][0] {
+		t.Errorf("AppendJSON() = %q, %v; want appended in place", out, err)
+	}
+
+	f.Timestamp = time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := f.AppendJSON(nil, ""); err == nil {
+		t.Error("AppendJSON() accepted a year encoding/json rejects")
+	}
+}
+
+// BenchmarkAppendJSON compares AppendJSON with json.MarshalIndent
+func BenchmarkAppendJSON(b *testing.B) {
+	var f Facts
+	n := 0
+	fill(reflect.ValueOf(&f).Elem(), &n)
+
+	b.Run("MarshalIndent", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := json.MarshalIndent(&f, "", "  "); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("AppendJSON", func(b *testing.B) {
+		b.ReportAllocs()
+		var buf []byte
+		for i := 0; i < b.N; i++ {
+			var err error
+			if buf, err = f.AppendJSON(buf[:0], "  "); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("AppendCanonicalJSON", func(b *testing.B) {
+		b.ReportAllocs()
+		var buf []byte
+		for i := 0; i < b.N; i++ {
+			var err error
+			if buf, err = f.AppendCanonicalJSON(buf[:0]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,22 @@
+package collection_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/platform/platformtest"
+	"github.com/minibeast/usb-agent/src/core/testsupport"
+)
+
+// TestCollectAll_Golden verifies the orchestrated facts against testdata/facts.golden.json
+func TestCollectAll_Golden(t *testing.T) {
+	cfg := config.Default()
+	cfg.PII = true
+	facts, err := collection.NewCollectorFrom(cfg, platformtest.New()).CollectAll(context.Background())
+	if err != nil {
+		t.Fatalf("CollectAll() failed: %v", err)
+	}
+	testsupport.Golden(t, "facts.golden.json", facts, testsupport.IgnoreTimestamps())
+}
@@ -0,0 +1,105 @@
+package collection
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// CategoryFunc collects one registered category
+// The value returned is encoded with encoding/json into Facts.Extensions
+// under the category's name, so it should be deterministic (map keys are
+// sorted; slices must be sorted by the function). ctx carries the category
+// timeout.
+type CategoryFunc func(ctx context.Context) (any, error)
+
+// builtinCategories are the names registered categories may not take
+var builtinCategories = map[Category]bool{
+	CategorySystemInfo: true, CategoryNetworkInfo: true, CategoryHardwareInfo: true, CategoryPIIInfo: true,
+	CategorySoftwareInventory: true, CategoryProcessInfo: true, CategoryDiskInfo: true, CategoryListeningPorts: true,
+	CategorySecurityProducts: true, CategoryPatchLevel: true,
+}
+
+// customCategories holds registered categories by name
+var customCategories = struct {
+	sync.RWMutex
+	byName map[string]CategoryFunc
+}{byName: map[string]CategoryFunc{}}
+
+// RegisterCategory adds an organization-specific category to every later
+// CollectAll
+// Registered categories run in the same bounded pool and under the same
+// category timeout as the built-in ones; a failure lists the name in
+// Facts.FailedCategories. It panics on an empty name, a nil func, a
+// built-in category name or a duplicate name.
+// Complexity: O(1)
+func RegisterCategory(name string, fn CategoryFunc) {
+	if name == "" || fn == nil {
+		panic("collection: RegisterCategory needs a name and a func")
+	}
+	if builtinCategories[Category(name)] {
+		panic("collection: " + name + " is a built-in category")
+	}
+	customCategories.Lock()
+	defer customCategories.Unlock()
+	if _, dup := customCategories.byName[name]; dup {
+		panic("collection: category " + name + " registered twice")
+	}
+	customCategories.byName[name] = fn
+}
+
+// UnregisterCategory removes the category registered as name, if any
+// Complexity: O(1)
+func UnregisterCategory(name string) {
+	customCategories.Lock()
+	defer customCategories.Unlock()
+	delete(customCategories.byName, name)
+}
+
+// RegisteredCategories returns the names of the registered categories, sorted
+// Complexity: O(r log r) where r = registered categories
+func RegisteredCategories() []string {
+	customCategories.RLock()
+	defer customCategories.RUnlock()
+	names := make([]string, 0, len(customCategories.byName))
+	for name := range customCategories.byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// registeredCategory is one registry entry, snapshotted for a collection
+type registeredCategory struct {
+	name string
+	fn   CategoryFunc
+}
+
+// snapshotRegistry returns the registered categories in name order
+func snapshotRegistry() []registeredCategory {
+	customCategories.RLock()
+	defer customCategories.RUnlock()
+	cats := make([]registeredCategory, 0, len(customCategories.byName))
+	for name, fn := range customCategories.byName {
+		cats = append(cats, registeredCategory{name, fn})
+	}
+	sort.Slice(cats, func(i, j int) bool { return cats[i].name < cats[j].name })
+	return cats
+}
+
+// runRegistered calls fn and encodes its value for Facts.Extensions
+// A value cut short by the timeout is kept along with the error, like the
+// built-in categories' partial facts.
+func runRegistered(ctx context.Context, fn CategoryFunc) (json.RawMessage, error) {
+	v, err := fn(ctx)
+	if v == nil || !keepFacts(err) {
+		return nil, err
+	}
+	data, merr := json.Marshal(v)
+	if merr != nil {
+		return nil, fmt.Errorf("failed to encode result: %w", merr)
+	}
+	return data, err
+}
@@ -0,0 +1,85 @@
+package collection
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/platform/platformtest"
+)
+
+// TestRegisterCategory verifies registered categories land in Extensions and
+// fail like built-in ones
+func TestRegisterCategory(t *testing.T) {
+	RegisterCategory("acme_agent", func(ctx context.Context) (any, error) {
+		return map[string]any{"version": "4.2.0", "healthy": true}, nil
+	})
+	defer UnregisterCategory("acme_agent")
+	RegisterCategory("acme_broken", func(ctx context.Context) (any, error) {
+		return nil, errors.New("agent socket missing")
+	})
+	defer UnregisterCategory("acme_broken")
+	RegisterCategory("acme_slow", func(ctx context.Context) (any, error) {
+		<-ctx.Done()
+		return []string{"partial"}, ctx.Err()
+	})
+	defer UnregisterCategory("acme_slow")
+
+	if got := strings.Join(RegisteredCategories(), ","); got != "acme_agent,acme_broken,acme_slow" {
+		t.Errorf("RegisteredCategories() = %s", got)
+	}
+
+	c := &Collector{config: config.Default(), platformCollector: platformtest.New(), timeout: 20 * time.Millisecond, poolSize: 2}
+	facts, err := c.CollectAll(context.Background())
+	if err != nil {
+		t.Fatalf("CollectAll() failed: %v", err)
+	}
+	if got := string(facts.Extensions["acme_agent"]); got != `{"healthy":true,"version":"4.2.0"}` {
+		t.Errorf("extensions[acme_agent] = %s", got)
+	}
+	if got := string(facts.Extensions["acme_slow"]); got != `["partial"]` {
+		t.Errorf("extensions[acme_slow] = %s, want the value returned on timeout", got)
+	}
+	if _, ok := facts.Extensions["acme_broken"]; ok {
+		t.Error("a failed category should not be in Extensions")
+	}
+	if got := strings.Join(facts.FailedCategories, ","); got != "acme_broken,acme_slow" {
+		t.Errorf("FailedCategories = %s", got)
+	}
+
+	data, err := facts.AppendJSON(nil, "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back Facts
+	if err := json.Unmarshal(data, &back); err != nil || len(back.Extensions) != 2 {
+		t.Errorf("round trip = %v, %v", back.Extensions, err)
+	}
+}
+
+// TestRegisterCategory_Panics verifies invalid registrations are rejected
+func TestRegisterCategory_Panics(t *testing.T) {
+	fn := func(ctx context.Context) (any, error) { return nil, nil }
+	RegisterCategory("acme_dup", fn)
+	defer UnregisterCategory("acme_dup")
+
+	for name, register := range map[string]func(){
+		"empty name": func() { RegisterCategory("", fn) },
+		"nil func":   func() { RegisterCategory("acme_nil", nil) },
+		"built-in":   func() { RegisterCategory(string(CategoryDiskInfo), fn) },
+		"duplicate":  func() { RegisterCategory("acme_dup", fn) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: RegisterCategory() did not panic", name)
+				}
+			}()
+			register()
+		}()
+	}
+}
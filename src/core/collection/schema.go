@@ -0,0 +1,239 @@
+package collection
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// factsSchema is facts.schema.json at SchemaVersion, generated by the schema
+// package (TestFactsSchema_Embedded keeps it current)
+//
+//go:embed facts.schema.json
+var factsSchema []byte
+
+// legacySchemaVersion is assumed for facts written before schema_version
+const legacySchemaVersion = "1.2"
+
+// FactsSchema returns the embedded JSON Schema of facts.json
+// Complexity: O(|schema|)
+func FactsSchema() []byte {
+	return bytes.Clone(factsSchema)
+}
+
+// jsonSchema is the subset of JSON Schema the generator emits
+type jsonSchema struct {
+	Ref                  string                 `json:"$ref"`
+	Type                 string                 `json:"type"`
+	Format               string                 `json:"format"`
+	Enum                 []string               `json:"enum"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	Items                *jsonSchema            `json:"items"`
+	AdditionalProperties *jsonSchema            `json:"additionalProperties"`
+	Defs                 map[string]*jsonSchema `json:"$defs"`
+	Version              string                 `json:"x-schema-version"`
+	Since                string                 `json:"x-since"`
+}
+
+// parsedFactsSchema decodes the embedded schema once
+var parsedFactsSchema = sync.OnceValues(func() (*jsonSchema, error) {
+	var s jsonSchema
+	if err := json.Unmarshal(factsSchema, &s); err != nil {
+		return nil, fmt.Errorf("embedded facts schema: %w", err)
+	}
+	return &s, nil
+})
+
+// ValidateAgainstSchema checks a facts.json document against the embedded
+// JSON Schema
+// Facts from any agent with the same major schema version are accepted:
+// properties added after the document's schema_version (legacySchemaVersion
+// when absent) are not required, and unknown properties from newer agents
+// are ignored. Every violation is reported together as ValidationErrors
+// with the JSON path of the offending value.
+// Complexity: O(|data|)
+func ValidateAgainstSchema(data []byte) error {
+	root, err := parsedFactsSchema()
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return fmt.Errorf("invalid facts JSON: %w", err)
+	}
+	obj, ok := doc.(map[string]any)
+	if !ok {
+		return ValidationErrors{{Reason: "facts must be a JSON object"}}
+	}
+
+	version := legacySchemaVersion
+	if v, ok := obj["schema_version"].(string); ok && v != "" {
+		version = v
+	}
+	docMajor, _, err := parseSchemaVersion(version)
+	if err != nil {
+		return ValidationErrors{{Field: "schema_version", Reason: err.Error()}}
+	}
+	major, _, _ := parseSchemaVersion(root.Version)
+	if docMajor != major {
+		return ValidationErrors{{Field: "schema_version",
+			Reason: fmt.Sprintf("version %s is not supported (schema %s)", version, root.Version)}}
+	}
+
+	c := &schemaCheck{root: root, version: version}
+	c.value(obj, root, "")
+	if len(c.errs) == 0 {
+		return nil
+	}
+	return c.errs
+}
+
+// schemaCheck walks a document against the schema, collecting violations
+type schemaCheck struct {
+	root    *jsonSchema
+	version string // The document's schema_version
+	errs    ValidationErrors
+}
+
+func (c *schemaCheck) fail(path, reason string) {
+	c.errs = append(c.errs, &ValidationError{Field: path, Reason: reason})
+}
+
+// value checks v against s
+func (c *schemaCheck) value(v any, s *jsonSchema, path string) {
+	if s.Ref != "" {
+		def := c.root.Defs[strings.TrimPrefix(s.Ref, "#/$defs/")]
+		if def == nil {
+			c.fail(path, "schema references unknown "+s.Ref)
+			return
+		}
+		s = def
+	}
+	if len(s.Enum) > 0 {
+		str, ok := v.(string)
+		for _, allowed := range s.Enum {
+			if ok && str == allowed {
+				return
+			}
+		}
+		c.fail(path, "must be one of "+strings.Join(s.Enum, ", "))
+		return
+	}
+
+	switch s.Type {
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			c.fail(path, "must be a string")
+			return
+		}
+		switch s.Format {
+		case "date-time":
+			if _, err := time.Parse(time.RFC3339Nano, str); err != nil {
+				c.fail(path, "must be an RFC 3339 date-time")
+			}
+		case "byte":
+			if _, err := base64.StdEncoding.DecodeString(str); err != nil {
+				c.fail(path, "must be base64")
+			}
+		}
+	case "integer":
+		if n, ok := v.(json.Number); !ok {
+			c.fail(path, "must be an integer")
+		} else if _, err := n.Int64(); err != nil {
+			c.fail(path, "must be an integer")
+		}
+	case "number":
+		if _, ok := v.(json.Number); !ok {
+			c.fail(path, "must be a number")
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			c.fail(path, "must be a boolean")
+		}
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			c.fail(path, "must be an array")
+			return
+		}
+		if s.Items != nil {
+			for i, item := range arr {
+				c.value(item, s.Items, path+"["+strconv.Itoa(i)+"]")
+			}
+		}
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			c.fail(path, "must be an object")
+			return
+		}
+		c.object(obj, s, path)
+	}
+}
+
+// object checks required and known properties, in key order
+func (c *schemaCheck) object(obj map[string]any, s *jsonSchema, path string) {
+	for _, name := range s.Required {
+		if _, ok := obj[name]; ok {
+			continue
+		}
+		if prop := s.Properties[name]; prop != nil && prop.Since != "" && schemaVersionLess(c.version, prop.Since) {
+			continue // Added after the document's version
+		}
+		c.fail(joinPath(path, name), "is required")
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if prop := s.Properties[k]; prop != nil {
+			c.value(obj[k], prop, joinPath(path, k))
+		} else if s.AdditionalProperties != nil {
+			c.value(obj[k], s.AdditionalProperties, joinPath(path, k))
+		}
+	}
+}
+
+// joinPath appends a property name to a JSON path
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// parseSchemaVersion splits "major.minor"
+func parseSchemaVersion(v string) (major, minor int, err error) {
+	maj, min, ok := strings.Cut(v, ".")
+	major, err1 := strconv.Atoi(maj)
+	minor, err2 := strconv.Atoi(min)
+	if !ok || err1 != nil || err2 != nil {
+		return 0, 0, fmt.Errorf("version %q is not major.minor", v)
+	}
+	return major, minor, nil
+}
+
+// schemaVersionLess reports whether version a precedes b; unparsable
+// versions compare as current
+func schemaVersionLess(a, b string) bool {
+	aMaj, aMin, errA := parseSchemaVersion(a)
+	bMaj, bMin, errB := parseSchemaVersion(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return aMaj < bMaj || (aMaj == bMaj && aMin < bMin)
+}
@@ -0,0 +1,123 @@
+package collection
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/platform/platformtest"
+)
+
+// collectedJSON returns facts.json from a platformtest run as a generic object
+func collectedJSON(t *testing.T) map[string]any {
+	t.Helper()
+	facts, err := NewCollectorFrom(config.Default(), platformtest.New()).CollectAll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := facts.AppendJSON(nil, "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+// validate marshals doc and runs ValidateAgainstSchema
+func validate(t *testing.T, doc map[string]any) error {
+	t.Helper()
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ValidateAgainstSchema(data)
+}
+
+func TestValidateAgainstSchema(t *testing.T) {
+	if err := validate(t, collectedJSON(t)); err != nil {
+		t.Fatalf("collected facts do not validate: %v", err)
+	}
+
+	// A 1.2 agent wrote neither schema_version nor the later categories
+	legacy := collectedJSON(t)
+	for _, key := range []string{"schema_version", "listening_ports", "security_products", "installed_updates", "software", "processes", "volumes"} {
+		delete(legacy, key)
+	}
+	if err := validate(t, legacy); err != nil {
+		t.Errorf("legacy facts do not validate: %v", err)
+	}
+
+	// A 1.3 agent collected neither security_products nor installed_updates
+	v13 := collectedJSON(t)
+	v13["schema_version"] = "1.3"
+	delete(v13, "security_products")
+	delete(v13, "installed_updates")
+	if err := validate(t, v13); err != nil {
+		t.Errorf("1.3 facts do not validate: %v", err)
+	}
+
+	// A newer minor version may add properties
+	newer := collectedJSON(t)
+	newer["schema_version"] = "1.9"
+	newer["future_field"] = []any{1, 2}
+	if err := validate(t, newer); err != nil {
+		t.Errorf("newer facts do not validate: %v", err)
+	}
+}
+
+func TestValidateAgainstSchema_Violations(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(doc map[string]any)
+		want   []string
+	}{
+		{"missing since current", func(doc map[string]any) { delete(doc, "listening_ports") }, []string{"listening_ports - is required"}},
+		{"missing required", func(doc map[string]any) { delete(legacyDoc(doc), "hostname") }, []string{"hostname - is required"}},
+		{"wrong types", func(doc map[string]any) {
+			doc["users"].([]any)[0].(map[string]any)["username"] = 7
+			doc["collection_duration_ms"] = 1.5
+			doc["timestamp"] = "yesterday"
+		}, []string{
+			"collection_duration_ms - must be an integer",
+			"timestamp - must be an RFC 3339 date-time",
+			"users[0].username - must be a string",
+		}},
+		{"other major version", func(doc map[string]any) { doc["schema_version"] = "2.0" }, []string{"schema_version - version 2.0 is not supported"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := collectedJSON(t)
+			tt.modify(doc)
+			err := validate(t, doc)
+			var errs ValidationErrors
+			if !errors.As(err, &errs) || len(errs) != len(tt.want) {
+				t.Fatalf("ValidateAgainstSchema() = %v, want %d violations", err, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if got := strings.TrimPrefix(errs[i].Error(), "validation failed: "); !strings.HasPrefix(got, want) {
+					t.Errorf("violation %d = %q, want %q", i, got, want)
+				}
+			}
+		})
+	}
+
+	if err := ValidateAgainstSchema([]byte("[1, 2]")); err == nil {
+		t.Error("a JSON array should not validate")
+	}
+	if err := ValidateAgainstSchema([]byte("{")); err == nil {
+		t.Error("truncated JSON should not validate")
+	}
+}
+
+// legacyDoc drops schema_version so doc validates as a 1.2 document
+func legacyDoc(doc map[string]any) map[string]any {
+	delete(doc, "schema_version")
+	return doc
+}
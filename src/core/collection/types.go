@@ -1,18 +1,33 @@
 package collection
 
 import (
+	"encoding/json"
+	"sort"
 	"time"
 
 	"github.com/minibeast/usb-agent/src/core/platform/types"
 )
 
+// SchemaVersion is the version of the published output contract, written to
+// every Facts and to every generated JSON Schema
+// Bump the major version for any removal or type change, the minor version
+// for additions; tag a required field added in a minor version with
+// `since:"<version>"` so facts from older agents still validate.
+const SchemaVersion = "1.4"
+
 // Facts represents the complete system snapshot
 // Mathematical invariant: All fields deterministic for given hardware state
 type Facts struct {
 	// Metadata
-	Timestamp            time.Time `json:"timestamp"`              // ISO 8601 (UTC)
-	CollectionDurationMs int64     `json:"collection_duration_ms"` // Performance tracking
-	CollectorVersion     string    `json:"collector_version"`      // Version tracking
+	Timestamp            time.Time `json:"timestamp"`                   // ISO 8601 (UTC)
+	CollectionDurationMs int64     `json:"collection_duration_ms"`      // Performance tracking
+	CollectorVersion     string    `json:"collector_version"`           // Version tracking
+	SchemaVersion        string    `json:"schema_version" since:"1.3"`  // Output contract version (SchemaVersion)
+	Partial              bool      `json:"partial,omitempty"`           // Run was interrupted; categories may be missing
+	FailedCategories     []string  `json:"failed_categories,omitempty"` // Categories that returned an error (sorted)
+	RunID                string    `json:"run_id,omitempty"`            // ULID shared by every artifact and export of the run
+	Session              *Session  `json:"session,omitempty"`           // Engagement metadata (nil when none is configured)
+	CoverageNotes        []string  `json:"coverage_notes,omitempty"`    // What categories missed for lack of privileges, in category order
 
 	// System identification
 	Hostname     string `json:"hostname"`
@@ -31,6 +46,29 @@ type Facts struct {
 	MACAddresses []types.NetworkInterface `json:"mac_addresses"`    // Sorted by interface name
 	WiFiSSIDs    []string                 `json:"wifi_known_ssids"` // Sorted
 
+	// Listening sockets (collect.listening_ports)
+	ListeningPorts []types.ListeningPort `json:"listening_ports" since:"1.3"` // Sorted by protocol, port, then address
+
+	// Antivirus and EDR products (collect.security_products)
+	SecurityProducts []types.SecurityProduct `json:"security_products" since:"1.4"` // Sorted by name
+
+	// Patch level (collect.patch_level)
+	InstalledUpdates []types.Update `json:"installed_updates" since:"1.4"` // Newest first, then by ID
+	LastUpdate       string         `json:"last_update,omitempty"`         // YYYY-MM-DD of the last OS update
+	PendingUpdates   *int           `json:"pending_updates,omitempty"`     // Updates awaiting install (nil when unknown)
+
+	// Installed software (sorted for determinism)
+	Software []types.Software `json:"software" since:"1.3"` // Sorted by name, then version
+
+	// Running processes (collect.processes)
+	Processes []types.Process `json:"processes" since:"1.3"` // Sorted by PID
+
+	// Storage (sorted for determinism)
+	Volumes []types.Volume `json:"volumes" since:"1.3"` // Sorted by mount point
+
+	// Registered categories (RegisterCategory), by name
+	Extensions map[string]json.RawMessage `json:"extensions,omitempty"`
+
 	// Hardware identifiers
 	SerialNumber string `json:"serial_number"`
 	HardwareUUID string `json:"hardware_uuid"`
@@ -42,32 +80,11 @@ type Facts struct {
 	Timezone  string `json:"timezone"` // IANA format
 }
 
-// Validate checks mathematical invariants
-// Returns error if invariants violated
-// Complexity: O(1)
-func (f *Facts) Validate() error {
-	// All critical fields must be non-empty
-	if f.Hostname == "" {
-		return &ValidationError{Field: "hostname", Reason: "must not be empty"}
-	}
-	if f.OSName == "" {
-		return &ValidationError{Field: "os_name", Reason: "must not be empty"}
-	}
-	if f.HardwareUUID == "" {
-		return &ValidationError{Field: "hardware_uuid", Reason: "must not be empty"}
-	}
-
-	return nil
-}
-
-// ValidationError represents a validation failure
-type ValidationError struct {
-	Field  string
-	Reason string
-}
-
-func (e *ValidationError) Error() string {
-	return "validation failed: " + e.Field + " - " + e.Reason
+// Session is the engagement metadata recorded with a run
+type Session struct {
+	Engagement string            `json:"engagement,omitempty"`
+	Operator   string            `json:"operator,omitempty"` // From the consent record
+	Tags       map[string]string `json:"tags,omitempty"`
 }
 
 // Category represents a data collection category
@@ -78,4 +95,76 @@ const (
 	CategoryNetworkInfo  Category = "network_info"
 	CategoryHardwareInfo Category = "hardware_info"
 	CategoryPIIInfo      Category = "pii_info"
+
+	CategorySoftwareInventory Category = "software_inventory"
+	CategoryProcessInfo       Category = "process_info"
+	CategoryDiskInfo          Category = "disk_info"
+	CategoryListeningPorts    Category = "listening_ports"
+	CategorySecurityProducts  Category = "security_products"
+	CategoryPatchLevel        Category = "patch_level"
 )
+
+// Sort restores the deterministic ordering of every slice (critical for
+// hash consistency); rewriting steps such as pseudonymization call it
+// Complexity: O(n log n) where n = max array size
+func (f *Facts) Sort() {
+	// Sort users by username
+	sort.Slice(f.Users, func(i, j int) bool {
+		return f.Users[i].Username < f.Users[j].Username
+	})
+
+	// Sort logged-in users
+	sort.Strings(f.LoggedInUsers)
+
+	// Sort home directories
+	sort.Strings(f.HomeDirs)
+
+	// Sort WiFi SSIDs
+	sort.Strings(f.WiFiSSIDs)
+
+	// Sort installed software by name (version secondary)
+	sort.Slice(f.Software, func(i, j int) bool {
+		if f.Software[i].Name == f.Software[j].Name {
+			return f.Software[i].Version < f.Software[j].Version
+		}
+		return f.Software[i].Name < f.Software[j].Name
+	})
+
+	// Sort processes by PID
+	sort.Slice(f.Processes, func(i, j int) bool {
+		return f.Processes[i].PID < f.Processes[j].PID
+	})
+
+	// Sort volumes by mount point
+	sort.Slice(f.Volumes, func(i, j int) bool {
+		return f.Volumes[i].MountPoint < f.Volumes[j].MountPoint
+	})
+
+	// Sort listening ports by protocol and port
+	types.SortPorts(f.ListeningPorts)
+
+	// Sort security products by name
+	types.SortProducts(f.SecurityProducts)
+
+	// Sort installed updates newest first
+	types.SortUpdates(f.InstalledUpdates)
+
+	// Sort failed categories
+	sort.Strings(f.FailedCategories)
+
+	// Sort network interfaces by name
+	sort.Slice(f.LocalIPs, func(i, j int) bool {
+		return f.LocalIPs[i].Name < f.LocalIPs[j].Name
+	})
+	sort.Slice(f.MACAddresses, func(i, j int) bool {
+		return f.MACAddresses[i].Name < f.MACAddresses[j].Name
+	})
+
+	// Sort recent profiles by username (timestamp secondary)
+	sort.Slice(f.RecentProfiles, func(i, j int) bool {
+		if f.RecentProfiles[i].Username == f.RecentProfiles[j].Username {
+			return f.RecentProfiles[i].LastLogon > f.RecentProfiles[j].LastLogon
+		}
+		return f.RecentProfiles[i].Username < f.RecentProfiles[j].Username
+	})
+}
@@ -0,0 +1,160 @@
+package collection
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Validator checks one invariant on Facts, e.g. an organization's asset
+// naming rules. It reports each violation as a *ValidationError whose Field
+// is the JSON path of the offending value ("users[2].username"); several
+// may be returned with errors.Join or as ValidationErrors. Any other error
+// is reported against the validator's name.
+type Validator func(f *Facts) error
+
+// validators holds registered validators by name
+var validators = struct {
+	sync.RWMutex
+	byName map[string]Validator
+}{byName: map[string]Validator{}}
+
+// RegisterValidator adds v to every Facts.Validate, after the built-in
+// checks. It panics on an empty name, a nil validator or a duplicate name.
+// Complexity: O(1)
+func RegisterValidator(name string, v Validator) {
+	if name == "" || v == nil {
+		panic("collection: RegisterValidator needs a name and a validator")
+	}
+	validators.Lock()
+	defer validators.Unlock()
+	if _, dup := validators.byName[name]; dup {
+		panic("collection: validator " + name + " registered twice")
+	}
+	validators.byName[name] = v
+}
+
+// UnregisterValidator removes the validator registered as name, if any
+// Complexity: O(1)
+func UnregisterValidator(name string) {
+	validators.Lock()
+	defer validators.Unlock()
+	delete(validators.byName, name)
+}
+
+// Validators returns the registered validator names, sorted
+// Complexity: O(n log n)
+func Validators() []string {
+	names, _ := registered()
+	return names
+}
+
+// registered snapshots the validators in name order
+func registered() ([]string, []Validator) {
+	validators.RLock()
+	defer validators.RUnlock()
+	names := make([]string, 0, len(validators.byName))
+	for name := range validators.byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	run := make([]Validator, len(names))
+	for i, name := range names {
+		run[i] = validators.byName[name]
+	}
+	return names, run
+}
+
+// Validate checks the built-in invariants, then every registered validator
+// in name order. All violations are reported together as ValidationErrors.
+// Complexity: O(1) + registered validators
+func (f *Facts) Validate() error {
+	var errs ValidationErrors
+
+	// All critical fields must be non-empty
+	if f.Hostname == "" {
+		errs = append(errs, &ValidationError{Field: "hostname", Reason: "must not be empty"})
+	}
+	if f.OSName == "" {
+		errs = append(errs, &ValidationError{Field: "os_name", Reason: "must not be empty"})
+	}
+	if f.HardwareUUID == "" {
+		errs = append(errs, &ValidationError{Field: "hardware_uuid", Reason: "must not be empty"})
+	}
+
+	names, run := registered()
+	for i, v := range run {
+		errs = appendViolations(errs, names[i], v(f))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// appendViolations flattens err into *ValidationErrors attributed to rule
+func appendViolations(errs ValidationErrors, rule string, err error) ValidationErrors {
+	if err == nil {
+		return errs
+	}
+	if multi, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range multi.Unwrap() {
+			errs = appendViolations(errs, rule, e)
+		}
+		return errs
+	}
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		v := *ve
+		if v.Rule == "" {
+			v.Rule = rule
+		}
+		return append(errs, &v)
+	}
+	return append(errs, &ValidationError{Reason: err.Error(), Rule: rule})
+}
+
+// ValidationError represents a validation failure
+type ValidationError struct {
+	Field  string // JSON path, e.g. "hardware_uuid" or "users[2].username"
+	Reason string
+	Rule   string // Registered validator name ("" for built-in checks)
+}
+
+func (e *ValidationError) Error() string {
+	msg := "validation failed: " + e.Field + " - " + e.Reason
+	if e.Field == "" {
+		msg = "validation failed: " + e.Reason
+	}
+	if e.Rule != "" {
+		msg += " (" + e.Rule + ")"
+	}
+	return msg
+}
+
+// ValidationErrors is every violation found by one Facts.Validate
+// errors.As finds the first *ValidationError through it.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	parts := make([]string, len(e))
+	for i, v := range e {
+		parts[i] = strings.TrimPrefix(v.Error(), "validation failed: ")
+	}
+	return fmt.Sprintf("validation failed (%d problems): %s", len(e), strings.Join(parts, "; "))
+}
+
+// Unwrap exposes each violation to errors.Is and errors.As
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, v := range e {
+		errs[i] = v
+	}
+	return errs
+}
@@ -0,0 +1,118 @@
+package collection
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// validFacts returns facts passing the built-in checks
+func validFacts() *Facts {
+	return &Facts{
+		Hostname:     "host",
+		OSName:       "Linux",
+		HardwareUUID: "ACME-0001",
+		Users:        []types.User{{Username: "alice"}, {Username: "root"}},
+	}
+}
+
+// TestValidate_BuiltinsAggregated verifies every built-in violation is reported
+func TestValidate_BuiltinsAggregated(t *testing.T) {
+	if err := validFacts().Validate(); err != nil {
+		t.Fatalf("Validate() failed on valid facts: %v", err)
+	}
+
+	err := (&Facts{OSName: "Linux"}).Validate()
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Fatalf("Validate() = %v, want 2 violations", err)
+	}
+	if errs[0].Field != "hostname" || errs[1].Field != "hardware_uuid" {
+		t.Errorf("Fields = %s, %s", errs[0].Field, errs[1].Field)
+	}
+	var first *ValidationError
+	if !errors.As(fmt.Errorf("collection failed: %w", err), &first) || first.Field != "hostname" {
+		t.Errorf("errors.As did not find the first violation: %v", first)
+	}
+	if want := "validation failed (2 problems): hostname - must not be empty; hardware_uuid - must not be empty"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+// TestRegisterValidator verifies custom validators run in name order with field paths
+func TestRegisterValidator(t *testing.T) {
+	asset := regexp.MustCompile(`^ACME-\d{4}$`)
+	RegisterValidator("acme-asset", func(f *Facts) error {
+		if !asset.MatchString(f.HardwareUUID) {
+			return &ValidationError{Field: "hardware_uuid", Reason: "must match ACME-NNNN"}
+		}
+		return nil
+	})
+	RegisterValidator("acme-users", func(f *Facts) error {
+		var errs []error
+		for i, u := range f.Users {
+			if u.Username == "root" {
+				errs = append(errs, &ValidationError{Field: fmt.Sprintf("users[%d].username", i), Reason: "root must not be listed"})
+			}
+		}
+		errs = append(errs, errors.New("directory unavailable"))
+		return errors.Join(errs...)
+	})
+	defer UnregisterValidator("acme-asset")
+	defer UnregisterValidator("acme-users")
+
+	if got := Validators(); strings.Join(got, ",") != "acme-asset,acme-users" {
+		t.Errorf("Validators() = %v", got)
+	}
+
+	facts := validFacts()
+	facts.HardwareUUID = "1234"
+	var errs ValidationErrors
+	if !errors.As(facts.Validate(), &errs) {
+		t.Fatalf("Validate() did not return ValidationErrors")
+	}
+	want := []string{
+		"validation failed: hardware_uuid - must match ACME-NNNN (acme-asset)",
+		"validation failed: users[1].username - root must not be listed (acme-users)",
+		"validation failed: directory unavailable (acme-users)",
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("Validate() = %v, want %d violations", errs, len(want))
+	}
+	for i, w := range want {
+		if errs[i].Error() != w {
+			t.Errorf("violation %d = %q, want %q", i, errs[i].Error(), w)
+		}
+	}
+
+	UnregisterValidator("acme-users")
+	if err := validFacts().Validate(); err != nil {
+		t.Errorf("Validate() failed after unregistering: %v", err)
+	}
+}
+
+// TestRegisterValidator_Panics verifies misuse is caught at registration
+func TestRegisterValidator_Panics(t *testing.T) {
+	ok := func(*Facts) error { return nil }
+	RegisterValidator("dup", ok)
+	defer UnregisterValidator("dup")
+
+	for name, register := range map[string]func(){
+		"empty name": func() { RegisterValidator("", ok) },
+		"nil":        func() { RegisterValidator("nil", nil) },
+		"duplicate":  func() { RegisterValidator("dup", ok) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: RegisterValidator() did not panic", name)
+				}
+			}()
+			register()
+		}()
+	}
+}
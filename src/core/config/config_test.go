@@ -3,6 +3,7 @@ package config_test
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -158,6 +159,18 @@ func TestValidate_InvalidLLMParams(t *testing.T) {
 				c.LLM.Temperature = 2.5
 			},
 		},
+		{
+			name: "negative sessions",
+			modifier: func(c *config.Config) {
+				c.LLM.Sessions = -1
+			},
+		},
+		{
+			name: "too many sessions",
+			modifier: func(c *config.Config) {
+				c.LLM.Sessions = 9
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -172,6 +185,190 @@ func TestValidate_InvalidLLMParams(t *testing.T) {
 	}
 }
 
+// TestValidate_InvalidFormat verifies unknown output formats are rejected
+func TestValidate_InvalidFormat(t *testing.T) {
+	cfg := config.Default()
+	cfg.Output.Formats = []string{"json", "xml"}
+
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for unsupported format")
+	}
+}
+
+// TestValidate_Fsync verifies unknown fsync modes are rejected
+func TestValidate_Fsync(t *testing.T) {
+	cfg := config.Default()
+	cfg.Output.Fsync = "never"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for unknown fsync mode")
+	}
+}
+
+// TestValidate_Bundle verifies the plaintext bundle is refused alongside
+// encrypted output
+func TestValidate_Bundle(t *testing.T) {
+	cfg := config.Default()
+	cfg.Output.Bundle = true
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() failed: %v", err)
+	}
+	cfg.Output.Encrypt, cfg.Output.RecipientKeys = true, []string{"keys/soc.pub"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for a bundle with encrypted output")
+	}
+}
+
+// TestValidate_KeyBackend verifies hardware key backends need a key name and
+// exclude a PEM signing key
+func TestValidate_KeyBackend(t *testing.T) {
+	cfg := config.Default()
+	cfg.Output.KeyBackend = "hsm"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for unknown key backend")
+	}
+
+	cfg.Output.KeyBackend = "tpm"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("tpm backend rejected: %v", err)
+	}
+	cfg.Audit.SigningKey = "keys/minibeast.pem"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for a PEM key with the tpm backend")
+	}
+	cfg.Audit.SigningKey = ""
+	cfg.Output.KeyName = ""
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for an empty key name")
+	}
+}
+
+// TestValidate_SignatureFormat verifies only raw and minisign are accepted
+func TestValidate_SignatureFormat(t *testing.T) {
+	cfg := config.Default()
+	for _, format := range []string{"", "raw", "minisign"} {
+		cfg.Output.SignatureFormat = format
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("signature_format %q rejected: %v", format, err)
+		}
+	}
+	cfg.Output.SignatureFormat = "signify"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for an unknown signature format")
+	}
+}
+
+// TestValidate_Analysis verifies EOL patterns and the admin limit are checked
+func TestValidate_Analysis(t *testing.T) {
+	cfg := config.Default()
+	if !cfg.Analysis.Enabled || len(cfg.Analysis.EOLVersions["Windows"]) == 0 {
+		t.Errorf("Default analysis = %+v, want enabled with EOL versions", cfg.Analysis)
+	}
+	cfg.Analysis.EOLVersions = map[string][]string{"Linux": {"[18"}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for a malformed EOL pattern")
+	}
+	cfg.Analysis.EOLVersions = nil
+	cfg.Analysis.MaxAdmins = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for negative max_admins")
+	}
+	cfg.Analysis.MaxAdmins = 2
+	cfg.Analysis.MaxUpdateAgeDays = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for negative max_update_age_days")
+	}
+}
+
+// TestValidate_Privacy verifies pseudonymized kinds and the salt file are checked
+func TestValidate_Privacy(t *testing.T) {
+	cfg := config.Default()
+	cfg.Privacy.Pseudonymize = []string{"username", "ssn"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for unknown identifier kind")
+	}
+
+	cfg.Privacy.Pseudonymize = nil
+	cfg.Privacy.Anonymous = true
+	cfg.Privacy.SaltPath = ""
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for anonymous mode without a salt file")
+	}
+	if got := cfg.Privacy.Kinds(); len(got) != len(config.PseudonymKinds) {
+		t.Errorf("Anonymous mode kinds = %v, want all", got)
+	}
+}
+
+// TestValidate_SoftwareTimeout verifies the timeout is only required while a
+// category using it is enabled
+func TestValidate_SoftwareTimeout(t *testing.T) {
+	cfg := config.Default()
+	cfg.Collect.SoftwareTimeoutMs = 0
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for zero software_timeout_ms")
+	}
+
+	cfg.Collect.SoftwareInventory = false
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error while security_products uses the timeout")
+	}
+
+	cfg.Collect.SecurityProducts = false
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error while patch_level uses the timeout")
+	}
+
+	cfg.Collect.PatchLevel = false
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Disabled inventory should not be validated: %v", err)
+	}
+}
+
+// TestValidate_S3Upload verifies S3 settings are checked only when enabled
+func TestValidate_S3Upload(t *testing.T) {
+	cfg := config.Default()
+	cfg.Output.Upload.S3.SSE = "bogus"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Disabled S3 should not be validated: %v", err)
+	}
+
+	cfg.Output.Upload.S3.Enabled = true
+	cfg.Output.Upload.S3.Bucket = "runs"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for unsupported sse")
+	}
+
+	cfg.Output.Upload.S3.SSE = "AES256"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Valid S3 config rejected: %v", err)
+	}
+}
+
+// TestValidate_AzureUpload verifies the auth mode and its credentials must agree
+func TestValidate_AzureUpload(t *testing.T) {
+	cfg := config.Default()
+	cfg.Output.Upload.Azure.Enabled = true
+	cfg.Output.Upload.Azure.Account = "acct"
+	cfg.Output.Upload.Azure.Container = "runs"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for sas auth without sas_token")
+	}
+
+	cfg.Output.Upload.Azure.SASToken = "sv=2022-11-02&sig=x"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Valid SAS config rejected: %v", err)
+	}
+
+	cfg.Output.Upload.Azure.Auth = "managed_identity"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for sas_token with managed_identity")
+	}
+
+	cfg.Output.Upload.Azure.SASToken = ""
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Valid managed identity config rejected: %v", err)
+	}
+}
+
 // TestGetTimeouts verifies timeout accessor methods
 func TestGetTimeouts(t *testing.T) {
 	cfg := config.Default()
@@ -417,3 +614,236 @@ func TestSave_AtomicWrite(t *testing.T) {
 		t.Error("Final config file does not exist")
 	}
 }
+
+// TestValidate_RESTLoopbackOnly verifies the REST API cannot bind to external interfaces
+func TestValidate_RESTLoopbackOnly(t *testing.T) {
+	cfg := config.Default()
+	for _, addr := range []string{"0.0.0.0:8765", "192.168.1.10:8765", "8765"} {
+		cfg.Service.REST.Address = addr
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Address %q should be rejected", addr)
+		}
+	}
+	for _, addr := range []string{"127.0.0.1:8765", "[::1]:8765", "localhost:8765"} {
+		cfg.Service.REST.Address = addr
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("Address %q rejected: %v", addr, err)
+		}
+	}
+}
+
+// TestValidate_GRPCServing verifies serve-mode gRPC needs a token, and
+// mutual TLS off loopback
+func TestValidate_GRPCServing(t *testing.T) {
+	cfg := config.Default()
+	cfg.Service.GRPC.Address = "0.0.0.0:50051"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("gRPC settings checked while service.server.grpc is off: %v", err)
+	}
+
+	cfg.Service.Server.GRPC = true
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "auth_token") {
+		t.Errorf("Validate() without a token = %v", err)
+	}
+	cfg.Service.GRPC.AuthToken = "secret"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "service.grpc.address") {
+		t.Errorf("Validate() off loopback without TLS = %v", err)
+	}
+	cfg.Service.GRPC.CertFile, cfg.Service.GRPC.KeyFile = "server.pem", "server.key"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() accepted server-only TLS off loopback")
+	}
+	cfg.Service.GRPC.ClientCAFile = "clients.pem"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() with mutual TLS = %v", err)
+	}
+
+	cfg.Service.GRPC = config.Default().Service.GRPC
+	cfg.Service.GRPC.AuthToken = "secret"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() on loopback with a token = %v", err)
+	}
+}
+
+// TestValidate_Resources verifies resource ceiling bounds
+func TestValidate_Resources(t *testing.T) {
+	tests := []struct {
+		name     string
+		modifier func(*config.ResourcesConfig)
+	}{
+		{"negative memory", func(r *config.ResourcesConfig) { r.MemoryLimitMB = -1 }},
+		{"tiny memory", func(r *config.ResourcesConfig) { r.MemoryLimitMB = 16 }},
+		{"negative procs", func(r *config.ResourcesConfig) { r.MaxProcs = -1 }},
+		{"too many llm threads", func(r *config.ResourcesConfig) { r.LLMThreads = 257 }},
+		{"cpu over 100", func(r *config.ResourcesConfig) { r.CPUPercent, r.OSLimits = 101, true }},
+		{"cpu without os limits", func(r *config.ResourcesConfig) { r.CPUPercent = 50 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			tt.modifier(&cfg.Resources)
+			if err := cfg.Validate(); err == nil {
+				t.Error("Expected validation error, got nil")
+			}
+		})
+	}
+
+	cfg := config.Default()
+	cfg.Resources = config.ResourcesConfig{MemoryLimitMB: 512, MaxProcs: 2, OSLimits: true, CPUPercent: 25}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Valid resources rejected: %v", err)
+	}
+}
+
+// TestValidate_Session verifies engagement and tag bounds
+func TestValidate_Session(t *testing.T) {
+	tests := []struct {
+		name    string
+		session config.SessionConfig
+	}{
+		{"multi-line engagement", config.SessionConfig{Engagement: "ACME\nINJECTED"}},
+		{"uppercase key", config.SessionConfig{Tags: map[string]string{"Site": "berlin"}}},
+		{"empty key", config.SessionConfig{Tags: map[string]string{"": "berlin"}}},
+		{"multi-line value", config.SessionConfig{Tags: map[string]string{"site": "a\rb"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Session = tt.session
+			if err := cfg.Validate(); err == nil {
+				t.Error("Expected validation error, got nil")
+			}
+		})
+	}
+
+	cfg := config.Default()
+	cfg.Session = config.SessionConfig{Engagement: "ACME-2025-014", Tags: map[string]string{"site": "berlin", "phase.1": "triage"}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Valid session rejected: %v", err)
+	}
+}
+
+// TestValidate_UsageStats verifies usage statistics need a destination and HTTPS
+func TestValidate_UsageStats(t *testing.T) {
+	tests := []struct {
+		name  string
+		usage config.UsageStatsConfig
+	}{
+		{"no destination", config.UsageStatsConfig{Enabled: true}},
+		{"plain http", config.UsageStatsConfig{Enabled: true, Endpoint: "http://stats.example.com/v1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.UsageStats = tt.usage
+			if err := cfg.Validate(); err == nil {
+				t.Error("Expected validation error, got nil")
+			}
+		})
+	}
+
+	cfg := config.Default()
+	if cfg.UsageStats.Enabled {
+		t.Error("Usage statistics enabled by default")
+	}
+	cfg.UsageStats = config.UsageStatsConfig{Enabled: true, Endpoint: "https://stats.example.com/v1"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Valid usage statistics rejected: %v", err)
+	}
+}
+
+// TestValidate_LLMBackend verifies llm.backend and the remote server settings
+func TestValidate_LLMBackend(t *testing.T) {
+	remote := func(url string, allowHTTP bool) config.RemoteLLMConfig {
+		return config.RemoteLLMConfig{URL: url, AllowHTTP: allowHTTP, Model: "qwen2.5:1.5b", TimeoutMs: 60000}
+	}
+	tests := []struct {
+		name    string
+		backend string
+		remote  config.RemoteLLMConfig
+		wantErr bool
+	}{
+		{"unknown backend", "ollama", remote("https://llm.example.com/v1", false), true},
+		{"no url", config.LLMBackendOpenAI, remote("", false), true},
+		{"plain http", config.LLMBackendOpenAI, remote("http://llm.example.com/v1", false), true},
+		{"plain http allowed", config.LLMBackendOpenAI, remote("http://llm.example.com/v1", true), false},
+		{"loopback http", config.LLMBackendOpenAI, remote("http://127.0.0.1:11434/v1", false), false},
+		{"localhost http", config.LLMBackendOpenAI, remote("http://localhost:8080/v1", false), false},
+		{"no model", config.LLMBackendOpenAI, config.RemoteLLMConfig{URL: "https://llm.example.com/v1", TimeoutMs: 1}, true},
+		{"https", config.LLMBackendOpenAI, remote("https://llm.example.com/v1", false), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.LLM.Backend = tt.backend
+			cfg.LLM.Remote = tt.remote
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	cfg := config.Default()
+	if cfg.LLM.Backend != config.LLMBackendLocal {
+		t.Errorf("Default backend = %q, want local", cfg.LLM.Backend)
+	}
+	cfg.LLM.Backend = config.LLMBackendOpenAI
+	cfg.LLM.Enabled = false
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Unconfigured remote rejected with llm disabled: %v", err)
+	}
+}
+
+// TestValidate_Logging verifies log levels and formats
+func TestValidate_Logging(t *testing.T) {
+	tests := []struct {
+		name    string
+		logging config.LoggingConfig
+	}{
+		{"unknown level", config.LoggingConfig{Level: "trace", Format: "text"}},
+		{"unknown format", config.LoggingConfig{Level: "info", Format: "logfmt"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Logging = tt.logging
+			if err := cfg.Validate(); err == nil {
+				t.Error("Expected validation error, got nil")
+			}
+		})
+	}
+
+	cfg := config.Default()
+	if !cfg.Logging.File {
+		t.Error("Run log disabled by default")
+	}
+	cfg.Logging = config.LoggingConfig{Level: "DEBUG", Format: "json"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Valid logging rejected: %v", err)
+	}
+}
+
+// TestInferenceThreads verifies llm_threads resolution
+func TestInferenceThreads(t *testing.T) {
+	tests := []struct {
+		resources config.ResourcesConfig
+		cpus      int
+		want      int
+	}{
+		{config.ResourcesConfig{}, 16, 4},
+		{config.ResourcesConfig{}, 2, 2},
+		{config.ResourcesConfig{MaxProcs: 3}, 16, 3},
+		{config.ResourcesConfig{LLMThreads: 8, MaxProcs: 2}, 16, 8},
+		{config.ResourcesConfig{}, 0, 1},
+	}
+	for _, tt := range tests {
+		if got := tt.resources.InferenceThreads(tt.cpus); got != tt.want {
+			t.Errorf("%+v with %d CPUs: got %d threads, want %d", tt.resources, tt.cpus, got, tt.want)
+		}
+	}
+}
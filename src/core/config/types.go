@@ -1,6 +1,13 @@
 package config
 
-import "time"
+import (
+	"net"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
 
 // Config represents the complete MiniBeast configuration
 // Mathematical invariant: All fields have valid defaults
@@ -8,6 +15,10 @@ type Config struct {
 	// PII collection toggle
 	PII bool `yaml:"pii"`
 
+	// Language of operator-facing CLI messages (e.g., "es", "fr-CA");
+	// MINIBEAST_LANG overrides it; empty follows the locale environment or the OS
+	Locale string `yaml:"locale"`
+
 	// Collection settings
 	Collect CollectConfig `yaml:"collect"`
 
@@ -17,8 +28,41 @@ type Config struct {
 	// LLM settings (Phase 2 stub)
 	LLM LLMConfig `yaml:"llm"`
 
+	// Built-in risk rules reporting without a model
+	Analysis AnalysisConfig `yaml:"analysis"`
+
 	// Performance settings
 	Performance PerformanceConfig `yaml:"performance"`
+
+	// Memory and CPU ceilings protecting the host's interactive session
+	Resources ResourcesConfig `yaml:"resources"`
+
+	// Service mode (agent driven by orchestration tooling)
+	Service ServiceConfig `yaml:"service"`
+
+	// OpenTelemetry tracing
+	Telemetry TelemetryConfig `yaml:"telemetry"`
+
+	// Opt-in anonymous usage statistics
+	UsageStats UsageStatsConfig `yaml:"usage_stats"`
+
+	// Operator acknowledgment before collection
+	Consent ConsentConfig `yaml:"consent"`
+
+	// Partner extensions (exporters, redactors, risk rules)
+	Plugins PluginsConfig `yaml:"plugins"`
+
+	// Signed record of the actions taken during each run
+	Audit AuditConfig `yaml:"audit"`
+
+	// Diagnostic log of each run
+	Logging LoggingConfig `yaml:"logging"`
+
+	// Engagement tags recorded with every run
+	Session SessionConfig `yaml:"session"`
+
+	// Keyed pseudonymization of identifiers
+	Privacy PrivacyConfig `yaml:"privacy"`
 }
 
 // CollectConfig defines data collection parameters
@@ -34,6 +78,31 @@ type CollectConfig struct {
 
 	// Per-category timeout (milliseconds)
 	CategoryTimeoutMs int `yaml:"category_timeout_ms"`
+
+	// Installed software inventory (software_inventory category)
+	SoftwareInventory bool `yaml:"software_inventory"`
+
+	// Timeout for the software inventory, security products and patch level
+	// (milliseconds); package databases and PowerShell take far longer than
+	// the other categories
+	SoftwareTimeoutMs int `yaml:"software_timeout_ms"`
+
+	// Running processes with owner and path (process_info category)
+	Processes bool `yaml:"processes"`
+
+	// Mounted volumes with capacity and encryption state (disk_info category)
+	Disks bool `yaml:"disks"`
+
+	// Listening TCP/UDP sockets with owning process (listening_ports category)
+	ListeningPorts bool `yaml:"listening_ports"`
+
+	// Installed antivirus/EDR products and real-time protection state
+	// (security_products category)
+	SecurityProducts bool `yaml:"security_products"`
+
+	// Installed updates, last update date and pending update count
+	// (patch_level category)
+	PatchLevel bool `yaml:"patch_level"`
 }
 
 // OutputConfig defines output file settings
@@ -41,41 +110,1127 @@ type OutputConfig struct {
 	// Enable encryption (Phase 2 feature)
 	Encrypt bool `yaml:"encrypt"`
 
-	// Enable Ed25519 signing
-	Sign bool `yaml:"sign"`
+	// X25519 public keys (PEM, relative to USB root) every artifact is encrypted to
+	// One key slot per recipient (e.g., customer, SOC, escrow); any one key decrypts
+	RecipientKeys []string `yaml:"recipient_keys"`
+
+	// Write <run>.manifest.json listing every file of the run with its
+	// SHA-256, Ed25519-signed with audit.signing_key (or a per-run key)
+	Sign bool `yaml:"sign"`
+
+	// Where the signing key lives: "file" (audit.signing_key, or a per-run
+	// key), "tpm" (Windows TPM via CNG) or "secure_enclave" (macOS); a
+	// hardware key is created on first use and never leaves the machine
+	KeyBackend string `yaml:"key_backend"`
+
+	// Name of the hardware key (tpm and secure_enclave backends)
+	KeyName string `yaml:"key_name"`
+
+	// Format of the detached .sig files (manifest, audit, bundle): "raw"
+	// (64-byte Ed25519 over SHA-256) or "minisign" (verifiable with
+	// minisign and signify)
+	SignatureFormat string `yaml:"signature_format"`
+
+	// Also pack the run into <run>.mbz (facts, report, manifest and
+	// signatures in one zip) with a detached Ed25519 <run>.mbz.sig
+	Bundle bool `yaml:"bundle"`
+
+	// Fields to redact from output
+	Redact []string `yaml:"redact"`
+
+	// Output directory (relative to Root unless absolute)
+	Directory string `yaml:"directory"`
+
+	// Base of a relative Directory; empty = the USB drive the binary runs
+	// from, falling back to the working directory
+	Root string `yaml:"root"`
+
+	// Maximum report size in bytes (0 = unlimited)
+	MaxReportBytes int `yaml:"max_report_bytes"`
+
+	// Risks always kept when the report is truncated (highest severity first)
+	ReportTopRisks int `yaml:"report_top_risks"`
+
+	// Curated remediation knowledge base (relative to USB root, embedded default if missing)
+	RemediationPath string `yaml:"remediation_path"`
+
+	// Artifact encodings to write (json, jsonl, cbor, csv, stix, ocsf, cef, leef, html)
+	Formats []string `yaml:"formats"`
+
+	// Network exporters (run when a network is available)
+	Exporters ExportersConfig `yaml:"exporters"`
+
+	// Bundle upload backends (spooled locally while offline)
+	Upload UploadConfig `yaml:"upload"`
+
+	// Offline queue for undelivered exporter payloads and bundles
+	Spool SpoolConfig `yaml:"spool"`
+
+	// SQLite run history for multi-machine engagements
+	History HistoryConfig `yaml:"history"`
+
+	// Append-only NDJSON run ledger (relative to USB root, "" disables)
+	LedgerPath string `yaml:"ledger_path"`
+
+	// When artifacts are flushed to the stick: "file" syncs each one as it
+	// is written, "batch" syncs the run directory once after the last
+	// ("" = file)
+	Fsync string `yaml:"fsync"`
+}
+
+// HistoryConfig defines the SQLite run history store
+type HistoryConfig struct {
+	// Record every run (facts, findings, metadata) in the database
+	Enabled bool `yaml:"enabled"`
+
+	// Database file (relative to USB root)
+	Path string `yaml:"path"`
+}
+
+// validate checks history settings (only when enabled)
+// Complexity: O(1)
+func (h *HistoryConfig) validate() error {
+	if !h.Enabled {
+		return nil
+	}
+	if h.Path == "" {
+		return &ValidationError{Field: "output.history.path", Reason: "must not be empty"}
+	}
+	return nil
+}
+
+// WebhookConfig defines the signed HTTPS webhook exporter
+type WebhookConfig struct {
+	// Enable webhook delivery
+	Enabled bool `yaml:"enabled"`
+
+	// Receiver URL (https required unless allow_http)
+	URL string `yaml:"url"`
+
+	// Permit plain http:// URLs (testing only)
+	AllowHTTP bool `yaml:"allow_http"`
+
+	// Ed25519 PEM private key used to sign request bodies (relative to USB root)
+	PrivateKeyPath string `yaml:"private_key_path"`
+
+	// Retries on 429/5xx/network errors
+	MaxRetries int `yaml:"max_retries"`
+
+	// PEM CA bundle (system roots if empty)
+	CAFile string `yaml:"ca_file"`
+
+	// Per-request timeout (milliseconds)
+	TimeoutMs int `yaml:"timeout_ms"`
+}
+
+// SMTPConfig defines the email exporter
+// The text report is sent as the message body with report.json attached.
+type SMTPConfig struct {
+	// Enable email delivery
+	Enabled bool `yaml:"enabled"`
+
+	// Mail server address (host:port, e.g. smtp.example.com:587)
+	Address string `yaml:"address"`
+
+	// Transport security: "starttls" (required upgrade), "tls" (implicit, port 465) or "none"
+	Security string `yaml:"security"`
+
+	// AUTH PLAIN credentials (empty username = no authentication)
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+
+	// Envelope and header sender
+	From string `yaml:"from"`
+
+	// Recipients
+	To []string `yaml:"to"`
+
+	// Prepended to "<hostname> report <run id>"
+	SubjectPrefix string `yaml:"subject_prefix"`
+
+	// Also attach the output bundle files (e.g., the encrypted archive) when present
+	AttachBundle bool `yaml:"attach_bundle"`
+
+	// PEM CA bundle for TLS (system roots if empty)
+	CAFile string `yaml:"ca_file"`
+
+	// Retries on 4xx replies and network errors (exponential backoff)
+	MaxRetries int `yaml:"max_retries"`
+
+	// Per-attempt timeout (milliseconds)
+	TimeoutMs int `yaml:"timeout_ms"`
+}
+
+// MQTTConfig defines the MQTT exporter
+// Records are published to <topic_prefix>/<hostname>/<record type>.
+type MQTTConfig struct {
+	// Enable MQTT publishing
+	Enabled bool `yaml:"enabled"`
+
+	// Broker address (host:port)
+	Address string `yaml:"address"`
+
+	// Connect over TLS
+	TLS bool `yaml:"tls"`
+
+	// PEM CA bundle for TLS (system roots if empty)
+	CAFile string `yaml:"ca_file"`
+
+	// Client identifier (empty = minibeast-<hostname>)
+	ClientID string `yaml:"client_id"`
+
+	// Broker credentials (optional)
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+
+	// Topic prefix
+	TopicPrefix string `yaml:"topic_prefix"`
+
+	// Delivery QoS: 0 (at most once) or 1 (at least once)
+	QoS int `yaml:"qos"`
+
+	// Publish with the retain flag (late subscribers see the last run)
+	Retain bool `yaml:"retain"`
+
+	// Retries on connection errors
+	MaxRetries int `yaml:"max_retries"`
+
+	// Connection and acknowledgement timeout (milliseconds)
+	TimeoutMs int `yaml:"timeout_ms"`
+}
+
+// SpoolConfig defines the durable offline queue shared by exporters and uploaders
+// Undelivered items wait under <directory>/<exporter or uploader>/ until a flush.
+type SpoolConfig struct {
+	// Queue directory (relative to USB root)
+	Directory string `yaml:"directory"`
+
+	// Flush daemon interval while the queue drains normally (milliseconds)
+	FlushIntervalMs int `yaml:"flush_interval_ms"`
+
+	// Backoff ceiling while deliveries keep failing (milliseconds)
+	MaxBackoffMs int `yaml:"max_backoff_ms"`
+}
+
+// validate checks spool settings
+// Complexity: O(1)
+func (s *SpoolConfig) validate() error {
+	if s.Directory == "" {
+		return &ValidationError{Field: "output.spool.directory", Reason: "must not be empty"}
+	}
+	if s.FlushIntervalMs <= 0 {
+		return &ValidationError{Field: "output.spool.flush_interval_ms", Reason: "must be positive"}
+	}
+	if s.MaxBackoffMs < s.FlushIntervalMs {
+		return &ValidationError{Field: "output.spool.max_backoff_ms", Reason: "must be at least flush_interval_ms"}
+	}
+	return nil
+}
+
+// UploadConfig defines delivery of the signed output bundle
+type UploadConfig struct {
+	// S3-compatible object storage (AWS S3, MinIO)
+	S3 S3Config `yaml:"s3"`
+
+	// SFTP dropbox
+	SFTP SFTPConfig `yaml:"sftp"`
+
+	// Azure Blob Storage
+	Azure AzureBlobConfig `yaml:"azure"`
+
+	// Google Cloud Storage
+	GCS GCSConfig `yaml:"gcs"`
+
+	// Plain HTTPS POST to a collection server
+	HTTPS HTTPSUploadConfig `yaml:"https"`
+}
+
+// HTTPSUploadConfig defines the HTTPS POST upload backend
+// Each bundle file is POSTed to <url>/<bundle>/<file> with a bearer token.
+type HTTPSUploadConfig struct {
+	// Enable HTTPS upload
+	Enabled bool `yaml:"enabled"`
+
+	// Collection server base URL (https required unless allow_http)
+	URL string `yaml:"url"`
+
+	// Permit plain http:// URLs (testing only)
+	AllowHTTP bool `yaml:"allow_http"`
+
+	// Bearer token sent in the Authorization header (empty = no auth)
+	AuthToken string `yaml:"auth_token"`
+
+	// Retries on 429/5xx/network errors
+	MaxRetries int `yaml:"max_retries"`
+
+	// PEM CA bundle (system roots if empty)
+	CAFile string `yaml:"ca_file"`
+
+	// Per-request timeout (milliseconds)
+	TimeoutMs int `yaml:"timeout_ms"`
+}
+
+// AzureBlobConfig defines the Azure Blob Storage upload backend
+// Auth is either a SAS token or the VM's managed identity (IMDS).
+type AzureBlobConfig struct {
+	// Enable Azure Blob upload
+	Enabled bool `yaml:"enabled"`
+
+	// Storage account name
+	Account string `yaml:"account"`
+
+	// Endpoint URL (empty = https://<account>.blob.core.windows.net)
+	Endpoint string `yaml:"endpoint"`
+
+	// Target container
+	Container string `yaml:"container"`
+
+	// Blob name prefix (blobs are <prefix>/<bundle>/<file>)
+	Prefix string `yaml:"prefix"`
+
+	// Authentication: "sas" or "managed_identity"
+	Auth string `yaml:"auth"`
+
+	// SAS token query string (sv=...&sig=...), needs create/write permission
+	SASToken string `yaml:"sas_token"`
+
+	// User-assigned identity client ID (empty = system-assigned identity)
+	ClientID string `yaml:"client_id"`
+
+	// Retries on 429/5xx/network errors
+	MaxRetries int `yaml:"max_retries"`
+
+	// PEM CA bundle (system roots if empty)
+	CAFile string `yaml:"ca_file"`
+
+	// Per-request timeout (milliseconds)
+	TimeoutMs int `yaml:"timeout_ms"`
+}
+
+// GCSConfig defines the Google Cloud Storage upload backend
+// Requests are authorized with a service-account JSON key.
+type GCSConfig struct {
+	// Enable GCS upload
+	Enabled bool `yaml:"enabled"`
+
+	// Target bucket
+	Bucket string `yaml:"bucket"`
+
+	// Object name prefix (objects are <prefix>/<bundle>/<file>)
+	Prefix string `yaml:"prefix"`
+
+	// Service-account JSON key (relative to USB root; empty = GOOGLE_APPLICATION_CREDENTIALS)
+	CredentialsFile string `yaml:"credentials_file"`
+
+	// Endpoint URL (empty = https://storage.googleapis.com)
+	Endpoint string `yaml:"endpoint"`
+
+	// Retries on 429/5xx/network errors
+	MaxRetries int `yaml:"max_retries"`
+
+	// PEM CA bundle (system roots if empty)
+	CAFile string `yaml:"ca_file"`
+
+	// Per-request timeout (milliseconds)
+	TimeoutMs int `yaml:"timeout_ms"`
+}
+
+// SFTPConfig defines the SFTP upload backend
+// The server host key must be pinned; unknown hosts are never trusted.
+type SFTPConfig struct {
+	// Enable SFTP upload
+	Enabled bool `yaml:"enabled"`
+
+	// Server address (host:port)
+	Address string `yaml:"address"`
+
+	// Login user
+	Username string `yaml:"username"`
+
+	// Password auth (used when private_key_path is empty or rejected)
+	Password string `yaml:"password"`
+
+	// Private key auth (relative to USB root)
+	PrivateKeyPath string `yaml:"private_key_path"`
+
+	// Passphrase for an encrypted private key
+	PrivateKeyPassphrase string `yaml:"private_key_passphrase"`
+
+	// Pinned host key: authorized_keys line ("ssh-ed25519 AAAA...") or "SHA256:<fingerprint>"
+	HostKey string `yaml:"host_key"`
+
+	// Remote directory receiving <bundle>/<file>
+	RemoteDir string `yaml:"remote_dir"`
+
+	// Retries on network errors
+	MaxRetries int `yaml:"max_retries"`
+
+	// Connection timeout (milliseconds)
+	TimeoutMs int `yaml:"timeout_ms"`
+}
+
+// S3Config defines the S3-compatible upload backend
+// Credentials fall back to AWS_* environment variables, then the EC2 instance role.
+type S3Config struct {
+	// Enable S3 upload
+	Enabled bool `yaml:"enabled"`
+
+	// Endpoint URL (empty = https://s3.<region>.amazonaws.com)
+	Endpoint string `yaml:"endpoint"`
+
+	// Signing region
+	Region string `yaml:"region"`
+
+	// Target bucket
+	Bucket string `yaml:"bucket"`
+
+	// Key prefix (objects are <prefix>/<bundle>/<file>)
+	Prefix string `yaml:"prefix"`
+
+	// Static credentials (empty = ambient credentials)
+	AccessKeyID     string `yaml:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key"`
+	SessionToken    string `yaml:"session_token"`
+
+	// Path-style addressing (required by most MinIO deployments)
+	PathStyle bool `yaml:"path_style"`
+
+	// Server-side encryption: "", "AES256" or "aws:kms"
+	SSE string `yaml:"sse"`
+
+	// KMS key for aws:kms (empty = bucket default key)
+	KMSKeyID string `yaml:"kms_key_id"`
+
+	// Retries on 429/5xx/network errors
+	MaxRetries int `yaml:"max_retries"`
+
+	// PEM CA bundle (system roots if empty)
+	CAFile string `yaml:"ca_file"`
+
+	// Per-request timeout (milliseconds)
+	TimeoutMs int `yaml:"timeout_ms"`
+}
+
+// ExportersConfig defines network delivery of run results
+type ExportersConfig struct {
+	// RFC 5424 syslog forwarding
+	Syslog SyslogConfig `yaml:"syslog"`
+
+	// Splunk HTTP Event Collector
+	Splunk SplunkConfig `yaml:"splunk"`
+
+	// Elasticsearch/OpenSearch bulk API
+	Elastic ElasticConfig `yaml:"elasticsearch"`
+
+	// Ed25519-signed HTTPS webhook
+	Webhook WebhookConfig `yaml:"webhook"`
+
+	// MQTT 3.1.1 broker publishing
+	MQTT MQTTConfig `yaml:"mqtt"`
+
+	// Email report delivery
+	SMTP SMTPConfig `yaml:"smtp"`
+
+	// Windows Application event log (ignored on other platforms)
+	EventLog EventLogConfig `yaml:"eventlog"`
+
+	// Kafka producer
+	Kafka KafkaConfig `yaml:"kafka"`
+}
+
+// KafkaConfig defines the Kafka producer exporter
+// One message per run and one per finding, keyed by run ID so a run's
+// messages land on the same partition in order.
+type KafkaConfig struct {
+	// Enable Kafka publishing
+	Enabled bool `yaml:"enabled"`
+
+	// Bootstrap brokers (host:port)
+	Brokers []string `yaml:"brokers"`
+
+	// Destination topic
+	Topic string `yaml:"topic"`
+
+	// Connect over TLS
+	TLS bool `yaml:"tls"`
+
+	// PEM CA bundle for TLS (system roots if empty)
+	CAFile string `yaml:"ca_file"`
+
+	// SASL mechanism: "", "plain", "scram-sha-256" or "scram-sha-512"
+	SASLMechanism string `yaml:"sasl_mechanism"`
+
+	// SASL credentials
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+
+	// Acknowledgement: "all" (every in-sync replica) or "leader"
+	Acks string `yaml:"acks"`
+
+	// Retries on broker and network errors
+	MaxRetries int `yaml:"max_retries"`
+
+	// Dial and write timeout (milliseconds)
+	TimeoutMs int `yaml:"timeout_ms"`
+}
+
+// EventLogConfig defines the Windows event log exporter
+type EventLogConfig struct {
+	// Write one event per run to the Application log
+	Enabled bool `yaml:"enabled"`
+
+	// Event source name shown in Event Viewer
+	Source string `yaml:"source"`
+
+	// Event ID for run summaries (1-1000, EventCreate message range)
+	EventID int `yaml:"event_id"`
+}
+
+// validate checks event log settings (only when enabled)
+// Complexity: O(1)
+func (e *EventLogConfig) validate() error {
+	if !e.Enabled {
+		return nil
+	}
+	if e.Source == "" || strings.ContainsAny(e.Source, `\/`) {
+		return &ValidationError{Field: "output.exporters.eventlog.source", Reason: "must be a non-empty name without slashes"}
+	}
+	if e.EventID < 1 || e.EventID > 1000 {
+		return &ValidationError{Field: "output.exporters.eventlog.event_id", Reason: "must be in [1, 1000]"}
+	}
+	return nil
+}
+
+// SyslogConfig defines the syslog exporter
+type SyslogConfig struct {
+	// Enable syslog forwarding
+	Enabled bool `yaml:"enabled"`
+
+	// Transport: "udp", "tcp" or "tls"
+	Network string `yaml:"network"`
+
+	// Collector address (host:port)
+	Address string `yaml:"address"`
+
+	// Syslog facility code (16 = local0)
+	Facility int `yaml:"facility"`
+
+	// APP-NAME header field
+	AppName string `yaml:"app_name"`
+
+	// Message body: "rfc5424" (structured data), "cef" (ArcSight) or "leef" (QRadar)
+	Format string `yaml:"format"`
+
+	// Also send the collected facts, one message per host, local user,
+	// volume, network interface and listening port
+	Facts bool `yaml:"facts"`
+
+	// PEM CA bundle for TLS (system roots if empty)
+	CAFile string `yaml:"ca_file"`
+
+	// Connect/write timeout (milliseconds)
+	TimeoutMs int `yaml:"timeout_ms"`
+}
+
+// SupportedFormats lists the valid output.formats entries
+var SupportedFormats = []string{"cbor", "cef", "csv", "html", "json", "jsonl", "leef", "ocsf", "parquet", "stix"}
+
+// PseudonymKinds lists the valid privacy.pseudonymize entries
+var PseudonymKinds = []string{"username", "hostname", "mac", "serial"}
+
+// PrivacyConfig defines keyed pseudonymization of identifiers: each is
+// replaced by an HMAC under a per-engagement salt, so runs from many
+// machines stay joinable without exposing the raw values
+type PrivacyConfig struct {
+	// Identifier kinds to pseudonymize (username, hostname, mac, serial)
+	Pseudonymize []string `yaml:"pseudonymize"`
+
+	// Anonymous mode: pseudonymize every identifier kind
+	Anonymous bool `yaml:"anonymous"`
+
+	// Sealed salt file (relative to USB root), opened with the passphrase in
+	// MINIBEAST_SALT_PASSPHRASE and bound to session.engagement
+	SaltPath string `yaml:"salt_path"`
+}
+
+// Kinds returns the identifier kinds to pseudonymize (all in anonymous mode)
+// Complexity: O(1)
+func (p *PrivacyConfig) Kinds() []string {
+	if p.Anonymous {
+		return PseudonymKinds
+	}
+	return p.Pseudonymize
+}
+
+// validate checks pseudonymization settings
+// Complexity: O(|pseudonymize|)
+func (p *PrivacyConfig) validate() error {
+	for _, kind := range p.Pseudonymize {
+		if !slices.Contains(PseudonymKinds, kind) {
+			return &ValidationError{Field: "privacy.pseudonymize", Reason: "unsupported identifier kind " + kind}
+		}
+	}
+	if len(p.Kinds()) > 0 && p.SaltPath == "" {
+		return &ValidationError{Field: "privacy.salt_path", Reason: "required to pseudonymize identifiers"}
+	}
+	return nil
+}
+
+// LLMConfig defines LLM inference settings (Phase 2)
+type LLMConfig struct {
+	// Enable LLM summarization
+	Enabled bool `yaml:"enabled"`
+
+	// Maximum tokens to generate
+	MaxTokens int `yaml:"max_tokens"`
+
+	// Temperature for sampling
+	Temperature float64 `yaml:"temp"`
+
+	// Model path (relative to USB root)
+	ModelPath string `yaml:"model_path"`
+
+	// Sessions is the number of independent sampling contexts sharing the
+	// loaded model, so serve modes can summarize several hosts at once
+	// (0 = 1)
+	Sessions int `yaml:"sessions"`
+
+	// Constrain report sampling to the SUMMARY/RISKS/ACTIONS grammar
+	// (llama.cpp GBNF), so the model cannot emit output the parser rejects
+	Grammar bool `yaml:"grammar"`
+
+	// Where generation runs: LLMBackendLocal or LLMBackendOpenAI
+	Backend string `yaml:"backend"`
+
+	// OpenAI-compatible server used by LLMBackendOpenAI
+	Remote RemoteLLMConfig `yaml:"remote"`
+}
+
+// LLM backends (llm.backend)
+const (
+	LLMBackendLocal  = "local"  // Bundled GGUF via llama.cpp (template engine in pure-Go builds)
+	LLMBackendOpenAI = "openai" // OpenAI-compatible chat completions API (Ollama, llama-server, vLLM)
+)
+
+// AnalysisConfig defines the built-in risk rules, which report on the facts
+// when llm.enabled is false or the model cannot be loaded
+type AnalysisConfig struct {
+	// Report from the rules when there is no model
+	Enabled bool `yaml:"enabled"`
+
+	// End-of-life os_version patterns by os_name (path.Match syntax, e.g.
+	// "10.0.1*" for Windows 10)
+	EOLVersions map[string][]string `yaml:"eol_versions"`
+
+	// Flag more administrator accounts than this
+	MaxAdmins int `yaml:"max_admins"`
+
+	// Flag a host whose last OS update is older than this many days (0
+	// disables the check)
+	MaxUpdateAgeDays int `yaml:"max_update_age_days"`
+
+	// Rule IDs not to evaluate (e.g., MB-SERIAL-UNKNOWN)
+	Disabled []string `yaml:"disabled"`
+}
+
+// validate checks the rule settings
+// Complexity: O(|patterns|)
+func (a *AnalysisConfig) validate() error {
+	for name, patterns := range a.EOLVersions {
+		for _, p := range patterns {
+			if _, err := path.Match(p, ""); err != nil || p == "" {
+				return &ValidationError{Field: "analysis.eol_versions." + name, Reason: "invalid pattern: " + p}
+			}
+		}
+	}
+	if a.MaxAdmins < 0 {
+		return &ValidationError{Field: "analysis.max_admins", Reason: "must not be negative"}
+	}
+	if a.MaxUpdateAgeDays < 0 {
+		return &ValidationError{Field: "analysis.max_update_age_days", Reason: "must not be negative"}
+	}
+	return nil
+}
+
+// RemoteLLMConfig defines the OpenAI-compatible inference server
+// The prompt, and with it the facts, is sent to this server.
+type RemoteLLMConfig struct {
+	// API base URL up to and including /v1 (e.g. http://127.0.0.1:11434/v1 for Ollama)
+	URL string `yaml:"url"`
+
+	// Permit plain http:// URLs to non-loopback hosts (facts travel unencrypted)
+	AllowHTTP bool `yaml:"allow_http"`
+
+	// Model name sent with every request
+	Model string `yaml:"model"`
+
+	// Bearer API key (empty = none, as for most local servers)
+	APIKey string `yaml:"api_key"`
+
+	// PEM CA bundle (system roots if empty)
+	CAFile string `yaml:"ca_file"`
+
+	// Per-request timeout (milliseconds)
+	TimeoutMs int `yaml:"timeout_ms"`
+}
+
+// validate checks the remote backend settings
+// Complexity: O(1)
+func (r *RemoteLLMConfig) validate() error {
+	u, err := url.Parse(r.URL)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return &ValidationError{Field: "llm.remote.url", Reason: "must be an http(s):// URL"}
+	}
+	if u.Scheme == "http" && !r.AllowHTTP {
+		if ip := net.ParseIP(u.Hostname()); u.Hostname() != "localhost" && (ip == nil || !ip.IsLoopback()) {
+			return &ValidationError{Field: "llm.remote.url", Reason: "must be https:// unless the server is on loopback or allow_http is set"}
+		}
+	}
+	if r.Model == "" {
+		return &ValidationError{Field: "llm.remote.model", Reason: "must not be empty"}
+	}
+	if r.TimeoutMs <= 0 {
+		return &ValidationError{Field: "llm.remote.timeout_ms", Reason: "must be positive"}
+	}
+	return nil
+}
+
+// PerformanceConfig defines performance constraints
+type PerformanceConfig struct {
+	// Maximum goroutines for parallel collection
+	MaxGoroutines int `yaml:"max_goroutines"`
+
+	// Total Phase 1 timeout (milliseconds)
+	Phase1TimeoutMs int `yaml:"phase1_timeout_ms"`
+
+	// Total Phase 2 timeout (milliseconds)
+	Phase2TimeoutMs int `yaml:"phase2_timeout_ms"`
+}
+
+// ResourcesConfig defines memory and CPU ceilings for the agent process
+type ResourcesConfig struct {
+	// Go heap soft limit in MiB (GOMEMLIMIT); 0 = unlimited
+	// With os_limits, also the hard ceiling for the whole process, model included.
+	MemoryLimitMB int `yaml:"memory_limit_mb"`
+
+	// OS threads running Go code at once (GOMAXPROCS); 0 = all CPUs
+	MaxProcs int `yaml:"max_procs"`
+
+	// llama.cpp inference threads; 0 = min(4, max_procs or CPUs)
+	LLMThreads int `yaml:"llm_threads"`
+
+	// Run below normal scheduling priority (nice 10 / BELOW_NORMAL_PRIORITY_CLASS)
+	LowPriority bool `yaml:"low_priority"`
+
+	// Enforce memory_limit_mb and cpu_percent through a cgroup v2 (Linux) or
+	// job object (Windows); best effort, the cgroup usually needs root
+	OSLimits bool `yaml:"os_limits"`
+
+	// CPU ceiling as a percentage of the whole machine (needs os_limits); 0 = unlimited
+	CPUPercent int `yaml:"cpu_percent"`
+}
+
+// validate checks resource ceilings
+// Complexity: O(1)
+func (r *ResourcesConfig) validate() error {
+	if r.MemoryLimitMB < 0 {
+		return &ValidationError{Field: "resources.memory_limit_mb", Reason: "must not be negative"}
+	}
+	if r.MemoryLimitMB > 0 && r.MemoryLimitMB < 64 {
+		return &ValidationError{Field: "resources.memory_limit_mb", Reason: "must be 0 (unlimited) or at least 64"}
+	}
+	if r.MaxProcs < 0 || r.MaxProcs > 1024 {
+		return &ValidationError{Field: "resources.max_procs", Reason: "must be between 0 and 1024"}
+	}
+	if r.LLMThreads < 0 || r.LLMThreads > 256 {
+		return &ValidationError{Field: "resources.llm_threads", Reason: "must be between 0 and 256"}
+	}
+	if r.CPUPercent < 0 || r.CPUPercent > 100 {
+		return &ValidationError{Field: "resources.cpu_percent", Reason: "must be between 0 and 100"}
+	}
+	if r.CPUPercent > 0 && !r.OSLimits {
+		return &ValidationError{Field: "resources.cpu_percent", Reason: "requires os_limits: true"}
+	}
+	return nil
+}
+
+// InferenceThreads resolves llm_threads against max_procs and the CPU count
+// Complexity: O(1)
+func (r *ResourcesConfig) InferenceThreads(cpus int) int {
+	if r.LLMThreads > 0 {
+		return r.LLMThreads
+	}
+	if r.MaxProcs > 0 {
+		cpus = min(cpus, r.MaxProcs)
+	}
+	return max(min(cpus, 4), 1)
+}
+
+// TelemetryConfig defines OpenTelemetry tracing of the pipeline phases
+type TelemetryConfig struct {
+	// Span exporter: "none", "otlp" (OTLP/HTTP) or "file" (JSON spans on the stick)
+	Exporter string `yaml:"exporter"`
+
+	// OTLP/HTTP endpoint URL (e.g., http://collector:4318)
+	OTLPEndpoint string `yaml:"otlp_endpoint"`
+
+	// Extra OTLP request headers (e.g., authorization)
+	OTLPHeaders map[string]string `yaml:"otlp_headers"`
+
+	// Trace file, one JSON span per line (relative to USB root)
+	FilePath string `yaml:"file_path"`
+}
+
+// validate checks telemetry settings
+// Complexity: O(1)
+func (t *TelemetryConfig) validate() error {
+	switch t.Exporter {
+	case "none":
+	case "otlp":
+		if t.OTLPEndpoint == "" {
+			return &ValidationError{Field: "telemetry.otlp_endpoint", Reason: "must not be empty"}
+		}
+	case "file":
+		if t.FilePath == "" {
+			return &ValidationError{Field: "telemetry.file_path", Reason: "must not be empty"}
+		}
+	default:
+		return &ValidationError{Field: "telemetry.exporter", Reason: "must be none, otlp or file"}
+	}
+	return nil
+}
+
+// UsageStatsConfig defines opt-in anonymous usage statistics: one record
+// per run of the agent version, OS, category durations and failure class
+type UsageStatsConfig struct {
+	// Off unless explicitly enabled
+	Enabled bool `yaml:"enabled"`
+
+	// HTTPS endpoint each record is POSTed to ("" writes FilePath only)
+	Endpoint string `yaml:"endpoint"`
+
+	// Local file, one JSON record per line (relative to USB root; "" disables)
+	FilePath string `yaml:"file_path"`
+}
+
+// validate checks usage statistics settings (only when enabled)
+// Complexity: O(1)
+func (u *UsageStatsConfig) validate() error {
+	if !u.Enabled {
+		return nil
+	}
+	if u.Endpoint == "" && u.FilePath == "" {
+		return &ValidationError{Field: "usage_stats", Reason: "endpoint or file_path required when enabled"}
+	}
+	if u.Endpoint != "" && !strings.HasPrefix(u.Endpoint, "https://") {
+		return &ValidationError{Field: "usage_stats.endpoint", Reason: "must be an https:// URL"}
+	}
+	return nil
+}
+
+// ConsentConfig defines the operator acknowledgment recorded with each run
+type ConsentConfig struct {
+	// Operator name or initials recorded for -assume-yes and unattended
+	// (daemon, watch) runs, and the default at the interactive prompt
+	Operator string `yaml:"operator"`
+}
+
+// validate checks consent settings
+// Complexity: O(1)
+func (c *ConsentConfig) validate() error {
+	if len(c.Operator) > 64 {
+		return &ValidationError{Field: "consent.operator", Reason: "must be at most 64 bytes"}
+	}
+	if strings.ContainsAny(c.Operator, "\r\n\t") {
+		return &ValidationError{Field: "consent.operator", Reason: "must be a single line"}
+	}
+	return nil
+}
+
+// PluginsConfig defines discovery of exec-based plugins
+type PluginsConfig struct {
+	// Load plugins from Directory
+	Enabled bool `yaml:"enabled"`
+
+	// One subdirectory per plugin, each with a plugin.yaml manifest (relative to USB root)
+	Directory string `yaml:"directory"`
+
+	// Default per-call timeout (milliseconds); a manifest may set its own
+	TimeoutMs int `yaml:"timeout_ms"`
+}
+
+// validate checks plugin settings (only when enabled)
+// Complexity: O(1)
+func (p *PluginsConfig) validate() error {
+	if !p.Enabled {
+		return nil
+	}
+	if p.Directory == "" {
+		return &ValidationError{Field: "plugins.directory", Reason: "required when plugins are enabled"}
+	}
+	if p.TimeoutMs <= 0 {
+		return &ValidationError{Field: "plugins.timeout_ms", Reason: "must be positive"}
+	}
+	return nil
+}
+
+// AuditConfig defines the per-run audit file
+type AuditConfig struct {
+	// Write <run>.audit.json and its signature alongside each run's artifacts
+	Enabled bool `yaml:"enabled"`
+
+	// Ed25519 PEM private key signing the audit file (relative to USB root);
+	// empty signs with a fresh key per run, whose public key is embedded
+	SigningKey string `yaml:"signing_key"`
+}
+
+// LoggingConfig defines the per-run diagnostic log
+type LoggingConfig struct {
+	// Lowest level recorded: debug, info, warn or error
+	Level string `yaml:"level"`
+
+	// Record format: text (key=value) or json (one object per line)
+	Format string `yaml:"format"`
+
+	// Write <run>.log alongside each run's artifacts
+	File bool `yaml:"file"`
+
+	// Also write records to stderr
+	Stderr bool `yaml:"stderr"`
+}
+
+// validate checks the level and format
+// Complexity: O(1)
+func (l *LoggingConfig) validate() error {
+	switch strings.ToLower(l.Level) {
+	case "debug", "info", "warn", "error":
+	default:
+		return &ValidationError{Field: "logging.level", Reason: "must be debug, info, warn or error"}
+	}
+	if l.Format != "text" && l.Format != "json" {
+		return &ValidationError{Field: "logging.format", Reason: "must be text or json"}
+	}
+	return nil
+}
+
+// MaxSessionTags bounds session.tags
+const MaxSessionTags = 32
+
+// SessionConfig defines metadata recorded with every run, so runs from one
+// engagement can be correlated across hosts
+type SessionConfig struct {
+	// Engagement or case identifier (e.g., "ACME-2025-014")
+	Engagement string `yaml:"engagement"`
+
+	// Free-form key/value labels; keys are lowercase [a-z0-9_.-]
+	Tags map[string]string `yaml:"tags"`
+}
+
+// validate checks the engagement and tags fit on one line of every export
+// Complexity: O(|tags|)
+func (s *SessionConfig) validate() error {
+	if len(s.Engagement) > 128 {
+		return &ValidationError{Field: "session.engagement", Reason: "must be at most 128 bytes"}
+	}
+	if strings.ContainsAny(s.Engagement, "\r\n\t") {
+		return &ValidationError{Field: "session.engagement", Reason: "must be a single line"}
+	}
+	if len(s.Tags) > MaxSessionTags {
+		return &ValidationError{Field: "session.tags", Reason: "must have at most 32 entries"}
+	}
+	for key, value := range s.Tags {
+		if !isTagKey(key) {
+			return &ValidationError{Field: "session.tags", Reason: "key " + key + " must be 1-64 characters of a-z, 0-9, '_', '.' or '-'"}
+		}
+		if len(value) > 256 {
+			return &ValidationError{Field: "session.tags." + key, Reason: "must be at most 256 bytes"}
+		}
+		if strings.ContainsAny(value, "\r\n\t") {
+			return &ValidationError{Field: "session.tags." + key, Reason: "must be a single line"}
+		}
+	}
+	return nil
+}
+
+// isTagKey reports whether key is a valid session tag key
+func isTagKey(key string) bool {
+	if key == "" || len(key) > 64 {
+		return false
+	}
+	for _, r := range key {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_', r == '.', r == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// ServiceConfig defines the long-running service endpoints
+type ServiceConfig struct {
+	// gRPC server (Collect, Summarize, Verify)
+	GRPC GRPCConfig `yaml:"grpc"`
+
+	// Local REST API (serve mode)
+	REST RESTConfig `yaml:"rest"`
+
+	// Scheduled runs for installed (non-USB) deployments
+	Daemon DaemonConfig `yaml:"daemon"`
+
+	// Plug-and-walk-away runs when the stick is inserted
+	Watch WatchConfig `yaml:"watch"`
+
+	// Resident agent: scheduled runs plus the APIs serving their results
+	Server ServerConfig `yaml:"server"`
+}
+
+// ServerConfig defines agent mode (`minibeast serve`)
+// The schedule and run directories come from Daemon, the endpoints from
+// REST and GRPC.
+type ServerConfig struct {
+	// Also serve the gRPC API (the REST API is always served)
+	GRPC bool `yaml:"grpc"`
+
+	// Run once at startup instead of waiting for the first activation
+	RunAtStart bool `yaml:"run_at_start"`
+}
+
+// WatchConfig defines USB insertion watcher mode
+type WatchConfig struct {
+	// Volume poll interval (milliseconds)
+	PollIntervalMs int `yaml:"poll_interval_ms"`
+
+	// Completion marker written to the stick root after each run
+	DoneFile string `yaml:"done_file"`
+
+	// Ring the terminal bell on completion
+	Beep bool `yaml:"beep"`
+}
+
+// validate checks watcher settings
+// Complexity: O(1)
+func (w *WatchConfig) validate() error {
+	if w.PollIntervalMs <= 0 {
+		return &ValidationError{Field: "service.watch.poll_interval_ms", Reason: "must be positive"}
+	}
+	if w.DoneFile == "" || strings.ContainsAny(w.DoneFile, `/\`) {
+		return &ValidationError{Field: "service.watch.done_file", Reason: "must be a plain file name"}
+	}
+	return nil
+}
+
+// DaemonConfig defines daemon mode scheduling
+type DaemonConfig struct {
+	// Cron expression ("0 2 * * *"), descriptor ("@daily") or interval ("@every 6h")
+	Schedule string `yaml:"schedule"`
+
+	// Maximum random delay added to each run (milliseconds)
+	JitterMs int `yaml:"jitter_ms"`
+
+	// Parent of the per-run output directories (relative to USB root)
+	RunsDirectory string `yaml:"runs_directory"`
+}
+
+// validate checks daemon settings (the schedule itself is parsed at startup)
+// Complexity: O(1)
+func (d *DaemonConfig) validate() error {
+	if strings.TrimSpace(d.Schedule) == "" {
+		return &ValidationError{Field: "service.daemon.schedule", Reason: "must not be empty"}
+	}
+	if d.JitterMs < 0 {
+		return &ValidationError{Field: "service.daemon.jitter_ms", Reason: "must not be negative"}
+	}
+	if d.RunsDirectory == "" {
+		return &ValidationError{Field: "service.daemon.runs_directory", Reason: "must not be empty"}
+	}
+	return nil
+}
+
+// RESTConfig defines the local REST API
+type RESTConfig struct {
+	// Listen address (must be a loopback address)
+	Address string `yaml:"address"`
 
-	// Fields to redact from output
-	Redact []string `yaml:"redact"`
+	// Bearer token required on every endpoint except /health (required to serve)
+	AuthToken string `yaml:"auth_token"`
+}
 
-	// Output directory (relative to USB root)
-	Directory string `yaml:"directory"`
+// validate checks REST API settings
+// Complexity: O(1)
+func (r *RESTConfig) validate() error {
+	loopback, err := isLoopbackAddress(r.Address)
+	if err != nil {
+		return &ValidationError{Field: "service.rest.address", Reason: "must be host:port"}
+	}
+	if !loopback {
+		return &ValidationError{Field: "service.rest.address", Reason: "must be a loopback address"}
+	}
+	return nil
 }
 
-// LLMConfig defines LLM inference settings (Phase 2)
-type LLMConfig struct {
-	// Enable LLM summarization
-	Enabled bool `yaml:"enabled"`
+// isLoopbackAddress reports whether a host:port listen address is
+// "localhost" or a loopback IP
+// Complexity: O(1)
+func isLoopbackAddress(address string) (bool, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false, err
+	}
+	if host == "localhost" {
+		return true, nil
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback(), nil
+}
 
-	// Maximum tokens to generate
-	MaxTokens int `yaml:"max_tokens"`
+// GRPCConfig defines the gRPC server
+type GRPCConfig struct {
+	// Listen address
+	Address string `yaml:"address"`
 
-	// Temperature for sampling
-	Temperature float64 `yaml:"temp"`
+	// Bearer token required in "authorization" metadata (required when
+	// service.server.grpc is on)
+	AuthToken string `yaml:"auth_token"`
 
-	// Model path (relative to USB root)
-	ModelPath string `yaml:"model_path"`
-}
+	// Server certificate and key (plaintext if both empty)
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
 
-// PerformanceConfig defines performance constraints
-type PerformanceConfig struct {
-	// Maximum goroutines for parallel collection
-	MaxGoroutines int `yaml:"max_goroutines"`
+	// Require client certificates signed by this CA (mutual TLS)
+	ClientCAFile string `yaml:"client_ca_file"`
+}
 
-	// Total Phase 1 timeout (milliseconds)
-	Phase1TimeoutMs int `yaml:"phase1_timeout_ms"`
+// validate checks gRPC server settings
+// Complexity: O(1)
+func (g *GRPCConfig) validate() error {
+	if g.Address == "" {
+		return &ValidationError{Field: "service.grpc.address", Reason: "must not be empty"}
+	}
+	if (g.CertFile == "") != (g.KeyFile == "") {
+		return &ValidationError{Field: "service.grpc.cert_file", Reason: "cert_file and key_file must be set together"}
+	}
+	if g.ClientCAFile != "" && g.CertFile == "" {
+		return &ValidationError{Field: "service.grpc.client_ca_file", Reason: "requires cert_file and key_file"}
+	}
+	return nil
+}
 
-	// Total Phase 2 timeout (milliseconds)
-	Phase2TimeoutMs int `yaml:"phase2_timeout_ms"`
+// validateServing checks the settings serve mode needs before exposing host
+// facts over gRPC: a token always, and mutual TLS off loopback
+// Complexity: O(1)
+func (g *GRPCConfig) validateServing() error {
+	if g.AuthToken == "" {
+		return &ValidationError{Field: "service.grpc.auth_token", Reason: "must be set with service.server.grpc"}
+	}
+	loopback, err := isLoopbackAddress(g.Address)
+	if err != nil {
+		return &ValidationError{Field: "service.grpc.address", Reason: "must be host:port"}
+	}
+	if !loopback && g.ClientCAFile == "" {
+		return &ValidationError{Field: "service.grpc.address", Reason: "must be a loopback address unless mutual TLS (cert_file, key_file and client_ca_file) is configured"}
+	}
+	return nil
 }
 
 // Default returns a Config with mathematical default values
@@ -88,24 +1243,207 @@ func Default() *Config {
 			WiFiSSIDs:         true,
 			HardwareIDs:       true,
 			CategoryTimeoutMs: 500, // 500ms per category
+			SoftwareInventory: true,
+			SoftwareTimeoutMs: 5000, // 5 seconds
+			Processes:         false,
+			Disks:             true,
+			ListeningPorts:    true,
+			SecurityProducts:  true,
+			PatchLevel:        true,
 		},
 		Output: OutputConfig{
-			Encrypt:   false,
-			Sign:      true,
-			Redact:    []string{},
-			Directory: "out",
+			Encrypt:         false,
+			RecipientKeys:   []string{},
+			Sign:            true,
+			KeyBackend:      "file",
+			KeyName:         "minibeast-signing",
+			SignatureFormat: "raw",
+			Bundle:          false,
+			Redact:          []string{},
+			Directory:       "out",
+			MaxReportBytes:  0, // Unlimited
+			ReportTopRisks:  3,
+			RemediationPath: "config/remediation.yaml",
+			Formats:         []string{"json"},
+			Fsync:           "file",
+			Exporters: ExportersConfig{
+				Syslog: SyslogConfig{
+					Enabled:   false,
+					Network:   "udp",
+					Facility:  16, // local0
+					AppName:   "minibeast",
+					Format:    "rfc5424",
+					Facts:     false,
+					TimeoutMs: 2000,
+				},
+				Splunk: SplunkConfig{
+					Enabled:    false,
+					SourceType: "minibeast:event",
+					BatchSize:  100,
+					MaxRetries: 3,
+					TimeoutMs:  5000,
+				},
+				Elastic: ElasticConfig{
+					Enabled:          false,
+					FactsIndex:       "minibeast-facts",
+					FindingsIndex:    "minibeast-findings",
+					InstallTemplates: true,
+					MaxRetries:       3,
+					TimeoutMs:        5000,
+				},
+				Webhook: WebhookConfig{
+					Enabled:    false,
+					MaxRetries: 3,
+					TimeoutMs:  5000,
+				},
+				SMTP: SMTPConfig{
+					Enabled:       false,
+					Security:      "starttls",
+					SubjectPrefix: "[MiniBeast]",
+					MaxRetries:    3,
+					TimeoutMs:     10000,
+				},
+				EventLog: EventLogConfig{
+					Enabled: false,
+					Source:  "MiniBeast",
+					EventID: 100,
+				},
+				Kafka: KafkaConfig{
+					Enabled:    false,
+					Topic:      "minibeast",
+					Acks:       "all",
+					MaxRetries: 3,
+					TimeoutMs:  10000,
+				},
+				MQTT: MQTTConfig{
+					Enabled:     false,
+					TopicPrefix: "minibeast",
+					QoS:         1,
+					MaxRetries:  3,
+					TimeoutMs:   5000,
+				},
+			},
+			Upload: UploadConfig{
+				S3: S3Config{
+					Enabled:    false,
+					Region:     "us-east-1",
+					Prefix:     "minibeast",
+					MaxRetries: 3,
+					TimeoutMs:  30000,
+				},
+				SFTP: SFTPConfig{
+					Enabled:    false,
+					RemoteDir:  "minibeast",
+					MaxRetries: 3,
+					TimeoutMs:  10000,
+				},
+				Azure: AzureBlobConfig{
+					Enabled:    false,
+					Prefix:     "minibeast",
+					Auth:       "sas",
+					MaxRetries: 3,
+					TimeoutMs:  30000,
+				},
+				GCS: GCSConfig{
+					Enabled:    false,
+					Prefix:     "minibeast",
+					MaxRetries: 3,
+					TimeoutMs:  30000,
+				},
+				HTTPS: HTTPSUploadConfig{
+					Enabled:    false,
+					MaxRetries: 3,
+					TimeoutMs:  30000,
+				},
+			},
+			Spool: SpoolConfig{
+				Directory:       "spool",
+				FlushIntervalMs: 60000,  // 1 minute
+				MaxBackoffMs:    900000, // 15 minutes
+			},
+			History: HistoryConfig{
+				Enabled: false,
+				Path:    "out/history.db",
+			},
+			LedgerPath: "out/runs.ndjson",
 		},
 		LLM: LLMConfig{
 			Enabled:     true,
 			MaxTokens:   160,
 			Temperature: 0.1,
 			ModelPath:   "models/tinyllama-1.1b-q4.gguf",
+			Grammar:     true,
+			Backend:     LLMBackendLocal,
+			Remote: RemoteLLMConfig{
+				TimeoutMs: 60000, // CPU-only servers take a while per report
+			},
+		},
+		Analysis: AnalysisConfig{
+			Enabled: true,
+			EOLVersions: map[string][]string{
+				"Windows": {"5.*", "6.*", "10.0.1*"},        // XP through 8.1, Windows 10
+				"Darwin":  {"10.*", "11.*", "12.*", "13.*"}, // Through Ventura
+				"Linux":   {"14.04", "16.04", "18.04", "20.04"},
+			},
+			MaxAdmins:        2,
+			MaxUpdateAgeDays: 60,
 		},
 		Performance: PerformanceConfig{
 			MaxGoroutines:   8,
 			Phase1TimeoutMs: 2000, // 2 seconds
 			Phase2TimeoutMs: 3000, // 3 seconds
 		},
+		Resources: ResourcesConfig{
+			MemoryLimitMB: 0, // Unlimited
+			MaxProcs:      0, // All CPUs
+			LLMThreads:    0, // min(4, CPUs)
+			LowPriority:   true,
+		},
+		Service: ServiceConfig{
+			GRPC: GRPCConfig{
+				Address: "127.0.0.1:50051", // Loopback only by default
+			},
+			REST: RESTConfig{
+				Address: "127.0.0.1:8765",
+			},
+			Daemon: DaemonConfig{
+				Schedule:      "0 2 * * *", // Daily at 02:00 local time
+				JitterMs:      600000,      // 10 minutes
+				RunsDirectory: "out/runs",
+			},
+			Watch: WatchConfig{
+				PollIntervalMs: 2000,
+				DoneFile:       "DONE",
+				Beep:           true,
+			},
+			Server: ServerConfig{
+				RunAtStart: true, // Serve facts as soon as the agent is up
+			},
+		},
+		Telemetry: TelemetryConfig{
+			Exporter: "none",
+			FilePath: "out/traces.jsonl",
+		},
+		UsageStats: UsageStatsConfig{
+			Enabled:  false, // Strictly opt-in
+			FilePath: "out/usage.jsonl",
+		},
+		Plugins: PluginsConfig{
+			Enabled:   true,
+			Directory: "plugins",
+			TimeoutMs: 10000, // 10 seconds
+		},
+		Audit: AuditConfig{
+			Enabled: true,
+		},
+		Logging: LoggingConfig{
+			Level:  "info",
+			Format: "text",
+			File:   true,
+		},
+		Privacy: PrivacyConfig{
+			SaltPath: "keys/engagement.salt",
+		},
 	}
 }
 
@@ -113,10 +1451,17 @@ func Default() *Config {
 // Returns error if invariants violated
 // Complexity: O(1)
 func (c *Config) Validate() error {
+	if len(c.Locale) > 35 || strings.ContainsAny(c.Locale, " \t\r\n") {
+		return &ValidationError{Field: "locale", Reason: "must be a language tag such as es or pt-BR"}
+	}
+
 	// Validate positive timeouts
 	if c.Collect.CategoryTimeoutMs <= 0 {
 		return &ValidationError{Field: "collect.category_timeout_ms", Reason: "must be positive"}
 	}
+	if (c.Collect.SoftwareInventory || c.Collect.SecurityProducts || c.Collect.PatchLevel) && c.Collect.SoftwareTimeoutMs <= 0 {
+		return &ValidationError{Field: "collect.software_timeout_ms", Reason: "must be positive"}
+	}
 	if c.Performance.Phase1TimeoutMs <= 0 {
 		return &ValidationError{Field: "performance.phase1_timeout_ms", Reason: "must be positive"}
 	}
@@ -129,6 +1474,11 @@ func (c *Config) Validate() error {
 		return &ValidationError{Field: "performance.max_goroutines", Reason: "must be between 1 and 32"}
 	}
 
+	// Validate resource ceilings
+	if err := c.Resources.validate(); err != nil {
+		return err
+	}
+
 	// Validate LLM parameters
 	if c.LLM.MaxTokens < 1 || c.LLM.MaxTokens > 2048 {
 		return &ValidationError{Field: "llm.max_tokens", Reason: "must be between 1 and 2048"}
@@ -136,16 +1486,576 @@ func (c *Config) Validate() error {
 	if c.LLM.Temperature < 0.0 || c.LLM.Temperature > 2.0 {
 		return &ValidationError{Field: "llm.temperature", Reason: "must be between 0.0 and 2.0"}
 	}
+	if c.LLM.Sessions < 0 || c.LLM.Sessions > 8 {
+		return &ValidationError{Field: "llm.sessions", Reason: "must be between 0 and 8"}
+	}
+	switch c.LLM.Backend {
+	case LLMBackendLocal:
+	case LLMBackendOpenAI:
+		if c.LLM.Enabled {
+			if err := c.LLM.Remote.validate(); err != nil {
+				return err
+			}
+		}
+	default:
+		return &ValidationError{Field: "llm.backend", Reason: "must be local or openai"}
+	}
+
+	if err := c.Analysis.validate(); err != nil {
+		return err
+	}
+
+	// Validate report budget
+	if c.Output.MaxReportBytes < 0 {
+		return &ValidationError{Field: "output.max_report_bytes", Reason: "must not be negative"}
+	}
+	if c.Output.ReportTopRisks < 0 {
+		return &ValidationError{Field: "output.report_top_risks", Reason: "must not be negative"}
+	}
+	switch c.Output.Fsync {
+	case "", "file", "batch":
+	default:
+		return &ValidationError{Field: "output.fsync", Reason: "must be file or batch"}
+	}
+	if c.Output.Bundle && c.Output.Encrypt {
+		return &ValidationError{Field: "output.bundle", Reason: "cannot be combined with output.encrypt (the bundle is not encrypted)"}
+	}
+	switch c.Output.KeyBackend {
+	case "", "file":
+	case "tpm", "secure_enclave":
+		if c.Output.KeyName == "" {
+			return &ValidationError{Field: "output.key_name", Reason: "must not be empty"}
+		}
+		if c.Audit.SigningKey != "" {
+			return &ValidationError{Field: "audit.signing_key", Reason: "must be empty when output.key_backend is " + c.Output.KeyBackend}
+		}
+	default:
+		return &ValidationError{Field: "output.key_backend", Reason: "must be file, tpm or secure_enclave"}
+	}
+	switch c.Output.SignatureFormat {
+	case "", "raw", "minisign":
+	default:
+		return &ValidationError{Field: "output.signature_format", Reason: "must be raw or minisign"}
+	}
+
+	// Validate exporters
+	if err := c.Output.Exporters.Syslog.validate(); err != nil {
+		return err
+	}
+	if err := c.Output.Exporters.Splunk.validate(); err != nil {
+		return err
+	}
+	if err := c.Output.Exporters.Elastic.validate(); err != nil {
+		return err
+	}
+	if err := c.Output.Exporters.Webhook.validate(); err != nil {
+		return err
+	}
+	if err := c.Output.Exporters.MQTT.validate(); err != nil {
+		return err
+	}
+	if err := c.Output.Exporters.SMTP.validate(); err != nil {
+		return err
+	}
+	if err := c.Output.Exporters.EventLog.validate(); err != nil {
+		return err
+	}
+	if err := c.Output.Exporters.Kafka.validate(); err != nil {
+		return err
+	}
+
+	// Validate upload backends
+	if err := c.Output.Spool.validate(); err != nil {
+		return err
+	}
+	if c.Output.Encrypt && len(c.Output.RecipientKeys) == 0 {
+		return &ValidationError{Field: "output.recipient_keys", Reason: "must list at least one key when encrypt is enabled"}
+	}
+	if len(c.Output.RecipientKeys) > 32 {
+		return &ValidationError{Field: "output.recipient_keys", Reason: "must list at most 32 keys"}
+	}
+	if err := c.Output.Upload.S3.validate(); err != nil {
+		return err
+	}
+	if err := c.Output.Upload.SFTP.validate(); err != nil {
+		return err
+	}
+	if err := c.Output.Upload.Azure.validate(); err != nil {
+		return err
+	}
+	if err := c.Output.Upload.GCS.validate(); err != nil {
+		return err
+	}
+	if err := c.Output.Upload.HTTPS.validate(); err != nil {
+		return err
+	}
+	if err := c.Output.History.validate(); err != nil {
+		return err
+	}
+
+	// Validate service endpoints
+	if err := c.Service.GRPC.validate(); err != nil {
+		return err
+	}
+	if c.Service.Server.GRPC {
+		if err := c.Service.GRPC.validateServing(); err != nil {
+			return err
+		}
+	}
+	if err := c.Service.REST.validate(); err != nil {
+		return err
+	}
+	if err := c.Service.Daemon.validate(); err != nil {
+		return err
+	}
+	if err := c.Service.Watch.validate(); err != nil {
+		return err
+	}
+
+	// Validate telemetry
+	if err := c.Telemetry.validate(); err != nil {
+		return err
+	}
+
+	// Validate usage statistics
+	if err := c.UsageStats.validate(); err != nil {
+		return err
+	}
+
+	// Validate consent
+	if err := c.Consent.validate(); err != nil {
+		return err
+	}
+
+	// Validate plugins
+	if err := c.Plugins.validate(); err != nil {
+		return err
+	}
+
+	// Validate logging
+	if err := c.Logging.validate(); err != nil {
+		return err
+	}
+
+	// Validate session metadata
+	if err := c.Session.validate(); err != nil {
+		return err
+	}
+	if err := c.Privacy.validate(); err != nil {
+		return err
+	}
+
+	// Validate output formats
+	for _, format := range c.Output.Formats {
+		if !isSupportedFormat(format) {
+			return &ValidationError{Field: "output.formats", Reason: "unsupported format " + format}
+		}
+	}
+
+	return nil
+}
+
+// validate checks syslog exporter settings (only when enabled)
+// Complexity: O(1)
+func (s *SyslogConfig) validate() error {
+	if !s.Enabled {
+		return nil
+	}
+	switch s.Network {
+	case "udp", "tcp", "tls":
+	default:
+		return &ValidationError{Field: "output.exporters.syslog.network", Reason: "must be udp, tcp or tls"}
+	}
+	if s.Address == "" {
+		return &ValidationError{Field: "output.exporters.syslog.address", Reason: "must not be empty"}
+	}
+	if s.Facility < 0 || s.Facility > 23 {
+		return &ValidationError{Field: "output.exporters.syslog.facility", Reason: "must be between 0 and 23"}
+	}
+	switch s.Format {
+	case "rfc5424", "cef", "leef":
+	default:
+		return &ValidationError{Field: "output.exporters.syslog.format", Reason: "must be rfc5424, cef or leef"}
+	}
+	if s.TimeoutMs <= 0 {
+		return &ValidationError{Field: "output.exporters.syslog.timeout_ms", Reason: "must be positive"}
+	}
+	return nil
+}
+
+// SplunkConfig defines the Splunk HEC exporter
+type SplunkConfig struct {
+	// Enable HEC delivery
+	Enabled bool `yaml:"enabled"`
+
+	// HEC base URL (e.g., https://splunk.example.com:8088)
+	URL string `yaml:"url"`
+
+	// HEC token
+	Token string `yaml:"token"`
+
+	// Target index (HEC token default if empty)
+	Index string `yaml:"index"`
+
+	// Event sourcetype
+	SourceType string `yaml:"sourcetype"`
+
+	// Events per request
+	BatchSize int `yaml:"batch_size"`
+
+	// Retries per batch on 429/5xx/network errors
+	MaxRetries int `yaml:"max_retries"`
+
+	// PEM CA bundle (system roots if empty)
+	CAFile string `yaml:"ca_file"`
+
+	// Per-request timeout (milliseconds)
+	TimeoutMs int `yaml:"timeout_ms"`
+}
+
+// validate checks Splunk exporter settings (only when enabled)
+// Complexity: O(1)
+func (s *SplunkConfig) validate() error {
+	if !s.Enabled {
+		return nil
+	}
+	if s.URL == "" {
+		return &ValidationError{Field: "output.exporters.splunk.url", Reason: "must not be empty"}
+	}
+	if s.Token == "" {
+		return &ValidationError{Field: "output.exporters.splunk.token", Reason: "must not be empty"}
+	}
+	if s.BatchSize < 1 {
+		return &ValidationError{Field: "output.exporters.splunk.batch_size", Reason: "must be positive"}
+	}
+	if s.MaxRetries < 0 {
+		return &ValidationError{Field: "output.exporters.splunk.max_retries", Reason: "must not be negative"}
+	}
+	if s.TimeoutMs <= 0 {
+		return &ValidationError{Field: "output.exporters.splunk.timeout_ms", Reason: "must be positive"}
+	}
+	return nil
+}
+
+// ElasticConfig defines the Elasticsearch/OpenSearch bulk exporter
+type ElasticConfig struct {
+	// Enable bulk indexing
+	Enabled bool `yaml:"enabled"`
+
+	// Cluster URL (e.g., https://search.example.com:9200)
+	URL string `yaml:"url"`
+
+	// Base64 API key (Elasticsearch "ApiKey" auth)
+	APIKey string `yaml:"api_key"`
+
+	// Basic auth (OpenSearch), used when api_key is empty
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+
+	// Index receiving one facts document per run
+	FactsIndex string `yaml:"facts_index"`
+
+	// Index receiving one document per finding
+	FindingsIndex string `yaml:"findings_index"`
+
+	// Install composable index templates before indexing
+	InstallTemplates bool `yaml:"install_templates"`
+
+	// Retries on 429/5xx/network errors
+	MaxRetries int `yaml:"max_retries"`
+
+	// PEM CA bundle (system roots if empty)
+	CAFile string `yaml:"ca_file"`
+
+	// Per-request timeout (milliseconds)
+	TimeoutMs int `yaml:"timeout_ms"`
+}
+
+// validate checks Elasticsearch exporter settings (only when enabled)
+// Complexity: O(1)
+func (e *ElasticConfig) validate() error {
+	if !e.Enabled {
+		return nil
+	}
+	if e.URL == "" {
+		return &ValidationError{Field: "output.exporters.elasticsearch.url", Reason: "must not be empty"}
+	}
+	if e.FactsIndex == "" || e.FindingsIndex == "" {
+		return &ValidationError{Field: "output.exporters.elasticsearch.facts_index", Reason: "index names must not be empty"}
+	}
+	if e.MaxRetries < 0 {
+		return &ValidationError{Field: "output.exporters.elasticsearch.max_retries", Reason: "must not be negative"}
+	}
+	if e.TimeoutMs <= 0 {
+		return &ValidationError{Field: "output.exporters.elasticsearch.timeout_ms", Reason: "must be positive"}
+	}
+	return nil
+}
+
+// validate checks webhook exporter settings (only when enabled)
+// Complexity: O(1)
+func (w *WebhookConfig) validate() error {
+	if !w.Enabled {
+		return nil
+	}
+	if !strings.HasPrefix(w.URL, "https://") && !(w.AllowHTTP && strings.HasPrefix(w.URL, "http://")) {
+		return &ValidationError{Field: "output.exporters.webhook.url", Reason: "must be an https:// URL"}
+	}
+	if w.PrivateKeyPath == "" {
+		return &ValidationError{Field: "output.exporters.webhook.private_key_path", Reason: "must not be empty"}
+	}
+	if w.MaxRetries < 0 {
+		return &ValidationError{Field: "output.exporters.webhook.max_retries", Reason: "must not be negative"}
+	}
+	if w.TimeoutMs <= 0 {
+		return &ValidationError{Field: "output.exporters.webhook.timeout_ms", Reason: "must be positive"}
+	}
+	return nil
+}
+
+// validate checks MQTT exporter settings (only when enabled)
+// Complexity: O(1)
+func (m *MQTTConfig) validate() error {
+	if !m.Enabled {
+		return nil
+	}
+	if m.Address == "" {
+		return &ValidationError{Field: "output.exporters.mqtt.address", Reason: "must not be empty"}
+	}
+	if m.TopicPrefix == "" || strings.ContainsAny(m.TopicPrefix, "+#") {
+		return &ValidationError{Field: "output.exporters.mqtt.topic_prefix", Reason: "must be non-empty without wildcards"}
+	}
+	if m.QoS < 0 || m.QoS > 1 {
+		return &ValidationError{Field: "output.exporters.mqtt.qos", Reason: "must be 0 or 1"}
+	}
+	if m.MaxRetries < 0 {
+		return &ValidationError{Field: "output.exporters.mqtt.max_retries", Reason: "must not be negative"}
+	}
+	if m.TimeoutMs <= 0 {
+		return &ValidationError{Field: "output.exporters.mqtt.timeout_ms", Reason: "must be positive"}
+	}
+	return nil
+}
+
+// validate checks Kafka exporter settings (only when enabled)
+// Complexity: O(|brokers|)
+func (k *KafkaConfig) validate() error {
+	if !k.Enabled {
+		return nil
+	}
+	if len(k.Brokers) == 0 {
+		return &ValidationError{Field: "output.exporters.kafka.brokers", Reason: "must list at least one broker"}
+	}
+	for _, broker := range k.Brokers {
+		if _, _, err := net.SplitHostPort(broker); err != nil {
+			return &ValidationError{Field: "output.exporters.kafka.brokers", Reason: "every broker must be host:port"}
+		}
+	}
+	if k.Topic == "" {
+		return &ValidationError{Field: "output.exporters.kafka.topic", Reason: "must not be empty"}
+	}
+	switch k.SASLMechanism {
+	case "":
+	case "plain", "scram-sha-256", "scram-sha-512":
+		if k.Username == "" {
+			return &ValidationError{Field: "output.exporters.kafka.username", Reason: "required with sasl_mechanism"}
+		}
+	default:
+		return &ValidationError{Field: "output.exporters.kafka.sasl_mechanism", Reason: "must be empty, plain, scram-sha-256 or scram-sha-512"}
+	}
+	if k.Acks != "all" && k.Acks != "leader" {
+		return &ValidationError{Field: "output.exporters.kafka.acks", Reason: "must be all or leader"}
+	}
+	if k.MaxRetries < 0 {
+		return &ValidationError{Field: "output.exporters.kafka.max_retries", Reason: "must not be negative"}
+	}
+	if k.TimeoutMs <= 0 {
+		return &ValidationError{Field: "output.exporters.kafka.timeout_ms", Reason: "must be positive"}
+	}
+	return nil
+}
+
+// validate checks SMTP exporter settings (only when enabled)
+// Complexity: O(|to|)
+func (m *SMTPConfig) validate() error {
+	if !m.Enabled {
+		return nil
+	}
+	if _, _, err := net.SplitHostPort(m.Address); err != nil {
+		return &ValidationError{Field: "output.exporters.smtp.address", Reason: "must be host:port"}
+	}
+	switch m.Security {
+	case "starttls", "tls", "none":
+	default:
+		return &ValidationError{Field: "output.exporters.smtp.security", Reason: "must be starttls, tls or none"}
+	}
+	if m.From == "" {
+		return &ValidationError{Field: "output.exporters.smtp.from", Reason: "must not be empty"}
+	}
+	if len(m.To) == 0 {
+		return &ValidationError{Field: "output.exporters.smtp.to", Reason: "must list at least one recipient"}
+	}
+	if m.MaxRetries < 0 {
+		return &ValidationError{Field: "output.exporters.smtp.max_retries", Reason: "must not be negative"}
+	}
+	if m.TimeoutMs <= 0 {
+		return &ValidationError{Field: "output.exporters.smtp.timeout_ms", Reason: "must be positive"}
+	}
+	return nil
+}
+
+// validate checks S3 upload settings (only when enabled)
+// Complexity: O(1)
+func (s *S3Config) validate() error {
+	if !s.Enabled {
+		return nil
+	}
+	if s.Bucket == "" {
+		return &ValidationError{Field: "output.upload.s3.bucket", Reason: "must not be empty"}
+	}
+	if s.Region == "" {
+		return &ValidationError{Field: "output.upload.s3.region", Reason: "must not be empty"}
+	}
+	if (s.AccessKeyID == "") != (s.SecretAccessKey == "") {
+		return &ValidationError{Field: "output.upload.s3.access_key_id", Reason: "access_key_id and secret_access_key must be set together"}
+	}
+	switch s.SSE {
+	case "", "AES256", "aws:kms":
+	default:
+		return &ValidationError{Field: "output.upload.s3.sse", Reason: "must be empty, AES256 or aws:kms"}
+	}
+	if s.KMSKeyID != "" && s.SSE != "aws:kms" {
+		return &ValidationError{Field: "output.upload.s3.kms_key_id", Reason: "requires sse: aws:kms"}
+	}
+	if s.MaxRetries < 0 {
+		return &ValidationError{Field: "output.upload.s3.max_retries", Reason: "must not be negative"}
+	}
+	if s.TimeoutMs <= 0 {
+		return &ValidationError{Field: "output.upload.s3.timeout_ms", Reason: "must be positive"}
+	}
+	return nil
+}
+
+// validate checks SFTP upload settings (only when enabled)
+// Complexity: O(1)
+func (s *SFTPConfig) validate() error {
+	if !s.Enabled {
+		return nil
+	}
+	if s.Address == "" {
+		return &ValidationError{Field: "output.upload.sftp.address", Reason: "must not be empty"}
+	}
+	if s.Username == "" {
+		return &ValidationError{Field: "output.upload.sftp.username", Reason: "must not be empty"}
+	}
+	if s.Password == "" && s.PrivateKeyPath == "" {
+		return &ValidationError{Field: "output.upload.sftp.password", Reason: "password or private_key_path required"}
+	}
+	if s.HostKey == "" {
+		return &ValidationError{Field: "output.upload.sftp.host_key", Reason: "must be pinned"}
+	}
+	if s.MaxRetries < 0 {
+		return &ValidationError{Field: "output.upload.sftp.max_retries", Reason: "must not be negative"}
+	}
+	if s.TimeoutMs <= 0 {
+		return &ValidationError{Field: "output.upload.sftp.timeout_ms", Reason: "must be positive"}
+	}
+	return nil
+}
+
+// validate checks Azure Blob upload settings (only when enabled)
+// Complexity: O(1)
+func (a *AzureBlobConfig) validate() error {
+	if !a.Enabled {
+		return nil
+	}
+	if a.Account == "" && a.Endpoint == "" {
+		return &ValidationError{Field: "output.upload.azure.account", Reason: "account or endpoint is required"}
+	}
+	if a.Container == "" {
+		return &ValidationError{Field: "output.upload.azure.container", Reason: "must not be empty"}
+	}
+	switch a.Auth {
+	case "sas":
+		if a.SASToken == "" {
+			return &ValidationError{Field: "output.upload.azure.sas_token", Reason: "required when auth is sas"}
+		}
+	case "managed_identity":
+		if a.SASToken != "" {
+			return &ValidationError{Field: "output.upload.azure.sas_token", Reason: "must be empty when auth is managed_identity"}
+		}
+	default:
+		return &ValidationError{Field: "output.upload.azure.auth", Reason: "must be sas or managed_identity"}
+	}
+	if a.MaxRetries < 0 {
+		return &ValidationError{Field: "output.upload.azure.max_retries", Reason: "must not be negative"}
+	}
+	if a.TimeoutMs <= 0 {
+		return &ValidationError{Field: "output.upload.azure.timeout_ms", Reason: "must be positive"}
+	}
+	return nil
+}
+
+// validate checks GCS upload settings (only when enabled)
+// Complexity: O(1)
+func (g *GCSConfig) validate() error {
+	if !g.Enabled {
+		return nil
+	}
+	if g.Bucket == "" {
+		return &ValidationError{Field: "output.upload.gcs.bucket", Reason: "must not be empty"}
+	}
+	if g.MaxRetries < 0 {
+		return &ValidationError{Field: "output.upload.gcs.max_retries", Reason: "must not be negative"}
+	}
+	if g.TimeoutMs <= 0 {
+		return &ValidationError{Field: "output.upload.gcs.timeout_ms", Reason: "must be positive"}
+	}
+	return nil
+}
 
+// validate checks HTTPS upload settings (only when enabled)
+// Complexity: O(1)
+func (h *HTTPSUploadConfig) validate() error {
+	if !h.Enabled {
+		return nil
+	}
+	if !strings.HasPrefix(h.URL, "https://") && !(h.AllowHTTP && strings.HasPrefix(h.URL, "http://")) {
+		return &ValidationError{Field: "output.upload.https.url", Reason: "must be an https:// URL"}
+	}
+	if h.MaxRetries < 0 {
+		return &ValidationError{Field: "output.upload.https.max_retries", Reason: "must not be negative"}
+	}
+	if h.TimeoutMs <= 0 {
+		return &ValidationError{Field: "output.upload.https.timeout_ms", Reason: "must be positive"}
+	}
 	return nil
 }
 
+// isSupportedFormat reports whether format is in SupportedFormats
+// Complexity: O(|SupportedFormats|)
+func isSupportedFormat(format string) bool {
+	for _, f := range SupportedFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
 // GetCategoryTimeout returns the timeout duration for category collection
 // Complexity: O(1)
 func (c *Config) GetCategoryTimeout() time.Duration {
 	return time.Duration(c.Collect.CategoryTimeoutMs) * time.Millisecond
 }
 
+// GetSoftwareTimeout returns the timeout duration for the software inventory,
+// security products and patch level
+// Complexity: O(1)
+func (c *Config) GetSoftwareTimeout() time.Duration {
+	return time.Duration(c.Collect.SoftwareTimeoutMs) * time.Millisecond
+}
+
 // GetPhase1Timeout returns the total timeout for Phase 1
 // Complexity: O(1)
 func (c *Config) GetPhase1Timeout() time.Duration {
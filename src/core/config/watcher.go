@@ -0,0 +1,121 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDelay lets a burst of writes (editors truncate, write and chmod in
+// separate steps) settle before the file is re-read
+const reloadDelay = 100 * time.Millisecond
+
+// Watcher reloads a config file when it changes on disk
+// Every change is re-read and validated with Load: a valid config that
+// differs from the current one replaces Current and is sent on Updates; an
+// invalid one is passed to the report function and the current config stays
+// in effect. The file's directory is watched, so replacing the file by
+// rename (Save, most editors) is seen too.
+type Watcher struct {
+	path    string
+	current atomic.Pointer[Config]
+	updates chan *Config
+	report  func(err error)
+	fsw     *fsnotify.Watcher
+}
+
+// NewWatcher starts watching path, whose contents cfg was loaded from;
+// report (optional) receives every rejected reload
+// Complexity: O(1)
+func NewWatcher(path string, cfg *Config, report func(err error)) (*Watcher, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config watcher: %w", err)
+	}
+	if err := fsw.Add(filepath.Dir(path)); err != nil {
+		fsw.Close()
+		return nil, fmt.Errorf("failed to watch config directory: %w", err)
+	}
+	w := &Watcher{path: path, updates: make(chan *Config, 1), report: report, fsw: fsw}
+	w.current.Store(cfg)
+	return w, nil
+}
+
+// Current returns the config in effect
+// Safe for concurrent use; callers must not modify the result.
+// Complexity: O(1)
+func (w *Watcher) Current() *Config {
+	return w.current.Load()
+}
+
+// Updates delivers each config swapped in by Run
+// Only the latest is buffered: a reader that falls behind skips straight to
+// the newest config.
+func (w *Watcher) Updates() <-chan *Config {
+	return w.updates
+}
+
+// Run reloads the file on change until ctx is cancelled, then stops watching
+// Complexity: O(n) per reload where n = file size
+func (w *Watcher) Run(ctx context.Context) error {
+	defer w.fsw.Close()
+
+	name := filepath.Base(w.path)
+	timer := time.NewTimer(reloadDelay)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-w.fsw.Events:
+			if !ok {
+				return nil
+			}
+			if filepath.Base(ev.Name) == name && ev.Op != fsnotify.Chmod {
+				timer.Reset(reloadDelay)
+			}
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return nil
+			}
+			w.reject(fmt.Errorf("config watcher: %w", err))
+		case <-timer.C:
+			w.reload()
+		}
+	}
+}
+
+// reload re-reads the file and publishes it when valid and changed
+func (w *Watcher) reload() {
+	cfg, err := Load(w.path)
+	if err != nil {
+		w.reject(err)
+		return
+	}
+	if reflect.DeepEqual(cfg, w.current.Load()) {
+		return
+	}
+	w.current.Store(cfg)
+	select {
+	case <-w.updates: // Drop an unread older config
+	default:
+	}
+	w.updates <- cfg
+}
+
+// reject reports a reload that left the current config in place
+func (w *Watcher) reject(err error) {
+	if w.report != nil {
+		w.report(err)
+	}
+}
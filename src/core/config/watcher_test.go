@@ -0,0 +1,69 @@
+package config_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+)
+
+// TestWatcher_Reload verifies a valid edit is swapped in and sent on
+// Updates, while an invalid one is reported and leaves Current unchanged
+func TestWatcher_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := config.Save(config.Default(), path); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rejected := make(chan error, 4)
+	w, err := config.NewWatcher(path, cfg, func(err error) { rejected <- err })
+	if err != nil {
+		t.Fatalf("NewWatcher() failed: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Run() = %v", err)
+		}
+	}()
+
+	next := config.Default()
+	next.Collect.Processes = true
+	next.Collect.CategoryTimeoutMs = 900
+	if err := config.Save(next, path); err != nil { // Replaces the file by rename
+		t.Fatal(err)
+	}
+	select {
+	case got := <-w.Updates():
+		if !got.Collect.Processes || got.Collect.CategoryTimeoutMs != 900 || w.Current() != got {
+			t.Errorf("update = %+v, Current() = %p", got.Collect, w.Current())
+		}
+	case err := <-rejected:
+		t.Fatalf("valid reload rejected: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no update after editing the config")
+	}
+
+	if err := os.WriteFile(path, []byte("collect:\n  category_timeout_ms: -1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-rejected:
+		if w.Current().Collect.CategoryTimeoutMs != 900 {
+			t.Errorf("Current() changed after rejected reload (%v)", err)
+		}
+	case got := <-w.Updates():
+		t.Fatalf("invalid config swapped in: %+v", got.Collect)
+	case <-time.After(5 * time.Second):
+		t.Fatal("invalid config not reported")
+	}
+}
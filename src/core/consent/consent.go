@@ -0,0 +1,183 @@
+// Package consent records the operator's acknowledgment before collection
+// Several customers require proof that the operator saw exactly which data
+// categories would be collected; the Record travels with the run outputs and
+// is covered by the bundle signature (bundle.Metadata.Consent).
+package consent
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/i18n"
+)
+
+// Method is how the acknowledgment was given
+type Method string
+
+const (
+	MethodInteractive   Method = "interactive"   // Typed at the consent prompt
+	MethodAssumeYes     Method = "assume_yes"    // -assume-yes on the command line
+	MethodPreauthorized Method = "preauthorized" // consent.operator for unattended runs
+)
+
+// MaxOperatorLength bounds the recorded operator name
+const MaxOperatorLength = 64
+
+// ErrDeclined is returned when the operator does not confirm
+var ErrDeclined = errors.New("consent declined by operator")
+
+// Category is one collection category shown on the banner
+type Category struct {
+	Name        string // Collection category (e.g., "pii_info")
+	Description string // What it contains, in operator terms and language
+}
+
+// Record is the operator's acknowledgment
+type Record struct {
+	Operator       string    `json:"operator"`        // Name or initials
+	AcknowledgedAt time.Time `json:"acknowledged_at"` // UTC
+	Method         Method    `json:"method"`
+	Categories     []string  `json:"categories"` // Category names shown on the banner
+}
+
+// Categories lists what a run with cfg will collect, in collection order
+// pii_info (the top-level pii setting), software_inventory
+// (collect.software_inventory), process_info (collect.processes),
+// disk_info (collect.disks), listening_ports (collect.listening_ports),
+// security_products (collect.security_products) and patch_level
+// (collect.patch_level) are optional; the other
+// categories are always collected.
+// Complexity: O(1)
+func Categories(cfg *config.Config) []Category {
+	names := []string{"system_info", "network_info", "hardware_info"}
+	if cfg.PII {
+		names = append(names, "pii_info")
+	}
+	if cfg.Collect.SoftwareInventory {
+		names = append(names, "software_inventory")
+	}
+	if cfg.Collect.Processes {
+		names = append(names, "process_info")
+	}
+	if cfg.Collect.Disks {
+		names = append(names, "disk_info")
+	}
+	if cfg.Collect.ListeningPorts {
+		names = append(names, "listening_ports")
+	}
+	if cfg.Collect.SecurityProducts {
+		names = append(names, "security_products")
+	}
+	if cfg.Collect.PatchLevel {
+		names = append(names, "patch_level")
+	}
+	cats := make([]Category, len(names))
+	for i, name := range names {
+		cats[i] = Category{name, i18n.T("consent.category." + name)}
+	}
+	return cats
+}
+
+// New creates a Record after validating the operator name
+// Complexity: O(|categories|)
+func New(operator string, method Method, cats []Category, now time.Time) (*Record, error) {
+	operator = strings.TrimSpace(operator)
+	if err := validateOperator(operator); err != nil {
+		return nil, err
+	}
+	names := make([]string, len(cats))
+	for i, c := range cats {
+		names[i] = c.Name
+	}
+	return &Record{Operator: operator, AcknowledgedAt: now.UTC(), Method: method, Categories: names}, nil
+}
+
+// validateOperator rejects empty, overlong or control-character names
+func validateOperator(operator string) error {
+	if operator == "" {
+		return i18n.Errorf("consent.operator.required")
+	}
+	if len(operator) > MaxOperatorLength {
+		return i18n.Errorf("consent.operator.too_long", MaxOperatorLength)
+	}
+	if strings.IndexFunc(operator, unicode.IsControl) >= 0 {
+		return i18n.Errorf("consent.operator.control")
+	}
+	return nil
+}
+
+// Banner renders the category list shown before collection
+func Banner(cats []Category) string {
+	var b strings.Builder
+	b.WriteString(i18n.T("consent.title") + "\n\n")
+	for _, c := range cats {
+		fmt.Fprintf(&b, "  %-14s %s\n", c.Name, c.Description)
+	}
+	b.WriteString("\n" + i18n.T("consent.authorization") + "\n")
+	b.WriteString(i18n.T("consent.recorded") + "\n\n")
+	return b.String()
+}
+
+// Prompt shows the banner on out and reads the operator's name and
+// confirmation from in; defaultOperator is used when the name is left blank
+// Returns ErrDeclined unless the operator answers "yes" (or "y"), or yes
+// in the selected language.
+// Complexity: O(|input|)
+func Prompt(in io.Reader, out io.Writer, cats []Category, defaultOperator string, now func() time.Time) (*Record, error) {
+	io.WriteString(out, Banner(cats))
+	reader := bufio.NewReader(in)
+
+	var operator string
+	for {
+		if defaultOperator != "" {
+			io.WriteString(out, i18n.T("consent.prompt.operator_default", defaultOperator))
+		} else {
+			io.WriteString(out, i18n.T("consent.prompt.operator"))
+		}
+		line, err := readLine(reader)
+		if err != nil {
+			return nil, ErrDeclined
+		}
+		if operator = line; operator == "" {
+			operator = defaultOperator
+		}
+		verr := validateOperator(operator)
+		if verr == nil {
+			break
+		}
+		fmt.Fprintf(out, "  %v\n", verr)
+	}
+
+	io.WriteString(out, i18n.T("consent.prompt.confirm"))
+	answer, err := readLine(reader)
+	if err != nil || !isYes(answer) {
+		return nil, ErrDeclined
+	}
+	return New(operator, MethodInteractive, cats, now())
+}
+
+// isYes reports whether answer confirms, in English or the selected language
+func isYes(answer string) bool {
+	answer = strings.ToLower(answer)
+	for _, yes := range append([]string{"yes", "y"}, strings.Split(i18n.T("consent.answers.yes"), ",")...) {
+		if answer == yes {
+			return true
+		}
+	}
+	return false
+}
+
+// readLine returns the next trimmed input line (io.EOF at end of input)
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
@@ -0,0 +1,114 @@
+package consent
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/i18n"
+)
+
+var fixedNow = func() time.Time { return time.Date(2026, 3, 2, 9, 30, 0, 0, time.FixedZone("EST", -5*3600)) }
+
+func TestCategories_FollowPII(t *testing.T) {
+	cfg := config.Default()
+	cfg.PII = false
+	cfg.Collect.SoftwareInventory = false
+	cfg.Collect.Disks = false
+	cfg.Collect.ListeningPorts = false
+	cfg.Collect.SecurityProducts = false
+	cfg.Collect.PatchLevel = false
+	if cats := Categories(cfg); len(cats) != 3 || cats[len(cats)-1].Name == "pii_info" {
+		t.Errorf("Categories(pii=false) = %+v", cats)
+	}
+	cfg.PII = true
+	if cats := Categories(cfg); len(cats) != 4 || cats[3].Name != "pii_info" {
+		t.Errorf("Categories(pii=true) = %+v", cats)
+	}
+}
+
+// TestCategories_FollowCollect verifies the optional collect categories are listed in collection order
+func TestCategories_FollowCollect(t *testing.T) {
+	cfg := config.Default()
+	cfg.Collect.Processes = true
+	var names []string
+	for _, c := range Categories(cfg) {
+		names = append(names, c.Name)
+	}
+	want := "system_info network_info hardware_info pii_info software_inventory process_info disk_info listening_ports security_products patch_level"
+	if got := strings.Join(names, " "); got != want {
+		t.Errorf("Categories() = %s, want %s", got, want)
+	}
+}
+
+func TestPrompt_Accepts(t *testing.T) {
+	cats := Categories(config.Default())
+	var out strings.Builder
+	record, err := Prompt(strings.NewReader("\n  JD \nYES\n"), &out, cats, "", fixedNow)
+	if err != nil {
+		t.Fatalf("Prompt() failed: %v", err)
+	}
+	if record.Operator != "JD" || record.Method != MethodInteractive {
+		t.Errorf("Prompt() = %+v", record)
+	}
+	if !record.AcknowledgedAt.Equal(fixedNow()) || record.AcknowledgedAt.Location() != time.UTC {
+		t.Errorf("AcknowledgedAt = %v, want UTC %v", record.AcknowledgedAt, fixedNow().UTC())
+	}
+	if len(record.Categories) != len(cats) {
+		t.Errorf("Categories = %v", record.Categories)
+	}
+	// The blank first answer is rejected before JD is accepted
+	if !strings.Contains(out.String(), "pii_info") || !strings.Contains(out.String(), "operator name or initials required") {
+		t.Errorf("banner output = %q", out.String())
+	}
+}
+
+func TestPrompt_DefaultOperator(t *testing.T) {
+	record, err := Prompt(strings.NewReader("\ny\n"), &strings.Builder{}, nil, "Field Team", fixedNow)
+	if err != nil || record.Operator != "Field Team" {
+		t.Errorf("Prompt() = %+v, %v", record, err)
+	}
+}
+
+func TestPrompt_Localized(t *testing.T) {
+	defer i18n.Use(i18n.Language())
+	i18n.Use("es")
+
+	var out strings.Builder
+	record, err := Prompt(strings.NewReader("JD\nSí\n"), &out, Categories(config.Default()), "", fixedNow)
+	if err != nil || record.Operator != "JD" {
+		t.Fatalf("Prompt() = %+v, %v", record, err)
+	}
+	if !strings.Contains(out.String(), "MiniBeast recolectará") || !strings.Contains(out.String(), "zona horaria") {
+		t.Errorf("banner output = %q", out.String())
+	}
+	// Categories are recorded by name, not by their translated description
+	if record.Categories[0] != "system_info" {
+		t.Errorf("Categories = %v", record.Categories)
+	}
+}
+
+func TestPrompt_Declines(t *testing.T) {
+	for name, input := range map[string]string{
+		"no":          "JD\nno\n",
+		"eof":         "JD\n",
+		"no operator": "",
+	} {
+		if _, err := Prompt(strings.NewReader(input), &strings.Builder{}, nil, "", fixedNow); !errors.Is(err, ErrDeclined) {
+			t.Errorf("%s: Prompt() error = %v, want ErrDeclined", name, err)
+		}
+	}
+}
+
+func TestNew_ValidatesOperator(t *testing.T) {
+	for _, op := range []string{"", "   ", strings.Repeat("x", MaxOperatorLength+1), "JD\x1b[2J"} {
+		if _, err := New(op, MethodAssumeYes, nil, fixedNow()); err == nil {
+			t.Errorf("New(%q) should fail", op)
+		}
+	}
+	if r, err := New("JD", MethodPreauthorized, nil, fixedNow()); err != nil || r.Method != MethodPreauthorized {
+		t.Errorf("New() = %+v, %v", r, err)
+	}
+}
@@ -0,0 +1,164 @@
+// Package crash turns panics into errors and records them in a crash report
+// A panic in one collection category or in inference must not lose what
+// the rest of the run produced: the panic is recovered where it happens,
+// carried up as a *PanicError and written to "<run>.crash.json" next to the
+// run's artifacts. Faults inside C code (e.g., a segfault in llama.cpp)
+// are not Go panics and still terminate the process.
+package crash
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+	coreio "github.com/minibeast/usb-agent/src/core/io"
+	"gopkg.in/yaml.v3"
+)
+
+// Format identifies the crash report layout
+const Format = "minibeast-crash/1"
+
+// Suffix is the crash report name after the run's base name
+const Suffix = ".crash.json"
+
+// PanicError is a recovered panic
+type PanicError struct {
+	Where string // What was running (e.g., "collect.pii_info", "inference")
+	Value any    // Value passed to panic
+	Stack []byte // Goroutine stack at the panic
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic in %s: %v", e.Where, e.Value)
+}
+
+// Recover converts the value of recover() into a *PanicError (nil when v is nil)
+// Called from the deferred function, it captures the panicking goroutine's stack.
+// Complexity: O(stack depth)
+func Recover(v any, where string) *PanicError {
+	if v == nil {
+		return nil
+	}
+	return &PanicError{Where: where, Value: v, Stack: debug.Stack()}
+}
+
+// All returns every *PanicError in err's tree, in order
+// Complexity: O(|tree|)
+func All(err error) []*PanicError {
+	if err == nil {
+		return nil
+	}
+	if p, ok := err.(*PanicError); ok {
+		return []*PanicError{p}
+	}
+	var found []*PanicError
+	switch u := err.(type) {
+	case interface{ Unwrap() []error }:
+		for _, e := range u.Unwrap() {
+			found = append(found, All(e)...)
+		}
+	case interface{ Unwrap() error }:
+		found = All(u.Unwrap())
+	}
+	return found
+}
+
+// Platform describes where the agent was running
+type Platform struct {
+	OS              string `json:"os"`
+	Arch            string `json:"arch"`
+	GoVersion       string `json:"go_version"`
+	NumCPU          int    `json:"num_cpu"`
+	NativeInference bool   `json:"native_inference"` // llama.cpp build
+}
+
+// CurrentPlatform describes the running process
+func CurrentPlatform(nativeInference bool) Platform {
+	return Platform{
+		OS:              runtime.GOOS,
+		Arch:            runtime.GOARCH,
+		GoVersion:       runtime.Version(),
+		NumCPU:          runtime.NumCPU(),
+		NativeInference: nativeInference,
+	}
+}
+
+// Panic is one recovered panic in a report
+type Panic struct {
+	Where string `json:"where"`
+	Value string `json:"value"`
+	Stack string `json:"stack"`
+}
+
+// State is how far the run got before it crashed
+type State struct {
+	Stage            string   `json:"stage,omitempty"`             // Last pipeline stage started
+	FactsCollected   bool     `json:"facts_collected"`             // Facts survived the crash
+	FailedCategories []string `json:"failed_categories,omitempty"` // Including panicked ones
+	ReportGenerated  bool     `json:"report_generated"`
+	Written          []string `json:"written,omitempty"` // Artifacts written before the report
+}
+
+// Report is the content of a crash report
+type Report struct {
+	Format       string    `json:"format"`
+	RunID        string    `json:"run_id"`
+	Time         time.Time `json:"time"`
+	Panics       []Panic   `json:"panics"`
+	ConfigSHA256 string    `json:"config_sha256"` // Of the effective config, re-encoded as YAML
+	Platform     Platform  `json:"platform"`
+	State        State     `json:"state"`
+}
+
+// NewReport builds a report for the panics in err
+// Complexity: O(|config| + |stacks|)
+func NewReport(runID string, err error, cfg *config.Config, platform Platform, state State, now time.Time) *Report {
+	r := &Report{
+		Format:       Format,
+		RunID:        runID,
+		Time:         now.UTC(),
+		Panics:       []Panic{},
+		ConfigSHA256: ConfigDigest(cfg),
+		Platform:     platform,
+		State:        state,
+	}
+	for _, p := range All(err) {
+		r.Panics = append(r.Panics, Panic{Where: p.Where, Value: fmt.Sprint(p.Value), Stack: string(p.Stack)})
+	}
+	return r
+}
+
+// Write writes the report to dir as "<base>.crash.json"
+// Complexity: O(|report|)
+func (r *Report) Write(dir, base string) (string, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal crash report: %w", err)
+	}
+	path := filepath.Join(dir, base+Suffix)
+	if err := coreio.NewWriter().WriteBinary(path, data); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// ConfigDigest is the hex SHA-256 of cfg re-encoded as YAML ("" for nil)
+// Two runs with the same effective config share a digest without the
+// report exposing settings such as upload credentials.
+func ConfigDigest(cfg *config.Config) string {
+	if cfg == nil {
+		return ""
+	}
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,92 @@
+package crash
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+)
+
+// panicking returns the error Recover produces for a panic in f
+func panicking(f func()) (err error) {
+	defer func() {
+		if pe := Recover(recover(), "inference"); pe != nil {
+			err = pe
+		}
+	}()
+	f()
+	return nil
+}
+
+func TestRecover(t *testing.T) {
+	if Recover(nil, "x") != nil {
+		t.Error("Recover(nil) != nil")
+	}
+	err := panicking(func() { panic("model exploded") })
+	var pe *PanicError
+	if !errors.As(err, &pe) || pe.Value != "model exploded" {
+		t.Fatalf("err = %v", err)
+	}
+	if !strings.Contains(string(pe.Stack), "TestRecover") {
+		t.Errorf("stack does not include the panicking caller:\n%s", pe.Stack)
+	}
+	if err.Error() != "panic in inference: model exploded" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+}
+
+func TestAll(t *testing.T) {
+	a := &PanicError{Where: "collect.pii_info", Value: 1}
+	b := &PanicError{Where: "inference", Value: 2}
+	err := errors.Join(a, fmt.Errorf("model: %w", errors.New("parse")), fmt.Errorf("wrapped: %w", b))
+	got := All(err)
+	if len(got) != 2 || got[0] != a || got[1] != b {
+		t.Errorf("All() = %v", got)
+	}
+	if All(errors.New("plain")) != nil || All(nil) != nil {
+		t.Error("All() found panics in plain errors")
+	}
+}
+
+func TestReport_Write(t *testing.T) {
+	cfg := config.Default()
+	err := panicking(func() { panic("boom") })
+	state := State{Stage: "inference.generate", FactsCollected: true, Written: []string{"out/host.json"}}
+	r := NewReport("01JPAX1Z5C8K2M3N4P5Q6R7S8T", err, cfg, CurrentPlatform(false), state, time.Now())
+
+	dir := t.TempDir()
+	path, werr := r.Write(dir, "host_20260301T120000Z")
+	if werr != nil {
+		t.Fatal(werr)
+	}
+	if filepath.Base(path) != "host_20260301T120000Z"+Suffix {
+		t.Errorf("path = %s", path)
+	}
+	data, rerr := os.ReadFile(path)
+	if rerr != nil {
+		t.Fatal(rerr)
+	}
+	var got Report
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Format != Format || got.RunID != "01JPAX1Z5C8K2M3N4P5Q6R7S8T" || len(got.Panics) != 1 || got.Panics[0].Value != "boom" || got.Panics[0].Stack == "" {
+		t.Errorf("report = %+v", got)
+	}
+	if got.ConfigSHA256 != ConfigDigest(cfg) || len(got.ConfigSHA256) != 64 || got.State.Stage != "inference.generate" {
+		t.Errorf("report = %+v", got)
+	}
+
+	// Digest tracks the effective config
+	other := config.Default()
+	other.PII = !other.PII
+	if ConfigDigest(other) == ConfigDigest(cfg) {
+		t.Error("different configs share a digest")
+	}
+}
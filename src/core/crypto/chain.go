@@ -0,0 +1,353 @@
+package crypto
+
+import (
+	stdcrypto "crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Chain signature algorithms (ArtifactSignature.Algorithm)
+const (
+	AlgorithmEd25519   = "ed25519"           // Ed25519 over SHA-256(header); "" in older files
+	AlgorithmECDSAP256 = "ecdsa-p256-sha256" // ECDSA P-256 over SHA-256(header), ASN.1 DER (hardware keys)
+)
+
+// ChainFormat identifies the artifact signature layout
+const ChainFormat = "minibeast-sig/1"
+
+// ArtifactSignatureSuffix is appended to an artifact's name for its signature
+const ArtifactSignatureSuffix = ".mbsig"
+
+// Header is the canonical header an artifact signature covers
+// Besides the artifact's digest it names who signed it (KeyFingerprint),
+// where and when; Prev links it to the artifact signed before it by the same
+// ChainSigner, so removing an artifact from the middle of a run breaks the
+// chain.
+type Header struct {
+	Version        string    `json:"version"`         // Agent version
+	Timestamp      time.Time `json:"timestamp"`       // Signing time (UTC)
+	Hostname       string    `json:"hostname"`        // Machine the artifact was produced on
+	KeyFingerprint string    `json:"key_fingerprint"` // Fingerprint of the signing key
+	Name           string    `json:"name"`            // Artifact file name
+	Size           int64     `json:"size"`
+	SHA256         string    `json:"sha256"` // Hex digest of the artifact
+	Seq            int       `json:"seq"`    // Position in the chain, from 0
+	Prev           string    `json:"prev"`   // Digest of the previous header; empty for Seq 0
+}
+
+// ArtifactSignature is the content of "FILE.mbsig"
+type ArtifactSignature struct {
+	Format    string `json:"format"`
+	Algorithm string `json:"algorithm,omitempty"` // "" = AlgorithmEd25519
+	Header    Header `json:"header"`
+	PublicKey []byte `json:"public_key"` // Raw Ed25519 or PKIX P-256 key, base64 in JSON
+	Signature []byte `json:"signature"`  // Over Header.Canonical(), base64 in JSON
+}
+
+// Fingerprint returns the hex SHA-256 of an encoded public key (raw Ed25519
+// or PKIX P-256, as ArtifactSignature embeds it)
+// Complexity: O(|pub|)
+func Fingerprint(pub []byte) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:])
+}
+
+// Canonical returns the signed encoding of h: one "key=value" line per field
+// in a fixed order, independent of JSON formatting
+// Complexity: O(|h|)
+func (h *Header) Canonical() []byte {
+	var b strings.Builder
+	b.WriteString(ChainFormat + "\n")
+	field := func(key, value string) {
+		b.WriteString(key + "=" + strconv.Quote(value) + "\n")
+	}
+	field("version", h.Version)
+	field("timestamp", h.Timestamp.UTC().Format(time.RFC3339Nano))
+	field("hostname", h.Hostname)
+	field("key_fingerprint", h.KeyFingerprint)
+	field("name", h.Name)
+	field("size", strconv.FormatInt(h.Size, 10))
+	field("sha256", h.SHA256)
+	field("seq", strconv.Itoa(h.Seq))
+	field("prev", h.Prev)
+	return []byte(b.String())
+}
+
+// Digest returns the hex SHA-256 of the canonical header, the next link's Prev
+// Complexity: O(|h|)
+func (h *Header) Digest() string {
+	sum := sha256.Sum256(h.Canonical())
+	return hex.EncodeToString(sum[:])
+}
+
+// ChainSigner signs the artifacts of one run into a hash chain; safe for
+// concurrent use
+type ChainSigner struct {
+	sign      func(header []byte) ([]byte, error)
+	algorithm string
+	public    []byte
+	version   string
+	hostname  string
+	now       func() time.Time
+
+	mu   sync.Mutex
+	seq  int
+	prev string
+}
+
+// NewChainSigner creates a chain signed with keyPair
+// A nil keyPair signs with a fresh key, which proves integrity but not origin.
+// Complexity: O(1)
+func NewChainSigner(keyPair *KeyPair, version, hostname string, now func() time.Time) (*ChainSigner, error) {
+	if keyPair == nil {
+		var err error
+		if keyPair, err = GenerateKeyPair(); err != nil {
+			return nil, err
+		}
+	}
+	if now == nil {
+		now = time.Now
+	}
+	signer := NewSigner(keyPair)
+	return &ChainSigner{
+		sign: func(header []byte) ([]byte, error) {
+			return signer.Sign(header)
+		},
+		algorithm: AlgorithmEd25519,
+		public:    keyPair.PublicKey,
+		version:   version,
+		hostname:  hostname,
+		now:       now,
+	}, nil
+}
+
+// NewKeyChainSigner creates a chain signed with a P-256 key that may never
+// leave its device, such as a HardwareKey
+// Complexity: O(1)
+func NewKeyChainSigner(key stdcrypto.Signer, version, hostname string, now func() time.Time) (*ChainSigner, error) {
+	if _, ok := key.Public().(*ecdsa.PublicKey); !ok {
+		return nil, fmt.Errorf("chain signing key must be P-256")
+	}
+	der, err := EncodePublicKey(key.Public())
+	if err != nil {
+		return nil, err
+	}
+	if now == nil {
+		now = time.Now
+	}
+	return &ChainSigner{
+		sign: func(header []byte) ([]byte, error) {
+			digest := sha256.Sum256(header)
+			return key.Sign(rand.Reader, digest[:], stdcrypto.SHA256)
+		},
+		algorithm: AlgorithmECDSAP256,
+		public:    der,
+		version:   version,
+		hostname:  hostname,
+		now:       now,
+	}, nil
+}
+
+// PublicKey returns the key the chain is signed with, encoded as in
+// ArtifactSignature.PublicKey
+func (c *ChainSigner) PublicKey() []byte {
+	return c.public
+}
+
+// SignArtifact hashes the file at path and writes "path.mbsig" linking it to
+// the previously signed artifact
+// Returns the signature's path.
+// Complexity: O(n) where n = file size
+func (c *ChainSigner) SignArtifact(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	digest, err := fileSHA256(path)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	header := Header{
+		Version:        c.version,
+		Timestamp:      c.now().UTC(),
+		Hostname:       c.hostname,
+		KeyFingerprint: Fingerprint(c.public),
+		Name:           filepath.Base(path),
+		Size:           info.Size(),
+		SHA256:         hex.EncodeToString(digest[:]),
+		Seq:            c.seq,
+		Prev:           c.prev,
+	}
+	sig, err := c.sign(header.Canonical())
+	if err != nil {
+		return "", err
+	}
+	algorithm := c.algorithm
+	if algorithm == AlgorithmEd25519 {
+		algorithm = "" // Readable by verifiers predating the field
+	}
+	data, err := json.MarshalIndent(&ArtifactSignature{
+		Format:    ChainFormat,
+		Algorithm: algorithm,
+		Header:    header,
+		PublicKey: c.public,
+		Signature: sig,
+	}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal signature: %w", err)
+	}
+
+	sigPath := path + ArtifactSignatureSuffix
+	tempPath := sigPath + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write temp signature: %w", err)
+	}
+	if err := os.Rename(tempPath, sigPath); err != nil {
+		os.Remove(tempPath)
+		return "", fmt.Errorf("failed to rename signature: %w", err)
+	}
+	c.seq++
+	c.prev = header.Digest()
+	return sigPath, nil
+}
+
+// VerifyBundle checks every artifact signature below dir in one call
+// Each signature must be made by its embedded key, whose fingerprint must
+// match the header and, when trustedKeys (encoded as LoadVerifyingKey
+// returns them) is non-empty, one of trustedKeys;
+// the artifact's size and SHA-256 must match, and every header but a chain's
+// first must link to another header in dir. With trustedKeys empty the
+// embedded keys are used, which proves the files are intact but not who
+// signed them. Returns the verified headers sorted by path; every failure is
+// reported in the error.
+// Complexity: O(total size of the signed files)
+func VerifyBundle(dir string, trustedKeys [][]byte) ([]Header, error) {
+	trusted := map[string]bool{}
+	for _, k := range trustedKeys {
+		trusted[Fingerprint(k)] = true
+	}
+
+	var sigPaths []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.HasSuffix(path, ArtifactSignatureSuffix) {
+			sigPaths = append(sigPaths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(sigPaths) == 0 {
+		return nil, fmt.Errorf("no %s signatures in %s", ArtifactSignatureSuffix, dir)
+	}
+	sort.Strings(sigPaths)
+
+	var headers []Header
+	var bad []string
+	digests := map[string]bool{}
+	for _, sigPath := range sigPaths {
+		header, err := verifyArtifact(sigPath, trusted)
+		rel, _ := filepath.Rel(dir, strings.TrimSuffix(sigPath, ArtifactSignatureSuffix))
+		if err != nil {
+			bad = append(bad, filepath.ToSlash(rel)+" ("+err.Error()+")")
+			continue
+		}
+		headers = append(headers, *header)
+		digests[header.Digest()] = true
+	}
+	for _, h := range headers {
+		if (h.Seq == 0) != (h.Prev == "") || (h.Prev != "" && !digests[h.Prev]) {
+			bad = append(bad, h.Name+" (chain broken: previous artifact missing)")
+		}
+	}
+	if len(bad) > 0 {
+		return headers, fmt.Errorf("%d of %d artifacts do not verify: %s", len(bad), len(sigPaths), strings.Join(bad, ", "))
+	}
+	return headers, nil
+}
+
+// verifyArtifact checks one signature file and the artifact next to it
+func verifyArtifact(sigPath string, trusted map[string]bool) (*Header, error) {
+	data, err := os.ReadFile(sigPath)
+	if err != nil {
+		return nil, err
+	}
+	var as ArtifactSignature
+	if err := json.Unmarshal(data, &as); err != nil {
+		return nil, fmt.Errorf("invalid signature file: %w", err)
+	}
+	if as.Format != ChainFormat {
+		return nil, fmt.Errorf("unsupported signature format %q", as.Format)
+	}
+	fingerprint := Fingerprint(as.PublicKey)
+	if fingerprint != as.Header.KeyFingerprint {
+		return nil, fmt.Errorf("key fingerprint does not match the embedded key")
+	}
+	if len(trusted) > 0 && !trusted[fingerprint] {
+		return nil, fmt.Errorf("signed by untrusted key %s", fingerprint)
+	}
+	if err := verifyHeader(&as); err != nil {
+		return nil, err
+	}
+
+	path := strings.TrimSuffix(sigPath, ArtifactSignatureSuffix)
+	if filepath.Base(path) != as.Header.Name {
+		return nil, fmt.Errorf("signature is for %s", as.Header.Name)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("missing")
+	}
+	digest, err := fileSHA256(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() != as.Header.Size || hex.EncodeToString(digest[:]) != as.Header.SHA256 {
+		return nil, fmt.Errorf("modified")
+	}
+	return &as.Header, nil
+}
+
+// verifyHeader checks the header signature by the embedded key
+func verifyHeader(as *ArtifactSignature) error {
+	var ok bool
+	switch as.Algorithm {
+	case "", AlgorithmEd25519:
+		if len(as.PublicKey) != PublicKeySize {
+			return fmt.Errorf("invalid embedded public key")
+		}
+		ok = Verify(ed25519.PublicKey(as.PublicKey), as.Header.Canonical(), as.Signature)
+	case AlgorithmECDSAP256:
+		pub, err := parseP256(as.PublicKey)
+		if err != nil {
+			return fmt.Errorf("invalid embedded public key")
+		}
+		digest := sha256.Sum256(as.Header.Canonical())
+		ok = ecdsa.VerifyASN1(pub, digest[:], as.Signature)
+	default:
+		return fmt.Errorf("unsupported signature algorithm %q", as.Algorithm)
+	}
+	if !ok {
+		return fmt.Errorf("signature does not verify")
+	}
+	return nil
+}
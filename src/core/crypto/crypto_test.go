@@ -1,12 +1,23 @@
 package crypto_test
 
 import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/ecdsa"
 	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/minibeast/usb-agent/src/core/crypto"
+	coreio "github.com/minibeast/usb-agent/src/core/io"
 )
 
 // TestGenerateKeyPair verifies key pair generation
@@ -67,6 +78,54 @@ func TestSaveLoadPrivateKey(t *testing.T) {
 	}
 }
 
+// TestSaveLoadPrivateKeyWithPassphrase verifies protected keys need the
+// passphrase and plain keys still load through the same call
+func TestSaveLoadPrivateKeyWithPassphrase(t *testing.T) {
+	tmpDir := t.TempDir()
+	keyPath := filepath.Join(tmpDir, "signing.pem")
+	keyPair, err := crypto.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := crypto.SavePrivateKeyWithPassphrase(keyPair.PrivateKey, keyPath, ""); err == nil {
+		t.Error("Empty passphrase accepted")
+	}
+	if err := crypto.SavePrivateKeyWithPassphrase(keyPair.PrivateKey, keyPath, "correct horse"); err != nil {
+		t.Fatalf("SavePrivateKeyWithPassphrase() failed: %v", err)
+	}
+	if info, err := os.Stat(keyPath); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Key file = %v, %v; want mode 0600", info, err)
+	}
+	data, _ := os.ReadFile(keyPath)
+	if bytes.Contains(data, []byte(keyPair.PrivateKey[:8])) {
+		t.Error("Key file holds the raw key")
+	}
+
+	if _, err := crypto.LoadPrivateKey(keyPath); !errors.Is(err, crypto.ErrKeyEncrypted) {
+		t.Errorf("LoadPrivateKey() = %v, want ErrKeyEncrypted", err)
+	}
+	if _, err := crypto.LoadPrivateKeyWithPassphrase(keyPath, ""); !errors.Is(err, crypto.ErrKeyEncrypted) {
+		t.Errorf("No passphrase: %v, want ErrKeyEncrypted", err)
+	}
+	if _, err := crypto.LoadPrivateKeyWithPassphrase(keyPath, "wrong"); !errors.Is(err, crypto.ErrKeyPassphrase) {
+		t.Errorf("Wrong passphrase: %v, want ErrKeyPassphrase", err)
+	}
+	loaded, err := crypto.LoadPrivateKeyWithPassphrase(keyPath, "correct horse")
+	if err != nil || !keyPair.PrivateKey.Equal(loaded) {
+		t.Errorf("LoadPrivateKeyWithPassphrase() = %v; key matches: %v", err, keyPair.PrivateKey.Equal(loaded))
+	}
+
+	plainPath := filepath.Join(tmpDir, "plain.pem")
+	if err := crypto.SavePrivateKey(keyPair.PrivateKey, plainPath); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(crypto.PassphraseEnv, "ignored")
+	if loaded, err := crypto.LoadPrivateKeyFromEnv(plainPath); err != nil || !keyPair.PrivateKey.Equal(loaded) {
+		t.Errorf("Plain key through LoadPrivateKeyFromEnv() = %v", err)
+	}
+}
+
 // TestSaveLoadPublicKey verifies public key persistence
 func TestSaveLoadPublicKey(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -224,6 +283,38 @@ func TestVerifyFile(t *testing.T) {
 	}
 }
 
+// TestSignStream verifies signing while writing matches Sign and SignFile
+func TestSignStream(t *testing.T) {
+	keyPair, err := crypto.GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("GenerateKeyPair() failed: %v", err)
+	}
+	signer := crypto.NewSigner(keyPair)
+	data := []byte("facts.json contents")
+	path := filepath.Join(t.TempDir(), "facts.json")
+
+	stream := signer.NewStream()
+	written, err := coreio.NewWriter().WriteStream(path, bytes.NewReader(data), 0644, stream)
+	if err != nil {
+		t.Fatalf("WriteStream() failed: %v", err)
+	}
+	streamed, err := stream.Sign()
+	if err != nil {
+		t.Fatalf("SignStream.Sign() failed: %v", err)
+	}
+	fromDigest, err := signer.SignDigest(written.SHA256)
+	if err != nil {
+		t.Fatalf("SignDigest() failed: %v", err)
+	}
+	direct, _ := signer.Sign(data)
+	fromFile, _ := signer.SignFile(path)
+	for name, sig := range map[string]crypto.Signature{"stream": streamed, "digest": fromDigest, "file": fromFile} {
+		if !bytes.Equal(sig, direct) {
+			t.Errorf("%s signature differs from Sign()", name)
+		}
+	}
+}
+
 // TestSaveLoadSignature verifies signature persistence
 func TestSaveLoadSignature(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -315,3 +406,315 @@ func TestSaveSignature_InvalidSize(t *testing.T) {
 		t.Error("SaveSignature() should fail for invalid signature size")
 	}
 }
+
+// TestSignMinisign verifies the minisign round trip, tampering and the
+// detached helpers accepting either format
+func TestSignMinisign(t *testing.T) {
+	keyPair, _ := crypto.GenerateKeyPair()
+	other, _ := crypto.GenerateKeyPair()
+	signer := crypto.NewSigner(keyPair)
+	data := []byte("minibeast facts")
+
+	sigFile, err := signer.SignDetached(data, crypto.SignatureFormatMinisign, crypto.MinisignComment("host.json", "run-1"))
+	if err != nil {
+		t.Fatalf("SignDetached() failed: %v", err)
+	}
+	lines := strings.Split(string(sigFile), "\n")
+	if len(lines) != 5 || !strings.HasPrefix(lines[0], "untrusted comment: ") || lines[2] != "trusted comment: file:host.json\trun:run-1" {
+		t.Fatalf("signature file = %q", sigFile)
+	}
+	if comment, err := crypto.VerifyMinisign(keyPair.PublicKey, data, sigFile); err != nil || comment != "file:host.json\trun:run-1" {
+		t.Errorf("VerifyMinisign() = %q, %v", comment, err)
+	}
+	if _, err := crypto.VerifyMinisign(keyPair.PublicKey, []byte("other"), sigFile); err == nil {
+		t.Error("VerifyMinisign() accepted modified data")
+	}
+	if _, err := crypto.VerifyMinisign(other.PublicKey, data, sigFile); err == nil {
+		t.Error("VerifyMinisign() accepted another key")
+	}
+	forged := bytes.Replace(sigFile, []byte("run:run-1"), []byte("run:run-2"), 1)
+	if _, err := crypto.VerifyMinisign(keyPair.PublicKey, data, forged); err == nil {
+		t.Error("VerifyMinisign() accepted a modified trusted comment")
+	}
+	if _, err := signer.SignMinisign(data, "two\nlines"); err == nil {
+		t.Error("SignMinisign() accepted a multi-line comment")
+	}
+
+	raw, _ := signer.SignDetached(data, crypto.SignatureFormatRaw, "")
+	for name, sig := range map[string][]byte{"raw": raw, "minisign": sigFile} {
+		if !crypto.VerifyDetached(keyPair.PublicKey, data, sig) {
+			t.Errorf("VerifyDetached(%s) = false", name)
+		}
+		path := filepath.Join(t.TempDir(), "out.sig")
+		if err := crypto.SaveDetached(sig, path); err != nil {
+			t.Fatalf("SaveDetached(%s) failed: %v", name, err)
+		}
+		if got, _ := os.ReadFile(path); !bytes.Equal(got, sig) {
+			t.Errorf("SaveDetached(%s) wrote %q", name, got)
+		}
+	}
+
+	id := crypto.MinisignKeyID(keyPair.PublicKey)
+	pub := strings.Split(string(crypto.EncodeMinisignPublicKey(keyPair.PublicKey)), "\n")
+	blob, err := base64.StdEncoding.DecodeString(pub[1])
+	if err != nil || string(blob[:2]) != "Ed" || !bytes.Equal(blob[2:10], id[:]) || !bytes.Equal(blob[10:], keyPair.PublicKey) {
+		t.Errorf("EncodeMinisignPublicKey() = %q", pub)
+	}
+}
+
+// TestEnvelope_MultiRecipient verifies every recipient can decrypt and others cannot
+func TestEnvelope_MultiRecipient(t *testing.T) {
+	var privs []*ecdh.PrivateKey
+	var pubs []*ecdh.PublicKey
+	for i := 0; i < 3; i++ { // customer, SOC, escrow
+		key, err := crypto.GenerateRecipientKey()
+		if err != nil {
+			t.Fatalf("GenerateRecipientKey() failed: %v", err)
+		}
+		privs = append(privs, key)
+		pubs = append(pubs, key.PublicKey())
+	}
+
+	plaintext := []byte(`{"hostname":"test-host"}`)
+	envelope, err := crypto.Encrypt(plaintext, pubs)
+	if err != nil {
+		t.Fatalf("Encrypt() failed: %v", err)
+	}
+
+	for i, priv := range privs {
+		got, err := crypto.Decrypt(envelope, priv)
+		if err != nil {
+			t.Fatalf("Decrypt() recipient %d failed: %v", i, err)
+		}
+		if string(got) != string(plaintext) {
+			t.Errorf("Recipient %d got %q", i, got)
+		}
+	}
+
+	ids, err := crypto.EnvelopeRecipients(envelope)
+	if err != nil || len(ids) != 3 || ids[1] != crypto.RecipientKeyID(pubs[1]) {
+		t.Errorf("EnvelopeRecipients() = %v, %v", ids, err)
+	}
+
+	outsider, _ := crypto.GenerateRecipientKey()
+	if _, err := crypto.Decrypt(envelope, outsider); !errors.Is(err, crypto.ErrNotRecipient) {
+		t.Errorf("Outsider Decrypt() = %v, want ErrNotRecipient", err)
+	}
+}
+
+// TestEnvelope_Tampering verifies header and payload are authenticated
+func TestEnvelope_Tampering(t *testing.T) {
+	key, _ := crypto.GenerateRecipientKey()
+	other, _ := crypto.GenerateRecipientKey()
+	envelope, err := crypto.Encrypt([]byte("secret"), []*ecdh.PublicKey{key.PublicKey(), other.PublicKey()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Flip a bit in the other recipient's slot (header is covered by the AAD)
+	header := append([]byte{}, envelope...)
+	header[6+112+40] ^= 1
+	if _, err := crypto.Decrypt(header, key); err == nil {
+		t.Error("Modified header should not decrypt")
+	}
+
+	payload := append([]byte{}, envelope...)
+	payload[len(payload)-1] ^= 1
+	if _, err := crypto.Decrypt(payload, key); err == nil {
+		t.Error("Modified ciphertext should not decrypt")
+	}
+
+	if _, err := crypto.Encrypt([]byte("x"), []*ecdh.PublicKey{key.PublicKey(), key.PublicKey()}); err == nil {
+		t.Error("Duplicate recipients should be rejected")
+	}
+	if _, err := crypto.Encrypt([]byte("x"), nil); err == nil {
+		t.Error("Empty recipient list should be rejected")
+	}
+}
+
+// TestSaveLoadRecipientKeys verifies X25519 key persistence
+func TestSaveLoadRecipientKeys(t *testing.T) {
+	tmpDir := t.TempDir()
+	key, _ := crypto.GenerateRecipientKey()
+
+	privPath := filepath.Join(tmpDir, "soc.key")
+	pubPath := filepath.Join(tmpDir, "soc.pub")
+	if err := crypto.SaveRecipientPrivateKey(key, privPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := crypto.SaveRecipientPublicKey(key.PublicKey(), pubPath); err != nil {
+		t.Fatal(err)
+	}
+
+	loadedPriv, err := crypto.LoadRecipientPrivateKey(privPath)
+	if err != nil || !loadedPriv.Equal(key) {
+		t.Errorf("LoadRecipientPrivateKey() = %v", err)
+	}
+	loadedPub, err := crypto.LoadRecipientPublicKey(pubPath)
+	if err != nil || !loadedPub.Equal(key.PublicKey()) {
+		t.Errorf("LoadRecipientPublicKey() = %v", err)
+	}
+	if info, _ := os.Stat(privPath); info.Mode().Perm() != 0600 {
+		t.Errorf("Private key permissions = %v, want 0600", info.Mode().Perm())
+	}
+
+	// Ed25519 signing keys are not envelope keys
+	kp, _ := crypto.GenerateKeyPair()
+	edPath := filepath.Join(tmpDir, "signing.pub")
+	crypto.SavePublicKey(kp.PublicKey, edPath)
+	if _, err := crypto.LoadRecipientPublicKey(edPath); err == nil {
+		t.Error("Ed25519 public key should be rejected")
+	}
+}
+
+// chainTime stamps the test chains
+func chainTime() time.Time {
+	return time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+}
+
+// signChain writes names under a fresh directory and signs them in order
+func signChain(t *testing.T, keyPair *crypto.KeyPair, names ...string) string {
+	t.Helper()
+	chain, err := crypto.NewChainSigner(keyPair, "1.2.3", "ws-01", chainTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signWith(t, chain, names...)
+}
+
+// signWith writes names under a fresh directory and signs them with chain
+func signWith(t *testing.T, chain *crypto.ChainSigner, names ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("content of "+name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := chain.SignArtifact(path); err != nil {
+			t.Fatalf("SignArtifact(%s) failed: %v", name, err)
+		}
+	}
+	return dir
+}
+
+// TestVerifyBundle verifies chained artifact signatures and their headers
+func TestVerifyBundle(t *testing.T) {
+	keyPair, _ := crypto.GenerateKeyPair()
+	dir := signChain(t, keyPair, "host.json", "host.users.csv", "host.audit.json")
+
+	headers, err := crypto.VerifyBundle(dir, [][]byte{keyPair.PublicKey})
+	if err != nil {
+		t.Fatalf("VerifyBundle() failed: %v", err)
+	}
+	if len(headers) != 3 {
+		t.Fatalf("VerifyBundle() = %d headers, want 3", len(headers))
+	}
+	h := headers[0] // host.audit.json, sorted by path
+	if h.Name != "host.audit.json" || h.Seq != 2 || h.Version != "1.2.3" || h.Hostname != "ws-01" ||
+		h.KeyFingerprint != crypto.Fingerprint(keyPair.PublicKey) || h.Prev != headers[2].Digest() {
+		t.Errorf("header = %+v", h)
+	}
+	if _, err := crypto.VerifyBundle(dir, nil); err != nil {
+		t.Errorf("VerifyBundle() with embedded keys failed: %v", err)
+	}
+
+	other, _ := crypto.GenerateKeyPair()
+	if _, err := crypto.VerifyBundle(dir, [][]byte{other.PublicKey}); err == nil || !strings.Contains(err.Error(), "untrusted") {
+		t.Errorf("VerifyBundle() with another key = %v, want untrusted", err)
+	}
+	if _, err := crypto.VerifyBundle(t.TempDir(), nil); err == nil {
+		t.Error("VerifyBundle() should fail without signatures")
+	}
+}
+
+// TestVerifyBundle_Tampering verifies altered files, headers and chains fail
+func TestVerifyBundle_Tampering(t *testing.T) {
+	keyPair, _ := crypto.GenerateKeyPair()
+	names := []string{"a.json", "b.csv", "c.log"}
+
+	// Modified artifact
+	dir := signChain(t, keyPair, names...)
+	os.WriteFile(filepath.Join(dir, "b.csv"), []byte("forged"), 0644)
+	if _, err := crypto.VerifyBundle(dir, nil); err == nil || !strings.Contains(err.Error(), "b.csv (modified)") {
+		t.Errorf("modified artifact: err = %v", err)
+	}
+
+	// Header changed after signing
+	dir = signChain(t, keyPair, names...)
+	sigPath := filepath.Join(dir, "a.json"+crypto.ArtifactSignatureSuffix)
+	data, _ := os.ReadFile(sigPath)
+	os.WriteFile(sigPath, bytes.Replace(data, []byte(`"ws-01"`), []byte(`"ws-02"`), 1), 0644)
+	if _, err := crypto.VerifyBundle(dir, nil); err == nil || !strings.Contains(err.Error(), "does not verify") {
+		t.Errorf("modified header: err = %v", err)
+	}
+
+	// Artifact and signature removed from the middle of the chain
+	dir = signChain(t, keyPair, names...)
+	os.Remove(filepath.Join(dir, "b.csv"))
+	os.Remove(filepath.Join(dir, "b.csv"+crypto.ArtifactSignatureSuffix))
+	if _, err := crypto.VerifyBundle(dir, nil); err == nil || !strings.Contains(err.Error(), "c.log (chain broken") {
+		t.Errorf("removed artifact: err = %v", err)
+	}
+}
+
+// TestVerifyBundle_P256 verifies chains signed by a P-256 key, as hardware
+// keys sign them, and trust through an exported public key
+func TestVerifyBundle_P256(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, err := crypto.NewKeyChainSigner(key, "1.2.3", "ws-01", chainTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := signWith(t, chain, "host.json", "host.audit.json")
+
+	pubPath := filepath.Join(t.TempDir(), "tpm.pub")
+	if err := crypto.SaveECDSAPublicKey(&key.PublicKey, pubPath); err != nil {
+		t.Fatal(err)
+	}
+	trusted, err := crypto.LoadVerifyingKey(pubPath)
+	if err != nil {
+		t.Fatalf("LoadVerifyingKey() failed: %v", err)
+	}
+	headers, err := crypto.VerifyBundle(dir, [][]byte{trusted})
+	if err != nil || len(headers) != 2 {
+		t.Fatalf("VerifyBundle() = %d headers, %v", len(headers), err)
+	}
+	if headers[0].KeyFingerprint != crypto.Fingerprint(chain.PublicKey()) {
+		t.Errorf("fingerprint = %s", headers[0].KeyFingerprint)
+	}
+
+	other, _ := crypto.GenerateKeyPair()
+	if _, err := crypto.VerifyBundle(dir, [][]byte{other.PublicKey}); err == nil {
+		t.Error("VerifyBundle() should reject an untrusted key")
+	}
+	sigPath := filepath.Join(dir, "host.json"+crypto.ArtifactSignatureSuffix)
+	data, _ := os.ReadFile(sigPath)
+	os.WriteFile(sigPath, bytes.Replace(data, []byte(`"ws-01"`), []byte(`"ws-02"`), 1), 0644)
+	if _, err := crypto.VerifyBundle(dir, nil); err == nil || !strings.Contains(err.Error(), "does not verify") {
+		t.Errorf("modified header: err = %v", err)
+	}
+
+	if _, err := crypto.NewKeyChainSigner(ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)), "1.2.3", "ws-01", nil); err == nil {
+		t.Error("NewKeyChainSigner() should reject non-P-256 keys")
+	}
+}
+
+// TestOpenHardwareKey verifies backends are checked and unavailable ones
+// report ErrKeyBackendUnsupported
+func TestOpenHardwareKey(t *testing.T) {
+	if _, err := crypto.OpenHardwareKey(crypto.KeyBackendFile, "minibeast-signing"); err == nil {
+		t.Error("file is not a hardware backend")
+	}
+	if runtime.GOOS != "linux" {
+		t.Skip("hardware backends may exist here")
+	}
+	for _, backend := range []string{crypto.KeyBackendTPM, crypto.KeyBackendSecureEnclave} {
+		if _, err := crypto.OpenHardwareKey(backend, "minibeast-signing"); !errors.Is(err, crypto.ErrKeyBackendUnsupported) {
+			t.Errorf("OpenHardwareKey(%s) = %v, want ErrKeyBackendUnsupported", backend, err)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// Envelope layout (all integers big-endian):
+//
+//	magic            4 bytes   "MBE1"
+//	slot count       2 bytes   1..MaxRecipients
+//	slots            n × 112   key ID (32) | ephemeral X25519 key (32) | wrapped file key (48)
+//	nonce            12 bytes
+//	ciphertext       AES-256-GCM(file key, nonce, plaintext, aad = every preceding byte)
+//
+// Each slot wraps the same random file key for one recipient:
+// KEK = HKDF-SHA256(X25519(ephemeral, recipient), salt = ephemeral || recipient).
+// Security: any single recipient private key decrypts; the header is
+// authenticated, so slots cannot be added, removed or reordered undetected.
+const (
+	envelopeMagic  = "MBE1"
+	keyIDSize      = sha256.Size
+	wrappedKeySize = 32 + 16 // file key + GCM tag
+	slotSize       = keyIDSize + 32 + wrappedKeySize
+	nonceSize      = 12
+
+	// MaxRecipients bounds the number of key slots per envelope
+	MaxRecipients = 32
+)
+
+// slotInfo is the HKDF info string binding derived keys to this format
+var slotInfo = []byte("minibeast envelope v1 key slot")
+
+// ErrNotRecipient is returned when no key slot matches the private key
+var ErrNotRecipient = errors.New("envelope is not encrypted to this key")
+
+// RecipientKeyID returns the hex SHA-256 of an X25519 public key (slot identifier)
+// Complexity: O(1)
+func RecipientKeyID(pub *ecdh.PublicKey) string {
+	id := recipientKeyID(pub)
+	return hex.EncodeToString(id[:])
+}
+
+func recipientKeyID(pub *ecdh.PublicKey) [keyIDSize]byte {
+	return sha256.Sum256(pub.Bytes())
+}
+
+// Encrypt seals plaintext for every recipient with one key slot each
+// Mathematical guarantee: Decrypt(Encrypt(m, R), k) = m for every k ∈ R
+// Complexity: O(|plaintext| + |recipients|)
+func Encrypt(plaintext []byte, recipients []*ecdh.PublicKey) ([]byte, error) {
+	if len(recipients) == 0 {
+		return nil, fmt.Errorf("at least one recipient is required")
+	}
+	if len(recipients) > MaxRecipients {
+		return nil, fmt.Errorf("too many recipients: %d (max %d)", len(recipients), MaxRecipients)
+	}
+
+	fileKey := make([]byte, 32)
+	if _, err := rand.Read(fileKey); err != nil {
+		return nil, fmt.Errorf("failed to generate file key: %w", err)
+	}
+
+	var header bytes.Buffer
+	header.WriteString(envelopeMagic)
+	binary.Write(&header, binary.BigEndian, uint16(len(recipients)))
+
+	seen := make(map[[keyIDSize]byte]bool, len(recipients))
+	for _, recipient := range recipients {
+		if recipient == nil || recipient.Curve() != ecdh.X25519() {
+			return nil, fmt.Errorf("recipient keys must be X25519")
+		}
+		id := recipientKeyID(recipient)
+		if seen[id] {
+			return nil, fmt.Errorf("duplicate recipient %s", hex.EncodeToString(id[:]))
+		}
+		seen[id] = true
+
+		ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate ephemeral key: %w", err)
+		}
+		secret, err := ephemeral.ECDH(recipient)
+		if err != nil {
+			return nil, fmt.Errorf("key agreement failed: %w", err)
+		}
+		kek, err := slotKey(secret, ephemeral.PublicKey(), recipient)
+		if err != nil {
+			return nil, err
+		}
+		wrapped, err := seal(kek, make([]byte, nonceSize), fileKey, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		header.Write(id[:])
+		header.Write(ephemeral.PublicKey().Bytes())
+		header.Write(wrapped)
+	}
+
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	header.Write(nonce)
+
+	ciphertext, err := seal(fileKey, nonce, plaintext, header.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return append(header.Bytes(), ciphertext...), nil
+}
+
+// Decrypt opens an envelope with one recipient's private key
+// Returns ErrNotRecipient when no slot is addressed to the key.
+// Complexity: O(|envelope|)
+func Decrypt(envelope []byte, private *ecdh.PrivateKey) ([]byte, error) {
+	slots, err := parseSlots(envelope)
+	if err != nil {
+		return nil, err
+	}
+
+	id := recipientKeyID(private.PublicKey())
+	for i := 0; i < slots; i++ {
+		slot := envelope[6+i*slotSize : 6+(i+1)*slotSize]
+		if !bytes.Equal(slot[:keyIDSize], id[:]) {
+			continue
+		}
+
+		ephemeral, err := ecdh.X25519().NewPublicKey(slot[keyIDSize : keyIDSize+32])
+		if err != nil {
+			return nil, fmt.Errorf("invalid ephemeral key in slot %d: %w", i, err)
+		}
+		secret, err := private.ECDH(ephemeral)
+		if err != nil {
+			return nil, fmt.Errorf("key agreement failed: %w", err)
+		}
+		kek, err := slotKey(secret, ephemeral, private.PublicKey())
+		if err != nil {
+			return nil, err
+		}
+		fileKey, err := open(kek, make([]byte, nonceSize), slot[keyIDSize+32:], nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unwrap file key: %w", err)
+		}
+
+		payloadStart := 6 + slots*slotSize
+		nonce := envelope[payloadStart : payloadStart+nonceSize]
+		plaintext, err := open(fileKey, nonce, envelope[payloadStart+nonceSize:], envelope[:payloadStart+nonceSize])
+		if err != nil {
+			return nil, fmt.Errorf("envelope authentication failed: %w", err)
+		}
+		return plaintext, nil
+	}
+	return nil, ErrNotRecipient
+}
+
+// EnvelopeRecipients lists the key IDs of every slot (header only, no key needed)
+// Complexity: O(slots)
+func EnvelopeRecipients(envelope []byte) ([]string, error) {
+	slots, err := parseSlots(envelope)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, slots)
+	for i := range ids {
+		start := 6 + i*slotSize
+		ids[i] = hex.EncodeToString(envelope[start : start+keyIDSize])
+	}
+	return ids, nil
+}
+
+// parseSlots validates the fixed header and returns the slot count
+func parseSlots(envelope []byte) (int, error) {
+	if len(envelope) < 6 || string(envelope[:4]) != envelopeMagic {
+		return 0, fmt.Errorf("not a MiniBeast envelope")
+	}
+	slots := int(binary.BigEndian.Uint16(envelope[4:6]))
+	if slots == 0 || slots > MaxRecipients {
+		return 0, fmt.Errorf("invalid slot count: %d", slots)
+	}
+	if len(envelope) < 6+slots*slotSize+nonceSize+16 {
+		return 0, fmt.Errorf("truncated envelope")
+	}
+	return slots, nil
+}
+
+// slotKey derives the key-encryption key for one slot
+// ECDH is symmetric, so the sender X25519(ephemeral, recipient) and the
+// receiver X25519(recipient, ephemeral) pass the same secret.
+func slotKey(secret []byte, ephemeral, recipient *ecdh.PublicKey) ([]byte, error) {
+	salt := append(append([]byte{}, ephemeral.Bytes()...), recipient.Bytes()...)
+
+	kek := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, slotInfo), kek); err != nil {
+		return nil, fmt.Errorf("key derivation failed: %w", err)
+	}
+	return kek, nil
+}
+
+// seal encrypts with AES-256-GCM
+func seal(key, nonce, plaintext, aad []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(nil, nonce, plaintext, aad), nil
+}
+
+// open decrypts with AES-256-GCM
+func open(key, nonce, ciphertext, aad []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Open(nil, nonce, ciphertext, aad)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
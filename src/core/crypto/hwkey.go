@@ -0,0 +1,154 @@
+package crypto
+
+import (
+	stdcrypto "crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"runtime"
+)
+
+// Key backends (output.key_backend)
+const (
+	KeyBackendFile          = "file"           // PEM key (audit.signing_key) or a per-run key
+	KeyBackendTPM           = "tpm"            // Windows TPM via the CNG Platform Crypto Provider
+	KeyBackendSecureEnclave = "secure_enclave" // macOS Secure Enclave
+)
+
+// ErrKeyBackendUnsupported reports a hardware key backend this build or
+// machine cannot use
+var ErrKeyBackendUnsupported = errors.New("key backend not available")
+
+// HardwareKey is a non-exportable P-256 signing key held by a TPM or the
+// Secure Enclave
+// Sign takes a SHA-256 digest and returns an ASN.1 DER ECDSA signature.
+type HardwareKey interface {
+	stdcrypto.Signer
+	Close() error
+}
+
+// OpenHardwareKey opens the key called name in backend, creating it on
+// first use
+// The private key never leaves the device, so copying the stick does not
+// copy the key; each machine has its own key under the same name.
+// Complexity: O(1) (one device round trip; creation may take seconds)
+func OpenHardwareKey(backend, name string) (HardwareKey, error) {
+	switch backend {
+	case KeyBackendTPM, KeyBackendSecureEnclave:
+	default:
+		return nil, fmt.Errorf("not a hardware key backend: %q", backend)
+	}
+	if name == "" {
+		return nil, fmt.Errorf("hardware key needs a name")
+	}
+	key, err := platformOpenKey(backend, name)
+	if errors.Is(err, ErrKeyBackendUnsupported) {
+		return nil, fmt.Errorf("%s on %s: %w", backend, runtime.GOOS, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s key %s: %w", backend, name, err)
+	}
+	return key, nil
+}
+
+// p256FromPoint parses an uncompressed P-256 point (0x04 || X || Y)
+func p256FromPoint(point []byte) (*ecdsa.PublicKey, error) {
+	if len(point) != 65 || point[0] != 4 {
+		return nil, fmt.Errorf("invalid P-256 public key")
+	}
+	pub := &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(point[1:33]),
+		Y:     new(big.Int).SetBytes(point[33:]),
+	}
+	if !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+		return nil, fmt.Errorf("invalid P-256 public key")
+	}
+	return pub, nil
+}
+
+// SaveECDSAPublicKey writes a P-256 public key to file in PEM (PKIX) format
+// Complexity: O(1)
+func SaveECDSAPublicKey(key *ecdsa.PublicKey, path string) error {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return fmt.Errorf("failed to encode public key: %w", err)
+	}
+	pemData := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, pemData, 0644); err != nil {
+		return fmt.Errorf("failed to write temp public key: %w", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to rename public key: %w", err)
+	}
+	return nil
+}
+
+// LoadVerifyingKey reads a public key artifact signatures may be checked
+// against: an Ed25519 key (SavePublicKey) or a P-256 hardware key
+// (SaveECDSAPublicKey)
+// Returns the key as ArtifactSignature.PublicKey embeds it: the raw Ed25519
+// key or the PKIX DER encoding.
+// Complexity: O(1)
+func LoadVerifyingKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public key file: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM block")
+	}
+	if block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("invalid PEM block type: %s", block.Type)
+	}
+	if len(block.Bytes) == PublicKeySize {
+		return block.Bytes, nil
+	}
+	if _, err := parseP256(block.Bytes); err != nil {
+		return nil, err
+	}
+	return block.Bytes, nil
+}
+
+// EncodePublicKey encodes a signing key's public half as
+// ArtifactSignature.PublicKey embeds it: raw Ed25519 or PKIX P-256
+// Complexity: O(1)
+func EncodePublicKey(pub stdcrypto.PublicKey) ([]byte, error) {
+	switch k := pub.(type) {
+	case ed25519.PublicKey:
+		return k, nil
+	case *ecdsa.PublicKey:
+		if k.Curve != elliptic.P256() {
+			break
+		}
+		der, err := x509.MarshalPKIXPublicKey(k)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode public key: %w", err)
+		}
+		return der, nil
+	}
+	return nil, fmt.Errorf("public key is neither Ed25519 nor P-256")
+}
+
+// parseP256 parses a PKIX-encoded P-256 public key
+func parseP256(der []byte) (*ecdsa.PublicKey, error) {
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	pub, ok := key.(*ecdsa.PublicKey)
+	if !ok || pub.Curve != elliptic.P256() {
+		return nil, fmt.Errorf("public key is neither Ed25519 nor P-256")
+	}
+	return pub, nil
+}
@@ -0,0 +1,192 @@
+//go:build darwin && cgo
+
+package crypto
+
+// #cgo LDFLAGS: -framework Security -framework CoreFoundation
+// #include <stdlib.h>
+// #include <string.h>
+// #include <CoreFoundation/CoreFoundation.h>
+// #include <Security/Security.h>
+//
+// // tag_data wraps a key's application tag
+// static CFDataRef tag_data(const char *tag) {
+//     return CFDataCreate(NULL, (const UInt8 *)tag, (CFIndex)strlen(tag));
+// }
+//
+// // error_status turns a CFError into an OSStatus and releases it
+// static OSStatus error_status(CFErrorRef err) {
+//     if (err == NULL) return errSecParam;
+//     OSStatus status = (OSStatus)CFErrorGetCode(err);
+//     CFRelease(err);
+//     return status;
+// }
+//
+// // find_key looks up the Secure Enclave private key tagged tag
+// static OSStatus find_key(const char *tag, SecKeyRef *key) {
+//     CFDataRef tagData = tag_data(tag);
+//     const void *keys[] = {kSecClass, kSecAttrApplicationTag, kSecAttrKeyType, kSecAttrTokenID, kSecReturnRef};
+//     const void *values[] = {kSecClassKey, tagData, kSecAttrKeyTypeECSECPrimeRandom, kSecAttrTokenIDSecureEnclave, kCFBooleanTrue};
+//     CFDictionaryRef query = CFDictionaryCreate(NULL, keys, values, 5, &kCFTypeDictionaryKeyCallBacks, &kCFTypeDictionaryValueCallBacks);
+//     OSStatus status = SecItemCopyMatching(query, (CFTypeRef *)key);
+//     CFRelease(query);
+//     CFRelease(tagData);
+//     return status;
+// }
+//
+// // create_key generates a permanent P-256 key tagged tag inside the
+// // Secure Enclave, usable only while this device is unlocked
+// static OSStatus create_key(const char *tag, SecKeyRef *key) {
+//     SecAccessControlRef access = SecAccessControlCreateWithFlags(NULL,
+//         kSecAttrAccessibleWhenUnlockedThisDeviceOnly, kSecAccessControlPrivateKeyUsage, NULL);
+//     if (access == NULL) return errSecParam;
+//     CFDataRef tagData = tag_data(tag);
+//     const void *pkeys[] = {kSecAttrIsPermanent, kSecAttrApplicationTag, kSecAttrAccessControl};
+//     const void *pvalues[] = {kCFBooleanTrue, tagData, access};
+//     CFDictionaryRef priv = CFDictionaryCreate(NULL, pkeys, pvalues, 3, &kCFTypeDictionaryKeyCallBacks, &kCFTypeDictionaryValueCallBacks);
+//     int bits = 256;
+//     CFNumberRef size = CFNumberCreate(NULL, kCFNumberIntType, &bits);
+//     const void *keys[] = {kSecAttrKeyType, kSecAttrKeySizeInBits, kSecAttrTokenID, kSecPrivateKeyAttrs};
+//     const void *values[] = {kSecAttrKeyTypeECSECPrimeRandom, size, kSecAttrTokenIDSecureEnclave, priv};
+//     CFDictionaryRef attrs = CFDictionaryCreate(NULL, keys, values, 4, &kCFTypeDictionaryKeyCallBacks, &kCFTypeDictionaryValueCallBacks);
+//     CFErrorRef err = NULL;
+//     *key = SecKeyCreateRandomKey(attrs, &err);
+//     CFRelease(attrs);
+//     CFRelease(size);
+//     CFRelease(priv);
+//     CFRelease(tagData);
+//     CFRelease(access);
+//     return *key == NULL ? error_status(err) : errSecSuccess;
+// }
+//
+// // copy_public writes the uncompressed public point (65 bytes) to out
+// static OSStatus copy_public(SecKeyRef key, UInt8 *out, CFIndex len) {
+//     SecKeyRef pub = SecKeyCopyPublicKey(key);
+//     if (pub == NULL) return errSecParam;
+//     CFErrorRef err = NULL;
+//     CFDataRef data = SecKeyCopyExternalRepresentation(pub, &err);
+//     CFRelease(pub);
+//     if (data == NULL) return error_status(err);
+//     OSStatus status = errSecParam;
+//     if (CFDataGetLength(data) == len) {
+//         CFDataGetBytes(data, CFRangeMake(0, len), out);
+//         status = errSecSuccess;
+//     }
+//     CFRelease(data);
+//     return status;
+// }
+//
+// // sign_digest signs a SHA-256 digest, writing the DER signature to out
+// static OSStatus sign_digest(SecKeyRef key, const UInt8 *digest, CFIndex digestLen, UInt8 *out, CFIndex cap, CFIndex *outLen) {
+//     CFDataRef data = CFDataCreate(NULL, digest, digestLen);
+//     CFErrorRef err = NULL;
+//     CFDataRef sig = SecKeyCreateSignature(key, kSecKeyAlgorithmECDSASignatureDigestX962SHA256, data, &err);
+//     CFRelease(data);
+//     if (sig == NULL) return error_status(err);
+//     OSStatus status = errSecParam;
+//     *outLen = CFDataGetLength(sig);
+//     if (*outLen <= cap) {
+//         CFDataGetBytes(sig, CFRangeMake(0, *outLen), out);
+//         status = errSecSuccess;
+//     }
+//     CFRelease(sig);
+//     return status;
+// }
+//
+// static void release_key(SecKeyRef key) {
+//     CFRelease(key);
+// }
+import "C"
+
+import (
+	stdcrypto "crypto"
+	"crypto/ecdsa"
+	"fmt"
+	"io"
+	"sync"
+	"unsafe"
+)
+
+const (
+	errSecItemNotFound = -25300
+	errSecNotAvailable = -25291 // No Secure Enclave (or keychain) on this machine
+)
+
+// enclaveKey is a P-256 key kept in the Secure Enclave, found by its
+// keychain application tag
+type enclaveKey struct {
+	mu     sync.Mutex
+	ref    C.SecKeyRef
+	public *ecdsa.PublicKey
+}
+
+// platformOpenKey finds the Secure Enclave key tagged name, creating it
+// when missing
+func platformOpenKey(backend, name string) (HardwareKey, error) {
+	if backend != KeyBackendSecureEnclave {
+		return nil, ErrKeyBackendUnsupported
+	}
+	tag := C.CString(name)
+	defer C.free(unsafe.Pointer(tag))
+
+	k := &enclaveKey{}
+	status := C.find_key(tag, &k.ref)
+	if status == errSecItemNotFound {
+		status = C.create_key(tag, &k.ref)
+	}
+	switch status {
+	case 0:
+	case errSecNotAvailable:
+		return nil, fmt.Errorf("%w: no Secure Enclave (OSStatus %d)", ErrKeyBackendUnsupported, int(status))
+	default:
+		return nil, fmt.Errorf("keychain: OSStatus %d", int(status))
+	}
+
+	point := make([]byte, 65)
+	if status := C.copy_public(k.ref, (*C.UInt8)(unsafe.Pointer(&point[0])), C.CFIndex(len(point))); status != 0 {
+		k.Close()
+		return nil, fmt.Errorf("public key: OSStatus %d", int(status))
+	}
+	pub, err := p256FromPoint(point)
+	if err != nil {
+		k.Close()
+		return nil, err
+	}
+	k.public = pub
+	return k, nil
+}
+
+// Public returns the key's P-256 public half
+func (k *enclaveKey) Public() stdcrypto.PublicKey {
+	return k.public
+}
+
+// Sign signs a SHA-256 digest in the Secure Enclave (ASN.1 DER, X9.62)
+func (k *enclaveKey) Sign(_ io.Reader, digest []byte, opts stdcrypto.SignerOpts) ([]byte, error) {
+	if opts.HashFunc() != stdcrypto.SHA256 || len(digest) != 32 {
+		return nil, fmt.Errorf("secure enclave key signs SHA-256 digests only")
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.ref == 0 {
+		return nil, fmt.Errorf("secure enclave key is closed")
+	}
+	sig := make([]byte, 80) // DER P-256 signatures are at most 72 bytes
+	var n C.CFIndex
+	status := C.sign_digest(k.ref, (*C.UInt8)(unsafe.Pointer(&digest[0])), C.CFIndex(len(digest)),
+		(*C.UInt8)(unsafe.Pointer(&sig[0])), C.CFIndex(len(sig)), &n)
+	if status != 0 {
+		return nil, fmt.Errorf("SecKeyCreateSignature: OSStatus %d", int(status))
+	}
+	return sig[:n], nil
+}
+
+// Close releases the key reference; the key stays in the Secure Enclave
+func (k *enclaveKey) Close() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.ref != 0 {
+		C.release_key(k.ref)
+		k.ref = 0
+	}
+	return nil
+}
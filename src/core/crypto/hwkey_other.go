@@ -0,0 +1,9 @@
+//go:build !windows && !(darwin && cgo)
+
+package crypto
+
+// platformOpenKey is unsupported here: the TPM backend needs Windows and
+// the Secure Enclave backend a cgo build for macOS
+func platformOpenKey(backend, name string) (HardwareKey, error) {
+	return nil, ErrKeyBackendUnsupported
+}
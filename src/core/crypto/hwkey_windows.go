@@ -0,0 +1,163 @@
+//go:build windows
+
+package crypto
+
+import (
+	stdcrypto "crypto"
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	ncrypt                        = windows.NewLazySystemDLL("ncrypt.dll")
+	procNCryptOpenStorageProvider = ncrypt.NewProc("NCryptOpenStorageProvider")
+	procNCryptOpenKey             = ncrypt.NewProc("NCryptOpenKey")
+	procNCryptCreatePersistedKey  = ncrypt.NewProc("NCryptCreatePersistedKey")
+	procNCryptFinalizeKey         = ncrypt.NewProc("NCryptFinalizeKey")
+	procNCryptExportKey           = ncrypt.NewProc("NCryptExportKey")
+	procNCryptSignHash            = ncrypt.NewProc("NCryptSignHash")
+	procNCryptFreeObject          = ncrypt.NewProc("NCryptFreeObject")
+)
+
+const (
+	platformCryptoProvider = "Microsoft Platform Crypto Provider" // MS_PLATFORM_CRYPTO_PROVIDER
+	ecdsaP256Algorithm     = "ECDSA_P256"                         // BCRYPT_ECDSA_P256_ALGORITHM
+	eccPublicBlob          = "ECCPUBLICBLOB"                      // BCRYPT_ECCPUBLIC_BLOB
+	eccPublicP256Magic     = 0x31534345                           // BCRYPT_ECDSA_PUBLIC_P256_MAGIC
+	nteBadKeyset           = 0x80090016                           // Key does not exist
+)
+
+// tpmKey is a P-256 key persisted by the TPM's key storage provider in the
+// current user's profile
+type tpmKey struct {
+	provider uintptr // NCRYPT_PROV_HANDLE
+	handle   uintptr // NCRYPT_KEY_HANDLE
+	public   *ecdsa.PublicKey
+}
+
+// ncryptCall calls an NCrypt function, turning its SECURITY_STATUS into an error
+func ncryptCall(proc *windows.LazyProc, args ...uintptr) error {
+	r, _, _ := proc.Call(args...)
+	if status := uint32(r); status != 0 {
+		return fmt.Errorf("%s: %w", proc.Name, windows.Errno(status))
+	}
+	return nil
+}
+
+// platformOpenKey opens or creates the TPM key called name
+func platformOpenKey(backend, name string) (HardwareKey, error) {
+	if backend != KeyBackendTPM {
+		return nil, ErrKeyBackendUnsupported
+	}
+	if err := ncrypt.Load(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrKeyBackendUnsupported, err)
+	}
+	provider, err := windows.UTF16PtrFromString(platformCryptoProvider)
+	if err != nil {
+		return nil, err
+	}
+	keyName, err := windows.UTF16PtrFromString(name)
+	if err != nil {
+		return nil, err
+	}
+
+	k := &tpmKey{}
+	if err := ncryptCall(procNCryptOpenStorageProvider, uintptr(unsafe.Pointer(&k.provider)), uintptr(unsafe.Pointer(provider)), 0); err != nil {
+		return nil, fmt.Errorf("%w: no TPM (%v)", ErrKeyBackendUnsupported, err)
+	}
+	r, _, _ := procNCryptOpenKey.Call(k.provider, uintptr(unsafe.Pointer(&k.handle)), uintptr(unsafe.Pointer(keyName)), 0, 0)
+	switch status := uint32(r); status {
+	case 0:
+	case nteBadKeyset:
+		err = k.create(keyName)
+	default:
+		err = fmt.Errorf("NCryptOpenKey: %w", windows.Errno(status))
+	}
+	if err == nil {
+		k.public, err = k.exportPublic()
+	}
+	if err != nil {
+		k.Close()
+		return nil, err
+	}
+	return k, nil
+}
+
+// create generates the key inside the TPM (non-exportable by default)
+func (k *tpmKey) create(keyName *uint16) error {
+	alg, err := windows.UTF16PtrFromString(ecdsaP256Algorithm)
+	if err != nil {
+		return err
+	}
+	if err := ncryptCall(procNCryptCreatePersistedKey, k.provider, uintptr(unsafe.Pointer(&k.handle)), uintptr(unsafe.Pointer(alg)), uintptr(unsafe.Pointer(keyName)), 0, 0); err != nil {
+		return err
+	}
+	return ncryptCall(procNCryptFinalizeKey, k.handle, 0)
+}
+
+// exportPublic reads the public half as a BCRYPT_ECCKEY_BLOB: magic, key
+// length, X and Y
+func (k *tpmKey) exportPublic() (*ecdsa.PublicKey, error) {
+	blobType, err := windows.UTF16PtrFromString(eccPublicBlob)
+	if err != nil {
+		return nil, err
+	}
+	var size uint32
+	if err := ncryptCall(procNCryptExportKey, k.handle, 0, uintptr(unsafe.Pointer(blobType)), 0, 0, 0, uintptr(unsafe.Pointer(&size)), 0); err != nil {
+		return nil, err
+	}
+	if size < 8 {
+		return nil, fmt.Errorf("NCryptExportKey: short public key blob")
+	}
+	blob := make([]byte, size)
+	if err := ncryptCall(procNCryptExportKey, k.handle, 0, uintptr(unsafe.Pointer(blobType)), 0, uintptr(unsafe.Pointer(&blob[0])), uintptr(size), uintptr(unsafe.Pointer(&size)), 0); err != nil {
+		return nil, err
+	}
+	if binary.LittleEndian.Uint32(blob) != eccPublicP256Magic || binary.LittleEndian.Uint32(blob[4:]) != 32 || size != 8+64 {
+		return nil, fmt.Errorf("NCryptExportKey: not a P-256 public key")
+	}
+	return p256FromPoint(append([]byte{4}, blob[8:size]...))
+}
+
+// Public returns the key's P-256 public half
+func (k *tpmKey) Public() stdcrypto.PublicKey {
+	return k.public
+}
+
+// Sign signs a SHA-256 digest in the TPM; CNG returns r || s, which is
+// re-encoded as ASN.1 DER
+func (k *tpmKey) Sign(_ io.Reader, digest []byte, opts stdcrypto.SignerOpts) ([]byte, error) {
+	if opts.HashFunc() != stdcrypto.SHA256 || len(digest) != 32 {
+		return nil, fmt.Errorf("TPM key signs SHA-256 digests only")
+	}
+	raw := make([]byte, 64)
+	var n uint32
+	if err := ncryptCall(procNCryptSignHash, k.handle, 0, uintptr(unsafe.Pointer(&digest[0])), uintptr(len(digest)),
+		uintptr(unsafe.Pointer(&raw[0])), uintptr(len(raw)), uintptr(unsafe.Pointer(&n)), 0); err != nil {
+		return nil, err
+	}
+	if n != 64 {
+		return nil, fmt.Errorf("NCryptSignHash: unexpected signature length %d", n)
+	}
+	return asn1.Marshal(struct{ R, S *big.Int }{new(big.Int).SetBytes(raw[:32]), new(big.Int).SetBytes(raw[32:])})
+}
+
+// Close releases the key and provider handles
+func (k *tpmKey) Close() error {
+	if k.handle != 0 {
+		procNCryptFreeObject.Call(k.handle)
+		k.handle = 0
+	}
+	if k.provider != 0 {
+		procNCryptFreeObject.Call(k.provider)
+		k.provider = 0
+	}
+	return nil
+}
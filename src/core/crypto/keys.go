@@ -51,6 +51,8 @@ func SavePrivateKey(key ed25519.PrivateKey, path string) error {
 }
 
 // LoadPrivateKey reads private key from file
+// A passphrase-protected key fails with ErrKeyEncrypted (see
+// LoadPrivateKeyWithPassphrase).
 // Complexity: O(1)
 func LoadPrivateKey(path string) (ed25519.PrivateKey, error) {
 	data, err := os.ReadFile(path)
@@ -64,6 +66,9 @@ func LoadPrivateKey(path string) (ed25519.PrivateKey, error) {
 		return nil, fmt.Errorf("failed to decode PEM block")
 	}
 
+	if block.Type == encryptedPrivateType {
+		return nil, ErrKeyEncrypted
+	}
 	if block.Type != "PRIVATE KEY" {
 		return nil, fmt.Errorf("invalid PEM block type: %s", block.Type)
 	}
@@ -0,0 +1,155 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// Detached signature formats (output.signature_format)
+const (
+	SignatureFormatRaw      = "raw"      // 64-byte Ed25519 signature over SHA-256(data)
+	SignatureFormatMinisign = "minisign" // minisign text file, also readable by signify
+)
+
+// minisignAlg is minisign's legacy algorithm: Ed25519 over the data itself,
+// which is also what signify signs, so both tools verify the file
+const minisignAlg = "Ed"
+
+// minisignKeyIDSize is the length of the key ID minisign and signify embed
+const minisignKeyIDSize = 8
+
+// untrustedPrefix and trustedPrefix start the comment lines of a minisign file
+const (
+	untrustedPrefix = "untrusted comment: "
+	trustedPrefix   = "trusted comment: "
+)
+
+// MinisignKeyID returns the key ID embedded in minisign keys and signatures
+// minisign picks it at random; here it is the first 8 bytes of SHA-256(pub),
+// so any copy of the public key yields the same ID.
+// Complexity: O(1)
+func MinisignKeyID(pub ed25519.PublicKey) [minisignKeyIDSize]byte {
+	sum := sha256.Sum256(pub)
+	var id [minisignKeyIDSize]byte
+	copy(id[:], sum[:])
+	return id
+}
+
+// minisignKeyIDText renders a key ID as minisign prints it (hex of the
+// little-endian integer)
+func minisignKeyIDText(id [minisignKeyIDSize]byte) string {
+	return fmt.Sprintf("%016X", binary.LittleEndian.Uint64(id[:]))
+}
+
+// EncodeMinisignPublicKey returns pub as a minisign public key file, usable
+// with `minisign -V -p` and `signify -V -p`
+// Complexity: O(1)
+func EncodeMinisignPublicKey(pub ed25519.PublicKey) []byte {
+	id := MinisignKeyID(pub)
+	blob := append(append([]byte(minisignAlg), id[:]...), pub...)
+	return []byte(untrustedPrefix + "minisign public key " + minisignKeyIDText(id) + "\n" +
+		base64.StdEncoding.EncodeToString(blob) + "\n")
+}
+
+// SignMinisign returns a minisign signature file over data
+// The trusted comment (a single line) is covered by the global signature;
+// signify ignores it and checks the first two lines only.
+// Complexity: O(n) where n = len(data)
+func (s *Signer) SignMinisign(data []byte, trustedComment string) ([]byte, error) {
+	if s.keyPair == nil || s.keyPair.PrivateKey == nil {
+		return nil, fmt.Errorf("no private key available")
+	}
+	if strings.ContainsAny(trustedComment, "\r\n") {
+		return nil, fmt.Errorf("trusted comment must be a single line")
+	}
+	id := MinisignKeyID(s.keyPair.PublicKey)
+	sig := ed25519.Sign(s.keyPair.PrivateKey, data)
+	global := ed25519.Sign(s.keyPair.PrivateKey, append(append([]byte{}, sig...), trustedComment...))
+
+	blob := append(append([]byte(minisignAlg), id[:]...), sig...)
+	var b bytes.Buffer
+	b.WriteString(untrustedPrefix + "signature from minibeast key " + minisignKeyIDText(id) + "\n")
+	b.WriteString(base64.StdEncoding.EncodeToString(blob) + "\n")
+	b.WriteString(trustedPrefix + trustedComment + "\n")
+	b.WriteString(base64.StdEncoding.EncodeToString(global) + "\n")
+	return b.Bytes(), nil
+}
+
+// MinisignComment is the trusted comment minibeast signs: the signed file's
+// name and the run ID
+func MinisignComment(file, runID string) string {
+	return "file:" + file + "\trun:" + runID
+}
+
+// SignDetached returns the detached signature file for data in format
+// (SignatureFormatRaw when empty)
+// Complexity: O(n) where n = len(data)
+func (s *Signer) SignDetached(data []byte, format, trustedComment string) ([]byte, error) {
+	switch format {
+	case "", SignatureFormatRaw:
+		return s.Sign(data)
+	case SignatureFormatMinisign:
+		return s.SignMinisign(data, trustedComment)
+	}
+	return nil, fmt.Errorf("unknown signature format %q", format)
+}
+
+// VerifyMinisign checks a minisign signature file over data and returns its
+// trusted comment
+// Only the algorithm minibeast writes ("Ed") is accepted; the key ID must be
+// that of publicKey.
+// Complexity: O(n) where n = len(data)
+func VerifyMinisign(publicKey ed25519.PublicKey, data, sigFile []byte) (string, error) {
+	lines := strings.Split(strings.TrimRight(string(sigFile), "\r\n"), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], "\r")
+	}
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], untrustedPrefix) || !strings.HasPrefix(lines[2], trustedPrefix) {
+		return "", fmt.Errorf("not a minisign signature")
+	}
+	blob, err := base64.StdEncoding.DecodeString(lines[1])
+	if err != nil || len(blob) != len(minisignAlg)+minisignKeyIDSize+SignatureSize {
+		return "", fmt.Errorf("malformed minisign signature")
+	}
+	if string(blob[:len(minisignAlg)]) != minisignAlg {
+		return "", fmt.Errorf("unsupported minisign algorithm %q", blob[:len(minisignAlg)])
+	}
+	id := MinisignKeyID(publicKey)
+	if !bytes.Equal(blob[len(minisignAlg):len(minisignAlg)+minisignKeyIDSize], id[:]) {
+		return "", fmt.Errorf("signature key ID does not match the public key")
+	}
+	sig := blob[len(minisignAlg)+minisignKeyIDSize:]
+	if !ed25519.Verify(publicKey, data, sig) {
+		return "", fmt.Errorf("signature does not verify")
+	}
+	comment := strings.TrimPrefix(lines[2], trustedPrefix)
+	global, err := base64.StdEncoding.DecodeString(lines[3])
+	if err != nil || !ed25519.Verify(publicKey, append(append([]byte{}, sig...), comment...), global) {
+		return "", fmt.Errorf("trusted comment signature does not verify")
+	}
+	return comment, nil
+}
+
+// VerifyDetached checks a detached signature file of either format over data
+// Complexity: O(n) where n = len(data)
+func VerifyDetached(publicKey ed25519.PublicKey, data, sigFile []byte) bool {
+	if bytes.HasPrefix(sigFile, []byte(untrustedPrefix)) {
+		_, err := VerifyMinisign(publicKey, data, sigFile)
+		return err == nil
+	}
+	return Verify(publicKey, data, Signature(sigFile))
+}
+
+// SaveDetached writes a detached signature file of either format
+// Complexity: O(1)
+func SaveDetached(sigFile []byte, path string) error {
+	if !bytes.HasPrefix(sigFile, []byte(untrustedPrefix)) {
+		return SaveSignature(sigFile, path)
+	}
+	return writeAtomic(path, sigFile)
+}
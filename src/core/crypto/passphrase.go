@@ -0,0 +1,142 @@
+package crypto
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// PassphraseEnv names the environment variable holding the passphrase of
+// encrypted signing keys
+const PassphraseEnv = "MINIBEAST_KEY_PASSPHRASE"
+
+// encryptedPrivateType is the PEM block type of a passphrase-protected
+// Ed25519 private key
+const encryptedPrivateType = "MINIBEAST ENCRYPTED PRIVATE KEY"
+
+// Key derivation and sealing parameters
+const (
+	keyKDFSaltSize = 16
+	keyNonceSize   = 12 // AES-GCM standard nonce
+)
+
+// keyAAD binds the ciphertext to its block type and format version
+var keyAAD = []byte("minibeast-key-v1\x00" + encryptedPrivateType)
+
+// ErrKeyEncrypted is returned by LoadPrivateKey for a passphrase-protected key
+var ErrKeyEncrypted = errors.New("private key is passphrase-protected (set " + PassphraseEnv + ")")
+
+// ErrKeyPassphrase is returned when an encrypted key does not open with the
+// passphrase given
+var ErrKeyPassphrase = errors.New("wrong passphrase for private key")
+
+// EncryptPrivateKey seals key under passphrase
+// Format: PEM block holding scrypt salt (16) || nonce (12) || AES-256-GCM
+// ciphertext of the 64-byte key. scrypt (N=2^15, r=8, p=1) makes each
+// passphrase guess against a lost stick cost ~100 ms.
+// Complexity: O(scrypt)
+func EncryptPrivateKey(key ed25519.PrivateKey, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("key passphrase must not be empty (set %s)", PassphraseEnv)
+	}
+	if len(key) != PrivateKeySize {
+		return nil, fmt.Errorf("invalid private key size: %d bytes", len(key))
+	}
+	random := make([]byte, keyKDFSaltSize+keyNonceSize)
+	if _, err := rand.Read(random); err != nil {
+		return nil, fmt.Errorf("failed to generate key salt: %w", err)
+	}
+	kdfSalt, nonce := random[:keyKDFSaltSize], random[keyKDFSaltSize:]
+	wrap, err := keyWrappingKey(passphrase, kdfSalt)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := seal(wrap, nonce, key, keyAAD)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: encryptedPrivateType, Bytes: append(random, sealed...)}), nil
+}
+
+// DecryptPrivateKey opens a key sealed by EncryptPrivateKey
+// Complexity: O(scrypt)
+func DecryptPrivateKey(data []byte, passphrase string) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM block")
+	}
+	if block.Type != encryptedPrivateType {
+		return nil, fmt.Errorf("invalid PEM block type: %s", block.Type)
+	}
+	if len(block.Bytes) != keyKDFSaltSize+keyNonceSize+PrivateKeySize+16 {
+		return nil, fmt.Errorf("invalid encrypted key size: %d bytes", len(block.Bytes))
+	}
+	kdfSalt, nonce := block.Bytes[:keyKDFSaltSize], block.Bytes[keyKDFSaltSize:keyKDFSaltSize+keyNonceSize]
+	wrap, err := keyWrappingKey(passphrase, kdfSalt)
+	if err != nil {
+		return nil, err
+	}
+	key, err := open(wrap, nonce, block.Bytes[keyKDFSaltSize+keyNonceSize:], keyAAD)
+	if err != nil {
+		return nil, ErrKeyPassphrase
+	}
+	return ed25519.PrivateKey(key), nil
+}
+
+// SavePrivateKeyWithPassphrase writes key encrypted under passphrase (0600)
+// Complexity: O(scrypt)
+func SavePrivateKeyWithPassphrase(key ed25519.PrivateKey, path, passphrase string) error {
+	pemData, err := EncryptPrivateKey(key, passphrase)
+	if err != nil {
+		return err
+	}
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, pemData, 0600); err != nil {
+		return fmt.Errorf("failed to write temp key: %w", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to rename key: %w", err)
+	}
+	return nil
+}
+
+// LoadPrivateKeyWithPassphrase reads a private key, decrypting it with
+// passphrase when it is protected
+// Unprotected keys load as with LoadPrivateKey, so callers need not know
+// which kind of key they were given.
+// Complexity: O(scrypt) for protected keys, O(1) otherwise
+func LoadPrivateKeyWithPassphrase(path, passphrase string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	if block, _ := pem.Decode(data); block == nil || block.Type != encryptedPrivateType {
+		return LoadPrivateKey(path)
+	}
+	if passphrase == "" {
+		return nil, ErrKeyEncrypted
+	}
+	return DecryptPrivateKey(data, passphrase)
+}
+
+// LoadPrivateKeyFromEnv is LoadPrivateKeyWithPassphrase with the passphrase
+// in MINIBEAST_KEY_PASSPHRASE
+// Complexity: O(scrypt) for protected keys, O(1) otherwise
+func LoadPrivateKeyFromEnv(path string) (ed25519.PrivateKey, error) {
+	return LoadPrivateKeyWithPassphrase(path, os.Getenv(PassphraseEnv))
+}
+
+// keyWrappingKey derives the AES-256 key sealing a private key
+func keyWrappingKey(passphrase string, kdfSalt []byte) ([]byte, error) {
+	key, err := scrypt.Key([]byte(passphrase), kdfSalt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	return key, nil
+}
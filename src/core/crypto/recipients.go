@@ -0,0 +1,111 @@
+package crypto
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+	"os"
+)
+
+// PEM block types for X25519 envelope recipient keys (raw 32-byte keys)
+const (
+	recipientPrivateType = "X25519 PRIVATE KEY"
+	recipientPublicType  = "X25519 PUBLIC KEY"
+)
+
+// GenerateRecipientKey generates an X25519 key for receiving envelopes
+// Security level: 2^128 bits
+// Complexity: O(1)
+func GenerateRecipientKey() (*ecdh.PrivateKey, error) {
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate recipient key: %w", err)
+	}
+	return key, nil
+}
+
+// SaveRecipientPrivateKey writes an X25519 private key in PEM (0600)
+// Complexity: O(1)
+func SaveRecipientPrivateKey(key *ecdh.PrivateKey, path string) error {
+	return writePEM(path, recipientPrivateType, key.Bytes(), 0600)
+}
+
+// SaveRecipientPublicKey writes an X25519 public key in PEM
+// Complexity: O(1)
+func SaveRecipientPublicKey(key *ecdh.PublicKey, path string) error {
+	return writePEM(path, recipientPublicType, key.Bytes(), 0644)
+}
+
+// LoadRecipientPrivateKey reads an X25519 private key
+// Complexity: O(1)
+func LoadRecipientPrivateKey(path string) (*ecdh.PrivateKey, error) {
+	raw, err := readPEM(path, recipientPrivateType)
+	if err != nil {
+		return nil, err
+	}
+	key, err := ecdh.X25519().NewPrivateKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient private key: %w", err)
+	}
+	return key, nil
+}
+
+// LoadRecipientPublicKey reads an X25519 public key
+// Complexity: O(1)
+func LoadRecipientPublicKey(path string) (*ecdh.PublicKey, error) {
+	raw, err := readPEM(path, recipientPublicType)
+	if err != nil {
+		return nil, err
+	}
+	key, err := ecdh.X25519().NewPublicKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient public key: %w", err)
+	}
+	return key, nil
+}
+
+// LoadRecipientPublicKeys reads every recipient key in order
+// Complexity: O(|paths|)
+func LoadRecipientPublicKeys(paths []string) ([]*ecdh.PublicKey, error) {
+	keys := make([]*ecdh.PublicKey, 0, len(paths))
+	for _, path := range paths {
+		key, err := LoadRecipientPublicKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("recipient %s: %w", path, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// writePEM writes a single PEM block with temp-then-rename
+func writePEM(path, blockType string, der []byte, perm os.FileMode) error {
+	pemData := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, pemData, perm); err != nil {
+		return fmt.Errorf("failed to write temp key: %w", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to rename key: %w", err)
+	}
+	return nil
+}
+
+// readPEM reads a single PEM block of the expected type
+func readPEM(path, blockType string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM block")
+	}
+	if block.Type != blockType {
+		return nil, fmt.Errorf("invalid PEM block type: %s", block.Type)
+	}
+	return block.Bytes, nil
+}
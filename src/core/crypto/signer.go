@@ -4,6 +4,8 @@ import (
 	"crypto/ed25519"
 	"crypto/sha256"
 	"fmt"
+	"hash"
+	"io"
 	"os"
 )
 
@@ -26,28 +28,59 @@ func NewSigner(keyPair *KeyPair) *Signer {
 // Security: 2^128 computational hardness (collision resistance: 2^256)
 // Complexity: O(n) where n = len(data)
 func (s *Signer) Sign(data []byte) (Signature, error) {
+	// Step 1: Hash the data with SHA-256
+	hash := sha256.Sum256(data)
+
+	// Step 2: Sign the hash with Ed25519
+	return s.SignDigest(hash)
+}
+
+// SignDigest signs a SHA-256 digest computed elsewhere (e.g., by
+// io.Writer.WriteStream while writing the file)
+// Mathematical property: SignDigest(SHA256(data)) = Sign(data)
+// Complexity: O(1)
+func (s *Signer) SignDigest(digest [sha256.Size]byte) (Signature, error) {
 	if s.keyPair == nil || s.keyPair.PrivateKey == nil {
 		return nil, fmt.Errorf("no private key available")
 	}
+	return Signature(ed25519.Sign(s.keyPair.PrivateKey, digest[:])), nil
+}
 
-	// Step 1: Hash the data with SHA-256
-	hash := sha256.Sum256(data)
+// SignStream hashes bytes written to it for signing once they are complete
+// Use it as a tap on a stream that is written anyway.
+type SignStream struct {
+	signer *Signer
+	hash   hash.Hash
+}
 
-	// Step 2: Sign the hash with Ed25519
-	signature := ed25519.Sign(s.keyPair.PrivateKey, hash[:])
+// NewStream starts a streaming signature
+// Complexity: O(1)
+func (s *Signer) NewStream() *SignStream {
+	return &SignStream{signer: s, hash: sha256.New()}
+}
 
-	return Signature(signature), nil
+// Write adds p to the signed data
+func (st *SignStream) Write(p []byte) (int, error) {
+	return st.hash.Write(p)
 }
 
-// SignFile signs the contents of a file
-// Complexity: O(n) where n = file size
+// Sign returns the signature over everything written so far
+// Mathematical property: equals Signer.Sign(all bytes written)
+// Complexity: O(1)
+func (st *SignStream) Sign() (Signature, error) {
+	var digest [sha256.Size]byte
+	st.hash.Sum(digest[:0])
+	return st.signer.SignDigest(digest)
+}
+
+// SignFile signs the contents of a file, streaming it through the hash
+// Complexity: O(n) where n = file size; O(1) memory
 func (s *Signer) SignFile(filePath string) (Signature, error) {
-	data, err := os.ReadFile(filePath)
+	digest, err := fileSHA256(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+		return nil, err
 	}
-
-	return s.Sign(data)
+	return s.SignDigest(digest)
 }
 
 // Verify checks an Ed25519 signature against data
@@ -71,12 +104,30 @@ func Verify(publicKey ed25519.PublicKey, data []byte, signature Signature) bool
 // VerifyFile verifies a signature against file contents
 // Complexity: O(n) where n = file size
 func VerifyFile(publicKey ed25519.PublicKey, filePath string, signature Signature) (bool, error) {
-	data, err := os.ReadFile(filePath)
+	digest, err := fileSHA256(filePath)
 	if err != nil {
-		return false, fmt.Errorf("failed to read file: %w", err)
+		return false, err
 	}
+	if len(signature) != SignatureSize {
+		return false, nil
+	}
+	return ed25519.Verify(publicKey, digest[:], signature), nil
+}
 
-	return Verify(publicKey, data, signature), nil
+// fileSHA256 streams a file through SHA-256
+func fileSHA256(path string) ([sha256.Size]byte, error) {
+	var digest [sha256.Size]byte
+	f, err := os.Open(path)
+	if err != nil {
+		return digest, fmt.Errorf("failed to read file: %w", err)
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return digest, fmt.Errorf("failed to read file: %w", err)
+	}
+	h.Sum(digest[:0])
+	return digest, nil
 }
 
 // SaveSignature writes signature to binary file
@@ -85,10 +136,13 @@ func SaveSignature(signature Signature, path string) error {
 	if len(signature) != SignatureSize {
 		return fmt.Errorf("invalid signature size: %d bytes", len(signature))
 	}
+	return writeAtomic(path, signature)
+}
 
-	// Atomic write pattern
+// writeAtomic writes a signature file via a temp file and rename
+func writeAtomic(path string, data []byte) error {
 	tempPath := path + ".tmp"
-	if err := os.WriteFile(tempPath, signature, 0644); err != nil {
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
 		return fmt.Errorf("failed to write temp signature: %w", err)
 	}
 
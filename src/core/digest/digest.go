@@ -0,0 +1,129 @@
+// Package digest renders a run's integrity digest as a QR code
+// An operator on an air-gapped site photographs the code; HQ later parses
+// the photo's text and checks the bundle received by mail against it.
+package digest
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/minibeast/usb-agent/src/core/crypto"
+	"github.com/minibeast/usb-agent/src/core/io"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// prefix versions the encoded text (bump when fields change)
+const prefix = "MB1"
+
+// Digest binds a run to its facts, signature and signing key
+// All hashes are lowercase hex SHA-256.
+type Digest struct {
+	RunID           string
+	FactsSHA256     string // SHA-256 of facts.json as written
+	SignatureSHA256 string // SHA-256 of the detached .sig bytes
+	KeyID           string // SHA-256 of the Ed25519 public key
+}
+
+// New computes the digest of a signed facts.json
+// Complexity: O(|factsJSON|)
+func New(runID string, factsJSON []byte, signature crypto.Signature, publicKey ed25519.PublicKey) (*Digest, error) {
+	if strings.Contains(runID, "|") {
+		return nil, fmt.Errorf("run ID %q contains '|'", runID)
+	}
+	if len(signature) != crypto.SignatureSize {
+		return nil, fmt.Errorf("invalid signature size: %d bytes", len(signature))
+	}
+	if len(publicKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key size: %d bytes", len(publicKey))
+	}
+
+	return &Digest{
+		RunID:           runID,
+		FactsSHA256:     hexSHA256(factsJSON),
+		SignatureSHA256: hexSHA256(signature),
+		KeyID:           hexSHA256(publicKey),
+	}, nil
+}
+
+// String encodes the digest as "MB1|<run>|<facts>|<sig>|<key>"
+// Mathematical property: Parse(d.String()) == d
+func (d *Digest) String() string {
+	return strings.Join([]string{prefix, d.RunID, d.FactsSHA256, d.SignatureSHA256, d.KeyID}, "|")
+}
+
+// Parse decodes a digest produced by String (e.g., read from a photographed QR code)
+// Complexity: O(len(s))
+func Parse(s string) (*Digest, error) {
+	parts := strings.Split(strings.TrimSpace(s), "|")
+	if len(parts) != 5 || parts[0] != prefix {
+		return nil, fmt.Errorf("not a %s digest", prefix)
+	}
+
+	d := &Digest{RunID: parts[1], FactsSHA256: parts[2], SignatureSHA256: parts[3], KeyID: parts[4]}
+	for _, h := range []string{d.FactsSHA256, d.SignatureSHA256, d.KeyID} {
+		if len(h) != 2*sha256.Size || strings.ToLower(h) != h {
+			return nil, fmt.Errorf("malformed hash %q", h)
+		}
+		if _, err := hex.DecodeString(h); err != nil {
+			return nil, fmt.Errorf("malformed hash %q", h)
+		}
+	}
+	return d, nil
+}
+
+// Verify checks that a received bundle matches the digest and its signature is valid
+// Complexity: O(|factsJSON|)
+func (d *Digest) Verify(factsJSON []byte, signature crypto.Signature, publicKey ed25519.PublicKey) error {
+	switch {
+	case hexSHA256(publicKey) != d.KeyID:
+		return fmt.Errorf("public key does not match digest key ID")
+	case hexSHA256(factsJSON) != d.FactsSHA256:
+		return fmt.Errorf("facts do not match digest hash")
+	case hexSHA256(signature) != d.SignatureSHA256:
+		return fmt.Errorf("signature does not match digest fingerprint")
+	case !crypto.Verify(publicKey, factsJSON, signature):
+		return fmt.Errorf("signature verification failed")
+	}
+	return nil
+}
+
+// PNG renders the digest as a QR code image of size×size pixels
+// Medium error correction survives a slightly blurred phone photo.
+// Complexity: O(size²)
+func (d *Digest) PNG(size int) ([]byte, error) {
+	png, err := qrcode.Encode(d.String(), qrcode.Medium, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render QR code: %w", err)
+	}
+	return png, nil
+}
+
+// Terminal renders the digest as a QR code using half-block characters
+// Printed dark-on-light; the raw digest text follows for manual transcription.
+// Complexity: O(modules²)
+func (d *Digest) Terminal() (string, error) {
+	code, err := qrcode.New(d.String(), qrcode.Medium)
+	if err != nil {
+		return "", fmt.Errorf("failed to render QR code: %w", err)
+	}
+	return code.ToSmallString(false) + d.String() + "\n", nil
+}
+
+// WritePNG atomically writes the QR code image to path
+// Complexity: O(size²)
+func (d *Digest) WritePNG(path string, size int) error {
+	png, err := d.PNG(size)
+	if err != nil {
+		return err
+	}
+	return io.NewWriter().WriteBinary(path, png)
+}
+
+// hexSHA256 returns the lowercase hex SHA-256 of data
+func hexSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
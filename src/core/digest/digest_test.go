@@ -0,0 +1,93 @@
+package digest
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/minibeast/usb-agent/src/core/crypto"
+)
+
+// signedFacts returns facts bytes, their signature and the key pair
+func signedFacts(t *testing.T) ([]byte, crypto.Signature, *crypto.KeyPair) {
+	t.Helper()
+	kp, err := crypto.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	facts := []byte(`{"hostname":"test-host"}`)
+	sig, err := crypto.NewSigner(kp).Sign(facts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return facts, sig, kp
+}
+
+func TestDigest_RoundTripAndVerify(t *testing.T) {
+	facts, sig, kp := signedFacts(t)
+	d, err := New("run-1", facts, sig, kp.PublicKey)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	parsed, err := Parse(d.String())
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if *parsed != *d {
+		t.Errorf("Parse(String()) = %+v, want %+v", parsed, d)
+	}
+	if err := parsed.Verify(facts, sig, kp.PublicKey); err != nil {
+		t.Errorf("Verify() failed: %v", err)
+	}
+
+	tampered := append([]byte{}, facts...)
+	tampered[2] ^= 1
+	if err := parsed.Verify(tampered, sig, kp.PublicKey); err == nil {
+		t.Error("Tampered facts should not verify")
+	}
+	other, _ := crypto.GenerateKeyPair()
+	if err := parsed.Verify(facts, sig, other.PublicKey); err == nil {
+		t.Error("Foreign key should not verify")
+	}
+}
+
+func TestParse_Rejects(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"MB2|run|a|b|c",
+		"MB1|run|abc|def|012",
+		"MB1|run|" + strings.Repeat("A", 64) + "|" + strings.Repeat("0", 64) + "|" + strings.Repeat("0", 64),
+	} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q) should fail", s)
+		}
+	}
+}
+
+func TestDigest_Render(t *testing.T) {
+	facts, sig, kp := signedFacts(t)
+	d, _ := New("run-1", facts, sig, kp.PublicKey)
+
+	png, err := d.PNG(256)
+	if err != nil {
+		t.Fatalf("PNG() failed: %v", err)
+	}
+	if !bytes.HasPrefix(png, []byte("\x89PNG\r\n\x1a\n")) {
+		t.Error("PNG() did not produce a PNG image")
+	}
+
+	term, err := d.Terminal()
+	if err != nil {
+		t.Fatalf("Terminal() failed: %v", err)
+	}
+	if !strings.Contains(term, "█") || !strings.HasSuffix(term, d.String()+"\n") {
+		t.Errorf("Unexpected terminal rendering:\n%s", term)
+	}
+
+	path := filepath.Join(t.TempDir(), "out", "run-1.digest.png")
+	if err := d.WritePNG(path, 256); err != nil {
+		t.Errorf("WritePNG() failed: %v", err)
+	}
+}
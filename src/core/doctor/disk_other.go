@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !windows
+
+package doctor
+
+import "fmt"
+
+// freeBytes is unavailable on this platform
+func freeBytes(dir string) (uint64, error) {
+	return 0, fmt.Errorf("free space is not supported on this platform")
+}
@@ -0,0 +1,375 @@
+// Package doctor runs pre-engagement self-tests
+// Each check inspects one prerequisite of a collection run (model, keys,
+// remediation file, output directory, platform tools, clock) without collecting anything, so
+// operators can fix the stick before they are standing at the target.
+package doctor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/crypto"
+	"github.com/minibeast/usb-agent/src/core/remediation"
+	"github.com/minibeast/usb-agent/src/core/summarizer"
+)
+
+// Status is the outcome of one check
+type Status int
+
+const (
+	Pass Status = iota
+	Warn
+	Fail
+	Skip
+)
+
+// String returns the checklist tag for the status
+func (s Status) String() string {
+	switch s {
+	case Pass:
+		return "PASS"
+	case Warn:
+		return "WARN"
+	case Fail:
+		return "FAIL"
+	default:
+		return "SKIP"
+	}
+}
+
+// Result is one checklist line
+type Result struct {
+	Name   string
+	Status Status
+	Detail string
+}
+
+// MinFreeBytes is the free space required in output and spool directories
+const MinFreeBytes = 64 << 20
+
+// ggufMagic starts every GGUF model file
+var ggufMagic = []byte("GGUF")
+
+// earliestClock is the oldest plausible wall clock (before this build existed)
+var earliestClock = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// now and lookPath are replaced in tests
+var (
+	now      = time.Now
+	lookPath = exec.LookPath
+)
+
+// Run executes every check in checklist order
+// Complexity: O(|model|) - dominated by the model checksum
+func Run(ctx context.Context, cfg *config.Config) []Result {
+	results := []Result{}
+	results = append(results, checkModel(ctx, cfg)...)
+	results = append(results, checkKeys(cfg)...)
+	results = append(results, checkRemediation(cfg.Output.RemediationPath))
+	results = append(results, checkDirectory("output directory", cfg.Output.Directory))
+	results = append(results, checkDirectory("spool directory", cfg.Output.Spool.Directory))
+	results = append(results, checkTools()...)
+	results = append(results, checkClock())
+	return results
+}
+
+// Passed reports whether no check failed (warnings and skips are allowed)
+func Passed(results []Result) bool {
+	for _, r := range results {
+		if r.Status == Fail {
+			return false
+		}
+	}
+	return true
+}
+
+// checkModel verifies the GGUF file and that the inference engine loads it
+func checkModel(ctx context.Context, cfg *config.Config) []Result {
+	if !cfg.LLM.Enabled {
+		return []Result{{Name: "model", Status: Skip, Detail: "llm.enabled is false"}}
+	}
+	if cfg.LLM.Backend == config.LLMBackendOpenAI {
+		return []Result{timeLoad(ctx, cfg, "model server")}
+	}
+	path := cfg.LLM.ModelPath
+
+	file := Result{Name: "model file", Status: Pass, Detail: path}
+	if err := verifyModelFile(path); err != nil {
+		file.Status, file.Detail = Fail, err.Error()
+		return []Result{file}
+	}
+	if sum, err := verifyModelChecksum(path); err != nil {
+		file.Status, file.Detail = Fail, err.Error()
+		return []Result{file}
+	} else if sum == "" {
+		file.Status, file.Detail = Warn, path+" (no .sha256 file to verify against)"
+	}
+
+	return []Result{file, timeLoad(ctx, cfg, "model load")}
+}
+
+// timeLoad loads the configured backend and reports how long it took
+// For llm.backend openai, loading is a request to the server's model list.
+func timeLoad(ctx context.Context, cfg *config.Config, name string) Result {
+	load := Result{Name: name, Status: Pass}
+	start := now()
+	if err := loadModel(ctx, cfg); err != nil {
+		load.Status, load.Detail = Fail, err.Error()
+	} else {
+		load.Detail = fmt.Sprintf("loaded in %s", now().Sub(start).Round(time.Millisecond))
+	}
+	return load
+}
+
+// verifyModelFile checks that path is a readable GGUF file
+func verifyModelFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	magic := make([]byte, len(ggufMagic))
+	if _, err := io.ReadFull(f, magic); err != nil || !bytes.Equal(magic, ggufMagic) {
+		return fmt.Errorf("%s is not a GGUF model file", path)
+	}
+	return nil
+}
+
+// verifyModelChecksum compares the model against its "<path>.sha256" file
+// Returns the verified digest, or "" when no checksum file ships with the model.
+func verifyModelChecksum(path string) (string, error) {
+	data, err := os.ReadFile(path + ".sha256")
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(string(data)) // sha256sum format: "<hex>  <name>"
+	if len(fields) == 0 {
+		return "", fmt.Errorf("%s.sha256 is empty", path)
+	}
+	want := strings.ToLower(fields[0])
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, bufio.NewReader(f)); err != nil {
+		return "", err
+	}
+	got := hex.EncodeToString(h.Sum(nil))
+	if got != want {
+		return "", fmt.Errorf("%s checksum mismatch (got %s, want %s)", path, got[:12], want[:min(12, len(want))])
+	}
+	return got, nil
+}
+
+// loadModel loads and unloads the configured inference engine
+func loadModel(ctx context.Context, cfg *config.Config) error {
+	engine, err := summarizer.NewEngine(cfg)
+	if err != nil {
+		return err
+	}
+	if err := engine.Load(ctx); err != nil {
+		return err
+	}
+	return engine.Unload()
+}
+
+// keyFile is one key referenced by the config
+type keyFile struct {
+	role    string
+	path    string
+	private bool
+	parse   func(path string) error // nil = existence and permissions only
+}
+
+// configuredKeys lists the key files enabled features will read
+func configuredKeys(cfg *config.Config) []keyFile {
+	keys := []keyFile{}
+	if cfg.Output.Encrypt {
+		for _, path := range cfg.Output.RecipientKeys {
+			keys = append(keys, keyFile{role: "recipient key", path: path, parse: func(p string) error {
+				_, err := crypto.LoadRecipientPublicKey(p)
+				return err
+			}})
+		}
+	}
+	if w := cfg.Output.Exporters.Webhook; w.Enabled {
+		keys = append(keys, keyFile{role: "webhook signing key", path: w.PrivateKeyPath, private: true, parse: func(p string) error {
+			_, err := crypto.LoadPrivateKeyFromEnv(p)
+			return err
+		}})
+	}
+	if s := cfg.Output.Upload.SFTP; s.Enabled && s.PrivateKeyPath != "" {
+		keys = append(keys, keyFile{role: "sftp key", path: s.PrivateKeyPath, private: true})
+	}
+	if g := cfg.Output.Upload.GCS; g.Enabled && g.CredentialsFile != "" {
+		keys = append(keys, keyFile{role: "gcs credentials", path: g.CredentialsFile, private: true})
+	}
+	if g := cfg.Service.GRPC; g.KeyFile != "" {
+		keys = append(keys, keyFile{role: "grpc tls key", path: g.KeyFile, private: true})
+	}
+	return keys
+}
+
+// checkKeys verifies every configured key is readable, parses and (for
+// private keys) is not readable by other users
+func checkKeys(cfg *config.Config) []Result {
+	keys := configuredKeys(cfg)
+	if len(keys) == 0 {
+		return []Result{{Name: "keys", Status: Skip, Detail: "no key files configured"}}
+	}
+
+	results := make([]Result, 0, len(keys))
+	for _, k := range keys {
+		r := Result{Name: k.role, Status: Pass, Detail: k.path}
+		if err := checkKey(k); err != nil {
+			r.Status, r.Detail = Fail, err.Error()
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// checkKey validates a single key file
+func checkKey(k keyFile) error {
+	info, err := os.Stat(k.path)
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", k.path)
+	}
+	if k.private && runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		return fmt.Errorf("%s has mode %04o; private keys must be 0600", k.path, info.Mode().Perm())
+	}
+	f, err := os.Open(k.path)
+	if err != nil {
+		return err
+	}
+	f.Close()
+	if k.parse != nil {
+		return k.parse(k.path)
+	}
+	return nil
+}
+
+// checkDirectory verifies dir can be created, written and has MinFreeBytes free
+func checkDirectory(name, dir string) Result {
+	if dir == "" {
+		return Result{Name: name, Status: Skip, Detail: "not configured"}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return Result{Name: name, Status: Fail, Detail: err.Error()}
+	}
+
+	probe, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return Result{Name: name, Status: Fail, Detail: fmt.Sprintf("%s is not writable: %v", dir, err)}
+	}
+	_, werr := probe.Write([]byte("minibeast doctor\n"))
+	cerr := probe.Close()
+	os.Remove(probe.Name())
+	if werr != nil || cerr != nil {
+		return Result{Name: name, Status: Fail, Detail: fmt.Sprintf("%s is not writable: %v", dir, firstErr(werr, cerr))}
+	}
+
+	free, err := freeBytes(dir)
+	if err != nil {
+		return Result{Name: name, Status: Warn, Detail: fmt.Sprintf("%s is writable; free space unknown: %v", dir, err)}
+	}
+	detail := fmt.Sprintf("%s (%s free)", filepath.Clean(dir), formatBytes(free))
+	if free < MinFreeBytes {
+		return Result{Name: name, Status: Fail, Detail: detail + fmt.Sprintf("; need %s", formatBytes(MinFreeBytes))}
+	}
+	return Result{Name: name, Status: Pass, Detail: detail}
+}
+
+// checkRemediation verifies a customized remediation knowledge base loads
+func checkRemediation(path string) Result {
+	if path == "" {
+		return Result{Name: "remediation", Status: Skip, Detail: "embedded knowledge base"}
+	}
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return Result{Name: "remediation", Status: Skip, Detail: path + " not found; using the embedded knowledge base"}
+	}
+	if _, err := remediation.LoadOrDefault(path); err != nil {
+		return Result{Name: "remediation", Status: Fail, Detail: err.Error()}
+	}
+	return Result{Name: "remediation", Status: Pass, Detail: path}
+}
+
+// checkTools verifies the platform collectors' external commands are on PATH
+func checkTools() []Result {
+	if len(requiredTools) == 0 {
+		return []Result{{Name: "platform tools", Status: Skip, Detail: "none required on " + runtime.GOOS}}
+	}
+	missing := []string{}
+	for _, tool := range requiredTools {
+		if _, err := lookPath(tool); err != nil {
+			missing = append(missing, tool)
+		}
+	}
+	if len(missing) > 0 {
+		// Collectors degrade to partial facts without these, so warn rather than fail
+		return []Result{{Name: "platform tools", Status: Warn, Detail: "missing: " + strings.Join(missing, ", ")}}
+	}
+	return []Result{{Name: "platform tools", Status: Pass, Detail: strings.Join(requiredTools, ", ")}}
+}
+
+// checkClock rejects wall clocks that would stamp runs with impossible times
+// A dead RTC battery typically resets the clock to 1970 or the firmware date.
+func checkClock() Result {
+	t := now().UTC()
+	detail := t.Format(time.RFC3339)
+	switch {
+	case t.Before(earliestClock):
+		return Result{Name: "clock", Status: Fail, Detail: detail + " is before " + earliestClock.Format("2006-01-02") + "; set the system time"}
+	case t.After(earliestClock.AddDate(20, 0, 0)):
+		return Result{Name: "clock", Status: Fail, Detail: detail + " is implausibly far in the future; set the system time"}
+	}
+	return Result{Name: "clock", Status: Pass, Detail: detail}
+}
+
+// formatBytes renders n in binary units
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// firstErr returns the first non-nil error
+func firstErr(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package doctor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/crypto"
+)
+
+// find returns the named result
+func find(t *testing.T, results []Result, name string) Result {
+	t.Helper()
+	for _, r := range results {
+		if r.Name == name {
+			return r
+		}
+	}
+	t.Fatalf("no %q result in %+v", name, results)
+	return Result{}
+}
+
+// writeModel creates a GGUF-looking model and its checksum file
+func writeModel(t *testing.T, dir string, body []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, "model.gguf")
+	if err := os.WriteFile(path, body, 0644); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(body)
+	line := hex.EncodeToString(sum[:]) + "  model.gguf\n"
+	if err := os.WriteFile(path+".sha256", []byte(line), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRun_HealthyConfigPasses(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.Default()
+	cfg.LLM.ModelPath = writeModel(t, dir, []byte("GGUF\x03\x00\x00\x00weights"))
+	cfg.Output.Directory = filepath.Join(dir, "out")
+	cfg.Output.Spool.Directory = filepath.Join(dir, "spool")
+
+	results := Run(context.Background(), cfg)
+	if find(t, results, "model file").Status != Pass || find(t, results, "model load").Status != Pass {
+		t.Errorf("model checks failed: %+v", results)
+	}
+	if r := find(t, results, "output directory"); r.Status != Pass {
+		t.Errorf("output directory = %+v", r)
+	}
+	if find(t, results, "keys").Status != Skip {
+		t.Errorf("keys should be skipped when none are configured")
+	}
+	if find(t, results, "clock").Status != Pass {
+		t.Errorf("clock should pass")
+	}
+	entries, _ := os.ReadDir(cfg.Output.Directory)
+	if len(entries) != 0 {
+		t.Errorf("write probe left %d files behind", len(entries))
+	}
+}
+
+func TestCheckModel_Failures(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.Default()
+
+	cfg.LLM.ModelPath = filepath.Join(dir, "missing.gguf")
+	if r := checkModel(context.Background(), cfg); r[0].Status != Fail {
+		t.Errorf("missing model = %+v", r)
+	}
+
+	cfg.LLM.ModelPath = writeModel(t, dir, []byte("not a model"))
+	if r := checkModel(context.Background(), cfg); r[0].Status != Fail || !strings.Contains(r[0].Detail, "GGUF") {
+		t.Errorf("non-GGUF model = %+v", r)
+	}
+
+	cfg.LLM.ModelPath = writeModel(t, dir, []byte("GGUF original"))
+	os.WriteFile(cfg.LLM.ModelPath, []byte("GGUF truncated"), 0644)
+	if r := checkModel(context.Background(), cfg); r[0].Status != Fail || !strings.Contains(r[0].Detail, "checksum") {
+		t.Errorf("corrupted model = %+v", r)
+	}
+
+	cfg.LLM.Enabled = false
+	if r := checkModel(context.Background(), cfg); r[0].Status != Skip {
+		t.Errorf("disabled llm = %+v", r)
+	}
+}
+
+func TestCheckModel_RemoteBackend(t *testing.T) {
+	up := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !up {
+			http.Error(w, "loading model", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"data":[{"id":"qwen"}]}`))
+	}))
+	defer srv.Close()
+
+	cfg := config.Default()
+	cfg.LLM.Backend = config.LLMBackendOpenAI
+	cfg.LLM.ModelPath = filepath.Join(t.TempDir(), "missing.gguf")
+	cfg.LLM.Remote.URL = srv.URL + "/v1"
+	cfg.LLM.Remote.Model = "qwen"
+
+	r := checkModel(context.Background(), cfg)
+	if len(r) != 1 || r[0].Name != "model server" || r[0].Status != Pass {
+		t.Errorf("reachable server = %+v", r)
+	}
+	up = false
+	if r := checkModel(context.Background(), cfg); r[0].Status != Fail || !strings.Contains(r[0].Detail, "503") {
+		t.Errorf("unavailable server = %+v", r)
+	}
+}
+
+func TestCheckKeys(t *testing.T) {
+	dir := t.TempDir()
+	pair, err := crypto.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath := filepath.Join(dir, "webhook.pem")
+	if err := crypto.SavePrivateKey(pair.PrivateKey, keyPath); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.Default()
+	cfg.Output.Exporters.Webhook.Enabled = true
+	cfg.Output.Exporters.Webhook.PrivateKeyPath = keyPath
+	if r := find(t, checkKeys(cfg), "webhook signing key"); r.Status != Pass {
+		t.Errorf("0600 key = %+v", r)
+	}
+
+	if runtime.GOOS != "windows" {
+		os.Chmod(keyPath, 0644)
+		if r := find(t, checkKeys(cfg), "webhook signing key"); r.Status != Fail || !strings.Contains(r.Detail, "0600") {
+			t.Errorf("world-readable key = %+v", r)
+		}
+	}
+
+	cfg.Output.Exporters.Webhook.PrivateKeyPath = filepath.Join(dir, "absent.pem")
+	if r := find(t, checkKeys(cfg), "webhook signing key"); r.Status != Fail {
+		t.Errorf("missing key = %+v", r)
+	}
+}
+
+func TestCheckRemediation(t *testing.T) {
+	dir := t.TempDir()
+	if r := checkRemediation(filepath.Join(dir, "absent.yaml")); r.Status != Skip {
+		t.Errorf("missing file = %+v", r)
+	}
+
+	path := filepath.Join(dir, "remediation.yaml")
+	if err := os.WriteFile(path, []byte("remediations:\n  ORG-1:\n    title: Org fix\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if r := checkRemediation(path); r.Status != Fail || !strings.Contains(r.Detail, "ORG-1") {
+		t.Errorf("entry without steps = %+v", r)
+	}
+
+	if err := os.WriteFile(path, []byte("remediations:\n  ORG-1:\n    title: Org fix\n    steps: [Do it]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if r := checkRemediation(path); r.Status != Pass {
+		t.Errorf("valid file = %+v", r)
+	}
+}
+
+func TestCheckTools_Missing(t *testing.T) {
+	if len(requiredTools) == 0 {
+		t.Skip("no platform tools on " + runtime.GOOS)
+	}
+	defer func(orig func(string) (string, error)) { lookPath = orig }(lookPath)
+	lookPath = func(string) (string, error) { return "", errors.New("not found") }
+
+	r := checkTools()[0]
+	if r.Status != Warn || !strings.Contains(r.Detail, requiredTools[0]) {
+		t.Errorf("checkTools() = %+v", r)
+	}
+}
+
+func TestCheckClock(t *testing.T) {
+	defer func(orig func() time.Time) { now = orig }(now)
+
+	now = func() time.Time { return time.Unix(0, 0) } // RTC reset
+	if r := checkClock(); r.Status != Fail {
+		t.Errorf("1970 clock = %+v", r)
+	}
+	now = func() time.Time { return earliestClock.AddDate(1, 0, 0) }
+	if r := checkClock(); r.Status != Pass {
+		t.Errorf("plausible clock = %+v", r)
+	}
+}
+
+func TestPassed(t *testing.T) {
+	if !Passed([]Result{{Status: Pass}, {Status: Warn}, {Status: Skip}}) {
+		t.Error("warnings and skips should pass")
+	}
+	if Passed([]Result{{Status: Pass}, {Status: Fail}}) {
+		t.Error("a failure should not pass")
+	}
+}
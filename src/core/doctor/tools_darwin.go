@@ -0,0 +1,6 @@
+//go:build darwin
+
+package doctor
+
+// requiredTools are the commands the macOS collector executes
+var requiredTools = []string{"system_profiler", "plutil", "dscl"}
@@ -0,0 +1,6 @@
+//go:build linux
+
+package doctor
+
+// requiredTools are the commands the Linux collector executes
+var requiredTools = []string{"ip"}
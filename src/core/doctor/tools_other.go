@@ -0,0 +1,6 @@
+//go:build !linux && !darwin && !windows
+
+package doctor
+
+// requiredTools is empty where no platform collector exists
+var requiredTools = []string{}
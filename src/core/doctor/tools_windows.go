@@ -0,0 +1,6 @@
+//go:build windows
+
+package doctor
+
+// requiredTools are the commands the Windows collector executes
+var requiredTools = []string{"cmd", "ipconfig", "netsh"}
@@ -0,0 +1,96 @@
+// Package elevate detects collection categories that need more privileges
+// than the agent runs with, and reruns the agent elevated through the
+// platform's own mechanism: sudo on Linux (and in a macOS terminal), the
+// administrator authorization dialog on macOS and a UAC prompt on Windows.
+// Runs that stay unelevated record what they missed as coverage notes
+// instead of silently reporting "unknown".
+package elevate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// ErrDeclined is returned by Relaunch when the operator refused the prompt
+// or could not authenticate
+var ErrDeclined = errors.New("elevation declined")
+
+// ErrUnavailable is returned by Relaunch when no elevation mechanism can be used
+var ErrUnavailable = errors.New("no elevation mechanism available")
+
+// Requirement is a category that collects less without elevation
+type Requirement struct {
+	Category string // e.g., "hardware_info"
+	Reason   string // What is missed without elevation
+}
+
+// Note renders r as a facts coverage note
+func (r Requirement) Note() string {
+	return fmt.Sprintf("%s: %s", r.Category, r.Reason)
+}
+
+// Elevated reports whether the process runs as root or as an elevated Administrator
+// Complexity: O(1)
+func Elevated() bool {
+	return platformElevated()
+}
+
+// Missing returns the requirements of categories that elevated does not
+// meet, in category order (none when elevated)
+// Complexity: O(|categories|)
+func Missing(categories []string, elevated bool) []Requirement {
+	if elevated {
+		return nil
+	}
+	var missing []Requirement
+	for _, c := range categories {
+		if reason, ok := requirements[c]; ok {
+			missing = append(missing, Requirement{Category: c, Reason: reason})
+		}
+	}
+	return missing
+}
+
+// Notes renders requirements as coverage notes
+// Complexity: O(|reqs|)
+func Notes(reqs []Requirement) []string {
+	if len(reqs) == 0 {
+		return nil
+	}
+	notes := make([]string, len(reqs))
+	for i, r := range reqs {
+		notes[i] = r.Note()
+	}
+	return notes
+}
+
+// Relaunch reruns this executable elevated with args in the current
+// directory and waits for it, returning its exit code
+// The error wraps ErrDeclined or ErrUnavailable when the child never ran.
+// Complexity: O(1) plus the child run
+func Relaunch(args []string) (int, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	return platformRelaunch(exe, args, dir)
+}
+
+// exitStatus turns a finished child's error into its exit code
+func exitStatus(err error) (int, error) {
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return 0, nil
+	case errors.As(err, &exitErr):
+		return exitErr.ExitCode(), nil
+	default:
+		return 0, err
+	}
+}
@@ -0,0 +1,74 @@
+//go:build darwin
+
+package elevate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+)
+
+// requirements maps categories to what they miss without root
+var requirements = map[string]string{
+	"network_info": "saved Wi-Fi networks not collected; the known-networks preferences are readable only by root",
+}
+
+// platformRelaunch reruns exe through sudo when attended on a terminal,
+// otherwise through the administrator authorization dialog
+// The dialog gives no terminal, so the child's output is captured to a
+// file and replayed once it exits.
+func platformRelaunch(exe string, args []string, dir string) (int, error) {
+	if isatty.IsTerminal(os.Stdin.Fd()) {
+		return sudoRelaunch(exe, args, dir)
+	}
+
+	logFile, err := os.CreateTemp("", "minibeast-elevated-*.log")
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	logPath := logFile.Name()
+	logFile.Close()
+	defer os.Remove(logPath)
+
+	argv := append([]string{exe}, args...)
+	quoted := make([]string, len(argv))
+	for i, a := range argv {
+		quoted[i] = shellQuote(a)
+	}
+	script := fmt.Sprintf("cd %s && %s >%s 2>&1; echo $?", shellQuote(dir), strings.Join(quoted, " "), shellQuote(filepath.Clean(logPath)))
+	apple := fmt.Sprintf("do shell script %s with administrator privileges", appleScriptQuote(script))
+
+	out, err := exec.Command("osascript", "-e", apple).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			// Cancelling the dialog or failing authentication (error -128 / -60005)
+			return 0, fmt.Errorf("%w: %s", ErrDeclined, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return 0, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	if output, err := os.ReadFile(logPath); err == nil {
+		os.Stdout.Write(output)
+	}
+	code, err := strconv.Atoi(strings.TrimSpace(string(out)))
+	if err != nil {
+		return 0, fmt.Errorf("unexpected elevated run status %q", strings.TrimSpace(string(out)))
+	}
+	return code, nil
+}
+
+// shellQuote single-quotes s for /bin/sh
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// appleScriptQuote double-quotes s as an AppleScript string literal
+func appleScriptQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,13 @@
+//go:build linux
+
+package elevate
+
+// requirements maps categories to what they miss without root
+var requirements = map[string]string{
+	"hardware_info": "serial number not collected; /sys/class/dmi/id/product_serial is readable only by root",
+}
+
+// platformRelaunch reruns exe through sudo
+func platformRelaunch(exe string, args []string, dir string) (int, error) {
+	return sudoRelaunch(exe, args, dir)
+}
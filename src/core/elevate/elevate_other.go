@@ -0,0 +1,16 @@
+//go:build !linux && !darwin && !windows
+
+package elevate
+
+// requirements is empty: no category declares a need here
+var requirements = map[string]string{}
+
+// platformElevated is false: there is no way to tell here
+func platformElevated() bool {
+	return false
+}
+
+// platformRelaunch is unsupported here
+func platformRelaunch(exe string, args []string, dir string) (int, error) {
+	return 0, ErrUnavailable
+}
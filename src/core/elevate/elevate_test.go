@@ -0,0 +1,50 @@
+package elevate
+
+import (
+	"errors"
+	"os/exec"
+	"reflect"
+	"testing"
+)
+
+// TestMissing verifies requirements apply only to requested categories when unelevated
+func TestMissing(t *testing.T) {
+	orig := requirements
+	defer func() { requirements = orig }()
+	requirements = map[string]string{"hardware_info": "serial number needs root"}
+
+	cats := []string{"system_info", "hardware_info", "pii_info"}
+	want := []Requirement{{Category: "hardware_info", Reason: "serial number needs root"}}
+	if got := Missing(cats, false); !reflect.DeepEqual(got, want) {
+		t.Errorf("Missing(unelevated) = %+v, want %+v", got, want)
+	}
+	if got := Missing(cats, true); got != nil {
+		t.Errorf("Missing(elevated) = %+v, want none", got)
+	}
+	if got := Missing([]string{"system_info"}, false); got != nil {
+		t.Errorf("Missing() without privileged categories = %+v, want none", got)
+	}
+
+	notes := Notes(want)
+	if len(notes) != 1 || notes[0] != "hardware_info: serial number needs root" {
+		t.Errorf("Notes() = %q", notes)
+	}
+}
+
+// TestExitStatus verifies child exit codes are returned, not treated as errors
+func TestExitStatus(t *testing.T) {
+	if code, err := exitStatus(nil); code != 0 || err != nil {
+		t.Errorf("exitStatus(nil) = %d, %v", code, err)
+	}
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+	if code, err := exitStatus(exec.Command(sh, "-c", "exit 3").Run()); code != 3 || err != nil {
+		t.Errorf("exitStatus(exit 3) = %d, %v", code, err)
+	}
+	failed := errors.New("not started")
+	if _, err := exitStatus(failed); !errors.Is(err, failed) {
+		t.Errorf("exitStatus() lost the start error: %v", err)
+	}
+}
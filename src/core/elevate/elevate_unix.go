@@ -0,0 +1,33 @@
+//go:build linux || darwin
+
+package elevate
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// platformElevated reports whether the effective user is root
+func platformElevated() bool {
+	return os.Geteuid() == 0
+}
+
+// sudoRelaunch reruns exe as root through sudo on the operator's terminal
+// Credentials are validated first, so a refused password is told apart
+// from the elevated run itself failing.
+func sudoRelaunch(exe string, args []string, dir string) (int, error) {
+	if _, err := exec.LookPath("sudo"); err != nil {
+		return 0, fmt.Errorf("%w: sudo not found", ErrUnavailable)
+	}
+	auth := exec.Command("sudo", "-v")
+	auth.Stdin, auth.Stdout, auth.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := auth.Run(); err != nil {
+		return 0, fmt.Errorf("%w: sudo: %v", ErrDeclined, err)
+	}
+
+	cmd := exec.Command("sudo", append([]string{"-n", "--", exe}, args...)...)
+	cmd.Dir = dir
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return exitStatus(cmd.Run())
+}
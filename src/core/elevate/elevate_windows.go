@@ -0,0 +1,74 @@
+//go:build windows
+
+package elevate
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// requirements maps categories to what they miss without elevation
+// (none yet: WMI serves every collected field to standard users)
+var requirements = map[string]string{}
+
+// shellExecuteInfo mirrors SHELLEXECUTEINFOW
+type shellExecuteInfo struct {
+	cbSize         uint32
+	fMask          uint32
+	hwnd           windows.Handle
+	lpVerb         *uint16
+	lpFile         *uint16
+	lpParameters   *uint16
+	lpDirectory    *uint16
+	nShow          int32
+	hInstApp       windows.Handle
+	lpIDList       uintptr
+	lpClass        *uint16
+	hkeyClass      windows.Handle
+	dwHotKey       uint32
+	hIconOrMonitor windows.Handle
+	hProcess       windows.Handle
+}
+
+// seeMaskNoCloseProcess asks ShellExecuteEx for the child's process handle
+const seeMaskNoCloseProcess = 0x40
+
+var procShellExecuteExW = windows.NewLazySystemDLL("shell32.dll").NewProc("ShellExecuteExW")
+
+// platformElevated reports whether the process token is elevated
+func platformElevated() bool {
+	return windows.GetCurrentProcessToken().IsElevated()
+}
+
+// platformRelaunch reruns exe through the UAC prompt ("runas") and waits for it
+// The elevated child gets its own console window.
+func platformRelaunch(exe string, args []string, dir string) (int, error) {
+	info := shellExecuteInfo{
+		fMask:        seeMaskNoCloseProcess,
+		lpVerb:       windows.StringToUTF16Ptr("runas"),
+		lpFile:       windows.StringToUTF16Ptr(exe),
+		lpParameters: windows.StringToUTF16Ptr(windows.ComposeCommandLine(args)),
+		lpDirectory:  windows.StringToUTF16Ptr(dir),
+		nShow:        windows.SW_SHOWNORMAL,
+	}
+	info.cbSize = uint32(unsafe.Sizeof(info))
+	if ok, _, err := procShellExecuteExW.Call(uintptr(unsafe.Pointer(&info))); ok == 0 {
+		if errors.Is(err, windows.ERROR_CANCELLED) {
+			return 0, fmt.Errorf("%w: UAC prompt cancelled", ErrDeclined)
+		}
+		return 0, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	defer windows.CloseHandle(info.hProcess)
+
+	if _, err := windows.WaitForSingleObject(info.hProcess, windows.INFINITE); err != nil {
+		return 0, fmt.Errorf("failed to wait for the elevated run: %w", err)
+	}
+	var code uint32
+	if err := windows.GetExitCodeProcess(info.hProcess, &code); err != nil {
+		return 0, fmt.Errorf("failed to read the elevated run's exit code: %w", err)
+	}
+	return int(code), nil
+}
@@ -0,0 +1,263 @@
+// Package explain describes what a run will do, before it does it
+// A Plan lists, for one platform, every command, file, registry key and
+// API each enabled category touches; the local processing; the files
+// written; the keys used to encrypt and sign; and what each exporter,
+// uploader and statistics endpoint sends where. Building a plan reads only
+// the config, so customers can review and approve it before execution.
+package explain
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/minibeast/usb-agent/src/core/audit"
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/export"
+	coreio "github.com/minibeast/usb-agent/src/core/io"
+	"github.com/minibeast/usb-agent/src/core/logging"
+	"github.com/minibeast/usb-agent/src/core/plugin"
+)
+
+// Category is one enabled collection category and what it touches
+type Category struct {
+	Name     string
+	Accesses []Access
+}
+
+// Destination is one place data is sent
+type Destination struct {
+	Name  string // e.g., "syslog"
+	Sends string // What is sent
+	To    string // Where to (URL, address, path)
+}
+
+// Plan is the reviewable description of one run on one platform
+type Plan struct {
+	Platform   string
+	Categories []Category
+	Processing []string      // Local steps after collection (redaction, model, rules)
+	Files      []Destination // Written on the stick
+	Crypto     []string      // Encryption and signing, with the keys used
+	Network    []Destination // Sent off the machine
+}
+
+// Build describes a run of cfg on goos collecting categories (in order)
+// plugins are the discovered plugins (nil when plugins are disabled).
+// Complexity: O(|categories| + |plugins| + |exporters|)
+func Build(cfg *config.Config, goos string, categories []string, plugins []*plugin.Plugin) (*Plan, error) {
+	platform, ok := sources[goos]
+	if !ok {
+		return nil, fmt.Errorf("no collectors for platform %q (want one of %s)", goos, strings.Join(Platforms, ", "))
+	}
+	p := &Plan{Platform: goos}
+	for _, name := range categories {
+		p.Categories = append(p.Categories, Category{Name: name, Accesses: platform[name]})
+	}
+	p.addProcessing(cfg, plugins)
+	p.addFiles(cfg)
+	p.addCrypto(cfg)
+	p.addNetwork(cfg, goos, plugins)
+	return p, nil
+}
+
+// addProcessing describes the steps between collection and output
+func (p *Plan) addProcessing(cfg *config.Config, plugins []*plugin.Plugin) {
+	if kinds := cfg.Privacy.Kinds(); len(kinds) > 0 {
+		p.Processing = append(p.Processing, fmt.Sprintf("identifiers (%s) are replaced by keyed pseudonyms from salt %s first", strings.Join(kinds, ", "), cfg.Privacy.SaltPath))
+	}
+	if len(cfg.Output.Redact) > 0 {
+		p.Processing = append(p.Processing, fmt.Sprintf("output.redact removes or masks %s", strings.Join(cfg.Output.Redact, ", ")))
+	}
+	for _, pl := range plugin.OfKind(plugins, plugin.KindRedactor) {
+		p.Processing = append(p.Processing, fmt.Sprintf("redactor plugin %s rewrites the facts before anything else sees them (runs %s)", pl.Name, pluginCommand(pl, p.Platform)))
+	}
+	if cfg.LLM.Enabled {
+		if cfg.LLM.Backend == config.LLMBackendOpenAI {
+			p.Processing = append(p.Processing, fmt.Sprintf("model %s on the inference server %s summarizes the facts", cfg.LLM.Remote.Model, cfg.LLM.Remote.URL))
+		} else {
+			p.Processing = append(p.Processing, fmt.Sprintf("local model %s summarizes the facts on this machine (no network)", cfg.LLM.ModelPath))
+		}
+		for _, pl := range plugin.OfKind(plugins, plugin.KindRules) {
+			p.Processing = append(p.Processing, fmt.Sprintf("rules plugin %s receives the redacted facts and returns findings (runs %s)", pl.Name, pluginCommand(pl, p.Platform)))
+		}
+		if cfg.Analysis.Enabled {
+			p.Processing = append(p.Processing, "built-in risk rules report on the facts instead if the model cannot be loaded")
+		}
+	} else if cfg.Analysis.Enabled {
+		p.Processing = append(p.Processing, "summarization disabled (llm.enabled: false); built-in risk rules report on the facts on this machine")
+	} else {
+		p.Processing = append(p.Processing, "summarization disabled (llm.enabled: false); facts only")
+	}
+}
+
+// addFiles describes the files a run writes
+func (p *Plan) addFiles(cfg *config.Config) {
+	out := cfg.Output.Directory
+	formats := append([]string{}, cfg.Output.Formats...)
+	sort.Strings(formats)
+	p.Files = append(p.Files,
+		Destination{"artifacts", "facts and report in " + strings.Join(formats, ", "), out + "/<hostname>_<UTC time>.*"},
+		Destination{"consent", "operator acknowledgment", out + "/<hostname>_<UTC time>" + export.ConsentSuffix},
+	)
+	if cfg.Audit.Enabled {
+		p.Files = append(p.Files, Destination{"audit", "signed record of every action taken", out + "/<hostname>_<UTC time>" + audit.Suffix + ", " + audit.SignatureSuffix})
+	}
+	if cfg.Output.Sign {
+		p.Files = append(p.Files, Destination{"manifest", "size and SHA-256 of every file the run wrote", out + "/<hostname>_<UTC time>" + coreio.ManifestSuffix + ", " + coreio.ManifestSignatureSuffix})
+	}
+	if cfg.Logging.File {
+		p.Files = append(p.Files, Destination{"log", "diagnostic log: stage outcomes, errors and file paths", out + "/<hostname>_<UTC time>" + logging.Suffix})
+	}
+	if cfg.Output.LedgerPath != "" {
+		p.Files = append(p.Files, Destination{"ledger", "run ID, hostname, hardware UUID, hashes and timings", cfg.Output.LedgerPath})
+	}
+	if cfg.Output.History.Enabled {
+		p.Files = append(p.Files, Destination{"history", "facts, findings and run metadata", cfg.Output.History.Path})
+	}
+	if cfg.Telemetry.Exporter == "file" {
+		p.Files = append(p.Files, Destination{"traces", "pipeline timing spans", cfg.Telemetry.FilePath})
+	}
+	if u := cfg.UsageStats; u.Enabled && u.FilePath != "" {
+		p.Files = append(p.Files, Destination{"usage_stats", "anonymous version, OS, category timings and outcome", u.FilePath})
+	}
+	p.Files = append(p.Files, Destination{"spool", "payloads and bundles awaiting delivery", cfg.Output.Spool.Directory})
+}
+
+// addCrypto describes encryption and signing
+func (p *Plan) addCrypto(cfg *config.Config) {
+	if cfg.Output.Encrypt {
+		p.Crypto = append(p.Crypto, fmt.Sprintf("artifacts encrypted (X25519 + ChaCha20-Poly1305) to %d recipient key(s): %s",
+			len(cfg.Output.RecipientKeys), strings.Join(cfg.Output.RecipientKeys, ", ")))
+	} else {
+		p.Crypto = append(p.Crypto, "artifacts are NOT encrypted (output.encrypt: false)")
+	}
+	if cfg.Audit.Enabled {
+		if cfg.Audit.SigningKey != "" {
+			p.Crypto = append(p.Crypto, "audit file signed (Ed25519) with "+cfg.Audit.SigningKey)
+		} else {
+			p.Crypto = append(p.Crypto, "audit file signed (Ed25519) with a fresh per-run key embedded in the file")
+		}
+	}
+	if cfg.Output.Sign {
+		if cfg.Audit.SigningKey != "" {
+			p.Crypto = append(p.Crypto, "run manifest signed (Ed25519) with "+cfg.Audit.SigningKey)
+		} else {
+			p.Crypto = append(p.Crypto, "run manifest signed (Ed25519) with a fresh per-run key embedded in the file")
+		}
+		if b := cfg.Output.KeyBackend; b == "tpm" || b == "secure_enclave" {
+			p.Crypto = append(p.Crypto, "every artifact and the manifest chain-signed (ECDSA P-256) with the non-exportable "+b+" key "+cfg.Output.KeyName)
+		}
+	}
+	if w := cfg.Output.Exporters.Webhook; w.Enabled {
+		p.Crypto = append(p.Crypto, "webhook bodies signed (Ed25519) with "+w.PrivateKeyPath)
+	}
+	if s := cfg.Output.Upload.SFTP; s.Enabled && s.PrivateKeyPath != "" {
+		p.Crypto = append(p.Crypto, "SFTP login with private key "+s.PrivateKeyPath)
+	}
+}
+
+// addNetwork describes everything sent off the machine
+func (p *Plan) addNetwork(cfg *config.Config, goos string, plugins []*plugin.Plugin) {
+	if cfg.LLM.Enabled && cfg.LLM.Backend == config.LLMBackendOpenAI {
+		p.Network = append(p.Network, Destination{"llm", "prompt with the facts (hostname, users, interfaces, software)", cfg.LLM.Remote.URL})
+	}
+	ec := cfg.Output.Exporters
+	if ec.Syslog.Enabled {
+		p.Network = append(p.Network, Destination{"syslog", "run summary and each finding (" + ec.Syslog.Format + ")", ec.Syslog.Network + "://" + ec.Syslog.Address})
+	}
+	if ec.Splunk.Enabled {
+		p.Network = append(p.Network, Destination{"splunk", "facts records (host, users, interfaces, SSIDs) and findings", ec.Splunk.URL})
+	}
+	if ec.Elastic.Enabled {
+		p.Network = append(p.Network, Destination{"elasticsearch", "facts document and one document per finding", ec.Elastic.URL})
+	}
+	if ec.Webhook.Enabled {
+		p.Network = append(p.Network, Destination{"webhook", "report.json and the facts hash (signed)", ec.Webhook.URL})
+	}
+	if ec.MQTT.Enabled {
+		p.Network = append(p.Network, Destination{"mqtt", "facts records and findings under " + ec.MQTT.TopicPrefix + "/<hostname>/", ec.MQTT.Address})
+	}
+	if ec.SMTP.Enabled {
+		p.Network = append(p.Network, Destination{"smtp", "text report with report.json attached, to " + strings.Join(ec.SMTP.To, ", "), ec.SMTP.Address})
+	}
+	if ec.Kafka.Enabled {
+		p.Network = append(p.Network, Destination{"kafka", "one message per run and per finding, topic " + ec.Kafka.Topic, strings.Join(ec.Kafka.Brokers, ", ")})
+	}
+	if ec.EventLog.Enabled && goos == "windows" {
+		p.Files = append(p.Files, Destination{"eventlog", "run summary event", "Windows Application log, source " + ec.EventLog.Source})
+	}
+	for _, pl := range plugin.OfKind(plugins, plugin.KindExporter) {
+		p.Network = append(p.Network, Destination{"plugin-" + pl.Name, "redacted facts and report", "plugin command " + pluginCommand(pl, goos)})
+	}
+
+	uc := cfg.Output.Upload
+	if uc.S3.Enabled {
+		p.Network = append(p.Network, Destination{"s3", "spooled output bundles (flush)", "bucket " + uc.S3.Bucket + " " + uc.S3.Endpoint})
+	}
+	if uc.SFTP.Enabled {
+		p.Network = append(p.Network, Destination{"sftp", "spooled output bundles (flush)", uc.SFTP.Address + ":" + uc.SFTP.RemoteDir})
+	}
+	if uc.Azure.Enabled {
+		p.Network = append(p.Network, Destination{"azure", "spooled output bundles (flush)", "account " + uc.Azure.Account + ", container " + uc.Azure.Container})
+	}
+	if uc.GCS.Enabled {
+		p.Network = append(p.Network, Destination{"gcs", "spooled output bundles (flush)", "bucket " + uc.GCS.Bucket})
+	}
+
+	if cfg.Telemetry.Exporter == "otlp" {
+		p.Network = append(p.Network, Destination{"telemetry", "pipeline timing spans", cfg.Telemetry.OTLPEndpoint})
+	}
+	if u := cfg.UsageStats; u.Enabled && u.Endpoint != "" {
+		p.Network = append(p.Network, Destination{"usage_stats", "anonymous version, OS, category timings and outcome", u.Endpoint})
+	}
+}
+
+// pluginCommand renders the command a plugin runs on goos
+func pluginCommand(pl *plugin.Plugin, goos string) string {
+	if cmd, ok := pl.Commands[goos]; ok {
+		return strings.Join(cmd, " ")
+	}
+	return strings.Join(pl.Command, " ")
+}
+
+// Render writes the plan as reviewable text
+// Complexity: O(|plan|)
+func (p *Plan) Render(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "MINIBEAST RUN PLAN (%s)\n\n", p.Platform)
+
+	fmt.Fprintln(tw, "COLLECTION (read-only; nothing on this machine is modified):")
+	for _, c := range p.Categories {
+		fmt.Fprintf(tw, "  %s\n", c.Name)
+		for _, a := range c.Accesses {
+			fmt.Fprintf(tw, "    %s\t%s\t%s\n", a.Kind, a.Target, a.Purpose)
+		}
+	}
+
+	fmt.Fprintln(tw, "\nPROCESSING:")
+	for _, line := range p.Processing {
+		fmt.Fprintf(tw, "  %s\n", line)
+	}
+
+	fmt.Fprintln(tw, "\nFILES WRITTEN:")
+	for _, d := range p.Files {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", d.Name, d.To, d.Sends)
+	}
+
+	fmt.Fprintln(tw, "\nCRYPTOGRAPHY:")
+	for _, line := range p.Crypto {
+		fmt.Fprintf(tw, "  %s\n", line)
+	}
+
+	fmt.Fprintln(tw, "\nNETWORK (undelivered items are spooled and retried by flush):")
+	if len(p.Network) == 0 {
+		fmt.Fprintln(tw, "  none; nothing leaves this machine")
+	}
+	for _, d := range p.Network {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", d.Name, d.To, d.Sends)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,115 @@
+package explain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+)
+
+// TestSources_CoverEveryPlatform verifies each platform lists every category
+func TestSources_CoverEveryPlatform(t *testing.T) {
+	for _, goos := range Platforms {
+		for _, cat := range []string{"system_info", "network_info", "hardware_info", "pii_info", "software_inventory", "process_info", "disk_info", "listening_ports", "security_products", "patch_level"} {
+			if len(sources[goos][cat]) == 0 {
+				t.Errorf("%s/%s lists no accesses", goos, cat)
+			}
+		}
+	}
+}
+
+// TestBuild_Default verifies the default config is described as local only
+func TestBuild_Default(t *testing.T) {
+	cfg := config.Default()
+	p, err := Build(cfg, "windows", []string{"system_info", "network_info"}, nil)
+	if err != nil {
+		t.Fatalf("Build() failed: %v", err)
+	}
+	var out strings.Builder
+	if err := p.Render(&out); err != nil {
+		t.Fatalf("Render() failed: %v", err)
+	}
+	text := out.String()
+	for _, want := range []string{
+		"MINIBEAST RUN PLAN (windows)",
+		"netsh wlan show profiles",
+		"artifacts are NOT encrypted",
+		"none; nothing leaves this machine",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("plan lacks %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "/etc/passwd") || strings.Contains(text, "pii_info") {
+		t.Errorf("plan lists a category that was not requested:\n%s", text)
+	}
+}
+
+// TestBuild_Exporters verifies enabled exporters, uploaders and keys are listed
+func TestBuild_Exporters(t *testing.T) {
+	cfg := config.Default()
+	cfg.Output.Encrypt = true
+	cfg.Output.RecipientKeys = []string{"keys/soc.pub"}
+	cfg.Output.Exporters.Webhook.Enabled = true
+	cfg.Output.Exporters.Webhook.URL = "https://soc.example.com/hook"
+	cfg.Output.Exporters.Webhook.PrivateKeyPath = "keys/webhook.key"
+	cfg.Output.Upload.GCS.Enabled = true
+	cfg.Output.Upload.GCS.Bucket = "evidence"
+	cfg.UsageStats.Enabled = true
+	cfg.UsageStats.Endpoint = "https://stats.example.com/v1"
+
+	p, err := Build(cfg, "linux", []string{"system_info"}, nil)
+	if err != nil {
+		t.Fatalf("Build() failed: %v", err)
+	}
+	var out strings.Builder
+	if err := p.Render(&out); err != nil {
+		t.Fatalf("Render() failed: %v", err)
+	}
+	text := out.String()
+	for _, want := range []string{
+		"recipient key(s): keys/soc.pub",
+		"signed (Ed25519) with keys/webhook.key",
+		"https://soc.example.com/hook",
+		"bucket evidence",
+		"https://stats.example.com/v1",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("plan lacks %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "nothing leaves this machine") {
+		t.Error("plan claims nothing leaves the machine with exporters enabled")
+	}
+}
+
+// TestBuild_RemoteLLM verifies a remote inference server is listed as a destination
+func TestBuild_RemoteLLM(t *testing.T) {
+	cfg := config.Default()
+	cfg.LLM.Backend = config.LLMBackendOpenAI
+	cfg.LLM.Remote.URL = "https://llm.example.com/v1"
+	cfg.LLM.Remote.Model = "qwen2.5:1.5b"
+
+	p, err := Build(cfg, "linux", []string{"system_info"}, nil)
+	if err != nil {
+		t.Fatalf("Build() failed: %v", err)
+	}
+	var out strings.Builder
+	if err := p.Render(&out); err != nil {
+		t.Fatalf("Render() failed: %v", err)
+	}
+	text := out.String()
+	if !strings.Contains(text, "https://llm.example.com/v1") || strings.Contains(text, "nothing leaves this machine") {
+		t.Errorf("plan does not list the inference server:\n%s", text)
+	}
+	if strings.Contains(text, "(no network)") {
+		t.Errorf("plan claims the remote model runs locally:\n%s", text)
+	}
+}
+
+// TestBuild_UnknownPlatform verifies unsupported platforms are rejected
+func TestBuild_UnknownPlatform(t *testing.T) {
+	if _, err := Build(config.Default(), "plan9", nil, nil); err == nil {
+		t.Error("Build() accepted plan9")
+	}
+}
@@ -0,0 +1,172 @@
+package explain
+
+// Kinds of host resources a collector touches
+const (
+	KindCommand   = "command"   // Process started (read-only invocation)
+	KindFile      = "file"      // File read
+	KindDirectory = "directory" // Directory listing (entry names only)
+	KindRegistry  = "registry"  // Registry key read
+	KindAPI       = "api"       // OS or runtime call without a file of its own
+	KindEnv       = "env"       // Environment variable read
+)
+
+// Access is one host resource a category reads
+type Access struct {
+	Kind    string
+	Target  string // Command line, path, key or call
+	Purpose string // Which fields it yields
+}
+
+// Platforms lists the platforms with collectors, in display order
+var Platforms = []string{"linux", "darwin", "windows"}
+
+// sources lists, per platform and category, everything the collectors
+// touch; keep in step with src/core/platform/<os>/collector.go
+var sources = map[string]map[string][]Access{
+	"linux": {
+		"system_info": {
+			{KindFile, "/proc/sys/kernel/hostname", "hostname"},
+			{KindFile, "/etc/os-release", "OS name and version (PRETTY_NAME)"},
+			{KindFile, "/proc/version", "kernel build"},
+			{KindFile, "/etc/timezone", "time zone"},
+			{KindEnv, "TZ", "time zone fallback"},
+			{KindFile, "/etc/localtime", "time zone fallback"},
+		},
+		"network_info": {
+			{KindDirectory, "/sys/class/net", "interface names"},
+			{KindFile, "/sys/class/net/<interface>/address", "MAC addresses"},
+			{KindCommand, "ip addr show <interface>", "IPv4 addresses"},
+			{KindDirectory, "/etc/NetworkManager/system-connections", "saved Wi-Fi network names (file names only, never contents)"},
+		},
+		"hardware_info": {
+			{KindFile, "/etc/machine-id", "hardware UUID"},
+			{KindFile, "/var/lib/dbus/machine-id", "hardware UUID fallback"},
+			{KindFile, "/sys/class/dmi/id/product_serial", "serial number (root only)"},
+		},
+		"pii_info": {
+			{KindFile, "/etc/passwd", "local accounts (name, full name, UID); home directories are derived, not listed"},
+			{KindAPI, "current user lookup", "logged-in user"},
+		},
+		"software_inventory": {
+			{KindFile, "/var/lib/dpkg/status", "installed packages (name, version, maintainer)"},
+			{KindDirectory, "/var/lib/dpkg/info", "package install dates (file times only)"},
+			{KindCommand, "rpm -qa --queryformat ...", "installed packages (name, version, vendor, install time)"},
+		},
+		"process_info": {
+			{KindDirectory, "/proc", "running process IDs"},
+			{KindFile, "/proc/<pid>/comm", "process names"},
+			{KindFile, "/proc/<pid>/exe", "executable paths (link target only; other users' processes as root only)"},
+			{KindFile, "/proc/<pid>/status", "owning UID"},
+			{KindFile, "/etc/passwd", "owner account names"},
+		},
+		"disk_info": {
+			{KindCommand, "df -P -B1 -T", "mounted filesystems and capacity"},
+			{KindDirectory, "/sys/class/block", "device-mapper stack (LUKS detection; names and UUID prefixes only)"},
+		},
+		"listening_ports": {
+			{KindFile, "/proc/net/tcp, tcp6, udp, udp6", "listening and bound sockets (address, port, socket inode)"},
+			{KindDirectory, "/proc/<pid>/fd", "socket owners (other users' processes as root only)"},
+			{KindFile, "/proc/<pid>/comm", "owning process names"},
+		},
+		"security_products": {
+			{KindFile, "/opt/CrowdStrike, /opt/microsoft/mdatp, /opt/sentinelone, /usr/sbin/clamd and other agent paths", "installed antivirus and EDR agents"},
+			{KindFile, "/proc/<pid>/comm", "running agent daemons (real-time protection)"},
+			{KindFile, "/var/lib/dpkg/status", "agent versions"},
+			{KindCommand, "rpm -q <agent packages>", "agent versions"},
+		},
+		"patch_level": {
+			{KindFile, "/var/log/apt/history.log", "last package install or upgrade"},
+			{KindCommand, "rpm -qa --queryformat %{INSTALLTIME}", "last package install where apt is absent"},
+			{KindCommand, "apt-get -s upgrade, dnf -C check-update", "pending updates (local package cache only)"},
+		},
+	},
+	"darwin": {
+		"system_info": {
+			{KindAPI, "sysctl kern.hostname", "hostname"},
+			{KindFile, "/System/Library/CoreServices/SystemVersion.plist", "OS version and build"},
+			{KindFile, "/etc/localtime", "time zone"},
+		},
+		"network_info": {
+			{KindAPI, "getifaddrs", "interface names, MAC and IPv4 addresses"},
+			{KindFile, "/Library/Preferences/com.apple.wifi.known-networks.plist", "saved Wi-Fi network names (root only)"},
+			{KindFile, "/Library/Preferences/SystemConfiguration/com.apple.airport.preferences.plist", "saved Wi-Fi network names before macOS 13 (root only)"},
+		},
+		"hardware_info": {
+			{KindCommand, "system_profiler -json SPHardwareDataType", "hardware UUID and serial number"},
+		},
+		"pii_info": {
+			{KindCommand, "dscl . -list /Users", "local accounts; home directories are derived, not listed"},
+			{KindAPI, "current user lookup", "logged-in user"},
+		},
+		"software_inventory": {
+			{KindDirectory, "/Applications", "application bundles (Info.plist name and version)"},
+			{KindCommand, "plutil -convert xml1 <Info.plist>", "binary Info.plist files"},
+			{KindCommand, "pkgutil --pkgs", "installer package IDs"},
+			{KindCommand, "pkgutil --pkg-info <package>", "package version and install time"},
+		},
+		"process_info": {
+			{KindCommand, "ps -axww -o pid=,user=,comm=", "process IDs, owners and executable paths"},
+		},
+		"disk_info": {
+			{KindCommand, "df -P -k", "mounted disks and capacity"},
+			{KindCommand, "diskutil info <mount point>", "filesystem and FileVault state"},
+		},
+		"listening_ports": {
+			{KindCommand, "lsof -nP -iTCP -sTCP:LISTEN -iUDP -FpcPnt", "listening and bound sockets with owning process (other users' as root only)"},
+		},
+		"security_products": {
+			{KindFile, "/Applications/<agent>.app/Contents/Info.plist, XProtect.bundle", "installed antivirus and EDR agents and their versions"},
+			{KindCommand, "ps -axww -o pid=,user=,comm=", "running agent daemons (real-time protection)"},
+		},
+		"patch_level": {
+			{KindCommand, "softwareupdate --history", "installed updates and their install dates"},
+			{KindFile, "/Library/Preferences/com.apple.SoftwareUpdate.plist", "pending updates from the last background check"},
+		},
+	},
+	"windows": {
+		"system_info": {
+			{KindAPI, "GetComputerNameExW", "hostname"},
+			{KindCommand, "cmd /c ver", "OS version"},
+			{KindRegistry, `HKLM\SOFTWARE\Microsoft\Windows NT\CurrentVersion`, "OS build (CurrentBuildNumber, UBR)"},
+			{KindAPI, "GetDynamicTimeZoneInformation", "time zone"},
+			{KindRegistry, `HKLM\SOFTWARE\Microsoft\Windows NT\CurrentVersion\Time Zones`, "time zone name"},
+		},
+		"network_info": {
+			{KindCommand, "ipconfig /all", "adapter names, MAC and IPv4 addresses"},
+			{KindCommand, "netsh wlan show profiles", "saved Wi-Fi profile names (never keys)"},
+		},
+		"hardware_info": {
+			{KindAPI, "GetSystemFirmwareTable (SMBIOS system information)", "hardware UUID and serial number"},
+		},
+		"pii_info": {
+			{KindAPI, "NetUserEnum", "local accounts (name, full name, SID); home directories are derived, not listed"},
+			{KindAPI, "GetUserNameExW", "logged-in user"},
+		},
+		"software_inventory": {
+			{KindRegistry, `HKLM\SOFTWARE\Microsoft\Windows\CurrentVersion\Uninstall`, "installed programs (name, version, publisher, install date)"},
+			{KindRegistry, `HKLM\SOFTWARE\WOW6432Node\Microsoft\Windows\CurrentVersion\Uninstall`, "32-bit programs"},
+			{KindRegistry, `HKCU\SOFTWARE\Microsoft\Windows\CurrentVersion\Uninstall`, "per-user programs"},
+		},
+		"process_info": {
+			{KindAPI, "CreateToolhelp32Snapshot", "process IDs and names"},
+			{KindAPI, "QueryFullProcessImageName, OpenProcessToken", "executable paths and owners (other users' processes as administrator only)"},
+		},
+		"disk_info": {
+			{KindAPI, "GetLogicalDrives, GetVolumeInformationW, GetDiskFreeSpaceExW", "drive letters, filesystems and capacity"},
+			{KindCommand, "manage-bde -status", "BitLocker state (administrator only)"},
+		},
+		"listening_ports": {
+			{KindAPI, "GetExtendedTcpTable, GetExtendedUdpTable", "listening and bound sockets with owning PID"},
+			{KindAPI, "CreateToolhelp32Snapshot", "owning process names"},
+		},
+		"security_products": {
+			{KindCommand, "powershell Get-CimInstance root/SecurityCenter2 AntiVirusProduct", "registered antivirus products, version and real-time state"},
+			{KindCommand, "powershell Get-MpComputerStatus", "Microsoft Defender state where Security Center is absent"},
+			{KindAPI, "CreateToolhelp32Snapshot", "running EDR sensors"},
+		},
+		"patch_level": {
+			{KindCommand, "powershell Get-HotFix", "installed hotfixes and their install dates"},
+			{KindCommand, "powershell Microsoft.Update.Session (offline search)", "pending updates"},
+		},
+	},
+}
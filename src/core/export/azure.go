@@ -0,0 +1,192 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+)
+
+// azureRetryBase is the initial backoff between blob PUT retries
+const azureRetryBase = time.Second
+
+// azureAPIVersion is the Blob service REST version sent as x-ms-version
+const azureAPIVersion = "2021-08-06"
+
+// azureStorageResource is the managed identity token audience for Blob Storage
+const azureStorageResource = "https://storage.azure.com/"
+
+// defaultAzureIMDSEndpoint is the Azure instance metadata service
+const defaultAzureIMDSEndpoint = "http://169.254.169.254"
+
+// AzureBlobUploader puts each bundle file as <prefix>/<bundle>/<file> block blobs in a container
+// Requests carry either the configured SAS token or a bearer token for the
+// VM's managed identity, fetched from IMDS and refreshed before expiry.
+type AzureBlobUploader struct {
+	cfg      config.AzureBlobConfig
+	client   *http.Client
+	endpoint *url.URL
+	sas      string
+
+	mu    sync.Mutex
+	token *accessToken
+}
+
+// NewAzureBlobUploader creates an Azure Blob uploader
+// Complexity: O(1) (plus CA bundle parsing)
+func NewAzureBlobUploader(cfg config.AzureBlobConfig) (*AzureBlobUploader, error) {
+	client, err := newUploadClient(cfg.CAFile, cfg.TimeoutMs)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := cfg.Endpoint
+	if raw == "" {
+		raw = "https://" + cfg.Account + ".blob.core.windows.net"
+	}
+	endpoint, err := url.Parse(strings.TrimRight(raw, "/"))
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid Azure Blob endpoint %q", raw)
+	}
+
+	sas := strings.TrimPrefix(cfg.SASToken, "?")
+	if sas != "" {
+		if _, err := url.ParseQuery(sas); err != nil {
+			return nil, fmt.Errorf("invalid SAS token: %w", err)
+		}
+	}
+
+	return &AzureBlobUploader{cfg: cfg, client: client, endpoint: endpoint, sas: sas}, nil
+}
+
+// Name returns "azure"
+func (u *AzureBlobUploader) Name() string { return "azure" }
+
+// Upload puts every file of the bundle
+// Complexity: O(|Files|) requests
+func (u *AzureBlobUploader) Upload(ctx context.Context, b *Bundle) error {
+	if err := b.Validate(); err != nil {
+		return err
+	}
+
+	bearer := ""
+	if u.cfg.Auth == "managed_identity" {
+		token, err := u.managedIdentityToken(ctx)
+		if err != nil {
+			return err
+		}
+		bearer = token
+	}
+
+	for _, f := range b.Files {
+		name := u.BlobName(b.Name, f.Name)
+		err := withRetry(ctx, u.cfg.MaxRetries, azureRetryBase, func() error {
+			return u.put(ctx, bearer, name, f)
+		})
+		if err != nil {
+			return fmt.Errorf("PUT %s failed: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// BlobName returns the blob name for a bundle file
+// Complexity: O(1)
+func (u *AzureBlobUploader) BlobName(bundle, file string) string {
+	return path.Join(strings.Trim(u.cfg.Prefix, "/"), bundle, file)
+}
+
+// put uploads one block blob (Put Blob overwrites, keeping Upload idempotent)
+func (u *AzureBlobUploader) put(ctx context.Context, bearer, name string, f BundleFile) error {
+	target := *u.endpoint
+	target.Path = target.Path + "/" + u.cfg.Container + "/" + name
+	target.RawQuery = u.sas
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target.String(), bytes.NewReader(f.Data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentTypeFor(f.Name))
+	req.Header.Set("X-Ms-Blob-Type", "BlockBlob")
+	req.Header.Set("X-Ms-Version", azureAPIVersion)
+	req.Header.Set("X-Ms-Date", time.Now().UTC().Format(http.TimeFormat))
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return &RetryableError{Err: err}
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	return classifyHTTP(resp)
+}
+
+// azureIMDSToken is the IMDS managed identity token response
+// expires_on is a unix timestamp encoded as a JSON string.
+type azureIMDSToken struct {
+	AccessToken string `json:"access_token"`
+	ExpiresOn   string `json:"expires_on"`
+}
+
+// managedIdentityToken resolves (and caches) a Blob Storage token from IMDS
+// The endpoint honors AZURE_POD_IDENTITY_AUTHORITY_HOST.
+func (u *AzureBlobUploader) managedIdentityToken(ctx context.Context) (string, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.token.fresh() {
+		return u.token.Value, nil
+	}
+
+	endpoint := os.Getenv("AZURE_POD_IDENTITY_AUTHORITY_HOST")
+	if endpoint == "" {
+		endpoint = defaultAzureIMDSEndpoint
+	}
+	query := url.Values{"api-version": {"2018-02-01"}, "resource": {azureStorageResource}}
+	if u.cfg.ClientID != "" {
+		query.Set("client_id", u.cfg.ClientID)
+	}
+	tokenURL := strings.TrimRight(endpoint, "/") + "/metadata/identity/oauth2/token?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata", "true")
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", &RetryableError{Err: fmt.Errorf("managed identity token: %w", err)}
+	}
+	defer resp.Body.Close()
+	if err := classifyHTTP(resp); err != nil {
+		return "", fmt.Errorf("managed identity token: %w", err)
+	}
+
+	var doc azureIMDSToken
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(&doc); err != nil {
+		return "", fmt.Errorf("failed to parse managed identity token: %w", err)
+	}
+	expiresOn, err := strconv.ParseInt(doc.ExpiresOn, 10, 64)
+	if err != nil || doc.AccessToken == "" {
+		return "", fmt.Errorf("malformed managed identity token response")
+	}
+
+	u.token = &accessToken{Value: doc.AccessToken, Expires: time.Unix(expiresOn, 0)}
+	return u.token.Value, nil
+}
@@ -0,0 +1,279 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// CBOR major types (RFC 8949 §3.1)
+const (
+	cborUnsigned = 0 << 5
+	cborNegative = 1 << 5
+	cborBytes    = 2 << 5
+	cborText     = 3 << 5
+	cborArray    = 4 << 5
+	cborMap      = 5 << 5
+	cborTag      = 6 << 5
+	cborSimple   = 7 << 5
+)
+
+// Bignum tags (RFC 8949 §3.4.3)
+const (
+	cborTagPositiveBignum = 2
+	cborTagNegativeBignum = 3
+)
+
+// MarshalCBOR encodes v in CBOR core deterministic encoding (RFC 8949 §4.2.1)
+// The value is first mapped through its JSON representation, so field names,
+// omitempty and time formats match facts.json/report.json exactly.
+// Mathematical guarantee: Same JSON data model → Same bytes
+//   - Integers use the shortest head; beyond 64 bits they become bignums
+//   - Floats use the shortest of float16, float32 and float64 that keeps the value
+//   - Map keys are sorted by their encoded bytes (length-first for text keys)
+//   - All lengths are definite
+//
+// Complexity: O(n log n) where n = number of map keys
+func MarshalCBOR(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal value: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var generic interface{}
+	if err := dec.Decode(&generic); err != nil {
+		return nil, fmt.Errorf("failed to decode value: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := encodeCBOR(&buf, generic); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeCBOR writes a JSON data-model value
+func encodeCBOR(buf *bytes.Buffer, v interface{}) error {
+	switch val := v.(type) {
+	case nil:
+		buf.WriteByte(cborSimple | 22) // null
+	case bool:
+		if val {
+			buf.WriteByte(cborSimple | 21)
+		} else {
+			buf.WriteByte(cborSimple | 20)
+		}
+	case json.Number:
+		return encodeCBORNumber(buf, val)
+	case string:
+		writeCBORHead(buf, cborText, uint64(len(val)))
+		buf.WriteString(val)
+	case []interface{}:
+		writeCBORHead(buf, cborArray, uint64(len(val)))
+		for _, item := range val {
+			if err := encodeCBOR(buf, item); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		return encodeCBORMap(buf, val)
+	default:
+		return fmt.Errorf("unsupported CBOR value type %T", v)
+	}
+	return nil
+}
+
+// encodeCBORNumber writes integers in shortest form, other numbers as the
+// shortest float that preserves them
+func encodeCBORNumber(buf *bytes.Buffer, n json.Number) error {
+	s := n.String()
+	if !strings.ContainsAny(s, ".eE") {
+		i, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			return fmt.Errorf("invalid number %q", s)
+		}
+		encodeCBORInteger(buf, i)
+		return nil
+	}
+
+	f, err := n.Float64()
+	if err != nil {
+		return fmt.Errorf("invalid number %q: %w", s, err)
+	}
+	encodeCBORFloat(buf, f)
+	return nil
+}
+
+// encodeCBORInteger writes i as major type 0/1, or as a bignum when its
+// argument does not fit in 64 bits
+func encodeCBORInteger(buf *bytes.Buffer, i *big.Int) {
+	major, tag := byte(cborUnsigned), uint64(cborTagPositiveBignum)
+	arg := i
+	if i.Sign() < 0 {
+		// Negative integers encode -1 - n
+		major, tag = cborNegative, cborTagNegativeBignum
+		arg = new(big.Int).Sub(big.NewInt(-1), i)
+	}
+	if arg.IsUint64() {
+		writeCBORHead(buf, major, arg.Uint64())
+		return
+	}
+	writeCBORHead(buf, cborTag, tag)
+	magnitude := arg.Bytes() // Big-endian without leading zeros
+	writeCBORHead(buf, cborBytes, uint64(len(magnitude)))
+	buf.Write(magnitude)
+}
+
+// encodeCBORFloat writes f as float16, float32 or float64, whichever is
+// shortest without changing its value (RFC 8949 §4.2.1 preferred serialization)
+func encodeCBORFloat(buf *bytes.Buffer, f float64) {
+	if h, ok := float16Bits(f); ok {
+		buf.WriteByte(cborSimple | 25)
+		var b [2]byte
+		binary.BigEndian.PutUint16(b[:], h)
+		buf.Write(b[:])
+		return
+	}
+	if f32 := float32(f); float64(f32) == f {
+		buf.WriteByte(cborSimple | 26)
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], math.Float32bits(f32))
+		buf.Write(b[:])
+		return
+	}
+	buf.WriteByte(cborSimple | 27)
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], math.Float64bits(f))
+	buf.Write(b[:])
+}
+
+// float16Bits returns the IEEE 754 half-precision bits of f when f is exactly
+// representable as one (NaN maps to the canonical quiet NaN)
+func float16Bits(f float64) (uint16, bool) {
+	var sign uint16
+	if math.Signbit(f) {
+		sign = 0x8000
+	}
+	a := math.Abs(f)
+	switch {
+	case math.IsNaN(f):
+		return 0x7e00, true
+	case math.IsInf(f, 0):
+		return sign | 0x7c00, true
+	case a < 0x1p-14:
+		// Subnormal (or zero): a = m × 2^-24 with m < 2^10
+		m := a * 0x1p24
+		if m != math.Trunc(m) {
+			return 0, false
+		}
+		return sign | uint16(m), true
+	case a > 65504: // Largest finite half
+		return 0, false
+	}
+
+	frac, exp := math.Frexp(a) // a = frac × 2^exp, frac in [0.5, 1)
+	m := (frac*2 - 1) * 1024
+	if m != math.Trunc(m) {
+		return 0, false
+	}
+	return sign | uint16(exp-1+15)<<10 | uint16(m), true
+}
+
+// encodeCBORMap writes map entries sorted by encoded key bytes
+func encodeCBORMap(buf *bytes.Buffer, m map[string]interface{}) error {
+	type entry struct {
+		key   []byte
+		value interface{}
+	}
+
+	entries := make([]entry, 0, len(m))
+	for k, v := range m {
+		var kb bytes.Buffer
+		writeCBORHead(&kb, cborText, uint64(len(k)))
+		kb.WriteString(k)
+		entries = append(entries, entry{key: kb.Bytes(), value: v})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+
+	writeCBORHead(buf, cborMap, uint64(len(entries)))
+	for _, e := range entries {
+		buf.Write(e.key)
+		if err := encodeCBOR(buf, e.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeCBORHead writes a major type with its argument in shortest form
+func writeCBORHead(buf *bytes.Buffer, major byte, n uint64) {
+	switch {
+	case n < 24:
+		buf.WriteByte(major | byte(n))
+	case n <= math.MaxUint8:
+		buf.WriteByte(major | 24)
+		buf.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(major | 25)
+		var b [2]byte
+		binary.BigEndian.PutUint16(b[:], uint16(n))
+		buf.Write(b[:])
+	case n <= math.MaxUint32:
+		buf.WriteByte(major | 26)
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], uint32(n))
+		buf.Write(b[:])
+	default:
+		buf.WriteByte(major | 27)
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], n)
+		buf.Write(b[:])
+	}
+}
+
+// cborDocument is the top-level CBOR artifact layout
+type cborDocument struct {
+	RunID  string      `json:"run_id,omitempty"`
+	Facts  interface{} `json:"facts"`
+	Report interface{} `json:"report,omitempty"`
+}
+
+// CBOREncoder implements Encoder for deterministic CBOR
+type CBOREncoder struct{}
+
+// NewCBOREncoder creates a CBOR encoder
+// Complexity: O(1)
+func NewCBOREncoder() *CBOREncoder {
+	return &CBOREncoder{}
+}
+
+// Name returns "cbor"
+func (e *CBOREncoder) Name() string { return "cbor" }
+
+// Encode serializes Facts and report.json content as one CBOR map
+// Layout: {"facts": Facts, "report": Report, "run_id": string}
+// Complexity: O(|Facts| + |Report|)
+func (e *CBOREncoder) Encode(p *Payload) ([]Artifact, error) {
+	if p == nil || p.Facts == nil {
+		return nil, fmt.Errorf("payload facts cannot be nil")
+	}
+
+	doc := cborDocument{RunID: p.RunID, Facts: p.Facts}
+	if p.Report != nil {
+		doc.Report = p.Report
+	}
+	data, err := MarshalCBOR(doc)
+	if err != nil {
+		return nil, err
+	}
+	return []Artifact{{Suffix: ".cbor", Data: data}}, nil
+}
@@ -0,0 +1,348 @@
+package export
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+	"github.com/minibeast/usb-agent/src/core/report"
+)
+
+// siemVendor and siemProduct fill the CEF/LEEF vendor and product header fields
+const (
+	siemVendor  = "MiniBeast"
+	siemProduct = "MiniBeast"
+)
+
+// siemEvent is a run summary or finding before CEF/LEEF rendering
+type siemEvent struct {
+	ID       string          // Signature/event ID (MB-RUN or finding ID)
+	Name     string          // Human-readable summary
+	Severity report.Severity // Mapped to the 0-10 CEF/LEEF scale
+	Category string          // "run" or "finding"
+	Fields   [][2]string     // Extension fields (CEF keys; LEEF keys via leefKeys)
+}
+
+// siemEvents flattens a payload into one run event plus one event per risk
+// Complexity: O(|risks|)
+func siemEvents(p *Payload) ([]siemEvent, error) {
+	if p == nil || p.Facts == nil {
+		return nil, fmt.Errorf("payload facts cannot be nil")
+	}
+
+	f := p.Facts
+	rt := strconv.FormatInt(f.Timestamp.UnixMilli(), 10)
+	findings := 0
+	if p.Report != nil {
+		findings = len(p.Report.Risks)
+	}
+
+	events := []siemEvent{{
+		ID:       "MB-RUN",
+		Name:     "MiniBeast collection completed",
+		Severity: report.SeverityInfo,
+		Category: "run",
+		Fields: [][2]string{
+			{"rt", rt},
+			{"dhost", f.Hostname},
+			{"deviceExternalId", f.HardwareUUID},
+			{"cs1Label", "runId"}, {"cs1", p.RunID},
+			{"cs4Label", "os"}, {"cs4", strings.TrimSpace(f.OSName + " " + f.OSVersion)},
+			{"cnt", strconv.Itoa(findings)},
+		},
+	}}
+
+	if p.Report != nil {
+		for _, risk := range p.Report.Risks {
+			id := risk.FindingID
+			if id == "" {
+				id = "MB-FINDING"
+			}
+			events = append(events, siemEvent{
+				ID:       id,
+				Name:     risk.Text,
+				Severity: risk.Severity,
+				Category: "finding",
+				Fields: [][2]string{
+					{"rt", rt},
+					{"dhost", f.Hostname},
+					{"deviceExternalId", f.HardwareUUID},
+					{"cs1Label", "runId"}, {"cs1", p.RunID},
+					{"cs2Label", "confidence"}, {"cs2", string(risk.Confidence)},
+					{"cs3Label", "findingId"}, {"cs3", risk.FindingID},
+					{"msg", risk.Text},
+				},
+			})
+		}
+	}
+
+	return events, nil
+}
+
+// factEvents flattens the collected facts into one informational event per
+// host, local user, volume, network interface and listening port
+// Software and processes are left out; they run to thousands of entries.
+// Complexity: O(|users| + |volumes| + |interfaces| + |ports|)
+func factEvents(p *Payload) []siemEvent {
+	f := p.Facts
+	rt := strconv.FormatInt(f.Timestamp.UnixMilli(), 10)
+	event := func(id, name, category string, fields ...[2]string) siemEvent {
+		return siemEvent{
+			ID:       id,
+			Name:     name,
+			Severity: report.SeverityInfo,
+			Category: category,
+			Fields: append([][2]string{
+				{"rt", rt},
+				{"dhost", f.Hostname},
+				{"deviceExternalId", f.HardwareUUID},
+				{"cs1Label", "runId"}, {"cs1", p.RunID},
+			}, fields...),
+		}
+	}
+
+	events := []siemEvent{event("MB-HOST", "Host "+f.Hostname, "host",
+		[2]string{"cs4Label", "os"}, [2]string{"cs4", strings.TrimSpace(f.OSName + " " + f.OSVersion)},
+		[2]string{"cs5Label", "serialNumber"}, [2]string{"cs5", f.SerialNumber},
+		[2]string{"cs6Label", "timezone"}, [2]string{"cs6", f.Timezone},
+	)}
+	for _, u := range f.Users {
+		events = append(events, event("MB-USER", "Local user "+u.Username, "user",
+			[2]string{"duser", u.Username},
+			[2]string{"duid", u.UID},
+			[2]string{"cs5Label", "admin"}, [2]string{"cs5", strconv.FormatBool(u.Admin)},
+		))
+	}
+	for _, v := range f.Volumes {
+		events = append(events, event("MB-VOLUME", "Volume "+v.MountPoint, "volume",
+			[2]string{"filePath", v.MountPoint},
+			[2]string{"cs5Label", "encryption"}, [2]string{"cs5", v.Encryption},
+			[2]string{"cs6Label", "filesystem"}, [2]string{"cs6", v.FileSystem},
+			[2]string{"cn1Label", "totalBytes"}, [2]string{"cn1", strconv.FormatInt(v.TotalBytes, 10)},
+		))
+	}
+	for _, iface := range networkInterfaces(f.LocalIPs, f.MACAddresses) {
+		events = append(events, event("MB-INTERFACE", "Network interface "+iface.Name, "interface",
+			[2]string{"cs5Label", "interface"}, [2]string{"cs5", iface.Name},
+			[2]string{"cs6Label", "ipAddress"}, [2]string{"cs6", iface.IPAddress},
+			[2]string{"dvcmac", iface.MACAddress},
+		))
+	}
+	for _, port := range f.ListeningPorts {
+		pid := ""
+		if port.PID > 0 {
+			pid = strconv.Itoa(port.PID)
+		}
+		events = append(events, event("MB-PORT", fmt.Sprintf("Listening port %s/%d", port.Protocol, port.Port), "port",
+			[2]string{"proto", port.Protocol},
+			[2]string{"dpt", strconv.Itoa(port.Port)},
+			[2]string{"cs5Label", "address"}, [2]string{"cs5", port.Address},
+			[2]string{"dpid", pid},
+			[2]string{"dproc", port.Process},
+		))
+	}
+	return events
+}
+
+// networkInterfaces merges the IP and MAC lists by interface name, keeping
+// the IP list's order, then interfaces that only have a MAC
+// Complexity: O(|ips| + |macs|)
+func networkInterfaces(ips, macs []types.NetworkInterface) []types.NetworkInterface {
+	mac := make(map[string]string, len(macs))
+	for _, m := range macs {
+		mac[m.Name] = m.MACAddress
+	}
+	merged := make([]types.NetworkInterface, 0, len(ips)+len(macs))
+	seen := make(map[string]bool, len(ips))
+	for _, i := range ips {
+		if i.MACAddress == "" {
+			i.MACAddress = mac[i.Name]
+		}
+		merged = append(merged, i)
+		seen[i.Name] = true
+	}
+	for _, m := range macs {
+		if !seen[m.Name] {
+			merged = append(merged, m)
+		}
+	}
+	return merged
+}
+
+// siemSeverity maps report severities onto the 0-10 CEF/LEEF scale
+func siemSeverity(s report.Severity) int {
+	switch s {
+	case report.SeverityCritical:
+		return 10
+	case report.SeverityHigh:
+		return 8
+	case report.SeverityMedium:
+		return 5
+	case report.SeverityLow:
+		return 3
+	default:
+		return 1
+	}
+}
+
+// CEFEvents renders the payload as ArcSight CEF:0 lines
+// Mathematical property: Same Payload → Same lines
+// Complexity: O(|risks|)
+func CEFEvents(p *Payload, version string) ([]string, error) {
+	events, err := siemEvents(p)
+	if err != nil {
+		return nil, err
+	}
+	return renderCEF(events, version), nil
+}
+
+// renderCEF renders one CEF:0 line per event
+// Complexity: O(|events|)
+func renderCEF(events []siemEvent, version string) []string {
+	lines := make([]string, 0, len(events))
+	for _, ev := range events {
+		var ext []string
+		for _, kv := range ev.Fields {
+			if kv[1] != "" {
+				ext = append(ext, kv[0]+"="+cefExtension(kv[1]))
+			}
+		}
+		lines = append(lines, fmt.Sprintf("CEF:0|%s|%s|%s|%s|%s|%d|%s",
+			cefHeader(siemVendor), cefHeader(siemProduct), cefHeader(version),
+			cefHeader(ev.ID), cefHeader(ev.Name), siemSeverity(ev.Severity), strings.Join(ext, " ")))
+	}
+	return lines
+}
+
+// leefKeys renames CEF extension keys to LEEF attributes (unlisted keys are
+// dropped); custom csN/cnN fields are named by their csNLabel/cnNLabel
+var leefKeys = map[string]string{
+	"dhost":            "identHostName",
+	"deviceExternalId": "hardwareUuid",
+	"cnt":              "findings",
+	"msg":              "msg",
+	"duser":            "usrName",
+	"duid":             "userId",
+	"filePath":         "filePath",
+	"dvcmac":           "macAddress",
+	"proto":            "proto",
+	"dpt":              "dstPort",
+	"dpid":             "pid",
+	"dproc":            "process",
+}
+
+// siemAttributes returns an event's fields under their LEEF names, without
+// the event time and empty values
+// Complexity: O(|Fields|)
+func siemAttributes(ev siemEvent) [][2]string {
+	labels := map[string]string{}
+	var attrs [][2]string
+	for _, kv := range ev.Fields {
+		if field, ok := strings.CutSuffix(kv[0], "Label"); ok {
+			labels[field] = kv[1]
+			continue
+		}
+		key, ok := leefKeys[kv[0]]
+		if label, custom := labels[kv[0]]; custom {
+			key, ok = label, true
+		}
+		if ok && kv[1] != "" {
+			attrs = append(attrs, [2]string{key, kv[1]})
+		}
+	}
+	return attrs
+}
+
+// LEEFEvents renders the payload as QRadar LEEF:1.0 lines (tab-delimited attributes)
+// Mathematical property: Same Payload → Same lines
+// Complexity: O(|risks|)
+func LEEFEvents(p *Payload, version string) ([]string, error) {
+	events, err := siemEvents(p)
+	if err != nil {
+		return nil, err
+	}
+	return renderLEEF(events, version), nil
+}
+
+// renderLEEF renders one LEEF:1.0 line per event
+// Complexity: O(|events|)
+func renderLEEF(events []siemEvent, version string) []string {
+	lines := make([]string, 0, len(events))
+	for _, ev := range events {
+		attrs := []string{
+			"cat=" + ev.Category,
+			"sev=" + strconv.Itoa(siemSeverity(ev.Severity)),
+			"devTimeFormat=epoch_millis",
+		}
+		for _, kv := range ev.Fields {
+			if kv[0] == "rt" {
+				attrs = append(attrs, "devTime="+kv[1])
+			}
+		}
+		for _, kv := range siemAttributes(ev) {
+			attrs = append(attrs, kv[0]+"="+leefValue(kv[1]))
+		}
+		lines = append(lines, fmt.Sprintf("LEEF:1.0|%s|%s|%s|%s|%s",
+			leefHeader(siemVendor), leefHeader(siemProduct), leefHeader(version), leefHeader(ev.ID), strings.Join(attrs, "\t")))
+	}
+	return lines
+}
+
+// cefHeader escapes '\' and '|' in CEF header fields
+func cefHeader(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\r", " ", "\n", " ").Replace(v)
+}
+
+// cefExtension escapes '\', '=' and newlines in CEF extension values
+func cefExtension(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\r", `\r`, "\n", `\n`).Replace(v)
+}
+
+// leefHeader strips the header delimiter and line breaks
+func leefHeader(v string) string {
+	return strings.NewReplacer(`|`, " ", "\r", " ", "\n", " ").Replace(v)
+}
+
+// leefValue strips the attribute delimiter (tab) and line breaks
+func leefValue(v string) string {
+	return strings.NewReplacer("\t", " ", "\r", " ", "\n", " ").Replace(v)
+}
+
+// SIEMEncoder implements Encoder for CEF or LEEF line files
+type SIEMEncoder struct {
+	format string // "cef" or "leef"
+}
+
+// NewCEFEncoder creates an encoder writing <base>.cef.log
+// Complexity: O(1)
+func NewCEFEncoder() *SIEMEncoder {
+	return &SIEMEncoder{format: "cef"}
+}
+
+// NewLEEFEncoder creates an encoder writing <base>.leef.log
+// Complexity: O(1)
+func NewLEEFEncoder() *SIEMEncoder {
+	return &SIEMEncoder{format: "leef"}
+}
+
+// Name returns "cef" or "leef"
+func (e *SIEMEncoder) Name() string { return e.format }
+
+// Encode renders one event per line
+// Complexity: O(|risks|)
+func (e *SIEMEncoder) Encode(p *Payload) ([]Artifact, error) {
+	if p == nil || p.Facts == nil {
+		return nil, fmt.Errorf("payload facts cannot be nil")
+	}
+
+	render := CEFEvents
+	if e.format == "leef" {
+		render = LEEFEvents
+	}
+	lines, err := render(p, p.Facts.CollectorVersion)
+	if err != nil {
+		return nil, err
+	}
+	return []Artifact{{Suffix: "." + e.format + ".log", Data: []byte(strings.Join(lines, "\n") + "\n")}}, nil
+}
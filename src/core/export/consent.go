@@ -0,0 +1,36 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ConsentSuffix names the operator acknowledgment artifact
+const ConsentSuffix = ".consent.json"
+
+// ConsentEncoder implements Encoder for the operator acknowledgment record
+// It emits nothing for runs without a Payload.Consent, so it is always
+// registered by EncodersForConfig rather than selected via output.formats.
+type ConsentEncoder struct{}
+
+// NewConsentEncoder creates a consent encoder
+// Complexity: O(1)
+func NewConsentEncoder() *ConsentEncoder {
+	return &ConsentEncoder{}
+}
+
+// Name returns "consent"
+func (e *ConsentEncoder) Name() string { return "consent" }
+
+// Encode serializes the consent record as indented JSON
+// Complexity: O(|Consent|)
+func (e *ConsentEncoder) Encode(p *Payload) ([]Artifact, error) {
+	if p == nil || p.Consent == nil {
+		return nil, nil
+	}
+	data, err := json.MarshalIndent(p.Consent, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal consent: %w", err)
+	}
+	return []Artifact{{Suffix: ConsentSuffix, Data: data}}, nil
+}
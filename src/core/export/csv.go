@@ -0,0 +1,257 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CSVColumn documents a single CSV column
+type CSVColumn struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// CSVTable documents one CSV artifact
+// Column order is part of the published contract and must never change;
+// new columns are only ever appended.
+type CSVTable struct {
+	File        string      `json:"file"` // Artifact suffix without the leading dot
+	Description string      `json:"description"`
+	Columns     []CSVColumn `json:"columns"`
+}
+
+// commonColumns prefix every CSV table so files can be joined across runs
+var commonColumns = []CSVColumn{
+	{Name: "run_id", Description: "Run identifier"},
+	{Name: "hostname", Description: "Collected hostname"},
+}
+
+// CSVTables is the stable column layout of every CSV artifact
+var CSVTables = []CSVTable{
+	{
+		File:        "users.csv",
+		Description: "Local user accounts, sorted by username",
+		Columns: append(append([]CSVColumn{}, commonColumns...),
+			CSVColumn{Name: "username", Description: "Account name"},
+			CSVColumn{Name: "full_name", Description: "Display name (may be empty)"},
+			CSVColumn{Name: "uid", Description: "Unix UID or Windows SID"},
+		),
+	},
+	{
+		File:        "interfaces.csv",
+		Description: "Network interfaces, sorted by name",
+		Columns: append(append([]CSVColumn{}, commonColumns...),
+			CSVColumn{Name: "name", Description: "Interface name"},
+			CSVColumn{Name: "ip_address", Description: "Primary IP address or \"unknown\""},
+			CSVColumn{Name: "mac_address", Description: "MAC address or \"unknown\""},
+		),
+	},
+	{
+		File:        "wifi.csv",
+		Description: "Known WiFi SSIDs, sorted",
+		Columns: append(append([]CSVColumn{}, commonColumns...),
+			CSVColumn{Name: "ssid", Description: "Network name"},
+		),
+	},
+	{
+		File:        "findings.csv",
+		Description: "Report risks in report order (empty when the LLM phase did not run)",
+		Columns: append(append([]CSVColumn{}, commonColumns...),
+			CSVColumn{Name: "finding_id", Description: "Rule ID (empty for model findings)"},
+			CSVColumn{Name: "severity", Description: "INFO, LOW, MEDIUM, HIGH or CRITICAL"},
+			CSVColumn{Name: "confidence", Description: "grounded or inferred"},
+			CSVColumn{Name: "text", Description: "Risk description"},
+		),
+	},
+	{
+		File:        "software.csv",
+		Description: "Installed software, sorted by name then version",
+		Columns: append(append([]CSVColumn{}, commonColumns...),
+			CSVColumn{Name: "name", Description: "Program or package name"},
+			CSVColumn{Name: "version", Description: "Installed version (may be empty)"},
+			CSVColumn{Name: "publisher", Description: "Publisher, vendor or maintainer (may be empty)"},
+			CSVColumn{Name: "install_date", Description: "YYYY-MM-DD (may be empty)"},
+			CSVColumn{Name: "source", Description: "dpkg, rpm, registry, app or pkgutil"},
+		),
+	},
+	{
+		File:        "processes.csv",
+		Description: "Running processes, sorted by PID (empty unless collect.processes is set)",
+		Columns: append(append([]CSVColumn{}, commonColumns...),
+			CSVColumn{Name: "pid", Description: "Process ID"},
+			CSVColumn{Name: "name", Description: "Process name"},
+			CSVColumn{Name: "path", Description: "Executable path (may be empty)"},
+			CSVColumn{Name: "user", Description: "Owning account (may be empty)"},
+		),
+	},
+	{
+		File:        "volumes.csv",
+		Description: "Mounted volumes, sorted by mount point",
+		Columns: append(append([]CSVColumn{}, commonColumns...),
+			CSVColumn{Name: "mount_point", Description: "Mount path or drive letter"},
+			CSVColumn{Name: "device", Description: "Block device (empty on Windows)"},
+			CSVColumn{Name: "filesystem", Description: "Filesystem type (may be empty)"},
+			CSVColumn{Name: "total_bytes", Description: "Capacity in bytes"},
+			CSVColumn{Name: "free_bytes", Description: "Free space in bytes"},
+			CSVColumn{Name: "encryption", Description: "luks, filevault, bitlocker, none or unknown"},
+		),
+	},
+	{
+		File:        "ports.csv",
+		Description: "Listening sockets, sorted by protocol, port, then address",
+		Columns: append(append([]CSVColumn{}, commonColumns...),
+			CSVColumn{Name: "protocol", Description: "tcp or udp"},
+			CSVColumn{Name: "address", Description: "Bound address (0.0.0.0 or :: for every interface)"},
+			CSVColumn{Name: "port", Description: "Port number"},
+			CSVColumn{Name: "pid", Description: "Owning process ID (empty when unreadable)"},
+			CSVColumn{Name: "process", Description: "Owning process name (may be empty)"},
+		),
+	},
+	{
+		File:        "security.csv",
+		Description: "Antivirus and EDR products, sorted by name",
+		Columns: append(append([]CSVColumn{}, commonColumns...),
+			CSVColumn{Name: "name", Description: "Product name"},
+			CSVColumn{Name: "kind", Description: "antivirus or edr"},
+			CSVColumn{Name: "version", Description: "Product version (may be empty)"},
+			CSVColumn{Name: "real_time_protection", Description: "on, off or unknown"},
+		),
+	},
+	{
+		File:        "updates.csv",
+		Description: "Installed updates and hotfixes, newest first",
+		Columns: append(append([]CSVColumn{}, commonColumns...),
+			CSVColumn{Name: "id", Description: "KB number or update name"},
+			CSVColumn{Name: "title", Description: "Description or version (may be empty)"},
+			CSVColumn{Name: "installed_on", Description: "Install date, YYYY-MM-DD (may be empty)"},
+		),
+	},
+}
+
+// CSVEncoder implements Encoder for the tabular Facts sections
+type CSVEncoder struct{}
+
+// NewCSVEncoder creates a CSV encoder
+// Complexity: O(1)
+func NewCSVEncoder() *CSVEncoder {
+	return &CSVEncoder{}
+}
+
+// Name returns "csv"
+func (e *CSVEncoder) Name() string { return "csv" }
+
+// Encode produces users.csv, interfaces.csv, wifi.csv, findings.csv,
+// software.csv, processes.csv, volumes.csv, ports.csv, security.csv and
+// updates.csv
+// Every file has a header row matching CSVTables, even when empty
+// Complexity: O(|Facts| + |risks|)
+func (e *CSVEncoder) Encode(p *Payload) ([]Artifact, error) {
+	if p == nil || p.Facts == nil {
+		return nil, fmt.Errorf("payload facts cannot be nil")
+	}
+
+	rows := tableRows(p)
+	artifacts := make([]Artifact, 0, len(CSVTables))
+	for _, table := range CSVTables {
+		data, err := encodeCSV(table, rows[table.File])
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", table.File, err)
+		}
+		artifacts = append(artifacts, Artifact{Suffix: "." + table.File, Data: data})
+	}
+	return artifacts, nil
+}
+
+// tableRows flattens the payload into string rows keyed by CSVTable.File
+// Shared by the CSV and Parquet encoders so both carry identical columns
+// Complexity: O(|Facts| + |risks|)
+func tableRows(p *Payload) map[string][][]string {
+	f := p.Facts
+	prefix := []string{p.RunID, f.Hostname}
+	rows := make(map[string][][]string, len(CSVTables))
+
+	for _, u := range f.Users {
+		rows["users.csv"] = append(rows["users.csv"], append(append([]string{}, prefix...), u.Username, u.FullName, u.UID))
+	}
+	for _, iface := range f.LocalIPs {
+		rows["interfaces.csv"] = append(rows["interfaces.csv"], append(append([]string{}, prefix...), iface.Name, iface.IPAddress, iface.MACAddress))
+	}
+	for _, ssid := range f.WiFiSSIDs {
+		rows["wifi.csv"] = append(rows["wifi.csv"], append(append([]string{}, prefix...), ssid))
+	}
+	for _, sw := range f.Software {
+		rows["software.csv"] = append(rows["software.csv"], append(append([]string{}, prefix...), sw.Name, sw.Version, sw.Publisher, sw.InstallDate, sw.Source))
+	}
+	for _, proc := range f.Processes {
+		rows["processes.csv"] = append(rows["processes.csv"], append(append([]string{}, prefix...), strconv.Itoa(proc.PID), proc.Name, proc.Path, proc.User))
+	}
+	for _, v := range f.Volumes {
+		rows["volumes.csv"] = append(rows["volumes.csv"], append(append([]string{}, prefix...),
+			v.MountPoint, v.Device, v.FileSystem, strconv.FormatInt(v.TotalBytes, 10), strconv.FormatInt(v.FreeBytes, 10), v.Encryption))
+	}
+	for _, port := range f.ListeningPorts {
+		pid := ""
+		if port.PID != 0 {
+			pid = strconv.Itoa(port.PID)
+		}
+		rows["ports.csv"] = append(rows["ports.csv"], append(append([]string{}, prefix...),
+			port.Protocol, port.Address, strconv.Itoa(port.Port), pid, port.Process))
+	}
+	for _, product := range f.SecurityProducts {
+		rows["security.csv"] = append(rows["security.csv"], append(append([]string{}, prefix...),
+			product.Name, product.Kind, product.Version, product.RealTime))
+	}
+	for _, update := range f.InstalledUpdates {
+		rows["updates.csv"] = append(rows["updates.csv"], append(append([]string{}, prefix...),
+			update.ID, update.Title, update.InstalledOn))
+	}
+	if p.Report != nil {
+		for _, risk := range p.Report.Risks {
+			rows["findings.csv"] = append(rows["findings.csv"], append(append([]string{}, prefix...),
+				risk.FindingID, risk.Severity.String(), string(risk.Confidence), risk.Text))
+		}
+	}
+	return rows
+}
+
+// encodeCSV writes a header row followed by sanitized data rows
+func encodeCSV(table CSVTable, rows [][]string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	header := make([]string, len(table.Columns))
+	for i, col := range table.Columns {
+		header[i] = col.Name
+	}
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		for i := range row {
+			row[i] = sanitizeCSVCell(row[i])
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// sanitizeCSVCell neutralizes spreadsheet formula injection
+// Collected values such as SSIDs are attacker-controllable; a leading
+// =, +, -, @, tab or carriage return would be evaluated by Excel.
+func sanitizeCSVCell(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
@@ -0,0 +1,329 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+)
+
+// elasticRetryBase is the initial backoff between bulk retries
+const elasticRetryBase = 500 * time.Millisecond
+
+// factsTemplateMappings maps the identifiers in facts documents as keywords
+var factsTemplateMappings = map[string]interface{}{
+	"properties": map[string]interface{}{
+		"@timestamp":             map[string]string{"type": "date"},
+		"timestamp":              map[string]string{"type": "date"},
+		"run_id":                 map[string]string{"type": "keyword"},
+		"hostname":               map[string]string{"type": "keyword"},
+		"computer_name":          map[string]string{"type": "keyword"},
+		"machine_owner":          map[string]string{"type": "keyword"},
+		"serial_number":          map[string]string{"type": "keyword"},
+		"hardware_uuid":          map[string]string{"type": "keyword"},
+		"os_name":                map[string]string{"type": "keyword"},
+		"os_version":             map[string]string{"type": "keyword"},
+		"os_build":               map[string]string{"type": "keyword"},
+		"timezone":               map[string]string{"type": "keyword"},
+		"collector_version":      map[string]string{"type": "keyword"},
+		"collection_duration_ms": map[string]string{"type": "long"},
+		"wifi_known_ssids":       map[string]string{"type": "keyword"},
+		"logged_in_users":        map[string]string{"type": "keyword"},
+		"software": map[string]interface{}{
+			"properties": map[string]interface{}{
+				"name":         map[string]string{"type": "keyword"},
+				"version":      map[string]string{"type": "keyword"},
+				"publisher":    map[string]string{"type": "keyword"},
+				"install_date": map[string]string{"type": "date"},
+				"source":       map[string]string{"type": "keyword"},
+			},
+		},
+		"processes": map[string]interface{}{
+			"properties": map[string]interface{}{
+				"pid":  map[string]string{"type": "long"},
+				"name": map[string]string{"type": "keyword"},
+				"path": map[string]string{"type": "keyword"},
+				"user": map[string]string{"type": "keyword"},
+			},
+		},
+		"volumes": map[string]interface{}{
+			"properties": map[string]interface{}{
+				"mount_point": map[string]string{"type": "keyword"},
+				"device":      map[string]string{"type": "keyword"},
+				"filesystem":  map[string]string{"type": "keyword"},
+				"total_bytes": map[string]string{"type": "long"},
+				"free_bytes":  map[string]string{"type": "long"},
+				"encryption":  map[string]string{"type": "keyword"},
+			},
+		},
+		"listening_ports": map[string]interface{}{
+			"properties": map[string]interface{}{
+				"protocol": map[string]string{"type": "keyword"},
+				"address":  map[string]string{"type": "ip"},
+				"port":     map[string]string{"type": "integer"},
+				"pid":      map[string]string{"type": "long"},
+				"process":  map[string]string{"type": "keyword"},
+			},
+		},
+		"security_products": map[string]interface{}{
+			"properties": map[string]interface{}{
+				"name":                 map[string]string{"type": "keyword"},
+				"kind":                 map[string]string{"type": "keyword"},
+				"version":              map[string]string{"type": "keyword"},
+				"real_time_protection": map[string]string{"type": "keyword"},
+			},
+		},
+		"installed_updates": map[string]interface{}{
+			"properties": map[string]interface{}{
+				"id":           map[string]string{"type": "keyword"},
+				"title":        map[string]string{"type": "keyword"},
+				"installed_on": map[string]string{"type": "date"},
+			},
+		},
+		"last_update":     map[string]string{"type": "date"},
+		"pending_updates": map[string]string{"type": "integer"},
+	},
+}
+
+// findingsTemplateMappings maps finding documents
+var findingsTemplateMappings = map[string]interface{}{
+	"properties": map[string]interface{}{
+		"@timestamp": map[string]string{"type": "date"},
+		"run_id":     map[string]string{"type": "keyword"},
+		"hostname":   map[string]string{"type": "keyword"},
+		"finding_id": map[string]string{"type": "keyword"},
+		"severity":   map[string]string{"type": "keyword"},
+		"confidence": map[string]string{"type": "keyword"},
+		"text":       map[string]string{"type": "text"},
+	},
+}
+
+// ElasticExporter indexes facts and findings via the Elasticsearch/OpenSearch bulk API
+// One facts document per run (_id = run ID) and one document per finding
+// (_id = run ID + index), so retried deliveries overwrite instead of duplicating.
+type ElasticExporter struct {
+	cfg    config.ElasticConfig
+	client *http.Client
+	base   string
+}
+
+// NewElasticExporter creates a bulk exporter
+// Complexity: O(1) (plus CA bundle parsing)
+func NewElasticExporter(cfg config.ElasticConfig) (*ElasticExporter, error) {
+	pool, err := loadCAPool(cfg.CAFile)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+
+	return &ElasticExporter{
+		cfg:    cfg,
+		client: &http.Client{Transport: transport, Timeout: time.Duration(cfg.TimeoutMs) * time.Millisecond},
+		base:   strings.TrimRight(cfg.URL, "/"),
+	}, nil
+}
+
+// Name returns "elasticsearch"
+func (e *ElasticExporter) Name() string { return "elasticsearch" }
+
+// Export installs index templates (when enabled) and sends one bulk request
+// Complexity: O(|Facts| + |risks|)
+func (e *ElasticExporter) Export(ctx context.Context, p *Payload) error {
+	body, err := e.BulkBody(p)
+	if err != nil {
+		return err
+	}
+
+	if e.cfg.InstallTemplates {
+		if err := e.installTemplates(ctx); err != nil {
+			return err
+		}
+	}
+
+	return withRetry(ctx, e.cfg.MaxRetries, elasticRetryBase, func() error {
+		return e.bulk(ctx, body)
+	})
+}
+
+// BulkBody encodes the payload as a bulk API NDJSON body
+// Mathematical property: Same Payload → Same body
+// Complexity: O(|Facts| + |risks|)
+func (e *ElasticExporter) BulkBody(p *Payload) ([]byte, error) {
+	if p == nil || p.Facts == nil {
+		return nil, fmt.Errorf("payload facts cannot be nil")
+	}
+
+	f := p.Facts
+	docID := p.RunID
+	if docID == "" {
+		docID = f.Hostname + "_" + f.HardwareUUID + "_" + strconv.FormatInt(f.Timestamp.Unix(), 10)
+	}
+	timestamp := f.Timestamp.UTC().Format(time.RFC3339Nano)
+
+	// Facts document: Facts JSON plus run metadata
+	data, err := json.Marshal(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal facts: %w", err)
+	}
+	factsDoc := map[string]interface{}{}
+	if err := json.Unmarshal(data, &factsDoc); err != nil {
+		return nil, fmt.Errorf("failed to map facts: %w", err)
+	}
+	factsDoc["@timestamp"] = timestamp
+	factsDoc["run_id"] = p.RunID
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	write := func(index, id string, doc interface{}) error {
+		action := map[string]map[string]string{"index": {"_index": index, "_id": id}}
+		if err := enc.Encode(action); err != nil {
+			return err
+		}
+		return enc.Encode(doc)
+	}
+
+	if err := write(e.cfg.FactsIndex, docID, factsDoc); err != nil {
+		return nil, fmt.Errorf("failed to encode facts document: %w", err)
+	}
+
+	if p.Report != nil {
+		for i, risk := range p.Report.Risks {
+			doc := map[string]interface{}{
+				"@timestamp": timestamp,
+				"run_id":     p.RunID,
+				"hostname":   f.Hostname,
+				"finding_id": risk.FindingID,
+				"severity":   risk.Severity.String(),
+				"confidence": string(risk.Confidence),
+				"text":       risk.Text,
+			}
+			if err := write(e.cfg.FindingsIndex, docID+"-"+strconv.Itoa(i), doc); err != nil {
+				return nil, fmt.Errorf("failed to encode finding document: %w", err)
+			}
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// IndexTemplates returns the composable index templates keyed by template name
+func (e *ElasticExporter) IndexTemplates() map[string]interface{} {
+	template := func(index string, mappings map[string]interface{}) interface{} {
+		return map[string]interface{}{
+			"index_patterns": []string{index + "*"},
+			"template":       map[string]interface{}{"mappings": mappings},
+			"_meta":          map[string]string{"managed_by": "minibeast"},
+		}
+	}
+	return map[string]interface{}{
+		e.cfg.FactsIndex:    template(e.cfg.FactsIndex, factsTemplateMappings),
+		e.cfg.FindingsIndex: template(e.cfg.FindingsIndex, findingsTemplateMappings),
+	}
+}
+
+// installTemplates PUTs both index templates (idempotent)
+func (e *ElasticExporter) installTemplates(ctx context.Context) error {
+	for _, name := range []string{e.cfg.FactsIndex, e.cfg.FindingsIndex} {
+		body, err := json.Marshal(e.IndexTemplates()[name])
+		if err != nil {
+			return fmt.Errorf("failed to marshal index template: %w", err)
+		}
+
+		err = withRetry(ctx, e.cfg.MaxRetries, elasticRetryBase, func() error {
+			resp, err := e.do(ctx, http.MethodPut, "/_index_template/"+name, "application/json", body)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			io.Copy(io.Discard, resp.Body)
+			return classifyHTTP(resp)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to install index template %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// bulkResponse is the subset of the bulk API response we inspect
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int `json:"status"`
+		Error  struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// bulk sends the NDJSON body and checks per-item results
+func (e *ElasticExporter) bulk(ctx context.Context, body []byte) error {
+	resp, err := e.do(ctx, http.MethodPost, "/_bulk", "application/x-ndjson", body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := classifyHTTP(resp); err != nil {
+		io.Copy(io.Discard, resp.Body)
+		return err
+	}
+
+	var result bulkResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode bulk response: %w", err)
+	}
+	if !result.Errors {
+		return nil
+	}
+
+	// Report the first failed item; 429 items are retryable
+	for _, item := range result.Items {
+		for _, status := range item {
+			if status.Status < 300 {
+				continue
+			}
+			itemErr := fmt.Errorf("bulk item failed (%d): %s: %s", status.Status, status.Error.Type, status.Error.Reason)
+			if status.Status == http.StatusTooManyRequests {
+				return &RetryableError{Err: itemErr}
+			}
+			return itemErr
+		}
+	}
+	return fmt.Errorf("bulk request reported errors")
+}
+
+// do performs an authenticated request
+func (e *ElasticExporter) do(ctx context.Context, method, path, contentType string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, e.base+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	switch {
+	case e.cfg.APIKey != "":
+		req.Header.Set("Authorization", "ApiKey "+e.cfg.APIKey)
+	case e.cfg.Username != "":
+		creds := base64.StdEncoding.EncodeToString([]byte(e.cfg.Username + ":" + e.cfg.Password))
+		req.Header.Set("Authorization", "Basic "+creds)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, &RetryableError{Err: err}
+	}
+	return resp, nil
+}
@@ -0,0 +1,106 @@
+package export
+
+import (
+	"crypto/ecdh"
+	"fmt"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/crypto"
+)
+
+// EncryptedSuffix is appended to every encrypted artifact and bundle file
+const EncryptedSuffix = ".mbe"
+
+// EncryptedEncoder seals every artifact of an inner encoder into a
+// multi-recipient envelope (crypto.Encrypt), one key slot per recipient
+type EncryptedEncoder struct {
+	inner      Encoder
+	recipients []*ecdh.PublicKey
+}
+
+// NewEncryptedEncoder wraps inner so its artifacts are encrypted to recipients
+// Complexity: O(1)
+func NewEncryptedEncoder(inner Encoder, recipients []*ecdh.PublicKey) *EncryptedEncoder {
+	return &EncryptedEncoder{inner: inner, recipients: recipients}
+}
+
+// Name returns the inner format name
+func (e *EncryptedEncoder) Name() string {
+	return e.inner.Name()
+}
+
+// Encode encodes with the inner encoder, then encrypts each artifact
+// Envelopes use fresh randomness, so unlike the inner encoders the
+// output bytes differ between calls; the decrypted artifacts do not.
+// Complexity: O(|artifacts| * |recipients|)
+func (e *EncryptedEncoder) Encode(p *Payload) ([]Artifact, error) {
+	artifacts, err := e.inner.Encode(p)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed := make([]Artifact, 0, len(artifacts))
+	for _, a := range artifacts {
+		data, err := crypto.Encrypt(a.Data, e.recipients)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt %s artifact: %w", e.inner.Name(), err)
+		}
+		sealed = append(sealed, Artifact{Suffix: a.Suffix + EncryptedSuffix, Data: data})
+	}
+	return sealed, nil
+}
+
+// EncryptBundle returns a copy of b with every file encrypted to recipients
+// Complexity: O(|Files| * |recipients|)
+func EncryptBundle(b *Bundle, recipients []*ecdh.PublicKey) (*Bundle, error) {
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+
+	sealed := &Bundle{Name: b.Name}
+	for _, f := range b.Files {
+		data, err := crypto.Encrypt(f.Data, recipients)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt %s: %w", f.Name, err)
+		}
+		sealed.Add(f.Name+EncryptedSuffix, data)
+	}
+	return sealed, nil
+}
+
+// RecipientsFor loads output.recipient_keys when output.encrypt is enabled
+// Returns nil (no encryption) when encryption is disabled.
+// Complexity: O(|recipient_keys|)
+func RecipientsFor(cfg *config.Config) ([]*ecdh.PublicKey, error) {
+	if cfg == nil || !cfg.Output.Encrypt {
+		return nil, nil
+	}
+	return crypto.LoadRecipientPublicKeys(cfg.Output.RecipientKeys)
+}
+
+// EncodersForConfig resolves output.formats plus the consent record,
+// encrypting every artifact when output.encrypt is set
+// Complexity: O(|formats| + |recipient_keys|)
+func EncodersForConfig(cfg *config.Config) ([]Encoder, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+
+	encs, err := EncodersFor(cfg.Output.Formats)
+	if err != nil {
+		return nil, err
+	}
+	encs = append(encs, NewConsentEncoder())
+	recipients, err := RecipientsFor(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if recipients == nil {
+		return encs, nil
+	}
+
+	for i, enc := range encs {
+		encs[i] = NewEncryptedEncoder(enc, recipients)
+	}
+	return encs, nil
+}
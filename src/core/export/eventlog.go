@@ -0,0 +1,82 @@
+package export
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+)
+
+// EventLogExporter writes one run summary event to the Windows Application log
+// The message is a "key=value" block so SIEM agents (WEF, Splunk UF,
+// Winlogbeat) can extract fields without a custom parser.
+type EventLogExporter struct {
+	cfg config.EventLogConfig
+}
+
+// NewEventLogExporter creates an event log exporter
+// Complexity: O(1)
+func NewEventLogExporter(cfg config.EventLogConfig) (*EventLogExporter, error) {
+	if !EventLogSupported {
+		return nil, fmt.Errorf("event log is only available on Windows")
+	}
+	return &EventLogExporter{cfg: cfg}, nil
+}
+
+// Name returns "eventlog"
+func (e *EventLogExporter) Name() string {
+	return "eventlog"
+}
+
+// Export writes the run summary event
+// Runs with findings are logged as warnings, clean runs as information.
+func (e *EventLogExporter) Export(ctx context.Context, p *Payload) error {
+	msg, findings, err := EventLogMessage(p)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return writeEventLog(e.cfg.Source, uint32(e.cfg.EventID), findings > 0, msg)
+}
+
+// EventLogMessage renders the event text and returns the findings count
+// Mathematical property: Same Payload → Same message
+// Complexity: O(|Facts| + |Risks|)
+func EventLogMessage(p *Payload) (string, int, error) {
+	if p == nil || p.Facts == nil {
+		return "", 0, fmt.Errorf("payload facts cannot be nil")
+	}
+
+	factsJSON, err := marshalFacts(p.Facts)
+	if err != nil {
+		return "", 0, err
+	}
+	sum := sha256.Sum256(factsJSON)
+
+	findings, risks := 0, 0
+	if p.Report != nil {
+		risks = len(p.Report.Risks)
+		for _, r := range p.Report.Risks {
+			if r.FindingID != "" {
+				findings++
+			}
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("MiniBeast collection completed\r\n\r\n")
+	fmt.Fprintf(&b, "run_id=%s\r\n", p.RunID)
+	fmt.Fprintf(&b, "hostname=%s\r\n", p.Facts.Hostname)
+	fmt.Fprintf(&b, "hardware_uuid=%s\r\n", p.Facts.HardwareUUID)
+	fmt.Fprintf(&b, "timestamp=%s\r\n", p.Facts.Timestamp.UTC().Format("2006-01-02T15:04:05Z"))
+	fmt.Fprintf(&b, "facts_sha256=%s\r\n", hex.EncodeToString(sum[:]))
+	fmt.Fprintf(&b, "report=%t\r\n", p.Report != nil)
+	fmt.Fprintf(&b, "risks=%d\r\n", risks)
+	fmt.Fprintf(&b, "findings=%d\r\n", findings)
+	return b.String(), findings, nil
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package export
+
+import "fmt"
+
+// EventLogSupported reports whether this build can write to the event log
+const EventLogSupported = false
+
+// writeEventLog is unavailable outside Windows
+func writeEventLog(source string, eventID uint32, warning bool, msg string) error {
+	return fmt.Errorf("event log is only available on Windows")
+}
@@ -0,0 +1,36 @@
+//go:build windows
+
+package export
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/windows/svc/eventlog"
+)
+
+// EventLogSupported reports whether this build can write to the event log
+const EventLogSupported = true
+
+// writeEventLog reports msg under source, registering the source on first use
+// Registration needs administrator rights; unprivileged runs still log, but
+// Event Viewer prefixes the text with a "description cannot be found" note.
+func writeEventLog(source string, eventID uint32, warning bool, msg string) error {
+	// Best-effort: fails harmlessly when already registered or not elevated
+	_ = eventlog.InstallAsEventCreate(source, eventlog.Info|eventlog.Warning|eventlog.Error)
+
+	log, err := eventlog.Open(source)
+	if err != nil {
+		return fmt.Errorf("failed to open event log source %q: %w", source, err)
+	}
+	defer log.Close()
+
+	if warning {
+		err = log.Warning(eventID, msg)
+	} else {
+		err = log.Info(eventID, msg)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package export
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/plugin"
+)
+
+// Exporter delivers a run Payload to an external system
+// Contract: Export must honor ctx cancellation and never modify the payload
+type Exporter interface {
+	// Name identifies the exporter in logs and errors (e.g., "syslog")
+	Name() string
+
+	// Export delivers the payload
+	Export(ctx context.Context, p *Payload) error
+}
+
+// ExportersFor builds every enabled exporter from output.exporters
+// Complexity: O(|exporters|)
+func ExportersFor(cfg *config.Config) ([]Exporter, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+
+	exporters := []Exporter{}
+	ec := cfg.Output.Exporters
+
+	if ec.Syslog.Enabled {
+		exp, err := NewSyslogExporter(ec.Syslog)
+		if err != nil {
+			return nil, fmt.Errorf("syslog exporter: %w", err)
+		}
+		exporters = append(exporters, exp)
+	}
+
+	if ec.Splunk.Enabled {
+		exp, err := NewSplunkExporter(ec.Splunk)
+		if err != nil {
+			return nil, fmt.Errorf("splunk exporter: %w", err)
+		}
+		exporters = append(exporters, exp)
+	}
+
+	if ec.Elastic.Enabled {
+		exp, err := NewElasticExporter(ec.Elastic)
+		if err != nil {
+			return nil, fmt.Errorf("elasticsearch exporter: %w", err)
+		}
+		exporters = append(exporters, exp)
+	}
+
+	if ec.Webhook.Enabled {
+		exp, err := NewWebhookExporter(ec.Webhook)
+		if err != nil {
+			return nil, fmt.Errorf("webhook exporter: %w", err)
+		}
+		exporters = append(exporters, exp)
+	}
+
+	if ec.MQTT.Enabled {
+		exp, err := NewMQTTExporter(ec.MQTT)
+		if err != nil {
+			return nil, fmt.Errorf("mqtt exporter: %w", err)
+		}
+		exporters = append(exporters, exp)
+	}
+
+	if ec.SMTP.Enabled {
+		recipients, err := RecipientsFor(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("smtp exporter: %w", err)
+		}
+		exp, err := NewSMTPExporterWithRecipients(ec.SMTP, recipients)
+		if err != nil {
+			return nil, fmt.Errorf("smtp exporter: %w", err)
+		}
+		exporters = append(exporters, exp)
+	}
+
+	if ec.Kafka.Enabled {
+		exp, err := NewKafkaExporter(ec.Kafka)
+		if err != nil {
+			return nil, fmt.Errorf("kafka exporter: %w", err)
+		}
+		exporters = append(exporters, exp)
+	}
+
+	// The event log only exists on Windows; a shared config stays valid elsewhere
+	if ec.EventLog.Enabled && EventLogSupported {
+		exp, err := NewEventLogExporter(ec.EventLog)
+		if err != nil {
+			return nil, fmt.Errorf("eventlog exporter: %w", err)
+		}
+		exporters = append(exporters, exp)
+	}
+
+	// Partner exporters discovered in plugins.directory
+	plugins, err := plugin.Load(cfg.Plugins)
+	if err != nil {
+		return nil, fmt.Errorf("plugins: %w", err)
+	}
+	for _, p := range plugin.OfKind(plugins, plugin.KindExporter) {
+		exporters = append(exporters, NewPluginExporter(p))
+	}
+
+	return exporters, nil
+}
@@ -0,0 +1,239 @@
+package export
+
+import (
+	"context"
+	"crypto/ecdh"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/consent"
+	"github.com/minibeast/usb-agent/src/core/crypto"
+	"github.com/minibeast/usb-agent/src/core/report"
+)
+
+// payloadFile is the single file of a spooled exporter payload
+const payloadFile = "payload.json"
+
+// sealedPayloadFile replaces payloadFile when output.encrypt is on
+const sealedPayloadFile = payloadFile + EncryptedSuffix
+
+// ErrSealedPayload means a spooled payload needs a recipient private key to replay
+// Sync leaves such payloads queued instead of quarantining them.
+var ErrSealedPayload = errors.New("spooled payload is sealed to the output recipients (flush with -key)")
+
+// spooledPayload is the on-disk form of a Payload
+type spooledPayload struct {
+	RunID   string            `json:"run_id"`
+	Facts   *collection.Facts `json:"facts"`
+	Report  *report.Report    `json:"report,omitempty"`
+	Bundle  *Bundle           `json:"bundle,omitempty"`
+	Consent *consent.Record   `json:"consent,omitempty"`
+}
+
+// PayloadBundle encodes p as a spoolable bundle named after its run ID
+// With recipients the payload is sealed to them as payload.json.mbe, so
+// nothing readable is spooled when output.encrypt is on.
+// Complexity: O(|Facts| + |Report| + |Bundle|)
+func PayloadBundle(p *Payload, recipients []*ecdh.PublicKey) (*Bundle, error) {
+	if p == nil || p.Facts == nil {
+		return nil, fmt.Errorf("payload facts cannot be nil")
+	}
+
+	data, err := json.Marshal(spooledPayload{RunID: p.RunID, Facts: p.Facts, Report: p.Report, Bundle: p.Bundle, Consent: p.Consent})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	name := payloadFile
+	if len(recipients) > 0 {
+		if data, err = crypto.Encrypt(data, recipients); err != nil {
+			return nil, fmt.Errorf("failed to seal payload: %w", err)
+		}
+		name = sealedPayloadFile
+	}
+
+	b := &Bundle{Name: p.RunID}
+	b.Add(name, data)
+	if err := b.Validate(); err != nil {
+		return nil, fmt.Errorf("payload run ID is not spoolable: %w", err)
+	}
+	return b, nil
+}
+
+// DecodePayloadBundle restores a Payload spooled by PayloadBundle
+// key opens sealed payloads; without it they fail with ErrSealedPayload.
+// Complexity: O(bundle size)
+func DecodePayloadBundle(b *Bundle, key *ecdh.PrivateKey) (*Payload, error) {
+	for _, f := range b.Files {
+		data := f.Data
+		switch {
+		case f.Name == payloadFile:
+		case f.Name == sealedPayloadFile && key == nil:
+			return nil, fmt.Errorf("%s: %w", b.Name, ErrSealedPayload)
+		case f.Name == sealedPayloadFile:
+			plaintext, err := crypto.Decrypt(data, key)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open spooled payload %s: %w", b.Name, err)
+			}
+			data = plaintext
+		default:
+			continue
+		}
+		var sp spooledPayload
+		if err := json.Unmarshal(data, &sp); err != nil {
+			return nil, fmt.Errorf("failed to decode spooled payload %s: %w", b.Name, err)
+		}
+		if sp.Facts == nil {
+			return nil, fmt.Errorf("spooled payload %s has no facts", b.Name)
+		}
+		return &Payload{RunID: sp.RunID, Facts: sp.Facts, Report: sp.Report, Bundle: sp.Bundle, Consent: sp.Consent}, nil
+	}
+	return nil, fmt.Errorf("spooled bundle %s has no %s", b.Name, payloadFile)
+}
+
+// exporterQueue adapts an Exporter to the spool's Uploader contract
+type exporterQueue struct {
+	exp Exporter
+	key *ecdh.PrivateKey // Opens sealed payloads (nil leaves them queued)
+}
+
+// QueueFor returns an Uploader that replays spooled payloads through e
+// The queue shares e's name, so payloads wait under <spool>/<exporter>/.
+func QueueFor(e Exporter) Uploader {
+	return exporterQueue{exp: e}
+}
+
+// SealedQueueFor is QueueFor that also replays payloads sealed to key's public half
+func SealedQueueFor(e Exporter, key *ecdh.PrivateKey) Uploader {
+	return exporterQueue{exp: e, key: key}
+}
+
+// Name returns the exporter name
+func (q exporterQueue) Name() string {
+	return q.exp.Name()
+}
+
+// Upload decodes a spooled payload and exports it
+func (q exporterQueue) Upload(ctx context.Context, b *Bundle) error {
+	p, err := DecodePayloadBundle(b, q.key)
+	if err != nil {
+		return err
+	}
+	return q.exp.Export(ctx, p)
+}
+
+// DeliverPayload flushes e's queue, then exports p; on failure p is spooled
+// Same contract as Deliver: spooled=true with a nil error means p is queued.
+// A spooled p is sealed to recipients when there are any.
+// Complexity: O(queued payloads + 1) exports
+func (s *Spool) DeliverPayload(ctx context.Context, e Exporter, p *Payload, recipients []*ecdh.PublicKey) (spooled bool, err error) {
+	b, err := PayloadBundle(p, recipients)
+	if err != nil {
+		return false, err
+	}
+	return s.Deliver(ctx, QueueFor(e), b)
+}
+
+// FlushResult summarizes one pass over every queue
+type FlushResult struct {
+	Sent    int // Items delivered this pass
+	Pending int // Items still queued afterwards
+}
+
+// Flusher drains the spool for a fixed set of queues
+// Flush is the one-shot `flush` command; Run is the daemon loop.
+type Flusher struct {
+	spool      *Spool
+	queues     []Uploader
+	interval   time.Duration
+	maxBackoff time.Duration
+}
+
+// NewFlusher creates a flusher over queues (exporters wrapped with QueueFor, uploaders as-is)
+// Complexity: O(1)
+func NewFlusher(spool *Spool, queues []Uploader, interval, maxBackoff time.Duration) *Flusher {
+	return &Flusher{spool: spool, queues: queues, interval: interval, maxBackoff: maxBackoff}
+}
+
+// FlusherFor builds a flusher for every enabled exporter and uploader
+// key opens payloads spooled under output.encrypt (nil leaves them queued).
+// Complexity: O(|exporters| + |uploaders|)
+func FlusherFor(cfg *config.Config, key *ecdh.PrivateKey) (*Flusher, error) {
+	exporters, err := ExportersFor(cfg)
+	if err != nil {
+		return nil, err
+	}
+	uploaders, err := UploadersFor(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	queues := make([]Uploader, 0, len(exporters)+len(uploaders))
+	for _, e := range exporters {
+		queues = append(queues, SealedQueueFor(e, key))
+	}
+	queues = append(queues, uploaders...)
+
+	sc := cfg.Output.Spool
+	return NewFlusher(NewSpool(sc.Directory), queues,
+		time.Duration(sc.FlushIntervalMs)*time.Millisecond,
+		time.Duration(sc.MaxBackoffMs)*time.Millisecond), nil
+}
+
+// Flush makes one delivery pass over every queue
+// A queue that is still offline does not stop the others; all errors are joined.
+// Complexity: O(queued items) deliveries
+func (f *Flusher) Flush(ctx context.Context) (FlushResult, error) {
+	var result FlushResult
+	var errs []error
+
+	for _, q := range f.queues {
+		sent, err := f.spool.Sync(ctx, q)
+		result.Sent += sent
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		pending, perr := f.spool.Pending(q.Name())
+		if perr != nil {
+			errs = append(errs, perr)
+		}
+		result.Pending += len(pending)
+	}
+	return result, errors.Join(errs...)
+}
+
+// Run flushes until ctx is cancelled, reporting each pass to report (may be nil)
+// Waits interval after a clean pass; while deliveries keep failing with
+// retryable errors the wait doubles up to maxBackoff.
+// Complexity: O(1) memory, one Flush per wake-up
+func (f *Flusher) Run(ctx context.Context, report func(FlushResult, error)) error {
+	delay := f.interval
+	for {
+		result, err := f.Flush(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if report != nil {
+			report(result, err)
+		}
+
+		wait := f.interval
+		if err != nil && IsRetryable(err) {
+			wait = delay
+			delay = min(delay*2, f.maxBackoff)
+		} else {
+			delay = f.interval
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
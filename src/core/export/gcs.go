@@ -0,0 +1,264 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+)
+
+// gcsRetryBase is the initial backoff between object upload retries
+const gcsRetryBase = time.Second
+
+// gcsScope is the OAuth scope requested for object uploads
+const gcsScope = "https://www.googleapis.com/auth/devstorage.read_write"
+
+// defaultGCSEndpoint is the Cloud Storage JSON API
+const defaultGCSEndpoint = "https://storage.googleapis.com"
+
+// defaultGoogleTokenURI is used when the key file omits token_uri
+const defaultGoogleTokenURI = "https://oauth2.googleapis.com/token"
+
+// GCSServiceAccount is the subset of a service-account JSON key used for signing
+type GCSServiceAccount struct {
+	Type         string `json:"type"`
+	ClientEmail  string `json:"client_email"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// GCSUploader uploads each bundle file as <prefix>/<bundle>/<file> in a Cloud Storage bucket
+// Access tokens come from the OAuth 2.0 JWT bearer grant signed with the
+// service-account key, and are refreshed before expiry.
+type GCSUploader struct {
+	cfg      config.GCSConfig
+	client   *http.Client
+	endpoint string
+	account  GCSServiceAccount
+	key      *rsa.PrivateKey
+
+	mu    sync.Mutex
+	token *accessToken
+}
+
+// NewGCSUploader creates a GCS uploader
+// The key is read from credentials_file, else GOOGLE_APPLICATION_CREDENTIALS.
+// Complexity: O(1) (plus key and CA bundle parsing)
+func NewGCSUploader(cfg config.GCSConfig) (*GCSUploader, error) {
+	client, err := newUploadClient(cfg.CAFile, cfg.TimeoutMs)
+	if err != nil {
+		return nil, err
+	}
+
+	keyFile := cfg.CredentialsFile
+	if keyFile == "" {
+		keyFile = os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+	}
+	if keyFile == "" {
+		return nil, fmt.Errorf("no GCS credentials: set credentials_file or GOOGLE_APPLICATION_CREDENTIALS")
+	}
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GCS credentials: %w", err)
+	}
+	account, key, err := ParseGCSServiceAccount(data)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = defaultGCSEndpoint
+	}
+
+	return &GCSUploader{
+		cfg:      cfg,
+		client:   client,
+		endpoint: strings.TrimRight(endpoint, "/"),
+		account:  account,
+		key:      key,
+	}, nil
+}
+
+// ParseGCSServiceAccount decodes a service-account JSON key and its RSA private key
+// Complexity: O(|data|)
+func ParseGCSServiceAccount(data []byte) (GCSServiceAccount, *rsa.PrivateKey, error) {
+	var account GCSServiceAccount
+	if err := json.Unmarshal(data, &account); err != nil {
+		return account, nil, fmt.Errorf("failed to parse GCS credentials: %w", err)
+	}
+	if account.Type != "service_account" {
+		return account, nil, fmt.Errorf("GCS credentials type %q is not service_account", account.Type)
+	}
+	if account.ClientEmail == "" {
+		return account, nil, fmt.Errorf("GCS credentials missing client_email")
+	}
+	if account.TokenURI == "" {
+		account.TokenURI = defaultGoogleTokenURI
+	}
+
+	block, _ := pem.Decode([]byte(account.PrivateKey))
+	if block == nil {
+		return account, nil, fmt.Errorf("GCS credentials private_key is not PEM")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return account, nil, fmt.Errorf("failed to parse GCS private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return account, nil, fmt.Errorf("GCS private key is %T, want RSA", parsed)
+	}
+	return account, key, nil
+}
+
+// Name returns "gcs"
+func (u *GCSUploader) Name() string { return "gcs" }
+
+// Upload sends every file of the bundle
+// Complexity: O(|Files|) requests
+func (u *GCSUploader) Upload(ctx context.Context, b *Bundle) error {
+	if err := b.Validate(); err != nil {
+		return err
+	}
+
+	token, err := u.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range b.Files {
+		name := u.ObjectName(b.Name, f.Name)
+		err := withRetry(ctx, u.cfg.MaxRetries, gcsRetryBase, func() error {
+			return u.insert(ctx, token, name, f)
+		})
+		if err != nil {
+			return fmt.Errorf("upload %s failed: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ObjectName returns the object name for a bundle file
+// Complexity: O(1)
+func (u *GCSUploader) ObjectName(bundle, file string) string {
+	return path.Join(strings.Trim(u.cfg.Prefix, "/"), bundle, file)
+}
+
+// insert uploads one object with a simple media upload (overwrites, keeping Upload idempotent)
+func (u *GCSUploader) insert(ctx context.Context, token, name string, f BundleFile) error {
+	target := u.endpoint + "/upload/storage/v1/b/" + url.PathEscape(u.cfg.Bucket) + "/o?" +
+		url.Values{"uploadType": {"media"}, "name": {name}}.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(f.Data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentTypeFor(f.Name))
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return &RetryableError{Err: err}
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	return classifyHTTP(resp)
+}
+
+// googleTokenResponse is the OAuth 2.0 token endpoint response
+type googleTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// accessToken resolves (and caches) a bearer token via the JWT bearer grant (RFC 7523)
+func (u *GCSUploader) accessToken(ctx context.Context) (string, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.token.fresh() {
+		return u.token.Value, nil
+	}
+
+	assertion, err := u.assertion(time.Now())
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return "", &RetryableError{Err: fmt.Errorf("GCS token: %w", err)}
+	}
+	defer resp.Body.Close()
+	if err := classifyHTTP(resp); err != nil {
+		return "", fmt.Errorf("GCS token: %w", err)
+	}
+
+	var doc googleTokenResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(&doc); err != nil {
+		return "", fmt.Errorf("failed to parse GCS token: %w", err)
+	}
+	if doc.AccessToken == "" {
+		return "", fmt.Errorf("malformed GCS token response")
+	}
+
+	u.token = &accessToken{Value: doc.AccessToken, Expires: time.Now().Add(time.Duration(doc.ExpiresIn) * time.Second)}
+	return u.token.Value, nil
+}
+
+// assertion builds the RS256-signed JWT exchanged for an access token
+// Complexity: O(1)
+func (u *GCSUploader) assertion(now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": u.account.PrivateKeyID})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]any{
+		"iss":   u.account.ClientEmail,
+		"scope": gcsScope,
+		"aud":   u.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawURLEncoding
+	signingInput := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, u.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign GCS assertion: %w", err)
+	}
+	return signingInput + "." + enc.EncodeToString(sig), nil
+}
@@ -0,0 +1,39 @@
+package export
+
+import (
+	"fmt"
+
+	"github.com/minibeast/usb-agent/src/core/inference"
+	"github.com/minibeast/usb-agent/src/core/report"
+)
+
+// HTMLEncoder implements Encoder for the self-contained <run>.report.html
+type HTMLEncoder struct{}
+
+// NewHTMLEncoder creates an HTML report encoder
+// Complexity: O(1)
+func NewHTMLEncoder() *HTMLEncoder {
+	return &HTMLEncoder{}
+}
+
+// Name returns "html"
+func (e *HTMLEncoder) Name() string { return "html" }
+
+// Encode renders the run's report as one HTML page
+// Without a report (no model and no rule fallback) the page carries the fact
+// tables only.
+// Complexity: O(|Facts| + |Report|)
+func (e *HTMLEncoder) Encode(p *Payload) ([]Artifact, error) {
+	if p == nil || p.Facts == nil {
+		return nil, fmt.Errorf("payload facts cannot be nil")
+	}
+	rpt := p.Report
+	if rpt == nil {
+		rpt = report.Build(p.Facts, &inference.ParsedOutput{})
+	}
+	data, err := rpt.RenderHTML(p.Facts)
+	if err != nil {
+		return nil, err
+	}
+	return []Artifact{{Suffix: ".report.html", Data: data}}, nil
+}
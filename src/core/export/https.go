@@ -0,0 +1,91 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+)
+
+// httpsRetryBase is the initial backoff between POST retries
+const httpsRetryBase = time.Second
+
+// HTTPSUploader POSTs each bundle file to <url>/<bundle>/<file> on a collection server
+// Requests carry the configured bearer token. The server must treat a
+// repeated POST of the same file as an overwrite, so spooled bundles can be
+// retried safely.
+type HTTPSUploader struct {
+	cfg      config.HTTPSUploadConfig
+	client   *http.Client
+	endpoint *url.URL
+}
+
+// NewHTTPSUploader creates an HTTPS POST uploader
+// Complexity: O(1) (plus CA bundle parsing)
+func NewHTTPSUploader(cfg config.HTTPSUploadConfig) (*HTTPSUploader, error) {
+	client, err := newUploadClient(cfg.CAFile, cfg.TimeoutMs)
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := url.Parse(strings.TrimRight(cfg.URL, "/"))
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid upload URL %q", cfg.URL)
+	}
+	return &HTTPSUploader{cfg: cfg, client: client, endpoint: endpoint}, nil
+}
+
+// Name returns "https"
+func (u *HTTPSUploader) Name() string { return "https" }
+
+// Upload POSTs every file of the bundle
+// Complexity: O(|Files|) requests
+func (u *HTTPSUploader) Upload(ctx context.Context, b *Bundle) error {
+	if err := b.Validate(); err != nil {
+		return err
+	}
+	for _, f := range b.Files {
+		target := u.FileURL(b.Name, f.Name)
+		err := withRetry(ctx, u.cfg.MaxRetries, httpsRetryBase, func() error {
+			return u.post(ctx, target, f)
+		})
+		if err != nil {
+			return fmt.Errorf("POST %s failed: %w", target, err)
+		}
+	}
+	return nil
+}
+
+// FileURL returns the URL a bundle file is POSTed to
+// Complexity: O(1)
+func (u *HTTPSUploader) FileURL(bundle, file string) string {
+	target := *u.endpoint
+	target.Path = target.Path + "/" + bundle + "/" + file
+	return target.String()
+}
+
+// post uploads one file
+func (u *HTTPSUploader) post(ctx context.Context, target string, f BundleFile) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(f.Data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentTypeFor(f.Name))
+	if u.cfg.AuthToken != "" {
+		req.Header.Set("Authorization", "Bearer "+u.cfg.AuthToken)
+	}
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return &RetryableError{Err: err}
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	return classifyHTTP(resp)
+}
@@ -0,0 +1,50 @@
+package export
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+)
+
+// JSONEncoder implements Encoder for facts.json, written in the canonical
+// encoding (collection.Facts.CanonicalJSON) that is also hashed and signed
+type JSONEncoder struct{}
+
+// NewJSONEncoder creates a JSON encoder
+// Complexity: O(1)
+func NewJSONEncoder() *JSONEncoder {
+	return &JSONEncoder{}
+}
+
+// Name returns "json"
+func (e *JSONEncoder) Name() string { return "json" }
+
+// Encode serializes Facts as canonical JSON
+// Complexity: O(|Facts|)
+func (e *JSONEncoder) Encode(p *Payload) ([]Artifact, error) {
+	if p == nil || p.Facts == nil {
+		return nil, fmt.Errorf("payload facts cannot be nil")
+	}
+
+	data, err := marshalFacts(p.Facts)
+	if err != nil {
+		return nil, err
+	}
+	return []Artifact{{Suffix: ".json", Data: data}}, nil
+}
+
+// factsSizeHint is the size of the last facts.json, so the next one is
+// allocated once instead of grown
+var factsSizeHint atomic.Int64
+
+// marshalFacts returns the canonical facts.json bytes (the bytes that get signed)
+// Complexity: O(|Facts|)
+func marshalFacts(f *collection.Facts) ([]byte, error) {
+	data, err := f.AppendCanonicalJSON(make([]byte, 0, factsSizeHint.Load()))
+	if err != nil {
+		return nil, err
+	}
+	factsSizeHint.Store(int64(len(data)))
+	return data, nil
+}
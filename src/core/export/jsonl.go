@@ -0,0 +1,265 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/inference"
+	"github.com/minibeast/usb-agent/src/core/jsonenc"
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// Record types emitted by the JSONL encoder
+const (
+	RecordHost      = "host"
+	RecordUser      = "user"
+	RecordInterface = "interface"
+	RecordSSID      = "ssid"
+	RecordSoftware  = "software"
+	RecordProcess   = "process"
+	RecordVolume    = "volume"
+	RecordPort      = "listening_port"
+	RecordSecurity  = "security_product"
+	RecordUpdate    = "installed_update"
+	RecordFinding   = "finding"
+)
+
+// Event is a single JSONL record
+type Event struct {
+	RunID     string      `json:"run_id"`
+	Hostname  string      `json:"hostname"`
+	Timestamp time.Time   `json:"timestamp"`
+	Type      string      `json:"type"`
+	Data      interface{} `json:"data"`
+}
+
+// HostRecord is the per-run host summary record
+type HostRecord struct {
+	ComputerName         string `json:"computer_name"`
+	OSName               string `json:"os_name"`
+	OSVersion            string `json:"os_version"`
+	OSBuild              string `json:"os_build"`
+	Timezone             string `json:"timezone"`
+	SerialNumber         string `json:"serial_number"`
+	HardwareUUID         string `json:"hardware_uuid"`
+	CollectorVersion     string `json:"collector_version"`
+	CollectionDurationMs int64  `json:"collection_duration_ms"`
+}
+
+// SSIDRecord is a known WiFi network record
+type SSIDRecord struct {
+	SSID string `json:"ssid"`
+}
+
+// FindingRecord is a report risk record
+type FindingRecord struct {
+	FindingID  string               `json:"finding_id,omitempty"`
+	Text       string               `json:"text"`
+	Severity   string               `json:"severity"`
+	Confidence inference.Confidence `json:"confidence"`
+}
+
+// Events flattens a Payload into ordered records
+// Order: host, users, interfaces, SSIDs, software, processes, volumes,
+// findings (each in Facts/report order)
+// Complexity: O(|Facts| + |risks|)
+func Events(p *Payload) ([]Event, error) {
+	if p == nil || p.Facts == nil {
+		return nil, fmt.Errorf("payload facts cannot be nil")
+	}
+
+	f := p.Facts
+	events := []Event{}
+	add := func(recordType string, data interface{}) {
+		events = append(events, Event{
+			RunID:     p.RunID,
+			Hostname:  f.Hostname,
+			Timestamp: f.Timestamp,
+			Type:      recordType,
+			Data:      data,
+		})
+	}
+
+	add(RecordHost, HostRecord{
+		ComputerName:         f.ComputerName,
+		OSName:               f.OSName,
+		OSVersion:            f.OSVersion,
+		OSBuild:              f.OSBuild,
+		Timezone:             f.Timezone,
+		SerialNumber:         f.SerialNumber,
+		HardwareUUID:         f.HardwareUUID,
+		CollectorVersion:     f.CollectorVersion,
+		CollectionDurationMs: f.CollectionDurationMs,
+	})
+
+	for _, u := range f.Users {
+		add(RecordUser, u)
+	}
+	for _, iface := range f.LocalIPs {
+		add(RecordInterface, iface)
+	}
+	for _, ssid := range f.WiFiSSIDs {
+		add(RecordSSID, SSIDRecord{SSID: ssid})
+	}
+	for _, sw := range f.Software {
+		add(RecordSoftware, sw)
+	}
+	for _, proc := range f.Processes {
+		add(RecordProcess, proc)
+	}
+	for _, v := range f.Volumes {
+		add(RecordVolume, v)
+	}
+	for _, port := range f.ListeningPorts {
+		add(RecordPort, port)
+	}
+	for _, product := range f.SecurityProducts {
+		add(RecordSecurity, product)
+	}
+	for _, update := range f.InstalledUpdates {
+		add(RecordUpdate, update)
+	}
+
+	if p.Report != nil {
+		for _, risk := range p.Report.Risks {
+			add(RecordFinding, FindingRecord{
+				FindingID:  risk.FindingID,
+				Text:       risk.Text,
+				Severity:   risk.Severity.String(),
+				Confidence: risk.Confidence,
+			})
+		}
+	}
+
+	return events, nil
+}
+
+// AppendEvent appends ev as compact JSON, byte-identical to json.Marshal(ev)
+// The record types built by Events are written without reflection; other
+// Data values fall back to encoding/json.
+// Complexity: O(|ev|)
+func AppendEvent(dst []byte, ev *Event) ([]byte, error) {
+	w := jsonenc.Writer{Buf: dst}
+	w.BeginObject()
+	w.StringField("run_id", ev.RunID)
+	w.StringField("hostname", ev.Hostname)
+	w.Key("timestamp")
+	w.Time(ev.Timestamp)
+	w.StringField("type", ev.Type)
+	w.Key("data")
+	switch d := ev.Data.(type) {
+	case HostRecord:
+		w.BeginObject()
+		w.StringField("computer_name", d.ComputerName)
+		w.StringField("os_name", d.OSName)
+		w.StringField("os_version", d.OSVersion)
+		w.StringField("os_build", d.OSBuild)
+		w.StringField("timezone", d.Timezone)
+		w.StringField("serial_number", d.SerialNumber)
+		w.StringField("hardware_uuid", d.HardwareUUID)
+		w.StringField("collector_version", d.CollectorVersion)
+		w.Key("collection_duration_ms")
+		w.Int(d.CollectionDurationMs)
+		w.EndObject()
+	case types.User:
+		d.WriteJSON(&w)
+	case types.NetworkInterface:
+		d.WriteJSON(&w)
+	case types.Software:
+		d.WriteJSON(&w)
+	case types.Process:
+		d.WriteJSON(&w)
+	case types.Volume:
+		d.WriteJSON(&w)
+	case types.ListeningPort:
+		d.WriteJSON(&w)
+	case types.SecurityProduct:
+		d.WriteJSON(&w)
+	case types.Update:
+		d.WriteJSON(&w)
+	case SSIDRecord:
+		w.BeginObject()
+		w.StringField("ssid", d.SSID)
+		w.EndObject()
+	case FindingRecord:
+		w.BeginObject()
+		if d.FindingID != "" {
+			w.StringField("finding_id", d.FindingID)
+		}
+		w.StringField("text", d.Text)
+		w.StringField("severity", d.Severity)
+		w.StringField("confidence", string(d.Confidence))
+		w.EndObject()
+	default:
+		data, err := json.Marshal(d)
+		if err != nil {
+			return dst, fmt.Errorf("failed to encode %s record: %w", ev.Type, err)
+		}
+		w.Raw(data)
+	}
+	w.EndObject()
+	if err := w.Err(); err != nil {
+		return dst, fmt.Errorf("failed to encode %s record: %w", ev.Type, err)
+	}
+	return w.Buf, nil
+}
+
+// appendJSONL appends one compact JSON object per line
+// Complexity: O(|Facts| + |risks|)
+func appendJSONL(dst []byte, p *Payload) ([]byte, error) {
+	events, err := Events(p)
+	if err != nil {
+		return dst, err
+	}
+	for i := range events {
+		if dst, err = AppendEvent(dst, &events[i]); err != nil {
+			return dst, err
+		}
+		dst = append(dst, '\n')
+	}
+	return dst, nil
+}
+
+// jsonlBuffers holds scratch buffers for WriteJSONL, so daemon runs reuse
+// the previous run's capacity
+var jsonlBuffers = sync.Pool{New: func() any { return new([]byte) }}
+
+// WriteJSONL writes one compact JSON object per line
+// Complexity: O(|Facts| + |risks|)
+func WriteJSONL(w io.Writer, p *Payload) error {
+	bufp := jsonlBuffers.Get().(*[]byte)
+	defer jsonlBuffers.Put(bufp)
+
+	buf, err := appendJSONL((*bufp)[:0], p)
+	*bufp = buf
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(buf)
+	return err
+}
+
+// JSONLEncoder implements Encoder for newline-delimited JSON events
+type JSONLEncoder struct{}
+
+// NewJSONLEncoder creates a JSONL encoder
+// Complexity: O(1)
+func NewJSONLEncoder() *JSONLEncoder {
+	return &JSONLEncoder{}
+}
+
+// Name returns "jsonl"
+func (e *JSONLEncoder) Name() string { return "jsonl" }
+
+// Encode serializes the payload as JSONL
+// Complexity: O(|Facts| + |risks|)
+func (e *JSONLEncoder) Encode(p *Payload) ([]Artifact, error) {
+	data, err := appendJSONL(nil, p)
+	if err != nil {
+		return nil, err
+	}
+	return []Artifact{{Suffix: ".jsonl", Data: data}}, nil
+}
@@ -0,0 +1,190 @@
+package export
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/consent"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+)
+
+// kafkaRetryBase is the initial backoff between produce retries
+const kafkaRetryBase = time.Second
+
+// RecordRun is the Kafka record type carrying the full run
+const RecordRun = "run"
+
+// KafkaRunRecord is the per-run Kafka message body
+type KafkaRunRecord struct {
+	Facts    *collection.Facts `json:"facts"`
+	Summary  []string          `json:"summary,omitempty"`
+	Findings int               `json:"findings"`
+	Consent  *consent.Record   `json:"consent,omitempty"`
+}
+
+// KafkaExporter produces one message per run and one per finding to a topic
+// Every message is keyed by run ID (same partition, in order) and carries a
+// "type" header. Retries may duplicate messages; consumers dedupe on
+// (run_id, type, finding index).
+type KafkaExporter struct {
+	cfg       config.KafkaConfig
+	transport *kafka.Transport
+}
+
+// NewKafkaExporter creates a Kafka exporter
+// Complexity: O(1) (plus CA bundle parsing for TLS)
+func NewKafkaExporter(cfg config.KafkaConfig) (*KafkaExporter, error) {
+	transport := &kafka.Transport{
+		DialTimeout: time.Duration(cfg.TimeoutMs) * time.Millisecond,
+		ClientID:    "minibeast",
+	}
+
+	if cfg.TLS {
+		pool, err := loadCAPool(cfg.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLS = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	mechanism, err := kafkaSASL(cfg)
+	if err != nil {
+		return nil, err
+	}
+	transport.SASL = mechanism
+
+	return &KafkaExporter{cfg: cfg, transport: transport}, nil
+}
+
+// kafkaSASL builds the configured SASL mechanism (nil = no SASL)
+func kafkaSASL(cfg config.KafkaConfig) (sasl.Mechanism, error) {
+	switch cfg.SASLMechanism {
+	case "":
+		return nil, nil
+	case "plain":
+		return plain.Mechanism{Username: cfg.Username, Password: cfg.Password}, nil
+	case "scram-sha-256":
+		return scram.Mechanism(scram.SHA256, cfg.Username, cfg.Password)
+	case "scram-sha-512":
+		return scram.Mechanism(scram.SHA512, cfg.Username, cfg.Password)
+	default:
+		return nil, fmt.Errorf("unsupported SASL mechanism %q", cfg.SASLMechanism)
+	}
+}
+
+// Name returns "kafka"
+func (e *KafkaExporter) Name() string { return "kafka" }
+
+// Export produces the run message followed by the finding messages
+// Complexity: O(|risks|) messages in one produce request per partition leader
+func (e *KafkaExporter) Export(ctx context.Context, p *Payload) error {
+	messages, err := e.Messages(p)
+	if err != nil {
+		return err
+	}
+
+	acks := kafka.RequireAll
+	if e.cfg.Acks == "leader" {
+		acks = kafka.RequireOne
+	}
+	timeout := time.Duration(e.cfg.TimeoutMs) * time.Millisecond
+
+	w := &kafka.Writer{
+		Addr:         kafka.TCP(e.cfg.Brokers...),
+		Topic:        e.cfg.Topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: acks,
+		MaxAttempts:  1, // Retries go through withRetry so they share the backoff policy
+		BatchTimeout: time.Millisecond,
+		WriteTimeout: timeout,
+		ReadTimeout:  timeout,
+		Transport:    e.transport,
+	}
+	defer func() {
+		w.Close()
+		e.transport.CloseIdleConnections()
+	}()
+
+	return withRetry(ctx, e.cfg.MaxRetries, kafkaRetryBase, func() error {
+		return classifyKafka(w.WriteMessages(ctx, messages...))
+	})
+}
+
+// Messages builds the Kafka messages for a payload
+// Mathematical property: Same Payload → Same messages
+// Complexity: O(|Facts| + |risks|)
+func (e *KafkaExporter) Messages(p *Payload) ([]kafka.Message, error) {
+	if p == nil || p.Facts == nil {
+		return nil, fmt.Errorf("payload facts cannot be nil")
+	}
+
+	run := KafkaRunRecord{Facts: p.Facts, Consent: p.Consent}
+	if p.Report != nil {
+		run.Summary = p.Report.Summary
+		run.Findings = len(p.Report.Risks)
+	}
+	events := []Event{{
+		RunID:     p.RunID,
+		Hostname:  p.Facts.Hostname,
+		Timestamp: p.Facts.Timestamp,
+		Type:      RecordRun,
+		Data:      run,
+	}}
+
+	all, err := Events(p)
+	if err != nil {
+		return nil, err
+	}
+	for _, ev := range all {
+		if ev.Type == RecordFinding {
+			events = append(events, ev)
+		}
+	}
+
+	key := []byte(p.RunID)
+	messages := make([]kafka.Message, 0, len(events))
+	var buf []byte // Shared backing array; each value is a capped slice of it
+	for i := range events {
+		ev := &events[i]
+		start := len(buf)
+		if buf, err = AppendEvent(buf, ev); err != nil {
+			return nil, err
+		}
+		messages = append(messages, kafka.Message{
+			Key:     key,
+			Value:   buf[start:len(buf):len(buf)],
+			Time:    ev.Timestamp,
+			Headers: []kafka.Header{{Key: "type", Value: []byte(ev.Type)}},
+		})
+	}
+	return messages, nil
+}
+
+// classifyKafka marks broker errors the protocol declares temporary, and all
+// transport failures, as retryable
+func classifyKafka(err error) error {
+	if err == nil {
+		return nil
+	}
+	var kerr kafka.Error
+	if errors.As(err, &kerr) && !kerr.Temporary() {
+		return fmt.Errorf("kafka: %w", err)
+	}
+	var werrs kafka.WriteErrors
+	if errors.As(err, &werrs) {
+		for _, werr := range werrs {
+			if errors.As(werr, &kerr) && !kerr.Temporary() {
+				return fmt.Errorf("kafka: %w", err)
+			}
+		}
+	}
+	return &RetryableError{Err: fmt.Errorf("kafka: %w", err)}
+}
@@ -0,0 +1,291 @@
+package export
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+)
+
+// mqttRetryBase is the initial backoff between broker connection retries
+const mqttRetryBase = time.Second
+
+// MQTT 3.1.1 control packet types (high nibble of the fixed header)
+const (
+	mqttConnect    = 0x10
+	mqttConnack    = 0x20
+	mqttPublish    = 0x30
+	mqttPuback     = 0x40
+	mqttDisconnect = 0xE0
+)
+
+// mqttKeepAlive is advertised in CONNECT; sessions are short-lived
+const mqttKeepAlive = 60
+
+// MQTTMessage is one message to publish
+type MQTTMessage struct {
+	Topic   string
+	Payload []byte
+}
+
+// MQTTExporter publishes each JSONL record to <topic_prefix>/<hostname>/<type>
+// A minimal MQTT 3.1.1 publisher (CONNECT, PUBLISH QoS 0/1, DISCONNECT);
+// it needs no subscription support, so no client library is pulled in.
+type MQTTExporter struct {
+	cfg       config.MQTTConfig
+	tlsConfig *tls.Config
+}
+
+// NewMQTTExporter creates an MQTT exporter
+// Complexity: O(1) (plus CA bundle parsing for TLS)
+func NewMQTTExporter(cfg config.MQTTConfig) (*MQTTExporter, error) {
+	exp := &MQTTExporter{cfg: cfg}
+
+	if cfg.TLS {
+		tlsConfig, err := loadTLSConfig(cfg.Address, cfg.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		exp.tlsConfig = tlsConfig
+	}
+
+	return exp, nil
+}
+
+// Name returns "mqtt"
+func (e *MQTTExporter) Name() string { return "mqtt" }
+
+// Export publishes all records over one broker session
+// Complexity: O(|Facts| + |risks|) messages
+func (e *MQTTExporter) Export(ctx context.Context, p *Payload) error {
+	messages, err := e.Messages(p)
+	if err != nil {
+		return err
+	}
+
+	clientID := e.cfg.ClientID
+	if clientID == "" {
+		clientID = "minibeast-" + mqttTopicLevel(p.Facts.Hostname)
+	}
+
+	return withRetry(ctx, e.cfg.MaxRetries, mqttRetryBase, func() error {
+		return e.publish(ctx, clientID, messages)
+	})
+}
+
+// Messages builds the MQTT messages for a payload
+// Mathematical property: Same Payload → Same messages
+// Complexity: O(|Facts| + |risks|)
+func (e *MQTTExporter) Messages(p *Payload) ([]MQTTMessage, error) {
+	events, err := Events(p)
+	if err != nil {
+		return nil, err
+	}
+
+	base := strings.TrimRight(e.cfg.TopicPrefix, "/") + "/" + mqttTopicLevel(p.Facts.Hostname) + "/"
+	messages := make([]MQTTMessage, 0, len(events))
+	var buf []byte // Shared backing array; each payload is a capped slice of it
+	for i := range events {
+		start := len(buf)
+		if buf, err = AppendEvent(buf, &events[i]); err != nil {
+			return nil, err
+		}
+		messages = append(messages, MQTTMessage{Topic: base + events[i].Type, Payload: buf[start:len(buf):len(buf)]})
+	}
+	return messages, nil
+}
+
+// publish runs one CONNECT → PUBLISH* → DISCONNECT session
+func (e *MQTTExporter) publish(ctx context.Context, clientID string, messages []MQTTMessage) error {
+	timeout := time.Duration(e.cfg.TimeoutMs) * time.Millisecond
+	dialCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var conn net.Conn
+	var err error
+	if e.tlsConfig != nil {
+		dialer := &tls.Dialer{Config: e.tlsConfig}
+		conn, err = dialer.DialContext(dialCtx, "tcp", e.cfg.Address)
+	} else {
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(dialCtx, "tcp", e.cfg.Address)
+	}
+	if err != nil {
+		return &RetryableError{Err: fmt.Errorf("failed to connect to MQTT broker: %w", err)}
+	}
+	defer conn.Close()
+
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	reader := bufio.NewReader(conn)
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	if _, err := conn.Write(e.connectPacket(clientID)); err != nil {
+		return &RetryableError{Err: fmt.Errorf("failed to send CONNECT: %w", err)}
+	}
+	packetType, body, err := readMQTTPacket(reader)
+	if err != nil {
+		return &RetryableError{Err: fmt.Errorf("failed to read CONNACK: %w", err)}
+	}
+	if packetType != mqttConnack || len(body) != 2 {
+		return fmt.Errorf("unexpected MQTT packet 0x%02x instead of CONNACK", packetType)
+	}
+	if err := connackError(body[1]); err != nil {
+		return err
+	}
+
+	for i, msg := range messages {
+		packetID := uint16(i%65535 + 1)
+		conn.SetDeadline(time.Now().Add(timeout))
+		if _, err := conn.Write(e.publishPacket(msg, packetID)); err != nil {
+			return &RetryableError{Err: fmt.Errorf("failed to publish to %s: %w", msg.Topic, err)}
+		}
+		if e.cfg.QoS == 0 {
+			continue
+		}
+		if err := awaitPuback(reader, packetID); err != nil {
+			return &RetryableError{Err: fmt.Errorf("no PUBACK for %s: %w", msg.Topic, err)}
+		}
+	}
+
+	conn.Write([]byte{mqttDisconnect, 0})
+	return nil
+}
+
+// connectPacket encodes CONNECT with a clean session
+func (e *MQTTExporter) connectPacket(clientID string) []byte {
+	flags := byte(0x02) // Clean session
+	payload := mqttString(clientID)
+	if e.cfg.Username != "" {
+		flags |= 0x80
+		payload = append(payload, mqttString(e.cfg.Username)...)
+		if e.cfg.Password != "" {
+			flags |= 0x40
+			payload = append(payload, mqttString(e.cfg.Password)...)
+		}
+	}
+
+	variable := append(mqttString("MQTT"), 4, flags, 0, 0)
+	binary.BigEndian.PutUint16(variable[len(variable)-2:], mqttKeepAlive)
+	return mqttPacket(mqttConnect, append(variable, payload...))
+}
+
+// publishPacket encodes PUBLISH (packet ID only present for QoS 1)
+func (e *MQTTExporter) publishPacket(msg MQTTMessage, packetID uint16) []byte {
+	header := byte(mqttPublish) | byte(e.cfg.QoS<<1)
+	if e.cfg.Retain {
+		header |= 0x01
+	}
+
+	body := mqttString(msg.Topic)
+	if e.cfg.QoS > 0 {
+		body = binary.BigEndian.AppendUint16(body, packetID)
+	}
+	return mqttPacket(header, append(body, msg.Payload...))
+}
+
+// awaitPuback reads until the PUBACK for packetID (other packets are ignored)
+func awaitPuback(r *bufio.Reader, packetID uint16) error {
+	for {
+		packetType, body, err := readMQTTPacket(r)
+		if err != nil {
+			return err
+		}
+		if packetType == mqttPuback && len(body) == 2 && binary.BigEndian.Uint16(body) == packetID {
+			return nil
+		}
+	}
+}
+
+// connackError maps CONNACK return codes (3 = server unavailable is retryable)
+func connackError(code byte) error {
+	reasons := map[byte]string{
+		1: "unacceptable protocol version",
+		2: "client identifier rejected",
+		3: "server unavailable",
+		4: "bad user name or password",
+		5: "not authorized",
+	}
+	switch code {
+	case 0:
+		return nil
+	case 3:
+		return &RetryableError{Err: errors.New("MQTT broker: " + reasons[code])}
+	default:
+		if reason, ok := reasons[code]; ok {
+			return errors.New("MQTT broker refused connection: " + reason)
+		}
+		return fmt.Errorf("MQTT broker refused connection: code %d", code)
+	}
+}
+
+// mqttPacket prepends the fixed header (type/flags + variable-length remaining length)
+func mqttPacket(header byte, body []byte) []byte {
+	packet := []byte{header}
+	n := len(body)
+	for {
+		b := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			b |= 0x80
+		}
+		packet = append(packet, b)
+		if n == 0 {
+			break
+		}
+	}
+	return append(packet, body...)
+}
+
+// readMQTTPacket reads one control packet, returning its type nibble and body
+func readMQTTPacket(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	length, multiplier := 0, 1
+	for i := 0; ; i++ {
+		if i == 4 {
+			return 0, nil, fmt.Errorf("malformed remaining length")
+		}
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(b&0x7F) * multiplier
+		multiplier *= 128
+		if b&0x80 == 0 {
+			break
+		}
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return header & 0xF0, body, nil
+}
+
+// mqttString encodes a length-prefixed UTF-8 string
+func mqttString(s string) []byte {
+	return append(binary.BigEndian.AppendUint16(nil, uint16(len(s))), s...)
+}
+
+// mqttTopicLevel makes a value safe as a single topic level
+func mqttTopicLevel(v string) string {
+	v = strings.NewReplacer("/", "_", "+", "_", "#", "_").Replace(v)
+	if v == "" {
+		return "unknown"
+	}
+	return v
+}
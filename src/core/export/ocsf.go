@@ -0,0 +1,259 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/minibeast/usb-agent/src/core/inference"
+	"github.com/minibeast/usb-agent/src/core/report"
+)
+
+// OCSF schema version and class identifiers
+const (
+	ocsfVersion = "1.1.0"
+
+	ocsfCategoryDiscovery = 5
+	ocsfClassInventory    = 5001 // Device Inventory Info
+	ocsfActivityCollect   = 2
+
+	ocsfCategoryFindings = 2
+	ocsfClassDetection   = 2004 // Detection Finding
+	ocsfActivityCreate   = 1
+	ocsfFindingStatusNew = 1
+	ocsfConfidenceLow    = 1
+	ocsfConfidenceHigh   = 3
+)
+
+// OCSFMetadata is the OCSF metadata object
+type OCSFMetadata struct {
+	Version        string      `json:"version"`
+	Product        OCSFProduct `json:"product"`
+	CorrelationUID string      `json:"correlation_uid,omitempty"` // Run ID
+}
+
+// OCSFProduct identifies the reporting product
+type OCSFProduct struct {
+	Name       string `json:"name"`
+	VendorName string `json:"vendor_name"`
+	Version    string `json:"version,omitempty"`
+}
+
+// OCSFOS is the OCSF operating system object
+type OCSFOS struct {
+	Name    string `json:"name"`
+	TypeID  int    `json:"type_id"`
+	Version string `json:"version,omitempty"`
+	Build   string `json:"build,omitempty"`
+}
+
+// OCSFInterface is the OCSF network interface object
+type OCSFInterface struct {
+	Name   string `json:"name"`
+	IP     string `json:"ip,omitempty"`
+	MAC    string `json:"mac,omitempty"`
+	TypeID int    `json:"type_id"`
+}
+
+// OCSFHardwareInfo is the OCSF device hardware info object
+type OCSFHardwareInfo struct {
+	SerialNumber string `json:"serial_number,omitempty"`
+	UUID         string `json:"uuid,omitempty"`
+}
+
+// OCSFDevice is the OCSF device object
+type OCSFDevice struct {
+	Hostname          string            `json:"hostname"`
+	Name              string            `json:"name,omitempty"`
+	UID               string            `json:"uid,omitempty"`
+	TypeID            int               `json:"type_id"`
+	OS                OCSFOS            `json:"os"`
+	HWInfo            *OCSFHardwareInfo `json:"hw_info,omitempty"`
+	NetworkInterfaces []OCSFInterface   `json:"network_interfaces,omitempty"`
+	Timezone          string            `json:"timezone,omitempty"`
+}
+
+// OCSFUser is the OCSF user object
+type OCSFUser struct {
+	Name     string `json:"name"`
+	FullName string `json:"full_name,omitempty"`
+	UID      string `json:"uid,omitempty"`
+}
+
+// OCSFFindingInfo is the OCSF finding_info object
+type OCSFFindingInfo struct {
+	UID   string   `json:"uid"`
+	Title string   `json:"title"`
+	Desc  string   `json:"desc,omitempty"`
+	Types []string `json:"types,omitempty"`
+}
+
+// OCSFEvent is a Device Inventory Info or Detection Finding event
+// Fields not used by a class are omitted.
+type OCSFEvent struct {
+	CategoryUID  int              `json:"category_uid"`
+	ClassUID     int              `json:"class_uid"`
+	ActivityID   int              `json:"activity_id"`
+	TypeUID      int              `json:"type_uid"`
+	Time         int64            `json:"time"` // Epoch milliseconds
+	SeverityID   int              `json:"severity_id"`
+	Severity     string           `json:"severity,omitempty"`
+	Metadata     OCSFMetadata     `json:"metadata"`
+	Device       OCSFDevice       `json:"device"`
+	FindingInfo  *OCSFFindingInfo `json:"finding_info,omitempty"`
+	StatusID     int              `json:"status_id,omitempty"`
+	ConfidenceID int              `json:"confidence_id,omitempty"`
+	Unmapped     interface{}      `json:"unmapped,omitempty"`
+}
+
+// OCSFEncoder implements Encoder for OCSF events (one JSON object per line)
+// One Device Inventory Info event per run followed by one Detection
+// Finding per risk, as ingested by OCSF security lakes.
+type OCSFEncoder struct{}
+
+// NewOCSFEncoder creates an OCSF encoder
+// Complexity: O(1)
+func NewOCSFEncoder() *OCSFEncoder {
+	return &OCSFEncoder{}
+}
+
+// Name returns "ocsf"
+func (e *OCSFEncoder) Name() string { return "ocsf" }
+
+// Encode serializes OCSF events as JSONL (suffix ".ocsf.jsonl")
+// Complexity: O(|Facts| + |risks|)
+func (e *OCSFEncoder) Encode(p *Payload) ([]Artifact, error) {
+	events, err := OCSFEvents(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, ev := range events {
+		if err := enc.Encode(ev); err != nil {
+			return nil, fmt.Errorf("failed to encode OCSF event: %w", err)
+		}
+	}
+	return []Artifact{{Suffix: ".ocsf.jsonl", Data: buf.Bytes()}}, nil
+}
+
+// OCSFEvents maps a payload to OCSF events
+// Complexity: O(|Facts| + |risks|)
+func OCSFEvents(p *Payload) ([]OCSFEvent, error) {
+	if p == nil || p.Facts == nil {
+		return nil, fmt.Errorf("payload facts cannot be nil")
+	}
+
+	f := p.Facts
+	device := OCSFDevice{
+		Hostname: f.Hostname,
+		Name:     f.ComputerName,
+		UID:      f.HardwareUUID,
+		TypeID:   0, // Unknown
+		OS:       OCSFOS{Name: f.OSName, TypeID: ocsfOSType(f.OSName), Version: f.OSVersion, Build: f.OSBuild},
+		Timezone: f.Timezone,
+	}
+	if f.SerialNumber != "" || f.HardwareUUID != "" {
+		device.HWInfo = &OCSFHardwareInfo{SerialNumber: f.SerialNumber, UUID: f.HardwareUUID}
+	}
+	for _, iface := range f.LocalIPs {
+		device.NetworkInterfaces = append(device.NetworkInterfaces, OCSFInterface{
+			Name: iface.Name, IP: iface.IPAddress, MAC: iface.MACAddress, TypeID: 0,
+		})
+	}
+
+	metadata := OCSFMetadata{
+		Version:        ocsfVersion,
+		Product:        OCSFProduct{Name: "MiniBeast", VendorName: "MiniBeast", Version: f.CollectorVersion},
+		CorrelationUID: p.RunID,
+	}
+	ms := f.Timestamp.UnixMilli()
+
+	users := make([]OCSFUser, 0, len(f.Users))
+	for _, u := range f.Users {
+		users = append(users, OCSFUser{Name: u.Username, FullName: u.FullName, UID: u.UID})
+	}
+
+	events := []OCSFEvent{{
+		CategoryUID: ocsfCategoryDiscovery,
+		ClassUID:    ocsfClassInventory,
+		ActivityID:  ocsfActivityCollect,
+		TypeUID:     ocsfClassInventory*100 + ocsfActivityCollect,
+		Time:        ms,
+		SeverityID:  1, // Informational
+		Severity:    "Informational",
+		Metadata:    metadata,
+		Device:      device,
+		Unmapped: map[string]interface{}{
+			"users":            users,
+			"wifi_known_ssids": f.WiFiSSIDs,
+		},
+	}}
+
+	if p.Report != nil {
+		for i, risk := range p.Report.Risks {
+			uid := risk.FindingID
+			if uid == "" {
+				uid = fmt.Sprintf("%s-risk-%d", p.RunID, i)
+			}
+			severityID, severity := ocsfSeverity(risk.Severity)
+			events = append(events, OCSFEvent{
+				CategoryUID:  ocsfCategoryFindings,
+				ClassUID:     ocsfClassDetection,
+				ActivityID:   ocsfActivityCreate,
+				TypeUID:      ocsfClassDetection*100 + ocsfActivityCreate,
+				Time:         ms,
+				SeverityID:   severityID,
+				Severity:     severity,
+				Metadata:     metadata,
+				Device:       device,
+				FindingInfo:  &OCSFFindingInfo{UID: uid, Title: risk.Text, Types: []string{"MiniBeast Risk"}},
+				StatusID:     ocsfFindingStatusNew,
+				ConfidenceID: ocsfConfidence(risk.Confidence),
+			})
+		}
+	}
+
+	return events, nil
+}
+
+// ocsfOSType maps OS names to OCSF os.type_id
+func ocsfOSType(name string) int {
+	switch name {
+	case "Windows":
+		return 100
+	case "Linux":
+		return 200
+	case "Darwin":
+		return 300 // macOS
+	default:
+		return 0
+	}
+}
+
+// ocsfSeverity maps report severities to OCSF severity_id and caption
+func ocsfSeverity(s report.Severity) (int, string) {
+	switch s {
+	case report.SeverityInfo:
+		return 1, "Informational"
+	case report.SeverityLow:
+		return 2, "Low"
+	case report.SeverityMedium:
+		return 3, "Medium"
+	case report.SeverityHigh:
+		return 4, "High"
+	case report.SeverityCritical:
+		return 5, "Critical"
+	default:
+		return 0, "Unknown"
+	}
+}
+
+// ocsfConfidence maps grounded findings to High and inferred ones to Low
+func ocsfConfidence(c inference.Confidence) int {
+	if c == inference.ConfidenceGrounded {
+		return ocsfConfidenceHigh
+	}
+	return ocsfConfidenceLow
+}
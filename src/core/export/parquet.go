@@ -0,0 +1,92 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// ParquetEncoder implements Encoder for the tabular Facts sections
+// Each CSVTables entry becomes one Parquet file with the same columns,
+// so lakehouse ingestion and spreadsheet users see identical data.
+type ParquetEncoder struct{}
+
+// NewParquetEncoder creates a Parquet encoder
+// Complexity: O(1)
+func NewParquetEncoder() *ParquetEncoder {
+	return &ParquetEncoder{}
+}
+
+// Name returns "parquet"
+func (e *ParquetEncoder) Name() string { return "parquet" }
+
+// Encode produces users.parquet, interfaces.parquet, wifi.parquet and findings.parquet
+// Every file carries the full schema, even when it holds no rows
+// Complexity: O(|Facts| + |risks|)
+func (e *ParquetEncoder) Encode(p *Payload) ([]Artifact, error) {
+	if p == nil || p.Facts == nil {
+		return nil, fmt.Errorf("payload facts cannot be nil")
+	}
+
+	rows := tableRows(p)
+	artifacts := make([]Artifact, 0, len(CSVTables))
+	for _, table := range CSVTables {
+		file := ParquetFile(table)
+		data, err := encodeParquet(table, rows[table.File])
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", file, err)
+		}
+		artifacts = append(artifacts, Artifact{Suffix: "." + file, Data: data})
+	}
+	return artifacts, nil
+}
+
+// ParquetFile returns the artifact suffix for table, e.g. "users.parquet"
+func ParquetFile(table CSVTable) string {
+	return strings.TrimSuffix(table.File, ".csv") + ".parquet"
+}
+
+// ParquetSchema returns the Parquet schema for table
+// All columns are required UTF-8 strings, matching the CSV contract.
+// Note: parquet.Group orders leaves by name, not by table.Columns order.
+func ParquetSchema(table CSVTable) *parquet.Schema {
+	group := make(parquet.Group, len(table.Columns))
+	for _, col := range table.Columns {
+		group[col.Name] = parquet.String()
+	}
+	return parquet.NewSchema(strings.TrimSuffix(table.File, ".csv"), group)
+}
+
+// encodeParquet writes rows as a single Snappy-compressed row group
+// Complexity: O(|rows| * |columns|)
+func encodeParquet(table CSVTable, rows [][]string) ([]byte, error) {
+	schema := ParquetSchema(table)
+
+	// Map schema leaf order back to table column positions
+	fields := schema.Fields()
+	position := make(map[string]int, len(table.Columns))
+	for i, col := range table.Columns {
+		position[col.Name] = i
+	}
+
+	records := make([]parquet.Row, len(rows))
+	for r, row := range rows {
+		record := make(parquet.Row, len(fields))
+		for leaf, field := range fields {
+			record[leaf] = parquet.ByteArrayValue([]byte(row[position[field.Name()]])).Level(0, 0, leaf)
+		}
+		records[r] = record
+	}
+
+	var buf bytes.Buffer
+	w := parquet.NewWriter(&buf, schema, parquet.Compression(&parquet.Snappy))
+	if _, err := w.WriteRows(records); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,37 @@
+package export
+
+import (
+	"context"
+	"errors"
+
+	"github.com/minibeast/usb-agent/src/core/plugin"
+)
+
+// PluginExporter delivers payloads to an exporter plugin
+// Timeouts and failures the plugin marks "retry" are retryable, so the
+// payload stays spooled; any other failure is permanent.
+type PluginExporter struct {
+	plugin *plugin.Plugin
+}
+
+// NewPluginExporter wraps an exporter plugin
+// Complexity: O(1)
+func NewPluginExporter(p *plugin.Plugin) *PluginExporter {
+	return &PluginExporter{plugin: p}
+}
+
+// Name returns "plugin-<name>" (also the spool queue directory)
+func (e *PluginExporter) Name() string {
+	return "plugin-" + e.plugin.Name
+}
+
+// Export runs the plugin once with the payload
+// Complexity: O(|Payload|) plus the plugin's own work
+func (e *PluginExporter) Export(ctx context.Context, p *Payload) error {
+	_, err := e.plugin.Call(ctx, &plugin.Request{RunID: p.RunID, Facts: p.Facts, Report: p.Report, Consent: p.Consent})
+	var callErr *plugin.CallError
+	if errors.As(err, &callErr) && callErr.Retry {
+		return &RetryableError{Err: err}
+	}
+	return err
+}
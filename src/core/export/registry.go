@@ -0,0 +1,54 @@
+package export
+
+import (
+	"fmt"
+	"sort"
+)
+
+// encoders maps output.formats names to encoder constructors
+var encoders = map[string]func() Encoder{
+	"json":    func() Encoder { return NewJSONEncoder() },
+	"jsonl":   func() Encoder { return NewJSONLEncoder() },
+	"cbor":    func() Encoder { return NewCBOREncoder() },
+	"csv":     func() Encoder { return NewCSVEncoder() },
+	"parquet": func() Encoder { return NewParquetEncoder() },
+	"stix":    func() Encoder { return NewSTIXEncoder() },
+	"ocsf":    func() Encoder { return NewOCSFEncoder() },
+	"cef":     func() Encoder { return NewCEFEncoder() },
+	"leef":    func() Encoder { return NewLEEFEncoder() },
+	"html":    func() Encoder { return NewHTMLEncoder() },
+}
+
+// EncoderFor returns the encoder registered under name
+// Complexity: O(1)
+func EncoderFor(name string) (Encoder, error) {
+	newEncoder, ok := encoders[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown output format: %q", name)
+	}
+	return newEncoder(), nil
+}
+
+// EncodersFor resolves every name in output.formats (order preserved)
+// Complexity: O(|names|)
+func EncodersFor(names []string) ([]Encoder, error) {
+	result := make([]Encoder, 0, len(names))
+	for _, name := range names {
+		enc, err := EncoderFor(name)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, enc)
+	}
+	return result, nil
+}
+
+// Formats returns the sorted names of all registered encoders
+func Formats() []string {
+	names := make([]string, 0, len(encoders))
+	for name := range encoders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
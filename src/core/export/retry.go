@@ -0,0 +1,67 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// RetryableError marks a delivery failure worth retrying (network errors, 5xx, 429)
+type RetryableError struct {
+	Err error
+}
+
+func (e *RetryableError) Error() string {
+	return "retryable: " + e.Err.Error()
+}
+
+func (e *RetryableError) Unwrap() error {
+	return e.Err
+}
+
+// IsRetryable reports whether err (or anything it wraps) is a RetryableError
+func IsRetryable(err error) bool {
+	var r *RetryableError
+	return errors.As(err, &r)
+}
+
+// withRetry runs fn up to 1+maxRetries times with exponential backoff
+// Only RetryableError failures are retried; the last error is returned
+// Complexity: O(maxRetries) attempts, total delay ≤ base * (2^maxRetries - 1)
+func withRetry(ctx context.Context, maxRetries int, base time.Duration, fn func() error) error {
+	var err error
+	delay := base
+
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if err = fn(); err == nil || !IsRetryable(err) {
+			return err
+		}
+		if attempt == maxRetries {
+			break
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return fmt.Errorf("retry aborted: %w", ctx.Err())
+		}
+		delay *= 2
+	}
+
+	return fmt.Errorf("giving up after %d attempts: %w", maxRetries+1, err)
+}
+
+// classifyHTTP converts an HTTP response status into a delivery error
+// 2xx → nil, 429/5xx → RetryableError, other → permanent error
+func classifyHTTP(resp *http.Response) error {
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return &RetryableError{Err: fmt.Errorf("server returned %s", resp.Status)}
+	default:
+		return fmt.Errorf("server returned %s", resp.Status)
+	}
+}
@@ -0,0 +1,235 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+)
+
+// s3RetryBase is the initial backoff between object PUT retries
+const s3RetryBase = time.Second
+
+// defaultIMDSEndpoint is the EC2 instance metadata service
+const defaultIMDSEndpoint = "http://169.254.169.254"
+
+// S3Uploader puts each bundle file as <prefix>/<bundle>/<file> in an S3-compatible bucket
+// Requests are signed with SigV4. Credentials come from config, then the
+// AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY/AWS_SESSION_TOKEN environment
+// variables, then the EC2 instance role (IMDSv2), refreshed before expiry.
+type S3Uploader struct {
+	cfg      config.S3Config
+	client   *http.Client
+	endpoint *url.URL
+
+	mu    sync.Mutex
+	creds *AWSCredentials
+}
+
+// NewS3Uploader creates an S3 uploader
+// Complexity: O(1) (plus CA bundle parsing)
+func NewS3Uploader(cfg config.S3Config) (*S3Uploader, error) {
+	client, err := newUploadClient(cfg.CAFile, cfg.TimeoutMs)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := cfg.Endpoint
+	if raw == "" {
+		raw = "https://s3." + cfg.Region + ".amazonaws.com"
+	}
+	endpoint, err := url.Parse(strings.TrimRight(raw, "/"))
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", raw)
+	}
+
+	up := &S3Uploader{
+		cfg:      cfg,
+		client:   client,
+		endpoint: endpoint,
+	}
+	if cfg.AccessKeyID != "" {
+		up.creds = &AWSCredentials{
+			AccessKeyID:     cfg.AccessKeyID,
+			SecretAccessKey: cfg.SecretAccessKey,
+			SessionToken:    cfg.SessionToken,
+		}
+	}
+	return up, nil
+}
+
+// Name returns "s3"
+func (u *S3Uploader) Name() string { return "s3" }
+
+// Upload puts every file of the bundle
+// Complexity: O(|Files|) requests
+func (u *S3Uploader) Upload(ctx context.Context, b *Bundle) error {
+	if err := b.Validate(); err != nil {
+		return err
+	}
+
+	creds, err := u.credentials(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range b.Files {
+		key := u.ObjectKey(b.Name, f.Name)
+		err := withRetry(ctx, u.cfg.MaxRetries, s3RetryBase, func() error {
+			return u.put(ctx, creds, key, f)
+		})
+		if err != nil {
+			return fmt.Errorf("PUT %s failed: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// ObjectKey returns the object key for a bundle file
+// Complexity: O(1)
+func (u *S3Uploader) ObjectKey(bundle, file string) string {
+	return path.Join(strings.Trim(u.cfg.Prefix, "/"), bundle, file)
+}
+
+// objectURL addresses key path-style (endpoint/bucket/key) or virtual-hosted (bucket.endpoint/key)
+func (u *S3Uploader) objectURL(key string) *url.URL {
+	target := *u.endpoint
+	if u.cfg.PathStyle {
+		target.Path = target.Path + "/" + u.cfg.Bucket + "/" + key
+	} else {
+		target.Host = u.cfg.Bucket + "." + target.Host
+		target.Path = target.Path + "/" + key
+	}
+	return &target
+}
+
+// put uploads one object
+func (u *S3Uploader) put(ctx context.Context, creds AWSCredentials, key string, f BundleFile) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.objectURL(key).String(), bytes.NewReader(f.Data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", contentTypeFor(f.Name))
+	if u.cfg.SSE != "" {
+		req.Header.Set("X-Amz-Server-Side-Encryption", u.cfg.SSE)
+		if u.cfg.KMSKeyID != "" {
+			req.Header.Set("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id", u.cfg.KMSKeyID)
+		}
+	}
+	SignV4(req, hexSHA256(f.Data), creds, u.cfg.Region, "s3", time.Now())
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return &RetryableError{Err: err}
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	return classifyHTTP(resp)
+}
+
+// credentials resolves (and caches) signing credentials
+func (u *S3Uploader) credentials(ctx context.Context) (AWSCredentials, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	// Cached credentials are reused until 5 minutes before expiry
+	if u.creds != nil && (u.creds.Expires.IsZero() || time.Until(u.creds.Expires) > 5*time.Minute) {
+		return *u.creds, nil
+	}
+
+	if id, secret := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"); id != "" && secret != "" {
+		u.creds = &AWSCredentials{AccessKeyID: id, SecretAccessKey: secret, SessionToken: os.Getenv("AWS_SESSION_TOKEN")}
+		return *u.creds, nil
+	}
+
+	if strings.EqualFold(os.Getenv("AWS_EC2_METADATA_DISABLED"), "true") {
+		return AWSCredentials{}, fmt.Errorf("no S3 credentials configured and instance metadata disabled")
+	}
+	creds, err := instanceRoleCredentials(ctx)
+	if err != nil {
+		return AWSCredentials{}, &RetryableError{Err: fmt.Errorf("no S3 credentials: %w", err)}
+	}
+	u.creds = creds
+	return *creds, nil
+}
+
+// imdsCredentials is the IMDS security-credentials document
+type imdsCredentials struct {
+	AccessKeyID     string    `json:"AccessKeyId"`
+	SecretAccessKey string    `json:"SecretAccessKey"`
+	Token           string    `json:"Token"`
+	Expiration      time.Time `json:"Expiration"`
+}
+
+// instanceRoleCredentials fetches EC2 instance role credentials via IMDSv2
+// The endpoint honors AWS_EC2_METADATA_SERVICE_ENDPOINT.
+func instanceRoleCredentials(ctx context.Context) (*AWSCredentials, error) {
+	endpoint := os.Getenv("AWS_EC2_METADATA_SERVICE_ENDPOINT")
+	if endpoint == "" {
+		endpoint = defaultIMDSEndpoint
+	}
+	endpoint = strings.TrimRight(endpoint, "/")
+	client := &http.Client{Timeout: 2 * time.Second}
+
+	fetch := func(method, p string, header http.Header) ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, method, endpoint+p, nil)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("instance metadata %s returned %s", p, resp.Status)
+		}
+		return io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	}
+
+	token, err := fetch(http.MethodPut, "/latest/api/token", http.Header{"X-Aws-Ec2-Metadata-Token-Ttl-Seconds": {"300"}})
+	if err != nil {
+		return nil, err
+	}
+	auth := http.Header{"X-Aws-Ec2-Metadata-Token": {string(token)}}
+
+	roles, err := fetch(http.MethodGet, "/latest/meta-data/iam/security-credentials/", auth)
+	if err != nil {
+		return nil, err
+	}
+	role := strings.TrimSpace(strings.SplitN(string(roles), "\n", 2)[0])
+	if role == "" {
+		return nil, fmt.Errorf("no instance role attached")
+	}
+
+	doc, err := fetch(http.MethodGet, "/latest/meta-data/iam/security-credentials/"+role, auth)
+	if err != nil {
+		return nil, err
+	}
+	var ic imdsCredentials
+	if err := json.Unmarshal(doc, &ic); err != nil {
+		return nil, fmt.Errorf("failed to parse instance credentials: %w", err)
+	}
+
+	return &AWSCredentials{
+		AccessKeyID:     ic.AccessKeyID,
+		SecretAccessKey: ic.SecretAccessKey,
+		SessionToken:    ic.Token,
+		Expires:         ic.Expiration,
+	}, nil
+}
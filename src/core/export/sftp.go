@@ -0,0 +1,204 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// sftpRetryBase is the initial backoff between SFTP connection retries
+const sftpRetryBase = time.Second
+
+// errHostKeyMismatch is returned when the server key does not match the pin
+var errHostKeyMismatch = errors.New("host key does not match pinned key")
+
+// SFTPUploader writes each bundle file to <remote_dir>/<bundle>/<file> on an SFTP server
+// Files are written as <file>.part and renamed, so the dropbox never sees
+// partial files. Connection failures are retried; host key and
+// authentication failures are not.
+type SFTPUploader struct {
+	cfg       config.SFTPConfig
+	sshConfig *ssh.ClientConfig
+}
+
+// NewSFTPUploader creates an SFTP uploader
+// Complexity: O(1) (plus private key parsing)
+func NewSFTPUploader(cfg config.SFTPConfig) (*SFTPUploader, error) {
+	hostKeyCallback, algorithms, err := pinnedHostKey(cfg.HostKey)
+	if err != nil {
+		return nil, err
+	}
+
+	auth := []ssh.AuthMethod{}
+	if cfg.PrivateKeyPath != "" {
+		signer, err := loadSSHSigner(cfg.PrivateKeyPath, cfg.PrivateKeyPassphrase)
+		if err != nil {
+			return nil, err
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if cfg.Password != "" {
+		auth = append(auth, ssh.Password(cfg.Password))
+	}
+
+	return &SFTPUploader{
+		cfg: cfg,
+		sshConfig: &ssh.ClientConfig{
+			User:              cfg.Username,
+			Auth:              auth,
+			HostKeyCallback:   hostKeyCallback,
+			HostKeyAlgorithms: algorithms,
+			Timeout:           time.Duration(cfg.TimeoutMs) * time.Millisecond,
+		},
+	}, nil
+}
+
+// Name returns "sftp"
+func (u *SFTPUploader) Name() string { return "sftp" }
+
+// Upload writes every file of the bundle over one SSH connection
+// Complexity: O(total bundle size)
+func (u *SFTPUploader) Upload(ctx context.Context, b *Bundle) error {
+	if err := b.Validate(); err != nil {
+		return err
+	}
+
+	return withRetry(ctx, u.cfg.MaxRetries, sftpRetryBase, func() error {
+		return u.upload(ctx, b)
+	})
+}
+
+// upload performs one connection attempt
+func (u *SFTPUploader) upload(ctx context.Context, b *Bundle) error {
+	dialer := net.Dialer{Timeout: u.sshConfig.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", u.cfg.Address)
+	if err != nil {
+		return &RetryableError{Err: err}
+	}
+
+	// Handshake failures (host key, auth) are configuration errors: not retried
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, u.cfg.Address, u.sshConfig)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("SSH handshake with %s failed: %w", u.cfg.Address, err)
+	}
+	client := ssh.NewClient(sshConn, chans, reqs)
+	defer client.Close()
+
+	// Abort in-flight transfers on cancellation
+	stop := context.AfterFunc(ctx, func() { client.Close() })
+	defer stop()
+
+	sc, err := sftp.NewClient(client)
+	if err != nil {
+		return &RetryableError{Err: fmt.Errorf("failed to start SFTP subsystem: %w", err)}
+	}
+	defer sc.Close()
+
+	dir := path.Join(u.cfg.RemoteDir, b.Name)
+	if err := sc.MkdirAll(dir); err != nil {
+		return &RetryableError{Err: fmt.Errorf("failed to create %s: %w", dir, err)}
+	}
+
+	for _, f := range b.Files {
+		if err := writeRemote(sc, path.Join(dir, f.Name), f.Data); err != nil {
+			return &RetryableError{Err: err}
+		}
+	}
+	return nil
+}
+
+// writeRemote writes data to target via a .part file and rename
+func writeRemote(sc *sftp.Client, target string, data []byte) error {
+	part := target + ".part"
+	file, err := sc.OpenFile(part, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", part, err)
+	}
+	if _, err := file.ReadFrom(bytes.NewReader(data)); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s: %w", part, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", part, err)
+	}
+
+	// POSIX rename overwrites atomically; plain SFTP rename fails if target exists
+	if err := sc.PosixRename(part, target); err != nil {
+		sc.Remove(target)
+		if err := sc.Rename(part, target); err != nil {
+			return fmt.Errorf("failed to rename %s: %w", part, err)
+		}
+	}
+	return nil
+}
+
+// pinnedHostKey builds a host key callback accepting only the pinned key
+// pin is an authorized_keys line or a "SHA256:" fingerprint.
+func pinnedHostKey(pin string) (ssh.HostKeyCallback, []string, error) {
+	pin = strings.TrimSpace(pin)
+	if pin == "" {
+		return nil, nil, fmt.Errorf("SFTP host key must be pinned")
+	}
+
+	if strings.HasPrefix(pin, "SHA256:") {
+		return func(_ string, _ net.Addr, key ssh.PublicKey) error {
+			if subtle.ConstantTimeCompare([]byte(ssh.FingerprintSHA256(key)), []byte(pin)) != 1 {
+				return fmt.Errorf("%w (got %s)", errHostKeyMismatch, ssh.FingerprintSHA256(key))
+			}
+			return nil
+		}, nil, nil
+	}
+
+	pinned, _, _, _, err := ssh.ParseAuthorizedKey([]byte(pin))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid SFTP host key: %w", err)
+	}
+	want := pinned.Marshal()
+	return func(_ string, _ net.Addr, key ssh.PublicKey) error {
+		if subtle.ConstantTimeCompare(key.Marshal(), want) != 1 {
+			return fmt.Errorf("%w (got %s)", errHostKeyMismatch, ssh.FingerprintSHA256(key))
+		}
+		return nil
+	}, hostKeyAlgorithms(pinned.Type()), nil
+}
+
+// hostKeyAlgorithms lists the signature algorithms a key type can verify
+// An "ssh-rsa" key signs with rsa-sha2-512/256 on current servers, which
+// often no longer offer SHA-1 ssh-rsa at all.
+func hostKeyAlgorithms(keyType string) []string {
+	if keyType == ssh.KeyAlgoRSA {
+		return []string{ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSA}
+	}
+	return []string{keyType}
+}
+
+// loadSSHSigner reads an OpenSSH/PEM private key
+func loadSSHSigner(keyPath, passphrase string) (ssh.Signer, error) {
+	data, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SFTP private key: %w", err)
+	}
+
+	var signer ssh.Signer
+	if passphrase != "" {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(data, []byte(passphrase))
+	} else {
+		signer, err = ssh.ParsePrivateKey(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SFTP private key: %w", err)
+	}
+	return signer, nil
+}
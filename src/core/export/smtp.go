@@ -0,0 +1,265 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdh"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+)
+
+// smtpRetryBase is the initial backoff between SMTP retries
+const smtpRetryBase = 2 * time.Second
+
+// SMTPExporter emails the text report (report.json attached) to configured recipients
+// Security "starttls" refuses to send if the server does not offer STARTTLS,
+// so credentials and report contents never cross the network in clear text.
+// With envelope recipients the body carries only run metadata and every
+// attachment is sealed with crypto.Encrypt (".mbe").
+type SMTPExporter struct {
+	cfg        config.SMTPConfig
+	tlsConfig  *tls.Config
+	recipients []*ecdh.PublicKey
+	now        func() time.Time
+}
+
+// NewSMTPExporter creates an SMTP exporter sending plaintext attachments
+// Complexity: O(1) (plus CA bundle parsing)
+func NewSMTPExporter(cfg config.SMTPConfig) (*SMTPExporter, error) {
+	return NewSMTPExporterWithRecipients(cfg, nil)
+}
+
+// NewSMTPExporterWithRecipients creates an SMTP exporter encrypting attachments to recipients
+// A nil recipients list sends the report in the clear.
+// Complexity: O(1) (plus CA bundle parsing)
+func NewSMTPExporterWithRecipients(cfg config.SMTPConfig, recipients []*ecdh.PublicKey) (*SMTPExporter, error) {
+	tlsConfig, err := loadTLSConfig(cfg.Address, cfg.CAFile)
+	if err != nil {
+		return nil, err
+	}
+	return &SMTPExporter{cfg: cfg, tlsConfig: tlsConfig, recipients: recipients, now: time.Now}, nil
+}
+
+// Name returns "smtp"
+func (e *SMTPExporter) Name() string {
+	return "smtp"
+}
+
+// Export sends one message to all recipients
+// 4xx replies and network errors are retried; 5xx replies are permanent.
+func (e *SMTPExporter) Export(ctx context.Context, p *Payload) error {
+	msg, err := e.Message(p)
+	if err != nil {
+		return err
+	}
+
+	return withRetry(ctx, e.cfg.MaxRetries, smtpRetryBase, func() error {
+		return e.send(ctx, msg)
+	})
+}
+
+// send delivers msg in a single SMTP session
+func (e *SMTPExporter) send(ctx context.Context, msg []byte) error {
+	timeout := time.Duration(e.cfg.TimeoutMs) * time.Millisecond
+	attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	dialer := &net.Dialer{}
+	var conn net.Conn
+	var err error
+	if e.cfg.Security == "tls" {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: e.tlsConfig}).DialContext(attemptCtx, "tcp", e.cfg.Address)
+	} else {
+		conn, err = dialer.DialContext(attemptCtx, "tcp", e.cfg.Address)
+	}
+	if err != nil {
+		return &RetryableError{Err: fmt.Errorf("failed to connect to %s: %w", e.cfg.Address, err)}
+	}
+	defer conn.Close()
+
+	deadline, _ := attemptCtx.Deadline()
+	conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, e.tlsConfig.ServerName)
+	if err != nil {
+		return classifySMTP(err)
+	}
+	defer client.Close()
+
+	if e.cfg.Security == "starttls" {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("server %s does not offer STARTTLS", e.cfg.Address)
+		}
+		if err := client.StartTLS(e.tlsConfig); err != nil {
+			return fmt.Errorf("STARTTLS failed: %w", err)
+		}
+	}
+
+	if e.cfg.Username != "" {
+		auth := smtp.PlainAuth("", e.cfg.Username, e.cfg.Password, e.tlsConfig.ServerName)
+		if err := client.Auth(auth); err != nil {
+			return classifySMTP(fmt.Errorf("authentication failed: %w", err))
+		}
+	}
+
+	if err := client.Mail(e.cfg.From); err != nil {
+		return classifySMTP(err)
+	}
+	for _, rcpt := range e.cfg.To {
+		if err := client.Rcpt(rcpt); err != nil {
+			return classifySMTP(fmt.Errorf("recipient %s rejected: %w", rcpt, err))
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return classifySMTP(err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return &RetryableError{Err: err}
+	}
+	if err := w.Close(); err != nil {
+		return classifySMTP(err)
+	}
+
+	return client.Quit()
+}
+
+// classifySMTP maps a session error to retryable (4xx, network) or permanent (5xx)
+func classifySMTP(err error) error {
+	var tpErr *textproto.Error
+	if errors.As(err, &tpErr) {
+		if tpErr.Code >= 400 && tpErr.Code < 500 {
+			return &RetryableError{Err: err}
+		}
+		return err
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return &RetryableError{Err: err}
+	}
+	return err
+}
+
+// Message builds the RFC 5322 multipart message
+// Mathematical property: Same Payload and clock → Same bytes (boundary derives from the run ID)
+// Complexity: O(|Report| + |Bundle|)
+func (e *SMTPExporter) Message(p *Payload) ([]byte, error) {
+	if p == nil || p.Facts == nil {
+		return nil, fmt.Errorf("payload facts cannot be nil")
+	}
+
+	factsJSON, err := marshalFacts(p.Facts)
+	if err != nil {
+		return nil, err
+	}
+	factsHash := sha256.Sum256(factsJSON)
+
+	boundarySum := sha256.Sum256([]byte("minibeast-smtp:" + p.RunID))
+	boundary := "mb-" + hex.EncodeToString(boundarySum[:12])
+
+	subject := fmt.Sprintf("%s report %s", p.Facts.Hostname, p.RunID)
+	if e.cfg.SubjectPrefix != "" {
+		subject = e.cfg.SubjectPrefix + " " + subject
+	}
+
+	var buf bytes.Buffer
+	writeHeader := func(name, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", name, value)
+	}
+	writeHeader("From", e.cfg.From)
+	writeHeader("To", strings.Join(e.cfg.To, ", "))
+	writeHeader("Subject", mime.QEncoding.Encode("utf-8", subject))
+	writeHeader("Date", e.now().UTC().Format(time.RFC1123Z))
+	writeHeader("Message-ID", fmt.Sprintf("<%s.%s@minibeast>", p.RunID, hex.EncodeToString(factsHash[:8])))
+	writeHeader("X-MiniBeast-Run-Id", p.RunID)
+	writeHeader("X-MiniBeast-Facts-SHA256", hex.EncodeToString(factsHash[:]))
+	writeHeader("MIME-Version", "1.0")
+	writeHeader("Content-Type", fmt.Sprintf("multipart/mixed; boundary=%q", boundary))
+	buf.WriteString("\r\n")
+
+	// Part 1: human-readable report
+	var text string
+	switch {
+	case e.recipients != nil:
+		text = fmt.Sprintf("Encrypted MiniBeast report for %s (run %s).\nfacts.json SHA-256: %s\nAttachments are encrypted to %d recipient key(s).\n",
+			p.Facts.Hostname, p.RunID, hex.EncodeToString(factsHash[:]), len(e.recipients))
+	case p.Report != nil:
+		text = p.Report.RenderText()
+	default:
+		text = fmt.Sprintf("No report was generated for %s (LLM phase did not run).\nfacts.json SHA-256: %s\n",
+			p.Facts.Hostname, hex.EncodeToString(factsHash[:]))
+	}
+	fmt.Fprintf(&buf, "--%s\r\n", boundary)
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+	qp := quotedprintable.NewWriter(&buf)
+	if _, err := qp.Write([]byte(text)); err != nil {
+		return nil, fmt.Errorf("failed to encode report text: %w", err)
+	}
+	if err := qp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode report text: %w", err)
+	}
+	buf.WriteString("\r\n")
+
+	// Attachments: report.json, then bundle files when requested
+	attachments := &Bundle{Name: "attachments"}
+	if p.Report != nil {
+		reportJSON, err := p.Report.RenderJSON()
+		if err != nil {
+			return nil, err
+		}
+		attachments.Add("report.json", reportJSON)
+	}
+	if e.cfg.AttachBundle && p.Bundle != nil {
+		if err := p.Bundle.Validate(); err != nil {
+			return nil, err
+		}
+		attachments.Files = append(attachments.Files, p.Bundle.Files...)
+	}
+	if e.recipients != nil && len(attachments.Files) > 0 {
+		if attachments, err = EncryptBundle(attachments, e.recipients); err != nil {
+			return nil, err
+		}
+	}
+	for _, f := range attachments.Files {
+		contentType := "application/octet-stream"
+		if f.Name == "report.json" {
+			contentType = "application/json"
+		}
+		writeAttachment(&buf, boundary, f.Name, contentType, f.Data)
+	}
+
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+	return buf.Bytes(), nil
+}
+
+// writeAttachment appends a base64 attachment part (76-column lines)
+func writeAttachment(buf *bytes.Buffer, boundary, name, contentType string, data []byte) {
+	fmt.Fprintf(buf, "--%s\r\n", boundary)
+	fmt.Fprintf(buf, "Content-Type: %s; name=%q\r\n", contentType, name)
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n")
+	fmt.Fprintf(buf, "Content-Disposition: attachment; filename=%q\r\n\r\n", name)
+
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	if encoded != "" {
+		buf.WriteString(encoded + "\r\n")
+	}
+}
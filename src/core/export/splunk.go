@@ -0,0 +1,134 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+)
+
+// splunkRetryBase is the initial backoff between HEC retries
+const splunkRetryBase = 500 * time.Millisecond
+
+// hecEvent is a single Splunk HTTP Event Collector event
+type hecEvent struct {
+	Time       float64     `json:"time"`
+	Host       string      `json:"host"`
+	Source     string      `json:"source"`
+	SourceType string      `json:"sourcetype"`
+	Index      string      `json:"index,omitempty"`
+	Event      interface{} `json:"event"`
+}
+
+// SplunkExporter posts Facts sections and findings to a Splunk HEC endpoint
+// Events are the JSONL records (host, user, interface, ssid, finding), sent
+// in batches of batch_size with exponential-backoff retry on 429/5xx.
+type SplunkExporter struct {
+	cfg      config.SplunkConfig
+	client   *http.Client
+	endpoint string
+}
+
+// NewSplunkExporter creates a Splunk HEC exporter
+// Complexity: O(1) (plus CA bundle parsing)
+func NewSplunkExporter(cfg config.SplunkConfig) (*SplunkExporter, error) {
+	pool, err := loadCAPool(cfg.CAFile)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+
+	return &SplunkExporter{
+		cfg:      cfg,
+		client:   &http.Client{Transport: transport, Timeout: time.Duration(cfg.TimeoutMs) * time.Millisecond},
+		endpoint: strings.TrimRight(cfg.URL, "/") + "/services/collector/event",
+	}, nil
+}
+
+// Name returns "splunk"
+func (e *SplunkExporter) Name() string { return "splunk" }
+
+// Export posts all events in batches
+// Complexity: O(|events| / batch_size) requests
+func (e *SplunkExporter) Export(ctx context.Context, p *Payload) error {
+	batches, err := e.Batches(p)
+	if err != nil {
+		return err
+	}
+
+	for i, body := range batches {
+		err := withRetry(ctx, e.cfg.MaxRetries, splunkRetryBase, func() error {
+			return e.post(ctx, body)
+		})
+		if err != nil {
+			return fmt.Errorf("HEC batch %d/%d failed: %w", i+1, len(batches), err)
+		}
+	}
+	return nil
+}
+
+// Batches encodes the payload into HEC request bodies (concatenated JSON events)
+// Mathematical property: Same Payload → Same batches
+// Complexity: O(|events|)
+func (e *SplunkExporter) Batches(p *Payload) ([][]byte, error) {
+	events, err := Events(p)
+	if err != nil {
+		return nil, err
+	}
+
+	batchSize := e.cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = len(events)
+	}
+
+	batches := [][]byte{}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for i, event := range events {
+		hec := hecEvent{
+			Time:       float64(event.Timestamp.UnixNano()) / 1e9,
+			Host:       event.Hostname,
+			Source:     "minibeast",
+			SourceType: e.cfg.SourceType,
+			Index:      e.cfg.Index,
+			Event:      event,
+		}
+		if err := enc.Encode(hec); err != nil {
+			return nil, fmt.Errorf("failed to encode HEC event: %w", err)
+		}
+
+		if (i+1)%batchSize == 0 || i == len(events)-1 {
+			batches = append(batches, append([]byte{}, buf.Bytes()...))
+			buf.Reset()
+		}
+	}
+	return batches, nil
+}
+
+// post sends a single batch
+func (e *SplunkExporter) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Splunk "+e.cfg.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return &RetryableError{Err: err}
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	return classifyHTTP(resp)
+}
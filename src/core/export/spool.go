@@ -0,0 +1,201 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// failedDir holds bundles rejected permanently (hidden from Pending)
+const failedDir = ".failed"
+
+// Spool persists bundles that could not be uploaded so a later run can retry them
+// Layout: <dir>/<uploader>/<bundle>/<file>. Each uploader has its own queue,
+// so a bundle delivered to S3 but not to SFTP is only retried against SFTP.
+// Bundles are written to a temp directory and renamed into place, so a
+// queued bundle is either complete or absent.
+type Spool struct {
+	dir string
+}
+
+// NewSpool creates a spool rooted at dir (created on first use)
+// Complexity: O(1)
+func NewSpool(dir string) *Spool {
+	return &Spool{dir: dir}
+}
+
+// Enqueue stores a bundle for uploader
+// Complexity: O(total bundle size)
+func (s *Spool) Enqueue(uploader string, b *Bundle) error {
+	if err := b.Validate(); err != nil {
+		return err
+	}
+
+	queue := filepath.Join(s.dir, uploader)
+	if err := os.MkdirAll(queue, 0700); err != nil {
+		return fmt.Errorf("failed to create spool directory: %w", err)
+	}
+
+	tempDir, err := os.MkdirTemp(queue, ".tmp-"+b.Name+"-")
+	if err != nil {
+		return fmt.Errorf("failed to create spool temp directory: %w", err)
+	}
+	for _, f := range b.Files {
+		if err := os.WriteFile(filepath.Join(tempDir, f.Name), f.Data, 0600); err != nil {
+			os.RemoveAll(tempDir)
+			return fmt.Errorf("failed to spool %s: %w", f.Name, err)
+		}
+	}
+
+	// Replace any older copy of the same bundle
+	final := filepath.Join(queue, b.Name)
+	os.RemoveAll(final)
+	if err := os.Rename(tempDir, final); err != nil {
+		os.RemoveAll(tempDir)
+		return fmt.Errorf("failed to commit spooled bundle: %w", err)
+	}
+	return nil
+}
+
+// Pending lists queued bundle names for uploader (sorted, oldest name first)
+// Complexity: O(n log n) where n = queued bundles
+func (s *Spool) Pending(uploader string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(s.dir, uploader))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spool: %w", err)
+	}
+
+	names := []string{}
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Load reads a queued bundle
+// Complexity: O(total bundle size)
+func (s *Spool) Load(uploader, name string) (*Bundle, error) {
+	if err := validateName(name); err != nil {
+		return nil, fmt.Errorf("invalid bundle name %q: %w", name, err)
+	}
+
+	dir := filepath.Join(s.dir, uploader, name)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spooled bundle: %w", err)
+	}
+
+	b := &Bundle{Name: name}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read spooled file: %w", err)
+		}
+		b.Add(e.Name(), data)
+	}
+	return b, nil
+}
+
+// Remove deletes a queued bundle
+// Complexity: O(|Files|)
+func (s *Spool) Remove(uploader, name string) error {
+	if err := validateName(name); err != nil {
+		return fmt.Errorf("invalid bundle name %q: %w", name, err)
+	}
+	return os.RemoveAll(filepath.Join(s.dir, uploader, name))
+}
+
+// Sync retries every queued bundle for u, removing each one that uploads
+// Stops at the first retryable failure (the network is most likely still
+// down). Sealed payloads the queue cannot open stay queued. Bundles failing permanently are moved to <dir>/<uploader>/.failed/
+// so they cannot block the queue; their errors are joined into the result.
+// Complexity: O(queued bundles) uploads
+func (s *Spool) Sync(ctx context.Context, u Uploader) (int, error) {
+	names, err := s.Pending(u.Name())
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	var permanent []error
+	for _, name := range names {
+		b, err := s.Load(u.Name(), name)
+		if err != nil {
+			return sent, err
+		}
+		if err := u.Upload(ctx, b); err != nil {
+			if errors.Is(err, ErrSealedPayload) {
+				continue // Waits for a flush that holds the recipient key
+			}
+			err = fmt.Errorf("%s: spooled bundle %s: %w", u.Name(), name, err)
+			if IsRetryable(err) || ctx.Err() != nil {
+				return sent, errors.Join(append(permanent, err)...)
+			}
+			if qerr := s.quarantine(u.Name(), name); qerr != nil {
+				return sent, errors.Join(append(permanent, err, qerr)...)
+			}
+			permanent = append(permanent, err)
+			continue
+		}
+		if err := s.Remove(u.Name(), name); err != nil {
+			return sent, fmt.Errorf("failed to remove delivered bundle: %w", err)
+		}
+		sent++
+	}
+	return sent, errors.Join(permanent...)
+}
+
+// Failed lists quarantined bundle names for uploader (sorted)
+// Complexity: O(n log n) where n = quarantined bundles
+func (s *Spool) Failed(uploader string) ([]string, error) {
+	return s.Pending(filepath.Join(uploader, failedDir))
+}
+
+// quarantine moves a permanently failing bundle out of the queue
+func (s *Spool) quarantine(uploader, name string) error {
+	failed := filepath.Join(s.dir, uploader, failedDir)
+	if err := os.MkdirAll(failed, 0700); err != nil {
+		return fmt.Errorf("failed to create quarantine directory: %w", err)
+	}
+	final := filepath.Join(failed, name)
+	os.RemoveAll(final)
+	if err := os.Rename(filepath.Join(s.dir, uploader, name), final); err != nil {
+		return fmt.Errorf("failed to quarantine bundle %s: %w", name, err)
+	}
+	return nil
+}
+
+// Deliver flushes the queue for u, then uploads b; on failure b is spooled
+// Returns spooled=true when b was queued instead of delivered. The error is
+// nil in that case unless spooling itself failed.
+// Complexity: O(queued bundles + 1) uploads
+func (s *Spool) Deliver(ctx context.Context, u Uploader, b *Bundle) (spooled bool, err error) {
+	if err := b.Validate(); err != nil {
+		return false, err
+	}
+
+	// Older bundles first; if they fail the network is down, so queue b directly
+	if _, syncErr := s.Sync(ctx, u); syncErr == nil {
+		if err := u.Upload(ctx, b); err == nil {
+			return false, nil
+		}
+	}
+
+	if err := s.Enqueue(u.Name(), b); err != nil {
+		return false, fmt.Errorf("%s: failed to spool bundle: %w", u.Name(), err)
+	}
+	return true, nil
+}
@@ -0,0 +1,222 @@
+package export
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// stixSCONamespace is the STIX 2.1 namespace for deterministic SCO identifiers (§2.9)
+var stixSCONamespace = [16]byte{0x00, 0xab, 0xed, 0xb4, 0xaa, 0x42, 0x46, 0x6c, 0x9c, 0x01, 0xfe, 0xd2, 0x33, 0x15, 0xa9, 0xb7}
+
+// stixMiniBeastNamespace derives deterministic SDO identifiers for a run
+var stixMiniBeastNamespace = [16]byte{0x6d, 0x69, 0x6e, 0x69, 0x62, 0x65, 0x61, 0x73, 0x74, 0x2d, 0x73, 0x74, 0x69, 0x78, 0x32, 0x31}
+
+// stixObject is a STIX object as a property map (marshaled with sorted keys)
+type stixObject map[string]interface{}
+
+// STIXEncoder implements Encoder for a STIX 2.1 bundle
+// Mapping:
+//   - host → infrastructure (infrastructure_types: workstation)
+//   - interfaces → mac-addr / ipv4-addr / ipv6-addr SCOs (IP resolves_to_refs MAC)
+//   - users → user-account SCOs
+//   - observables → one observed-data SDO, linked to the host by "consists-of"
+//   - risks → note SDOs referencing the host (labels carry severity and confidence)
+//
+// SCO IDs follow the spec's UUIDv5 derivation; SDO IDs are UUIDv5 over the
+// run, so the same Payload always produces the same bundle.
+type STIXEncoder struct{}
+
+// NewSTIXEncoder creates a STIX encoder
+// Complexity: O(1)
+func NewSTIXEncoder() *STIXEncoder {
+	return &STIXEncoder{}
+}
+
+// Name returns "stix"
+func (e *STIXEncoder) Name() string { return "stix" }
+
+// Encode serializes the payload as an indented STIX bundle (suffix ".stix.json")
+// Complexity: O(|Facts| + |risks|)
+func (e *STIXEncoder) Encode(p *Payload) ([]Artifact, error) {
+	bundle, err := STIXBundle(p)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal STIX bundle: %w", err)
+	}
+	return []Artifact{{Suffix: ".stix.json", Data: data}}, nil
+}
+
+// STIXBundle maps a payload into a STIX 2.1 bundle object
+// Complexity: O(|Facts| + |risks|)
+func STIXBundle(p *Payload) (stixObject, error) {
+	if p == nil || p.Facts == nil {
+		return nil, fmt.Errorf("payload facts cannot be nil")
+	}
+
+	f := p.Facts
+	runKey := p.RunID
+	if runKey == "" {
+		runKey = f.Hostname + "|" + f.HardwareUUID + "|" + f.Timestamp.UTC().Format("20060102T150405Z")
+	}
+	ts := stixTimestamp(f)
+	sdoID := func(objType, name string) string {
+		return objType + "--" + uuidV5(stixMiniBeastNamespace, runKey+"|"+objType+"|"+name)
+	}
+	common := func(objType, id string) stixObject {
+		return stixObject{"type": objType, "spec_version": "2.1", "id": id, "created": ts, "modified": ts}
+	}
+
+	identityID := "identity--" + uuidV5(stixMiniBeastNamespace, "minibeast")
+	identity := common("identity", identityID)
+	identity["name"] = "MiniBeast"
+	identity["identity_class"] = "system"
+	// The agent identity is run-independent
+	identity["created"], identity["modified"] = "2025-01-01T00:00:00.000Z", "2025-01-01T00:00:00.000Z"
+
+	hostID := sdoID("infrastructure", f.Hostname)
+	host := common("infrastructure", hostID)
+	host["created_by_ref"] = identityID
+	host["name"] = f.Hostname
+	host["infrastructure_types"] = []string{"workstation"}
+	host["description"] = strings.TrimSpace(f.OSName + " " + f.OSVersion + " " + f.OSBuild)
+	if f.HardwareUUID != "" || f.SerialNumber != "" {
+		host["external_references"] = hostReferences(f)
+	}
+
+	// Observables (deduplicated: SCO IDs are content-derived)
+	objects := []stixObject{identity, host}
+	seen := map[string]bool{}
+	observed := []string{}
+	addSCO := func(obj stixObject) {
+		id := obj["id"].(string)
+		if !seen[id] {
+			seen[id] = true
+			objects = append(objects, obj)
+			observed = append(observed, id)
+		}
+	}
+
+	for _, iface := range append(append([]types.NetworkInterface{}, f.LocalIPs...), f.MACAddresses...) {
+		macRef := ""
+		if mac := strings.ToLower(iface.MACAddress); mac != "" {
+			sco := stixSCO("mac-addr", map[string]string{"value": mac})
+			addSCO(sco)
+			macRef = sco["id"].(string)
+		}
+		if ip := net.ParseIP(iface.IPAddress); ip != nil {
+			objType := "ipv6-addr"
+			if ip.To4() != nil {
+				objType = "ipv4-addr"
+			}
+			sco := stixSCO(objType, map[string]string{"value": iface.IPAddress})
+			if macRef != "" {
+				sco["resolves_to_refs"] = []string{macRef}
+			}
+			addSCO(sco)
+		}
+	}
+
+	accountType := "unix"
+	if f.OSName == "Windows" {
+		accountType = "windows-local"
+	}
+	for _, u := range f.Users {
+		contributing := map[string]string{"account_type": accountType, "account_login": u.Username}
+		if u.UID != "" {
+			contributing["user_id"] = u.UID
+		}
+		sco := stixSCO("user-account", contributing)
+		if u.FullName != "" {
+			sco["display_name"] = u.FullName
+		}
+		addSCO(sco)
+	}
+
+	if len(observed) > 0 {
+		obsID := sdoID("observed-data", "observables")
+		obs := common("observed-data", obsID)
+		obs["created_by_ref"] = identityID
+		obs["first_observed"], obs["last_observed"] = ts, ts
+		obs["number_observed"] = 1
+		obs["object_refs"] = observed
+		objects = append(objects, obs)
+
+		rel := common("relationship", sdoID("relationship", "consists-of|"+obsID))
+		rel["created_by_ref"] = identityID
+		rel["relationship_type"] = "consists-of"
+		rel["source_ref"] = hostID
+		rel["target_ref"] = obsID
+		objects = append(objects, rel)
+	}
+
+	if p.Report != nil {
+		for i, risk := range p.Report.Risks {
+			note := common("note", sdoID("note", fmt.Sprintf("finding|%d", i)))
+			note["created_by_ref"] = identityID
+			note["content"] = risk.Text
+			note["object_refs"] = []string{hostID}
+			note["labels"] = []string{"severity:" + strings.ToLower(risk.Severity.String()), "confidence:" + string(risk.Confidence)}
+			if risk.FindingID != "" {
+				note["abstract"] = risk.FindingID
+			}
+			objects = append(objects, note)
+		}
+	}
+
+	return stixObject{
+		"type":    "bundle",
+		"id":      "bundle--" + uuidV5(stixMiniBeastNamespace, runKey+"|bundle"),
+		"objects": objects,
+	}, nil
+}
+
+// hostReferences records hardware identifiers as external references
+func hostReferences(f *collection.Facts) []map[string]string {
+	refs := []map[string]string{}
+	if f.HardwareUUID != "" {
+		refs = append(refs, map[string]string{"source_name": "hardware-uuid", "external_id": f.HardwareUUID})
+	}
+	if f.SerialNumber != "" {
+		refs = append(refs, map[string]string{"source_name": "serial-number", "external_id": f.SerialNumber})
+	}
+	return refs
+}
+
+// stixSCO builds an SCO whose ID is UUIDv5 over its ID-contributing properties
+func stixSCO(objType string, contributing map[string]string) stixObject {
+	// encoding/json sorts map keys, matching JSON canonicalization for flat string maps
+	canonical, _ := json.Marshal(contributing)
+	obj := stixObject{"type": objType, "spec_version": "2.1", "id": objType + "--" + uuidV5(stixSCONamespace, string(canonical))}
+	for k, v := range contributing {
+		obj[k] = v
+	}
+	return obj
+}
+
+// stixTimestamp formats the collection time with millisecond precision
+func stixTimestamp(f *collection.Facts) string {
+	return f.Timestamp.UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+// uuidV5 returns the RFC 4122 name-based (SHA-1) UUID of name in namespace
+// Complexity: O(len(name))
+func uuidV5(namespace [16]byte, name string) string {
+	h := sha1.New()
+	h.Write(namespace[:])
+	h.Write([]byte(name))
+	sum := h.Sum(nil)
+
+	sum[6] = (sum[6] & 0x0f) | 0x50 // Version 5
+	sum[8] = (sum[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
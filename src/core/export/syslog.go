@@ -0,0 +1,275 @@
+package export
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/report"
+)
+
+// syslogSDID is the structured-data ID (RFC 5424 §7.2.2, example PEN 32473)
+const syslogSDID = "minibeast@32473"
+
+// Syslog severities (RFC 5424 §6.2.1)
+const (
+	syslogCritical = 2
+	syslogError    = 3
+	syslogWarning  = 4
+	syslogNotice   = 5
+	syslogInfo     = 6
+)
+
+// SyslogExporter emits the run summary and each finding as RFC 5424 messages,
+// followed with syslog.facts by the flattened facts (one message per host,
+// user, volume, interface and listening port)
+// Transports: UDP (one datagram per message), TCP and TLS (octet-counting framing, RFC 6587/5425)
+type SyslogExporter struct {
+	cfg       config.SyslogConfig
+	tlsConfig *tls.Config
+}
+
+// NewSyslogExporter creates a syslog exporter
+// Complexity: O(1) (plus CA bundle parsing for TLS)
+func NewSyslogExporter(cfg config.SyslogConfig) (*SyslogExporter, error) {
+	exp := &SyslogExporter{cfg: cfg}
+
+	if cfg.Network == "tls" {
+		tlsConfig, err := loadTLSConfig(cfg.Address, cfg.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		exp.tlsConfig = tlsConfig
+	}
+
+	return exp, nil
+}
+
+// Name returns "syslog"
+func (e *SyslogExporter) Name() string { return "syslog" }
+
+// Export sends one RUN message followed by one FINDING message per risk
+// Complexity: O(|risks|) messages over a single connection
+func (e *SyslogExporter) Export(ctx context.Context, p *Payload) error {
+	messages, err := e.Messages(p)
+	if err != nil {
+		return err
+	}
+
+	timeout := time.Duration(e.cfg.TimeoutMs) * time.Millisecond
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	conn, err := e.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to syslog collector: %w", err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetWriteDeadline(deadline)
+	}
+
+	for _, msg := range messages {
+		frame := []byte(msg)
+		if e.cfg.Network != "udp" {
+			frame = []byte(strconv.Itoa(len(msg)) + " " + msg)
+		}
+		if _, err := conn.Write(frame); err != nil {
+			return fmt.Errorf("failed to send syslog message: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Messages formats the RFC 5424 messages for a payload
+// Mathematical property: Same Payload → Same messages
+// Complexity: O(|risks|)
+func (e *SyslogExporter) Messages(p *Payload) ([]string, error) {
+	if p == nil || p.Facts == nil {
+		return nil, fmt.Errorf("payload facts cannot be nil")
+	}
+
+	if e.cfg.Format == "cef" || e.cfg.Format == "leef" {
+		return e.siemMessages(p)
+	}
+
+	f := p.Facts
+	findings := 0
+	if p.Report != nil {
+		findings = len(p.Report.Risks)
+	}
+
+	messages := []string{
+		e.format(syslogInfo, f.Hostname, f.Timestamp, "RUN", [][2]string{
+			{"run_id", p.RunID},
+			{"hardware_uuid", f.HardwareUUID},
+			{"os", f.OSName + " " + f.OSVersion},
+			{"findings", strconv.Itoa(findings)},
+		}, fmt.Sprintf("MiniBeast collection completed on %s (%d findings)", f.Hostname, findings)),
+	}
+
+	if p.Report != nil {
+		for _, risk := range p.Report.Risks {
+			messages = append(messages, e.format(severityToSyslog(risk.Severity), f.Hostname, f.Timestamp, "FINDING", [][2]string{
+				{"run_id", p.RunID},
+				{"finding_id", risk.FindingID},
+				{"severity", risk.Severity.String()},
+				{"confidence", string(risk.Confidence)},
+			}, risk.Text))
+		}
+	}
+
+	if e.cfg.Facts {
+		for _, ev := range factEvents(p) {
+			messages = append(messages, e.format(syslogInfo, f.Hostname, f.Timestamp, strings.ToUpper(ev.Category), siemAttributes(ev), ev.Name))
+		}
+	}
+
+	return messages, nil
+}
+
+// siemMessages wraps CEF/LEEF lines as the MSG of RFC 5424 messages without structured data
+func (e *SyslogExporter) siemMessages(p *Payload) ([]string, error) {
+	events, err := siemEvents(p)
+	if err != nil {
+		return nil, err
+	}
+	if e.cfg.Facts {
+		events = append(events, factEvents(p)...)
+	}
+
+	render := renderCEF
+	if e.cfg.Format == "leef" {
+		render = renderLEEF
+	}
+	lines := render(events, p.Facts.CollectorVersion)
+
+	messages := make([]string, 0, len(lines))
+	for i, line := range lines {
+		msgID := strings.ToUpper(events[i].Category)
+		messages = append(messages, e.header(severityToSyslog(events[i].Severity), p.Facts.Hostname, p.Facts.Timestamp, msgID)+" - "+line)
+	}
+	return messages, nil
+}
+
+// format builds a single RFC 5424 message
+// Layout: <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [SD] MSG
+func (e *SyslogExporter) format(severity int, hostname string, ts time.Time, msgID string, params [][2]string, msg string) string {
+	var sd strings.Builder
+	sd.WriteString("[")
+	sd.WriteString(syslogSDID)
+	for _, kv := range params {
+		if kv[1] == "" {
+			continue
+		}
+		sd.WriteString(" ")
+		sd.WriteString(kv[0])
+		sd.WriteString(`="`)
+		sd.WriteString(escapeSDParam(kv[1]))
+		sd.WriteString(`"`)
+	}
+	sd.WriteString("]")
+
+	return e.header(severity, hostname, ts, msgID) + " " + sd.String() + " " + msg
+}
+
+// header builds the RFC 5424 HEADER: <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID
+func (e *SyslogExporter) header(severity int, hostname string, ts time.Time, msgID string) string {
+	return fmt.Sprintf("<%d>1 %s %s %s %d %s",
+		e.cfg.Facility*8+severity,
+		ts.UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		headerField(hostname, 255),
+		headerField(e.cfg.AppName, 48),
+		os.Getpid(),
+		msgID,
+	)
+}
+
+// dial opens the configured transport
+func (e *SyslogExporter) dial(ctx context.Context) (net.Conn, error) {
+	if e.cfg.Network == "tls" {
+		dialer := &tls.Dialer{Config: e.tlsConfig}
+		return dialer.DialContext(ctx, "tcp", e.cfg.Address)
+	}
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, e.cfg.Network, e.cfg.Address)
+}
+
+// severityToSyslog maps report severities to syslog severities
+func severityToSyslog(s report.Severity) int {
+	switch s {
+	case report.SeverityCritical:
+		return syslogCritical
+	case report.SeverityHigh:
+		return syslogError
+	case report.SeverityMedium:
+		return syslogWarning
+	case report.SeverityLow:
+		return syslogNotice
+	default:
+		return syslogInfo
+	}
+}
+
+// escapeSDParam escapes '"', '\' and ']' in SD-PARAM values (RFC 5424 §6.3.3)
+func escapeSDParam(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(v)
+}
+
+// headerField restricts a header value to PRINTUSASCII without spaces ("-" if empty)
+func headerField(v string, maxLen int) string {
+	var b strings.Builder
+	for _, r := range v {
+		if r > 32 && r < 127 {
+			b.WriteRune(r)
+		}
+	}
+	out := b.String()
+	if out == "" {
+		return "-"
+	}
+	if len(out) > maxLen {
+		out = out[:maxLen]
+	}
+	return out
+}
+
+// loadTLSConfig builds a client TLS config verifying against caFile (system roots if empty)
+func loadTLSConfig(address, caFile string) (*tls.Config, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %q: %w", address, err)
+	}
+
+	pool, err := loadCAPool(caFile)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{ServerName: host, RootCAs: pool, MinVersion: tls.VersionTLS12}, nil
+}
+
+// loadCAPool reads a PEM CA bundle (nil pool = system roots when caFile is empty)
+func loadCAPool(caFile string) (*x509.CertPool, error) {
+	if caFile == "" {
+		return nil, nil
+	}
+
+	pemData, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pemData) {
+		return nil, fmt.Errorf("no certificates found in CA file")
+	}
+	return pool, nil
+}
@@ -0,0 +1,32 @@
+package export
+
+import (
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/consent"
+	"github.com/minibeast/usb-agent/src/core/report"
+)
+
+// Payload is everything an exporter may emit for a single run
+type Payload struct {
+	RunID   string            // Run identifier attached to every record
+	Facts   *collection.Facts // Collected facts (required)
+	Report  *report.Report    // Structured report (nil when the LLM phase did not run)
+	Bundle  *Bundle           // Output bundle files (nil when not assembled)
+	Consent *consent.Record   // Operator acknowledgment (nil when none was given)
+}
+
+// Artifact is a single encoded output file
+type Artifact struct {
+	Suffix string // Appended to the run's base file name (e.g., ".jsonl", ".users.csv")
+	Data   []byte
+}
+
+// Encoder serializes a run Payload into one or more output artifacts
+// Mathematical contract: Same Payload → Same artifacts
+type Encoder interface {
+	// Name is the format identifier used in output.formats (e.g., "jsonl")
+	Name() string
+
+	// Encode serializes the payload
+	Encode(p *Payload) ([]Artifact, error)
+}
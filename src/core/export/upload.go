@@ -0,0 +1,164 @@
+package export
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+)
+
+// BundleFile is one file of an output bundle (e.g., facts JSON, signature, report)
+type BundleFile struct {
+	Name string
+	Data []byte
+}
+
+// Bundle is the set of signed output files produced by one run
+// Name is the shared base name (<hostname>_<uuid>_<timestamp>) and becomes
+// the remote directory; file names must be plain names without separators.
+type Bundle struct {
+	Name  string
+	Files []BundleFile
+}
+
+// Add appends a file to the bundle
+// Complexity: O(1) amortized
+func (b *Bundle) Add(name string, data []byte) {
+	b.Files = append(b.Files, BundleFile{Name: name, Data: data})
+}
+
+// Validate checks that bundle and file names are safe to use as path components
+// Complexity: O(|Files|)
+func (b *Bundle) Validate() error {
+	if err := validateName(b.Name); err != nil {
+		return fmt.Errorf("invalid bundle name %q: %w", b.Name, err)
+	}
+	if len(b.Files) == 0 {
+		return fmt.Errorf("bundle %s has no files", b.Name)
+	}
+	seen := make(map[string]bool, len(b.Files))
+	for _, f := range b.Files {
+		if err := validateName(f.Name); err != nil {
+			return fmt.Errorf("invalid file name %q: %w", f.Name, err)
+		}
+		if seen[f.Name] {
+			return fmt.Errorf("duplicate file name %q", f.Name)
+		}
+		seen[f.Name] = true
+	}
+	return nil
+}
+
+// validateName rejects empty, hidden and path-like names
+func validateName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("must not be empty")
+	case strings.HasPrefix(name, "."):
+		return fmt.Errorf("must not start with '.'")
+	case strings.ContainsAny(name, `/\`) || name != filepath.Base(name):
+		return fmt.Errorf("must not contain path separators")
+	}
+	return nil
+}
+
+// contentTypeFor returns the MIME type stored with an uploaded bundle file
+func contentTypeFor(name string) string {
+	if t := mime.TypeByExtension(filepath.Ext(name)); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
+
+// newUploadClient builds the HTTP client shared by the object storage backends
+// Complexity: O(1) (plus CA bundle parsing)
+func newUploadClient(caFile string, timeoutMs int) (*http.Client, error) {
+	pool, err := loadCAPool(caFile)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	return &http.Client{Transport: transport, Timeout: time.Duration(timeoutMs) * time.Millisecond}, nil
+}
+
+// accessToken is a cached OAuth 2.0 bearer token
+type accessToken struct {
+	Value   string
+	Expires time.Time
+}
+
+// fresh reports whether the token can be reused (5 minute refresh margin, as for S3)
+func (t *accessToken) fresh() bool {
+	return t != nil && time.Until(t.Expires) > 5*time.Minute
+}
+
+// Uploader delivers an output bundle to remote storage
+// Contract: Upload must be idempotent (re-uploading a bundle overwrites it)
+// so spooled bundles can be retried safely.
+type Uploader interface {
+	// Name identifies the uploader in logs, errors and spool paths (e.g., "s3")
+	Name() string
+
+	// Upload delivers every file of the bundle
+	Upload(ctx context.Context, b *Bundle) error
+}
+
+// UploadersFor builds every enabled uploader from output.upload
+// Complexity: O(|uploaders|)
+func UploadersFor(cfg *config.Config) ([]Uploader, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+
+	uploaders := []Uploader{}
+	uc := cfg.Output.Upload
+
+	if uc.S3.Enabled {
+		up, err := NewS3Uploader(uc.S3)
+		if err != nil {
+			return nil, fmt.Errorf("s3 uploader: %w", err)
+		}
+		uploaders = append(uploaders, up)
+	}
+
+	if uc.SFTP.Enabled {
+		up, err := NewSFTPUploader(uc.SFTP)
+		if err != nil {
+			return nil, fmt.Errorf("sftp uploader: %w", err)
+		}
+		uploaders = append(uploaders, up)
+	}
+
+	if uc.Azure.Enabled {
+		up, err := NewAzureBlobUploader(uc.Azure)
+		if err != nil {
+			return nil, fmt.Errorf("azure uploader: %w", err)
+		}
+		uploaders = append(uploaders, up)
+	}
+
+	if uc.GCS.Enabled {
+		up, err := NewGCSUploader(uc.GCS)
+		if err != nil {
+			return nil, fmt.Errorf("gcs uploader: %w", err)
+		}
+		uploaders = append(uploaders, up)
+	}
+
+	if uc.HTTPS.Enabled {
+		up, err := NewHTTPSUploader(uc.HTTPS)
+		if err != nil {
+			return nil, fmt.Errorf("https uploader: %w", err)
+		}
+		uploaders = append(uploaders, up)
+	}
+
+	return uploaders, nil
+}
@@ -0,0 +1,167 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/crypto"
+)
+
+// webhookRetryBase is the initial backoff between webhook retries
+const webhookRetryBase = 500 * time.Millisecond
+
+// Webhook signature headers
+const (
+	// WebhookSignatureHeader carries base64(Ed25519(SHA-256(body)))
+	WebhookSignatureHeader = "X-MiniBeast-Signature"
+
+	// WebhookKeyIDHeader carries the hex SHA-256 of the signing public key
+	WebhookKeyIDHeader = "X-MiniBeast-Key-Id"
+)
+
+// WebhookBody is the JSON document POSTed to the receiver
+type WebhookBody struct {
+	RunID       string          `json:"run_id"`
+	Hostname    string          `json:"hostname"`
+	Timestamp   time.Time       `json:"timestamp"`
+	FactsSHA256 string          `json:"facts_sha256"` // SHA-256 of facts.json as written
+	Report      json.RawMessage `json:"report"`       // report.json (null when the LLM phase did not run)
+}
+
+// WebhookExporter POSTs report.json and the facts hash, signed with the agent's Ed25519 key
+// The signature covers the exact request body using crypto.Signer, so a
+// receiver verifies it with crypto.Verify and the agent's public key.
+type WebhookExporter struct {
+	cfg    config.WebhookConfig
+	client *http.Client
+	signer *crypto.Signer
+	keyID  string
+}
+
+// NewWebhookExporter creates a webhook exporter
+// Complexity: O(1) (plus key and CA bundle parsing)
+func NewWebhookExporter(cfg config.WebhookConfig) (*WebhookExporter, error) {
+	privateKey, err := crypto.LoadPrivateKeyFromEnv(cfg.PrivateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load webhook signing key: %w", err)
+	}
+	return NewWebhookExporterWithKey(cfg, privateKey)
+}
+
+// NewWebhookExporterWithKey creates a webhook exporter signing with privateKey
+// Complexity: O(1) (plus CA bundle parsing)
+func NewWebhookExporterWithKey(cfg config.WebhookConfig, privateKey ed25519.PrivateKey) (*WebhookExporter, error) {
+	pool, err := loadCAPool(cfg.CAFile)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+
+	publicKey := privateKey.Public().(ed25519.PublicKey)
+	return &WebhookExporter{
+		cfg:    cfg,
+		client: &http.Client{Transport: transport, Timeout: time.Duration(cfg.TimeoutMs) * time.Millisecond},
+		signer: crypto.NewSigner(&crypto.KeyPair{PublicKey: publicKey, PrivateKey: privateKey}),
+		keyID:  WebhookKeyID(publicKey),
+	}, nil
+}
+
+// Name returns "webhook"
+func (e *WebhookExporter) Name() string { return "webhook" }
+
+// Export signs and POSTs the webhook body
+// Complexity: O(|Facts| + |Report|)
+func (e *WebhookExporter) Export(ctx context.Context, p *Payload) error {
+	body, err := e.Body(p)
+	if err != nil {
+		return err
+	}
+
+	signature, err := e.signer.Sign(body)
+	if err != nil {
+		return fmt.Errorf("failed to sign webhook body: %w", err)
+	}
+	encoded := base64.StdEncoding.EncodeToString(signature)
+
+	return withRetry(ctx, e.cfg.MaxRetries, webhookRetryBase, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.cfg.URL, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(WebhookSignatureHeader, encoded)
+		req.Header.Set(WebhookKeyIDHeader, e.keyID)
+
+		resp, err := e.client.Do(req)
+		if err != nil {
+			return &RetryableError{Err: err}
+		}
+		defer resp.Body.Close()
+		io.Copy(io.Discard, resp.Body)
+
+		return classifyHTTP(resp)
+	})
+}
+
+// Body encodes the webhook request body
+// Mathematical property: Same Payload → Same body (and therefore same signature)
+// Complexity: O(|Facts| + |Report|)
+func (e *WebhookExporter) Body(p *Payload) ([]byte, error) {
+	if p == nil || p.Facts == nil {
+		return nil, fmt.Errorf("payload facts cannot be nil")
+	}
+
+	factsJSON, err := marshalFacts(p.Facts)
+	if err != nil {
+		return nil, err
+	}
+	factsHash := sha256.Sum256(factsJSON)
+
+	reportJSON := json.RawMessage("null")
+	if p.Report != nil {
+		if reportJSON, err = p.Report.RenderJSON(); err != nil {
+			return nil, err
+		}
+	}
+
+	body, err := json.Marshal(WebhookBody{
+		RunID:       p.RunID,
+		Hostname:    p.Facts.Hostname,
+		Timestamp:   p.Facts.Timestamp,
+		FactsSHA256: hex.EncodeToString(factsHash[:]),
+		Report:      reportJSON,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal webhook body: %w", err)
+	}
+	return body, nil
+}
+
+// WebhookKeyID identifies a signing key (its crypto.Fingerprint)
+// Complexity: O(1)
+func WebhookKeyID(publicKey ed25519.PublicKey) string {
+	return crypto.Fingerprint(publicKey)
+}
+
+// VerifyWebhook checks a received body against its signature header
+// Complexity: O(n) where n = len(body)
+func VerifyWebhook(publicKey ed25519.PublicKey, body []byte, signatureHeader string) bool {
+	signature, err := base64.StdEncoding.DecodeString(signatureHeader)
+	if err != nil {
+		return false
+	}
+	return crypto.Verify(publicKey, body, crypto.Signature(signature))
+}
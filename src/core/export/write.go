@@ -0,0 +1,91 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+
+	coreio "github.com/minibeast/usb-agent/src/core/io"
+	"github.com/minibeast/usb-agent/src/core/logging"
+	"github.com/minibeast/usb-agent/src/core/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// WriteArtifacts encodes p with every encoder and writes each artifact
+// atomically to dir/<base><suffix>, returning the written paths in order
+// Complexity: O(|encoders| * |artifact|)
+func WriteArtifacts(ctx context.Context, dir, base string, p *Payload, encs []Encoder) ([]string, error) {
+	files, err := WriteArtifactFiles(ctx, dir, base, p, encs)
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.Path
+	}
+	return paths, err
+}
+
+// WriteArtifactFiles is WriteArtifacts returning each file's size and
+// SHA-256, computed while it was written so nothing is read back
+// Each encoder runs under its own "output.<format>" span.
+// Complexity: O(|encoders| * |artifact|)
+func WriteArtifactFiles(ctx context.Context, dir, base string, p *Payload, encs []Encoder) ([]*coreio.Written, error) {
+	return WriteArtifactFilesWith(ctx, coreio.NewWriter(), dir, base, p, encs)
+}
+
+// WriteArtifactFilesWith is WriteArtifactFiles through writer
+// With a SyncBatch writer the caller must Flush it before the files exist.
+// Complexity: O(|encoders| * |artifact|)
+func WriteArtifactFilesWith(ctx context.Context, writer *coreio.Writer, dir, base string, p *Payload, encs []Encoder) ([]*coreio.Written, error) {
+	ctx, span := telemetry.Tracer().Start(ctx, "output")
+	defer span.End()
+
+	var files []*coreio.Written
+	for _, enc := range encs {
+		written, err := writeEncoded(ctx, writer, dir, base, p, enc)
+		files = append(files, written...)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return files, err
+		}
+	}
+	return files, nil
+}
+
+// writeEncoded runs a single encoder and writes its artifacts
+func writeEncoded(ctx context.Context, writer *coreio.Writer, dir, base string, p *Payload, enc Encoder) ([]*coreio.Written, error) {
+	ctx, span := telemetry.Tracer().Start(ctx, "output."+enc.Name())
+	defer span.End()
+
+	log := logging.From(ctx).With("format", enc.Name())
+	artifacts, err := enc.Encode(p)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		log.Error("encode failed", "error", err)
+		return nil, fmt.Errorf("%s encode failed: %w", enc.Name(), err)
+	}
+	if e, ok := enc.(*EncryptedEncoder); ok {
+		log.Debug("artifacts encrypted", "artifacts", len(artifacts), "recipients", len(e.recipients))
+	}
+
+	var files []*coreio.Written
+	var size int
+	for _, a := range artifacts {
+		path := filepath.Join(dir, base+a.Suffix)
+		written, err := writer.WriteContext(ctx, path, bytes.NewReader(a.Data), 0644)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			log.Error("artifact write failed", "path", path, "error", err)
+			return files, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		files = append(files, written)
+		size += len(a.Data)
+	}
+	span.SetAttributes(
+		attribute.Int("output.artifacts", len(artifacts)),
+		attribute.Int("output.bytes", size),
+	)
+	return files, nil
+}
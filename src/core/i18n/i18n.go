@@ -0,0 +1,149 @@
+// Package i18n translates operator-facing CLI messages
+// Messages are fmt format strings keyed by ID in embedded JSON catalogs,
+// one per language. English is complete by definition; a message missing
+// from another catalog falls back to English, and an unknown ID renders as
+// itself. Machine-readable output (facts, reports, exporter records) is
+// never translated.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Default is the fallback language
+const Default = "en"
+
+//go:embed catalogs/*.json
+var catalogFS embed.FS
+
+// catalogs maps a language tag (e.g., "es", "pt-BR") to its messages
+var catalogs = mustLoad()
+
+var (
+	mu      sync.RWMutex
+	current = Default
+)
+
+// mustLoad parses the embedded catalogs (a malformed one is a build error)
+func mustLoad() map[string]map[string]string {
+	entries, err := catalogFS.ReadDir("catalogs")
+	if err != nil {
+		panic(err)
+	}
+	out := map[string]map[string]string{}
+	for _, e := range entries {
+		data, err := catalogFS.ReadFile(path.Join("catalogs", e.Name()))
+		if err != nil {
+			panic(err)
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: catalog %s: %v", e.Name(), err))
+		}
+		out[strings.TrimSuffix(e.Name(), ".json")] = messages
+	}
+	return out
+}
+
+// Languages returns the supported language tags, sorted
+// Complexity: O(n log n) where n = number of catalogs
+func Languages() []string {
+	tags := make([]string, 0, len(catalogs))
+	for tag := range catalogs {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// Match returns the supported language closest to a locale name
+// Accepts BCP 47 tags and POSIX locales ("es-MX", "es_MX.UTF-8",
+// "de_DE@euro"): an exact region match wins, then the base language.
+// Returns "" when neither is supported; "C" and "POSIX" mean English.
+// Complexity: O(|locale|)
+func Match(locale string) string {
+	locale, _, _ = strings.Cut(locale, ".")
+	locale, _, _ = strings.Cut(locale, "@")
+	if locale == "C" || locale == "POSIX" {
+		return Default
+	}
+	lang, region, _ := strings.Cut(strings.ReplaceAll(locale, "_", "-"), "-")
+	lang = strings.ToLower(lang)
+	if region != "" {
+		if tag := lang + "-" + strings.ToUpper(region); catalogs[tag] != nil {
+			return tag
+		}
+	}
+	if catalogs[lang] != nil {
+		return lang
+	}
+	return ""
+}
+
+// Detect picks the language for this process
+// MINIBEAST_LANG wins, then configured (the locale config key), then
+// LC_ALL, LC_MESSAGES and LANG, then the OS user interface languages. The
+// first source naming a supported language decides; English otherwise.
+// Complexity: O(1)
+func Detect(configured string, getenv func(string) string) string {
+	candidates := []string{getenv("MINIBEAST_LANG"), configured}
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		candidates = append(candidates, getenv(name))
+	}
+	candidates = append(candidates, platformLanguages()...)
+	for _, c := range candidates {
+		if c == "" {
+			continue
+		}
+		if tag := Match(c); tag != "" {
+			return tag
+		}
+	}
+	return Default
+}
+
+// Use selects the language T translates into
+// An unsupported tag selects English.
+func Use(tag string) {
+	if catalogs[tag] == nil {
+		tag = Default
+	}
+	mu.Lock()
+	current = tag
+	mu.Unlock()
+}
+
+// Language returns the selected language
+func Language() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// T formats message id in the selected language
+// Complexity: O(|message|)
+func T(id string, args ...any) string {
+	return fmt.Sprintf(lookup(Language(), id), args...)
+}
+
+// Errorf is T for errors; %w in the message wraps as in fmt.Errorf
+func Errorf(id string, args ...any) error {
+	return fmt.Errorf(lookup(Language(), id), args...)
+}
+
+// lookup returns the format string for id in lang, falling back to English
+func lookup(lang, id string) string {
+	if msg, ok := catalogs[lang][id]; ok {
+		return msg
+	}
+	if msg, ok := catalogs[Default][id]; ok {
+		return msg
+	}
+	return id
+}
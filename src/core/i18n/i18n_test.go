@@ -0,0 +1,94 @@
+package i18n
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+// verbPattern matches fmt verbs (flags and width included) and %%
+var verbPattern = regexp.MustCompile(`%[-+# 0]*[0-9]*(?:\.[0-9]+)?[a-zA-Z%]`)
+
+// TestCatalogs_MatchEnglish verifies every catalog translates every message
+// with the same fmt verbs in the same order, so arguments line up
+func TestCatalogs_MatchEnglish(t *testing.T) {
+	base := catalogs[Default]
+	if len(catalogs) < 2 {
+		t.Fatalf("catalogs = %v, want English and at least one translation", Languages())
+	}
+	for lang, messages := range catalogs {
+		for id, msg := range base {
+			translated, ok := messages[id]
+			if !ok {
+				t.Errorf("%s: missing %s", lang, id)
+				continue
+			}
+			if want, got := verbPattern.FindAllString(msg, -1), verbPattern.FindAllString(translated, -1); !reflect.DeepEqual(want, got) {
+				t.Errorf("%s: %s verbs = %v, want %v", lang, id, got, want)
+			}
+		}
+		for id := range messages {
+			if _, ok := base[id]; !ok {
+				t.Errorf("%s: %s is not an English message", lang, id)
+			}
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	for locale, want := range map[string]string{
+		"es":          "es",
+		"es_MX.UTF-8": "es",
+		"fr-CA":       "fr",
+		"de_DE@euro":  "de",
+		"PT_br":       "pt",
+		"C":           "en",
+		"POSIX":       "en",
+		"ja_JP.UTF-8": "",
+		"":            "",
+	} {
+		if got := Match(locale); got != want {
+			t.Errorf("Match(%q) = %q, want %q", locale, got, want)
+		}
+	}
+}
+
+func TestDetect_Precedence(t *testing.T) {
+	env := map[string]string{"LANG": "de_DE.UTF-8", "LC_ALL": "ja_JP.UTF-8"}
+	getenv := func(name string) string { return env[name] }
+
+	// An unsupported LC_ALL falls through to LANG
+	if got := Detect("", getenv); got != "de" {
+		t.Errorf("Detect() = %q, want de", got)
+	}
+	if got := Detect("es", getenv); got != "es" {
+		t.Errorf("Detect(configured) = %q, want es", got)
+	}
+	env["MINIBEAST_LANG"] = "fr"
+	if got := Detect("es", getenv); got != "fr" {
+		t.Errorf("Detect() with MINIBEAST_LANG = %q, want fr", got)
+	}
+}
+
+func TestT_FallsBack(t *testing.T) {
+	defer Use(Language())
+
+	Use("es")
+	if got := T("main.unknown_command", "foo"); got != `minibeast: comando desconocido "foo"` {
+		t.Errorf("T() = %q", got)
+	}
+	if got := T("no.such.message"); got != "no.such.message" {
+		t.Errorf("T(unknown) = %q, want the ID", got)
+	}
+
+	Use("xx")
+	if Language() != Default {
+		t.Errorf("Use(unsupported) selected %q, want %q", Language(), Default)
+	}
+
+	sentinel := errors.New("usage")
+	if err := Errorf("consent.required", sentinel); !errors.Is(err, sentinel) {
+		t.Errorf("Errorf() = %v, does not wrap its %%w argument", err)
+	}
+}
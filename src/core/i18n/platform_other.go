@@ -0,0 +1,9 @@
+//go:build !windows
+
+package i18n
+
+// platformLanguages is empty: the locale environment variables are the
+// platform setting here
+func platformLanguages() []string {
+	return nil
+}
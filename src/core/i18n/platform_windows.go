@@ -0,0 +1,15 @@
+//go:build windows
+
+package i18n
+
+import "golang.org/x/sys/windows"
+
+// platformLanguages returns the user's preferred display languages, most
+// preferred first (e.g., "es-MX"); a console rarely sets LANG on Windows
+func platformLanguages() []string {
+	langs, err := windows.GetUserPreferredUILanguages(windows.MUI_LANGUAGE_NAME)
+	if err != nil {
+		return nil
+	}
+	return langs
+}
@@ -0,0 +1,25 @@
+package inference
+
+import "context"
+
+// Backend generates model output for prompts
+// Implemented by *Engine (bundled GGUF through llama.cpp, or the template
+// engine in pure-Go builds), *HTTPBackend (OpenAI-compatible server) and
+// *FakeEngine.
+type Backend interface {
+	// Load prepares the model (idempotent)
+	Load(ctx context.Context) error
+
+	// Generate produces model output for a prompt
+	Generate(ctx context.Context, prompt string) (*InferenceResult, error)
+
+	// Unload releases model resources
+	Unload() error
+}
+
+// Interface checks
+var (
+	_ Backend = (*Engine)(nil)
+	_ Backend = (*HTTPBackend)(nil)
+	_ Backend = (*FakeEngine)(nil)
+)
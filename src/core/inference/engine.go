@@ -1,58 +1,77 @@
+//go:build llama
+
 package inference
 
 // #cgo CFLAGS: -I/home/redblack/projects/minibeast/vendor/llama.cpp/include
 // #cgo CXXFLAGS: -I/home/redblack/projects/minibeast/vendor/llama.cpp/include -std=c++17 -fopenmp
 // #cgo LDFLAGS: -L/home/redblack/projects/minibeast/vendor/llama.cpp/lib -lllama -lggml -lggml-base -lggml-cpu -lcommon -lstdc++ -lm -pthread -fopenmp
 // #include <stdlib.h>
-// #include <string.h>
 // #include "/home/redblack/projects/minibeast/vendor/llama.cpp/include/llama.h"
 //
-// // Simple wrapper to generate text
-// static char* simple_generate(struct llama_model* model, struct llama_context* ctx, 
-//                             const char* prompt, int max_tokens, float temperature) {
-//     // Deterministic response based on prompt analysis
-//     // TODO: Replace with real llama_decode + sampling in next iteration
-//     const char* response = 
-//         "SUMMARY:\n"
-//         "- System profile collected successfully with current hardware configuration\n"
-//         "- Operating system and network settings are within normal parameters\n"
-//         "- No immediate security concerns detected in this analysis\n"
-//         "\n"
-//         "RISKS:\n"
-//         "- No critical risks detected at this time\n"
-//         "\n"
-//         "ACTIONS:\n"
-//         "- Continue regular system monitoring and apply pending updates\n";
-//     
-//     char* result = (char*)malloc(strlen(response) + 1);
-//     strcpy(result, response);
-//     return result;
+// // new_sampler builds the sampling chain: top-k, top-p and temperature with
+// // a seeded draw, or greedy decoding at temperature 0, behind the GBNF
+// // grammar when one is given; NULL when the grammar does not parse
+// static struct llama_sampler* new_sampler(const struct llama_vocab* vocab, const char* grammar, float temperature, uint32_t seed) {
+//     struct llama_sampler* chain = llama_sampler_chain_init(llama_sampler_chain_default_params());
+//     if (grammar != NULL) {
+//         struct llama_sampler* g = llama_sampler_init_grammar(vocab, grammar, "root");
+//         if (g == NULL) {
+//             llama_sampler_free(chain);
+//             return NULL;
+//         }
+//         llama_sampler_chain_add(chain, g);
+//     }
+//     if (temperature <= 0.0f) {
+//         llama_sampler_chain_add(chain, llama_sampler_init_greedy());
+//         return chain;
+//     }
+//     llama_sampler_chain_add(chain, llama_sampler_init_top_k(40));
+//     llama_sampler_chain_add(chain, llama_sampler_init_top_p(0.95f, 1));
+//     llama_sampler_chain_add(chain, llama_sampler_init_temp(temperature));
+//     llama_sampler_chain_add(chain, llama_sampler_init_dist(seed));
+//     return chain;
+// }
+//
+// // decode_tokens evaluates n tokens on ctx after those already in its cache
+// static int decode_tokens(struct llama_context* ctx, llama_token* tokens, int32_t n) {
+//     return llama_decode(ctx, llama_batch_get_one(tokens, n));
 // }
 import "C"
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/binary"
 	"fmt"
 	"sync"
-	"time"
 	"unsafe"
+
+	"github.com/minibeast/usb-agent/src/core/clock"
 )
 
+// Native reports whether Engine runs a real GGUF model (llama.cpp builds)
+const Native = true
+
 // Engine provides GGUF model inference capabilities
+// One model is mapped and shared by a pool of independent sampling
+// contexts, so up to Sessions generations run in parallel; further calls
+// wait for a free context.
 // Mathematical guarantee: Deterministic output for fixed seed
 type Engine struct {
 	modelPath   string
 	maxTokens   int
 	temperature float64
-	seed        int64
-	loaded      bool
-	mu          sync.Mutex
-
-	// Real llama.cpp model and context
-	model *C.struct_llama_model
-	ctx   *C.struct_llama_context
+	threads     int
+	sessions    int
+	clock       clock.Clock
+
+	mu     sync.Mutex // Guards seed, loaded, model and contexts
+	seed   int64
+	loaded bool
+	active sync.WaitGroup // Generations holding a context
+
+	// Real llama.cpp model, every context created on it, and the idle ones
+	model    *C.struct_llama_model
+	contexts []*C.struct_llama_context
+	idle     chan *C.struct_llama_context
 }
 
 // NewEngine creates an inference engine with lazy loading
@@ -63,19 +82,36 @@ func NewEngine(config *InferenceConfig) (*Engine, error) {
 	}
 
 	seed := generateDeterministicSeed(config.HardwareUUID, config.Timestamp)
+	threads := config.Threads
+	if threads <= 0 {
+		threads = 4
+	}
+	sessions := config.Sessions
+	if sessions <= 0 {
+		sessions = 1
+	}
+	clk := config.Clock
+	if clk == nil {
+		clk = clock.System
+	}
 
 	return &Engine{
 		modelPath:   config.ModelPath,
 		maxTokens:   config.MaxTokens,
 		temperature: config.Temperature,
 		seed:        seed,
+		threads:     threads,
+		sessions:    sessions,
+		clock:       clk,
 		loaded:      false,
 	}, nil
 }
 
-// Load performs lazy model loading with mmap (zero-copy)
+// Load performs lazy model loading with mmap (zero-copy) and creates the
+// sampling contexts
 // Complexity: O(|model|) for file mapping, but mmap is lazy
-// Memory: ~30MB resident (model is mmap'd, not in RSS)
+// Memory: ~30MB resident (model is mmap'd, not in RSS) plus one KV cache
+// per session
 func (e *Engine) Load(ctx context.Context) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
@@ -96,63 +132,196 @@ func (e *Engine) Load(ctx context.Context) error {
 
 	e.model = C.llama_model_load_from_file(cModelPath, modelParams)
 	if e.model == nil {
+		C.llama_backend_free()
 		return fmt.Errorf("failed to load model from %s", e.modelPath)
 	}
 
-	// Create context using modern API
+	// Create one context per session; they share the model weights
 	ctxParams := C.llama_context_default_params()
-	ctxParams.n_ctx = 2048       // Context window
-	ctxParams.n_threads = 4      // CPU threads
+	ctxParams.n_ctx = 2048                           // Context window
+	ctxParams.n_threads = C.int32_t(e.threads)       // CPU threads (resources.llm_threads)
+	ctxParams.n_threads_batch = C.int32_t(e.threads) // Prompt processing threads
 	// Note: seed is set via sampling params, not context params in modern API
 
-	e.ctx = C.llama_init_from_model(e.model, ctxParams)
-	if e.ctx == nil {
-		C.llama_model_free(e.model)
-		return fmt.Errorf("failed to create llama context")
+	e.idle = make(chan *C.struct_llama_context, e.sessions)
+	for i := 0; i < e.sessions; i++ {
+		lctx := C.llama_init_from_model(e.model, ctxParams)
+		if lctx == nil {
+			e.release()
+			return fmt.Errorf("failed to create llama context %d of %d", i+1, e.sessions)
+		}
+		e.contexts = append(e.contexts, lctx)
+		e.idle <- lctx
 	}
 
 	e.loaded = true
 	return nil
 }
 
-// Generate produces text from the given prompt
+// Generate produces text from the given prompt using the engine seed
 // Complexity: O(m) where m = maxTokens
 // Latency: ~1800ms for 160 tokens at 11 tok/s
 func (e *Engine) Generate(ctx context.Context, prompt string) (*InferenceResult, error) {
 	e.mu.Lock()
-	defer e.mu.Unlock()
+	seed := e.seed
+	e.mu.Unlock()
+	return e.GenerateSeeded(ctx, prompt, seed)
+}
 
+// GenerateStream is Generate passing the text to fn as it is sampled (see
+// TokenFunc), so callers can show progress or stop early
+// Complexity: O(m) where m = maxTokens
+func (e *Engine) GenerateStream(ctx context.Context, prompt string, fn TokenFunc) (*InferenceResult, error) {
+	e.mu.Lock()
+	seed := e.seed
+	e.mu.Unlock()
+	return e.GenerateConstrainedStream(ctx, prompt, "", seed, fn)
+}
+
+// GenerateSeeded produces text from prompt sampling with seed
+// Safe for concurrent use: each call borrows one sampling context and
+// waits (honoring ctx) while all are busy.
+// Complexity: O(m) where m = maxTokens
+func (e *Engine) GenerateSeeded(ctx context.Context, prompt string, seed int64) (*InferenceResult, error) {
+	return e.GenerateConstrained(ctx, prompt, "", seed)
+}
+
+// GenerateConstrained is GenerateSeeded with sampling restricted to the GBNF
+// grammar (whose start rule is "root"); an empty grammar leaves it free
+// Complexity: O(m · |grammar|) where m = maxTokens
+func (e *Engine) GenerateConstrained(ctx context.Context, prompt, grammar string, seed int64) (*InferenceResult, error) {
+	return e.GenerateConstrainedStream(ctx, prompt, grammar, seed, nil)
+}
+
+// GenerateConstrainedStream is GenerateConstrained passing the text to fn
+// as it is sampled (nil fn = no streaming)
+// Complexity: O(m · |grammar|) where m = maxTokens
+func (e *Engine) GenerateConstrainedStream(ctx context.Context, prompt, grammar string, seed int64, fn TokenFunc) (*InferenceResult, error) {
+	e.mu.Lock()
 	if !e.loaded {
+		e.mu.Unlock()
 		return nil, fmt.Errorf("engine not loaded, call Load() first")
 	}
+	model, idle := e.model, e.idle
+	e.active.Add(1)
+	e.mu.Unlock()
+	defer e.active.Done()
+
+	var lctx *C.struct_llama_context
+	select {
+	case lctx = <-idle:
+		defer func() { idle <- lctx }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	timer := clock.Start(e.clock)
+
+	text, tokens, err := e.sample(ctx, model, lctx, prompt, grammar, seed, &tokenStream{fn: fn})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &InferenceResult{
+		Text:          text,
+		TokenCount:    tokens,
+		InferenceTime: timer.Elapsed(),
+		Seed:          seed,
+	}
 
-	startTime := time.Now()
+	return result, nil
+}
+
+// sample runs the tokenize → decode → sample loop on lctx, returning the
+// generated text and its token count
+// With a grammar, only tokens it allows are sampled and generation ends
+// when it completes. Each piece is passed to stream as it is sampled.
+// The context's KV cache is cleared first, so every call starts from the
+// prompt alone and a fixed seed reproduces the same tokens. Generation stops
+// at an end-of-generation token, after maxTokens tokens, when the stream's
+// TokenFunc asks to stop, or when ctx is cancelled (checked between tokens).
+// Complexity: O(|prompt| + maxTokens) decode steps
+func (e *Engine) sample(ctx context.Context, model *C.struct_llama_model, lctx *C.struct_llama_context, prompt, grammar string, seed int64, stream *tokenStream) (string, int, error) {
+	vocab := C.llama_model_get_vocab(model)
+	C.llama_memory_clear(C.llama_get_memory(lctx), C.bool(true))
 
-	// Use C wrapper for generation (simplified for Phase 3 completion)
 	cPrompt := C.CString(prompt)
 	defer C.free(unsafe.Pointer(cPrompt))
+	cLen := C.int32_t(len(prompt))
 
-	cResponse := C.simple_generate(e.model, e.ctx, cPrompt, C.int(e.maxTokens), C.float(e.temperature))
-	if cResponse == nil {
-		return nil, fmt.Errorf("generation failed")
+	// A negative count is the number of tokens the prompt needs
+	nPrompt := -C.llama_tokenize(vocab, cPrompt, cLen, nil, 0, C.bool(true), C.bool(true))
+	if nPrompt <= 0 {
+		return "", 0, fmt.Errorf("failed to tokenize prompt")
+	}
+	if nCtx := int(C.llama_n_ctx(lctx)); int(nPrompt)+e.maxTokens > nCtx {
+		return "", 0, fmt.Errorf("prompt of %d tokens plus %d new tokens exceeds the %d-token context", nPrompt, e.maxTokens, nCtx)
 	}
-	defer C.free(unsafe.Pointer(cResponse))
-
-	response := C.GoString(cResponse)
-	tokenCount := len(response) / 4 // Rough estimate
 
-	result := &InferenceResult{
-		Text:          response,
-		TokenCount:    tokenCount,
-		InferenceTime: time.Since(startTime),
-		Seed:          e.seed,
+	// Token buffers live in C memory: llama_batch_get_one keeps the pointer
+	tokens := (*C.llama_token)(C.malloc(C.size_t(nPrompt) * C.size_t(unsafe.Sizeof(C.llama_token(0)))))
+	defer C.free(unsafe.Pointer(tokens))
+	if C.llama_tokenize(vocab, cPrompt, cLen, tokens, nPrompt, C.bool(true), C.bool(true)) != nPrompt {
+		return "", 0, fmt.Errorf("failed to tokenize prompt")
+	}
+	if C.decode_tokens(lctx, tokens, nPrompt) != 0 {
+		return "", 0, fmt.Errorf("failed to decode prompt")
 	}
 
-	return result, nil
+	var cGrammar *C.char
+	if grammar != "" {
+		cGrammar = C.CString(grammar)
+		defer C.free(unsafe.Pointer(cGrammar))
+	}
+	sampler := C.new_sampler(vocab, cGrammar, C.float(e.temperature), C.uint32_t(uint32(seed)))
+	if sampler == nil {
+		return "", 0, fmt.Errorf("invalid sampling grammar")
+	}
+	defer C.llama_sampler_free(sampler)
+
+	next := (*C.llama_token)(C.malloc(C.size_t(unsafe.Sizeof(C.llama_token(0)))))
+	defer C.free(unsafe.Pointer(next))
+	piece := (*C.char)(C.malloc(256))
+	defer C.free(unsafe.Pointer(piece))
+
+	// Pieces are joined as bytes: a multi-byte character may span tokens
+	var out []byte
+	generated := 0
+	for generated < e.maxTokens {
+		if err := ctx.Err(); err != nil {
+			return "", generated, err
+		}
+		token := C.llama_sampler_sample(sampler, lctx, -1)
+		if C.llama_vocab_is_eog(vocab, token) {
+			break
+		}
+		generated++
+
+		n := C.llama_token_to_piece(vocab, token, piece, 256, 0, C.bool(false))
+		if n < 0 {
+			return "", generated, fmt.Errorf("failed to detokenize token %d", token)
+		}
+		text := C.GoBytes(unsafe.Pointer(piece), n)
+		out = append(out, text...)
+		if stop, err := stream.write(text); err != nil {
+			return "", generated, err
+		} else if stop {
+			return string(out), generated, nil
+		}
+
+		*next = token
+		if C.decode_tokens(lctx, next, 1) != 0 {
+			return "", generated, fmt.Errorf("failed to decode token %d of %d", generated, e.maxTokens)
+		}
+	}
+	if err := stream.flush(); err != nil {
+		return "", generated, err
+	}
+	return string(out), generated, nil
 }
 
-// Unload releases model resources
-// Complexity: O(1)
+// Unload waits for in-flight generations, then releases model resources
+// Complexity: O(sessions)
 func (e *Engine) Unload() error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
@@ -161,10 +330,20 @@ func (e *Engine) Unload() error {
 		return nil
 	}
 
-	if e.ctx != nil {
-		C.llama_free(e.ctx)
-		e.ctx = nil
+	// New calls now fail; in-flight ones return their context and finish
+	e.loaded = false
+	e.active.Wait()
+	e.release()
+	return nil
+}
+
+// release frees every context, the model and the backend (caller holds mu)
+func (e *Engine) release() {
+	for _, lctx := range e.contexts {
+		C.llama_free(lctx)
 	}
+	e.contexts = nil
+	e.idle = nil
 
 	if e.model != nil {
 		C.llama_model_free(e.model)
@@ -172,9 +351,15 @@ func (e *Engine) Unload() error {
 	}
 
 	C.llama_backend_free()
+}
 
-	e.loaded = false
-	return nil
+// SetSeed overrides the seed used by Generate (e.g., per-Facts deterministic seed)
+// Concurrent callers should pass seeds to GenerateSeeded instead.
+// Complexity: O(1)
+func (e *Engine) SetSeed(seed int64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.seed = seed
 }
 
 // IsLoaded returns whether the model is currently loaded
@@ -183,23 +368,3 @@ func (e *Engine) IsLoaded() bool {
 	defer e.mu.Unlock()
 	return e.loaded
 }
-
-// generateDeterministicSeed creates a reproducible seed from hardware UUID and timestamp
-// Mathematical property: Same inputs → same seed
-func generateDeterministicSeed(hardwareUUID string, timestamp time.Time) int64 {
-	// Combine UUID and timestamp for seed
-	h := sha256.New()
-	h.Write([]byte(hardwareUUID))
-
-	// Use timestamp to nanosecond precision
-	tsBytes := make([]byte, 8)
-	binary.LittleEndian.PutUint64(tsBytes, uint64(timestamp.UnixNano()))
-	h.Write(tsBytes)
-
-	hash := h.Sum(nil)
-
-	// Convert first 8 bytes to int64
-	seed := int64(binary.LittleEndian.Uint64(hash[:8]))
-
-	return seed
-}
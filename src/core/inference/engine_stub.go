@@ -0,0 +1,96 @@
+//go:build !llama
+
+package inference
+
+import (
+	"context"
+	"fmt"
+)
+
+// Native reports whether Engine runs a real GGUF model (llama.cpp builds)
+const Native = false
+
+// Engine provides inference for builds without llama.cpp (pure Go)
+// Generation is delegated to a FakeEngine, producing the template report
+// used by the Phase 1 Windows/macOS binaries. Build with -tags llama for
+// real GGUF inference.
+type Engine struct {
+	modelPath   string
+	maxTokens   int
+	temperature float64
+
+	fake *FakeEngine
+}
+
+// NewEngine creates a pure-Go inference engine
+// Complexity: O(1)
+func NewEngine(config *InferenceConfig) (*Engine, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+
+	fake := NewFakeEngine()
+	fake.SetSeed(generateDeterministicSeed(config.HardwareUUID, config.Timestamp))
+
+	return &Engine{
+		modelPath:   config.ModelPath,
+		maxTokens:   config.MaxTokens,
+		temperature: config.Temperature,
+		fake:        fake,
+	}, nil
+}
+
+// Load marks the engine ready (no model is mapped in pure-Go builds)
+// Complexity: O(1)
+func (e *Engine) Load(ctx context.Context) error {
+	return e.fake.Load(ctx)
+}
+
+// Generate returns the deterministic template response
+// Complexity: O(1)
+func (e *Engine) Generate(ctx context.Context, prompt string) (*InferenceResult, error) {
+	return e.fake.Generate(ctx, prompt)
+}
+
+// GenerateStream returns the template response, passing it to fn a word
+// at a time
+// Complexity: O(|response|)
+func (e *Engine) GenerateStream(ctx context.Context, prompt string, fn TokenFunc) (*InferenceResult, error) {
+	return e.fake.GenerateStream(ctx, prompt, fn)
+}
+
+// GenerateSeeded returns the template response reporting seed
+// Complexity: O(1)
+func (e *Engine) GenerateSeeded(ctx context.Context, prompt string, seed int64) (*InferenceResult, error) {
+	return e.fake.GenerateSeeded(ctx, prompt, seed)
+}
+
+// GenerateConstrained returns the template response, which already follows
+// ReportGrammar; other grammars are not enforced
+// Complexity: O(1)
+func (e *Engine) GenerateConstrained(ctx context.Context, prompt, grammar string, seed int64) (*InferenceResult, error) {
+	return e.fake.GenerateConstrained(ctx, prompt, grammar, seed)
+}
+
+// GenerateConstrainedStream is GenerateConstrained passing the response to
+// fn a word at a time
+// Complexity: O(|response|)
+func (e *Engine) GenerateConstrainedStream(ctx context.Context, prompt, grammar string, seed int64, fn TokenFunc) (*InferenceResult, error) {
+	return e.fake.GenerateConstrainedStream(ctx, prompt, grammar, seed, fn)
+}
+
+// Unload releases engine state
+// Complexity: O(1)
+func (e *Engine) Unload() error {
+	return e.fake.Unload()
+}
+
+// SetSeed overrides the sampling seed
+func (e *Engine) SetSeed(seed int64) {
+	e.fake.SetSeed(seed)
+}
+
+// IsLoaded returns whether the engine is currently loaded
+func (e *Engine) IsLoaded() bool {
+	return e.fake.IsLoaded()
+}
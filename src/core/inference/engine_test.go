@@ -0,0 +1,74 @@
+//go:build llama
+
+package inference
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestEngine_Sampling verifies real generation is bounded by MaxTokens and
+// reproducible for a fixed seed (set MINIBEAST_TEST_MODEL to a GGUF file)
+func TestEngine_Sampling(t *testing.T) {
+	path := os.Getenv("MINIBEAST_TEST_MODEL")
+	if path == "" {
+		t.Skip("MINIBEAST_TEST_MODEL not set")
+	}
+	engine, err := NewEngine(&InferenceConfig{ModelPath: path, MaxTokens: 24, Temperature: 0.8, Sessions: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := engine.Load(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer engine.Unload()
+
+	first, err := engine.GenerateSeeded(ctx, "List three colors:", 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.TokenCount == 0 || first.TokenCount > 24 || first.Text == "" {
+		t.Errorf("TokenCount = %d, Text = %q; want 1-24 tokens of output", first.TokenCount, first.Text)
+	}
+	again, err := engine.GenerateSeeded(ctx, "List three colors:", 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Text != first.Text {
+		t.Errorf("same seed, different output:\n%q\n%q", first.Text, again.Text)
+	}
+}
+
+// TestEngine_Grammar verifies constrained output follows ReportGrammar and parses
+func TestEngine_Grammar(t *testing.T) {
+	path := os.Getenv("MINIBEAST_TEST_MODEL")
+	if path == "" {
+		t.Skip("MINIBEAST_TEST_MODEL not set")
+	}
+	engine, err := NewEngine(&InferenceConfig{ModelPath: path, MaxTokens: 160, Temperature: 0.1, Sessions: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := engine.Load(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer engine.Unload()
+
+	result, err := engine.GenerateConstrained(ctx, "Describe a Linux laptop in three bullets:", ReportGrammar, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(result.Text, "SUMMARY:\n- ") {
+		t.Errorf("output does not follow the grammar: %q", result.Text)
+	}
+	if _, err := NewParser().Parse(result.Text); err != nil {
+		t.Errorf("Parse() of constrained output failed: %v", err)
+	}
+	if _, err := engine.GenerateConstrained(ctx, "x", "root ::= (", 42); err == nil {
+		t.Error("GenerateConstrained() accepted an invalid grammar")
+	}
+}
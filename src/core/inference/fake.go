@@ -0,0 +1,188 @@
+package inference
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultFakeResponse is the canned model output returned by FakeEngine
+const DefaultFakeResponse = `SUMMARY:
+- System profile collected successfully with current hardware configuration
+- Operating system and network settings are within normal parameters
+- No immediate security concerns detected in this analysis
+
+RISKS:
+- No critical risks detected at this time
+
+ACTIONS:
+- Continue regular system monitoring and apply pending updates`
+
+// FakeEngine is a deterministic in-memory engine for tests and pure-Go builds
+// Mathematical guarantee: Same Response → same InferenceResult.Text (no model required)
+type FakeEngine struct {
+	Response    string // Text returned by Generate (DefaultFakeResponse if empty)
+	LoadErr     error  // Returned by Load when set
+	GenerateErr error  // Returned by Generate when set
+
+	mu       sync.Mutex
+	loaded   bool
+	seed     int64
+	prompts  []string
+	grammars []string // Per prompt; "" when unconstrained
+}
+
+// NewFakeEngine creates a fake engine returning DefaultFakeResponse
+// Complexity: O(1)
+func NewFakeEngine() *FakeEngine {
+	return &FakeEngine{Response: DefaultFakeResponse}
+}
+
+// Load marks the engine loaded (or returns LoadErr)
+// Complexity: O(1)
+func (f *FakeEngine) Load(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.LoadErr != nil {
+		return f.LoadErr
+	}
+	f.loaded = true
+	return nil
+}
+
+// Generate returns the canned response and records the prompt
+// Complexity: O(1)
+func (f *FakeEngine) Generate(ctx context.Context, prompt string) (*InferenceResult, error) {
+	f.mu.Lock()
+	seed := f.seed
+	f.mu.Unlock()
+	return f.GenerateSeeded(ctx, prompt, seed)
+}
+
+// GenerateStream is Generate passing the response to fn a word at a time
+// Complexity: O(|Response|)
+func (f *FakeEngine) GenerateStream(ctx context.Context, prompt string, fn TokenFunc) (*InferenceResult, error) {
+	f.mu.Lock()
+	seed := f.seed
+	f.mu.Unlock()
+	return f.GenerateConstrainedStream(ctx, prompt, "", seed, fn)
+}
+
+// GenerateSeeded is Generate with a per-call seed (SetSeed is not consulted)
+// Complexity: O(1)
+func (f *FakeEngine) GenerateSeeded(ctx context.Context, prompt string, seed int64) (*InferenceResult, error) {
+	return f.GenerateConstrained(ctx, prompt, "", seed)
+}
+
+// GenerateConstrained is GenerateSeeded recording grammar; Response is
+// returned as is, whether or not it follows the grammar
+// Complexity: O(1)
+func (f *FakeEngine) GenerateConstrained(ctx context.Context, prompt, grammar string, seed int64) (*InferenceResult, error) {
+	return f.GenerateConstrainedStream(ctx, prompt, grammar, seed, nil)
+}
+
+// GenerateConstrainedStream is GenerateConstrained passing the response to
+// fn a word at a time (each word with the whitespace after it)
+// fn runs without the engine's lock held.
+// Complexity: O(|Response|)
+func (f *FakeEngine) GenerateConstrainedStream(ctx context.Context, prompt, grammar string, seed int64, fn TokenFunc) (*InferenceResult, error) {
+	f.mu.Lock()
+	if !f.loaded {
+		f.mu.Unlock()
+		return nil, fmt.Errorf("engine not loaded, call Load() first")
+	}
+	if f.GenerateErr != nil {
+		f.mu.Unlock()
+		return nil, f.GenerateErr
+	}
+	if err := ctx.Err(); err != nil {
+		f.mu.Unlock()
+		return nil, err
+	}
+
+	f.prompts = append(f.prompts, prompt)
+	f.grammars = append(f.grammars, grammar)
+
+	response := f.Response
+	if response == "" {
+		response = DefaultFakeResponse
+	}
+	f.mu.Unlock()
+
+	if fn != nil {
+		var err error
+		if response, err = streamWords(response, fn); err != nil {
+			return nil, err
+		}
+	}
+
+	return &InferenceResult{
+		Text:          response,
+		TokenCount:    len(response) / 4, // Rough token estimate
+		InferenceTime: time.Duration(0),
+		Seed:          seed,
+	}, nil
+}
+
+// Unload marks the engine unloaded
+// Complexity: O(1)
+func (f *FakeEngine) Unload() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.loaded = false
+	return nil
+}
+
+// SetSeed records the sampling seed reported in results
+func (f *FakeEngine) SetSeed(seed int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.seed = seed
+}
+
+// IsLoaded returns whether Load has succeeded
+func (f *FakeEngine) IsLoaded() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.loaded
+}
+
+// Prompts returns every prompt passed to Generate
+func (f *FakeEngine) Prompts() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string{}, f.prompts...)
+}
+
+// Grammars returns the grammar of every prompt in Prompts ("" when unconstrained)
+func (f *FakeEngine) Grammars() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string{}, f.grammars...)
+}
+
+// streamWords passes text to fn one word (with its trailing whitespace) at a
+// time, returning the text passed before fn asked to stop
+func streamWords(text string, fn TokenFunc) (string, error) {
+	end := 0
+	for end < len(text) {
+		next := strings.IndexAny(text[end:], " \n")
+		if next < 0 {
+			next = len(text)
+		} else {
+			next += end + 1
+		}
+		stop, err := callToken(fn, text[end:next])
+		if err != nil {
+			return "", err
+		}
+		end = next
+		if stop {
+			break
+		}
+	}
+	return text[:end], nil
+}
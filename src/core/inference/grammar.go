@@ -0,0 +1,11 @@
+package inference
+
+// ReportGrammar is the GBNF grammar of report output (llm.grammar)
+// Sampling under it can only produce the SUMMARY/RISKS/ACTIONS layout of
+// buildOutputFormatInstructions, with the section caps and the minimum item
+// length Validate enforces, so Parse never sees a missing or renamed header.
+// Output cut short by llm.max_tokens can still end mid-item.
+const ReportGrammar = `root    ::= "SUMMARY:\n" item item? item? "\nRISKS:\n" item? item? item? "\nACTIONS:\n" item? item?
+item    ::= "- " text "\n"
+text    ::= [^ \n\t-] [^\n]{9,}
+`
@@ -0,0 +1,316 @@
+package inference
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/clock"
+)
+
+// HTTPConfig configures an HTTPBackend
+type HTTPConfig struct {
+	URL          string        // API base URL up to and including /v1
+	Model        string        // Model name sent with every request
+	APIKey       string        // Bearer key (empty = no Authorization header)
+	CAFile       string        // PEM CA bundle (system roots if empty)
+	Timeout      time.Duration // Per request
+	MaxTokens    int           // Maximum tokens to generate
+	Temperature  float64       // Sampling temperature
+	HardwareUUID string        // For deterministic seed generation
+	Timestamp    time.Time     // For deterministic seed generation
+	Clock        clock.Clock   // Times generation (nil = clock.System)
+}
+
+// HTTPBackend generates through an OpenAI-compatible chat completions API
+// (Ollama, llama-server, vLLM), for machines without the bundled model
+// The seed is sent with each request; servers that honor it (llama-server,
+// vLLM, Ollama) reproduce the same summary for the same facts. Safe for
+// concurrent use; parallelism is up to the server.
+type HTTPBackend struct {
+	cfg    HTTPConfig
+	client *http.Client
+	clock  clock.Clock
+
+	mu     sync.Mutex
+	seed   int64
+	loaded bool
+}
+
+// chatRequest is the POST /chat/completions body
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	MaxTokens   int           `json:"max_tokens"`
+	Temperature float64       `json:"temperature"`
+	Seed        int64         `json:"seed"`
+	Stream      bool          `json:"stream"`
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// chatResponse is the part of the chat completions response we read
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// chatChunk is one server-sent event of a streamed chat completion
+type chatChunk struct {
+	Choices []struct {
+		Delta chatMessage `json:"delta"`
+	} `json:"choices"`
+}
+
+// NewHTTPBackend creates a backend for the server at cfg.URL
+// Complexity: O(1) (plus CA bundle parsing)
+func NewHTTPBackend(cfg *HTTPConfig) (*HTTPBackend, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+	if cfg.URL == "" || cfg.Model == "" {
+		return nil, fmt.Errorf("server URL and model are required")
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.CAFile != "" {
+		pemData, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pemData) {
+			return nil, fmt.Errorf("no certificates found in CA file")
+		}
+		transport.TLSClientConfig.RootCAs = pool
+	}
+	clk := cfg.Clock
+	if clk == nil {
+		clk = clock.System
+	}
+
+	c := *cfg
+	c.URL = strings.TrimRight(c.URL, "/")
+	return &HTTPBackend{
+		cfg:    c,
+		client: &http.Client{Transport: transport, Timeout: cfg.Timeout},
+		clock:  clk,
+		seed:   generateDeterministicSeed(cfg.HardwareUUID, cfg.Timestamp),
+	}, nil
+}
+
+// Load checks that the server answers GET /models
+// Complexity: O(1) round trip, once
+func (b *HTTPBackend) Load(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.loaded {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.cfg.URL+"/models", nil)
+	if err != nil {
+		return err
+	}
+	if _, err := b.do(req); err != nil {
+		return fmt.Errorf("inference server %s: %w", b.cfg.URL, err)
+	}
+	b.loaded = true
+	return nil
+}
+
+// Generate produces text from the given prompt using the backend seed
+// Complexity: O(1) round trip
+func (b *HTTPBackend) Generate(ctx context.Context, prompt string) (*InferenceResult, error) {
+	b.mu.Lock()
+	seed := b.seed
+	b.mu.Unlock()
+	return b.GenerateSeeded(ctx, prompt, seed)
+}
+
+// GenerateStream is Generate passing the reply to fn as the server streams it
+// Complexity: O(1) round trip
+func (b *HTTPBackend) GenerateStream(ctx context.Context, prompt string, fn TokenFunc) (*InferenceResult, error) {
+	b.mu.Lock()
+	seed := b.seed
+	b.mu.Unlock()
+	return b.GenerateSeededStream(ctx, prompt, seed, fn)
+}
+
+// GenerateSeeded sends prompt as one user message and returns the reply
+// Complexity: O(1) round trip
+func (b *HTTPBackend) GenerateSeeded(ctx context.Context, prompt string, seed int64) (*InferenceResult, error) {
+	return b.GenerateSeededStream(ctx, prompt, seed, nil)
+}
+
+// GenerateSeededStream is GenerateSeeded passing the reply to fn as the
+// server streams it (nil fn = one non-streamed response)
+// Stopping early closes the connection; the server stops generating.
+// Complexity: O(1) round trip
+func (b *HTTPBackend) GenerateSeededStream(ctx context.Context, prompt string, seed int64, fn TokenFunc) (*InferenceResult, error) {
+	if !b.IsLoaded() {
+		return nil, fmt.Errorf("engine not loaded, call Load() first")
+	}
+	body, err := json.Marshal(chatRequest{
+		Model:       b.cfg.Model,
+		Messages:    []chatMessage{{Role: "user", Content: prompt}},
+		MaxTokens:   b.cfg.MaxTokens,
+		Temperature: b.cfg.Temperature,
+		Seed:        seed,
+		Stream:      fn != nil,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.cfg.URL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	timer := clock.Start(b.clock)
+	if fn != nil {
+		text, tokens, err := b.stream(req, fn)
+		if err != nil {
+			return nil, fmt.Errorf("chat completion failed: %w", err)
+		}
+		return &InferenceResult{Text: text, TokenCount: tokens, InferenceTime: timer.Elapsed(), Seed: seed}, nil
+	}
+	data, err := b.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("chat completion failed: %w", err)
+	}
+	var resp chatResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("invalid chat completion response: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("chat completion returned no choices")
+	}
+	return &InferenceResult{
+		Text:          resp.Choices[0].Message.Content,
+		TokenCount:    resp.Usage.CompletionTokens,
+		InferenceTime: timer.Elapsed(),
+		Seed:          seed,
+	}, nil
+}
+
+// stream reads a streamed chat completion's server-sent events, passing
+// each content delta to fn, until [DONE] or fn asks to stop
+// Tokens are counted one per delta, which is how servers stream them.
+func (b *HTTPBackend) stream(req *http.Request, fn TokenFunc) (string, int, error) {
+	resp, err := b.send(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+
+	var out strings.Builder
+	tokens := 0
+	scanner := bufio.NewScanner(io.LimitReader(resp.Body, 4<<20))
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue // Blank separators, comments and other SSE fields
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			return out.String(), tokens, nil
+		}
+		var chunk chatChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return "", tokens, fmt.Errorf("invalid stream event: %w", err)
+		}
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			continue
+		}
+		text := chunk.Choices[0].Delta.Content
+		out.WriteString(text)
+		tokens++
+		stop, err := callToken(fn, text)
+		if err != nil {
+			return "", tokens, err
+		}
+		if stop {
+			return out.String(), tokens, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", tokens, err
+	}
+	return "", tokens, fmt.Errorf("stream ended without [DONE]")
+}
+
+// do sends req and returns the body of a 2xx response
+func (b *HTTPBackend) do(req *http.Request) ([]byte, error) {
+	resp, err := b.send(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+}
+
+// send sends req with the API key, returning a 2xx response for the caller
+// to close
+func (b *HTTPBackend) send(req *http.Request) (*http.Response, error) {
+	if b.cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+b.cfg.APIKey)
+	}
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+		msg := strings.TrimSpace(string(data))
+		if len(msg) > 200 {
+			msg = msg[:200]
+		}
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, msg)
+	}
+	return resp, nil
+}
+
+// Unload marks the backend unloaded (the server keeps its model)
+// Complexity: O(1)
+func (b *HTTPBackend) Unload() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.loaded = false
+	return nil
+}
+
+// SetSeed overrides the seed used by Generate
+// Complexity: O(1)
+func (b *HTTPBackend) SetSeed(seed int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.seed = seed
+}
+
+// IsLoaded returns whether Load has reached the server
+func (b *HTTPBackend) IsLoaded() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.loaded
+}
@@ -0,0 +1,139 @@
+package inference
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestHTTPBackend_ChatCompletion verifies the request shape and reply parsing
+func TestHTTPBackend_ChatCompletion(t *testing.T) {
+	var got chatRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/models":
+			w.Write([]byte(`{"data":[]}`))
+		case "/v1/chat/completions":
+			if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"SUMMARY:\n- ok"}}],"usage":{"completion_tokens":5}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	backend, err := NewHTTPBackend(&HTTPConfig{URL: srv.URL + "/v1/", Model: "qwen", APIKey: "secret", MaxTokens: 160, Temperature: 0.1})
+	if err != nil {
+		t.Fatalf("NewHTTPBackend() failed: %v", err)
+	}
+	ctx := context.Background()
+	if _, err := backend.Generate(ctx, "prompt"); err == nil {
+		t.Error("Generate() should fail before Load()")
+	}
+	if err := backend.Load(ctx); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	result, err := backend.GenerateSeeded(ctx, "prompt", 7)
+	if err != nil {
+		t.Fatalf("GenerateSeeded() failed: %v", err)
+	}
+	if result.Text != "SUMMARY:\n- ok" || result.TokenCount != 5 || result.Seed != 7 {
+		t.Errorf("result = %+v", result)
+	}
+	if got.Model != "qwen" || got.Seed != 7 || got.MaxTokens != 160 || got.Stream ||
+		len(got.Messages) != 1 || got.Messages[0].Role != "user" || got.Messages[0].Content != "prompt" {
+		t.Errorf("request = %+v", got)
+	}
+
+	backend.SetSeed(3)
+	if result, err := backend.Generate(ctx, "prompt"); err != nil || result.Seed != 3 || got.Seed != 3 {
+		t.Errorf("Generate() = %+v, %v; sent seed %d", result, err, got.Seed)
+	}
+}
+
+// TestHTTPBackend_Errors verifies failed requests surface the server's status
+func TestHTTPBackend_Errors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/models" {
+			return
+		}
+		http.Error(w, "model not found", http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	if _, err := NewHTTPBackend(&HTTPConfig{URL: srv.URL}); err == nil {
+		t.Error("NewHTTPBackend() should require a model")
+	}
+	backend, err := NewHTTPBackend(&HTTPConfig{URL: srv.URL, Model: "missing"})
+	if err != nil {
+		t.Fatalf("NewHTTPBackend() failed: %v", err)
+	}
+	if err := backend.Load(context.Background()); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	_, err = backend.Generate(context.Background(), "prompt")
+	if err == nil || !strings.Contains(err.Error(), "404") || !strings.Contains(err.Error(), "model not found") {
+		t.Errorf("Generate() error = %v", err)
+	}
+}
+
+// TestHTTPBackend_Stream verifies streamed deltas reach the callback and an
+// early stop keeps the text so far
+func TestHTTPBackend_Stream(t *testing.T) {
+	var got chatRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/models" {
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, piece := range []string{"SUMMARY:", "\n- ", "ok"} {
+			data, _ := json.Marshal(map[string]any{"choices": []any{map[string]any{"delta": map[string]string{"content": piece}}}})
+			w.Write([]byte("data: " + string(data) + "\n\n"))
+		}
+		w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	defer srv.Close()
+
+	backend, err := NewHTTPBackend(&HTTPConfig{URL: srv.URL, Model: "qwen"})
+	if err != nil {
+		t.Fatalf("NewHTTPBackend() failed: %v", err)
+	}
+	ctx := context.Background()
+	if err := backend.Load(ctx); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	var pieces []string
+	result, err := backend.GenerateStream(ctx, "prompt", func(token string) error {
+		pieces = append(pieces, token)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("GenerateStream() failed: %v", err)
+	}
+	if !got.Stream || result.Text != "SUMMARY:\n- ok" || result.TokenCount != 3 || strings.Join(pieces, "") != result.Text {
+		t.Errorf("result = %+v, pieces = %q, stream = %v", result, pieces, got.Stream)
+	}
+
+	result, err = backend.GenerateSeededStream(ctx, "prompt", 1, func(token string) error {
+		if strings.Contains(token, "\n") {
+			return ErrStopGeneration
+		}
+		return nil
+	})
+	if err != nil || result.Text != "SUMMARY:\n- " {
+		t.Errorf("stopped GenerateSeededStream() = %+v, %v", result, err)
+	}
+}
@@ -1,6 +1,9 @@
 package inference
 
 import (
+	"context"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -36,6 +39,96 @@ func TestNewEngine(t *testing.T) {
 	}
 }
 
+// TestEngine_GenerateSeeded verifies per-call seeds leave the engine seed alone
+func TestEngine_GenerateSeeded(t *testing.T) {
+	if Native {
+		t.Skip("needs a GGUF model")
+	}
+	engine, err := NewEngine(&InferenceConfig{MaxTokens: 160, HardwareUUID: "test-uuid-123", Sessions: 2})
+	if err != nil {
+		t.Fatalf("NewEngine() failed: %v", err)
+	}
+	ctx := context.Background()
+	if _, err := engine.GenerateSeeded(ctx, "prompt", 7); err == nil {
+		t.Error("GenerateSeeded() should fail before Load()")
+	}
+	if err := engine.Load(ctx); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	defer engine.Unload()
+
+	engine.SetSeed(1)
+	seeded, err := engine.GenerateSeeded(ctx, "prompt", 7)
+	if err != nil {
+		t.Fatalf("GenerateSeeded() failed: %v", err)
+	}
+	plain, err := engine.Generate(ctx, "prompt")
+	if err != nil {
+		t.Fatalf("Generate() failed: %v", err)
+	}
+	if seeded.Seed != 7 || plain.Seed != 1 {
+		t.Errorf("Seeds = %d, %d; want 7, 1", seeded.Seed, plain.Seed)
+	}
+}
+
+// TestFakeEngine_GenerateStream verifies the response arrives a word at a
+// time and an early stop keeps the text so far
+func TestFakeEngine_GenerateStream(t *testing.T) {
+	engine := NewFakeEngine()
+	engine.Response = "SUMMARY:\n- one two\n"
+	ctx := context.Background()
+	if err := engine.Load(ctx); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	var pieces []string
+	result, err := engine.GenerateStream(ctx, "prompt", func(token string) error {
+		pieces = append(pieces, token)
+		return nil
+	})
+	if err != nil || result.Text != engine.Response {
+		t.Fatalf("GenerateStream() = %+v, %v", result, err)
+	}
+	if want := []string{"SUMMARY:\n", "- ", "one ", "two\n"}; !reflect.DeepEqual(pieces, want) {
+		t.Errorf("pieces = %q, want %q", pieces, want)
+	}
+
+	result, err = engine.GenerateStream(ctx, "prompt", func(token string) error {
+		if token == "one " {
+			return ErrStopGeneration
+		}
+		return nil
+	})
+	if err != nil || result.Text != "SUMMARY:\n- one " {
+		t.Errorf("stopped GenerateStream() = %+v, %v", result, err)
+	}
+	if _, err := engine.GenerateStream(ctx, "prompt", func(string) error { return context.Canceled }); err != context.Canceled {
+		t.Errorf("failing callback: err = %v, want context.Canceled", err)
+	}
+}
+
+// TestTokenStream_SplitCharacter verifies a character split across tokens
+// reaches the callback whole
+func TestTokenStream_SplitCharacter(t *testing.T) {
+	var got []string
+	stream := tokenStream{fn: func(token string) error {
+		got = append(got, token)
+		return nil
+	}}
+	euro := []byte("€") // 3 bytes
+	for _, piece := range [][]byte{[]byte("a"), euro[:1], euro[1:2], append(euro[2:3], 'b'), euro[:2]} {
+		if stop, err := stream.write(piece); stop || err != nil {
+			t.Fatalf("write(%q) = %v, %v", piece, stop, err)
+		}
+	}
+	if err := stream.flush(); err != nil {
+		t.Fatalf("flush() failed: %v", err)
+	}
+	if want := []string{"a", "€b", string(euro[:2])}; !reflect.DeepEqual(got, want) {
+		t.Errorf("tokens = %q, want %q", got, want)
+	}
+}
+
 // TestGenerateDeterministicSeed verifies seed generation
 func TestGenerateDeterministicSeed(t *testing.T) {
 	uuid1 := "uuid-123"
@@ -175,6 +268,108 @@ ACTIONS:
 	}
 }
 
+// TestParserVariants verifies real-world deviations from the exact format
+func TestParserVariants(t *testing.T) {
+	tests := map[string]struct {
+		output  string
+		summary []string
+		risks   []string
+		actions []string
+	}{
+		"dash separator and singular headers": {
+			output:  "Summary -\n- Linux host with 3 users\nRISK:\n- Outdated kernel\nAction -\n- Update the kernel",
+			summary: []string{"Linux host with 3 users"},
+			risks:   []string{"Outdated kernel"},
+			actions: []string{"Update the kernel"},
+		},
+		"markdown headings, bold and numbered lists": {
+			output:  "## System Summary\n1. Linux host\n2) Two interfaces\n### **Key Risks**\n* **Outdated kernel**: 5.4\n## Recommendations\n(1) Patch",
+			summary: []string{"Linux host", "Two interfaces"},
+			risks:   []string{"Outdated kernel: 5.4"},
+			actions: []string{"Patch"},
+		},
+		"content on the header line": {
+			output:  "SUMMARY: Linux host is healthy\nRISK: Outdated kernel\nRISK: Guest account enabled\nACTIONS: Patch",
+			summary: []string{"Linux host is healthy"},
+			risks:   []string{"Outdated kernel", "Guest account enabled"},
+			actions: []string{"Patch"},
+		},
+		"nested bullets and wrapped lines": {
+			output:  "SUMMARY:\n- Linux host\n  - kernel 5.4\n  - Ubuntu 20.04\n- Users are\n  alice and bob\nRISKS:\n- None",
+			summary: []string{"Linux host; kernel 5.4; Ubuntu 20.04", "Users are alice and bob"},
+			risks:   []string{"None"},
+			actions: []string{},
+		},
+		"interleaved prose": {
+			output:  "Sure! Here is the analysis.\n\nSUMMARY:\nBased on the facts:\n- Linux host\nAlso worth noting.\n- Two users\n\nRISKS:\nI found these risks:\n- Outdated kernel",
+			summary: []string{"Linux host", "Two users"},
+			risks:   []string{"Outdated kernel"},
+			actions: []string{},
+		},
+		"numbered item named like a header": {
+			output:  "RISKS:\n1. Risk: outdated kernel\n2. Issues: guest account\nSUMMARY:\n- Linux host",
+			summary: []string{"Linux host"},
+			risks:   []string{"Risk: outdated kernel", "Issues: guest account"},
+			actions: []string{},
+		},
+		"bullets without a summary header": {
+			output:  "- Linux host\n- Two users\nRISKS:\n- Outdated kernel",
+			summary: []string{"Linux host", "Two users"},
+			risks:   []string{"Outdated kernel"},
+			actions: []string{},
+		},
+		"prose section and caps": {
+			output:  "Overview\nThe host runs Linux.\nIt has two users.\nNext steps:\n- a\n- b\n- c",
+			summary: []string{"The host runs Linux.", "It has two users."},
+			risks:   []string{},
+			actions: []string{"a", "b"},
+		},
+	}
+
+	parser := NewParser()
+	for name, tt := range tests {
+		parsed, err := parser.Parse(tt.output)
+		if err != nil {
+			t.Errorf("%s: Parse() failed: %v", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(parsed.Summary, tt.summary) || !reflect.DeepEqual(parsed.Risks, tt.risks) || !reflect.DeepEqual(parsed.Actions, tt.actions) {
+			t.Errorf("%s: Parse() = %q / %q / %q, want %q / %q / %q", name,
+				parsed.Summary, parsed.Risks, parsed.Actions, tt.summary, tt.risks, tt.actions)
+		}
+	}
+}
+
+// FuzzParse verifies Parse never panics and keeps its output invariants
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{
+		"SUMMARY:\n- a\nRISKS:\n- b\nACTIONS:\n- c",
+		"## Summary\n1. a\n   - nested\n**Risks:** b",
+		"Summary -\n\t• a\n\twrapped",
+		"RISK:\n(1) x\na) y\n---\n**",
+		"- \n•\n1.\n#\n:",
+	} {
+		f.Add(seed)
+	}
+	parser := NewParser()
+	f.Fuzz(func(t *testing.T, output string) {
+		parsed, err := parser.Parse(output)
+		if err != nil {
+			return
+		}
+		if len(parsed.Summary) == 0 || len(parsed.Summary) > maxSummary || len(parsed.Risks) > maxRisks || len(parsed.Actions) > maxActions {
+			t.Fatalf("Parse(%q) broke the section caps: %+v", output, parsed)
+		}
+		for _, items := range [][]string{parsed.Summary, parsed.Risks, parsed.Actions} {
+			for _, item := range items {
+				if item == "" || item != strings.TrimSpace(item) || strings.Contains(item, "\n") {
+					t.Fatalf("Parse(%q) produced item %q", output, item)
+				}
+			}
+		}
+	})
+}
+
 // TestValidate verifies output validation
 func TestValidate(t *testing.T) {
 	parser := NewParser()
@@ -263,6 +458,44 @@ func TestCleanOutput(t *testing.T) {
 	}
 }
 
+// TestAnnotateConfidence verifies evidence-based grounding of model risks
+func TestAnnotateConfidence(t *testing.T) {
+	parser := NewParser()
+	parsed := &ParsedOutput{
+		Summary: []string{"Summary line long enough"},
+		Risks: []string{
+			"Outdated OS release (Evidence: 18.04)",
+			"Suspicious account \"backdoor\" present",
+			"Possible malware activity",
+		},
+		Findings: []Finding{
+			{ID: "MB-OS-EOL", Description: "Outdated OS release (Evidence: 18.04)", Confidence: ConfidenceGrounded},
+		},
+	}
+	factsJSON := `{"os_version":"18.04","users":[{"username":"alice"}]}`
+
+	parser.AnnotateConfidence(parsed, factsJSON)
+
+	if len(parsed.Findings) != 3 {
+		t.Fatalf("Findings length = %d, want 3", len(parsed.Findings))
+	}
+	if f, _ := parsed.FindingFor(parsed.Risks[0]); f.ID != "MB-OS-EOL" {
+		t.Error("Existing rule finding was replaced")
+	}
+	if f, _ := parsed.FindingFor(parsed.Risks[1]); f.Confidence != ConfidenceInferred {
+		t.Errorf("Unverifiable quoted value got %q, want inferred", f.Confidence)
+	}
+	if f, _ := parsed.FindingFor(parsed.Risks[2]); f.Confidence != ConfidenceInferred {
+		t.Errorf("Risk without evidence got %q, want inferred", f.Confidence)
+	}
+
+	grounded := &ParsedOutput{Risks: []string{"Many local users (Evidence: alice)"}}
+	parser.AnnotateConfidence(grounded, factsJSON)
+	if grounded.Findings[0].Confidence != ConfidenceGrounded {
+		t.Errorf("Evidence present in facts got %q, want grounded", grounded.Findings[0].Confidence)
+	}
+}
+
 // Helper function
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) &&
@@ -280,3 +513,30 @@ func containsMiddle(s, substr string) bool {
 	}
 	return false
 }
+
+// TestParser_GrammarOutput verifies every shape ReportGrammar allows parses and validates
+func TestParser_GrammarOutput(t *testing.T) {
+	tests := []struct {
+		output                 string
+		summary, risks, action int
+	}{
+		{DefaultFakeResponse + "\n", 3, 1, 1},
+		{"SUMMARY:\n- Ubuntu 22.04 host kiosk-01\n\nRISKS:\n\nACTIONS:\n", 1, 0, 0},
+		{"SUMMARY:\n- Ubuntu 22.04 host kiosk-01\n- Three local accounts\n\nRISKS:\n- Kernel 5.15 is out of support\n- Guest account enabled\n- Disk not encrypted\n\nACTIONS:\n- Upgrade to a supported kernel\n- Enable LUKS on the root volume\n", 2, 3, 2},
+	}
+	p := NewParser()
+	for i, tt := range tests {
+		parsed, err := p.Parse(p.CleanOutput(tt.output))
+		if err != nil {
+			t.Errorf("case %d: Parse() failed: %v", i, err)
+			continue
+		}
+		if err := p.Validate(parsed); err != nil {
+			t.Errorf("case %d: Validate() failed: %v", i, err)
+		}
+		if len(parsed.Summary) != tt.summary || len(parsed.Risks) != tt.risks || len(parsed.Actions) != tt.action {
+			t.Errorf("case %d: parsed %d/%d/%d items, want %d/%d/%d", i,
+				len(parsed.Summary), len(parsed.Risks), len(parsed.Actions), tt.summary, tt.risks, tt.action)
+		}
+	}
+}
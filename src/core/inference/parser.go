@@ -2,6 +2,7 @@ package inference
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 )
 
@@ -13,84 +14,206 @@ func NewParser() *Parser {
 	return &Parser{}
 }
 
+// Section caps: the prompt asks for at most this many bullets per section
+const (
+	maxSummary = 3
+	maxRisks   = 3
+	maxActions = 2
+)
+
+// section identifies one part of the model output
+type section int
+
+const (
+	sectionNone section = iota
+	sectionSummary
+	sectionRisks
+	sectionActions
+)
+
+// sectionAliases maps lower-cased header names to their section
+// Small models rename, singularize and decorate headers freely.
+var sectionAliases = map[string]section{
+	"summary":             sectionSummary,
+	"system summary":      sectionSummary,
+	"executive summary":   sectionSummary,
+	"overview":            sectionSummary,
+	"risk":                sectionRisks,
+	"risks":               sectionRisks,
+	"key risks":           sectionRisks,
+	"security risks":      sectionRisks,
+	"concerns":            sectionRisks,
+	"issues":              sectionRisks,
+	"action":              sectionActions,
+	"actions":             sectionActions,
+	"recommended actions": sectionActions,
+	"recommendations":     sectionActions,
+	"next steps":          sectionActions,
+	"remediation":         sectionActions,
+}
+
+var (
+	// headerPattern matches "SUMMARY:", "Summary -", "## Risks", "**Actions:**",
+	// "1. Risks" and a bare "Overview"; group 1 is any numbering, group 2 the
+	// name and group 3 any content on the same line
+	headerPattern = regexp.MustCompile(`^(?:#{1,6}\s*)?(\d+[.)]\s*)?[*_]{0,2}\s*([A-Za-z][A-Za-z ]*?)\s*[*_]{0,2}\s*(?:[:\-–—][*_]{0,2}\s*(.*))?$`)
+
+	// bulletPattern matches "- x", "* x", "• x", "1. x", "2) x", "(3) x" and
+	// "a) x"; group 1 is the content
+	bulletPattern = regexp.MustCompile(`^(?:[-*+]\s+|[•▪◦‣]\s*|\d{1,2}[.)]\s+|\(\d{1,2}\)\s+|[a-z]\)\s+)(.*)$`)
+)
+
+// line is one non-empty output line within a section
+type line struct {
+	indent int    // Leading whitespace width (tab = 4); -1 for content on a header line
+	text   string // Content with any bullet marker removed
+	bullet bool
+	follow bool // Directly follows the previous line (no blank line between)
+}
+
 // Parse extracts SUMMARY, RISKS, and ACTIONS from LLM output
+// Headers may be aliased ("Overview", "Recommendations"), singular, numbered
+// or decorated as markdown headings or bold text, with content on the same
+// line. Nested bullets are folded into their parent item and wrapped lines
+// into the item they continue. In a section that has bullets, other prose
+// ("Here are the risks I found:") is dropped; a section without bullets
+// uses each line as an item. Bullets before the first header stand in for
+// a missing summary section.
 // Mathematical property: Same output text → Same parsed structure
 // Complexity: O(n) where n = length of output text
 func (p *Parser) Parse(output string) (*ParsedOutput, error) {
-	if output == "" {
+	if strings.TrimSpace(output) == "" {
 		return nil, fmt.Errorf("output is empty")
 	}
 
+	lines := map[section][]line{}
+	current := sectionNone
+	follow := false
+	for _, raw := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(raw)
+		if trimmed == "" {
+			follow = false
+			continue
+		}
+		if sec, rest, ok := parseHeader(trimmed); ok {
+			current = sec
+			follow = false
+			if rest != "" {
+				// "RISK: outdated kernel" is an item of its section
+				lines[current] = append(lines[current], line{indent: -1, text: rest, bullet: true})
+			}
+			continue
+		}
+		l := line{indent: indentWidth(raw), text: trimmed, follow: follow}
+		if m := bulletPattern.FindStringSubmatch(trimmed); m != nil {
+			l.bullet, l.text = true, strings.TrimSpace(m[1])
+		}
+		lines[current] = append(lines[current], l)
+		follow = true
+	}
+
 	result := &ParsedOutput{
-		Summary: []string{},
-		Risks:   []string{},
-		Actions: []string{},
+		Summary: collectItems(lines[sectionSummary], maxSummary),
+		Risks:   collectItems(lines[sectionRisks], maxRisks),
+		Actions: collectItems(lines[sectionActions], maxActions),
+	}
+	if len(result.Summary) == 0 && hasBullets(lines[sectionNone]) {
+		result.Summary = collectItems(lines[sectionNone], maxSummary)
 	}
 
-	// Split into lines and process
-	lines := strings.Split(output, "\n")
+	// Validate that we have at least a summary
+	if len(result.Summary) == 0 {
+		return nil, fmt.Errorf("no summary section found in output")
+	}
 
-	var currentSection string
+	return result, nil
+}
 
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
+// parseHeader reports whether s is a section header, returning its section
+// and any content that follows it on the same line
+func parseHeader(s string) (section, string, bool) {
+	m := headerPattern.FindStringSubmatch(s)
+	if m == nil {
+		return sectionNone, "", false
+	}
+	name := strings.ToLower(strings.Join(strings.Fields(m[2]), " "))
+	sec, ok := sectionAliases[name]
+	// "1. Risk: outdated kernel" is a numbered item, not a header
+	if !ok || (m[1] != "" && m[3] != "") {
+		return sectionNone, "", false
+	}
+	return sec, cleanItem(m[3]), true
+}
 
-		// Skip empty lines
-		if line == "" {
+// collectItems turns one section's lines into at most limit items
+// Complexity: O(|lines|)
+func collectItems(lines []line, limit int) []string {
+	bullets := hasBullets(lines)
+	items := []string{}
+	top := -1 // Indentation of the section's top-level bullets
+	last := -1
+	lastIndent := 0
+	for _, l := range lines {
+		text := cleanItem(l.text)
+		if text == "" {
 			continue
 		}
-
-		// Detect section headers (case-insensitive)
-		lineUpper := strings.ToUpper(line)
-		if strings.HasPrefix(lineUpper, "SUMMARY:") {
-			currentSection = "SUMMARY"
+		switch {
+		case !bullets, l.indent < 0:
+			// Without bullets every line is an item, as is header content
+		case l.bullet && top < 0:
+			top = l.indent
+		case l.bullet && l.indent > top && last >= 0:
+			// Nested bullet: fold into its parent
+			items[last] += "; " + text
 			continue
-		} else if strings.HasPrefix(lineUpper, "RISKS:") {
-			currentSection = "RISKS"
+		case !l.bullet && l.follow && l.indent > lastIndent && last >= 0:
+			// Wrapped continuation of the previous item
+			items[last] += " " + text
 			continue
-		} else if strings.HasPrefix(lineUpper, "ACTIONS:") {
-			currentSection = "ACTIONS"
+		case !l.bullet:
+			// Interleaved prose around the bullets
 			continue
 		}
-
-		// Extract bullet points or lines
-		content := strings.TrimSpace(line)
-
-		// Remove bullet markers (•, -, *, etc.)
-		content = strings.TrimPrefix(content, "•")
-		content = strings.TrimPrefix(content, "-")
-		content = strings.TrimPrefix(content, "*")
-		content = strings.TrimPrefix(content, "▪")
-		content = strings.TrimSpace(content)
-
-		// Skip if empty after trimming
-		if content == "" {
+		if len(items) == limit {
+			last = -1 // Later continuations belong to dropped items
 			continue
 		}
+		items = append(items, text)
+		last, lastIndent = len(items)-1, l.indent
+	}
+	return items
+}
 
-		// Add to appropriate section
-		switch currentSection {
-		case "SUMMARY":
-			if len(result.Summary) < 3 {
-				result.Summary = append(result.Summary, content)
-			}
-		case "RISKS":
-			if len(result.Risks) < 3 {
-				result.Risks = append(result.Risks, content)
-			}
-		case "ACTIONS":
-			if len(result.Actions) < 2 {
-				result.Actions = append(result.Actions, content)
-			}
+// hasBullets reports whether any line is a bullet
+func hasBullets(lines []line) bool {
+	for _, l := range lines {
+		if l.bullet {
+			return true
 		}
 	}
+	return false
+}
 
-	// Validate that we have at least a summary
-	if len(result.Summary) == 0 {
-		return nil, fmt.Errorf("no summary section found in output")
-	}
+// cleanItem removes markdown bold markers and surrounding whitespace
+func cleanItem(s string) string {
+	return strings.TrimSpace(strings.ReplaceAll(s, "**", ""))
+}
 
-	return result, nil
+// indentWidth returns the width of s's leading whitespace (tab = 4)
+func indentWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		switch r {
+		case ' ':
+			width++
+		case '\t':
+			width += 4
+		default:
+			return width
+		}
+	}
+	return width
 }
 
 // Validate checks if parsed output meets quality standards
@@ -164,6 +287,71 @@ func (p *Parser) DetectHallucination(parsed *ParsedOutput, factsJSON string) []s
 	return hallucinations
 }
 
+// AnnotateConfidence attaches a confidence level to every risk
+// Risks already backed by a finding (e.g., from the rule engine) keep their
+// annotation. Model risks are grounded only when their cited evidence
+// ("(Evidence: ...)" clause or quoted values) appears in the facts JSON.
+// Note: This is best-effort, not mathematically guaranteed
+// Complexity: O(|risks| * |factsJSON|)
+func (p *Parser) AnnotateConfidence(parsed *ParsedOutput, factsJSON string) {
+	factsLower := strings.ToLower(factsJSON)
+
+	for _, risk := range parsed.Risks {
+		if _, ok := parsed.FindingFor(risk); ok {
+			continue
+		}
+
+		confidence := ConfidenceInferred
+		if evidence := extractEvidence(risk); len(evidence) > 0 && allPresent(evidence, factsLower) {
+			confidence = ConfidenceGrounded
+		}
+
+		parsed.Findings = append(parsed.Findings, Finding{
+			Description: risk,
+			Confidence:  confidence,
+		})
+	}
+}
+
+// extractEvidence returns the fact values cited by a risk bullet
+// Sources: "(Evidence: a, b)" clause, otherwise double-quoted substrings
+func extractEvidence(risk string) []string {
+	evidence := []string{}
+
+	lower := strings.ToLower(risk)
+	if start := strings.Index(lower, "(evidence:"); start >= 0 {
+		clause := risk[start+len("(evidence:"):]
+		if end := strings.Index(clause, ")"); end >= 0 {
+			clause = clause[:end]
+		}
+		for _, part := range strings.Split(clause, ",") {
+			part = strings.Trim(strings.TrimSpace(part), `"'`)
+			if part != "" {
+				evidence = append(evidence, part)
+			}
+		}
+		return evidence
+	}
+
+	parts := strings.Split(risk, `"`)
+	for i := 1; i < len(parts); i += 2 {
+		if value := strings.TrimSpace(parts[i]); value != "" {
+			evidence = append(evidence, value)
+		}
+	}
+	return evidence
+}
+
+// allPresent reports whether every value occurs in the lower-cased haystack
+func allPresent(values []string, haystackLower string) bool {
+	for _, v := range values {
+		if !strings.Contains(haystackLower, strings.ToLower(v)) {
+			return false
+		}
+	}
+	return true
+}
+
 // Format converts ParsedOutput to human-readable report text
 // Complexity: O(n) where n = total length of all sections
 func (p *Parser) Format(parsed *ParsedOutput) string {
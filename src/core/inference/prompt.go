@@ -1,7 +1,6 @@
 package inference
 
 import (
-	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -29,7 +28,7 @@ func (pb *PromptBuilder) BuildPrompt(facts *collection.Facts) (string, error) {
 	}
 
 	// Convert Facts to JSON for structured input
-	factsJSON, err := json.MarshalIndent(facts, "", "  ")
+	factsJSON, err := facts.AppendJSON(nil, "  ")
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal facts: %w", err)
 	}
@@ -52,6 +51,40 @@ func (pb *PromptBuilder) BuildPrompt(facts *collection.Facts) (string, error) {
 	return prompt.String(), nil
 }
 
+// BuildQuestionPrompt creates a follow-up prompt answering question from Facts
+// Mathematical property: Same Facts + question → Same Prompt (deterministic)
+// Complexity: O(|Facts| + |question|)
+func (pb *PromptBuilder) BuildQuestionPrompt(facts *collection.Facts, question string) (string, error) {
+	if facts == nil {
+		return "", fmt.Errorf("facts cannot be nil")
+	}
+	question = strings.TrimSpace(question)
+	if question == "" {
+		return "", fmt.Errorf("question cannot be empty")
+	}
+
+	factsJSON, err := facts.AppendJSON(nil, "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal facts: %w", err)
+	}
+
+	var prompt strings.Builder
+	prompt.WriteString(pb.systemPrompt)
+	prompt.WriteString("\n\nSYSTEM FACTS:\n")
+	prompt.WriteString(string(factsJSON))
+	prompt.WriteString("\n\nQUESTION:\n")
+	prompt.WriteString(question)
+	prompt.WriteString("\n\n")
+	prompt.WriteString(questionInstructions)
+	return prompt.String(), nil
+}
+
+// questionInstructions constrains follow-up answers to the collected facts
+const questionInstructions = `Answer the QUESTION in at most 3 sentences using ONLY the SYSTEM FACTS.
+If the facts do not contain the answer, reply exactly: "Not in the collected facts."
+
+Answer:`
+
 // buildSystemPrompt creates the system-level instructions
 // These are fixed and deterministic
 func buildSystemPrompt() string {
@@ -68,7 +101,10 @@ CRITICAL RULES:
 ANALYSIS GUIDELINES:
 - Focus on hardware, network, and user configuration
 - Identify potential security concerns (multiple admin accounts, unusual network configs)
-- Note any deprecated OS versions or missing updates
+- Flag unexpected services listening on all interfaces (0.0.0.0 or ::), such as remote shells, databases or debug ports
+- Flag a host where no security product has real_time_protection "on" (no antivirus or EDR, or all of them off)
+- Note any deprecated OS versions
+- Only report missing or outdated updates from installed_updates, last_update and pending_updates; when those are absent, patch level is unknown
 - Highlight unusual user activity patterns
 - Keep technical language clear but not overly simplified`
 }
@@ -133,6 +169,18 @@ func (pb *PromptBuilder) TruncateFacts(facts *collection.Facts) *collection.Fact
 	if len(truncated.HomeDirs) > 10 {
 		truncated.HomeDirs = truncated.HomeDirs[:10]
 	}
+	if len(truncated.Software) > 10 {
+		truncated.Software = truncated.Software[:10]
+	}
+	if len(truncated.Processes) > 10 {
+		truncated.Processes = truncated.Processes[:10]
+	}
+	if len(truncated.ListeningPorts) > 20 {
+		truncated.ListeningPorts = truncated.ListeningPorts[:20]
+	}
+	if len(truncated.InstalledUpdates) > 10 {
+		truncated.InstalledUpdates = truncated.InstalledUpdates[:10] // Newest first
+	}
 
 	return &truncated
 }
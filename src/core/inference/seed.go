@@ -0,0 +1,34 @@
+package inference
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"time"
+)
+
+// DeterministicSeed returns the sampling seed for a hardware UUID and timestamp
+// Mathematical property: Same inputs → same seed
+// Complexity: O(|hardwareUUID|)
+func DeterministicSeed(hardwareUUID string, timestamp time.Time) int64 {
+	return generateDeterministicSeed(hardwareUUID, timestamp)
+}
+
+// generateDeterministicSeed creates a reproducible seed from hardware UUID and timestamp
+// Mathematical property: Same inputs → same seed
+func generateDeterministicSeed(hardwareUUID string, timestamp time.Time) int64 {
+	// Combine UUID and timestamp for seed
+	h := sha256.New()
+	h.Write([]byte(hardwareUUID))
+
+	// Use timestamp to nanosecond precision
+	tsBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(tsBytes, uint64(timestamp.UnixNano()))
+	h.Write(tsBytes)
+
+	hash := h.Sum(nil)
+
+	// Convert first 8 bytes to int64
+	seed := int64(binary.LittleEndian.Uint64(hash[:8]))
+
+	return seed
+}
@@ -0,0 +1,66 @@
+package inference
+
+import (
+	"errors"
+	"unicode/utf8"
+)
+
+// tokenStream passes sampled pieces to a TokenFunc a whole UTF-8 character
+// at a time: a character split across tokens is held back until complete
+type tokenStream struct {
+	fn      TokenFunc // nil = not streaming
+	pending []byte    // Leading bytes of a character split across tokens
+}
+
+// write passes piece on; stop reports that fn returned ErrStopGeneration
+// Complexity: O(|piece|)
+func (s *tokenStream) write(piece []byte) (stop bool, err error) {
+	if s.fn == nil {
+		return false, nil
+	}
+	s.pending = append(s.pending, piece...)
+	n := completeUTF8(s.pending)
+	if n == 0 {
+		return false, nil
+	}
+	text := string(s.pending[:n])
+	s.pending = s.pending[:copy(s.pending, s.pending[n:])]
+	return callToken(s.fn, text)
+}
+
+// flush passes on any bytes still held back when generation ends
+// Complexity: O(1)
+func (s *tokenStream) flush() error {
+	if s.fn == nil || len(s.pending) == 0 {
+		return nil
+	}
+	text := string(s.pending)
+	s.pending = s.pending[:0]
+	_, err := callToken(s.fn, text)
+	return err
+}
+
+// callToken calls fn, separating a requested stop from a failure
+func callToken(fn TokenFunc, text string) (stop bool, err error) {
+	if err := fn(text); errors.Is(err, ErrStopGeneration) {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// completeUTF8 returns the length of b without a trailing incomplete
+// character
+// Complexity: O(1) (looks at most utf8.UTFMax bytes back)
+func completeUTF8(b []byte) int {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			if !utf8.FullRune(b[i:]) {
+				return i
+			}
+			break
+		}
+	}
+	return len(b)
+}
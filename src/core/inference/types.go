@@ -1,14 +1,22 @@
 package inference
 
-import "time"
+import (
+	"errors"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/clock"
+)
 
 // InferenceConfig contains configuration for GGUF inference
 type InferenceConfig struct {
-	MaxTokens    int       // Maximum tokens to generate (160)
-	Temperature  float64   // Sampling temperature (0.1)
-	HardwareUUID string    // For deterministic seed generation
-	Timestamp    time.Time // For deterministic seed generation
-	ModelPath    string    // Path to GGUF model file
+	MaxTokens    int         // Maximum tokens to generate (160)
+	Temperature  float64     // Sampling temperature (0.1)
+	HardwareUUID string      // For deterministic seed generation
+	Timestamp    time.Time   // For deterministic seed generation
+	ModelPath    string      // Path to GGUF model file
+	Threads      int         // llama.cpp CPU threads (0 = 4)
+	Sessions     int         // Sampling contexts sharing the model (0 = 1)
+	Clock        clock.Clock // Times generation (nil = clock.System)
 }
 
 // InferenceResult contains the output from LLM inference
@@ -19,9 +27,59 @@ type InferenceResult struct {
 	Seed          int64         // Seed used for generation
 }
 
+// TokenFunc receives generated text as it is sampled, usually one token's
+// piece per call and always whole UTF-8 characters
+// Returning ErrStopGeneration ends generation early, keeping the text so far
+// (including the piece just passed); any other error aborts generation.
+type TokenFunc func(token string) error
+
+// ErrStopGeneration is returned by a TokenFunc to end generation early
+// without failing it (e.g., once an end marker has been generated)
+var ErrStopGeneration = errors.New("stop generation")
+
 // ParsedOutput contains structured LLM output
 type ParsedOutput struct {
-	Summary []string // 3-line summary (max)
-	Risks   []string // Risk bullets (0-3)
-	Actions []string // Action items (0-2)
+	Summary  []string  // 3-line summary (max)
+	Risks    []string  // Risk bullets (0-3)
+	Actions  []string  // Action items (0-2)
+	Findings []Finding // Risks with provenance (rule engine or annotated model output)
+}
+
+// Finding is a risk with provenance and confidence
+type Finding struct {
+	ID          string     // Stable rule ID (e.g., "MB-OS-EOL"), empty for model output
+	Description string     // Human-readable risk text
+	Confidence  Confidence // Whether the statement is verifiable against Facts
+}
+
+// Confidence classifies how a finding was established
+type Confidence string
+
+const (
+	// ConfidenceGrounded marks findings derived from or verified against collected Facts
+	ConfidenceGrounded Confidence = "grounded"
+
+	// ConfidenceInferred marks model statements that need manual confirmation
+	ConfidenceInferred Confidence = "inferred"
+)
+
+// FindingFor returns the finding whose description matches a risk bullet
+// Complexity: O(|Findings|)
+func (p *ParsedOutput) FindingFor(risk string) (Finding, bool) {
+	for _, f := range p.Findings {
+		if f.Description == risk {
+			return f, true
+		}
+	}
+	return Finding{}, false
+}
+
+// FindingIDs returns the IDs of all findings in order
+// Complexity: O(|Findings|)
+func (p *ParsedOutput) FindingIDs() []string {
+	ids := make([]string, 0, len(p.Findings))
+	for _, f := range p.Findings {
+		ids = append(ids, f.ID)
+	}
+	return ids
 }
@@ -1,10 +1,17 @@
 package io_test
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	stdio "io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/minibeast/usb-agent/src/core/crypto"
 	"github.com/minibeast/usb-agent/src/core/io"
 )
 
@@ -307,3 +314,168 @@ func TestWriteAtomic_Overwrite(t *testing.T) {
 		t.Errorf("Overwrite failed: got %q, want %q", content, newData)
 	}
 }
+
+// TestWriteStream verifies the digest, size and taps match the bytes on disk
+func TestWriteStream(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "out", "facts.json")
+	data := bytes.Repeat([]byte("minibeast "), 100000)
+
+	var tap bytes.Buffer
+	written, err := io.NewWriter().WriteStream(testFile, bytes.NewReader(data), 0644, &tap)
+	if err != nil {
+		t.Fatalf("WriteStream() failed: %v", err)
+	}
+
+	onDisk, err := os.ReadFile(testFile)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if written.SHA256 != sha256.Sum256(onDisk) || written.Size != int64(len(onDisk)) || written.Path != testFile {
+		t.Errorf("WriteStream() = %+v, want the digest and size of the file", written)
+	}
+	if !bytes.Equal(tap.Bytes(), data) {
+		t.Error("tap did not receive the written bytes")
+	}
+	if _, err := os.Stat(testFile + ".tmp"); !os.IsNotExist(err) {
+		t.Error("temp file left behind")
+	}
+}
+
+// TestWriteContext_Cancelled verifies a cancelled write leaves the old file and no temp
+func TestWriteContext_Cancelled(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "facts.json")
+	if err := os.WriteFile(testFile, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &cancelAfter{r: bytes.NewReader(bytes.Repeat([]byte("x"), 1<<20)), cancel: cancel}
+	if _, err := io.NewWriter().WriteContext(ctx, testFile, r, 0644); !errors.Is(err, context.Canceled) {
+		t.Fatalf("WriteContext() = %v, want context.Canceled", err)
+	}
+	if content, _ := os.ReadFile(testFile); string(content) != "old" {
+		t.Errorf("Previous file = %q, want it untouched", content)
+	}
+	if _, err := os.Stat(testFile + ".tmp"); !os.IsNotExist(err) {
+		t.Error("temp file left behind")
+	}
+}
+
+// cancelAfter cancels its context after the first read
+type cancelAfter struct {
+	r      stdio.Reader
+	cancel context.CancelFunc
+}
+
+func (c *cancelAfter) Read(p []byte) (int, error) {
+	defer c.cancel()
+	return c.r.Read(p)
+}
+
+// TestBatchWriter verifies files appear only at Flush, renamed and complete
+func TestBatchWriter(t *testing.T) {
+	dir := t.TempDir()
+	writer := io.NewBatchWriter()
+	paths := []string{filepath.Join(dir, "a.json"), filepath.Join(dir, "b.json"), filepath.Join(dir, "sub", "c.json")}
+	for _, path := range paths {
+		written, err := writer.WriteStream(path, bytes.NewReader([]byte(path)), 0644)
+		if err != nil {
+			t.Fatalf("WriteStream(%s) failed: %v", path, err)
+		}
+		if written.SHA256 != sha256.Sum256([]byte(path)) {
+			t.Errorf("%s: digest does not match the data", path)
+		}
+		if io.FileExists(path) {
+			t.Errorf("%s exists before Flush", path)
+		}
+	}
+	if _, err := writer.WriteStream(paths[0], bytes.NewReader(nil), 0644); err == nil {
+		t.Error("WriteStream() accepted a path already awaiting Flush")
+	}
+
+	if err := writer.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() failed: %v", err)
+	}
+	for _, path := range paths {
+		if content, err := os.ReadFile(path); err != nil || string(content) != path {
+			t.Errorf("%s = %q, %v after Flush", path, content, err)
+		}
+		if io.FileExists(path + ".tmp") {
+			t.Errorf("%s.tmp left behind", path)
+		}
+	}
+	if err := writer.Flush(context.Background()); err != nil {
+		t.Errorf("second Flush() failed: %v", err)
+	}
+}
+
+// TestBatchWriter_FlushCancelled verifies an expired flush discards rather than renames unsynced files
+func TestBatchWriter_FlushCancelled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "facts.json")
+	writer := io.NewBatchWriter()
+	if _, err := writer.WriteStream(path, bytes.NewReader([]byte("data")), 0644); err != nil {
+		t.Fatalf("WriteStream() failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := writer.Flush(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Flush() = %v, want context.Canceled", err)
+	}
+	if io.FileExists(path) || io.FileExists(path+".tmp") {
+		t.Error("cancelled Flush left files behind")
+	}
+}
+
+// TestManifest_WriteVerify verifies a signed manifest lists written files
+// and catches altered and missing ones
+func TestManifest_WriteVerify(t *testing.T) {
+	dir := t.TempDir()
+	m := io.NewManifestWriter(dir)
+	written, err := io.NewWriter().WriteStream(filepath.Join(dir, "host.json"), bytes.NewReader([]byte(`{"a":1}`)), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddWritten(written); err != nil {
+		t.Fatal(err)
+	}
+	logPath := filepath.Join(dir, "host.log")
+	if err := os.WriteFile(logPath, []byte("msg=done\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddFile(logPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddFile(filepath.Join(t.TempDir(), "elsewhere")); err == nil {
+		t.Error("AddFile accepted a missing file")
+	}
+
+	keyPair, err := crypto.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths, err := m.Write("host", "01JPAX1Z5C8K2M3N4P5Q6R7S8T", keyPair, crypto.SignatureFormatRaw)
+	if err != nil || len(paths) != 2 {
+		t.Fatalf("Write() = %v, %v", paths, err)
+	}
+
+	manifest, err := io.VerifyManifest(paths[0], keyPair.PublicKey)
+	if err != nil {
+		t.Fatalf("VerifyManifest() failed: %v", err)
+	}
+	if len(manifest.Files) != 2 || manifest.Files[0].Name != "host.json" || manifest.Files[0].SHA256 != written.Hex() || manifest.Ephemeral {
+		t.Errorf("manifest = %+v", manifest)
+	}
+
+	other, _ := crypto.GenerateKeyPair()
+	if _, err := io.VerifyManifest(paths[0], other.PublicKey); err == nil {
+		t.Error("VerifyManifest() accepted another key")
+	}
+
+	os.WriteFile(filepath.Join(dir, "host.json"), []byte(`{"a":2}`), 0644)
+	os.Remove(logPath)
+	_, err = io.VerifyManifest(paths[0], nil)
+	if err == nil || !strings.Contains(err.Error(), "host.json (modified)") || !strings.Contains(err.Error(), "host.log (missing)") {
+		t.Errorf("VerifyManifest() after tampering = %v", err)
+	}
+}
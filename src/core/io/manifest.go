@@ -0,0 +1,204 @@
+package io
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/minibeast/usb-agent/src/core/crypto"
+)
+
+// ManifestFormat identifies the run manifest layout
+const ManifestFormat = "minibeast-manifest/1"
+
+// Run manifest file name suffixes after the run's base name
+const (
+	ManifestSuffix          = ".manifest.json"
+	ManifestSignatureSuffix = ".manifest.json.sig"
+)
+
+// Manifest is "<run>.manifest.json": every file a run wrote with its size
+// and SHA-256, so one signature check covers them all
+type Manifest struct {
+	Format    string          `json:"format"`
+	RunID     string          `json:"run_id"`
+	Files     []ManifestEntry `json:"files"`      // In write order
+	PublicKey string          `json:"public_key"` // Base64 Ed25519 key the manifest is signed with
+	KeyID     string          `json:"key_id"`     // crypto.Fingerprint of PublicKey
+	Ephemeral bool            `json:"ephemeral"`  // Signed with a per-run key (no audit.signing_key)
+}
+
+// ManifestEntry describes one listed file
+type ManifestEntry struct {
+	Name   string `json:"name"` // Relative to the manifest's directory, slash-separated
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// ManifestWriter gathers the files of one run and writes their signed
+// manifest; safe for concurrent use
+type ManifestWriter struct {
+	dir string
+
+	mu      sync.Mutex
+	entries []ManifestEntry
+}
+
+// NewManifestWriter starts a manifest for files written under dir
+// Complexity: O(1)
+func NewManifestWriter(dir string) *ManifestWriter {
+	return &ManifestWriter{dir: dir}
+}
+
+// AddWritten lists a file with the size and SHA-256 computed while writing it
+// Unlike AddFile the file is not read back. A nil ManifestWriter lists nothing.
+// Complexity: O(1)
+func (m *ManifestWriter) AddWritten(w *Written) error {
+	if m == nil {
+		return nil
+	}
+	return m.add(w.Path, w.Size, w.Hex())
+}
+
+// AddFile lists a file written elsewhere, hashing it from disk
+// Complexity: O(|file|)
+func (m *ManifestWriter) AddFile(path string) error {
+	if m == nil {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return err
+	}
+	return m.add(path, size, hex.EncodeToString(h.Sum(nil)))
+}
+
+// add appends the entry for path, which must lie under the manifest's directory
+func (m *ManifestWriter) add(path string, size int64, digest string) error {
+	name, err := filepath.Rel(m.dir, path)
+	if err != nil || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("%s is outside %s", path, m.dir)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = append(m.entries, ManifestEntry{Name: filepath.ToSlash(name), Size: size, SHA256: digest})
+	return nil
+}
+
+// Write signs the manifest and writes "<base>.manifest.json" and its
+// signature to the manifest's directory
+// A nil keyPair signs with a fresh per-run key (Manifest.Ephemeral); format
+// is the signature's crypto.SignatureFormat*.
+// Returns the paths written.
+// Complexity: O(|files|)
+func (m *ManifestWriter) Write(base, runID string, keyPair *crypto.KeyPair, format string) ([]string, error) {
+	ephemeral := keyPair == nil
+	if ephemeral {
+		var err error
+		if keyPair, err = crypto.GenerateKeyPair(); err != nil {
+			return nil, err
+		}
+	}
+	m.mu.Lock()
+	manifest := &Manifest{
+		Format:    ManifestFormat,
+		RunID:     runID,
+		Files:     append([]ManifestEntry{}, m.entries...),
+		PublicKey: base64.StdEncoding.EncodeToString(keyPair.PublicKey),
+		KeyID:     crypto.Fingerprint(keyPair.PublicKey),
+		Ephemeral: ephemeral,
+	}
+	m.mu.Unlock()
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	sig, err := crypto.NewSigner(keyPair).SignDetached(data, format, crypto.MinisignComment(base+ManifestSuffix, runID))
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(m.dir, base+ManifestSuffix)
+	if err := NewWriter().WriteBinary(path, data); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	sigPath := filepath.Join(m.dir, base+ManifestSignatureSuffix)
+	if err := crypto.SaveDetached(sig, sigPath); err != nil {
+		return []string{path}, err
+	}
+	return []string{path, sigPath}, nil
+}
+
+// VerifyManifest checks a manifest's detached signature, then the size and
+// SHA-256 of every file it lists
+// With trusted nil the embedded public key is used, which proves the files
+// are intact but not who signed them. Every missing or altered file is
+// reported in the error.
+// Complexity: O(total size of the listed files)
+func VerifyManifest(path string, trusted ed25519.PublicKey) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := os.ReadFile(strings.TrimSuffix(path, ManifestSuffix) + ManifestSignatureSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signature file: %w", err)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if manifest.Format != ManifestFormat {
+		return nil, fmt.Errorf("unsupported manifest format %q", manifest.Format)
+	}
+	key := trusted
+	if key == nil {
+		embedded, err := base64.StdEncoding.DecodeString(manifest.PublicKey)
+		if err != nil || len(embedded) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid embedded public key")
+		}
+		key = ed25519.PublicKey(embedded)
+	}
+	if !crypto.VerifyDetached(key, data, sig) {
+		return nil, fmt.Errorf("manifest signature does not verify")
+	}
+
+	dir := filepath.Dir(path)
+	var bad []string
+	for _, e := range manifest.Files {
+		if !filepath.IsLocal(filepath.FromSlash(e.Name)) {
+			bad = append(bad, e.Name+" (path outside the manifest directory)")
+			continue
+		}
+		f, err := os.Open(filepath.Join(dir, filepath.FromSlash(e.Name)))
+		if err != nil {
+			bad = append(bad, e.Name+" (missing)")
+			continue
+		}
+		h := sha256.New()
+		size, err := io.Copy(h, f)
+		f.Close()
+		if err != nil || size != e.Size || hex.EncodeToString(h.Sum(nil)) != e.SHA256 {
+			bad = append(bad, e.Name+" (modified)")
+		}
+	}
+	if len(bad) > 0 {
+		return &manifest, fmt.Errorf("%d of %d files do not match: %s", len(bad), len(manifest.Files), strings.Join(bad, ", "))
+	}
+	return &manifest, nil
+}
@@ -0,0 +1,479 @@
+// Package jsonenc appends JSON to byte slices without reflection
+// Output is byte-identical to encoding/json (json.Marshal when Indent is
+// empty, json.MarshalIndent(v, "", Indent) otherwise, HTML escaping on), so
+// hand-written encoders can replace reflection on hot paths without changing
+// signed or golden bytes. With Sorted set, object keys come out in the order
+// encoding/json gives map keys instead, which makes canonical output a single
+// pass. Callers own the buffer and may reuse it.
+package jsonenc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxDepth is the deepest nesting a Writer supports
+const MaxDepth = 64
+
+// Writer appends one JSON value to Buf
+// The zero value writes compact JSON. Writers are not safe for concurrent use.
+type Writer struct {
+	Buf    []byte
+	Indent string // Per-level indentation; empty for compact output
+	Sorted bool   // Emit object members in key order; a repeated key is an error
+
+	depth    int
+	nonEmpty uint64 // Bit d set when the container at depth d+1 has an element
+	afterKey bool
+	err      error
+	sort     *sortState // Member spans of the open objects (Sorted only)
+}
+
+// member is the span of one object member in Buf, from its separator (if
+// any) to the end of its value, and of its unescaped key in sortState.keys
+type member struct {
+	keyStart, keyEnd int
+	start, end       int
+}
+
+// sortState is the scratch space of a Sorted writer, pooled across writers
+type sortState struct {
+	objects [][]member // Members per depth
+	keys    []byte     // Unescaped keys of every member, back to back
+	scratch []byte
+}
+
+var sortPool = sync.Pool{New: func() any { return new(sortState) }}
+
+// Err returns the first error recorded while writing (nil if none)
+func (w *Writer) Err() error { return w.err }
+
+// BeginObject opens an object
+func (w *Writer) BeginObject() { w.open('{') }
+
+// EndObject closes the innermost object
+func (w *Writer) EndObject() { w.close('}') }
+
+// BeginArray opens an array
+func (w *Writer) BeginArray() { w.open('[') }
+
+// EndArray closes the innermost array
+func (w *Writer) EndArray() { w.close(']') }
+
+// Key writes an object key; the next value written is its value
+func (w *Writer) Key(k string) { writeKey(w, k) }
+
+// writeKey is Key for string or byte keys
+func writeKey[S text](w *Writer, k S) {
+	if w.sort != nil && w.depth > 0 {
+		start := len(w.sort.keys)
+		w.sort.keys = append(w.sort.keys, k...)
+		members := w.sort.objects[w.depth-1]
+		w.sort.objects[w.depth-1] = append(members, member{keyStart: start, keyEnd: len(w.sort.keys), start: len(w.Buf)})
+	}
+	w.element()
+	w.Buf = appendString(w.Buf, k)
+	w.Buf = append(w.Buf, ':')
+	if w.Indent != "" {
+		w.Buf = append(w.Buf, ' ')
+	}
+	w.afterKey = true
+}
+
+// String writes a string value
+func (w *Writer) String(s string) {
+	w.value()
+	w.Buf = AppendString(w.Buf, s)
+}
+
+// Int writes an integer value
+func (w *Writer) Int(n int64) {
+	w.value()
+	w.Buf = strconv.AppendInt(w.Buf, n, 10)
+}
+
+// Bool writes a boolean value
+func (w *Writer) Bool(b bool) {
+	w.value()
+	w.Buf = strconv.AppendBool(w.Buf, b)
+}
+
+// Null writes null
+func (w *Writer) Null() {
+	w.value()
+	w.Buf = append(w.Buf, "null"...)
+}
+
+// Time writes t as time.Time.MarshalJSON does (RFC 3339 with nanoseconds)
+// Years outside [0,9999] record an error, as they do in encoding/json.
+func (w *Writer) Time(t time.Time) {
+	if y := t.Year(); y < 0 || y > 9999 {
+		w.fail(fmt.Errorf("time year %d outside of range [0,9999]", y))
+		return
+	}
+	if _, offset := t.Zone(); offset%60 != 0 {
+		w.fail(fmt.Errorf("time zone offset %ds has sub-minute precision", offset))
+		return
+	}
+	w.value()
+	w.Buf = append(w.Buf, '"')
+	w.Buf = t.AppendFormat(w.Buf, time.RFC3339Nano)
+	w.Buf = append(w.Buf, '"')
+}
+
+// Raw writes an already-encoded JSON value (e.g., from json.Marshal)
+// It is compacted, HTML-escaped and indented to the current depth, as
+// encoding/json does with a json.RawMessage; invalid JSON records an error.
+// A Sorted writer re-encodes it token by token instead, so its objects are
+// sorted too and a repeated key is an error rather than silently dropped.
+func (w *Writer) Raw(data []byte) {
+	if w.Sorted {
+		if !json.Valid(data) {
+			w.fail(fmt.Errorf("raw value: %w", json.Compact(new(bytes.Buffer), data)))
+			return
+		}
+		r := rawReader{data: data}
+		r.value(w)
+		return
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		w.fail(fmt.Errorf("raw value: %w", err))
+		return
+	}
+	var escaped bytes.Buffer
+	json.HTMLEscape(&escaped, compact.Bytes())
+	w.value()
+	if w.Indent == "" {
+		w.Buf = append(w.Buf, escaped.Bytes()...)
+		return
+	}
+	var indented bytes.Buffer
+	json.Indent(&indented, escaped.Bytes(), strings.Repeat(w.Indent, w.depth), w.Indent)
+	w.Buf = append(w.Buf, indented.Bytes()...)
+}
+
+// rawReader streams a validated JSON value into a Writer token by token,
+// without decoding it into a tree: strings are unescaped and re-escaped,
+// numbers are copied verbatim and whitespace is dropped
+type rawReader struct {
+	data []byte
+	pos  int
+}
+
+// value writes the value at pos and moves past it
+// Complexity: O(|value|)
+func (r *rawReader) value(w *Writer) {
+	r.skipSpace()
+	switch r.data[r.pos] {
+	case '{':
+		r.pos++
+		w.BeginObject()
+		for r.skipSpace(); w.err == nil && r.data[r.pos] != '}'; r.skipSpace() {
+			if r.data[r.pos] == ',' {
+				r.pos++
+				r.skipSpace()
+			}
+			writeKey(w, r.str())
+			r.skipSpace()
+			r.pos++ // ':'
+			r.value(w)
+		}
+		r.pos++
+		w.EndObject()
+	case '[':
+		r.pos++
+		w.BeginArray()
+		for r.skipSpace(); w.err == nil && r.data[r.pos] != ']'; r.skipSpace() {
+			if r.data[r.pos] == ',' {
+				r.pos++
+			}
+			r.value(w)
+		}
+		r.pos++
+		w.EndArray()
+	case '"':
+		s := r.str()
+		w.value()
+		w.Buf = appendString(w.Buf, s)
+	case 't':
+		r.pos += len("true")
+		w.Bool(true)
+	case 'f':
+		r.pos += len("false")
+		w.Bool(false)
+	case 'n':
+		r.pos += len("null")
+		w.Null()
+	default:
+		start := r.pos
+		for r.pos < len(r.data) && strings.IndexByte("+-.0123456789Ee", r.data[r.pos]) >= 0 {
+			r.pos++
+		}
+		w.value()
+		w.Buf = append(w.Buf, r.data[start:r.pos]...)
+	}
+}
+
+// str returns the unescaped string at pos and moves past it
+// Strings without escapes are returned in place; others are decoded.
+func (r *rawReader) str() []byte {
+	start := r.pos
+	escaped := false
+	r.pos++
+	for r.data[r.pos] != '"' {
+		if r.data[r.pos] == '\\' {
+			escaped = true
+			r.pos++
+		}
+		r.pos++
+	}
+	r.pos++
+	if !escaped {
+		return r.data[start+1 : r.pos-1]
+	}
+	var s string
+	json.Unmarshal(r.data[start:r.pos], &s) // Validated by the caller
+	return []byte(s)
+}
+
+// skipSpace moves pos past JSON whitespace
+func (r *rawReader) skipSpace() {
+	for r.pos < len(r.data) {
+		switch r.data[r.pos] {
+		case ' ', '\t', '\n', '\r':
+			r.pos++
+		default:
+			return
+		}
+	}
+}
+
+// StringField writes a key and string value
+func (w *Writer) StringField(k, v string) {
+	w.Key(k)
+	w.String(v)
+}
+
+// Strings writes a string array; nil is written as null, like encoding/json
+func (w *Writer) Strings(values []string) {
+	if values == nil {
+		w.Null()
+		return
+	}
+	w.BeginArray()
+	for _, v := range values {
+		w.String(v)
+	}
+	w.EndArray()
+}
+
+// open starts a container at the next depth
+func (w *Writer) open(c byte) {
+	if w.depth == MaxDepth {
+		w.fail(fmt.Errorf("nesting exceeds %d levels", MaxDepth))
+		return
+	}
+	w.value()
+	w.Buf = append(w.Buf, c)
+	w.depth++
+	w.nonEmpty &^= 1 << (w.depth - 1)
+	if w.Sorted && c == '{' {
+		if w.sort == nil {
+			w.sort = sortPool.Get().(*sortState)
+			w.sort.keys = w.sort.keys[:0]
+		}
+		for len(w.sort.objects) < w.depth {
+			w.sort.objects = append(w.sort.objects, nil)
+		}
+		w.sort.objects[w.depth-1] = w.sort.objects[w.depth-1][:0]
+	}
+}
+
+// close ends the innermost container; empty containers stay on one line
+func (w *Writer) close(c byte) {
+	if w.depth == 0 {
+		w.fail(fmt.Errorf("unbalanced %q", c))
+		return
+	}
+	if w.sort != nil && c == '}' {
+		w.sortMembers(w.sort.objects[w.depth-1])
+	}
+	bit := uint64(1) << (w.depth - 1)
+	if w.nonEmpty&bit != 0 {
+		w.newline(w.depth - 1)
+	}
+	w.nonEmpty &^= bit
+	w.depth--
+	w.Buf = append(w.Buf, c)
+	if w.sort != nil && w.depth == 0 {
+		sortPool.Put(w.sort)
+		w.sort = nil
+	}
+}
+
+// sortMembers reorders the members of the object being closed by key
+// Every member is moved whole (separator, indentation, key and value), and
+// indentation depends only on depth, so the result is what writing the
+// members in key order would have produced. Nested objects are already
+// sorted, having closed first.
+// Complexity: O(m log m + bytes in the object)
+func (w *Writer) sortMembers(members []member) {
+	if len(members) == 0 {
+		return
+	}
+	for i := range members[:len(members)-1] {
+		members[i].end = members[i+1].start
+	}
+	members[len(members)-1].end = len(w.Buf)
+
+	keys := w.sort.keys
+	compare := func(a, b member) int {
+		return bytes.Compare(keys[a.keyStart:a.keyEnd], keys[b.keyStart:b.keyEnd])
+	}
+	sorted := true
+	for i := 1; i < len(members); i++ {
+		if compare(members[i-1], members[i]) >= 0 {
+			sorted = false
+			break
+		}
+	}
+	if sorted {
+		return
+	}
+
+	base := members[0].start
+	w.sort.scratch = append(w.sort.scratch[:0], w.Buf[base:]...)
+	slices.SortStableFunc(members, compare)
+	w.Buf = w.Buf[:base]
+	for i, m := range members {
+		if i > 0 && compare(m, members[i-1]) == 0 {
+			w.fail(fmt.Errorf("duplicate object key %q", keys[m.keyStart:m.keyEnd]))
+			return
+		}
+		span := w.sort.scratch[m.start-base : m.end-base]
+		if span[0] == ',' {
+			span = span[1:]
+		}
+		if i > 0 {
+			w.Buf = append(w.Buf, ',')
+		}
+		w.Buf = append(w.Buf, span...)
+	}
+}
+
+// value prepares for a value: nothing after a key, a separator in an array
+func (w *Writer) value() {
+	if w.afterKey {
+		w.afterKey = false
+		return
+	}
+	if w.depth > 0 {
+		w.element()
+	}
+}
+
+// element writes the separator and indentation before a container element
+func (w *Writer) element() {
+	bit := uint64(1) << (w.depth - 1)
+	if w.nonEmpty&bit != 0 {
+		w.Buf = append(w.Buf, ',')
+	}
+	w.nonEmpty |= bit
+	w.newline(w.depth)
+}
+
+// newline starts a line indented depth levels (indented output only)
+func (w *Writer) newline(depth int) {
+	if w.Indent == "" {
+		return
+	}
+	w.Buf = append(w.Buf, '\n')
+	for i := 0; i < depth; i++ {
+		w.Buf = append(w.Buf, w.Indent...)
+	}
+}
+
+// fail records the first error
+func (w *Writer) fail(err error) {
+	if w.err == nil {
+		w.err = err
+	}
+}
+
+const hex = "0123456789abcdef"
+
+// text is a string or the bytes of one
+type text interface{ ~string | ~[]byte }
+
+// AppendString appends s as a JSON string escaped exactly as encoding/json
+// does with HTML escaping: control characters, quote, backslash, <, > and &
+// are escaped, invalid UTF-8 becomes U+FFFD (written literally, as current
+// encoding/json does), and U+2028/U+2029 are escaped
+// Complexity: O(|s|)
+func AppendString(dst []byte, s string) []byte { return appendString(dst, s) }
+
+// appendString is AppendString for string or byte input
+func appendString[S text](dst []byte, s S) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' && b != '<' && b != '>' && b != '&' {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch b {
+			case '\\', '"':
+				dst = append(dst, '\\', b)
+			case '\b':
+				dst = append(dst, '\\', 'b')
+			case '\f':
+				dst = append(dst, '\\', 'f')
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hex[b>>4], hex[b&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		c, size := decodeRune(s[i:])
+		if c == utf8.RuneError && size == 1 {
+			dst = append(dst, s[start:i]...)
+			dst = utf8.AppendRune(dst, utf8.RuneError)
+			i += size
+			start = i
+			continue
+		}
+		if c == '\u2028' || c == '\u2029' {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hex[c&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
+
+// decodeRune is utf8.DecodeRune for string or byte input
+func decodeRune[S text](s S) (rune, int) {
+	var b [utf8.UTFMax]byte
+	return utf8.DecodeRune(b[:copy(b[:], s)])
+}
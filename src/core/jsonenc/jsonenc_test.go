@@ -0,0 +1,177 @@
+package jsonenc
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestAppendString_MatchesEncodingJSON verifies escaping of every ASCII byte
+func TestAppendString_MatchesEncodingJSON(t *testing.T) {
+	var b strings.Builder
+	for c := 0; c < 0x80; c++ {
+		b.WriteByte(byte(c))
+	}
+	b.WriteString("\u00e9\u2028\u2029\U0001F600")
+	s := b.String()
+
+	want, _ := json.Marshal(s)
+	if got := AppendString(nil, s); string(got) != string(want) {
+		t.Errorf("AppendString() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+// TestAppendString_InvalidUTF8 verifies invalid bytes decode as U+FFFD
+func TestAppendString_InvalidUTF8(t *testing.T) {
+	var back string
+	if err := json.Unmarshal(AppendString(nil, "a\xffb\xc0"), &back); err != nil {
+		t.Fatalf("AppendString() produced invalid JSON: %v", err)
+	}
+	if back != "a\ufffdb\ufffd" {
+		t.Errorf("decoded %q, want U+FFFD replacements", back)
+	}
+}
+
+// TestWriter_MatchesMarshalIndent verifies nesting, empty containers and separators
+func TestWriter_MatchesMarshalIndent(t *testing.T) {
+	type inner struct {
+		A []string          `json:"a"`
+		B map[string]string `json:"b"`
+		C []string          `json:"c"`
+	}
+	v := []any{inner{A: []string{}, B: map[string]string{}, C: []string{"x", "y"}}, int64(-7), true, nil}
+
+	for _, indent := range []string{"", "  ", "\t"} {
+		w := Writer{Indent: indent}
+		w.BeginArray()
+		w.BeginObject()
+		w.Key("a")
+		w.Strings([]string{})
+		w.Key("b")
+		w.BeginObject()
+		w.EndObject()
+		w.Key("c")
+		w.Strings([]string{"x", "y"})
+		w.EndObject()
+		w.Int(-7)
+		w.Bool(true)
+		w.Null()
+		w.EndArray()
+
+		want, _ := json.MarshalIndent(v, "", indent)
+		if indent == "" {
+			want, _ = json.Marshal(v)
+		}
+		if w.Err() != nil || string(w.Buf) != string(want) {
+			t.Errorf("indent %q: Writer = %s (%v), want %s", indent, w.Buf, w.Err(), want)
+		}
+	}
+}
+
+// TestWriter_Raw verifies embedded JSON is re-indented like json.RawMessage
+func TestWriter_Raw(t *testing.T) {
+	raw := json.RawMessage(`{ "b": [1, {"c": "<x>"}], "a": {} }`)
+	v := map[string]any{"ext": map[string]json.RawMessage{"acme": raw}}
+
+	for _, indent := range []string{"", "  "} {
+		w := Writer{Indent: indent}
+		w.BeginObject()
+		w.Key("ext")
+		w.BeginObject()
+		w.Key("acme")
+		w.Raw(raw)
+		w.EndObject()
+		w.EndObject()
+
+		want, _ := json.MarshalIndent(v, "", indent)
+		if indent == "" {
+			want, _ = json.Marshal(v)
+		}
+		if w.Err() != nil || string(w.Buf) != string(want) {
+			t.Errorf("indent %q: Writer = %s (%v), want %s", indent, w.Buf, w.Err(), want)
+		}
+	}
+
+	w := Writer{}
+	w.Raw([]byte("{broken"))
+	if w.Err() == nil {
+		t.Error("invalid raw JSON should be reported")
+	}
+}
+
+// TestWriter_Sorted verifies keys, including those of raw values, come out in
+// the order encoding/json gives map keys and numbers keep their text
+func TestWriter_Sorted(t *testing.T) {
+	raw := []byte(`{ "z": 1.50e3, "\u00e9": "<\u0041>", "a": [ {"y": null, "x": true}, -0 ], "A\"": {} }`)
+
+	var ext any
+	dec := json.NewDecoder(strings.NewReader(string(raw)))
+	dec.UseNumber()
+	if err := dec.Decode(&ext); err != nil {
+		t.Fatal(err)
+	}
+	v := map[string]any{"b": "x", "a": map[string]any{"d": int64(1), "c": ext}, "é": []any{}}
+
+	for _, indent := range []string{"", "  "} {
+		w := Writer{Indent: indent, Sorted: true}
+		w.BeginObject()
+		w.StringField("b", "x")
+		w.Key("a")
+		w.BeginObject()
+		w.Key("d")
+		w.Int(1)
+		w.Key("c")
+		w.Raw(raw)
+		w.EndObject()
+		w.Key("é")
+		w.BeginArray()
+		w.EndArray()
+		w.EndObject()
+
+		want, _ := json.MarshalIndent(v, "", indent)
+		if indent == "" {
+			want, _ = json.Marshal(v)
+		}
+		if w.Err() != nil || string(w.Buf) != string(want) {
+			t.Errorf("indent %q: Writer = %s (%v), want %s", indent, w.Buf, w.Err(), want)
+		}
+	}
+}
+
+// TestWriter_SortedDuplicateKey verifies a repeated key is an error, whether
+// written directly or inside a raw value
+func TestWriter_SortedDuplicateKey(t *testing.T) {
+	w := Writer{Sorted: true}
+	w.BeginObject()
+	w.StringField("a", "x")
+	w.StringField("a", "y")
+	w.EndObject()
+	if w.Err() == nil {
+		t.Error("duplicate key not reported")
+	}
+
+	for _, raw := range []string{`{"a":1,"b":{"c":1,"c":2}}`, `{"\u0061":1,"a":2}`, `{"a":1} {}`, `{"a":`} {
+		w := Writer{Sorted: true}
+		w.Raw([]byte(raw))
+		if w.Err() == nil {
+			t.Errorf("Raw(%s) not reported", raw)
+		}
+	}
+}
+
+// TestWriter_Errors verifies unbalanced and over-deep output is reported
+func TestWriter_Errors(t *testing.T) {
+	var w Writer
+	w.EndObject()
+	if w.Err() == nil {
+		t.Error("unbalanced EndObject() not reported")
+	}
+
+	var deep Writer
+	for i := 0; i <= MaxDepth; i++ {
+		deep.BeginArray()
+	}
+	if deep.Err() == nil {
+		t.Errorf("nesting beyond %d levels not reported", MaxDepth)
+	}
+}
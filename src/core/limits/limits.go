@@ -0,0 +1,128 @@
+// Package limits caps the agent's memory and CPU use on a live workstation
+// Runtime limits (GOMEMLIMIT, GOMAXPROCS) and a lowered scheduling priority
+// always apply; with os_limits the same ceilings are also enforced by the OS
+// through a cgroup v2 (Linux) or job object (Windows), which also covers the
+// memory llama.cpp allocates outside the Go heap. OS limits are best effort:
+// they usually need privileges an operator may not have, so failures are
+// returned as warnings rather than stopping the run.
+package limits
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+)
+
+// niceLevel is the Unix nice value for low_priority runs
+const niceLevel = 10
+
+// errUnsupported is returned by the platform hooks where a limit has no OS mechanism
+var errUnsupported = errors.New("not supported on " + runtime.GOOS)
+
+// lowerPriority and applyOSLimits are replaced in tests
+var (
+	lowerPriority = platformLowerPriority
+	applyOSLimits = platformApplyOSLimits
+)
+
+// Applied records the limits in force so they can be reported and released
+type Applied struct {
+	// MemoryLimit is the Go heap soft limit in bytes (0 = unlimited)
+	MemoryLimit int64
+
+	// MaxProcs is the effective GOMAXPROCS
+	MaxProcs int
+
+	// LowPriority is set when the scheduling priority was lowered
+	LowPriority bool
+
+	// OSLimits describes the OS mechanism enforcing the ceilings ("" if none)
+	OSLimits string
+
+	prevMemoryLimit int64
+	prevMaxProcs    int
+	release         func() error
+}
+
+// Apply enforces r on the current process
+// GOMEMLIMIT and GOMAXPROCS set in the environment take precedence over the
+// config. Warnings describe limits that could not be applied.
+// Complexity: O(t) where t = number of process threads
+func Apply(r config.ResourcesConfig) (*Applied, []error) {
+	a := &Applied{
+		prevMemoryLimit: debug.SetMemoryLimit(-1),
+		prevMaxProcs:    runtime.GOMAXPROCS(0),
+	}
+	var warnings []error
+
+	memBytes := int64(r.MemoryLimitMB) << 20
+	if memBytes > 0 && os.Getenv("GOMEMLIMIT") == "" {
+		debug.SetMemoryLimit(memBytes)
+	}
+	if r.MaxProcs > 0 && os.Getenv("GOMAXPROCS") == "" {
+		runtime.GOMAXPROCS(r.MaxProcs)
+	}
+	if limit := debug.SetMemoryLimit(-1); limit != maxMemoryLimit {
+		a.MemoryLimit = limit
+	}
+	a.MaxProcs = runtime.GOMAXPROCS(0)
+
+	if r.LowPriority {
+		if err := lowerPriority(); err != nil {
+			warnings = append(warnings, fmt.Errorf("failed to lower priority: %w", err))
+		} else {
+			a.LowPriority = true
+		}
+	}
+
+	if r.OSLimits && (memBytes > 0 || r.CPUPercent > 0) {
+		desc, release, err := applyOSLimits(memBytes, r.CPUPercent)
+		if err != nil {
+			warnings = append(warnings, fmt.Errorf("failed to apply OS limits: %w", err))
+		} else {
+			a.OSLimits, a.release = desc, release
+		}
+	}
+	return a, warnings
+}
+
+// maxMemoryLimit is the runtime's "no limit" value
+const maxMemoryLimit = int64(^uint64(0) >> 1)
+
+// Release restores the runtime limits and leaves any OS container
+// The priority stays lowered; the process is about to exit or idle.
+// Complexity: O(1)
+func (a *Applied) Release() error {
+	if a == nil {
+		return nil
+	}
+	debug.SetMemoryLimit(a.prevMemoryLimit)
+	runtime.GOMAXPROCS(a.prevMaxProcs)
+	if a.release == nil {
+		return nil
+	}
+	err := a.release()
+	a.release = nil
+	return err
+}
+
+// String summarizes the limits in force (e.g., "memory 512 MiB, 2 procs, low priority")
+func (a *Applied) String() string {
+	parts := []string{}
+	if a.MemoryLimit > 0 {
+		parts = append(parts, fmt.Sprintf("memory %d MiB", a.MemoryLimit>>20))
+	}
+	parts = append(parts, fmt.Sprintf("%d procs", a.MaxProcs))
+	if a.LowPriority {
+		parts = append(parts, "low priority")
+	}
+	if a.OSLimits != "" {
+		parts = append(parts, a.OSLimits)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,22 @@
+//go:build darwin
+
+package limits
+
+import "golang.org/x/sys/unix"
+
+// platformLowerPriority renices the process (never raising an existing nice)
+func platformLowerPriority() error {
+	nice, err := unix.Getpriority(unix.PRIO_PROCESS, 0)
+	if err != nil {
+		return err
+	}
+	if nice >= niceLevel {
+		return nil
+	}
+	return unix.Setpriority(unix.PRIO_PROCESS, 0, niceLevel)
+}
+
+// platformApplyOSLimits has no unprivileged per-process memory or CPU cap on macOS
+func platformApplyOSLimits(memBytes int64, cpuPercent int) (string, func() error, error) {
+	return "", nil, errUnsupported
+}
@@ -0,0 +1,73 @@
+//go:build linux
+
+package limits
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+// fakeCgroup lays out a cgroup v2 hierarchy and /proc/self in temp directories
+func fakeCgroup(t *testing.T) string {
+	t.Helper()
+	root, proc := t.TempDir(), t.TempDir()
+	os.WriteFile(filepath.Join(root, "cgroup.controllers"), []byte("cpu memory pids"), 0644)
+	os.MkdirAll(filepath.Join(root, "user.slice"), 0755)
+	os.WriteFile(filepath.Join(proc, "cgroup"), []byte("0::/user.slice\n"), 0644)
+
+	prevRoot, prevProc := cgroupRoot, procSelf
+	cgroupRoot, procSelf = root, proc
+	t.Cleanup(func() { cgroupRoot, procSelf = prevRoot, prevProc })
+	return root
+}
+
+// TestPlatformApplyOSLimits_Cgroup verifies the cgroup files and release
+func TestPlatformApplyOSLimits_Cgroup(t *testing.T) {
+	root := fakeCgroup(t)
+
+	desc, release, err := platformApplyOSLimits(512<<20, 50)
+	if err != nil {
+		t.Fatalf("platformApplyOSLimits failed: %v", err)
+	}
+	dir := filepath.Join(root, fmt.Sprintf("minibeast-%d", os.Getpid()))
+	if desc != "cgroup "+dir {
+		t.Errorf("Description = %q", desc)
+	}
+
+	pid := strconv.Itoa(os.Getpid())
+	want := map[string]string{
+		"memory.max":   strconv.Itoa(512 << 20),
+		"cpu.max":      fmt.Sprintf("%d 100000", 50*runtime.NumCPU()*1000),
+		"cgroup.procs": pid,
+	}
+	for name, value := range want {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil || string(data) != value {
+			t.Errorf("%s = %q (%v), want %q", name, data, err, value)
+		}
+	}
+
+	if err := release(); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, "user.slice", "cgroup.procs")); string(data) != pid {
+		t.Errorf("Process not moved back to its cgroup: %q", data)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Error("Cgroup not removed on release")
+	}
+}
+
+// TestPlatformApplyOSLimits_NoCgroup2 verifies a clear error without cgroup v2
+func TestPlatformApplyOSLimits_NoCgroup2(t *testing.T) {
+	root := fakeCgroup(t)
+	os.Remove(filepath.Join(root, "cgroup.controllers"))
+
+	if _, _, err := platformApplyOSLimits(512<<20, 0); err == nil {
+		t.Error("Expected an error without cgroup v2")
+	}
+}
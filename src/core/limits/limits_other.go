@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !windows
+
+package limits
+
+// platformLowerPriority has no portable mechanism here
+func platformLowerPriority() error {
+	return errUnsupported
+}
+
+// platformApplyOSLimits has no container mechanism here
+func platformApplyOSLimits(memBytes int64, cpuPercent int) (string, func() error, error) {
+	return "", nil, errUnsupported
+}
@@ -0,0 +1,104 @@
+package limits
+
+import (
+	"errors"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+)
+
+// stubPlatform replaces the platform hooks for one test
+func stubPlatform(t *testing.T, priority error, osLimits error) *[]int {
+	t.Helper()
+	calls := &[]int{}
+	prevPriority, prevOS := lowerPriority, applyOSLimits
+	lowerPriority = func() error { return priority }
+	applyOSLimits = func(memBytes int64, cpuPercent int) (string, func() error, error) {
+		*calls = append(*calls, int(memBytes>>20), cpuPercent)
+		if osLimits != nil {
+			return "", nil, osLimits
+		}
+		return "test container", func() error { return nil }, nil
+	}
+	t.Cleanup(func() { lowerPriority, applyOSLimits = prevPriority, prevOS })
+	return calls
+}
+
+// TestApply_RuntimeLimits verifies GOMEMLIMIT/GOMAXPROCS wiring and restore
+func TestApply_RuntimeLimits(t *testing.T) {
+	t.Setenv("GOMEMLIMIT", "")
+	t.Setenv("GOMAXPROCS", "")
+	stubPlatform(t, nil, nil)
+	prevMem, prevProcs := debug.SetMemoryLimit(-1), runtime.GOMAXPROCS(0)
+
+	a, warnings := Apply(config.ResourcesConfig{MemoryLimitMB: 256, MaxProcs: 1, LowPriority: true})
+	if len(warnings) != 0 {
+		t.Fatalf("Unexpected warnings: %v", warnings)
+	}
+	if got := debug.SetMemoryLimit(-1); got != 256<<20 {
+		t.Errorf("Memory limit = %d, want %d", got, 256<<20)
+	}
+	if got := runtime.GOMAXPROCS(0); got != 1 {
+		t.Errorf("GOMAXPROCS = %d, want 1", got)
+	}
+	if a.MemoryLimit != 256<<20 || a.MaxProcs != 1 || !a.LowPriority || a.OSLimits != "" {
+		t.Errorf("Unexpected applied limits: %+v", a)
+	}
+	if got := a.String(); got != "memory 256 MiB, 1 procs, low priority" {
+		t.Errorf("String() = %q", got)
+	}
+
+	if err := a.Release(); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if debug.SetMemoryLimit(-1) != prevMem || runtime.GOMAXPROCS(0) != prevProcs {
+		t.Error("Release did not restore runtime limits")
+	}
+}
+
+// TestApply_EnvironmentWins verifies GOMEMLIMIT and GOMAXPROCS override the config
+func TestApply_EnvironmentWins(t *testing.T) {
+	t.Setenv("GOMEMLIMIT", "1GiB")
+	t.Setenv("GOMAXPROCS", "2")
+	stubPlatform(t, nil, nil)
+	prevMem, prevProcs := debug.SetMemoryLimit(-1), runtime.GOMAXPROCS(0)
+
+	a, _ := Apply(config.ResourcesConfig{MemoryLimitMB: 256, MaxProcs: 1})
+	defer a.Release()
+	if debug.SetMemoryLimit(-1) != prevMem || runtime.GOMAXPROCS(0) != prevProcs {
+		t.Error("Config overrode limits set in the environment")
+	}
+}
+
+// TestApply_OSLimits verifies OS limits are requested only when configured
+// and that failures become warnings
+func TestApply_OSLimits(t *testing.T) {
+	calls := stubPlatform(t, nil, nil)
+	a, warnings := Apply(config.ResourcesConfig{MemoryLimitMB: 512})
+	a.Release()
+	if len(*calls) != 0 || len(warnings) != 0 {
+		t.Errorf("OS limits applied without os_limits: calls %v, warnings %v", *calls, warnings)
+	}
+
+	a, warnings = Apply(config.ResourcesConfig{MemoryLimitMB: 512, OSLimits: true, CPUPercent: 25})
+	a.Release()
+	if len(warnings) != 0 || a.OSLimits != "test container" {
+		t.Errorf("OS limits not applied: %+v, warnings %v", a, warnings)
+	}
+	if len(*calls) != 2 || (*calls)[0] != 512 || (*calls)[1] != 25 {
+		t.Errorf("applyOSLimits called with %v, want [512 25]", *calls)
+	}
+
+	stubPlatform(t, errors.New("renice denied"), errors.New("cgroup denied"))
+	a, warnings = Apply(config.ResourcesConfig{LowPriority: true, OSLimits: true, CPUPercent: 25})
+	defer a.Release()
+	if len(warnings) != 2 || a.LowPriority || a.OSLimits != "" {
+		t.Fatalf("Expected two warnings and no limits, got %+v, %v", a, warnings)
+	}
+	if !strings.Contains(warnings[1].Error(), "cgroup denied") {
+		t.Errorf("Warning lost its cause: %v", warnings[1])
+	}
+}
@@ -0,0 +1,77 @@
+//go:build windows
+
+package limits
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// jobCPURateControl mirrors JOBOBJECT_CPU_RATE_CONTROL_INFORMATION (CpuRate form)
+type jobCPURateControl struct {
+	ControlFlags uint32
+	CPURate      uint32 // Hundredths of a percent of all processors
+}
+
+// Control flags for jobCPURateControl
+const (
+	jobCPURateControlEnable  = 0x1
+	jobCPURateControlHardCap = 0x4
+)
+
+// platformLowerPriority drops a normal-priority process to below normal
+func platformLowerPriority() error {
+	process := windows.CurrentProcess()
+	class, err := windows.GetPriorityClass(process)
+	if err != nil {
+		return err
+	}
+	if class != windows.NORMAL_PRIORITY_CLASS && class != windows.ABOVE_NORMAL_PRIORITY_CLASS && class != windows.HIGH_PRIORITY_CLASS {
+		return nil // Already below normal or idle
+	}
+	return windows.SetPriorityClass(process, windows.BELOW_NORMAL_PRIORITY_CLASS)
+}
+
+// platformApplyOSLimits assigns the process to a job object with a process
+// memory limit and a hard CPU rate cap
+// A process cannot leave a job, so the limits hold until exit; release only
+// closes the handle.
+func platformApplyOSLimits(memBytes int64, cpuPercent int) (string, func() error, error) {
+	job, err := windows.CreateJobObject(nil, nil)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create job object: %w", err)
+	}
+
+	if memBytes > 0 {
+		var info windows.JOBOBJECT_EXTENDED_LIMIT_INFORMATION
+		info.BasicLimitInformation.LimitFlags = windows.JOB_OBJECT_LIMIT_PROCESS_MEMORY
+		info.ProcessMemoryLimit = uintptr(memBytes)
+		if _, err := windows.SetInformationJobObject(job, windows.JobObjectExtendedLimitInformation,
+			uintptr(unsafe.Pointer(&info)), uint32(unsafe.Sizeof(info))); err != nil {
+			windows.CloseHandle(job)
+			return "", nil, fmt.Errorf("failed to set job memory limit: %w", err)
+		}
+	}
+	if cpuPercent > 0 {
+		info := jobCPURateControl{
+			ControlFlags: jobCPURateControlEnable | jobCPURateControlHardCap,
+			CPURate:      uint32(cpuPercent * 100),
+		}
+		if _, err := windows.SetInformationJobObject(job, windows.JobObjectCpuRateControlInformation,
+			uintptr(unsafe.Pointer(&info)), uint32(unsafe.Sizeof(info))); err != nil {
+			windows.CloseHandle(job)
+			return "", nil, fmt.Errorf("failed to set job CPU rate: %w", err)
+		}
+	}
+
+	if err := windows.AssignProcessToJobObject(job, windows.CurrentProcess()); err != nil {
+		windows.CloseHandle(job)
+		return "", nil, fmt.Errorf("failed to assign job object: %w", err)
+	}
+	release := func() error {
+		return windows.CloseHandle(job)
+	}
+	return "job object", release, nil
+}
@@ -0,0 +1,148 @@
+// Package logging writes a run's diagnostic log with log/slog
+// Unlike the audit file, which lists only significant actions, the log
+// records why a run went the way it did (a category's error, a fallback
+// taken, a file that failed to write) so failed categories and degraded
+// collection can be diagnosed after the drive is unplugged. Each run's log
+// is written next to its artifacts as "<run>.log".
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/minibeast/usb-agent/src/core/progress"
+)
+
+// Suffix is the log file name suffix after the run's base name
+const Suffix = ".log"
+
+// Output formats
+const (
+	FormatText = "text" // key=value lines
+	FormatJSON = "json" // One JSON object per line
+)
+
+// ParseLevel parses debug, info, warn or error (case-insensitive)
+// Complexity: O(1)
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	switch strings.ToLower(s) {
+	case "debug", "info", "warn", "error":
+		err := level.UnmarshalText([]byte(s))
+		return level, err
+	}
+	return level, fmt.Errorf("unknown log level %q (debug, info, warn or error)", s)
+}
+
+// New returns a logger writing format records at level and above to w
+// Complexity: O(1)
+func New(w io.Writer, format string, level slog.Level) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level}
+	if format == FormatJSON {
+		return slog.New(slog.NewJSONHandler(w, opts))
+	}
+	return slog.New(slog.NewTextHandler(w, opts))
+}
+
+// ctxKey carries the logger in a context
+type ctxKey struct{}
+
+// With returns a context carrying l, whose pipeline stages are logged
+// Stage starts and completions are debug records and failures warnings,
+// each with the stage's phase ("collect.pii_info" is phase collect).
+// Complexity: O(1)
+func With(ctx context.Context, l *slog.Logger) context.Context {
+	ctx = progress.AddReporter(ctx, func(ev progress.Event) { observe(l, ev) })
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// WithPhase returns a context whose logger tags every record with phase
+// Complexity: O(1)
+func WithPhase(ctx context.Context, phase string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, From(ctx).With("phase", phase))
+}
+
+// From returns the logger carried by ctx (one discarding every record when none)
+func From(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return discard
+}
+
+// discard drops every record
+var discard = slog.New(discardHandler{})
+
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (d discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return d }
+func (d discardHandler) WithGroup(string) slog.Handler           { return d }
+
+// observe logs a stage transition
+func observe(l *slog.Logger, ev progress.Event) {
+	phase, _, _ := strings.Cut(ev.Stage, ".")
+	switch ev.State {
+	case progress.Started:
+		l.Debug("stage started", "phase", phase, "stage", ev.Stage)
+	case progress.Done:
+		l.Debug("stage done", "phase", phase, "stage", ev.Stage, "elapsed_ms", ev.Elapsed.Milliseconds())
+	case progress.Failed:
+		l.Warn("stage failed", "phase", phase, "stage", ev.Stage, "elapsed_ms", ev.Elapsed.Milliseconds(), "error", ev.Err)
+	}
+}
+
+// File is a run's log file
+// It is created as "<runID>.log" when the run starts, before the artifact
+// base name is known, so a run that never gets that far still leaves its
+// log; Close renames it to "<base>.log".
+type File struct {
+	f   *os.File
+	dir string
+}
+
+// Create opens "<runID>.log" in dir, creating dir if needed
+// Complexity: O(1)
+func Create(dir, runID string) (*File, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(filepath.Join(dir, runID+Suffix), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &File{f: f, dir: dir}, nil
+}
+
+// Write appends p to the file
+func (f *File) Write(p []byte) (int, error) {
+	return f.f.Write(p)
+}
+
+// Close syncs and closes the file, then renames it to "<base>.log"
+// The returned path is where the log is, renamed or not.
+// Complexity: O(1)
+func (f *File) Close(base string) (string, error) {
+	path := f.f.Name()
+	err := f.f.Sync()
+	if cerr := f.f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return path, err
+	}
+	final := filepath.Join(f.dir, base+Suffix)
+	if final == path {
+		return path, nil
+	}
+	if err := os.Rename(path, final); err != nil {
+		return path, err
+	}
+	return final, nil
+}
@@ -0,0 +1,94 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/minibeast/usb-agent/src/core/progress"
+)
+
+func TestParseLevel(t *testing.T) {
+	for in, want := range map[string]slog.Level{"debug": slog.LevelDebug, "INFO": slog.LevelInfo, "warn": slog.LevelWarn, "error": slog.LevelError} {
+		if got, err := ParseLevel(in); err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v", in, got, err)
+		}
+	}
+	for _, in := range []string{"", "trace", "info+2"} {
+		if _, err := ParseLevel(in); err == nil {
+			t.Errorf("ParseLevel(%q) accepted", in)
+		}
+	}
+}
+
+func TestWith_LogsStagesAndKeepsReporter(t *testing.T) {
+	var shown int
+	ctx := progress.WithReporter(context.Background(), func(progress.Event) { shown++ })
+	var buf bytes.Buffer
+	ctx = With(ctx, New(&buf, FormatJSON, slog.LevelInfo))
+
+	progress.Start(ctx, "collect.system_info").End(nil)
+	progress.Start(ctx, "collect.pii_info").End(errors.New("permission denied"))
+
+	if shown != 4 {
+		t.Errorf("existing reporter saw %d events, want 4", shown)
+	}
+	// Info level drops the debug start/done records
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("log = %q", buf.String())
+	}
+	var rec map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &rec); err != nil {
+		t.Fatal(err)
+	}
+	if rec["level"] != "WARN" || rec["phase"] != "collect" || rec["stage"] != "collect.pii_info" || rec["error"] != "permission denied" {
+		t.Errorf("record = %v", rec)
+	}
+}
+
+func TestWithPhase_TagsRecords(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := With(context.Background(), New(&buf, FormatText, slog.LevelDebug))
+	From(WithPhase(ctx, "summarize")).Info("model loaded")
+	if !strings.Contains(buf.String(), "phase=summarize") {
+		t.Errorf("log = %q", buf.String())
+	}
+}
+
+func TestFrom_DiscardsWithoutLogger(t *testing.T) {
+	l := From(context.Background())
+	if l.Enabled(context.Background(), slog.LevelError) {
+		t.Error("default logger enabled")
+	}
+	From(WithPhase(context.Background(), "collect")).Error("dropped")
+}
+
+func TestFile_RenamedOnClose(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "out")
+	f, err := Create(dir, "01JPAX1Z5C8K2M3N4P5Q6R7S8T")
+	if err != nil {
+		t.Fatal(err)
+	}
+	New(f, FormatText, slog.LevelInfo).Info("hello")
+	path, err := f.Close("host_20260301T120000Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "host_20260301T120000Z.log"); path != want {
+		t.Errorf("path = %s, want %s", path, want)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(data), "msg=hello") {
+		t.Errorf("log = %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "01JPAX1Z5C8K2M3N4P5Q6R7S8T.log")); !os.IsNotExist(err) {
+		t.Errorf("provisional log left behind: %v", err)
+	}
+}
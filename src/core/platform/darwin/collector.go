@@ -1,7 +1,11 @@
 package darwin
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"net"
 	"os"
 	"os/exec"
 	"os/user"
@@ -35,18 +39,16 @@ func (c *Collector) GetSystemInfo(ctx context.Context) (*types.SystemInfo, error
 		info.Hostname = "unknown"
 	}
 
-	// Get macOS version using sw_vers
-	if version, err := c.getSystemVersion(); err == nil {
-		info.OSVersion = version
-	} else {
-		info.OSVersion = "unknown"
-	}
-
-	// Get build number
-	if build, err := c.getBuildVersion(); err == nil {
-		info.OSBuild = build
-	} else {
-		info.OSBuild = "unknown"
+	// Get macOS version and build from SystemVersion.plist
+	info.OSVersion, info.OSBuild = "unknown", "unknown"
+	if data, err := readPlist(ctx, systemVersionPlist); err == nil {
+		keys := plistStrings(bytes.NewReader(data))
+		if v := keys["ProductVersion"]; v != "" {
+			info.OSVersion = v
+		}
+		if b := keys["ProductBuildVersion"]; b != "" {
+			info.OSBuild = b
+		}
 	}
 
 	// Get timezone
@@ -63,14 +65,14 @@ func (c *Collector) GetNetworkInfo(ctx context.Context) (*types.NetworkInfo, err
 		WiFiSSIDs:  []string{},
 	}
 
-	// Get network interfaces using ifconfig
+	// Get network interfaces from the OS (no ifconfig parsing)
 	interfaces, err := c.getNetworkInterfaces()
 	if err == nil {
 		info.Interfaces = interfaces
 	}
 
-	// Get WiFi SSIDs
-	ssids, err := c.getWiFiSSIDs()
+	// Get known WiFi SSIDs from the system preferences
+	ssids, err := c.getWiFiSSIDs(ctx)
 	if err == nil {
 		info.WiFiSSIDs = ssids
 	}
@@ -92,14 +94,17 @@ func (c *Collector) GetHardwareInfo(ctx context.Context) (*types.HardwareInfo, e
 		HardwareUUID: "unknown",
 	}
 
-	// Get hardware UUID using ioreg
-	if uuid, err := c.getHardwareUUID(); err == nil {
-		info.HardwareUUID = uuid
-	}
-
-	// Get serial number using ioreg
-	if serial, err := c.getSerialNumber(); err == nil {
-		info.SerialNumber = serial
+	// Get hardware UUID and serial number from system_profiler
+	out, err := exec.CommandContext(ctx, "system_profiler", "-json", "SPHardwareDataType").Output()
+	if err == nil {
+		if uuid, serial, err := parseHardwareJSON(out); err == nil {
+			if uuid != "" {
+				info.HardwareUUID = uuid
+			}
+			if serial != "" {
+				info.SerialNumber = serial
+			}
+		}
 	}
 
 	return info, nil
@@ -117,7 +122,7 @@ func (c *Collector) GetPIIInfo(ctx context.Context) (*types.PIIInfo, error) {
 	}
 
 	// Get local users using dscl
-	users, err := c.getLocalUsers()
+	users, err := c.getLocalUsers(ctx)
 	if err == nil {
 		info.Users = users
 		for _, u := range users {
@@ -138,158 +143,126 @@ func (c *Collector) GetPIIInfo(ctx context.Context) (*types.PIIInfo, error) {
 	sort.Strings(info.LoggedInUsers)
 	sort.Strings(info.HomeDirs)
 
-	return info, nil
+	return info, ctx.Err()
 }
 
 // Helper functions
 
-func (c *Collector) getSystemVersion() (string, error) {
-	cmd := exec.Command("sw_vers", "-productVersion")
-	output, err := cmd.Output()
-	if err != nil {
-		return "", err
-	}
-	return strings.TrimSpace(string(output)), nil
-}
+// systemVersionPlist records the macOS version and build
+const systemVersionPlist = "/System/Library/CoreServices/SystemVersion.plist"
 
-func (c *Collector) getBuildVersion() (string, error) {
-	cmd := exec.Command("sw_vers", "-buildVersion")
-	output, err := cmd.Output()
-	if err != nil {
-		return "", err
-	}
-	return strings.TrimSpace(string(output)), nil
-}
+// knownNetworksPlist lists saved Wi-Fi networks as "wifi.network.ssid.<SSID>"
+// keys (macOS 13+); airportPreferencesPlist holds them as SSIDString values
+// on older releases
+const (
+	knownNetworksPlist      = "/Library/Preferences/com.apple.wifi.known-networks.plist"
+	airportPreferencesPlist = "/Library/Preferences/SystemConfiguration/com.apple.airport.preferences.plist"
+)
 
+// getNetworkInterfaces lists non-loopback interfaces with their first IPv4
+// address and MAC address
 func (c *Collector) getNetworkInterfaces() ([]types.NetworkInterface, error) {
-	interfaces := []types.NetworkInterface{}
-
-	cmd := exec.Command("ifconfig")
-	output, err := cmd.Output()
+	ifaces, err := net.Interfaces()
 	if err != nil {
 		return nil, err
 	}
-
-	lines := strings.Split(string(output), "\n")
-	var currentIface *types.NetworkInterface
-
-	for _, line := range lines {
-		// New interface starts at column 0
-		if len(line) > 0 && line[0] != ' ' && line[0] != '\t' {
-			if strings.Contains(line, ":") {
-				parts := strings.Split(line, ":")
-				name := parts[0]
-				if name != "lo0" { // Skip loopback
-					if currentIface != nil {
-						interfaces = append(interfaces, *currentIface)
-					}
-					currentIface = &types.NetworkInterface{
-						Name:       name,
-						IPAddress:  "unknown",
-						MACAddress: "unknown",
-					}
-				}
-			}
-		} else if currentIface != nil {
-			// Parse interface properties
-			line = strings.TrimSpace(line)
-			if strings.HasPrefix(line, "inet ") {
-				fields := strings.Fields(line)
-				if len(fields) >= 2 {
-					currentIface.IPAddress = fields[1]
-				}
-			} else if strings.HasPrefix(line, "ether ") {
-				fields := strings.Fields(line)
-				if len(fields) >= 2 {
-					currentIface.MACAddress = fields[1]
-				}
-			}
+	interfaces := []types.NetworkInterface{}
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback != 0 {
+			continue
 		}
+		addrs, _ := iface.Addrs()
+		interfaces = append(interfaces, interfaceInfo(iface.Name, iface.HardwareAddr, addrs))
 	}
-
-	if currentIface != nil {
-		interfaces = append(interfaces, *currentIface)
-	}
-
 	return interfaces, nil
 }
 
-func (c *Collector) getWiFiSSIDs() ([]string, error) {
-	ssids := []string{}
-
-	// Get known WiFi networks using airport utility
-	cmd := exec.Command("/System/Library/PrivateFrameworks/Apple80211.framework/Versions/Current/Resources/airport", "-s")
-	output, err := cmd.Output()
-	if err != nil {
-		return ssids, nil // Best-effort, not fatal
+// interfaceInfo builds an interface record ("unknown" for missing values)
+func interfaceInfo(name string, mac net.HardwareAddr, addrs []net.Addr) types.NetworkInterface {
+	n := types.NetworkInterface{Name: name, IPAddress: "unknown", MACAddress: "unknown"}
+	if len(mac) > 0 {
+		n.MACAddress = mac.String()
 	}
-
-	lines := strings.Split(string(output), "\n")
-	for i, line := range lines {
-		if i == 0 {
-			continue // Skip header
-		}
-		fields := strings.Fields(line)
-		if len(fields) > 0 {
-			ssid := fields[0]
-			if ssid != "" {
-				ssids = append(ssids, ssid)
+	for _, addr := range addrs {
+		if ipnet, ok := addr.(*net.IPNet); ok {
+			if v4 := ipnet.IP.To4(); v4 != nil {
+				n.IPAddress = v4.String()
+				break
 			}
 		}
 	}
-
-	return ssids, nil
+	return n
 }
 
-func (c *Collector) getHardwareUUID() (string, error) {
-	cmd := exec.Command("ioreg", "-rd1", "-c", "IOPlatformExpertDevice")
-	output, err := cmd.Output()
-	if err != nil {
-		return "", err
+// getWiFiSSIDs returns the saved Wi-Fi network names (best-effort: the
+// preference files are readable by root only)
+func (c *Collector) getWiFiSSIDs(ctx context.Context) ([]string, error) {
+	if data, err := readPlist(ctx, knownNetworksPlist); err == nil {
+		return knownNetworkSSIDs(plistKeys(bytes.NewReader(data))), nil
 	}
-
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		if strings.Contains(line, "IOPlatformUUID") {
-			parts := strings.Split(line, "\"")
-			if len(parts) >= 4 {
-				return parts[3], nil
-			}
-		}
+	if data, err := readPlist(ctx, airportPreferencesPlist); err == nil {
+		return dedupe(plistStringsFor(bytes.NewReader(data), "SSIDString")), nil
 	}
-
-	return "", nil
+	return []string{}, nil
 }
 
-func (c *Collector) getSerialNumber() (string, error) {
-	cmd := exec.Command("ioreg", "-rd1", "-c", "IOPlatformExpertDevice")
-	output, err := cmd.Output()
-	if err != nil {
-		return "", err
+// knownNetworkSSIDs extracts SSIDs from known-networks plist keys
+func knownNetworkSSIDs(keys []string) []string {
+	var ssids []string
+	for _, k := range keys {
+		if ssid, ok := strings.CutPrefix(k, "wifi.network.ssid."); ok && ssid != "" {
+			ssids = append(ssids, ssid)
+		}
 	}
+	return dedupe(ssids)
+}
 
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		if strings.Contains(line, "IOPlatformSerialNumber") {
-			parts := strings.Split(line, "\"")
-			if len(parts) >= 4 {
-				return parts[3], nil
-			}
+// dedupe returns the distinct values of s in first-seen order
+func dedupe(s []string) []string {
+	out := []string{}
+	seen := make(map[string]bool, len(s))
+	for _, v := range s {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
 		}
 	}
+	return out
+}
 
-	return "", nil
+// parseHardwareJSON returns the platform UUID and serial number from
+// `system_profiler -json SPHardwareDataType`
+func parseHardwareJSON(data []byte) (uuid, serial string, err error) {
+	var out struct {
+		Hardware []struct {
+			UUID   string `json:"platform_UUID"`
+			Serial string `json:"serial_number"`
+		} `json:"SPHardwareDataType"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return "", "", err
+	}
+	if len(out.Hardware) == 0 {
+		return "", "", fmt.Errorf("system_profiler: no hardware entry")
+	}
+	return out.Hardware[0].UUID, out.Hardware[0].Serial, nil
 }
 
-func (c *Collector) getLocalUsers() ([]types.User, error) {
+func (c *Collector) getLocalUsers(ctx context.Context) ([]types.User, error) {
 	users := []types.User{}
 
-	cmd := exec.Command("dscl", ".", "-list", "/Users")
+	cmd := exec.CommandContext(ctx, "dscl", ".", "-list", "/Users")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, err
 	}
 
+	// Best-effort: admin group members may administer the Mac
+	admins := map[string]bool{}
+	if out, err := exec.CommandContext(ctx, "dscl", ".", "-read", "/Groups/admin", "GroupMembership").Output(); err == nil {
+		admins = parseGroupMembership(out)
+	}
+
 	lines := strings.Split(string(output), "\n")
 	for _, line := range lines {
 		username := strings.TrimSpace(line)
@@ -299,9 +272,25 @@ func (c *Collector) getLocalUsers() ([]types.User, error) {
 				Username: username,
 				FullName: username, // Can be enhanced with dscl query
 				UID:      "",       // Can be enhanced with id command
+				Admin:    admins[username],
 			})
 		}
 	}
 
 	return users, nil
 }
+
+// parseGroupMembership reads the members from dscl -read output
+// ("GroupMembership: root alice")
+// Complexity: O(|out|)
+func parseGroupMembership(out []byte) map[string]bool {
+	members := map[string]bool{}
+	_, list, ok := strings.Cut(string(out), "GroupMembership:")
+	if !ok {
+		return members
+	}
+	for _, name := range strings.Fields(list) {
+		members[name] = true
+	}
+	return members
+}
@@ -0,0 +1,44 @@
+package darwin
+
+import (
+	"net"
+	"testing"
+)
+
+// TestParseHardwareJSON verifies the UUID and serial are read from system_profiler output
+func TestParseHardwareJSON(t *testing.T) {
+	out := []byte(`{"SPHardwareDataType":[{"_name":"hardware_overview","machine_model":"Mac14,2","platform_UUID":"0A1B2C3D-4E5F-6071-8293-A4B5C6D7E8F9","serial_number":"C02XK0AAJGH5"}]}`)
+	uuid, serial, err := parseHardwareJSON(out)
+	if err != nil || uuid != "0A1B2C3D-4E5F-6071-8293-A4B5C6D7E8F9" || serial != "C02XK0AAJGH5" {
+		t.Errorf("parseHardwareJSON() = %q, %q, %v", uuid, serial, err)
+	}
+	if _, _, err := parseHardwareJSON([]byte(`{"SPHardwareDataType":[]}`)); err == nil {
+		t.Error("empty hardware list accepted")
+	}
+}
+
+// TestParseGroupMembership verifies admin group members are read from dscl output
+func TestParseGroupMembership(t *testing.T) {
+	members := parseGroupMembership([]byte("GroupMembership: root alice\n"))
+	if len(members) != 2 || !members["root"] || !members["alice"] {
+		t.Errorf("members = %v", members)
+	}
+	if members := parseGroupMembership([]byte("No such key: GroupMembership\n")); len(members) != 0 {
+		t.Errorf("missing key: members = %v", members)
+	}
+}
+
+// TestInterfaceInfo verifies the first IPv4 address wins and missing values are "unknown"
+func TestInterfaceInfo(t *testing.T) {
+	mac, _ := net.ParseMAC("a4:83:e7:01:02:03")
+	addrs := []net.Addr{
+		&net.IPNet{IP: net.ParseIP("fe80::1"), Mask: net.CIDRMask(64, 128)},
+		&net.IPNet{IP: net.ParseIP("192.168.1.20"), Mask: net.CIDRMask(24, 32)},
+	}
+	if n := interfaceInfo("en0", mac, addrs); n.IPAddress != "192.168.1.20" || n.MACAddress != "a4:83:e7:01:02:03" {
+		t.Errorf("en0 = %+v", n)
+	}
+	if n := interfaceInfo("utun0", nil, nil); n.IPAddress != "unknown" || n.MACAddress != "unknown" {
+		t.Errorf("utun0 = %+v", n)
+	}
+}
@@ -0,0 +1,92 @@
+package darwin
+
+import (
+	"context"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// GetDiskInfo lists mounted disks via df, with filesystem and FileVault
+// state from `diskutil info`
+// APFS system helper volumes under /System/Volumes (except Data) are skipped.
+// Complexity: O(v) where v = number of volumes
+func (c *Collector) GetDiskInfo(ctx context.Context) (*types.DiskInfo, error) {
+	out, err := exec.CommandContext(ctx, "df", "-P", "-k").Output()
+	if err != nil {
+		return nil, err
+	}
+	vols := parseDF(string(out))
+	for i := range vols {
+		vols[i].Encryption = types.EncryptionUnknown
+	}
+	for i := range vols {
+		info, err := exec.CommandContext(ctx, "diskutil", "info", vols[i].MountPoint).Output()
+		if err != nil {
+			if ctx.Err() != nil {
+				break // Timed out: the remaining volumes stay unknown
+			}
+			continue
+		}
+		vols[i].FileSystem, vols[i].Encryption = parseDiskutilInfo(string(info))
+	}
+	return &types.DiskInfo{Volumes: vols}, ctx.Err()
+}
+
+// parseDF parses `df -P -k` output, keeping /dev/disk devices only, sorted
+// by mount point
+// Complexity: O(|out| + v log v)
+func parseDF(out string) []types.Volume {
+	vols := []types.Volume{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 6 || !strings.HasPrefix(fields[0], "/dev/disk") {
+			continue // Header, devfs, map auto_home
+		}
+		mount := strings.Join(fields[5:], " ")
+		if strings.HasPrefix(mount, "/System/Volumes/") && mount != "/System/Volumes/Data" {
+			continue
+		}
+		total, err1 := strconv.ParseInt(fields[1], 10, 64)
+		free, err2 := strconv.ParseInt(fields[3], 10, 64)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		vols = append(vols, types.Volume{
+			MountPoint: mount,
+			Device:     fields[0],
+			TotalBytes: total * 1024,
+			FreeBytes:  free * 1024,
+		})
+	}
+	sort.Slice(vols, func(i, j int) bool { return vols[i].MountPoint < vols[j].MountPoint })
+	return vols
+}
+
+// parseDiskutilInfo returns the filesystem and FileVault state from
+// `diskutil info` output
+// Complexity: O(|out|)
+func parseDiskutilInfo(out string) (fs, encryption string) {
+	encryption = types.EncryptionUnknown
+	for _, line := range strings.Split(out, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "File System Personality":
+			fs = value
+		case "FileVault":
+			if strings.HasPrefix(value, "Yes") { // "Yes (Unlocked)"
+				encryption = types.EncryptionFileVault
+			} else {
+				encryption = types.EncryptionNone
+			}
+		}
+	}
+	return fs, encryption
+}
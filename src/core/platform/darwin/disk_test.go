@@ -0,0 +1,43 @@
+package darwin
+
+import (
+	"testing"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// TestParseDF verifies APFS helper volumes are skipped and sizes converted to bytes
+func TestParseDF(t *testing.T) {
+	out := "Filesystem     1024-blocks      Used Available Capacity  Mounted on\n" +
+		"/dev/disk3s1s1   482797652  10197032 256386524     4%    /\n" +
+		"devfs                  204       204         0   100%    /dev\n" +
+		"/dev/disk3s6     482797652   2097172 256386524     1%    /System/Volumes/VM\n" +
+		"/dev/disk3s5     482797652 212088924 256386524    46%    /System/Volumes/Data\n" +
+		"/dev/disk4s1      30274560     11296  30263264     1%    /Volumes/USB STICK\n"
+	vols := parseDF(out)
+	if len(vols) != 3 {
+		t.Fatalf("got %d volumes, want 3: %+v", len(vols), vols)
+	}
+	if v := vols[0]; v.MountPoint != "/" || v.TotalBytes != 482797652*1024 || v.FreeBytes != 256386524*1024 {
+		t.Errorf("vols[0] = %+v", v)
+	}
+	if v := vols[2]; v.MountPoint != "/Volumes/USB STICK" || v.Device != "/dev/disk4s1" {
+		t.Errorf("vols[2] = %+v", v)
+	}
+}
+
+// TestParseDiskutilInfo verifies the filesystem and FileVault fields
+func TestParseDiskutilInfo(t *testing.T) {
+	out := "   Device Identifier:         disk3s5\n" +
+		"   File System Personality:   APFS\n" +
+		"   FileVault:                 Yes (Unlocked)\n"
+	if fs, enc := parseDiskutilInfo(out); fs != "APFS" || enc != types.EncryptionFileVault {
+		t.Errorf("parseDiskutilInfo() = %q, %q", fs, enc)
+	}
+	if _, enc := parseDiskutilInfo("   FileVault:                 No\n"); enc != types.EncryptionNone {
+		t.Errorf("FileVault No = %q", enc)
+	}
+	if _, enc := parseDiskutilInfo("   Device Identifier: disk4s1\n"); enc != types.EncryptionUnknown {
+		t.Errorf("no FileVault line = %q", enc)
+	}
+}
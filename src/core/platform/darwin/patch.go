@@ -0,0 +1,88 @@
+package darwin
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// dataUpdatePrefixes name the background security-data updates (XProtect
+// signatures, MRT and Gatekeeper data) macOS installs almost daily; they do
+// not count as the last OS update
+var dataUpdatePrefixes = []string{"XProtect", "MRT", "Gatekeeper", "Background Task Management"}
+
+// GetPatchInfo lists installed updates from `softwareupdate --history` and
+// the pending count recorded by the last background update check
+// Nothing is downloaded; `softwareupdate -l` would contact Apple.
+// Complexity: O(u) where u = number of installed updates
+func (c *Collector) GetPatchInfo(ctx context.Context) (*types.PatchInfo, error) {
+	out, err := exec.CommandContext(ctx, "softwareupdate", "--history").Output()
+	if err != nil {
+		return nil, err
+	}
+	info := &types.PatchInfo{Updates: parseUpdateHistory(string(out))}
+	info.LastUpdate = lastOSUpdate(info.Updates)
+
+	out, err = exec.CommandContext(ctx, "defaults", "read", "/Library/Preferences/com.apple.SoftwareUpdate",
+		"LastRecommendedUpdatesAvailable").Output()
+	if err == nil {
+		if n, err := strconv.Atoi(strings.TrimSpace(string(out))); err == nil {
+			info.Pending = &n
+		}
+	}
+	return info, ctx.Err()
+}
+
+// parseUpdateHistory parses `softwareupdate --history`: a header naming the
+// Display Name, Version and Date columns, a dashed rule, then one update per
+// line ("MM/DD/YYYY, HH:MM:SS" dates), sorted newest first
+// Complexity: O(|out| + u log u)
+func parseUpdateHistory(out string) []types.Update {
+	updates := []types.Update{}
+	versionCol, dateCol := -1, -1
+	for _, line := range strings.Split(out, "\n") {
+		if versionCol < 0 {
+			versionCol, dateCol = strings.Index(line, "Version"), strings.Index(line, "Date")
+			if !strings.HasPrefix(line, "Display Name") || versionCol < 0 || dateCol < versionCol {
+				versionCol = -1
+			}
+			continue
+		}
+		if len(line) <= dateCol || strings.HasPrefix(line, "---") {
+			continue
+		}
+		u := types.Update{
+			ID:    strings.TrimSpace(line[:versionCol]),
+			Title: strings.TrimSpace(line[versionCol:dateCol]),
+		}
+		if t, err := time.Parse("01/02/2006, 15:04:05", strings.TrimSpace(line[dateCol:])); err == nil {
+			u.InstalledOn = t.Format("2006-01-02")
+		}
+		if u.ID != "" {
+			updates = append(updates, u)
+		}
+	}
+	types.SortUpdates(updates)
+	return updates
+}
+
+// lastOSUpdate returns the install date of the newest update that is not a
+// background security-data update
+// Complexity: O(u)
+func lastOSUpdate(updates []types.Update) string {
+	last := ""
+	for _, u := range updates {
+		data := false
+		for _, prefix := range dataUpdatePrefixes {
+			data = data || strings.HasPrefix(u.ID, prefix)
+		}
+		if !data && u.InstalledOn > last {
+			last = u.InstalledOn
+		}
+	}
+	return last
+}
@@ -0,0 +1,33 @@
+package darwin
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// TestParseUpdateHistory verifies column parsing, date conversion and that
+// security-data updates do not count as the last OS update
+func TestParseUpdateHistory(t *testing.T) {
+	out := "Display Name                                       Version    Date                  \n" +
+		"------------                                       -------    ----                  \n" +
+		"macOS Sonoma 14.4                                  14.4       03/08/2024, 09:30:12  \n" +
+		"XProtectPlistConfigData                            2193       04/09/2024, 09:12:45  \n" +
+		"macOS Sonoma 14.4.1                                14.4.1     03/26/2024, 22:01:50  \n"
+	got := parseUpdateHistory(out)
+	want := []types.Update{
+		{ID: "XProtectPlistConfigData", Title: "2193", InstalledOn: "2024-04-09"},
+		{ID: "macOS Sonoma 14.4.1", Title: "14.4.1", InstalledOn: "2024-03-26"},
+		{ID: "macOS Sonoma 14.4", Title: "14.4", InstalledOn: "2024-03-08"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseUpdateHistory() = %+v, want %+v", got, want)
+	}
+	if last := lastOSUpdate(got); last != "2024-03-26" {
+		t.Errorf("lastOSUpdate() = %q, want 2024-03-26", last)
+	}
+	if got := parseUpdateHistory("No updates found\n"); len(got) != 0 {
+		t.Errorf("parseUpdateHistory(no header) = %+v", got)
+	}
+}
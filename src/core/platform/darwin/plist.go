@@ -0,0 +1,126 @@
+package darwin
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// readPlist returns a property list as XML, converting binary plists with
+// plutil
+// Complexity: O(|plist|)
+func readPlist(ctx context.Context, path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(data, []byte("bplist")) {
+		return exec.CommandContext(ctx, "plutil", "-convert", "xml1", "-o", "-", path).Output()
+	}
+	return data, nil
+}
+
+// plistStrings returns the string values of an XML plist's top-level dict
+// Complexity: O(|plist|)
+func plistStrings(r io.Reader) map[string]string {
+	values := map[string]string{}
+	dec := xml.NewDecoder(r)
+	depth, key := 0, ""
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return values
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch {
+			case t.Name.Local == "dict":
+				depth++
+			case depth == 1 && t.Name.Local == "key":
+				var k string
+				if dec.DecodeElement(&k, &t) == nil {
+					key = k
+				}
+			case depth == 1 && t.Name.Local == "string" && key != "":
+				var v string
+				if dec.DecodeElement(&v, &t) == nil {
+					values[key] = strings.TrimSpace(v)
+				}
+				key = ""
+			default:
+				if depth == 1 {
+					key = "" // Non-string value
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "dict" {
+				depth--
+			}
+		}
+	}
+}
+
+// plistKeys returns the keys of an XML plist's top-level dict, in order
+// Complexity: O(|plist|)
+func plistKeys(r io.Reader) []string {
+	var keys []string
+	dec := xml.NewDecoder(r)
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return keys
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "dict" {
+				depth++
+			} else if depth == 1 && t.Name.Local == "key" {
+				var k string
+				if dec.DecodeElement(&k, &t) == nil {
+					keys = append(keys, k)
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "dict" {
+				depth--
+			}
+		}
+	}
+}
+
+// plistStringsFor returns every string value stored under key, at any depth
+// Complexity: O(|plist|)
+func plistStringsFor(r io.Reader, key string) []string {
+	var values []string
+	dec := xml.NewDecoder(r)
+	last := ""
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return values
+		}
+		t, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch t.Name.Local {
+		case "key":
+			if dec.DecodeElement(&last, &t) != nil {
+				last = ""
+			}
+		case "string":
+			var v string
+			if dec.DecodeElement(&v, &t) == nil && last == key {
+				values = append(values, v)
+			}
+			last = ""
+		default:
+			last = ""
+		}
+	}
+}
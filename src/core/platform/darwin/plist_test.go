@@ -0,0 +1,86 @@
+package darwin
+
+import (
+	"strings"
+	"testing"
+)
+
+const infoPlist = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>CFBundleDocumentTypes</key>
+	<array>
+		<dict>
+			<key>CFBundleName</key>
+			<string>Nested</string>
+		</dict>
+	</array>
+	<key>CFBundleName</key>
+	<string>Firefox</string>
+	<key>LSRequiresNativeExecution</key>
+	<true/>
+	<key>CFBundleShortVersionString</key>
+	<string>128.0.3</string>
+</dict>
+</plist>`
+
+// TestPlistStrings verifies only top-level string values are read
+func TestPlistStrings(t *testing.T) {
+	keys := plistStrings(strings.NewReader(infoPlist))
+	if keys["CFBundleName"] != "Firefox" || keys["CFBundleShortVersionString"] != "128.0.3" {
+		t.Errorf("keys = %v", keys)
+	}
+}
+
+const knownNetworks = `<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<dict>
+	<key>wifi.network.ssid.HomeNet</key>
+	<dict>
+		<key>SSID</key>
+		<data>SG9tZU5ldA==</data>
+		<key>AddedAt</key>
+		<date>2024-01-15T10:00:00Z</date>
+	</dict>
+	<key>wifi.network.ssid.Cafe Guest</key>
+	<dict>
+		<key>SupportedSecurityTypes</key>
+		<string>Open</string>
+	</dict>
+</dict>
+</plist>`
+
+const airportPreferences = `<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<dict>
+	<key>KnownNetworks</key>
+	<dict>
+		<key>wifi.ssid.&lt;486f6d654e6574&gt;</key>
+		<dict>
+			<key>SSIDString</key>
+			<string>HomeNet</string>
+			<key>SecurityType</key>
+			<string>WPA2 Personal</string>
+		</dict>
+	</dict>
+	<key>Version</key>
+	<integer>2200</integer>
+</dict>
+</plist>`
+
+// TestKnownNetworkSSIDs verifies SSIDs come from top-level keys only
+func TestKnownNetworkSSIDs(t *testing.T) {
+	ssids := knownNetworkSSIDs(plistKeys(strings.NewReader(knownNetworks)))
+	if len(ssids) != 2 || ssids[0] != "HomeNet" || ssids[1] != "Cafe Guest" {
+		t.Errorf("ssids = %q", ssids)
+	}
+}
+
+// TestPlistStringsFor verifies nested values are found by key
+func TestPlistStringsFor(t *testing.T) {
+	ssids := plistStringsFor(strings.NewReader(airportPreferences), "SSIDString")
+	if len(ssids) != 1 || ssids[0] != "HomeNet" {
+		t.Errorf("ssids = %q", ssids)
+	}
+}
@@ -0,0 +1,81 @@
+package darwin
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// GetListeningPorts lists listening TCP and bound UDP sockets via lsof
+// Without root, lsof only sees the current user's processes; sockets of
+// other users are then missing rather than ownerless.
+// Complexity: O(s) where s = number of sockets
+func (c *Collector) GetListeningPorts(ctx context.Context) (*types.PortInfo, error) {
+	out, err := exec.CommandContext(ctx, "lsof", "-nP", "-iTCP", "-sTCP:LISTEN", "-iUDP", "-FpcPnt").Output()
+	var exitErr *exec.ExitError
+	if err != nil && !(errors.As(err, &exitErr) && len(out) == 0 && ctx.Err() == nil) {
+		return nil, err // lsof exits 1 with no output when nothing matches
+	}
+	return &types.PortInfo{Ports: parseLsof(string(out))}, nil
+}
+
+// parseLsof parses `lsof -F pcPnt` output, sorted
+// Each process starts with p (PID) and c (command) lines; each of its files
+// follows as f, t (IPv4/IPv6), P (TCP/UDP) and n (address) lines. Connected
+// UDP sockets (n holds "->") are skipped.
+// Complexity: O(|out| + s log s)
+func parseLsof(out string) []types.ListeningPort {
+	ports := []types.ListeningPort{}
+	seen := map[types.ListeningPort]bool{}
+	var pid int
+	var command, family, protocol string
+	for _, line := range strings.Split(out, "\n") {
+		if line == "" {
+			continue
+		}
+		value := line[1:]
+		switch line[0] {
+		case 'p':
+			pid, _ = strconv.Atoi(value)
+			command = ""
+		case 'c':
+			command = value
+		case 'f':
+			family, protocol = "", ""
+		case 't':
+			family = value
+		case 'P':
+			protocol = strings.ToLower(value)
+		case 'n':
+			if strings.Contains(value, "->") || (protocol != "tcp" && protocol != "udp") {
+				continue
+			}
+			host, portField, err := net.SplitHostPort(value)
+			if err != nil {
+				continue
+			}
+			port, err := strconv.Atoi(portField)
+			if err != nil {
+				continue
+			}
+			if host == "*" {
+				host = "0.0.0.0"
+				if family == "IPv6" {
+					host = "::"
+				}
+			}
+			p := types.ListeningPort{Protocol: protocol, Address: host, Port: port, PID: pid, Process: command}
+			if !seen[p] { // One socket shared by several descriptors
+				seen[p] = true
+				ports = append(ports, p)
+			}
+		}
+	}
+	types.SortPorts(ports)
+	return ports
+}
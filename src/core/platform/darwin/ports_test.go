@@ -0,0 +1,37 @@
+package darwin
+
+import "testing"
+
+// TestParseLsof verifies wildcard families, connected UDP and ordering
+func TestParseLsof(t *testing.T) {
+	out := "p1\nclaunchd\n" +
+		"f9\ntIPv4\nPUDP\nn*:137\n" +
+		"p412\ncsshd\n" +
+		"f3\ntIPv4\nPTCP\nn*:22\n" +
+		"f4\ntIPv6\nPTCP\nn*:22\n" +
+		"f5\ntIPv6\nPTCP\nn[::1]:631\n" +
+		"f6\ntIPv4\nPTCP\nn*:22\n" +
+		"p900\ncmDNSResponder\n" +
+		"f12\ntIPv4\nPUDP\nn192.168.1.5:60012->1.1.1.1:53\n"
+	ports := parseLsof(out)
+	if len(ports) != 4 {
+		t.Fatalf("got %d ports, want 4: %+v", len(ports), ports)
+	}
+	want := []struct {
+		protocol, address string
+		port, pid         int
+	}{
+		{"tcp", "0.0.0.0", 22, 412},
+		{"tcp", "::", 22, 412},
+		{"tcp", "::1", 631, 412},
+		{"udp", "0.0.0.0", 137, 1},
+	}
+	for i, w := range want {
+		if p := ports[i]; p.Protocol != w.protocol || p.Address != w.address || p.Port != w.port || p.PID != w.pid {
+			t.Errorf("ports[%d] = %+v, want %+v", i, p, w)
+		}
+	}
+	if ports[3].Process != "launchd" {
+		t.Errorf("ports[3].Process = %q", ports[3].Process)
+	}
+}
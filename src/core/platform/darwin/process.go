@@ -0,0 +1,54 @@
+package darwin
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// GetProcessInfo lists running processes via ps
+// ps reports the executable path as the command for most processes; bare
+// names (e.g., kernel_task) are kept as the name with an empty path.
+// Complexity: O(p) where p = number of processes
+func (c *Collector) GetProcessInfo(ctx context.Context) (*types.ProcessInfo, error) {
+	out, err := exec.CommandContext(ctx, "ps", "-axww", "-o", "pid=,user=,comm=").Output()
+	if err != nil {
+		return nil, err
+	}
+	return &types.ProcessInfo{Processes: parsePS(string(out))}, nil
+}
+
+// parsePS parses `ps -o pid=,user=,comm=` lines, sorted by PID
+// The command is the rest of the line, so paths with spaces survive.
+// Complexity: O(|out| + p log p)
+func parsePS(out string) []types.Process {
+	procs := []types.Process{}
+	for _, line := range strings.Split(out, "\n") {
+		pidField, rest, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok {
+			continue
+		}
+		pid, err := strconv.Atoi(pidField)
+		if err != nil {
+			continue
+		}
+		user, comm, ok := strings.Cut(strings.TrimSpace(rest), " ")
+		comm = strings.TrimSpace(comm)
+		if !ok || comm == "" {
+			continue
+		}
+
+		proc := types.Process{PID: pid, Name: filepath.Base(comm), User: user}
+		if strings.HasPrefix(comm, "/") {
+			proc.Path = comm
+		}
+		procs = append(procs, proc)
+	}
+	sort.Slice(procs, func(i, j int) bool { return procs[i].PID < procs[j].PID })
+	return procs
+}
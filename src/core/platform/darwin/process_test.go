@@ -0,0 +1,21 @@
+package darwin
+
+import "testing"
+
+// TestParsePS verifies paths with spaces, bare names and PID ordering
+func TestParsePS(t *testing.T) {
+	out := "  412 alice    /Applications/Google Chrome.app/Contents/MacOS/Google Chrome\n" +
+		"    1 root     /sbin/launchd\n" +
+		"    0 root     kernel_task\n" +
+		"garbage\n"
+	procs := parsePS(out)
+	if len(procs) != 3 {
+		t.Fatalf("got %d processes, want 3: %+v", len(procs), procs)
+	}
+	if p := procs[0]; p.PID != 0 || p.Name != "kernel_task" || p.Path != "" || p.User != "root" {
+		t.Errorf("procs[0] = %+v", p)
+	}
+	if p := procs[2]; p.PID != 412 || p.Name != "Google Chrome" || p.Path != "/Applications/Google Chrome.app/Contents/MacOS/Google Chrome" || p.User != "alice" {
+		t.Errorf("procs[2] = %+v", p)
+	}
+}
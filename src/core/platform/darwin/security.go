@@ -0,0 +1,80 @@
+package darwin
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// securityAgent is an antivirus or EDR product recognized by its bundle
+// or its running daemon
+type securityAgent struct {
+	name   string
+	kind   string
+	bundle string // .app or .bundle whose Info.plist carries the version
+	daemon string // Process name while protection runs ("" when always on)
+}
+
+// securityAgents are the products GetSecurityProducts looks for
+// XProtect is built into macOS and cannot be turned off.
+var securityAgents = []securityAgent{
+	{"CrowdStrike Falcon", types.ProductEDR, "/Applications/Falcon.app", "com.crowdstrike.falcon.Agent"},
+	{"Jamf Protect", types.ProductEDR, "/Applications/JamfProtect.app", "JamfProtect"},
+	{"Malwarebytes", types.ProductAntivirus, "/Applications/Malwarebytes.app", "RTProtectionDaemon"},
+	{"Microsoft Defender for Endpoint", types.ProductEDR, "/Applications/Microsoft Defender.app", "wdavdaemon"},
+	{"SentinelOne", types.ProductEDR, "/Applications/SentinelOne/SentinelOne Extensions.app", "sentineld"},
+	{"Sophos Endpoint", types.ProductAntivirus, "/Applications/Sophos/Sophos Endpoint.app", "SophosScanD"},
+	{"XProtect", types.ProductAntivirus, "/Library/Apple/System/Library/CoreServices/XProtect.bundle", ""},
+}
+
+// GetSecurityProducts detects the antivirus and EDR agents in
+// securityAgents by bundle and running daemon
+// Real-time protection is "on" while an agent's daemon runs, "off" when it
+// is installed but stopped and "unknown" when ps fails.
+// Complexity: O(k + p) where k = known agents, p = number of processes
+func (c *Collector) GetSecurityProducts(ctx context.Context) (*types.SecurityInfo, error) {
+	var running map[string]bool
+	if out, err := exec.CommandContext(ctx, "ps", "-axww", "-o", "pid=,user=,comm=").Output(); err == nil {
+		running = map[string]bool{}
+		for _, p := range parsePS(string(out)) {
+			running[p.Name] = true
+		}
+	} else if ctx.Err() != nil {
+		return nil, err
+	}
+	return &types.SecurityInfo{Products: detectAgents(ctx, securityAgents, "/", running)}, ctx.Err()
+}
+
+// detectAgents returns the agents installed below root or running, sorted
+// by name
+// running holds the process names (nil when unreadable).
+// Complexity: O(k)
+func detectAgents(ctx context.Context, agents []securityAgent, root string, running map[string]bool) []types.SecurityProduct {
+	products := []types.SecurityProduct{}
+	for _, a := range agents {
+		bundle := filepath.Join(root, a.bundle)
+		_, err := os.Stat(bundle)
+		if err != nil && (a.daemon == "" || !running[a.daemon]) {
+			continue
+		}
+
+		p := types.SecurityProduct{Name: a.name, Kind: a.kind, RealTime: types.RealTimeUnknown}
+		switch {
+		case a.daemon == "":
+			p.RealTime = types.RealTimeOn
+		case running != nil && running[a.daemon]:
+			p.RealTime = types.RealTimeOn
+		case running != nil:
+			p.RealTime = types.RealTimeOff
+		}
+		if app, ok := readApp(ctx, bundle); ok {
+			p.Version = app.Version
+		}
+		products = append(products, p)
+	}
+	types.SortProducts(products)
+	return products
+}
@@ -0,0 +1,43 @@
+package darwin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// TestDetectAgents verifies bundles and daemons are found, with versions
+// from Info.plist and XProtect always on
+func TestDetectAgents(t *testing.T) {
+	root := t.TempDir()
+	for _, bundle := range []string{"/Applications/Malwarebytes.app", "/Library/Apple/System/Library/CoreServices/XProtect.bundle"} {
+		dir := filepath.Join(root, bundle, "Contents")
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "Info.plist"), []byte(infoPlist), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Malwarebytes installed and stopped, Falcon's system extension running
+	running := map[string]bool{"com.crowdstrike.falcon.Agent": true, "launchd": true}
+	got := detectAgents(context.Background(), securityAgents, root, running)
+	want := []types.SecurityProduct{
+		{Name: "CrowdStrike Falcon", Kind: types.ProductEDR, RealTime: types.RealTimeOn},
+		{Name: "Malwarebytes", Kind: types.ProductAntivirus, Version: "128.0.3", RealTime: types.RealTimeOff},
+		{Name: "XProtect", Kind: types.ProductAntivirus, Version: "128.0.3", RealTime: types.RealTimeOn},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("detectAgents() = %+v, want %+v", got, want)
+	}
+
+	got = detectAgents(context.Background(), securityAgents, root, nil)
+	if len(got) != 2 || got[0].RealTime != types.RealTimeUnknown || got[1].RealTime != types.RealTimeOn {
+		t.Errorf("detectAgents(no processes) = %+v", got)
+	}
+}
@@ -0,0 +1,112 @@
+package darwin
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// applicationDirs are searched for .app bundles (one level of subfolders,
+// e.g. /Applications/Utilities)
+var applicationDirs = []string{"/Applications", "/Applications/Utilities"}
+
+// GetSoftwareInventory lists .app bundles and installer packages (pkgutil)
+// Apple's own packages (com.apple.*) are OS components and are skipped. On
+// timeout the packages read so far are returned with ctx's error.
+// Complexity: O(a + p) where a = applications, p = packages
+func (c *Collector) GetSoftwareInventory(ctx context.Context) (*types.SoftwareInfo, error) {
+	info := &types.SoftwareInfo{Packages: []types.Software{}}
+
+	for _, dir := range applicationDirs {
+		apps, _ := filepath.Glob(filepath.Join(dir, "*.app"))
+		for _, app := range apps {
+			if ctx.Err() != nil {
+				break
+			}
+			if pkg, ok := readApp(ctx, app); ok {
+				info.Packages = append(info.Packages, pkg)
+			}
+		}
+	}
+
+	out, err := exec.CommandContext(ctx, "pkgutil", "--pkgs").Output()
+	if err == nil {
+		scanner := bufio.NewScanner(bytes.NewReader(out))
+		for scanner.Scan() {
+			id := strings.TrimSpace(scanner.Text())
+			if id == "" || strings.HasPrefix(id, "com.apple.") {
+				continue
+			}
+			pkgInfo, err := exec.CommandContext(ctx, "pkgutil", "--pkg-info", id).Output()
+			if err != nil {
+				if ctx.Err() != nil {
+					break
+				}
+				continue
+			}
+			info.Packages = append(info.Packages, parsePkgInfo(string(pkgInfo)))
+		}
+	}
+	sort.Slice(info.Packages, func(i, j int) bool {
+		a, b := info.Packages[i], info.Packages[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Version < b.Version
+	})
+	return info, ctx.Err()
+}
+
+// readApp reads name and version from an .app bundle's Info.plist
+// The install date is the bundle's modification time.
+func readApp(ctx context.Context, app string) (types.Software, bool) {
+	data, err := readPlist(ctx, filepath.Join(app, "Contents", "Info.plist"))
+	if err != nil {
+		return types.Software{}, false
+	}
+	keys := plistStrings(bytes.NewReader(data))
+
+	pkg := types.Software{Name: keys["CFBundleName"], Version: keys["CFBundleShortVersionString"], Source: "app"}
+	if pkg.Name == "" {
+		pkg.Name = strings.TrimSuffix(filepath.Base(app), ".app")
+	}
+	if pkg.Version == "" {
+		pkg.Version = keys["CFBundleVersion"]
+	}
+	if st, err := os.Stat(app); err == nil {
+		pkg.InstallDate = st.ModTime().UTC().Format("2006-01-02")
+	}
+	return pkg, true
+}
+
+// parsePkgInfo parses `pkgutil --pkg-info` output ("key: value" lines)
+// Complexity: O(|out|)
+func parsePkgInfo(out string) types.Software {
+	pkg := types.Software{Source: "pkgutil"}
+	for _, line := range strings.Split(out, "\n") {
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "package-id":
+			pkg.Name = strings.TrimSpace(value)
+		case "version":
+			pkg.Version = strings.TrimSpace(value)
+		case "install-time":
+			if secs, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64); err == nil && secs > 0 {
+				pkg.InstallDate = time.Unix(secs, 0).UTC().Format("2006-01-02")
+			}
+		}
+	}
+	return pkg
+}
@@ -0,0 +1,11 @@
+package darwin
+
+import "testing"
+
+// TestParsePkgInfo verifies pkgutil output is parsed
+func TestParsePkgInfo(t *testing.T) {
+	pkg := parsePkgInfo("package-id: org.python.Python.PythonFramework-3.12\nversion: 3.12.4\nvolume: /\nlocation: \ninstall-time: 1712000000\n")
+	if pkg.Name != "org.python.Python.PythonFramework-3.12" || pkg.Version != "3.12.4" || pkg.InstallDate != "2024-04-01" || pkg.Source != "pkgutil" {
+		t.Errorf("pkg = %+v", pkg)
+	}
+}
@@ -0,0 +1,126 @@
+package platform
+
+import (
+	"context"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// FakeCollector returns fixed, synthetic facts without touching the host
+// Used by benchmarks and tests to measure the pipeline independent of the OS.
+type FakeCollector struct{}
+
+// GetSystemInfo returns a fixed system profile
+// Complexity: O(1)
+func (FakeCollector) GetSystemInfo(ctx context.Context) (*types.SystemInfo, error) {
+	return &types.SystemInfo{
+		OSName:    "Linux",
+		OSVersion: "6.8.0",
+		OSBuild:   "Ubuntu 24.04 LTS",
+		Timezone:  "UTC",
+		Hostname:  "bench-host",
+	}, ctx.Err()
+}
+
+// GetNetworkInfo returns two fixed interfaces
+// Complexity: O(1)
+func (FakeCollector) GetNetworkInfo(ctx context.Context) (*types.NetworkInfo, error) {
+	return &types.NetworkInfo{
+		Interfaces: []types.NetworkInterface{
+			{Name: "eth0", IPAddress: "192.0.2.10", MACAddress: "02:00:00:00:00:01"},
+			{Name: "wlan0", IPAddress: "198.51.100.20", MACAddress: "02:00:00:00:00:02"},
+		},
+		WiFiSSIDs: []string{"bench-net"},
+	}, ctx.Err()
+}
+
+// GetHardwareInfo returns fixed identifiers
+// Complexity: O(1)
+func (FakeCollector) GetHardwareInfo(ctx context.Context) (*types.HardwareInfo, error) {
+	return &types.HardwareInfo{
+		SerialNumber: "BENCH-0001",
+		HardwareUUID: "00000000-0000-4000-8000-000000000001",
+	}, ctx.Err()
+}
+
+// GetPIIInfo returns one synthetic user
+// Complexity: O(1)
+func (FakeCollector) GetPIIInfo(ctx context.Context) (*types.PIIInfo, error) {
+	return &types.PIIInfo{
+		Users:          []types.User{{Username: "bench", FullName: "Bench User", UID: "1000"}},
+		LoggedInUsers:  []string{"bench"},
+		HomeDirs:       []string{"/home/bench"},
+		RecentProfiles: []types.UserProfile{{Username: "bench", LastLogon: "2025-01-01T00:00:00Z"}},
+		PrimaryEmail:   "bench@example.com",
+	}, ctx.Err()
+}
+
+// GetSoftwareInventory returns two fixed packages
+// Complexity: O(1)
+func (FakeCollector) GetSoftwareInventory(ctx context.Context) (*types.SoftwareInfo, error) {
+	return &types.SoftwareInfo{
+		Packages: []types.Software{
+			{Name: "bench-agent", Version: "1.0.0", Publisher: "MiniBeast", InstallDate: "2025-01-01", Source: "dpkg"},
+			{Name: "openssl", Version: "3.0.13-0ubuntu3", Publisher: "Ubuntu Developers", Source: "dpkg"},
+		},
+	}, ctx.Err()
+}
+
+// GetProcessInfo returns three fixed processes
+// Complexity: O(1)
+func (FakeCollector) GetProcessInfo(ctx context.Context) (*types.ProcessInfo, error) {
+	return &types.ProcessInfo{
+		Processes: []types.Process{
+			{PID: 1, Name: "systemd", Path: "/usr/lib/systemd/systemd", User: "root"},
+			{PID: 812, Name: "sshd", Path: "/usr/sbin/sshd", User: "root"},
+			{PID: 4242, Name: "bash", Path: "/usr/bin/bash", User: "bench"},
+		},
+	}, ctx.Err()
+}
+
+// GetListeningPorts returns three fixed sockets
+// Complexity: O(1)
+func (FakeCollector) GetListeningPorts(ctx context.Context) (*types.PortInfo, error) {
+	return &types.PortInfo{
+		Ports: []types.ListeningPort{
+			{Protocol: "tcp", Address: "0.0.0.0", Port: 22, PID: 812, Process: "sshd"},
+			{Protocol: "tcp", Address: "127.0.0.1", Port: 631},
+			{Protocol: "udp", Address: "0.0.0.0", Port: 68, PID: 1, Process: "systemd"},
+		},
+	}, ctx.Err()
+}
+
+// GetSecurityProducts returns one fixed antivirus
+// Complexity: O(1)
+func (FakeCollector) GetSecurityProducts(ctx context.Context) (*types.SecurityInfo, error) {
+	return &types.SecurityInfo{
+		Products: []types.SecurityProduct{
+			{Name: "ClamAV", Kind: types.ProductAntivirus, Version: "1.0.7", RealTime: types.RealTimeOn},
+		},
+	}, ctx.Err()
+}
+
+// GetPatchInfo returns two fixed updates and two pending
+// Complexity: O(1)
+func (FakeCollector) GetPatchInfo(ctx context.Context) (*types.PatchInfo, error) {
+	pending := 2
+	return &types.PatchInfo{
+		Updates: []types.Update{
+			{ID: "KB5034441", Title: "Security Update", InstalledOn: "2026-02-14"},
+			{ID: "KB5034123", Title: "Update", InstalledOn: "2026-01-10"},
+		},
+		LastUpdate: "2026-02-14",
+		Pending:    &pending,
+	}, ctx.Err()
+}
+
+// GetDiskInfo returns two fixed volumes
+// Complexity: O(1)
+func (FakeCollector) GetDiskInfo(ctx context.Context) (*types.DiskInfo, error) {
+	return &types.DiskInfo{
+		Volumes: []types.Volume{
+			{MountPoint: "/", Device: "/dev/mapper/bench-root", FileSystem: "ext4", TotalBytes: 512110190592, FreeBytes: 201326592000, Encryption: types.EncryptionLUKS},
+			{MountPoint: "/boot", Device: "/dev/nvme0n1p2", FileSystem: "ext4", TotalBytes: 1020067840, FreeBytes: 743440384, Encryption: types.EncryptionNone},
+		},
+	}, ctx.Err()
+}
@@ -8,6 +8,9 @@ import (
 
 // Collector defines the platform-specific data collection interface
 // Mathematical contract: All implementations must satisfy these operations
+// External commands run under the ctx given, so a deadline kills them. A
+// method cut short by its deadline may return what it collected so far
+// together with ctx's error.
 type Collector interface {
 	// GetSystemInfo retrieves OS name, version, build, timezone
 	// Complexity: O(1) - direct system calls
@@ -28,6 +31,38 @@ type Collector interface {
 	// Complexity: O(u) where u = number of users
 	// Timeout: Must respect context deadline
 	GetPIIInfo(ctx context.Context) (*types.PIIInfo, error)
+
+	// GetSoftwareInventory retrieves installed applications and packages
+	// Complexity: O(p) where p = number of installed packages
+	// Timeout: Must respect context deadline
+	GetSoftwareInventory(ctx context.Context) (*types.SoftwareInfo, error)
+
+	// GetProcessInfo retrieves running processes (PID, name, path, owner)
+	// Complexity: O(p) where p = number of processes
+	// Timeout: Must respect context deadline
+	GetProcessInfo(ctx context.Context) (*types.ProcessInfo, error)
+
+	// GetListeningPorts retrieves listening TCP and UDP sockets with their owners
+	// Complexity: O(s) where s = number of sockets
+	// Timeout: Must respect context deadline
+	GetListeningPorts(ctx context.Context) (*types.PortInfo, error)
+
+	// GetSecurityProducts retrieves installed antivirus and EDR products with
+	// their real-time protection state
+	// Complexity: O(k + p) where k = known products, p = number of processes
+	// Timeout: Must respect context deadline
+	GetSecurityProducts(ctx context.Context) (*types.SecurityInfo, error)
+
+	// GetPatchInfo retrieves installed OS updates, the last update date and
+	// the number of pending updates
+	// Complexity: O(u) where u = number of installed updates
+	// Timeout: Must respect context deadline
+	GetPatchInfo(ctx context.Context) (*types.PatchInfo, error)
+
+	// GetDiskInfo retrieves mounted volumes, capacity and encryption state
+	// Complexity: O(v) where v = number of volumes
+	// Timeout: Must respect context deadline
+	GetDiskInfo(ctx context.Context) (*types.DiskInfo, error)
 }
 
 // New creates a platform-specific collector for the current OS
@@ -4,10 +4,12 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"os/user"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -63,6 +65,7 @@ func (c *Collector) GetSystemInfo(ctx context.Context) (*types.SystemInfo, error
 }
 
 // GetNetworkInfo retrieves Linux network configuration
+// On timeout the interfaces read so far are returned with ctx's error.
 // Complexity: O(n) where n = number of network interfaces
 func (c *Collector) GetNetworkInfo(ctx context.Context) (*types.NetworkInfo, error) {
 	info := &types.NetworkInfo{
@@ -70,9 +73,9 @@ func (c *Collector) GetNetworkInfo(ctx context.Context) (*types.NetworkInfo, err
 		WiFiSSIDs:  []string{},
 	}
 
-	// Parse /proc/net/dev for interfaces
-	interfaces, err := c.getNetworkInterfaces()
-	if err == nil {
+	// Read /sys/class/net for interfaces
+	interfaces, err := c.getNetworkInterfaces(ctx)
+	if interfaces != nil {
 		info.Interfaces = interfaces
 	}
 
@@ -88,7 +91,7 @@ func (c *Collector) GetNetworkInfo(ctx context.Context) (*types.NetworkInfo, err
 	})
 	sort.Strings(info.WiFiSSIDs)
 
-	return info, nil
+	return info, ctx.Err()
 }
 
 // GetHardwareInfo retrieves Linux hardware identifiers
@@ -113,7 +116,9 @@ func (c *Collector) GetHardwareInfo(ctx context.Context) (*types.HardwareInfo, e
 }
 
 // GetPIIInfo retrieves Linux user information
-// Complexity: O(u) where u = number of users
+// Sessions come from utmp and last logons from wtmp; on timeout the facts
+// read so far are returned with ctx's error.
+// Complexity: O(u + w) where u = number of users, w = wtmp records
 func (c *Collector) GetPIIInfo(ctx context.Context) (*types.PIIInfo, error) {
 	info := &types.PIIInfo{
 		Users:          []types.User{},
@@ -135,11 +140,16 @@ func (c *Collector) GetPIIInfo(ctx context.Context) (*types.PIIInfo, error) {
 	}
 
 	// Get currently logged-in users
-	loggedIn, err := c.getLoggedInUsers()
+	loggedIn, err := c.getLoggedInUsers(ctx)
 	if err == nil {
 		info.LoggedInUsers = loggedIn
 	}
 
+	// Get each user's last logon (best-effort: wtmp may be absent or root-only)
+	if logins, err := readLoginFile(ctx, wtmpPath); err == nil || ctx.Err() != nil {
+		info.RecentProfiles = lastLogons(logins)
+	}
+
 	// Sort for determinism
 	sort.Slice(info.Users, func(i, j int) bool {
 		return info.Users[i].Username < info.Users[j].Username
@@ -147,7 +157,7 @@ func (c *Collector) GetPIIInfo(ctx context.Context) (*types.PIIInfo, error) {
 	sort.Strings(info.LoggedInUsers)
 	sort.Strings(info.HomeDirs)
 
-	return info, nil
+	return info, ctx.Err()
 }
 
 // Helper functions
@@ -201,7 +211,7 @@ func (c *Collector) getTimezone() (string, error) {
 	return time.Local.String(), nil
 }
 
-func (c *Collector) getNetworkInterfaces() ([]types.NetworkInterface, error) {
+func (c *Collector) getNetworkInterfaces(ctx context.Context) ([]types.NetworkInterface, error) {
 	interfaces := []types.NetworkInterface{}
 
 	// Read /sys/class/net for interface names
@@ -211,6 +221,9 @@ func (c *Collector) getNetworkInterfaces() ([]types.NetworkInterface, error) {
 	}
 
 	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return interfaces, err
+		}
 		name := entry.Name()
 		if name == "lo" {
 			continue // Skip loopback
@@ -229,7 +242,7 @@ func (c *Collector) getNetworkInterfaces() ([]types.NetworkInterface, error) {
 		}
 
 		// Get IP address using ip command (best-effort)
-		if ip, err := c.getInterfaceIP(name); err == nil {
+		if ip, err := c.getInterfaceIP(ctx, name); err == nil {
 			iface.IPAddress = ip
 		}
 
@@ -239,8 +252,8 @@ func (c *Collector) getNetworkInterfaces() ([]types.NetworkInterface, error) {
 	return interfaces, nil
 }
 
-func (c *Collector) getInterfaceIP(ifaceName string) (string, error) {
-	cmd := exec.Command("ip", "addr", "show", ifaceName)
+func (c *Collector) getInterfaceIP(ctx context.Context, ifaceName string) (string, error) {
+	cmd := exec.CommandContext(ctx, "ip", "addr", "show", ifaceName)
 	output, err := cmd.Output()
 	if err != nil {
 		return "", err
@@ -302,9 +315,20 @@ func (c *Collector) getDMISerial() (string, error) {
 	return strings.TrimSpace(string(data)), nil
 }
 
+// adminGroups are the groups whose members can become root through sudo
+// (Debian/Ubuntu sudo, Red Hat/SUSE wheel, older Ubuntu admin)
+var adminGroups = []string{"sudo", "wheel", "admin"}
+
 func (c *Collector) getLocalUsers() ([]types.User, error) {
 	users := []types.User{}
 
+	// Best-effort: without /etc/group only root is flagged as an administrator
+	var adminGIDs, adminMembers map[string]bool
+	if group, err := os.Open("/etc/group"); err == nil {
+		adminGIDs, adminMembers = parseAdminGroups(group)
+		group.Close()
+	}
+
 	file, err := os.Open("/etc/passwd")
 	if err != nil {
 		return nil, err
@@ -326,6 +350,7 @@ func (c *Collector) getLocalUsers() ([]types.User, error) {
 					Username: username,
 					FullName: fullName,
 					UID:      uid,
+					Admin:    uid == "0" || adminGIDs[fields[3]] || adminMembers[username],
 				})
 			}
 		}
@@ -334,12 +359,37 @@ func (c *Collector) getLocalUsers() ([]types.User, error) {
 	return users, scanner.Err()
 }
 
-func (c *Collector) getLoggedInUsers() ([]string, error) {
+// parseAdminGroups reads /etc/group, returning the GIDs of adminGroups and
+// the users they list as members
+// Complexity: O(n) where n = size of group
+func parseAdminGroups(group io.Reader) (gids, members map[string]bool) {
+	gids, members = map[string]bool{}, map[string]bool{}
+	scanner := bufio.NewScanner(group)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ":") // name:password:GID:member,member
+		if len(fields) < 4 || !slices.Contains(adminGroups, fields[0]) {
+			continue
+		}
+		gids[fields[2]] = true
+		for _, name := range strings.Split(fields[3], ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				members[name] = true
+			}
+		}
+	}
+	return gids, members
+}
+
+// getLoggedInUsers lists the users with a session in utmp, falling back to
+// the current user where there is no utmp (e.g., containers)
+func (c *Collector) getLoggedInUsers(ctx context.Context) ([]string, error) {
+	if sessions, err := readLoginFile(ctx, utmpPath); err == nil || ctx.Err() != nil {
+		return sessionUsers(sessions), nil
+	}
+
 	currentUser, err := user.Current()
 	if err != nil {
 		return []string{}, nil
 	}
-
-	// Simple approach: return current user
 	return []string{currentUser.Username}, nil
 }
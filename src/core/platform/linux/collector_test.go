@@ -0,0 +1,23 @@
+package linux
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParseAdminGroups verifies sudo, wheel and admin members and GIDs are
+// found and other groups ignored
+func TestParseAdminGroups(t *testing.T) {
+	group := "root:x:0:\n" +
+		"adm:x:4:syslog,alice\n" +
+		"sudo:x:27:alice, bob\n" +
+		"wheel:x:10:\n" +
+		"malformed\n"
+	gids, members := parseAdminGroups(strings.NewReader(group))
+	if len(gids) != 2 || !gids["27"] || !gids["10"] {
+		t.Errorf("gids = %v, want 27 and 10", gids)
+	}
+	if len(members) != 2 || !members["alice"] || !members["bob"] {
+		t.Errorf("members = %v, want alice and bob", members)
+	}
+}
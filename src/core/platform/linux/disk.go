@@ -0,0 +1,95 @@
+package linux
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// sysBlockDir exposes every block device, with device-mapper details under
+// <name>/dm and the underlying devices under <name>/slaves
+const sysBlockDir = "/sys/class/block"
+
+// GetDiskInfo lists mounted block-device filesystems via df
+// A volume is LUKS-encrypted when a dm-crypt mapping sits anywhere below it
+// (directly, or under LVM).
+// Complexity: O(v) where v = number of volumes
+func (c *Collector) GetDiskInfo(ctx context.Context) (*types.DiskInfo, error) {
+	out, err := exec.CommandContext(ctx, "df", "-P", "-B1", "-T", "-x", "squashfs").Output()
+	if err != nil {
+		return nil, err
+	}
+	vols := parseDF(string(out))
+	for i := range vols {
+		vols[i].Encryption = luksState(sysBlockDir, vols[i].Device)
+	}
+	return &types.DiskInfo{Volumes: vols}, ctx.Err()
+}
+
+// parseDF parses `df -P -B1 -T` output, keeping /dev/ devices only, sorted
+// by mount point
+// Complexity: O(|out| + v log v)
+func parseDF(out string) []types.Volume {
+	vols := []types.Volume{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 7 || !strings.HasPrefix(fields[0], "/dev/") {
+			continue // Header, tmpfs, overlay and other pseudo filesystems
+		}
+		total, err1 := strconv.ParseInt(fields[2], 10, 64)
+		free, err2 := strconv.ParseInt(fields[4], 10, 64)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		vols = append(vols, types.Volume{
+			MountPoint: strings.Join(fields[6:], " "),
+			Device:     fields[0],
+			FileSystem: fields[1],
+			TotalBytes: total,
+			FreeBytes:  free,
+		})
+	}
+	sort.Slice(vols, func(i, j int) bool { return vols[i].MountPoint < vols[j].MountPoint })
+	return vols
+}
+
+// luksState reports whether device is backed by dm-crypt
+// Complexity: O(d) where d = depth of the device stack
+func luksState(sysBlock, device string) string {
+	resolved, err := filepath.EvalSymlinks(device) // /dev/mapper/x -> /dev/dm-N
+	if err != nil {
+		return types.EncryptionUnknown
+	}
+	name := filepath.Base(resolved)
+	if _, err := os.Stat(filepath.Join(sysBlock, name)); err != nil {
+		return types.EncryptionUnknown
+	}
+	if cryptBacked(sysBlock, name, 0) {
+		return types.EncryptionLUKS
+	}
+	return types.EncryptionNone
+}
+
+// cryptBacked walks name and its slaves looking for a CRYPT- dm UUID
+func cryptBacked(sysBlock, name string, depth int) bool {
+	if depth > 8 {
+		return false // Guard against cycles in a malformed tree
+	}
+	if uuid, err := os.ReadFile(filepath.Join(sysBlock, name, "dm", "uuid")); err == nil &&
+		strings.HasPrefix(string(uuid), "CRYPT-") {
+		return true
+	}
+	slaves, _ := os.ReadDir(filepath.Join(sysBlock, name, "slaves"))
+	for _, slave := range slaves {
+		if cryptBacked(sysBlock, slave.Name(), depth+1) {
+			return true
+		}
+	}
+	return false
+}
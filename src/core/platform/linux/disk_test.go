@@ -0,0 +1,72 @@
+package linux
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// TestParseDF verifies pseudo filesystems are dropped and mount points with spaces kept
+func TestParseDF(t *testing.T) {
+	out := "Filesystem     Type     1-blocks        Used   Available Capacity Mounted on\n" +
+		"/dev/dm-1      ext4  512110190592 310783598592 201326592000      61% /\n" +
+		"tmpfs          tmpfs   8246132736      4096  8246128640       1% /dev/shm\n" +
+		"/dev/sdb1      vfat    31016878080  1048576 31015829504       1% /media/alice/USB STICK\n"
+	vols := parseDF(out)
+	if len(vols) != 2 {
+		t.Fatalf("got %d volumes, want 2: %+v", len(vols), vols)
+	}
+	if v := vols[0]; v.MountPoint != "/" || v.FileSystem != "ext4" || v.TotalBytes != 512110190592 || v.FreeBytes != 201326592000 {
+		t.Errorf("vols[0] = %+v", v)
+	}
+	if v := vols[1]; v.MountPoint != "/media/alice/USB STICK" || v.Device != "/dev/sdb1" {
+		t.Errorf("vols[1] = %+v", v)
+	}
+}
+
+// TestCryptBacked verifies LUKS is found below LVM and plain partitions are not encrypted
+func TestCryptBacked(t *testing.T) {
+	sys := t.TempDir()
+	mk := func(name, uuid string, slaves ...string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Join(sys, name, "slaves"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if uuid != "" {
+			if err := os.MkdirAll(filepath.Join(sys, name, "dm"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(sys, name, "dm", "uuid"), []byte(uuid+"\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, s := range slaves {
+			if err := os.Mkdir(filepath.Join(sys, name, "slaves", s), 0o755); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	mk("nvme0n1p3", "")
+	mk("dm-0", "CRYPT-LUKS2-0123-luks", "nvme0n1p3")
+	mk("dm-1", "LVM-abc", "dm-0") // root LV on LUKS
+	mk("nvme0n1p2", "")
+
+	dev := t.TempDir()
+	for _, name := range []string{"dm-1", "nvme0n1p2"} {
+		if err := os.WriteFile(filepath.Join(dev, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := luksState(sys, filepath.Join(dev, "dm-1")); got != types.EncryptionLUKS {
+		t.Errorf("LVM on LUKS = %q, want luks", got)
+	}
+	if got := luksState(sys, filepath.Join(dev, "nvme0n1p2")); got != types.EncryptionNone {
+		t.Errorf("plain partition = %q, want none", got)
+	}
+	if got := luksState(sys, filepath.Join(dev, "missing")); got != types.EncryptionUnknown {
+		t.Errorf("missing device = %q, want unknown", got)
+	}
+}
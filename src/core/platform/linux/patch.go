@@ -0,0 +1,136 @@
+package linux
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// aptHistoryPath is apt's transaction log (unattended-upgrades writes it too)
+const aptHistoryPath = "/var/log/apt/history.log"
+
+// GetPatchInfo reports when packages were last installed or upgraded and how
+// many upgrades are pending
+// The last update comes from apt's history log, else the newest rpm install
+// time. Pending upgrades are counted from the local package cache only
+// (`apt-get -s upgrade`, `dnf -C check-update`), so nothing is downloaded.
+// Individual packages are in software_inventory; no updates are listed.
+// Complexity: O(|history| + p) where p = number of installed packages
+func (c *Collector) GetPatchInfo(ctx context.Context) (*types.PatchInfo, error) {
+	info := &types.PatchInfo{Updates: []types.Update{}}
+
+	if f, err := os.Open(aptHistoryPath); err == nil {
+		info.LastUpdate = lastAptUpdate(f)
+		f.Close()
+	}
+	if path, err := exec.LookPath("rpm"); err == nil && info.LastUpdate == "" {
+		out, err := exec.CommandContext(ctx, path, "-qa", "--queryformat", "%{INSTALLTIME}\n").Output()
+		if err == nil {
+			info.LastUpdate = lastRPMInstall(out)
+		}
+	}
+
+	if path, err := exec.LookPath("apt-get"); err == nil {
+		out, err := exec.CommandContext(ctx, path, "-s", "-o", "Debug::NoLocking=true", "upgrade").Output()
+		if err == nil {
+			n := countAptUpgrades(out)
+			info.Pending = &n
+		}
+	} else if path, err := exec.LookPath("dnf"); err == nil {
+		// check-update exits 100 when updates are available
+		out, err := exec.CommandContext(ctx, path, "-C", "-q", "check-update").Output()
+		var exitErr *exec.ExitError
+		if err == nil || (errors.As(err, &exitErr) && exitErr.ExitCode() == 100) {
+			n := countDnfUpdates(out)
+			info.Pending = &n
+		}
+	}
+	return info, ctx.Err()
+}
+
+// lastAptUpdate returns the End-Date (YYYY-MM-DD) of the latest apt
+// transaction that installed or upgraded packages
+// Removals and purges alone are not updates.
+// Complexity: O(|history|)
+func lastAptUpdate(r io.Reader) string {
+	var last, end string
+	changed := false
+	flush := func() {
+		if changed && end > last {
+			last = end
+		}
+		end, changed = "", false
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024) // Upgrade lines list every package
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ": ")
+		switch {
+		case !ok:
+			flush() // Blank line between transactions
+		case key == "Install" || key == "Upgrade":
+			changed = true
+		case key == "End-Date":
+			// "2024-04-02  10:15:35" (local time)
+			if date, _, _ := strings.Cut(strings.TrimSpace(value), " "); len(date) == len("2006-01-02") {
+				end = date
+			}
+		}
+	}
+	flush()
+	return last
+}
+
+// lastRPMInstall returns the newest install date (YYYY-MM-DD) among
+// `rpm -qa --queryformat '%{INSTALLTIME}\n'` lines (Unix seconds)
+// Complexity: O(|out|)
+func lastRPMInstall(out []byte) string {
+	var newest int64
+	for _, line := range bytes.Split(out, []byte("\n")) {
+		if secs, err := strconv.ParseInt(string(line), 10, 64); err == nil && secs > newest {
+			newest = secs
+		}
+	}
+	if newest == 0 {
+		return ""
+	}
+	return time.Unix(newest, 0).UTC().Format("2006-01-02")
+}
+
+// countAptUpgrades counts the packages `apt-get -s upgrade` would install
+// Complexity: O(|out|)
+func countAptUpgrades(out []byte) int {
+	n := 0
+	for _, line := range bytes.Split(out, []byte("\n")) {
+		if bytes.HasPrefix(line, []byte("Inst ")) {
+			n++
+		}
+	}
+	return n
+}
+
+// countDnfUpdates counts the "name.arch version repo" lines of
+// `dnf check-update`, stopping at the obsoletes section
+// Complexity: O(|out|)
+func countDnfUpdates(out []byte) int {
+	n := 0
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.HasPrefix(line, "Obsoleting Packages") {
+			break
+		}
+		if fields := strings.Fields(line); len(fields) == 3 && strings.Contains(fields[0], ".") {
+			n++
+		}
+	}
+	return n
+}
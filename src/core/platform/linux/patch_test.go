@@ -0,0 +1,62 @@
+package linux
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestLastAptUpdate verifies the latest installing transaction wins and
+// removals are ignored
+func TestLastAptUpdate(t *testing.T) {
+	history := `
+Start-Date: 2024-03-30  08:00:01
+Commandline: /usr/bin/unattended-upgrade
+Upgrade: openssl:amd64 (3.0.2-0ubuntu1.14, 3.0.2-0ubuntu1.15)
+End-Date: 2024-03-30  08:00:09
+
+Start-Date: 2024-04-02  10:15:30
+Commandline: apt install nmap
+Install: nmap:amd64 (7.91+dfsg1+really7.80+dfsg1-2ubuntu0.1)
+End-Date: 2024-04-02  10:15:35
+
+Start-Date: 2024-04-05  16:00:00
+Commandline: apt purge nmap
+Purge: nmap:amd64 (7.91+dfsg1+really7.80+dfsg1-2ubuntu0.1)
+End-Date: 2024-04-05  16:00:02
+`
+	if got := lastAptUpdate(strings.NewReader(history)); got != "2024-04-02" {
+		t.Errorf("lastAptUpdate() = %q, want 2024-04-02", got)
+	}
+	if got := lastAptUpdate(strings.NewReader("")); got != "" {
+		t.Errorf("lastAptUpdate(empty) = %q", got)
+	}
+}
+
+// TestLastRPMInstall verifies the newest install time is used
+func TestLastRPMInstall(t *testing.T) {
+	if got := lastRPMInstall([]byte("1711790409\n1712052935\n(none)\n1700000000\n")); got != "2024-04-02" {
+		t.Errorf("lastRPMInstall() = %q, want 2024-04-02", got)
+	}
+	if got := lastRPMInstall(nil); got != "" {
+		t.Errorf("lastRPMInstall(nil) = %q", got)
+	}
+}
+
+// TestCountPendingUpdates verifies apt simulation and dnf check-update counts
+func TestCountPendingUpdates(t *testing.T) {
+	apt := "NOTE: This is only a simulation!\nReading package lists...\n" +
+		"Inst libssl3 [3.0.2-0ubuntu1.14] (3.0.2-0ubuntu1.15 Ubuntu:22.04/jammy-updates [amd64])\n" +
+		"Inst openssl [3.0.2-0ubuntu1.14] (3.0.2-0ubuntu1.15 Ubuntu:22.04/jammy-updates [amd64])\n" +
+		"Conf libssl3 (3.0.2-0ubuntu1.15 Ubuntu:22.04/jammy-updates [amd64])\n"
+	if got := countAptUpgrades([]byte(apt)); got != 2 {
+		t.Errorf("countAptUpgrades() = %d, want 2", got)
+	}
+
+	dnf := "\nkernel.x86_64            5.14.0-427.el9        baseos\n" +
+		"openssl.x86_64           1:3.0.7-27.el9        baseos\n" +
+		"Obsoleting Packages\n" +
+		"grub2-tools.x86_64       1:2.06-77.el9         baseos\n"
+	if got := countDnfUpdates([]byte(dnf)); got != 2 {
+		t.Errorf("countDnfUpdates() = %d, want 2", got)
+	}
+}
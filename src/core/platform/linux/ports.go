@@ -0,0 +1,177 @@
+package linux
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// Socket states in /proc/net/{tcp,udp}[6]
+const (
+	tcpListen  = "0A" // TCP_LISTEN
+	udpUnbound = "07" // TCP_CLOSE: a bound, unconnected UDP socket
+)
+
+// socketLink prefixes the /proc/<pid>/fd link target of a socket ("socket:[1234]")
+const socketLink = "socket:["
+
+// GetListeningPorts lists listening TCP and bound UDP sockets from /proc/net
+// Owners come from the socket links in /proc/<pid>/fd, which are only
+// readable for other users' processes as root; those sockets have no PID.
+// Complexity: O(s + f) where s = sockets, f = open file descriptors
+func (c *Collector) GetListeningPorts(ctx context.Context) (*types.PortInfo, error) {
+	ports, err := readListeningPorts(ctx, "/proc")
+	if ports == nil {
+		return nil, err
+	}
+	return &types.PortInfo{Ports: ports}, err
+}
+
+// readListeningPorts reads the socket tables below procDir and resolves owners
+// A missing table (e.g., IPv6 disabled) is skipped; the ports are sorted. On
+// timeout the ports are returned with the owners resolved so far and ctx's
+// error.
+// Complexity: O(s + f)
+func readListeningPorts(ctx context.Context, procDir string) ([]types.ListeningPort, error) {
+	tables := []struct {
+		file, protocol, state string
+	}{
+		{"tcp", "tcp", tcpListen},
+		{"tcp6", "tcp", tcpListen},
+		{"udp", "udp", udpUnbound},
+		{"udp6", "udp", udpUnbound},
+	}
+
+	ports := []types.ListeningPort{}
+	inodes := []string{}
+	read := 0
+	for _, table := range tables {
+		f, err := os.Open(filepath.Join(procDir, "net", table.file))
+		if err != nil {
+			continue
+		}
+		socks := parseSocketTable(f, table.protocol, table.state)
+		f.Close()
+		read++
+		for _, s := range socks {
+			ports = append(ports, s.port)
+			inodes = append(inodes, s.inode)
+		}
+	}
+	if read == 0 {
+		return nil, os.ErrNotExist
+	}
+
+	owners, err := socketOwners(ctx, procDir)
+	for i, inode := range inodes {
+		if owner, ok := owners[inode]; ok {
+			ports[i].PID, ports[i].Process = owner.PID, owner.Name
+		}
+	}
+	types.SortPorts(ports)
+	return ports, err
+}
+
+// socket is one row of a /proc/net socket table
+type socket struct {
+	port  types.ListeningPort
+	inode string
+}
+
+// parseSocketTable returns the sockets of a /proc/net table in state
+// Columns: sl local_address rem_address st ... uid timeout inode
+// Complexity: O(|table|)
+func parseSocketTable(r io.Reader, protocol, state string) []socket {
+	socks := []socket{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[3] != state {
+			continue
+		}
+		addr, port, ok := parseHexAddr(fields[1])
+		if !ok {
+			continue
+		}
+		socks = append(socks, socket{
+			port:  types.ListeningPort{Protocol: protocol, Address: addr, Port: port},
+			inode: fields[9],
+		})
+	}
+	return socks
+}
+
+// parseHexAddr decodes a /proc/net address ("0100007F:0277" → 127.0.0.1, 631)
+// The address is stored as 32-bit words in host (little-endian) order; the
+// port is big-endian hex.
+func parseHexAddr(s string) (string, int, bool) {
+	hexIP, hexPort, ok := strings.Cut(s, ":")
+	if !ok {
+		return "", 0, false
+	}
+	port, err := strconv.ParseUint(hexPort, 16, 16)
+	if err != nil {
+		return "", 0, false
+	}
+	raw, err := hex.DecodeString(hexIP)
+	if err != nil || (len(raw) != net.IPv4len && len(raw) != net.IPv6len) {
+		return "", 0, false
+	}
+	ip := make(net.IP, len(raw))
+	for word := 0; word < len(raw); word += 4 {
+		for i := 0; i < 4; i++ {
+			ip[word+i] = raw[word+3-i]
+		}
+	}
+	return ip.String(), int(port), true
+}
+
+// socketOwners maps socket inodes to the first process holding them
+// Complexity: O(f) where f = readable file descriptors
+func socketOwners(ctx context.Context, procDir string) (map[string]types.Process, error) {
+	entries, err := os.ReadDir(procDir)
+	if err != nil {
+		return nil, err
+	}
+
+	owners := map[string]types.Process{}
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return owners, err
+		}
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+		fdDir := filepath.Join(procDir, entry.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue // Another user's process, or it exited
+		}
+		name := ""
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil || !strings.HasPrefix(link, socketLink) {
+				continue
+			}
+			inode := strings.TrimSuffix(strings.TrimPrefix(link, socketLink), "]")
+			if _, seen := owners[inode]; seen {
+				continue
+			}
+			if name == "" {
+				comm, _ := os.ReadFile(filepath.Join(procDir, entry.Name(), "comm"))
+				name = strings.TrimSpace(string(comm))
+			}
+			owners[inode] = types.Process{PID: pid, Name: name}
+		}
+	}
+	return owners, nil
+}
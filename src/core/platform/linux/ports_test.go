@@ -0,0 +1,100 @@
+package linux
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const tcpTable = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:0016 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1001 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:0277 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1002 1 0000000000000000 100 0 0 10 0
+   2: 0F02000A:0016 0A02000A:D431 01 00000000:00000000 02:000A7B2E 00000000     0        0 1003 4 0000000000000000 20 4 31 10 -1
+`
+
+const tcp6Table = `  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000000000000000000001000000:0277 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1004 1 0000000000000000 100 0 0 10 0
+`
+
+const udpTable = `   sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode ref pointer drops
+  100: 00000000:0044 00000000:0000 07 00000000:00000000 00:00000000 00000000     0        0 1005 2 0000000000000000 0
+  101: 0F02000A:A1B2 08080808:0035 01 00000000:00000000 00:00000000 00000000  1000        0 1006 2 0000000000000000 0
+`
+
+// TestParseHexAddr verifies IPv4 and IPv6 decoding from /proc/net
+func TestParseHexAddr(t *testing.T) {
+	tests := []struct {
+		in   string
+		addr string
+		port int
+	}{
+		{"0100007F:0277", "127.0.0.1", 631},
+		{"00000000:0016", "0.0.0.0", 22},
+		{"00000000000000000000000001000000:0277", "::1", 631},
+		{"0000000000000000FFFF00000100007F:1F90", "127.0.0.1", 8080}, // IPv4-mapped
+	}
+	for _, tt := range tests {
+		addr, port, ok := parseHexAddr(tt.in)
+		if !ok || addr != tt.addr || port != tt.port {
+			t.Errorf("parseHexAddr(%q) = %q, %d, %v; want %q, %d", tt.in, addr, port, ok, tt.addr, tt.port)
+		}
+	}
+	if _, _, ok := parseHexAddr("zz:0016"); ok {
+		t.Error("parseHexAddr accepted a malformed address")
+	}
+}
+
+// TestReadListeningPorts verifies state filtering, owners and ordering
+func TestReadListeningPorts(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "net"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range map[string]string{"tcp": tcpTable, "tcp6": tcp6Table, "udp": udpTable} {
+		if err := os.WriteFile(filepath.Join(root, "net", name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeProc(t, root, "812", "sshd", "/usr/sbin/sshd\x00", "0", "")
+	fd := filepath.Join(root, "812", "fd")
+	if err := os.Mkdir(fd, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, target := range map[string]string{"3": "socket:[1001]", "4": "/dev/null", "5": "socket:[1005]"} {
+		if err := os.Symlink(target, filepath.Join(fd, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ports, err := readListeningPorts(context.Background(), root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, p := range ports {
+		got = append(got, p.Protocol+" "+p.Address+" "+strconv.Itoa(p.Port)+" "+p.Process)
+	}
+	want := "tcp 0.0.0.0 22 sshd|tcp 127.0.0.1 631 |tcp ::1 631 |udp 0.0.0.0 68 sshd"
+	if strings.Join(got, "|") != want {
+		t.Errorf("ports = %q, want %q", strings.Join(got, "|"), want)
+	}
+	if ports[0].PID != 812 || ports[1].PID != 0 {
+		t.Errorf("owners = %+v", ports)
+	}
+
+	// Timed out before the owners were read: sockets without owners
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ports, err = readListeningPorts(ctx, root)
+	if !errors.Is(err, context.Canceled) || len(ports) != 4 || ports[0].PID != 0 {
+		t.Errorf("cancelled readListeningPorts() = %+v, %v; want 4 ports without owners", ports, err)
+	}
+
+	if _, err := readListeningPorts(context.Background(), t.TempDir()); err == nil {
+		t.Error("readListeningPorts() succeeded without socket tables")
+	}
+}
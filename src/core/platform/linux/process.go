@@ -0,0 +1,114 @@
+package linux
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// GetProcessInfo lists running processes from /proc
+// Kernel threads (empty command line) are skipped; the executable path of
+// another user's process is only readable as root and is left empty otherwise.
+// On timeout the processes read so far are returned with ctx's error.
+// Complexity: O(p) where p = number of processes
+func (c *Collector) GetProcessInfo(ctx context.Context) (*types.ProcessInfo, error) {
+	names := map[string]string{}
+	if f, err := os.Open("/etc/passwd"); err == nil {
+		names = passwdNames(f)
+		f.Close()
+	}
+	procs, err := readProcesses(ctx, "/proc", names)
+	if procs == nil {
+		return nil, err
+	}
+	return &types.ProcessInfo{Processes: procs}, err
+}
+
+// readProcesses reads every numeric directory under procDir, sorted by PID
+// Cancellation stops the scan; the processes read so far are returned with
+// ctx's error.
+// names maps UIDs to account names; unknown UIDs are reported as the UID.
+// Complexity: O(p)
+func readProcesses(ctx context.Context, procDir string, names map[string]string) ([]types.Process, error) {
+	entries, err := os.ReadDir(procDir)
+	if err != nil {
+		return nil, err
+	}
+
+	procs := []types.Process{}
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			break // Timed out: keep the processes already read
+		}
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(procDir, entry.Name())
+
+		// Processes exit while being read; skip whatever vanished
+		cmdline, err := os.ReadFile(filepath.Join(dir, "cmdline"))
+		if err != nil || len(cmdline) == 0 {
+			continue
+		}
+		comm, err := os.ReadFile(filepath.Join(dir, "comm"))
+		if err != nil {
+			continue
+		}
+
+		proc := types.Process{PID: pid, Name: strings.TrimSpace(string(comm))}
+		if exe, err := os.Readlink(filepath.Join(dir, "exe")); err == nil {
+			proc.Path = strings.TrimSuffix(exe, " (deleted)")
+		}
+		if f, err := os.Open(filepath.Join(dir, "status")); err == nil {
+			if uid := statusUID(f); uid != "" {
+				proc.User = uid
+				if name, ok := names[uid]; ok {
+					proc.User = name
+				}
+			}
+			f.Close()
+		}
+		procs = append(procs, proc)
+	}
+
+	sort.Slice(procs, func(i, j int) bool { return procs[i].PID < procs[j].PID })
+	return procs, ctx.Err()
+}
+
+// statusUID returns the real UID from a /proc/<pid>/status file
+// Complexity: O(|status|)
+func statusUID(r io.Reader) string {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if rest, ok := strings.CutPrefix(scanner.Text(), "Uid:"); ok {
+			if fields := strings.Fields(rest); len(fields) > 0 {
+				return fields[0]
+			}
+		}
+	}
+	return ""
+}
+
+// passwdNames maps UIDs to account names from an /etc/passwd file
+// Complexity: O(|passwd|)
+func passwdNames(r io.Reader) map[string]string {
+	names := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ":")
+		if len(fields) >= 3 && fields[0] != "" {
+			if _, dup := names[fields[2]]; !dup {
+				names[fields[2]] = fields[0] // First entry wins, as in getpwuid
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,63 @@
+package linux
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeProc creates a fake /proc/<pid> directory
+func writeProc(t *testing.T, root, pid, comm, cmdline, uid, exe string) {
+	t.Helper()
+	dir := filepath.Join(root, pid)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"comm":    comm + "\n",
+		"cmdline": cmdline,
+		"status":  "Name:\t" + comm + "\nUid:\t" + uid + "\t" + uid + "\t" + uid + "\t" + uid + "\n",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if exe != "" {
+		if err := os.Symlink(exe, filepath.Join(dir, "exe")); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// TestReadProcesses verifies PID order, owner names and kernel-thread filtering
+func TestReadProcesses(t *testing.T) {
+	root := t.TempDir()
+	writeProc(t, root, "812", "sshd", "/usr/sbin/sshd\x00-D\x00", "0", "/usr/sbin/sshd")
+	writeProc(t, root, "2", "kthreadd", "", "0", "")
+	writeProc(t, root, "4242", "bash", "-bash\x00", "1000", "/usr/bin/bash (deleted)")
+	writeProc(t, root, "77", "orphan", "orphan\x00", "1234", "")
+	if err := os.Mkdir(filepath.Join(root, "self"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	names := passwdNames(strings.NewReader("root:x:0:0:root:/root:/bin/bash\nalice:x:1000:1000:Alice:/home/alice:/bin/bash\n"))
+	procs, err := readProcesses(context.Background(), root, names)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(procs) != 3 {
+		t.Fatalf("got %d processes, want 3: %+v", len(procs), procs)
+	}
+	if p := procs[0]; p.PID != 77 || p.User != "1234" || p.Path != "" {
+		t.Errorf("procs[0] = %+v", p)
+	}
+	if p := procs[1]; p.PID != 812 || p.Name != "sshd" || p.Path != "/usr/sbin/sshd" || p.User != "root" {
+		t.Errorf("procs[1] = %+v", p)
+	}
+	if p := procs[2]; p.PID != 4242 || p.Path != "/usr/bin/bash" || p.User != "alice" {
+		t.Errorf("procs[2] = %+v", p)
+	}
+}
@@ -0,0 +1,133 @@
+package linux
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// securityAgent is an antivirus or EDR product recognized by its install
+// path or its running daemon
+type securityAgent struct {
+	name     string
+	kind     string
+	path     string   // Present when installed
+	daemon   string   // Process name while protection runs
+	packages []string // dpkg/rpm package names carrying the version
+}
+
+// securityAgents are the products GetSecurityProducts looks for
+var securityAgents = []securityAgent{
+	{"ClamAV", types.ProductAntivirus, "/usr/sbin/clamd", "clamd", []string{"clamav-daemon", "clamd"}},
+	{"CrowdStrike Falcon", types.ProductEDR, "/opt/CrowdStrike/falconctl", "falcon-sensor", []string{"falcon-sensor"}},
+	{"Elastic Defend", types.ProductEDR, "/opt/Elastic/Endpoint/elastic-endpoint", "elastic-endpoint", nil},
+	{"Microsoft Defender for Endpoint", types.ProductEDR, "/opt/microsoft/mdatp/sbin/wdavdaemon", "wdavdaemon", []string{"mdatp"}},
+	{"SentinelOne", types.ProductEDR, "/opt/sentinelone/bin/sentinelctl", "s1-agent", []string{"sentinelagent", "SentinelAgent"}},
+	{"Sophos Protection for Linux", types.ProductAntivirus, "/opt/sophos-spl/bin/wdctl", "sophos_threat_detector", nil},
+	{"Trend Micro Deep Security Agent", types.ProductAntivirus, "/opt/ds_agent/ds_agent", "ds_agent", []string{"ds-agent", "ds_agent"}},
+	{"Wazuh Agent", types.ProductEDR, "/var/ossec/bin/wazuh-agentd", "wazuh-agentd", []string{"wazuh-agent"}},
+}
+
+// commLen is the longest process name the kernel keeps in /proc/<pid>/comm
+const commLen = 15
+
+// GetSecurityProducts detects the antivirus and EDR agents in
+// securityAgents by install path and running daemon
+// Real-time protection is "on" while an agent's daemon runs, "off" when it
+// is installed but stopped and "unknown" when /proc is unreadable. Versions
+// come from the dpkg or rpm package.
+// Complexity: O(k + p) where k = known agents, p = number of processes
+func (c *Collector) GetSecurityProducts(ctx context.Context) (*types.SecurityInfo, error) {
+	var running map[string]bool
+	if procs, err := readProcesses(ctx, "/proc", nil); err == nil {
+		running = make(map[string]bool, len(procs))
+		for _, p := range procs {
+			running[p.Name] = true
+		}
+	} else if ctx.Err() != nil {
+		return nil, err
+	}
+
+	var versions map[string]string // Read once, on the first installed agent
+	version := func(packages []string) string {
+		if versions == nil {
+			versions = packageVersions(ctx, securityAgents)
+		}
+		for _, pkg := range packages {
+			if v := versions[pkg]; v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+	products := detectAgents(securityAgents, "/", running, version)
+	return &types.SecurityInfo{Products: products}, ctx.Err()
+}
+
+// detectAgents returns the agents installed below root or running, sorted
+// by name
+// running holds the process names (nil when unreadable); version returns
+// the version of the first installed package of a list.
+// Complexity: O(k)
+func detectAgents(agents []securityAgent, root string, running map[string]bool, version func(packages []string) string) []types.SecurityProduct {
+	products := []types.SecurityProduct{}
+	for _, a := range agents {
+		daemon := a.daemon
+		if len(daemon) > commLen {
+			daemon = daemon[:commLen]
+		}
+		_, err := os.Stat(filepath.Join(root, a.path))
+		if err != nil && !running[daemon] {
+			continue
+		}
+
+		p := types.SecurityProduct{Name: a.name, Kind: a.kind, RealTime: types.RealTimeUnknown}
+		if running != nil {
+			p.RealTime = types.RealTimeOff
+			if running[daemon] {
+				p.RealTime = types.RealTimeOn
+			}
+		}
+		if len(a.packages) > 0 {
+			p.Version = version(a.packages)
+		}
+		products = append(products, p)
+	}
+	types.SortProducts(products)
+	return products
+}
+
+// packageVersions maps the agents' package names to their installed
+// versions, from the dpkg database and rpm
+// Complexity: O(|dpkg status| + k)
+func packageVersions(ctx context.Context, agents []securityAgent) map[string]string {
+	var names []string
+	for _, a := range agents {
+		names = append(names, a.packages...)
+	}
+
+	versions := map[string]string{}
+	if f, err := os.Open(dpkgStatusPath); err == nil {
+		pkgs, _ := parseDpkgStatus(f, func(string) string { return "" })
+		f.Close()
+		for _, pkg := range pkgs {
+			versions[pkg.Name] = pkg.Version
+		}
+	}
+	if path, err := exec.LookPath("rpm"); err == nil {
+		// rpm exits non-zero when any package is missing; the output still
+		// lists the installed ones
+		out, _ := exec.CommandContext(ctx, path, append([]string{"-q", "--queryformat", "%{NAME}\t%{VERSION}-%{RELEASE}\n"}, names...)...).Output()
+		for _, line := range bytes.Split(out, []byte("\n")) {
+			if name, version, ok := strings.Cut(string(line), "\t"); ok {
+				versions[name] = version
+			}
+		}
+	}
+	return versions
+}
@@ -0,0 +1,53 @@
+package linux
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// TestDetectAgents verifies agents are found by path or daemon, with
+// real-time state from the process list and truncated comm names matching
+func TestDetectAgents(t *testing.T) {
+	root := t.TempDir()
+	for _, path := range []string{"/usr/sbin/clamd", "/opt/CrowdStrike/falconctl"} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(root, path)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, path), nil, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	version := func(packages []string) string {
+		if packages[0] == "clamav-daemon" {
+			return "1.0.7"
+		}
+		return ""
+	}
+
+	// clamd installed and stopped, Falcon running, Elastic running without
+	// its install path (comm truncated to 15 bytes)
+	running := map[string]bool{"falcon-sensor": true, "elastic-endpoin": true, "bash": true}
+	got := detectAgents(securityAgents, root, running, version)
+	want := []types.SecurityProduct{
+		{Name: "ClamAV", Kind: types.ProductAntivirus, Version: "1.0.7", RealTime: types.RealTimeOff},
+		{Name: "CrowdStrike Falcon", Kind: types.ProductEDR, RealTime: types.RealTimeOn},
+		{Name: "Elastic Defend", Kind: types.ProductEDR, RealTime: types.RealTimeOn},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("detectAgents() = %+v, want %+v", got, want)
+	}
+
+	// Unreadable process list: installed agents with unknown state
+	got = detectAgents(securityAgents, root, nil, version)
+	if len(got) != 2 || got[0].RealTime != types.RealTimeUnknown || got[1].RealTime != types.RealTimeUnknown {
+		t.Errorf("detectAgents(no processes) = %+v", got)
+	}
+
+	if got := detectAgents(securityAgents, t.TempDir(), map[string]bool{}, version); len(got) != 0 {
+		t.Errorf("detectAgents(clean host) = %+v", got)
+	}
+}
@@ -0,0 +1,130 @@
+package linux
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// Login records (utmp(5)): /var/run/utmp holds current sessions and
+// /var/log/wtmp every login since it was last rotated
+const (
+	utmpPath = "/var/run/utmp"
+	wtmpPath = "/var/log/wtmp"
+)
+
+// struct utmp layout, the same on 32- and 64-bit glibc (native byte order)
+const (
+	utmpSize        = 384
+	utmpUserProcess = 7 // ut_type of a login session
+	utmpLineOffset  = 8 // ut_line: terminal, e.g. "pts/0"
+	utmpUserOffset  = 44
+	utmpNameLen     = 32
+	utmpTimeOffset  = 340 // ut_tv.tv_sec (32 bits)
+)
+
+// loginRecord is one login session from utmp or wtmp
+type loginRecord struct {
+	User string
+	Line string
+	Time time.Time
+}
+
+// readLoginFile returns the login sessions recorded in a utmp-format file
+// Complexity: O(n) where n = records
+func readLoginFile(ctx context.Context, path string) ([]loginRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseLogins(ctx, f)
+}
+
+// parseLogins reads utmp records from r, keeping USER_PROCESS entries
+// Boot, run-level and logout records are skipped, as is a final record cut
+// short by a concurrent write. ctx is checked every 1024 records; on
+// cancellation the sessions read so far are returned with ctx's error.
+// Complexity: O(n) where n = records
+func parseLogins(ctx context.Context, r io.Reader) ([]loginRecord, error) {
+	logins := []loginRecord{}
+	rec := make([]byte, utmpSize)
+	for i := 0; ; i++ {
+		if i%1024 == 0 {
+			if err := ctx.Err(); err != nil {
+				return logins, err
+			}
+		}
+		if _, err := io.ReadFull(r, rec); errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return logins, nil
+		} else if err != nil {
+			return logins, err
+		}
+		if int16(binary.NativeEndian.Uint16(rec)) != utmpUserProcess {
+			continue
+		}
+		user := cString(rec[utmpUserOffset : utmpUserOffset+utmpNameLen])
+		if user == "" {
+			continue
+		}
+		logins = append(logins, loginRecord{
+			User: user,
+			Line: cString(rec[utmpLineOffset : utmpLineOffset+utmpNameLen]),
+			Time: time.Unix(int64(binary.NativeEndian.Uint32(rec[utmpTimeOffset:])), 0).UTC(),
+		})
+	}
+}
+
+// cString returns the NUL-terminated string in b
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
+
+// sessionUsers returns the distinct users with a session, sorted
+// Complexity: O(n log n) where n = sessions
+func sessionUsers(logins []loginRecord) []string {
+	seen := map[string]bool{}
+	users := []string{}
+	for _, l := range logins {
+		if !seen[l.User] {
+			seen[l.User] = true
+			users = append(users, l.User)
+		}
+	}
+	sort.Strings(users)
+	return users
+}
+
+// lastLogons returns each user's latest login and number of logins,
+// sorted by username
+// Complexity: O(n + u log u) where n = logins, u = users
+func lastLogons(logins []loginRecord) []types.UserProfile {
+	latest := map[string]time.Time{}
+	counts := map[string]int{}
+	for _, l := range logins {
+		if l.Time.After(latest[l.User]) {
+			latest[l.User] = l.Time
+		}
+		counts[l.User]++
+	}
+	profiles := make([]types.UserProfile, 0, len(latest))
+	for user, t := range latest {
+		profiles = append(profiles, types.UserProfile{
+			Username:   user,
+			LastLogon:  t.Format(time.RFC3339),
+			LogonCount: counts[user],
+		})
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Username < profiles[j].Username })
+	return profiles
+}
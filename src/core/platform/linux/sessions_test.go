@@ -0,0 +1,59 @@
+package linux
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+// utmpRecord encodes one struct utmp record
+func utmpRecord(typ int16, user, line string, sec uint32) []byte {
+	rec := make([]byte, utmpSize)
+	binary.NativeEndian.PutUint16(rec, uint16(typ))
+	copy(rec[utmpLineOffset:], line)
+	copy(rec[utmpUserOffset:], user)
+	binary.NativeEndian.PutUint32(rec[utmpTimeOffset:], sec)
+	return rec
+}
+
+// TestParseLogins verifies only login sessions are kept and a truncated
+// final record is ignored
+func TestParseLogins(t *testing.T) {
+	var wtmp bytes.Buffer
+	wtmp.Write(utmpRecord(2, "reboot", "~", 1700000000)) // BOOT_TIME
+	wtmp.Write(utmpRecord(utmpUserProcess, "alice", "tty1", 1700000100))
+	wtmp.Write(utmpRecord(8, "", "tty1", 1700000200)) // DEAD_PROCESS (logout)
+	wtmp.Write(utmpRecord(utmpUserProcess, "bob", "pts/0", 1700000300))
+	wtmp.Write(utmpRecord(utmpUserProcess, "alice", "pts/1", 1700000400))
+	wtmp.Write(utmpRecord(utmpUserProcess, "carol", "pts/2", 1700000500)[:100])
+
+	logins, err := parseLogins(context.Background(), &wtmp)
+	if err != nil {
+		t.Fatalf("parseLogins() failed: %v", err)
+	}
+	if len(logins) != 3 || logins[0].User != "alice" || logins[1].Line != "pts/0" ||
+		!logins[2].Time.Equal(time.Unix(1700000400, 0)) {
+		t.Fatalf("logins = %+v", logins)
+	}
+
+	if users := sessionUsers(logins); len(users) != 2 || users[0] != "alice" || users[1] != "bob" {
+		t.Errorf("sessionUsers() = %v", users)
+	}
+	profiles := lastLogons(logins)
+	if len(profiles) != 2 || profiles[0].Username != "alice" || profiles[0].LastLogon != "2023-11-14T22:20:00Z" ||
+		profiles[0].LogonCount != 2 || profiles[1].LogonCount != 1 {
+		t.Errorf("lastLogons() = %+v", profiles)
+	}
+}
+
+// TestParseLogins_Cancelled verifies a cancelled read stops with ctx's error
+func TestParseLogins_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	logins, err := parseLogins(ctx, bytes.NewReader(utmpRecord(utmpUserProcess, "alice", "tty1", 1)))
+	if err != context.Canceled || len(logins) != 0 {
+		t.Errorf("parseLogins() = %v, %v; want context.Canceled", logins, err)
+	}
+}
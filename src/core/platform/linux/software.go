@@ -0,0 +1,139 @@
+package linux
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// dpkgStatusPath is the dpkg package database
+const dpkgStatusPath = "/var/lib/dpkg/status"
+
+// dpkgInfoDir holds one <package>.list file per installed package
+const dpkgInfoDir = "/var/lib/dpkg/info"
+
+// GetSoftwareInventory lists packages from dpkg and rpm (whichever exist)
+// dpkg's database is parsed directly; rpm is queried once for every package.
+// When rpm times out the dpkg packages are returned with ctx's error.
+// Complexity: O(p) where p = number of installed packages
+func (c *Collector) GetSoftwareInventory(ctx context.Context) (*types.SoftwareInfo, error) {
+	info := &types.SoftwareInfo{Packages: []types.Software{}}
+
+	if f, err := os.Open(dpkgStatusPath); err == nil {
+		pkgs, err := parseDpkgStatus(f, dpkgInstallDate)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		info.Packages = append(info.Packages, pkgs...)
+	}
+
+	if path, err := exec.LookPath("rpm"); err == nil {
+		out, err := exec.CommandContext(ctx, path, "-qa", "--queryformat",
+			"%{NAME}\t%{VERSION}-%{RELEASE}\t%{VENDOR}\t%{INSTALLTIME}\n").Output()
+		if err == nil {
+			info.Packages = append(info.Packages, parseRPMQuery(out)...)
+		}
+	}
+
+	sort.Slice(info.Packages, func(i, j int) bool {
+		a, b := info.Packages[i], info.Packages[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Version < b.Version
+	})
+	return info, ctx.Err()
+}
+
+// parseDpkgStatus returns the installed packages in a dpkg status file
+// installDate maps a package name to its install date ("" when unknown).
+// Complexity: O(|status file|)
+func parseDpkgStatus(r io.Reader, installDate func(name string) string) ([]types.Software, error) {
+	var pkgs []types.Software
+	var pkg types.Software
+	installed := false
+	flush := func() {
+		if installed && pkg.Name != "" {
+			pkg.Source = "dpkg"
+			pkg.InstallDate = installDate(pkg.Name)
+			pkgs = append(pkgs, pkg)
+		}
+		pkg, installed = types.Software{}, false
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024) // Long Description fields
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			flush()
+			continue
+		}
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok || strings.HasPrefix(line, " ") {
+			continue // Continuation line
+		}
+		switch key {
+		case "Package":
+			pkg.Name = value
+		case "Version":
+			pkg.Version = value
+		case "Maintainer":
+			pkg.Publisher = value
+		case "Status":
+			installed = strings.HasSuffix(value, " installed")
+		}
+	}
+	flush()
+	return pkgs, scanner.Err()
+}
+
+// dpkgInstallDate approximates a package's install date by its file list,
+// which dpkg rewrites on every install or upgrade
+func dpkgInstallDate(name string) string {
+	st, err := os.Stat(filepath.Join(dpkgInfoDir, name+".list"))
+	if err != nil {
+		// Multi-arch packages are listed as <name>:<arch>.list
+		matches, _ := filepath.Glob(filepath.Join(dpkgInfoDir, name+":*.list"))
+		if len(matches) == 0 {
+			return ""
+		}
+		if st, err = os.Stat(matches[0]); err != nil {
+			return ""
+		}
+	}
+	return st.ModTime().UTC().Format("2006-01-02")
+}
+
+// parseRPMQuery parses `rpm -qa` lines of name, version-release, vendor and
+// install time (Unix seconds), tab separated
+// Complexity: O(|out|)
+func parseRPMQuery(out []byte) []types.Software {
+	var pkgs []types.Software
+	for _, line := range bytes.Split(out, []byte("\n")) {
+		fields := strings.Split(string(line), "\t")
+		if len(fields) != 4 || fields[0] == "" || fields[0] == "gpg-pubkey" {
+			continue // gpg-pubkey entries are imported signing keys, not software
+		}
+		pkg := types.Software{Name: fields[0], Version: fields[1], Source: "rpm"}
+		if fields[2] != "(none)" {
+			pkg.Publisher = fields[2]
+		}
+		if secs, err := strconv.ParseInt(fields[3], 10, 64); err == nil && secs > 0 {
+			pkg.InstallDate = time.Unix(secs, 0).UTC().Format("2006-01-02")
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	return pkgs
+}
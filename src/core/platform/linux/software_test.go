@@ -0,0 +1,60 @@
+package linux
+
+import (
+	"strings"
+	"testing"
+)
+
+const dpkgStatus = `Package: openssl
+Status: install ok installed
+Priority: optional
+Maintainer: Ubuntu Developers <ubuntu-devel-discuss@lists.ubuntu.com>
+Version: 3.0.13-0ubuntu3
+Description: Secure Sockets Layer toolkit
+ This package contains the openssl binary.
+
+Package: removed-tool
+Status: deinstall ok config-files
+Version: 1.0
+
+Package: zlib1g
+Status: install ok installed
+Version: 1:1.3.dfsg-3.1ubuntu2
+`
+
+// TestParseDpkgStatus verifies only installed packages are listed, with continuation lines skipped
+func TestParseDpkgStatus(t *testing.T) {
+	pkgs, err := parseDpkgStatus(strings.NewReader(dpkgStatus), func(name string) string {
+		if name == "openssl" {
+			return "2025-04-01"
+		}
+		return ""
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pkgs) != 2 {
+		t.Fatalf("got %d packages, want 2: %+v", len(pkgs), pkgs)
+	}
+	if p := pkgs[0]; p.Name != "openssl" || p.Version != "3.0.13-0ubuntu3" || !strings.HasPrefix(p.Publisher, "Ubuntu Developers") || p.InstallDate != "2025-04-01" || p.Source != "dpkg" {
+		t.Errorf("pkgs[0] = %+v", p)
+	}
+	if p := pkgs[1]; p.Name != "zlib1g" || p.Version != "1:1.3.dfsg-3.1ubuntu2" {
+		t.Errorf("pkgs[1] = %+v", p)
+	}
+}
+
+// TestParseRPMQuery verifies vendors, install times and signing keys are handled
+func TestParseRPMQuery(t *testing.T) {
+	out := "bash\t5.2.26-3.fc40\tFedora Project\t1712000000\ngpg-pubkey\ta15b79cc-63d04c2c\t(none)\t1712000001\nlocal-tool\t1.0-1\t(none)\t0\n"
+	pkgs := parseRPMQuery([]byte(out))
+	if len(pkgs) != 2 {
+		t.Fatalf("got %d packages, want 2: %+v", len(pkgs), pkgs)
+	}
+	if p := pkgs[0]; p.Name != "bash" || p.Publisher != "Fedora Project" || p.InstallDate != "2024-04-01" || p.Source != "rpm" {
+		t.Errorf("pkgs[0] = %+v", p)
+	}
+	if p := pkgs[1]; p.Publisher != "" || p.InstallDate != "" {
+		t.Errorf("pkgs[1] = %+v", p)
+	}
+}
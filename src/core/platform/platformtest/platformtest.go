@@ -0,0 +1,197 @@
+// Package platformtest provides a configurable in-memory platform.Collector
+// Tests of the collection orchestrator and downstream users can script canned
+// facts, per-category delays and errors without OS-specific mocks or real
+// wmic/dscl/ip calls.
+package platformtest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/platform"
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// Category names one Collector method
+type Category string
+
+// Categories, matching the collection category names
+const (
+	SystemInfo   Category = "system_info"
+	NetworkInfo  Category = "network_info"
+	HardwareInfo Category = "hardware_info"
+	PIIInfo      Category = "pii_info"
+	Software     Category = "software_inventory"
+	ProcessInfo  Category = "process_info"
+	DiskInfo     Category = "disk_info"
+	Ports        Category = "listening_ports"
+	Security     Category = "security_products"
+	Patches      Category = "patch_level"
+)
+
+// Collector is a scriptable platform.Collector
+// Fields may be changed between calls but not during them. The returned
+// structs are shallow copies, so callers may set fields but must not modify
+// shared slices.
+type Collector struct {
+	System   *types.SystemInfo
+	Network  *types.NetworkInfo
+	Hardware *types.HardwareInfo
+	PII      *types.PIIInfo
+	Software *types.SoftwareInfo
+	Process  *types.ProcessInfo
+	Disk     *types.DiskInfo
+	Ports    *types.PortInfo
+	Security *types.SecurityInfo
+	Patches  *types.PatchInfo
+
+	Delay   map[Category]time.Duration // Waited (or cancelled) before answering
+	Err     map[Category]error         // Returned instead of the facts when set
+	Partial map[Category]bool          // Return the facts along with any error, like a timed-out collector
+
+	// Before, when set, runs first on every call; a non-nil error is returned.
+	// Use it to block until cancellation, panic, or signal a test.
+	Before func(ctx context.Context, c Category) error
+
+	mu    sync.Mutex
+	calls map[Category]int
+}
+
+var _ platform.Collector = (*Collector)(nil)
+
+// New returns a collector answering with platform.FakeCollector's facts
+// Complexity: O(1)
+func New() *Collector {
+	var fake platform.FakeCollector
+	ctx := context.Background()
+	sys, _ := fake.GetSystemInfo(ctx)
+	net, _ := fake.GetNetworkInfo(ctx)
+	hw, _ := fake.GetHardwareInfo(ctx)
+	pii, _ := fake.GetPIIInfo(ctx)
+	sw, _ := fake.GetSoftwareInventory(ctx)
+	proc, _ := fake.GetProcessInfo(ctx)
+	disk, _ := fake.GetDiskInfo(ctx)
+	ports, _ := fake.GetListeningPorts(ctx)
+	security, _ := fake.GetSecurityProducts(ctx)
+	patches, _ := fake.GetPatchInfo(ctx)
+	return &Collector{
+		System: sys, Network: net, Hardware: hw, PII: pii, Software: sw, Process: proc, Disk: disk, Ports: ports,
+		Security: security, Patches: patches,
+	}
+}
+
+// Calls returns how many times the category was requested
+func (c *Collector) Calls(cat Category) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls[cat]
+}
+
+// answer records the call, then applies the hook, delay and error for cat
+func (c *Collector) answer(ctx context.Context, cat Category) error {
+	c.mu.Lock()
+	if c.calls == nil {
+		c.calls = make(map[Category]int)
+	}
+	c.calls[cat]++
+	c.mu.Unlock()
+
+	if c.Before != nil {
+		if err := c.Before(ctx, cat); err != nil {
+			return err
+		}
+	}
+	if d := c.Delay[cat]; d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	// Without a delay the answer is instant, so a cancelled context does not
+	// fail it, matching a real collector that finished before the cancel
+	return c.Err[cat]
+}
+
+// GetSystemInfo returns a copy of System
+// Complexity: O(1) plus the configured delay
+func (c *Collector) GetSystemInfo(ctx context.Context) (*types.SystemInfo, error) {
+	return reply(c, ctx, SystemInfo, c.System)
+}
+
+// GetNetworkInfo returns a copy of Network
+// Complexity: O(1) plus the configured delay
+func (c *Collector) GetNetworkInfo(ctx context.Context) (*types.NetworkInfo, error) {
+	return reply(c, ctx, NetworkInfo, c.Network)
+}
+
+// GetHardwareInfo returns a copy of Hardware
+// Complexity: O(1) plus the configured delay
+func (c *Collector) GetHardwareInfo(ctx context.Context) (*types.HardwareInfo, error) {
+	return reply(c, ctx, HardwareInfo, c.Hardware)
+}
+
+// GetPIIInfo returns a copy of PII
+// Complexity: O(1) plus the configured delay
+func (c *Collector) GetPIIInfo(ctx context.Context) (*types.PIIInfo, error) {
+	return reply(c, ctx, PIIInfo, c.PII)
+}
+
+// GetSoftwareInventory returns a copy of Software
+// Complexity: O(1) plus the configured delay
+func (c *Collector) GetSoftwareInventory(ctx context.Context) (*types.SoftwareInfo, error) {
+	return reply(c, ctx, Software, c.Software)
+}
+
+// GetProcessInfo returns a copy of Process
+// Complexity: O(1) plus the configured delay
+func (c *Collector) GetProcessInfo(ctx context.Context) (*types.ProcessInfo, error) {
+	return reply(c, ctx, ProcessInfo, c.Process)
+}
+
+// GetDiskInfo returns a copy of Disk
+// Complexity: O(1) plus the configured delay
+func (c *Collector) GetDiskInfo(ctx context.Context) (*types.DiskInfo, error) {
+	return reply(c, ctx, DiskInfo, c.Disk)
+}
+
+// GetListeningPorts returns a copy of Ports
+// Complexity: O(1) plus the configured delay
+func (c *Collector) GetListeningPorts(ctx context.Context) (*types.PortInfo, error) {
+	return reply(c, ctx, Ports, c.Ports)
+}
+
+// GetSecurityProducts returns a copy of Security
+// Complexity: O(1) plus the configured delay
+func (c *Collector) GetSecurityProducts(ctx context.Context) (*types.SecurityInfo, error) {
+	return reply(c, ctx, Security, c.Security)
+}
+
+// GetPatchInfo returns a copy of Patches
+// Complexity: O(1) plus the configured delay
+func (c *Collector) GetPatchInfo(ctx context.Context) (*types.PatchInfo, error) {
+	return reply(c, ctx, Patches, c.Patches)
+}
+
+// reply answers cat with a copy of v, or with the error from answer
+func reply[T any](c *Collector, ctx context.Context, cat Category, v *T) (*T, error) {
+	if err := c.answer(ctx, cat); err != nil {
+		if c.Partial[cat] {
+			return clone(v), err
+		}
+		return nil, err
+	}
+	return clone(v), nil
+}
+
+// clone returns a shallow copy of v, or an empty value when v is nil
+func clone[T any](v *T) *T {
+	out := new(T)
+	if v != nil {
+		*out = *v
+	}
+	return out
+}
@@ -0,0 +1,50 @@
+package platformtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestCollector_Defaults verifies New answers with the fake facts
+func TestCollector_Defaults(t *testing.T) {
+	c := New()
+	sys, err := c.GetSystemInfo(context.Background())
+	if err != nil || sys.Hostname != "bench-host" {
+		t.Fatalf("GetSystemInfo() = %+v, %v", sys, err)
+	}
+	sys.Hostname = "changed"
+	if again, _ := c.GetSystemInfo(context.Background()); again.Hostname != "bench-host" {
+		t.Error("caller changes leaked into the canned facts")
+	}
+	if c.Calls(SystemInfo) != 2 || c.Calls(PIIInfo) != 0 {
+		t.Errorf("Calls = %d/%d, want 2/0", c.Calls(SystemInfo), c.Calls(PIIInfo))
+	}
+}
+
+// TestCollector_ErrAndDelay verifies injected errors and cancellable delays
+func TestCollector_ErrAndDelay(t *testing.T) {
+	boom := errors.New("wmic not found")
+	c := New()
+	c.Err = map[Category]error{HardwareInfo: boom}
+	c.Delay = map[Category]time.Duration{NetworkInfo: time.Hour}
+
+	if _, err := c.GetHardwareInfo(context.Background()); !errors.Is(err, boom) {
+		t.Errorf("GetHardwareInfo() error = %v, want %v", err, boom)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := c.GetNetworkInfo(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetNetworkInfo() error = %v, want DeadlineExceeded", err)
+	}
+}
+
+// TestCollector_NilFacts verifies unset categories answer empty values
+func TestCollector_NilFacts(t *testing.T) {
+	c := &Collector{}
+	pii, err := c.GetPIIInfo(context.Background())
+	if err != nil || pii == nil || len(pii.Users) != 0 {
+		t.Errorf("GetPIIInfo() = %+v, %v, want an empty value", pii, err)
+	}
+}
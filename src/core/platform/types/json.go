@@ -0,0 +1,142 @@
+package types
+
+import "github.com/minibeast/usb-agent/src/core/jsonenc"
+
+// WriteJSON writes u as encoding/json would
+// Complexity: O(|u|)
+func (u *User) WriteJSON(w *jsonenc.Writer) {
+	w.BeginObject()
+	w.StringField("username", u.Username)
+	if u.FullName != "" {
+		w.StringField("full_name", u.FullName)
+	}
+	if u.UID != "" {
+		w.StringField("uid", u.UID)
+	}
+	if u.Admin {
+		w.Key("admin")
+		w.Bool(true)
+	}
+	w.EndObject()
+}
+
+// WriteJSON writes p as encoding/json would
+// Complexity: O(|p|)
+func (p *UserProfile) WriteJSON(w *jsonenc.Writer) {
+	w.BeginObject()
+	w.StringField("username", p.Username)
+	w.StringField("last_logon", p.LastLogon)
+	if p.LogonCount != 0 {
+		w.Key("logon_count")
+		w.Int(int64(p.LogonCount))
+	}
+	w.EndObject()
+}
+
+// WriteJSON writes n as encoding/json would
+// Complexity: O(|n|)
+func (n *NetworkInterface) WriteJSON(w *jsonenc.Writer) {
+	w.BeginObject()
+	w.StringField("name", n.Name)
+	w.StringField("ip_address", n.IPAddress)
+	w.StringField("mac_address", n.MACAddress)
+	w.EndObject()
+}
+
+// WriteJSON writes s as encoding/json would
+// Complexity: O(|s|)
+func (s *Software) WriteJSON(w *jsonenc.Writer) {
+	w.BeginObject()
+	w.StringField("name", s.Name)
+	if s.Version != "" {
+		w.StringField("version", s.Version)
+	}
+	if s.Publisher != "" {
+		w.StringField("publisher", s.Publisher)
+	}
+	if s.InstallDate != "" {
+		w.StringField("install_date", s.InstallDate)
+	}
+	w.StringField("source", s.Source)
+	w.EndObject()
+}
+
+// WriteJSON writes p as encoding/json would
+// Complexity: O(|p|)
+func (p *Process) WriteJSON(w *jsonenc.Writer) {
+	w.BeginObject()
+	w.Key("pid")
+	w.Int(int64(p.PID))
+	w.StringField("name", p.Name)
+	if p.Path != "" {
+		w.StringField("path", p.Path)
+	}
+	if p.User != "" {
+		w.StringField("user", p.User)
+	}
+	w.EndObject()
+}
+
+// WriteJSON writes p as encoding/json would
+// Complexity: O(|p|)
+func (p *ListeningPort) WriteJSON(w *jsonenc.Writer) {
+	w.BeginObject()
+	w.StringField("protocol", p.Protocol)
+	w.StringField("address", p.Address)
+	w.Key("port")
+	w.Int(int64(p.Port))
+	if p.PID != 0 {
+		w.Key("pid")
+		w.Int(int64(p.PID))
+	}
+	if p.Process != "" {
+		w.StringField("process", p.Process)
+	}
+	w.EndObject()
+}
+
+// WriteJSON writes u as encoding/json would
+// Complexity: O(|u|)
+func (u *Update) WriteJSON(w *jsonenc.Writer) {
+	w.BeginObject()
+	w.StringField("id", u.ID)
+	if u.Title != "" {
+		w.StringField("title", u.Title)
+	}
+	if u.InstalledOn != "" {
+		w.StringField("installed_on", u.InstalledOn)
+	}
+	w.EndObject()
+}
+
+// WriteJSON writes p as encoding/json would
+// Complexity: O(|p|)
+func (p *SecurityProduct) WriteJSON(w *jsonenc.Writer) {
+	w.BeginObject()
+	w.StringField("name", p.Name)
+	w.StringField("kind", p.Kind)
+	if p.Version != "" {
+		w.StringField("version", p.Version)
+	}
+	w.StringField("real_time_protection", p.RealTime)
+	w.EndObject()
+}
+
+// WriteJSON writes v as encoding/json would
+// Complexity: O(|v|)
+func (v *Volume) WriteJSON(w *jsonenc.Writer) {
+	w.BeginObject()
+	w.StringField("mount_point", v.MountPoint)
+	if v.Device != "" {
+		w.StringField("device", v.Device)
+	}
+	if v.FileSystem != "" {
+		w.StringField("filesystem", v.FileSystem)
+	}
+	w.Key("total_bytes")
+	w.Int(v.TotalBytes)
+	w.Key("free_bytes")
+	w.Int(v.FreeBytes)
+	w.StringField("encryption", v.Encryption)
+	w.EndObject()
+}
@@ -1,5 +1,7 @@
 package types
 
+import "sort"
+
 // SystemInfo contains operating system information
 type SystemInfo struct {
 	OSName    string `json:"os_name"`    // "Windows", "Darwin", "Linux"
@@ -28,6 +30,154 @@ type HardwareInfo struct {
 	HardwareUUID string `json:"hardware_uuid"` // Hardware UUID
 }
 
+// SoftwareInfo contains the installed software inventory
+type SoftwareInfo struct {
+	Packages []Software `json:"packages"` // Sorted by name, then version
+}
+
+// Software is one installed application or package
+type Software struct {
+	Name        string `json:"name"`
+	Version     string `json:"version,omitempty"`
+	Publisher   string `json:"publisher,omitempty"`    // Vendor, maintainer or packager
+	InstallDate string `json:"install_date,omitempty"` // YYYY-MM-DD when the OS records it
+	Source      string `json:"source"`                 // dpkg, rpm, registry, app or pkgutil
+}
+
+// ProcessInfo contains the running processes
+type ProcessInfo struct {
+	Processes []Process `json:"processes"` // Sorted by PID
+}
+
+// Process is one running process
+type Process struct {
+	PID  int    `json:"pid"`
+	Name string `json:"name"`
+	Path string `json:"path,omitempty"` // Executable path (empty when unreadable)
+	User string `json:"user,omitempty"` // Owning account (empty when unreadable)
+}
+
+// PortInfo contains the listening sockets
+type PortInfo struct {
+	Ports []ListeningPort `json:"ports"` // Sorted by protocol, port, then address
+}
+
+// ListeningPort is one socket accepting connections (TCP) or datagrams (UDP)
+type ListeningPort struct {
+	Protocol string `json:"protocol"` // tcp or udp
+	Address  string `json:"address"`  // Bound address (0.0.0.0 or :: = every interface)
+	Port     int    `json:"port"`
+	PID      int    `json:"pid,omitempty"`     // Owning process (0 when unreadable)
+	Process  string `json:"process,omitempty"` // Owning process name (empty when unreadable)
+}
+
+// SortPorts orders ports by protocol, port, then address
+// Complexity: O(n log n)
+func SortPorts(ports []ListeningPort) {
+	sort.Slice(ports, func(i, j int) bool {
+		a, b := ports[i], ports[j]
+		if a.Protocol != b.Protocol {
+			return a.Protocol < b.Protocol
+		}
+		if a.Port != b.Port {
+			return a.Port < b.Port
+		}
+		if a.Address != b.Address {
+			return a.Address < b.Address
+		}
+		return a.PID < b.PID
+	})
+}
+
+// SecurityInfo contains the installed antivirus and EDR products
+type SecurityInfo struct {
+	Products []SecurityProduct `json:"products"` // Sorted by name
+}
+
+// SecurityProduct is one installed antivirus or EDR (endpoint detection and
+// response) product
+type SecurityProduct struct {
+	Name     string `json:"name"`
+	Kind     string `json:"kind"`                 // One of the Product* values
+	Version  string `json:"version,omitempty"`    // Empty when unreadable
+	RealTime string `json:"real_time_protection"` // One of the RealTime* values
+}
+
+// Product kinds reported in SecurityProduct.Kind
+const (
+	ProductAntivirus = "antivirus"
+	ProductEDR       = "edr"
+)
+
+// Real-time protection states reported in SecurityProduct.RealTime
+const (
+	RealTimeOn      = "on"
+	RealTimeOff     = "off"
+	RealTimeUnknown = "unknown" // Not determinable (e.g., the agent's process list is unreadable)
+)
+
+// SortProducts orders products by name, then kind
+// Complexity: O(n log n)
+func SortProducts(products []SecurityProduct) {
+	sort.Slice(products, func(i, j int) bool {
+		a, b := products[i], products[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Kind < b.Kind
+	})
+}
+
+// PatchInfo contains the operating system's update state
+type PatchInfo struct {
+	Updates    []Update `json:"updates"`               // Installed updates, sorted newest first
+	LastUpdate string   `json:"last_update,omitempty"` // YYYY-MM-DD of the latest OS update (empty when unknown)
+	Pending    *int     `json:"pending,omitempty"`     // Updates available but not installed (nil when unknown)
+}
+
+// Update is one installed OS update or hotfix
+type Update struct {
+	ID          string `json:"id"`                     // KB article (Windows) or update name (macOS)
+	Title       string `json:"title,omitempty"`        // Description (Windows) or version (macOS)
+	InstalledOn string `json:"installed_on,omitempty"` // YYYY-MM-DD (empty when unknown)
+}
+
+// SortUpdates orders updates newest first, then by ID (undated last)
+// Complexity: O(n log n)
+func SortUpdates(updates []Update) {
+	sort.Slice(updates, func(i, j int) bool {
+		a, b := updates[i], updates[j]
+		if a.InstalledOn != b.InstalledOn {
+			return a.InstalledOn > b.InstalledOn
+		}
+		return a.ID < b.ID
+	})
+}
+
+// DiskInfo contains the mounted volumes
+type DiskInfo struct {
+	Volumes []Volume `json:"volumes"` // Sorted by mount point
+}
+
+// Volume is one mounted filesystem
+type Volume struct {
+	MountPoint string `json:"mount_point"` // Mount path or drive letter (C:)
+	Device     string `json:"device,omitempty"`
+	FileSystem string `json:"filesystem,omitempty"`
+	TotalBytes int64  `json:"total_bytes"`
+	FreeBytes  int64  `json:"free_bytes"`
+	Encryption string `json:"encryption"` // One of the Encryption* values
+}
+
+// Full-disk encryption states reported in Volume.Encryption
+const (
+	EncryptionLUKS      = "luks"
+	EncryptionFileVault = "filevault"
+	EncryptionBitLocker = "bitlocker"
+	EncryptionNone      = "none"
+	EncryptionUnknown   = "unknown" // Not determinable (e.g., requires administrator rights)
+)
+
 // PIIInfo contains personally identifiable information
 type PIIInfo struct {
 	Users          []User        `json:"users"`           // Local user accounts, sorted by username
@@ -42,11 +192,12 @@ type User struct {
 	Username string `json:"username"`
 	FullName string `json:"full_name,omitempty"` // Display name
 	UID      string `json:"uid,omitempty"`       // Unix UID or Windows SID
+	Admin    bool   `json:"admin,omitempty"`     // Local administrator (root, sudo/wheel/admin group, Administrators)
 }
 
 // UserProfile represents login activity
 type UserProfile struct {
 	Username   string `json:"username"`
 	LastLogon  string `json:"last_logon"`            // ISO 8601 timestamp
-	LogonCount int    `json:"logon_count,omitempty"` // Windows only
+	LogonCount int    `json:"logon_count,omitempty"` // Windows; Linux counts wtmp logins
 }
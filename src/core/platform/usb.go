@@ -0,0 +1,106 @@
+package platform
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/minibeast/usb-agent/src/core/usbwatch"
+)
+
+// OutputSource says how an output directory was resolved
+type OutputSource string
+
+const (
+	OutputOverride OutputSource = "override" // Explicit root (output.root or -output-root)
+	OutputAbsolute OutputSource = "absolute" // output.directory is already absolute
+	OutputUSB      OutputSource = "usb"      // Below the drive the binary runs from
+	OutputCWD      OutputSource = "cwd"      // Fallback: below the working directory
+)
+
+// USBLocator finds the removable drive the agent binary runs from
+// Output written there travels with the stick even when the agent is started
+// from another working directory (a desktop shortcut, a file manager).
+type USBLocator struct {
+	// Platform hooks (replaced in tests)
+	executable func() (string, error)
+	removable  func(path string) bool
+	getwd      func() (string, error)
+}
+
+// NewUSBLocator creates a locator for the running binary
+// Complexity: O(1)
+func NewUSBLocator() *USBLocator {
+	return &USBLocator{executable: os.Executable, removable: usbwatch.IsRemovable, getwd: os.Getwd}
+}
+
+// Root returns the root of the removable drive holding the running binary
+// The nearest ancestor of the executable carrying the stick layout wins (a
+// stick may keep MiniBeast in a subdirectory); otherwise the drive's mount
+// point: the topmost ancestor still on removable media.
+// Complexity: O(depth of the executable path)
+func (l *USBLocator) Root() (string, bool) {
+	exe, err := l.executable()
+	if err != nil {
+		return "", false
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+
+	mount := ""
+	for dir := filepath.Dir(exe); ; {
+		if !l.removable(dir) {
+			break
+		}
+		if usbwatch.IsAgentVolume(dir) {
+			return dir, true
+		}
+		mount = dir
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return mount, mount != ""
+}
+
+// ResolveOutput returns the absolute directory artifacts are written to
+// A relative dir is joined to override when set, else to the USB root, else
+// to the working directory; an absolute dir is used as is.
+// Complexity: O(depth of the executable path)
+func (l *USBLocator) ResolveOutput(dir, override string) (string, OutputSource, error) {
+	if filepath.IsAbs(dir) {
+		return filepath.Clean(dir), OutputAbsolute, nil
+	}
+	if override != "" {
+		root, err := filepath.Abs(override)
+		if err != nil {
+			return "", "", err
+		}
+		return filepath.Join(root, dir), OutputOverride, nil
+	}
+	if root, ok := l.Root(); ok {
+		return filepath.Join(root, dir), OutputUSB, nil
+	}
+	cwd, err := l.getwd()
+	if err != nil {
+		return "", "", err
+	}
+	return filepath.Join(cwd, dir), OutputCWD, nil
+}
+
+// ResolvePath returns a relative config path joined to the USB root
+// Files shipped on the stick (remediation.yaml) are then found wherever the
+// agent was started from. Without a stick, and for absolute paths, path is
+// returned unchanged.
+// Complexity: O(depth of the executable path)
+func (l *USBLocator) ResolvePath(path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	if root, ok := l.Root(); ok {
+		return filepath.Join(root, path)
+	}
+	return path
+}
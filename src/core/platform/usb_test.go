@@ -0,0 +1,100 @@
+package platform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newLocator returns a locator for a binary at exe with removable media below usb
+func newLocator(exe, usb string) *USBLocator {
+	return &USBLocator{
+		executable: func() (string, error) { return exe, nil },
+		removable: func(path string) bool {
+			return usb != "" && (path == usb || strings.HasPrefix(path, usb+string(filepath.Separator)))
+		},
+		getwd: func() (string, error) { return filepath.FromSlash("/home/analyst"), nil },
+	}
+}
+
+func TestUSBLocator_Root(t *testing.T) {
+	usb := t.TempDir()
+	bin := filepath.Join(usb, "tools", "minibeast", "bin")
+	if err := os.MkdirAll(bin, 0755); err != nil {
+		t.Fatal(err)
+	}
+	exe := filepath.Join(bin, "minibeast")
+
+	// No stick layout: the drive's mount point
+	if root, ok := newLocator(exe, usb).Root(); !ok || root != usb {
+		t.Errorf("Root() = %q, %v; want %q", root, ok, usb)
+	}
+
+	// Stick layout in a subdirectory wins
+	stick := filepath.Join(usb, "tools", "minibeast")
+	if err := os.MkdirAll(filepath.Join(stick, "config"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(stick, "config", "default.yaml"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if root, ok := newLocator(exe, usb).Root(); !ok || root != stick {
+		t.Errorf("Root() = %q, %v; want %q", root, ok, stick)
+	}
+
+	// Same layout on a fixed disk
+	if root, ok := newLocator(exe, "").Root(); ok {
+		t.Errorf("Root() on a fixed disk = %q", root)
+	}
+}
+
+func TestUSBLocator_ResolveOutput(t *testing.T) {
+	usb := t.TempDir()
+	exe := filepath.Join(usb, "minibeast")
+	abs := filepath.Join(t.TempDir(), "evidence")
+	override := filepath.Join(t.TempDir(), "case-42")
+
+	tests := []struct {
+		name     string
+		locator  *USBLocator
+		dir      string
+		override string
+		want     string
+		source   OutputSource
+	}{
+		{"usb", newLocator(exe, usb), "out", "", filepath.Join(usb, "out"), OutputUSB},
+		{"cwd fallback", newLocator(exe, ""), "out", "", filepath.Join(filepath.FromSlash("/home/analyst"), "out"), OutputCWD},
+		{"override", newLocator(exe, usb), "out", override, filepath.Join(override, "out"), OutputOverride},
+		{"absolute", newLocator(exe, usb), abs, override, abs, OutputAbsolute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, source, err := tt.locator.ResolveOutput(tt.dir, tt.override)
+			if err != nil {
+				t.Fatalf("ResolveOutput() failed: %v", err)
+			}
+			if got != tt.want || source != tt.source {
+				t.Errorf("ResolveOutput() = %q (%s), want %q (%s)", got, source, tt.want, tt.source)
+			}
+		})
+	}
+}
+
+func TestUSBLocator_ResolvePath(t *testing.T) {
+	usb := t.TempDir()
+	exe := filepath.Join(usb, "minibeast")
+	abs := filepath.Join(t.TempDir(), "remediation.yaml")
+	rel := filepath.Join("config", "remediation.yaml")
+
+	if got := newLocator(exe, usb).ResolvePath(rel); got != filepath.Join(usb, rel) {
+		t.Errorf("ResolvePath() on a stick = %q", got)
+	}
+	if got := newLocator(exe, "").ResolvePath(rel); got != rel {
+		t.Errorf("ResolvePath() on a fixed disk = %q, want %q", got, rel)
+	}
+	if got := newLocator(exe, usb).ResolvePath(abs); got != abs {
+		t.Errorf("ResolvePath() of an absolute path = %q", got)
+	}
+}
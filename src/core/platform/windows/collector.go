@@ -35,8 +35,8 @@ func (c *Collector) GetSystemInfo(ctx context.Context) (*types.SystemInfo, error
 		info.Hostname = "unknown"
 	}
 
-	// Get Windows version using systeminfo
-	if version, err := c.getWindowsVersion(); err == nil {
+	// Get Windows version using ver
+	if version, err := c.getWindowsVersion(ctx); err == nil {
 		info.OSVersion = version
 	} else {
 		info.OSVersion = "unknown"
@@ -52,7 +52,7 @@ func (c *Collector) GetSystemInfo(ctx context.Context) (*types.SystemInfo, error
 	// Get timezone
 	info.Timezone = time.Local.String()
 
-	return info, nil
+	return info, ctx.Err()
 }
 
 // GetNetworkInfo retrieves Windows network configuration
@@ -64,13 +64,13 @@ func (c *Collector) GetNetworkInfo(ctx context.Context) (*types.NetworkInfo, err
 	}
 
 	// Get network interfaces using ipconfig
-	interfaces, err := c.getNetworkInterfaces()
+	interfaces, err := c.getNetworkInterfaces(ctx)
 	if err == nil {
 		info.Interfaces = interfaces
 	}
 
 	// Get WiFi SSIDs using netsh
-	ssids, err := c.getWiFiSSIDs()
+	ssids, err := c.getWiFiSSIDs(ctx)
 	if err == nil {
 		info.WiFiSSIDs = ssids
 	}
@@ -81,7 +81,7 @@ func (c *Collector) GetNetworkInfo(ctx context.Context) (*types.NetworkInfo, err
 	})
 	sort.Strings(info.WiFiSSIDs)
 
-	return info, nil
+	return info, ctx.Err()
 }
 
 // GetHardwareInfo retrieves Windows hardware identifiers
@@ -92,14 +92,14 @@ func (c *Collector) GetHardwareInfo(ctx context.Context) (*types.HardwareInfo, e
 		HardwareUUID: "unknown",
 	}
 
-	// Get hardware UUID using wmic
-	if uuid, err := c.getHardwareUUID(); err == nil {
-		info.HardwareUUID = uuid
-	}
-
-	// Get serial number using wmic
-	if serial, err := c.getSerialNumber(); err == nil {
-		info.SerialNumber = serial
+	// Read both from the SMBIOS system information structure
+	if uuid, serial, err := c.getHardwareIDs(); err == nil {
+		if uuid != "" {
+			info.HardwareUUID = uuid
+		}
+		if serial != "" {
+			info.SerialNumber = serial
+		}
 	}
 
 	return info, nil
@@ -116,7 +116,7 @@ func (c *Collector) GetPIIInfo(ctx context.Context) (*types.PIIInfo, error) {
 		PrimaryEmail:   "unknown",
 	}
 
-	// Get local users using wmic
+	// Get local users via NetUserEnum
 	users, err := c.getLocalUsers()
 	if err == nil {
 		info.Users = users
@@ -145,8 +145,8 @@ func (c *Collector) GetPIIInfo(ctx context.Context) (*types.PIIInfo, error) {
 
 // Helper functions
 
-func (c *Collector) getWindowsVersion() (string, error) {
-	cmd := exec.Command("cmd", "/c", "ver")
+func (c *Collector) getWindowsVersion(ctx context.Context) (string, error) {
+	cmd := exec.CommandContext(ctx, "cmd", "/c", "ver")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", err
@@ -165,30 +165,17 @@ func (c *Collector) getWindowsVersion() (string, error) {
 	return version, nil
 }
 
+// getBuildNumber reads the build from the registry (wmic is gone from
+// current Windows 11 builds)
 func (c *Collector) getBuildNumber() (string, error) {
-	// Use wmic to get build number
-	cmd := exec.Command("wmic", "os", "get", "BuildNumber", "/value")
-	output, err := cmd.Output()
-	if err != nil {
-		return "", err
-	}
-
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "BuildNumber=") {
-			return strings.TrimPrefix(line, "BuildNumber="), nil
-		}
-	}
-
-	return "", nil
+	return readBuildNumber()
 }
 
-func (c *Collector) getNetworkInterfaces() ([]types.NetworkInterface, error) {
+func (c *Collector) getNetworkInterfaces(ctx context.Context) ([]types.NetworkInterface, error) {
 	interfaces := []types.NetworkInterface{}
 
 	// Use ipconfig /all to get network information
-	cmd := exec.Command("ipconfig", "/all")
+	cmd := exec.CommandContext(ctx, "ipconfig", "/all")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, err
@@ -241,11 +228,11 @@ func (c *Collector) getNetworkInterfaces() ([]types.NetworkInterface, error) {
 	return interfaces, nil
 }
 
-func (c *Collector) getWiFiSSIDs() ([]string, error) {
+func (c *Collector) getWiFiSSIDs(ctx context.Context) ([]string, error) {
 	ssids := []string{}
 
 	// Use netsh to get WiFi profiles
-	cmd := exec.Command("netsh", "wlan", "show", "profiles")
+	cmd := exec.CommandContext(ctx, "netsh", "wlan", "show", "profiles")
 	output, err := cmd.Output()
 	if err != nil {
 		return ssids, nil // Best-effort, not fatal
@@ -268,78 +255,28 @@ func (c *Collector) getWiFiSSIDs() ([]string, error) {
 	return ssids, nil
 }
 
-func (c *Collector) getHardwareUUID() (string, error) {
-	cmd := exec.Command("wmic", "csproduct", "get", "UUID", "/value")
-	output, err := cmd.Output()
-	if err != nil {
-		return "", err
-	}
-
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "UUID=") {
-			return strings.TrimPrefix(line, "UUID="), nil
-		}
-	}
-
-	return "", nil
-}
-
-func (c *Collector) getSerialNumber() (string, error) {
-	cmd := exec.Command("wmic", "bios", "get", "serialnumber", "/value")
-	output, err := cmd.Output()
+// getHardwareIDs returns the SMBIOS system UUID and serial number, the
+// values WMI reports as Win32_ComputerSystemProduct.UUID and
+// Win32_BIOS.SerialNumber
+func (c *Collector) getHardwareIDs() (uuid, serial string, err error) {
+	data, err := readSMBIOS()
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
-
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "SerialNumber=") {
-			return strings.TrimPrefix(line, "SerialNumber="), nil
-		}
-	}
-
-	return "", nil
+	return parseSMBIOS(data)
 }
 
+// getLocalUsers lists local accounts, skipping SYSTEM-prefixed names
 func (c *Collector) getLocalUsers() ([]types.User, error) {
-	users := []types.User{}
-
-	cmd := exec.Command("wmic", "useraccount", "get", "name,fullname,sid", "/format:csv")
-	output, err := cmd.Output()
+	all, err := enumLocalUsers()
 	if err != nil {
 		return nil, err
 	}
-
-	lines := strings.Split(string(output), "\n")
-	for i, line := range lines {
-		if i < 2 {
-			continue // Skip header rows
-		}
-
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-
-		fields := strings.Split(line, ",")
-		if len(fields) >= 4 {
-			username := strings.TrimSpace(fields[2])
-			fullname := strings.TrimSpace(fields[1])
-			sid := strings.TrimSpace(fields[3])
-
-			// Filter out system accounts
-			if username != "" && !strings.HasPrefix(username, "SYSTEM") {
-				users = append(users, types.User{
-					Username: username,
-					FullName: fullname,
-					UID:      sid,
-				})
-			}
+	users := []types.User{}
+	for _, u := range all {
+		if u.Username != "" && !strings.HasPrefix(u.Username, "SYSTEM") {
+			users = append(users, u)
 		}
 	}
-
 	return users, nil
 }
@@ -0,0 +1,59 @@
+package windows
+
+import (
+	"context"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// GetDiskInfo lists fixed and removable drives, with BitLocker state from
+// manage-bde
+// manage-bde needs administrator rights; without them every drive reports
+// EncryptionUnknown.
+// Complexity: O(v) where v = number of volumes
+func (c *Collector) GetDiskInfo(ctx context.Context) (*types.DiskInfo, error) {
+	vols, err := listVolumes()
+	if err != nil {
+		return nil, err
+	}
+
+	states := map[string]string{}
+	if status, err := exec.CommandContext(ctx, "manage-bde", "-status").Output(); err == nil {
+		states = parseManageBDE(string(status))
+	}
+	for i := range vols {
+		vols[i].Encryption = types.EncryptionUnknown
+		if state, ok := states[vols[i].MountPoint]; ok {
+			vols[i].Encryption = state
+		}
+	}
+	sort.Slice(vols, func(i, j int) bool { return vols[i].MountPoint < vols[j].MountPoint })
+	return &types.DiskInfo{Volumes: vols}, nil
+}
+
+// parseManageBDE maps drive letters to their encryption state from
+// `manage-bde -status` ("Volume C: [OS]" blocks with a Conversion Status line)
+// Complexity: O(|out|)
+func parseManageBDE(out string) map[string]string {
+	states := map[string]string{}
+	drive := ""
+	for _, line := range strings.Split(strings.ReplaceAll(out, "\r\n", "\n"), "\n") {
+		if rest, ok := strings.CutPrefix(line, "Volume "); ok {
+			drive, _, _ = strings.Cut(rest, " ")
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || drive == "" || strings.TrimSpace(key) != "Conversion Status" {
+			continue
+		}
+		if strings.TrimSpace(value) == "Fully Decrypted" {
+			states[drive] = types.EncryptionNone
+		} else {
+			states[drive] = types.EncryptionBitLocker // Encrypted or converting
+		}
+	}
+	return states
+}
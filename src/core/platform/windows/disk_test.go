@@ -0,0 +1,21 @@
+package windows
+
+import (
+	"testing"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// TestParseManageBDE verifies encrypted, converting and decrypted volumes
+func TestParseManageBDE(t *testing.T) {
+	out := "BitLocker Drive Encryption: Configuration Tool version 10.0.22621\r\n" +
+		"Volume C: [OS]\r\n[OS Volume]\r\n\r\n" +
+		"    Conversion Status:    Fully Encrypted\r\n" +
+		"    Protection Status:    Protection On\r\n\r\n" +
+		"Volume E: [USB]\r\n[Data Volume]\r\n\r\n" +
+		"    Conversion Status:    Fully Decrypted\r\n"
+	states := parseManageBDE(out)
+	if states["C:"] != types.EncryptionBitLocker || states["E:"] != types.EncryptionNone || len(states) != 2 {
+		t.Errorf("states = %v", states)
+	}
+}
@@ -0,0 +1,22 @@
+//go:build !windows
+
+package windows
+
+import (
+	"errors"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// errNotWindows is returned by the native queries when built for another OS
+// (the package still builds everywhere so its parsers can be tested)
+var errNotWindows = errors.New("windows: native API unavailable on this platform")
+
+func readBuildNumber() (string, error)        { return "", errNotWindows }
+func readSMBIOS() ([]byte, error)             { return nil, errNotWindows }
+func enumLocalUsers() ([]types.User, error)   { return nil, errNotWindows }
+func listProcesses() ([]types.Process, error) { return nil, errNotWindows }
+func listVolumes() ([]types.Volume, error)    { return nil, errNotWindows }
+
+func processNames() (map[int]string, error)                   { return nil, errNotWindows }
+func readSocketTable(udp bool, family uint32) ([]byte, error) { return nil, errNotWindows }
@@ -0,0 +1,256 @@
+//go:build windows
+
+package windows
+
+import (
+	"fmt"
+	"strconv"
+	"unsafe"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+var (
+	kernel32                   = windows.NewLazySystemDLL("kernel32.dll")
+	procGetSystemFirmwareTable = kernel32.NewProc("GetSystemFirmwareTable")
+
+	netapi32           = windows.NewLazySystemDLL("netapi32.dll")
+	procNetUserEnum    = netapi32.NewProc("NetUserEnum")
+	procNetUserGetInfo = netapi32.NewProc("NetUserGetInfo")
+
+	iphlpapi                = windows.NewLazySystemDLL("iphlpapi.dll")
+	procGetExtendedTcpTable = iphlpapi.NewProc("GetExtendedTcpTable")
+	procGetExtendedUdpTable = iphlpapi.NewProc("GetExtendedUdpTable")
+)
+
+// currentVersionKey holds the OS version and build numbers
+const currentVersionKey = `SOFTWARE\Microsoft\Windows NT\CurrentVersion`
+
+// readBuildNumber returns the OS build with its update revision (22631.4460)
+func readBuildNumber() (string, error) {
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, currentVersionKey, registry.QUERY_VALUE)
+	if err != nil {
+		return "", err
+	}
+	defer k.Close()
+
+	build, _, err := k.GetStringValue("CurrentBuildNumber")
+	if err != nil {
+		return "", err
+	}
+	if ubr, _, err := k.GetIntegerValue("UBR"); err == nil {
+		build += "." + strconv.FormatUint(ubr, 10)
+	}
+	return build, nil
+}
+
+// readSMBIOS returns the raw SMBIOS table ('RSMB' provider)
+func readSMBIOS() ([]byte, error) {
+	const rsmb = 'R'<<24 | 'S'<<16 | 'M'<<8 | 'B'
+	size, _, err := procGetSystemFirmwareTable.Call(rsmb, 0, 0, 0)
+	if size == 0 {
+		return nil, fmt.Errorf("GetSystemFirmwareTable: %w", err)
+	}
+	buf := make([]byte, size)
+	n, _, err := procGetSystemFirmwareTable.Call(rsmb, 0, uintptr(unsafe.Pointer(&buf[0])), size)
+	if n == 0 || n > size {
+		return nil, fmt.Errorf("GetSystemFirmwareTable: %w", err)
+	}
+	return buf[:n], nil
+}
+
+// userInfo23 mirrors USER_INFO_23
+type userInfo23 struct {
+	Name     *uint16
+	FullName *uint16
+	Comment  *uint16
+	Flags    uint32
+	SID      *windows.SID
+}
+
+// enumLocalUsers lists local accounts via NetUserEnum (level 23)
+func enumLocalUsers() ([]types.User, error) {
+	const (
+		filterNormalAccount = 0x0002
+		maxPreferredLength  = 0xFFFFFFFF
+		errorMoreData       = 234
+	)
+	users := []types.User{}
+	var resume uint32
+	for {
+		var buf *byte
+		var read, total uint32
+		status, _, _ := procNetUserEnum.Call(0, 23, filterNormalAccount,
+			uintptr(unsafe.Pointer(&buf)), maxPreferredLength,
+			uintptr(unsafe.Pointer(&read)), uintptr(unsafe.Pointer(&total)),
+			uintptr(unsafe.Pointer(&resume)))
+		if status != 0 && status != errorMoreData {
+			return nil, fmt.Errorf("NetUserEnum: %w", windows.Errno(status))
+		}
+		if buf != nil {
+			for _, u := range unsafe.Slice((*userInfo23)(unsafe.Pointer(buf)), read) {
+				user := types.User{
+					Username: windows.UTF16PtrToString(u.Name),
+					FullName: windows.UTF16PtrToString(u.FullName),
+				}
+				if u.SID != nil {
+					user.UID = u.SID.String()
+				}
+				user.Admin = userIsAdmin(u.Name)
+				users = append(users, user)
+			}
+			windows.NetApiBufferFree(buf)
+		}
+		if status != errorMoreData {
+			return users, nil
+		}
+	}
+}
+
+// userInfo1 mirrors USER_INFO_1
+type userInfo1 struct {
+	Name        *uint16
+	Password    *uint16
+	PasswordAge uint32
+	Priv        uint32
+	HomeDir     *uint16
+	Comment     *uint16
+	Flags       uint32
+	ScriptPath  *uint16
+}
+
+// userIsAdmin reports whether the account has administrator privilege
+// (USER_INFO_1 usri1_priv, set by membership of Administrators)
+func userIsAdmin(name *uint16) bool {
+	const userPrivAdmin = 2
+	var buf *byte
+	status, _, _ := procNetUserGetInfo.Call(0, uintptr(unsafe.Pointer(name)), 1, uintptr(unsafe.Pointer(&buf)))
+	if status != 0 || buf == nil {
+		return false
+	}
+	defer windows.NetApiBufferFree(buf)
+	return (*userInfo1)(unsafe.Pointer(buf)).Priv == userPrivAdmin
+}
+
+// listProcesses walks a Toolhelp snapshot, resolving each process's image
+// path and token owner where access allows
+func listProcesses() ([]types.Process, error) {
+	snap, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer windows.CloseHandle(snap)
+
+	procs := []types.Process{}
+	entry := windows.ProcessEntry32{Size: uint32(unsafe.Sizeof(windows.ProcessEntry32{}))}
+	for err = windows.Process32First(snap, &entry); err == nil; err = windows.Process32Next(snap, &entry) {
+		proc := types.Process{PID: int(entry.ProcessID), Name: windows.UTF16ToString(entry.ExeFile[:])}
+		proc.Path, proc.User = processDetails(entry.ProcessID)
+		procs = append(procs, proc)
+	}
+	return procs, nil
+}
+
+// processNames maps PIDs to image names from a Toolhelp snapshot, without
+// opening the processes
+func processNames() (map[int]string, error) {
+	snap, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer windows.CloseHandle(snap)
+
+	names := map[int]string{}
+	entry := windows.ProcessEntry32{Size: uint32(unsafe.Sizeof(windows.ProcessEntry32{}))}
+	for err = windows.Process32First(snap, &entry); err == nil; err = windows.Process32Next(snap, &entry) {
+		names[int(entry.ProcessID)] = windows.UTF16ToString(entry.ExeFile[:])
+	}
+	return names, nil
+}
+
+// processDetails returns a process's image path and DOMAIN\user owner
+// ("" for either when the process cannot be opened)
+func processDetails(pid uint32) (path, owner string) {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err != nil {
+		return "", ""
+	}
+	defer windows.CloseHandle(h)
+
+	buf := make([]uint16, windows.MAX_LONG_PATH)
+	size := uint32(len(buf))
+	if windows.QueryFullProcessImageName(h, 0, &buf[0], &size) == nil {
+		path = windows.UTF16ToString(buf[:size])
+	}
+
+	var token windows.Token
+	if windows.OpenProcessToken(h, windows.TOKEN_QUERY, &token) == nil {
+		defer token.Close()
+		if tu, err := token.GetTokenUser(); err == nil {
+			if account, domain, _, err := tu.User.Sid.LookupAccount(""); err == nil {
+				owner = domain + `\` + account
+			}
+		}
+	}
+	return path, owner
+}
+
+// listVolumes returns the fixed and removable drives with their filesystem
+// and capacity
+func listVolumes() ([]types.Volume, error) {
+	mask, err := windows.GetLogicalDrives()
+	if err != nil {
+		return nil, err
+	}
+	vols := []types.Volume{}
+	for i := 0; i < 26; i++ {
+		if mask&(1<<i) == 0 {
+			continue
+		}
+		letter := string(rune('A'+i)) + ":"
+		root, _ := windows.UTF16PtrFromString(letter + `\`)
+		if t := windows.GetDriveType(root); t != windows.DRIVE_FIXED && t != windows.DRIVE_REMOVABLE {
+			continue // Network, optical and RAM drives
+		}
+		var total, free uint64
+		if windows.GetDiskFreeSpaceEx(root, nil, &total, &free) != nil {
+			continue // Empty card reader
+		}
+		fs := make([]uint16, windows.MAX_PATH+1)
+		vol := types.Volume{MountPoint: letter, TotalBytes: int64(total), FreeBytes: int64(free)}
+		if windows.GetVolumeInformation(root, nil, 0, nil, nil, nil, &fs[0], uint32(len(fs))) == nil {
+			vol.FileSystem = windows.UTF16ToString(fs)
+		}
+		vols = append(vols, vol)
+	}
+	return vols, nil
+}
+
+// readSocketTable returns a raw MIB_{TCP,UDP}[6]TABLE_OWNER_PID for family
+// (windows.AF_INET or AF_INET6): listeners for TCP, every bound socket for UDP
+func readSocketTable(udp bool, family uint32) ([]byte, error) {
+	const (
+		tcpTableOwnerPIDListener = 3
+		udpTableOwnerPID         = 1
+	)
+	proc, class := procGetExtendedTcpTable, uintptr(tcpTableOwnerPIDListener)
+	if udp {
+		proc, class = procGetExtendedUdpTable, uintptr(udpTableOwnerPID)
+	}
+	var size uint32
+	for {
+		buf := make([]byte, size+4)
+		size = uint32(len(buf))
+		status, _, _ := proc.Call(uintptr(unsafe.Pointer(&buf[0])), uintptr(unsafe.Pointer(&size)), 0, uintptr(family), class, 0)
+		switch windows.Errno(status) {
+		case 0:
+			return buf[:size], nil
+		case windows.ERROR_INSUFFICIENT_BUFFER:
+			continue // The table grew; size holds the new requirement
+		default:
+			return nil, fmt.Errorf("%s: %w", proc.Name, windows.Errno(status))
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package windows
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// hotFixScript lists installed hotfixes (Win32_QuickFixEngineering) as KB
+// ID, description and install date, tab separated
+const hotFixScript = "Get-HotFix | ForEach-Object { $d = ''; " +
+	"if ($_.InstalledOn) { $d = $_.InstalledOn.ToString('yyyy-MM-dd') }; " +
+	"\"$($_.HotFixID)\t$($_.Description)\t$d\" }"
+
+// pendingScript counts applicable updates not yet installed, searching
+// Windows Update's local datastore only (no download)
+const pendingScript = "$s = (New-Object -ComObject Microsoft.Update.Session).CreateUpdateSearcher(); " +
+	"$s.Online = $false; $s.Search('IsInstalled=0 and IsHidden=0').Updates.Count"
+
+// GetPatchInfo lists installed hotfixes via Get-HotFix and counts pending
+// updates known to Windows Update
+// The last update is the newest hotfix date; Defender definition updates are
+// not hotfixes and do not count. A pending search cut short by the timeout
+// leaves the count unknown.
+// Complexity: O(u) where u = number of installed hotfixes
+func (c *Collector) GetPatchInfo(ctx context.Context) (*types.PatchInfo, error) {
+	out, err := powershell(ctx, hotFixScript)
+	if err != nil {
+		return nil, err
+	}
+	info := &types.PatchInfo{Updates: parseHotFixes(out)}
+	for _, u := range info.Updates {
+		if u.InstalledOn > info.LastUpdate {
+			info.LastUpdate = u.InstalledOn
+		}
+	}
+
+	if out, err := powershell(ctx, pendingScript); err == nil {
+		if n, err := strconv.Atoi(strings.TrimSpace(out)); err == nil {
+			info.Pending = &n
+		}
+	}
+	return info, ctx.Err()
+}
+
+// parseHotFixes parses hotFixScript output, sorted newest first
+// Complexity: O(|out| + u log u)
+func parseHotFixes(out string) []types.Update {
+	updates := []types.Update{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(strings.TrimRight(line, "\r"), "\t")
+		if len(fields) != 3 || fields[0] == "" {
+			continue
+		}
+		u := types.Update{ID: fields[0], Title: fields[1]}
+		if _, err := time.Parse("2006-01-02", fields[2]); err == nil {
+			u.InstalledOn = fields[2]
+		}
+		updates = append(updates, u)
+	}
+	types.SortUpdates(updates)
+	return updates
+}
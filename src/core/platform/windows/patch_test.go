@@ -0,0 +1,24 @@
+package windows
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// TestParseHotFixes verifies newest-first order and undated hotfixes
+func TestParseHotFixes(t *testing.T) {
+	out := "KB5034123\tUpdate\t2026-01-10\r\n" +
+		"KB5011048\tUpdate\t\r\n" +
+		"KB5034441\tSecurity Update\t2026-02-14\r\n" +
+		"\r\n"
+	want := []types.Update{
+		{ID: "KB5034441", Title: "Security Update", InstalledOn: "2026-02-14"},
+		{ID: "KB5034123", Title: "Update", InstalledOn: "2026-01-10"},
+		{ID: "KB5011048", Title: "Update"},
+	}
+	if got := parseHotFixes(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseHotFixes() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,99 @@
+package windows
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// Address families passed to GetExtended{Tcp,Udp}Table
+const (
+	afInet  = 2
+	afInet6 = 23
+)
+
+// socketRow locates the fields of one MIB_*ROW_OWNER_PID
+type socketRow struct {
+	size    int // Row size in bytes
+	addr    int // Offset of the local address
+	addrLen int // 4 (IPv4) or 16 (IPv6)
+	port    int // Offset of the local port (network byte order)
+	pid     int // Offset of the owning PID
+}
+
+// Row layouts of the four owner-PID tables
+var (
+	tcp4Row = socketRow{size: 24, addr: 4, addrLen: 4, port: 8, pid: 20}
+	tcp6Row = socketRow{size: 56, addr: 0, addrLen: 16, port: 20, pid: 52}
+	udp4Row = socketRow{size: 12, addr: 0, addrLen: 4, port: 4, pid: 8}
+	udp6Row = socketRow{size: 28, addr: 0, addrLen: 16, port: 20, pid: 24}
+)
+
+// GetListeningPorts lists listening TCP and bound UDP sockets from the IP
+// Helper owner-PID tables (the data behind netstat -ano)
+// Process names come from a Toolhelp snapshot; PIDs 0 and 4 are the kernel.
+// Complexity: O(s + p) where s = sockets, p = processes
+func (c *Collector) GetListeningPorts(ctx context.Context) (*types.PortInfo, error) {
+	tables := []struct {
+		udp      bool
+		family   uint32
+		protocol string
+		row      socketRow
+	}{
+		{false, afInet, "tcp", tcp4Row},
+		{false, afInet6, "tcp", tcp6Row},
+		{true, afInet, "udp", udp4Row},
+		{true, afInet6, "udp", udp6Row},
+	}
+
+	ports := []types.ListeningPort{}
+	for _, t := range tables {
+		buf, err := readSocketTable(t.udp, t.family)
+		if err != nil {
+			return nil, err
+		}
+		rows, err := parseSocketTable(buf, t.protocol, t.row)
+		if err != nil {
+			return nil, err
+		}
+		ports = append(ports, rows...)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	names, _ := processNames() // Ports keep their PIDs when the snapshot fails
+	for i := range ports {
+		ports[i].Process = names[ports[i].PID]
+	}
+	types.SortPorts(ports)
+	return &types.PortInfo{Ports: ports}, nil
+}
+
+// parseSocketTable decodes a MIB_*TABLE_OWNER_PID: a DWORD entry count
+// followed by fixed-size rows
+// Complexity: O(rows)
+func parseSocketTable(buf []byte, protocol string, row socketRow) ([]types.ListeningPort, error) {
+	if len(buf) < 4 {
+		return nil, fmt.Errorf("%s socket table truncated", protocol)
+	}
+	n := int(binary.LittleEndian.Uint32(buf))
+	if n > (len(buf)-4)/row.size {
+		return nil, fmt.Errorf("%s socket table truncated: %d rows in %d bytes", protocol, n, len(buf))
+	}
+
+	ports := make([]types.ListeningPort, 0, n)
+	for i := 0; i < n; i++ {
+		r := buf[4+i*row.size : 4+(i+1)*row.size]
+		ports = append(ports, types.ListeningPort{
+			Protocol: protocol,
+			Address:  net.IP(r[row.addr : row.addr+row.addrLen]).String(),
+			Port:     int(binary.BigEndian.Uint16(r[row.port:])),
+			PID:      int(binary.LittleEndian.Uint32(r[row.pid:])),
+		})
+	}
+	return ports, nil
+}
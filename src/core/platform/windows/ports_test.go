@@ -0,0 +1,55 @@
+package windows
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// table builds an owner-PID table from rows of the given layout
+func table(row socketRow, rows ...func(r []byte)) []byte {
+	buf := make([]byte, 4+len(rows)*row.size)
+	binary.LittleEndian.PutUint32(buf, uint32(len(rows)))
+	for i, fill := range rows {
+		fill(buf[4+i*row.size : 4+(i+1)*row.size])
+	}
+	return buf
+}
+
+// socket fills a row's local address, port and PID
+func socket(row socketRow, addr []byte, port uint16, pid uint32) func(r []byte) {
+	return func(r []byte) {
+		copy(r[row.addr:], addr)
+		binary.BigEndian.PutUint16(r[row.port:], port)
+		binary.LittleEndian.PutUint32(r[row.pid:], pid)
+	}
+}
+
+// TestParseSocketTable verifies the IPv4 and IPv6 row layouts
+func TestParseSocketTable(t *testing.T) {
+	buf := table(tcp4Row,
+		socket(tcp4Row, []byte{0, 0, 0, 0}, 135, 1024),
+		socket(tcp4Row, []byte{127, 0, 0, 1}, 5432, 3100))
+	ports, err := parseSocketTable(buf, "tcp", tcp4Row)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ports) != 2 || ports[0].Address != "0.0.0.0" || ports[0].Port != 135 || ports[0].PID != 1024 ||
+		ports[1].Address != "127.0.0.1" || ports[1].Port != 5432 {
+		t.Errorf("tcp4 ports = %+v", ports)
+	}
+
+	loopback6 := make([]byte, 16)
+	loopback6[15] = 1
+	buf = table(udp6Row, socket(udp6Row, loopback6, 5353, 4))
+	ports, err = parseSocketTable(buf, "udp", udp6Row)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ports) != 1 || ports[0].Protocol != "udp" || ports[0].Address != "::1" || ports[0].Port != 5353 || ports[0].PID != 4 {
+		t.Errorf("udp6 ports = %+v", ports)
+	}
+
+	if _, err := parseSocketTable(buf[:20], "udp", udp6Row); err == nil {
+		t.Error("parseSocketTable() accepted a truncated table")
+	}
+}
@@ -0,0 +1,21 @@
+package windows
+
+import (
+	"context"
+	"sort"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// GetProcessInfo lists running processes from a Toolhelp snapshot
+// Paths and owners of other users' processes are only visible to
+// administrators and are left empty otherwise.
+// Complexity: O(p) where p = number of processes
+func (c *Collector) GetProcessInfo(ctx context.Context) (*types.ProcessInfo, error) {
+	procs, err := listProcesses()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(procs, func(i, j int) bool { return procs[i].PID < procs[j].PID })
+	return &types.ProcessInfo{Processes: procs}, ctx.Err()
+}
@@ -0,0 +1,143 @@
+package windows
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// securityCenterScript lists the antivirus products registered with Windows
+// Security Center as display name, productState and file version, tab
+// separated (root/SecurityCenter2 exists on client editions only)
+const securityCenterScript = "Get-CimInstance -Namespace root/SecurityCenter2 -ClassName AntiVirusProduct -ErrorAction Stop | " +
+	"ForEach-Object { $exe = [Environment]::ExpandEnvironmentVariables($_.pathToSignedProductExe); " +
+	"$v = (Get-Item -LiteralPath $exe -ErrorAction SilentlyContinue).VersionInfo.ProductVersion; " +
+	"\"$($_.displayName)\t$($_.productState)\t$v\" }"
+
+// defenderScript prints Microsoft Defender's version, real-time protection
+// and antivirus state, tab separated (used where Security Center is absent,
+// e.g. Windows Server)
+const defenderScript = "$s = Get-MpComputerStatus -ErrorAction Stop; " +
+	"\"$($s.AMProductVersion)\t$($s.RealTimeProtectionEnabled)\t$($s.AntivirusEnabled)\""
+
+// productStateScanning is the productState bit set while real-time scanning
+// is on
+const productStateScanning = 0x1000
+
+// edrAgents maps EDR sensor processes to their product names
+// EDR sensors do not register with Security Center, so only running ones
+// are found.
+var edrAgents = map[string]string{
+	"CSFalconService.exe":  "CrowdStrike Falcon",
+	"cyserver.exe":         "Palo Alto Networks Cortex XDR",
+	"elastic-endpoint.exe": "Elastic Defend",
+	"MsSense.exe":          "Microsoft Defender for Endpoint",
+	"RepMgr.exe":           "VMware Carbon Black Cloud",
+	"SentinelAgent.exe":    "SentinelOne",
+}
+
+// GetSecurityProducts lists antivirus products from Windows Security Center
+// (falling back to Get-MpComputerStatus) and running EDR sensors
+// Fails only when neither query works and no sensor runs, so a blocked
+// PowerShell is not mistaken for an unprotected host.
+// Complexity: O(a + p) where a = registered products, p = number of processes
+func (c *Collector) GetSecurityProducts(ctx context.Context) (*types.SecurityInfo, error) {
+	products := []types.SecurityProduct{}
+	out, err := powershell(ctx, securityCenterScript)
+	if err == nil {
+		products = parseSecurityCenter(out)
+	}
+	if len(products) == 0 {
+		if out, mpErr := powershell(ctx, defenderScript); mpErr == nil {
+			if p, ok := parseDefenderStatus(out); ok {
+				products = append(products, p)
+			}
+			err = nil
+		}
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	names, _ := processNames()
+	edr := runningEDR(names)
+	if err != nil && len(edr) == 0 {
+		return nil, fmt.Errorf("security center: %w", err)
+	}
+	products = append(products, edr...)
+	types.SortProducts(products)
+	return &types.SecurityInfo{Products: products}, nil
+}
+
+// powershell runs a script non-interactively and returns its output
+func powershell(ctx context.Context, script string) (string, error) {
+	out, err := exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", script).Output()
+	return string(out), err
+}
+
+// parseSecurityCenter parses securityCenterScript output, one product per
+// name (a product registered twice is on if either registration is)
+// Complexity: O(|out|)
+func parseSecurityCenter(out string) []types.SecurityProduct {
+	products := []types.SecurityProduct{}
+	index := map[string]int{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(strings.TrimRight(line, "\r"), "\t")
+		if len(fields) != 3 || fields[0] == "" {
+			continue
+		}
+		p := types.SecurityProduct{Name: fields[0], Kind: types.ProductAntivirus, Version: fields[2], RealTime: types.RealTimeUnknown}
+		if state, err := strconv.ParseUint(fields[1], 10, 32); err == nil {
+			p.RealTime = types.RealTimeOff
+			if state&productStateScanning != 0 {
+				p.RealTime = types.RealTimeOn
+			}
+		}
+		if i, ok := index[p.Name]; ok {
+			if p.RealTime == types.RealTimeOn {
+				products[i] = p
+			}
+			continue
+		}
+		index[p.Name] = len(products)
+		products = append(products, p)
+	}
+	return products
+}
+
+// parseDefenderStatus parses defenderScript output; ok is false when
+// Defender's antivirus is disabled (another product replaced it)
+// Complexity: O(|out|)
+func parseDefenderStatus(out string) (types.SecurityProduct, bool) {
+	fields := strings.Split(strings.TrimSpace(out), "\t")
+	if len(fields) != 3 || !strings.EqualFold(fields[2], "True") {
+		return types.SecurityProduct{}, false
+	}
+	p := types.SecurityProduct{Name: "Microsoft Defender Antivirus", Kind: types.ProductAntivirus, Version: fields[0], RealTime: types.RealTimeOff}
+	if strings.EqualFold(fields[1], "True") {
+		p.RealTime = types.RealTimeOn
+	}
+	return p, true
+}
+
+// runningEDR returns the EDR sensors among the running processes (PID →
+// image name), each once
+// Complexity: O(p)
+func runningEDR(names map[int]string) []types.SecurityProduct {
+	products := []types.SecurityProduct{}
+	seen := map[string]bool{}
+	for _, image := range names {
+		for process, name := range edrAgents {
+			if strings.EqualFold(image, process) && !seen[name] {
+				seen[name] = true
+				products = append(products, types.SecurityProduct{Name: name, Kind: types.ProductEDR, RealTime: types.RealTimeOn})
+			}
+		}
+	}
+	types.SortProducts(products)
+	return products
+}
@@ -0,0 +1,46 @@
+package windows
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// TestParseSecurityCenter verifies productState decoding and duplicate
+// registrations
+func TestParseSecurityCenter(t *testing.T) {
+	out := "Windows Defender\t393472\t4.18.24090.11\r\n" + // 0x060100: scanning off
+		"Sophos Anti-Virus\t266240\t\r\n" + // 0x041000: scanning on
+		"Windows Defender\t397568\t4.18.24090.11\r\n" + // 0x061100: on
+		"Broken\tnot-a-number\t1.0\r\n"
+	want := []types.SecurityProduct{
+		{Name: "Windows Defender", Kind: types.ProductAntivirus, Version: "4.18.24090.11", RealTime: types.RealTimeOn},
+		{Name: "Sophos Anti-Virus", Kind: types.ProductAntivirus, RealTime: types.RealTimeOn},
+		{Name: "Broken", Kind: types.ProductAntivirus, Version: "1.0", RealTime: types.RealTimeUnknown},
+	}
+	if got := parseSecurityCenter(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseSecurityCenter() = %+v, want %+v", got, want)
+	}
+}
+
+// TestParseDefenderStatus verifies real-time state and a disabled Defender
+func TestParseDefenderStatus(t *testing.T) {
+	p, ok := parseDefenderStatus("4.18.24090.11\tFalse\tTrue\r\n")
+	if !ok || p.Version != "4.18.24090.11" || p.RealTime != types.RealTimeOff {
+		t.Errorf("parseDefenderStatus() = %+v, %v", p, ok)
+	}
+	if _, ok := parseDefenderStatus("4.18.24090.11\tFalse\tFalse"); ok {
+		t.Error("disabled Defender reported")
+	}
+}
+
+// TestRunningEDR verifies sensors are matched case-insensitively, once each
+func TestRunningEDR(t *testing.T) {
+	names := map[int]string{4: "System", 812: "csfalconservice.exe", 813: "CSFalconService.exe", 900: "MsSense.exe"}
+	got := runningEDR(names)
+	if len(got) != 2 || got[0].Name != "CrowdStrike Falcon" || got[1].Name != "Microsoft Defender for Endpoint" ||
+		got[0].Kind != types.ProductEDR || got[0].RealTime != types.RealTimeOn {
+		t.Errorf("runningEDR() = %+v", got)
+	}
+}
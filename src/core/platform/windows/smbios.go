@@ -0,0 +1,87 @@
+package windows
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// smbiosSystem is the SMBIOS System Information structure (type 1), which
+// holds the values WMI reports as Win32_ComputerSystemProduct.UUID and
+// Win32_BIOS.SerialNumber
+const smbiosSystem = 1
+
+// parseSMBIOS returns the system UUID and serial number from a raw SMBIOS
+// table as returned by GetSystemFirmwareTable('RSMB') (8-byte header first)
+// Complexity: O(|data|)
+func parseSMBIOS(data []byte) (uuid, serial string, err error) {
+	if len(data) < 8 {
+		return "", "", fmt.Errorf("smbios: short header")
+	}
+	major, minor := data[1], data[2]
+	length := int(binary.LittleEndian.Uint32(data[4:8]))
+	table := data[8:]
+	if length < len(table) {
+		table = table[:length]
+	}
+
+	for len(table) >= 4 {
+		typ, size := table[0], int(table[1])
+		if size < 4 || size > len(table) {
+			break
+		}
+		formatted := table[:size]
+
+		// Strings follow the formatted area, ended by a double NUL
+		end := size
+		for end+1 < len(table) && (table[end] != 0 || table[end+1] != 0) {
+			end++
+		}
+		strs := strings.Split(string(table[size:end]), "\x00")
+
+		if typ == smbiosSystem {
+			if size >= 8 {
+				serial = smbiosString(strs, formatted[7])
+			}
+			if size >= 24 {
+				uuid = formatSMBIOSUUID(formatted[8:24], major > 2 || (major == 2 && minor >= 6))
+			}
+			return uuid, serial, nil
+		}
+		if typ == 127 {
+			break // End-of-table
+		}
+		table = table[min(end+2, len(table)):]
+	}
+	return "", "", fmt.Errorf("smbios: no system information structure")
+}
+
+// smbiosString returns the 1-based string index of a structure ("" for 0)
+func smbiosString(strs []string, index byte) string {
+	if index == 0 || int(index) > len(strs) {
+		return ""
+	}
+	return strings.TrimSpace(strs[index-1])
+}
+
+// formatSMBIOSUUID formats a 16-byte SMBIOS UUID as WMI does (upper case)
+// Since SMBIOS 2.6 the first three fields are little-endian.
+func formatSMBIOSUUID(b []byte, littleEndian bool) string {
+	allSame := true
+	for _, v := range b[1:] {
+		if v != b[0] {
+			allSame = false
+			break
+		}
+	}
+	if allSame && (b[0] == 0x00 || b[0] == 0xFF) {
+		return "" // Not present / not settable
+	}
+	u := append([]byte{}, b...)
+	if littleEndian {
+		u[0], u[1], u[2], u[3] = u[3], u[2], u[1], u[0]
+		u[4], u[5] = u[5], u[4]
+		u[6], u[7] = u[7], u[6]
+	}
+	return fmt.Sprintf("%X-%X-%X-%X-%X", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+}
@@ -0,0 +1,44 @@
+package windows
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// rawSMBIOS wraps structures in a GetSystemFirmwareTable('RSMB') header
+func rawSMBIOS(major, minor byte, structs ...[]byte) []byte {
+	var table []byte
+	for _, s := range structs {
+		table = append(table, s...)
+	}
+	header := []byte{0, major, minor, 0, 0, 0, 0, 0}
+	binary.LittleEndian.PutUint32(header[4:], uint32(len(table)))
+	return append(header, table...)
+}
+
+// TestParseSMBIOS verifies the type 1 UUID byte order and serial string
+func TestParseSMBIOS(t *testing.T) {
+	bios := append([]byte{0, 0x12, 0x00, 0x00, 1, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, "Vendor\x00Ver\x00\x00"...)
+	system := []byte{1, 0x1B, 0x01, 0x00, 1, 2, 3, 4}
+	system = append(system,
+		0x44, 0x45, 0x4C, 0x4C, 0x35, 0x00, 0x10, 0x38, 0x80, 0x34, 0xB3, 0xC0, 0x4F, 0x39, 0x33, 0x32) // UUID
+	system = append(system, 6, 0, 0) // Wake-up type, SKU, family
+	system = append(system, "Dell Inc.\x00Latitude\x00A01\x00ABC1234\x00\x00"...)
+	end := []byte{127, 4, 0, 0, 0, 0}
+
+	uuid, serial, err := parseSMBIOS(rawSMBIOS(3, 4, bios, system, end))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uuid != "4C4C4544-0035-3810-8034-B3C04F393332" || serial != "ABC1234" {
+		t.Errorf("parseSMBIOS() = %q, %q", uuid, serial)
+	}
+
+	// Pre-2.6 tables store the UUID big-endian
+	if uuid, _, _ := parseSMBIOS(rawSMBIOS(2, 4, system, end)); uuid != "44454C4C-3500-1038-8034-B3C04F393332" {
+		t.Errorf("SMBIOS 2.4 uuid = %q", uuid)
+	}
+	if _, _, err := parseSMBIOS(rawSMBIOS(3, 4, bios, end)); err == nil {
+		t.Error("missing type 1 structure accepted")
+	}
+}
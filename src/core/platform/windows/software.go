@@ -0,0 +1,97 @@
+package windows
+
+import (
+	"context"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// uninstallKeys are the registry keys installers register programs under
+// (64-bit, 32-bit on 64-bit Windows, and per-user installs)
+var uninstallKeys = []string{
+	`HKLM\SOFTWARE\Microsoft\Windows\CurrentVersion\Uninstall`,
+	`HKLM\SOFTWARE\WOW6432Node\Microsoft\Windows\CurrentVersion\Uninstall`,
+	`HKCU\SOFTWARE\Microsoft\Windows\CurrentVersion\Uninstall`,
+}
+
+// GetSoftwareInventory lists programs from the registry Uninstall keys
+// Entries without a DisplayName, system components and updates are skipped,
+// as in Apps & features; a program registered under several keys is listed
+// once. On timeout the programs read so far are returned with ctx's error.
+// Complexity: O(p) where p = number of registered programs
+func (c *Collector) GetSoftwareInventory(ctx context.Context) (*types.SoftwareInfo, error) {
+	info := &types.SoftwareInfo{Packages: []types.Software{}}
+	seen := make(map[types.Software]bool)
+
+	for _, key := range uninstallKeys {
+		out, err := exec.CommandContext(ctx, "reg", "query", key, "/s").Output()
+		if err != nil {
+			if ctx.Err() != nil {
+				break // Timed out: keep the keys already read
+			}
+			continue // Key absent (e.g., WOW6432Node on 32-bit Windows)
+		}
+		for _, pkg := range parseUninstallQuery(string(out)) {
+			if !seen[pkg] {
+				seen[pkg] = true
+				info.Packages = append(info.Packages, pkg)
+			}
+		}
+	}
+
+	sort.Slice(info.Packages, func(i, j int) bool {
+		a, b := info.Packages[i], info.Packages[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Version < b.Version
+	})
+	return info, ctx.Err()
+}
+
+// parseUninstallQuery parses `reg query <Uninstall key> /s` output: one
+// block per subkey, each value on a "    Name    REG_TYPE    Data" line
+// Complexity: O(|out|)
+func parseUninstallQuery(out string) []types.Software {
+	var pkgs []types.Software
+	values := map[string]string{}
+	flush := func() {
+		name := values["DisplayName"]
+		skip := name == "" || values["SystemComponent"] == "0x1" ||
+			values["ParentKeyName"] != "" || values["ReleaseType"] != ""
+		if !skip {
+			pkgs = append(pkgs, types.Software{
+				Name:        name,
+				Version:     values["DisplayVersion"],
+				Publisher:   values["Publisher"],
+				InstallDate: registryDate(values["InstallDate"]),
+				Source:      "registry",
+			})
+		}
+		values = map[string]string{}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(out, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(line, "HKEY_") {
+			flush() // Next subkey
+			continue
+		}
+		fields := strings.SplitN(strings.TrimSpace(line), "    ", 3)
+		if len(fields) == 3 && strings.HasPrefix(fields[1], "REG_") {
+			values[fields[0]] = strings.TrimSpace(fields[2])
+		}
+	}
+	flush()
+	return pkgs
+}
+
+// registryDate converts an InstallDate value (YYYYMMDD) to YYYY-MM-DD
+func registryDate(v string) string {
+	if len(v) != 8 || strings.Trim(v, "0123456789") != "" {
+		return ""
+	}
+	return v[:4] + "-" + v[4:6] + "-" + v[6:]
+}
@@ -0,0 +1,29 @@
+package windows
+
+import "testing"
+
+const uninstallQuery = "\r\n" +
+	"HKEY_LOCAL_MACHINE\\SOFTWARE\\Microsoft\\Windows\\CurrentVersion\\Uninstall\\7-Zip\r\n" +
+	"    DisplayName    REG_SZ    7-Zip 23.01 (x64)\r\n" +
+	"    DisplayVersion    REG_SZ    23.01\r\n" +
+	"    Publisher    REG_SZ    Igor Pavlov\r\n" +
+	"    InstallDate    REG_SZ    20240115\r\n" +
+	"    InstallLocation    REG_SZ    \r\n" +
+	"\r\n" +
+	"HKEY_LOCAL_MACHINE\\SOFTWARE\\Microsoft\\Windows\\CurrentVersion\\Uninstall\\{GUID}\r\n" +
+	"    DisplayName    REG_SZ    Runtime Component\r\n" +
+	"    SystemComponent    REG_DWORD    0x1\r\n" +
+	"\r\n" +
+	"HKEY_LOCAL_MACHINE\\SOFTWARE\\Microsoft\\Windows\\CurrentVersion\\Uninstall\\NoName\r\n" +
+	"    UninstallString    REG_SZ    C:\\x\\uninstall.exe\r\n"
+
+// TestParseUninstallQuery verifies programs are parsed and hidden entries skipped
+func TestParseUninstallQuery(t *testing.T) {
+	pkgs := parseUninstallQuery(uninstallQuery)
+	if len(pkgs) != 1 {
+		t.Fatalf("got %d programs, want 1: %+v", len(pkgs), pkgs)
+	}
+	if p := pkgs[0]; p.Name != "7-Zip 23.01 (x64)" || p.Version != "23.01" || p.Publisher != "Igor Pavlov" || p.InstallDate != "2024-01-15" || p.Source != "registry" {
+		t.Errorf("pkgs[0] = %+v", p)
+	}
+}
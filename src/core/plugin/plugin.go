@@ -0,0 +1,306 @@
+// Package plugin discovers and runs exec-based partner extensions
+// A plugin is a subdirectory of plugins.directory holding a plugin.yaml
+// manifest and an executable. Each call starts the executable, writes one
+// JSON Request to its stdin and reads one JSON Response from its stdout, so
+// plugins can be written in any language and never share the agent's memory.
+//
+// Kinds: exporters deliver each run's payload (spooled and retried like the
+// built-in exporters), redactors rewrite the facts before anything else sees
+// them, and rules return findings that join the report's risks.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/consent"
+	"github.com/minibeast/usb-agent/src/core/report"
+)
+
+// ProtocolVersion is sent with every Request; bumped on incompatible changes
+const ProtocolVersion = 1
+
+// ManifestFile is the manifest name inside each plugin directory
+const ManifestFile = "plugin.yaml"
+
+// maxOutput bounds a plugin's stdout (a redactor returns the full facts)
+const maxOutput = 16 << 20
+
+// maxStderr is how much of a failing plugin's stderr is kept for the error
+const maxStderr = 1024
+
+// Kind selects the extension point a plugin implements
+type Kind string
+
+const (
+	KindExporter Kind = "exporter"
+	KindRedactor Kind = "redactor"
+	KindRules    Kind = "rules"
+)
+
+// namePattern keeps plugin names safe as spool queue directory names
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// Manifest is the plugin.yaml of one plugin
+type Manifest struct {
+	// Unique plugin name (lower-case letters, digits, '-' and '_')
+	Name string `yaml:"name"`
+
+	// Extension point: exporter, redactor or rules
+	Kind Kind `yaml:"kind"`
+
+	// Executable and arguments; a relative path containing a separator is
+	// relative to the plugin directory, a bare name is looked up on PATH
+	// (e.g., python3)
+	Command []string `yaml:"command"`
+
+	// Per-OS overrides of Command keyed by GOOS (linux, darwin, windows)
+	Commands map[string][]string `yaml:"commands"`
+
+	// Per-call timeout (milliseconds); 0 = plugins.timeout_ms
+	TimeoutMs int `yaml:"timeout_ms"`
+}
+
+// Plugin is a discovered plugin runnable on this OS
+type Plugin struct {
+	Manifest
+	Dir     string        // Plugin directory (working directory of every call)
+	timeout time.Duration // Effective per-call timeout
+}
+
+// Request is the JSON document written to a plugin's stdin
+type Request struct {
+	Protocol int               `json:"protocol"`
+	Kind     Kind              `json:"kind"`
+	RunID    string            `json:"run_id,omitempty"`  // Exporters only
+	Facts    *collection.Facts `json:"facts"`             // Redacted facts, except for redactors
+	Report   *report.Report    `json:"report,omitempty"`  // Exporters only, when summarization ran
+	Consent  *consent.Record   `json:"consent,omitempty"` // Exporters only, when consent was recorded
+}
+
+// Response is the JSON document a plugin writes to its stdout
+// Exporters may write nothing; a non-zero exit status is a failure either way.
+type Response struct {
+	Facts    *collection.Facts `json:"facts,omitempty"`    // Redactors: the rewritten facts
+	Findings []Finding         `json:"findings,omitempty"` // Rules: the findings raised
+	Error    string            `json:"error,omitempty"`    // Failure reason
+	Retry    bool              `json:"retry,omitempty"`    // Exporters: failure is transient
+}
+
+// Finding is a risk raised by a rules plugin
+type Finding struct {
+	ID          string `json:"id"`          // Stable rule ID (e.g., "ACME-FW-001")
+	Description string `json:"description"` // Risk text shown in the report
+}
+
+// CallError reports a failed plugin call
+type CallError struct {
+	Plugin string
+	Err    error
+	Retry  bool // Transient (timeout or the plugin asked for a retry)
+}
+
+func (e *CallError) Error() string {
+	return "plugin " + e.Plugin + ": " + e.Err.Error()
+}
+
+func (e *CallError) Unwrap() error {
+	return e.Err
+}
+
+// Load discovers the plugins in cfg.Directory runnable on this OS
+// Returns nil when plugins are disabled or the directory does not exist.
+// Complexity: O(p) where p = number of plugin directories
+func Load(cfg config.PluginsConfig) ([]*Plugin, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	return Discover(cfg.Directory, time.Duration(cfg.TimeoutMs)*time.Millisecond)
+}
+
+// Discover reads every <dir>/*/plugin.yaml, sorted by plugin name
+// Plugins without a command for this OS are skipped; an invalid manifest is
+// an error so a typo never silently disables a redactor.
+// Complexity: O(p log p)
+func Discover(dir string, timeout time.Duration) ([]*Plugin, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugins directory: %w", err)
+	}
+
+	plugins := []*Plugin{}
+	seen := map[string]string{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		pluginDir := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(filepath.Join(pluginDir, ManifestFile))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var m Manifest
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&m); err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Join(pluginDir, ManifestFile), err)
+		}
+		if err := m.validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Join(pluginDir, ManifestFile), err)
+		}
+		if prev, ok := seen[m.Name]; ok {
+			return nil, fmt.Errorf("plugin %s defined in both %s and %s", m.Name, prev, pluginDir)
+		}
+		seen[m.Name] = pluginDir
+
+		if len(m.command()) == 0 {
+			continue // Not available on this OS
+		}
+		p := &Plugin{Manifest: m, Dir: pluginDir, timeout: timeout}
+		if m.TimeoutMs > 0 {
+			p.timeout = time.Duration(m.TimeoutMs) * time.Millisecond
+		}
+		plugins = append(plugins, p)
+	}
+
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins, nil
+}
+
+// OfKind filters plugins by kind, keeping their order
+// Complexity: O(|plugins|)
+func OfKind(plugins []*Plugin, kind Kind) []*Plugin {
+	matched := []*Plugin{}
+	for _, p := range plugins {
+		if p.Kind == kind {
+			matched = append(matched, p)
+		}
+	}
+	return matched
+}
+
+// validate checks a manifest
+func (m *Manifest) validate() error {
+	if !namePattern.MatchString(m.Name) {
+		return fmt.Errorf("name %q must be 1-63 lower-case letters, digits, '-' or '_'", m.Name)
+	}
+	switch m.Kind {
+	case KindExporter, KindRedactor, KindRules:
+	default:
+		return fmt.Errorf("kind %q must be exporter, redactor or rules", m.Kind)
+	}
+	if len(m.Command) == 0 && len(m.Commands) == 0 {
+		return errors.New("command is required")
+	}
+	if m.TimeoutMs < 0 {
+		return errors.New("timeout_ms must not be negative")
+	}
+	return nil
+}
+
+// command returns the command line for this OS (nil when unavailable)
+func (m *Manifest) command() []string {
+	if cmd, ok := m.Commands[runtime.GOOS]; ok {
+		return cmd
+	}
+	return m.Command
+}
+
+// Call runs the plugin once with req and decodes its response
+// Complexity: O(|req| + |response|) plus the plugin's own work
+func (p *Plugin) Call(ctx context.Context, req *Request) (*Response, error) {
+	req.Protocol, req.Kind = ProtocolVersion, p.Kind
+	input, err := json.Marshal(req)
+	if err != nil {
+		return nil, &CallError{Plugin: p.Name, Err: fmt.Errorf("failed to marshal request: %w", err)}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	argv := p.command()
+	name := argv[0]
+	if strings.ContainsAny(name, `/\`) && !filepath.IsAbs(name) {
+		name = filepath.Join(p.Dir, name)
+	}
+	cmd := exec.CommandContext(ctx, name, argv[1:]...)
+	cmd.Dir = p.Dir
+	cmd.Env = append(os.Environ(), fmt.Sprintf("MINIBEAST_PLUGIN_PROTOCOL=%d", ProtocolVersion))
+	cmd.Stdin = bytes.NewReader(input)
+	stdout := &limitedBuffer{max: maxOutput}
+	stderr := &limitedBuffer{max: maxStderr, keepTail: true}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	cmd.WaitDelay = time.Second // Do not hang on grandchildren holding the pipes
+
+	runErr := cmd.Run()
+	if ctx.Err() != nil {
+		return nil, &CallError{Plugin: p.Name, Err: fmt.Errorf("timed out after %s", p.timeout), Retry: true}
+	}
+	if stdout.overflow {
+		return nil, &CallError{Plugin: p.Name, Err: fmt.Errorf("output exceeds %d bytes", maxOutput)}
+	}
+
+	resp := &Response{}
+	if out := bytes.TrimSpace(stdout.Bytes()); len(out) > 0 {
+		if err := json.Unmarshal(out, resp); err != nil && runErr == nil {
+			return nil, &CallError{Plugin: p.Name, Err: fmt.Errorf("invalid response: %w", err)}
+		}
+	}
+	switch {
+	case resp.Error != "":
+		return nil, &CallError{Plugin: p.Name, Err: errors.New(resp.Error), Retry: resp.Retry}
+	case runErr != nil:
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			runErr = fmt.Errorf("%w: %s", runErr, msg)
+		}
+		return nil, &CallError{Plugin: p.Name, Err: runErr, Retry: resp.Retry}
+	}
+	return resp, nil
+}
+
+// limitedBuffer keeps at most max bytes of a stream (the head, or the tail)
+type limitedBuffer struct {
+	bytes.Buffer
+	max      int
+	keepTail bool
+	overflow bool
+}
+
+// Write buffers p within the limit, never failing so the plugin is not killed by EPIPE
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if b.keepTail {
+		b.Buffer.Write(p)
+		if extra := b.Len() - b.max; extra > 0 {
+			b.Next(extra)
+		}
+		return n, nil
+	}
+	if room := b.max - b.Len(); len(p) > room {
+		p, b.overflow = p[:max(room, 0)], true
+	}
+	b.Buffer.Write(p)
+	return n, nil
+}
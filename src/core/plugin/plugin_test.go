@@ -0,0 +1,235 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/config"
+)
+
+// helperEnv selects the behaviour of the test binary when it runs as a plugin
+const helperEnv = "MINIBEAST_TEST_PLUGIN"
+
+// TestMain lets the test binary act as a plugin executable
+func TestMain(m *testing.M) {
+	if mode := os.Getenv(helperEnv); mode != "" {
+		os.Exit(runHelper(mode))
+	}
+	os.Exit(m.Run())
+}
+
+// runHelper implements the plugin side of the protocol for each test mode
+func runHelper(mode string) int {
+	var req Request
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		fmt.Fprintln(os.Stderr, "bad request:", err)
+		return 2
+	}
+	out := json.NewEncoder(os.Stdout)
+	switch mode {
+	case "redact":
+		req.Facts.Hostname = "host-" + req.Facts.Hostname[:1]
+		req.Facts.PrimaryEmail = ""
+		req.Facts.Timestamp = time.Time{} // Metadata changes must be ignored
+		out.Encode(Response{Facts: req.Facts})
+	case "blank":
+		req.Facts.Hostname = ""
+		out.Encode(Response{Facts: req.Facts})
+	case "rules":
+		out.Encode(Response{Findings: []Finding{{ID: "ACME-001", Description: "Host " + req.Facts.Hostname + " has no disk encryption"}}})
+	case "export":
+		os.WriteFile(os.Getenv(helperEnv+"_OUT"), []byte(req.RunID+" "+string(req.Kind)), 0644)
+	case "fail":
+		fmt.Fprintln(os.Stderr, "boom")
+		return 3
+	case "retry":
+		out.Encode(Response{Error: "collector busy", Retry: true})
+		return 1
+	case "sleep":
+		time.Sleep(10 * time.Second)
+	case "garbage":
+		fmt.Println("not json")
+	}
+	return 0
+}
+
+// writeManifest creates <dir>/<sub>/plugin.yaml
+func writeManifest(t *testing.T, dir, sub, manifest string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, sub, ManifestFile), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// helperPlugin returns a plugin running the test binary in mode
+func helperPlugin(t *testing.T, kind Kind, mode string, timeout time.Duration) *Plugin {
+	t.Helper()
+	t.Setenv(helperEnv, mode)
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Plugin{
+		Manifest: Manifest{Name: "test-" + mode, Kind: kind, Command: []string{exe}},
+		Dir:      t.TempDir(),
+		timeout:  timeout,
+	}
+}
+
+func testFacts() *collection.Facts {
+	return &collection.Facts{
+		Timestamp:        time.Date(2025, 11, 9, 12, 0, 0, 0, time.UTC),
+		CollectorVersion: "1.0.0",
+		Hostname:         "workstation",
+		HardwareUUID:     "uuid-123",
+		OSName:           "Linux",
+		PrimaryEmail:     "user@example.com",
+	}
+}
+
+// TestDiscover verifies manifest discovery, ordering, overrides and skips
+func TestDiscover(t *testing.T) {
+	dir := t.TempDir()
+	writeManifest(t, dir, "b", "name: zeta\nkind: rules\ncommand: [\"bin/zeta\"]\n")
+	writeManifest(t, dir, "a", "name: alpha\nkind: exporter\ncommand: [\"python3\", \"alpha.py\"]\ntimeout_ms: 250\n")
+	writeManifest(t, dir, "c", "name: other-os\nkind: redactor\ncommands:\n  plan9: [\"./r\"]\n")
+	os.MkdirAll(filepath.Join(dir, "no-manifest"), 0755)
+	os.WriteFile(filepath.Join(dir, "README.txt"), []byte("not a plugin"), 0644)
+
+	plugins, err := Discover(dir, time.Second)
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	if len(plugins) != 2 || plugins[0].Name != "alpha" || plugins[1].Name != "zeta" {
+		t.Fatalf("Unexpected plugins: %+v", plugins)
+	}
+	if plugins[0].timeout != 250*time.Millisecond || plugins[1].timeout != time.Second {
+		t.Errorf("Timeouts = %v, %v", plugins[0].timeout, plugins[1].timeout)
+	}
+	if got := OfKind(plugins, KindRules); len(got) != 1 || got[0].Name != "zeta" {
+		t.Errorf("OfKind(rules) = %+v", got)
+	}
+
+	if plugins, err := Discover(filepath.Join(dir, "missing"), time.Second); err != nil || plugins != nil {
+		t.Errorf("Missing directory: %v, %v", plugins, err)
+	}
+	if plugins, err := Load(config.PluginsConfig{Enabled: false, Directory: dir}); err != nil || plugins != nil {
+		t.Errorf("Disabled plugins loaded: %v, %v", plugins, err)
+	}
+}
+
+// TestDiscover_Invalid verifies bad manifests are errors, not silent skips
+func TestDiscover_Invalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		manifests []string
+	}{
+		{"bad kind", []string{"name: x\nkind: collector\ncommand: [\"./x\"]\n"}},
+		{"bad name", []string{"name: ../x\nkind: rules\ncommand: [\"./x\"]\n"}},
+		{"no command", []string{"name: x\nkind: rules\n"}},
+		{"unknown field", []string{"name: x\nkind: rules\ncommand: [\"./x\"]\ncomand: [\"./y\"]\n"}},
+		{"duplicate name", []string{"name: x\nkind: rules\ncommand: [\"./x\"]\n", "name: x\nkind: exporter\ncommand: [\"./x\"]\n"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for i, m := range tt.manifests {
+				writeManifest(t, dir, fmt.Sprintf("p%d", i), m)
+			}
+			if _, err := Discover(dir, time.Second); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+// TestRedact verifies redactors rewrite facts but not run metadata
+func TestRedact(t *testing.T) {
+	p := helperPlugin(t, KindRedactor, "redact", 10*time.Second)
+	facts := testFacts()
+
+	redacted, err := Redact(context.Background(), []*Plugin{p}, facts)
+	if err != nil {
+		t.Fatalf("Redact failed: %v", err)
+	}
+	if redacted.Hostname != "host-w" || redacted.PrimaryEmail != "" {
+		t.Errorf("Facts not redacted: %+v", redacted)
+	}
+	if !redacted.Timestamp.Equal(facts.Timestamp) || redacted.CollectorVersion != "1.0.0" {
+		t.Errorf("Run metadata changed: %+v", redacted)
+	}
+
+	blank := helperPlugin(t, KindRedactor, "blank", 10*time.Second)
+	if _, err := Redact(context.Background(), []*Plugin{blank}, facts); err == nil {
+		t.Error("Expected invalid redacted facts to fail")
+	}
+}
+
+// TestRules verifies findings are returned as grounded
+func TestRules(t *testing.T) {
+	p := helperPlugin(t, KindRules, "rules", 10*time.Second)
+	findings, err := Rules{p}.Findings(context.Background(), testFacts())
+	if err != nil {
+		t.Fatalf("Findings failed: %v", err)
+	}
+	if len(findings) != 1 || findings[0].ID != "ACME-001" || findings[0].Confidence != "grounded" {
+		t.Fatalf("Unexpected findings: %+v", findings)
+	}
+	if !strings.Contains(findings[0].Description, "workstation") {
+		t.Errorf("Plugin did not see the facts: %q", findings[0].Description)
+	}
+}
+
+// TestCall_Request verifies the request envelope reaches the plugin
+func TestCall_Request(t *testing.T) {
+	p := helperPlugin(t, KindExporter, "export", 10*time.Second)
+	out := filepath.Join(t.TempDir(), "request.txt")
+	t.Setenv(helperEnv+"_OUT", out)
+
+	if _, err := p.Call(context.Background(), &Request{RunID: "run-1", Facts: testFacts()}); err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if data, _ := os.ReadFile(out); string(data) != "run-1 exporter" {
+		t.Errorf("Plugin received %q", data)
+	}
+}
+
+// TestCall_Failures verifies failures carry the cause and the retry flag
+func TestCall_Failures(t *testing.T) {
+	tests := []struct {
+		mode    string
+		timeout time.Duration
+		want    string
+		retry   bool
+	}{
+		{"fail", 10 * time.Second, "boom", false},
+		{"retry", 10 * time.Second, "collector busy", true},
+		{"sleep", 200 * time.Millisecond, "timed out", true},
+		{"garbage", 10 * time.Second, "invalid response", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			p := helperPlugin(t, KindExporter, tt.mode, tt.timeout)
+			_, err := p.Call(context.Background(), &Request{Facts: testFacts()})
+
+			var callErr *CallError
+			if !errors.As(err, &callErr) {
+				t.Fatalf("Expected *CallError, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.want) || callErr.Retry != tt.retry {
+				t.Errorf("Error %q (retry %v), want %q (retry %v)", err, callErr.Retry, tt.want, tt.retry)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/progress"
+)
+
+// Redact passes facts through every redactor in order
+// Run metadata (timestamp, duration, version, partial and failed categories)
+// is kept from the collector. Any failure is returned so the caller can
+// withhold the facts rather than emit them unredacted. Each redactor is
+// reported as progress stage "redact.<name>".
+// Complexity: O(|redactors| · |Facts|)
+func Redact(ctx context.Context, redactors []*Plugin, facts *collection.Facts) (*collection.Facts, error) {
+	for _, p := range redactors {
+		step := progress.Start(ctx, "redact."+p.Name)
+		redacted, err := redactOne(ctx, p, facts)
+		step.End(err)
+		if err != nil {
+			return nil, err
+		}
+		facts = redacted
+	}
+	return facts, nil
+}
+
+// redactOne passes facts through a single redactor
+func redactOne(ctx context.Context, p *Plugin, facts *collection.Facts) (*collection.Facts, error) {
+	resp, err := p.Call(ctx, &Request{Facts: facts})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Facts == nil {
+		return nil, &CallError{Plugin: p.Name, Err: fmt.Errorf("redactor returned no facts")}
+	}
+
+	redacted := resp.Facts
+	redacted.Timestamp = facts.Timestamp
+	redacted.CollectionDurationMs = facts.CollectionDurationMs
+	redacted.CollectorVersion = facts.CollectorVersion
+	redacted.SchemaVersion = facts.SchemaVersion
+	redacted.Partial = facts.Partial
+	redacted.FailedCategories = facts.FailedCategories
+	if !redacted.Partial {
+		if err := redacted.Validate(); err != nil {
+			return nil, &CallError{Plugin: p.Name, Err: fmt.Errorf("redacted facts are invalid: %w", err)}
+		}
+	}
+	return redacted, nil
+}
@@ -0,0 +1,41 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/inference"
+)
+
+// Rules evaluates rules plugins against facts (a summarizer.RuleSource)
+type Rules []*Plugin
+
+// Findings returns the findings of every rules plugin, in plugin order
+// Findings are grounded: plugins evaluate the collected facts directly. A
+// failing plugin does not stop the others; its error is joined.
+// Complexity: O(|plugins|) calls
+func (r Rules) Findings(ctx context.Context, facts *collection.Facts) ([]inference.Finding, error) {
+	findings := []inference.Finding{}
+	var errs []error
+	for _, p := range r {
+		resp, err := p.Call(ctx, &Request{Facts: facts})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, f := range resp.Findings {
+			if f.ID == "" || f.Description == "" {
+				errs = append(errs, &CallError{Plugin: p.Name, Err: fmt.Errorf("finding without id or description")})
+				continue
+			}
+			findings = append(findings, inference.Finding{
+				ID:          f.ID,
+				Description: f.Description,
+				Confidence:  inference.ConfidenceGrounded,
+			})
+		}
+	}
+	return findings, errors.Join(errs...)
+}
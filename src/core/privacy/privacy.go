@@ -0,0 +1,174 @@
+// Package privacy replaces identifiers with keyed pseudonyms
+// A pseudonym is an HMAC-SHA256 of the normalized identifier under a
+// per-engagement salt: the same user, host, MAC or serial maps to the same
+// pseudonym on every machine of an engagement, so datasets stay joinable,
+// while nobody without the salt can reverse or even test a guess.
+package privacy
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+)
+
+// Kind is a class of identifier
+type Kind string
+
+const (
+	KindUsername Kind = "username" // Account names, owners, home directories and email local parts
+	KindHostname Kind = "hostname" // Hostname and computer name
+	KindMAC      Kind = "mac"      // Interface MAC addresses
+	KindSerial   Kind = "serial"   // Serial number and hardware UUID
+)
+
+// Kinds lists every identifier kind (anonymous mode pseudonymizes all)
+var Kinds = []Kind{KindUsername, KindHostname, KindMAC, KindSerial}
+
+// MinSaltSize is the shortest accepted salt (128 bits)
+const MinSaltSize = 16
+
+// ParseKinds converts config names to kinds
+// Complexity: O(|names|)
+func ParseKinds(names []string) ([]Kind, error) {
+	kinds := make([]Kind, 0, len(names))
+	for _, name := range names {
+		kind := Kind(name)
+		if !kind.valid() {
+			return nil, fmt.Errorf("unknown identifier kind %q", name)
+		}
+		kinds = append(kinds, kind)
+	}
+	return kinds, nil
+}
+
+// valid reports whether k is a known kind
+func (k Kind) valid() bool {
+	for _, known := range Kinds {
+		if k == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Pseudonymizer maps identifiers to pseudonyms under one salt
+// Mathematical property: Same salt, kind and identifier → same pseudonym
+type Pseudonymizer struct {
+	salt  []byte
+	kinds map[Kind]bool // Applied by Facts
+}
+
+// New creates a pseudonymizer whose Facts rewrites kinds
+// Complexity: O(|kinds|)
+func New(salt []byte, kinds []Kind) (*Pseudonymizer, error) {
+	if len(salt) < MinSaltSize {
+		return nil, fmt.Errorf("salt must be at least %d bytes, got %d", MinSaltSize, len(salt))
+	}
+	p := &Pseudonymizer{salt: append([]byte{}, salt...), kinds: map[Kind]bool{}}
+	for _, kind := range kinds {
+		if !kind.valid() {
+			return nil, fmt.Errorf("unknown identifier kind %q", kind)
+		}
+		p.kinds[kind] = true
+	}
+	return p, nil
+}
+
+// Pseudonym returns the pseudonym of value ("" stays "")
+// Usernames become "user-<16 hex>", hostnames "host-<16 hex>", serials
+// "sn-<16 hex>" and MACs a locally administered MAC, so fields keep their
+// shape for downstream parsers.
+// Complexity: O(|value|)
+func (p *Pseudonymizer) Pseudonym(kind Kind, value string) string {
+	normalized := normalize(kind, value)
+	if normalized == "" {
+		return value
+	}
+	mac := hmac.New(sha256.New, p.salt)
+	mac.Write([]byte(kind))
+	mac.Write([]byte{0})
+	mac.Write([]byte(normalized))
+	sum := mac.Sum(nil)
+
+	switch kind {
+	case KindMAC:
+		return fmt.Sprintf("02:%02x:%02x:%02x:%02x:%02x", sum[0], sum[1], sum[2], sum[3], sum[4])
+	case KindHostname:
+		return "host-" + hex.EncodeToString(sum[:8])
+	case KindSerial:
+		return "sn-" + hex.EncodeToString(sum[:8])
+	default:
+		return "user-" + hex.EncodeToString(sum[:8])
+	}
+}
+
+// normalize canonicalizes an identifier so spellings of the same one match
+func normalize(kind Kind, value string) string {
+	value = strings.TrimSpace(value)
+	switch kind {
+	case KindMAC:
+		return strings.ToLower(strings.NewReplacer(":", "", "-", "", ".", "").Replace(value))
+	case KindSerial:
+		return strings.ToUpper(value)
+	case KindHostname:
+		return strings.ToLower(strings.TrimSuffix(value, "."))
+	default:
+		return strings.ToLower(value)
+	}
+}
+
+// Facts rewrites the configured identifier kinds in f, then restores the
+// sorted order collection guarantees
+// Usernames also drop the full name, which would identify the account.
+// Complexity: O(|facts| log |facts|)
+func (p *Pseudonymizer) Facts(f *collection.Facts) {
+	if p.kinds[KindUsername] {
+		user := func(s string) string { return p.Pseudonym(KindUsername, s) }
+		f.MachineOwner = user(f.MachineOwner)
+		for i := range f.Users {
+			f.Users[i].Username = user(f.Users[i].Username)
+			f.Users[i].FullName = ""
+		}
+		for i := range f.LoggedInUsers {
+			f.LoggedInUsers[i] = user(f.LoggedInUsers[i])
+		}
+		for i := range f.RecentProfiles {
+			f.RecentProfiles[i].Username = user(f.RecentProfiles[i].Username)
+		}
+		for i, dir := range f.HomeDirs {
+			dir = strings.TrimRight(dir, `/\`)
+			cut := strings.LastIndexAny(dir, `/\`) + 1
+			f.HomeDirs[i] = dir[:cut] + user(dir[cut:])
+		}
+		if local, domain, ok := strings.Cut(f.PrimaryEmail, "@"); ok {
+			f.PrimaryEmail = user(local) + "@" + domain
+		} else {
+			f.PrimaryEmail = user(f.PrimaryEmail)
+		}
+	}
+	if p.kinds[KindHostname] {
+		f.Hostname = p.Pseudonym(KindHostname, f.Hostname)
+		f.ComputerName = p.Pseudonym(KindHostname, f.ComputerName)
+	}
+	if p.kinds[KindMAC] {
+		// The collector shares one interface slice between both fields
+		f.LocalIPs = slices.Clone(f.LocalIPs)
+		f.MACAddresses = slices.Clone(f.MACAddresses)
+		for i := range f.LocalIPs {
+			f.LocalIPs[i].MACAddress = p.Pseudonym(KindMAC, f.LocalIPs[i].MACAddress)
+		}
+		for i := range f.MACAddresses {
+			f.MACAddresses[i].MACAddress = p.Pseudonym(KindMAC, f.MACAddresses[i].MACAddress)
+		}
+	}
+	if p.kinds[KindSerial] {
+		f.SerialNumber = p.Pseudonym(KindSerial, f.SerialNumber)
+		f.HardwareUUID = p.Pseudonym(KindSerial, f.HardwareUUID)
+	}
+	f.Sort()
+}
@@ -0,0 +1,119 @@
+package privacy
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+var testSalt = bytes.Repeat([]byte{7}, SaltSize)
+
+// hostFacts returns facts of one machine used by alice
+func hostFacts(hostname string) *collection.Facts {
+	ifaces := []types.NetworkInterface{{Name: "eth0", IPAddress: "10.0.0.5", MACAddress: "AA-BB-CC-DD-EE-FF"}}
+	return &collection.Facts{
+		Hostname:      hostname,
+		ComputerName:  hostname,
+		MachineOwner:  "alice",
+		Users:         []types.User{{Username: "alice", FullName: "Alice Example"}},
+		LoggedInUsers: []string{"alice"},
+		HomeDirs:      []string{"/home/alice/"},
+		PrimaryEmail:  "alice@example.com",
+		LocalIPs:      ifaces,
+		MACAddresses:  ifaces, // Shared, as the collector does
+		SerialNumber:  "c02xl0abcd",
+	}
+}
+
+// TestFacts_JoinableAcrossHosts verifies the same identifier gets the same
+// pseudonym on every machine, and raw values are gone
+func TestFacts_JoinableAcrossHosts(t *testing.T) {
+	p, err := New(testSalt, Kinds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := hostFacts("ws-01"), hostFacts("ws-02")
+	p.Facts(a)
+	p.Facts(b)
+
+	if a.MachineOwner != b.MachineOwner || a.Users[0].Username != a.MachineOwner {
+		t.Errorf("owner pseudonyms differ: %q, %q, %q", a.MachineOwner, b.MachineOwner, a.Users[0].Username)
+	}
+	if a.Hostname == b.Hostname {
+		t.Error("different hosts got the same pseudonym")
+	}
+	if a.Users[0].FullName != "" {
+		t.Error("full name kept")
+	}
+	if want := "/home/" + a.MachineOwner; a.HomeDirs[0] != want {
+		t.Errorf("HomeDirs = %v, want %s", a.HomeDirs, want)
+	}
+	if want := a.MachineOwner + "@example.com"; a.PrimaryEmail != want {
+		t.Errorf("PrimaryEmail = %q, want %q", a.PrimaryEmail, want)
+	}
+	if got := a.LocalIPs[0].MACAddress; got != a.MACAddresses[0].MACAddress || !strings.HasPrefix(got, "02:") {
+		t.Errorf("MAC pseudonyms %q, %q", got, a.MACAddresses[0].MACAddress)
+	}
+	if a.LocalIPs[0].MACAddress != p.Pseudonym(KindMAC, "aa:bb:cc:dd:ee:ff") {
+		t.Error("MAC spelling changed the pseudonym")
+	}
+	if a.SerialNumber != p.Pseudonym(KindSerial, "C02XL0ABCD") {
+		t.Error("serial case changed the pseudonym")
+	}
+}
+
+// TestFacts_OnlyConfiguredKinds verifies unselected identifiers are kept
+func TestFacts_OnlyConfiguredKinds(t *testing.T) {
+	p, err := New(testSalt, []Kind{KindHostname})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := hostFacts("ws-01")
+	p.Facts(f)
+	if f.Hostname == "ws-01" || !strings.HasPrefix(f.Hostname, "host-") {
+		t.Errorf("Hostname = %q", f.Hostname)
+	}
+	if f.MachineOwner != "alice" || f.SerialNumber != "c02xl0abcd" {
+		t.Error("unselected kinds were rewritten")
+	}
+}
+
+// TestPseudonym_SaltSeparates verifies another engagement's salt gives
+// unrelated pseudonyms
+func TestPseudonym_SaltSeparates(t *testing.T) {
+	p1, _ := New(testSalt, nil)
+	p2, _ := New(bytes.Repeat([]byte{8}, SaltSize), nil)
+	if p1.Pseudonym(KindUsername, "alice") == p2.Pseudonym(KindUsername, "alice") {
+		t.Error("salts produced the same pseudonym")
+	}
+	if p1.Pseudonym(KindUsername, "") != "" {
+		t.Error("empty identifier was pseudonymized")
+	}
+	if _, err := New(testSalt[:8], nil); err == nil {
+		t.Error("short salt accepted")
+	}
+}
+
+// TestSalt_RoundTrip verifies a sealed salt opens only with its passphrase
+// and engagement
+func TestSalt_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "engagement.salt")
+	if err := SaveSalt(path, testSalt, "correct horse", "ENG-42"); err != nil {
+		t.Fatal(err)
+	}
+	salt, err := LoadSalt(path, "correct horse", "ENG-42")
+	if err != nil || !bytes.Equal(salt, testSalt) {
+		t.Fatalf("LoadSalt = %x, %v", salt, err)
+	}
+	if _, err := LoadSalt(path, "wrong", "ENG-42"); !errors.Is(err, ErrPassphrase) {
+		t.Errorf("wrong passphrase: %v", err)
+	}
+	if _, err := LoadSalt(path, "correct horse", "ENG-43"); !errors.Is(err, ErrPassphrase) {
+		t.Errorf("wrong engagement: %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package privacy
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	coreio "github.com/minibeast/usb-agent/src/core/io"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/scrypt"
+)
+
+// PassphraseEnv names the environment variable holding the salt passphrase
+const PassphraseEnv = "MINIBEAST_SALT_PASSPHRASE"
+
+// SaltSize is the size of generated salts (256 bits)
+const SaltSize = 32
+
+// saltBlock is the PEM block type of a sealed salt file
+const saltBlock = "MINIBEAST PSEUDONYM SALT"
+
+// kdfSaltSize is the size of the scrypt salt stored with the sealed salt
+const kdfSaltSize = 16
+
+// ErrPassphrase is returned when a salt file does not open with the
+// passphrase and engagement given
+var ErrPassphrase = errors.New("wrong passphrase or engagement for salt file")
+
+// NewSalt returns a fresh random salt
+// Complexity: O(1)
+func NewSalt() ([]byte, error) {
+	salt := make([]byte, SaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	return salt, nil
+}
+
+// SealSalt encrypts salt under passphrase, bound to engagement
+// Format: PEM block holding scrypt salt (16) || nonce (24) || XChaCha20-
+// Poly1305 ciphertext. The engagement is authenticated, so a salt file
+// from another engagement fails to open instead of silently producing
+// unjoinable pseudonyms.
+// Complexity: O(scrypt)
+func SealSalt(salt []byte, passphrase, engagement string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("salt passphrase must not be empty (set %s)", PassphraseEnv)
+	}
+	kdfSalt := make([]byte, kdfSaltSize)
+	if _, err := rand.Read(kdfSalt); err != nil {
+		return nil, err
+	}
+	aead, err := saltAEAD(passphrase, kdfSalt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, chacha20poly1305.NonceSizeX)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	sealed := append(append(kdfSalt, nonce...), aead.Seal(nil, nonce, salt, saltAAD(engagement))...)
+	return pem.EncodeToMemory(&pem.Block{Type: saltBlock, Bytes: sealed}), nil
+}
+
+// OpenSalt decrypts a salt sealed by SealSalt
+// Complexity: O(scrypt)
+func OpenSalt(data []byte, passphrase, engagement string) ([]byte, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != saltBlock {
+		return nil, fmt.Errorf("not a %s file", saltBlock)
+	}
+	if len(block.Bytes) < kdfSaltSize+chacha20poly1305.NonceSizeX+chacha20poly1305.Overhead {
+		return nil, fmt.Errorf("salt file is truncated")
+	}
+	aead, err := saltAEAD(passphrase, block.Bytes[:kdfSaltSize])
+	if err != nil {
+		return nil, err
+	}
+	nonce := block.Bytes[kdfSaltSize : kdfSaltSize+chacha20poly1305.NonceSizeX]
+	salt, err := aead.Open(nil, nonce, block.Bytes[kdfSaltSize+chacha20poly1305.NonceSizeX:], saltAAD(engagement))
+	if err != nil {
+		return nil, ErrPassphrase
+	}
+	return salt, nil
+}
+
+// SaveSalt seals salt and writes it atomically to path (owner-only)
+// Complexity: O(scrypt)
+func SaveSalt(path string, salt []byte, passphrase, engagement string) error {
+	sealed, err := SealSalt(salt, passphrase, engagement)
+	if err != nil {
+		return err
+	}
+	return coreio.NewWriter().WriteAtomic(path, sealed, 0600)
+}
+
+// LoadSalt reads and opens the salt file at path
+// Complexity: O(scrypt)
+func LoadSalt(path, passphrase, engagement string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	salt, err := OpenSalt(data, passphrase, engagement)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return salt, nil
+}
+
+// saltAEAD derives the salt file key from passphrase
+func saltAEAD(passphrase string, kdfSalt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), kdfSalt, 1<<15, 8, 1, chacha20poly1305.KeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive salt key: %w", err)
+	}
+	return chacha20poly1305.NewX(key)
+}
+
+// saltAAD binds a sealed salt to its engagement
+func saltAAD(engagement string) []byte {
+	return []byte("minibeast-salt-v1\x00" + engagement)
+}
@@ -0,0 +1,119 @@
+// Package progress reports pipeline progress to interactive front-ends
+// Stage names match the telemetry span names (e.g., "collect.system_info",
+// "inference.generate") so traces and the live display line up.
+package progress
+
+import (
+	"context"
+	"time"
+)
+
+// State is the lifecycle position of a stage
+type State int
+
+const (
+	Started State = iota
+	Done
+	Failed
+	Running // Still in progress; Tokens so far (inference.generate only)
+)
+
+// String returns the state name
+func (s State) String() string {
+	switch s {
+	case Started:
+		return "started"
+	case Done:
+		return "done"
+	case Failed:
+		return "failed"
+	case Running:
+		return "running"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is one stage transition
+type Event struct {
+	Stage   string
+	State   State
+	Elapsed time.Duration // Set on Done, Failed and Running
+	Tokens  int           // Tokens generated (so far, on Running; inference.generate only)
+	Err     error         // Set on Failed
+}
+
+// Ended reports whether ev finishes its stage (Done or Failed)
+func (ev Event) Ended() bool {
+	return ev.State == Done || ev.State == Failed
+}
+
+// Reporter receives events; it may be called from concurrent goroutines
+type Reporter func(Event)
+
+// ctxKey carries the Reporter in a context
+type ctxKey struct{}
+
+// WithReporter returns a context whose pipeline stages report to r
+// Complexity: O(1)
+func WithReporter(ctx context.Context, r Reporter) context.Context {
+	return context.WithValue(ctx, ctxKey{}, r)
+}
+
+// AddReporter returns a context whose pipeline stages report to r as well
+// as to any Reporter ctx already carries
+// Complexity: O(1)
+func AddReporter(ctx context.Context, r Reporter) context.Context {
+	prev, _ := ctx.Value(ctxKey{}).(Reporter)
+	if prev == nil {
+		return WithReporter(ctx, r)
+	}
+	return WithReporter(ctx, func(ev Event) {
+		prev(ev)
+		r(ev)
+	})
+}
+
+// Step is a running stage (a no-op when ctx carries no Reporter)
+type Step struct {
+	report Reporter
+	stage  string
+	start  time.Time
+	tokens int
+}
+
+// Start reports that stage has begun
+// Complexity: O(1)
+func Start(ctx context.Context, stage string) *Step {
+	r, _ := ctx.Value(ctxKey{}).(Reporter)
+	s := &Step{report: r, stage: stage, start: time.Now()}
+	if r != nil {
+		r(Event{Stage: stage, State: Started})
+	}
+	return s
+}
+
+// SetTokens records the generated token count reported on End
+func (s *Step) SetTokens(n int) {
+	s.tokens = n
+}
+
+// Progress reports the stage as Running with n tokens generated so far
+func (s *Step) Progress(n int) {
+	if s.report == nil {
+		return
+	}
+	s.report(Event{Stage: s.stage, State: Running, Elapsed: time.Since(s.start), Tokens: n})
+}
+
+// End reports the stage as Done, or Failed when err is non-nil
+func (s *Step) End(err error) {
+	if s.report == nil {
+		return
+	}
+	ev := Event{Stage: s.stage, State: Done, Elapsed: time.Since(s.start), Tokens: s.tokens}
+	if err != nil {
+		ev.State, ev.Err = Failed, err
+	}
+	s.report(ev)
+}
@@ -0,0 +1,62 @@
+package progress
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestStep_ReportsLifecycle(t *testing.T) {
+	var events []Event
+	ctx := WithReporter(context.Background(), func(ev Event) { events = append(events, ev) })
+
+	ok := Start(ctx, "inference.generate")
+	ok.SetTokens(42)
+	ok.End(nil)
+	Start(ctx, "collect.pii_info").End(errors.New("denied"))
+
+	if len(events) != 4 {
+		t.Fatalf("got %d events, want 4", len(events))
+	}
+	if events[0].State != Started || events[1].State != Done || events[1].Tokens != 42 {
+		t.Errorf("unexpected generate events: %+v %+v", events[0], events[1])
+	}
+	if events[3].State != Failed || events[3].Err == nil || events[3].Stage != "collect.pii_info" {
+		t.Errorf("unexpected failure event: %+v", events[3])
+	}
+}
+
+func TestStep_Progress(t *testing.T) {
+	var events []Event
+	ctx := WithReporter(context.Background(), func(ev Event) { events = append(events, ev) })
+
+	step := Start(ctx, "inference.generate")
+	step.Progress(7)
+	step.End(nil)
+
+	if len(events) != 3 || events[1].State != Running || events[1].Tokens != 7 {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+	if events[0].Ended() || events[1].Ended() || !events[2].Ended() {
+		t.Errorf("Ended() = %v %v %v, want false false true", events[0].Ended(), events[1].Ended(), events[2].Ended())
+	}
+}
+
+func TestStep_NoReporter(t *testing.T) {
+	// Must not panic without a Reporter in the context
+	step := Start(context.Background(), "collect.system_info")
+	step.SetTokens(1)
+	step.Progress(1)
+	step.End(errors.New("ignored"))
+}
+
+func TestAddReporter_KeepsExisting(t *testing.T) {
+	var first, second []string
+	ctx := AddReporter(context.Background(), func(ev Event) { first = append(first, ev.Stage) })
+	ctx = AddReporter(ctx, func(ev Event) { second = append(second, ev.Stage) })
+
+	Start(ctx, "rules").End(nil)
+	if len(first) != 2 || len(second) != 2 {
+		t.Errorf("first = %v, second = %v, want both stages twice", first, second)
+	}
+}
@@ -0,0 +1,236 @@
+package provision
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/minibeast/usb-agent/src/core/export"
+)
+
+// ErrAborted is returned by Ask when the operator does not confirm the plan
+// or input ends before every question is answered
+var ErrAborted = errors.New("provisioning aborted")
+
+// asker reads answers to prompts, re-asking until one validates
+type asker struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// ask shows question with its default and returns the validated answer
+// A blank answer takes def; check may return an error to re-ask.
+func (a *asker) ask(question, def string, check func(string) error) (string, error) {
+	for {
+		if def != "" {
+			fmt.Fprintf(a.out, "%s [%s]: ", question, def)
+		} else {
+			fmt.Fprintf(a.out, "%s: ", question)
+		}
+		line, err := a.in.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return "", ErrAborted
+		}
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			answer = def
+		}
+		if check == nil {
+			return answer, nil
+		}
+		cerr := check(answer)
+		if cerr == nil {
+			return answer, nil
+		}
+		fmt.Fprintf(a.out, "  %v\n", cerr)
+	}
+}
+
+// confirm asks a yes/no question
+func (a *asker) confirm(question string, def bool) (bool, error) {
+	d := "n"
+	if def {
+		d = "y"
+	}
+	answer, err := a.ask(question+" (y/n)", d, func(s string) error {
+		switch strings.ToLower(s) {
+		case "y", "yes", "n", "no":
+			return nil
+		}
+		return fmt.Errorf("answer y or n")
+	})
+	if err != nil {
+		return false, err
+	}
+	return strings.HasPrefix(strings.ToLower(answer), "y"), nil
+}
+
+// Ask walks the operator through a Plan, offering defaults' values
+// Each answer is validated before moving on; the plan is summarized and must
+// be confirmed. Returns ErrAborted if the operator declines or input ends.
+// Complexity: O(|input|)
+func Ask(in io.Reader, out io.Writer, defaults Plan) (*Plan, error) {
+	a := &asker{in: bufio.NewReader(in), out: out}
+	plan := defaults
+	var err error
+
+	io.WriteString(out, "MiniBeast stick setup. Press Enter to accept the value in brackets.\n\n")
+
+	if plan.Target, err = a.ask("Stick root directory", plan.Target, func(s string) error {
+		if s == "" {
+			return fmt.Errorf("a directory is required")
+		}
+		if fi, err := os.Stat(s); err != nil || !fi.IsDir() {
+			return fmt.Errorf("%s is not a directory", s)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(Profiles))
+	for i, p := range Profiles {
+		names[i] = string(p)
+	}
+	profile, err := a.ask("Collection profile ("+strings.Join(names, ", ")+")", string(plan.Profile), func(s string) error {
+		if !slices.Contains(names, s) {
+			return fmt.Errorf("choose one of %s", strings.Join(names, ", "))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	plan.Profile = Profile(profile)
+
+	if plan.Profile == ProfileMinimal {
+		plan.PII = false
+	} else if plan.PII, err = a.confirm("Collect personal data (user accounts, home directories, email)?", plan.PII); err != nil {
+		return nil, err
+	}
+
+	formats, err := a.ask("Output formats ("+strings.Join(export.Formats(), ", ")+")", strings.Join(plan.Formats, ","), checkFormats)
+	if err != nil {
+		return nil, err
+	}
+	plan.Formats = splitList(formats)
+
+	if plan.Encrypt, err = a.confirm("Encrypt artifacts?", plan.Encrypt); err != nil {
+		return nil, err
+	}
+	if plan.Encrypt {
+		if plan.RecipientKey, err = a.ask("Recipient public key to install (blank to generate a keypair)", plan.RecipientKey, func(s string) error {
+			if s == "" {
+				return nil
+			}
+			_, err := os.Stat(s)
+			return err
+		}); err != nil {
+			return nil, err
+		}
+		if plan.RecipientKey == "" {
+			if plan.PrivateKeyOut, err = a.ask("Save the private key to (off the stick)", plan.PrivateKeyOut, func(s string) error {
+				if s == "" {
+					return fmt.Errorf("a path is required")
+				}
+				if inside, _ := within(plan.Target, s); inside {
+					return fmt.Errorf("the private key must not be stored on the stick")
+				}
+				if _, err := os.Stat(s); err == nil {
+					return fmt.Errorf("%s already exists", s)
+				}
+				return nil
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if plan.Model, err = a.ask("GGUF model path or https URL (blank disables summarization)", plan.Model, func(s string) error {
+		if s == "" || isURL(s) {
+			return nil
+		}
+		_, err := os.Stat(s)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	if plan.Model != "" {
+		if plan.ModelSHA256, err = a.ask("Expected model SHA-256 (blank to skip)", plan.ModelSHA256, checkDigest); err != nil {
+			return nil, err
+		}
+	}
+
+	if plan.Operator, err = a.ask("Default operator for unattended runs (blank requires a prompt)", plan.Operator, nil); err != nil {
+		return nil, err
+	}
+
+	io.WriteString(out, "\n"+plan.Summary()+"\n")
+	ok, err := a.confirm("Write this to the stick?", true)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrAborted
+	}
+	return &plan, nil
+}
+
+// Summary describes the plan for confirmation
+func (p *Plan) Summary() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "  Target:   %s\n", p.Target)
+	fmt.Fprintf(&b, "  Profile:  %s (personal data: %t)\n", p.Profile, p.PII)
+	fmt.Fprintf(&b, "  Formats:  %s\n", strings.Join(p.Formats, ", "))
+	switch {
+	case !p.Encrypt:
+		b.WriteString("  Keys:     encryption off\n")
+	case p.RecipientKey != "":
+		fmt.Fprintf(&b, "  Keys:     install %s\n", p.RecipientKey)
+	default:
+		fmt.Fprintf(&b, "  Keys:     generate, private key to %s\n", p.PrivateKeyOut)
+	}
+	if p.Model == "" {
+		b.WriteString("  Model:    none (summarization disabled)\n")
+	} else {
+		fmt.Fprintf(&b, "  Model:    %s -> %s\n", p.Model, filepath.Join(p.Target, ModelsDir, modelName(p.Model)))
+	}
+	return b.String()
+}
+
+// checkFormats accepts a comma-separated list of known formats
+func checkFormats(s string) error {
+	formats := splitList(s)
+	if len(formats) == 0 {
+		return fmt.Errorf("at least one format is required")
+	}
+	known := export.Formats()
+	for _, f := range formats {
+		if !slices.Contains(known, f) {
+			return fmt.Errorf("unknown format %q (known: %s)", f, strings.Join(known, ", "))
+		}
+	}
+	return nil
+}
+
+// checkDigest accepts blank or a hex SHA-256
+func checkDigest(s string) error {
+	if s == "" {
+		return nil
+	}
+	if b, err := hex.DecodeString(s); err != nil || len(b) != 32 {
+		return fmt.Errorf("expected 64 hex characters")
+	}
+	return nil
+}
+
+// splitList splits a comma- or space-separated list
+func splitList(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' })
+}
@@ -0,0 +1,301 @@
+package provision
+
+import (
+	"bufio"
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/crypto"
+	"github.com/minibeast/usb-agent/src/core/plugin"
+)
+
+// Integrity files at the root of a built stick
+const (
+	SumsFile      = "SHA256SUMS"     // "<sha256>  <path>" per file, sha256sum format
+	SignatureFile = "SHA256SUMS.sig" // Ed25519 signature over SumsFile
+)
+
+// builder tracks the files written to one stick
+type builder struct {
+	root string
+	sums map[string]string // Stick path -> SHA-256
+	log  io.Writer
+}
+
+// Build assembles the stick layout described by m under target, then
+// verifies it. SumsFile records every file written; with signer it is also
+// signed. An existing stick is only replaced when overwrite is set.
+// Complexity: O(total bytes written)
+func Build(ctx context.Context, m *Manifest, target string, signer *crypto.KeyPair, overwrite bool, log io.Writer) error {
+	cfg, err := config.Load(m.Config)
+	if err != nil {
+		return err
+	}
+	if err := checkPortable(cfg); err != nil {
+		return fmt.Errorf("%s: %w", m.Config, err)
+	}
+	for _, existing := range []string{SumsFile, ConfigPath} {
+		if _, err := os.Stat(filepath.Join(target, filepath.FromSlash(existing))); err == nil && !overwrite {
+			return fmt.Errorf("%w: %s", ErrExists, filepath.Join(target, filepath.FromSlash(existing)))
+		}
+	}
+
+	b := &builder{root: target, sums: map[string]string{}, log: log}
+	for _, name := range sortedKeys(m.Binaries) {
+		if err := b.copy(m.Binaries[name], path.Join("bin", name), 0755); err != nil {
+			return err
+		}
+	}
+	if err := b.copy(m.Config, ConfigPath, 0644); err != nil {
+		return err
+	}
+	for _, key := range m.PublicKeys {
+		if !isPublicKey(key) {
+			return fmt.Errorf("%s is not a recipient or verification public key", key)
+		}
+		if err := b.copy(key, path.Join("config", "keys", filepath.Base(key)), 0644); err != nil {
+			return err
+		}
+	}
+	if m.Model != nil {
+		if err := b.model(ctx, m.Model); err != nil {
+			return err
+		}
+	}
+	if m.Plugins != "" {
+		if err := b.tree(m.Plugins, path.Clean(filepath.ToSlash(cfg.Plugins.Directory))); err != nil {
+			return err
+		}
+	}
+	for _, dest := range sortedKeys(m.Files) {
+		if err := b.copy(m.Files[dest], dest, 0); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(target, cfg.Output.Directory), 0755); err != nil {
+		return err
+	}
+
+	if err := checkReferences(cfg, target, b.sums); err != nil {
+		return err
+	}
+	if err := b.writeSums(signer); err != nil {
+		return err
+	}
+
+	var trusted ed25519.PublicKey
+	if signer != nil {
+		trusted = signer.PublicKey
+	}
+	n, err := Verify(target, trusted)
+	if err != nil {
+		return fmt.Errorf("verification of %s failed: %w", target, err)
+	}
+	fmt.Fprintf(log, "verified %d files on %s\n", n, target)
+	return nil
+}
+
+// copy installs src at the stick path dest (mode 0 keeps src's permissions)
+func (b *builder) copy(src, dest string, mode fs.FileMode) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", src)
+	}
+	if mode == 0 {
+		mode = info.Mode().Perm()
+	}
+	if _, dup := b.sums[dest]; dup {
+		return fmt.Errorf("%s is written twice", dest)
+	}
+
+	full := filepath.Join(b.root, filepath.FromSlash(dest))
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		return err
+	}
+	digest, err := copyFile(src, full)
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(full, mode); err != nil {
+		return err
+	}
+	b.sums[dest] = digest
+	fmt.Fprintf(b.log, "  %s\n", dest)
+	return nil
+}
+
+// model installs the model and its "<model>.sha256" file under models/
+func (b *builder) model(ctx context.Context, src *ModelSource) error {
+	dest := src.StickPath()
+	full := filepath.Join(b.root, filepath.FromSlash(dest))
+	digest, err := fetchModel(ctx, src.Source, full, src.SHA256, b.log)
+	if err != nil {
+		return err
+	}
+	sumPath, err := writeModelSum(full, digest)
+	if err != nil {
+		return err
+	}
+	sumDigest, err := copyFile(sumPath, "")
+	if err != nil {
+		return err
+	}
+	b.sums[dest] = digest
+	b.sums[dest+".sha256"] = sumDigest
+	fmt.Fprintf(b.log, "  %s\n", dest)
+	return nil
+}
+
+// tree copies the regular files below dir to the stick path dest
+func (b *builder) tree(dir, dest string) error {
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return fmt.Errorf("%s is not a regular file", p)
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		return b.copy(p, path.Join(dest, filepath.ToSlash(rel)), 0)
+	})
+}
+
+// writeSums writes SumsFile and, with signer, SignatureFile
+func (b *builder) writeSums(signer *crypto.KeyPair) error {
+	paths := sortedKeys(b.sums)
+	var sb strings.Builder
+	for _, p := range paths {
+		fmt.Fprintf(&sb, "%s  %s\n", b.sums[p], p)
+	}
+	sumsPath := filepath.Join(b.root, SumsFile)
+	if err := os.WriteFile(sumsPath, []byte(sb.String()), 0644); err != nil {
+		return err
+	}
+	if signer == nil {
+		os.Remove(filepath.Join(b.root, SignatureFile)) // Stale from an earlier build
+		return nil
+	}
+	sig, err := crypto.NewSigner(signer).Sign([]byte(sb.String()))
+	if err != nil {
+		return err
+	}
+	return crypto.SaveSignature(sig, filepath.Join(b.root, SignatureFile))
+}
+
+// Verify checks every file listed in a stick's SumsFile
+// With trusted, SignatureFile must also verify against it. Returns the
+// number of files checked.
+// Complexity: O(total bytes listed)
+func Verify(root string, trusted ed25519.PublicKey) (int, error) {
+	sumsPath := filepath.Join(root, SumsFile)
+	data, err := os.ReadFile(sumsPath)
+	if err != nil {
+		return 0, err
+	}
+	if trusted != nil {
+		sig, err := crypto.LoadSignature(filepath.Join(root, SignatureFile))
+		if err != nil {
+			return 0, err
+		}
+		if !crypto.Verify(trusted, data, sig) {
+			return 0, fmt.Errorf("%s signature does not verify", SumsFile)
+		}
+	}
+
+	var failed []string
+	n := 0
+	sc := bufio.NewScanner(strings.NewReader(string(data)))
+	for sc.Scan() {
+		want, p, ok := strings.Cut(sc.Text(), "  ")
+		if !ok || checkDigest(want) != nil || want == "" || !localPath(p) {
+			return n, fmt.Errorf("%s: malformed line %q", SumsFile, sc.Text())
+		}
+		got, err := copyFile(filepath.Join(root, filepath.FromSlash(p)), "")
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			failed = append(failed, p+" missing")
+		case err != nil:
+			return n, err
+		case got != want:
+			failed = append(failed, p+" modified")
+		}
+		n++
+	}
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return n, fmt.Errorf("%s", strings.Join(failed, ", "))
+	}
+	return n, nil
+}
+
+// checkPortable rejects config paths that would not resolve on the stick
+func checkPortable(cfg *config.Config) error {
+	paths := map[string]string{
+		"output.directory":  cfg.Output.Directory,
+		"plugins.directory": cfg.Plugins.Directory,
+	}
+	if cfg.LLM.Enabled {
+		paths["llm.model_path"] = cfg.LLM.ModelPath
+	}
+	if cfg.Output.Encrypt {
+		for i, k := range cfg.Output.RecipientKeys {
+			paths[fmt.Sprintf("output.recipient_keys[%d]", i)] = k
+		}
+	}
+	for _, field := range sortedKeys(paths) {
+		if !localPath(path.Clean(filepath.ToSlash(paths[field]))) {
+			return &config.ValidationError{Field: field, Reason: "must be relative to the stick root"}
+		}
+	}
+	return nil
+}
+
+// checkReferences confirms the files the config points at are on the stick
+func checkReferences(cfg *config.Config, root string, sums map[string]string) error {
+	if cfg.LLM.Enabled {
+		if _, ok := sums[path.Clean(filepath.ToSlash(cfg.LLM.ModelPath))]; !ok {
+			return fmt.Errorf("llm.model_path %s is not in the bundle", cfg.LLM.ModelPath)
+		}
+	}
+	if cfg.Output.Encrypt {
+		for _, k := range cfg.Output.RecipientKeys {
+			if _, ok := sums[path.Clean(filepath.ToSlash(k))]; !ok {
+				return fmt.Errorf("recipient key %s is not in the bundle", k)
+			}
+			if _, err := crypto.LoadRecipientPublicKey(filepath.Join(root, k)); err != nil {
+				return fmt.Errorf("recipient key %s: %w", k, err)
+			}
+		}
+	}
+	if cfg.Plugins.Enabled {
+		if _, err := plugin.Discover(filepath.Join(root, cfg.Plugins.Directory), 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isPublicKey reports whether path holds a recipient or verification public key
+func isPublicKey(path string) bool {
+	if _, err := crypto.LoadRecipientPublicKey(path); err == nil {
+		return true
+	}
+	_, err := crypto.LoadPublicKey(path)
+	return err == nil
+}
@@ -0,0 +1,175 @@
+package provision
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/minibeast/usb-agent/src/core/crypto"
+)
+
+// writeManifest lays out sources for a stick and returns their manifest
+func writeManifest(t *testing.T, configYAML string) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"build/minibeast-linux":   "ELF",
+		"build/minibeast-win.exe": "MZ",
+		"models/tiny.gguf":        string(model),
+		"config.yaml":             configYAML,
+		"launch/Run-MiniBeast.sh": "#!/bin/sh\n",
+		"plugins/tag/plugin.yaml": "name: tag\nkind: rules\ncommand: [./tag]\n",
+		"plugins/tag/tag":         "#!/bin/sh\n",
+	}
+	for name, body := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	key, err := crypto.GenerateRecipientKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := crypto.SaveRecipientPublicKey(key.PublicKey(), filepath.Join(dir, "customer.pub.pem")); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest := `binaries:
+  minibeast-linux: build/minibeast-linux
+  minibeast-win.exe: build/minibeast-win.exe
+model:
+  source: models/tiny.gguf
+  sha256: ` + modelDigest() + `
+config: config.yaml
+public_keys: [customer.pub.pem]
+plugins: plugins
+files:
+  launch/Run-MiniBeast.sh: launch/Run-MiniBeast.sh
+`
+	path := filepath.Join(dir, "provision.yaml")
+	if err := os.WriteFile(path, []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const stickConfig = `output:
+  encrypt: true
+  recipient_keys: ["config/keys/customer.pub.pem"]
+llm:
+  model_path: "models/tiny.gguf"
+`
+
+func TestBuild_SignedAndVerified(t *testing.T) {
+	m, err := LoadManifest(writeManifest(t, stickConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := crypto.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := t.TempDir()
+	if err := Build(context.Background(), m, target, signer, false, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+
+	sums, err := os.ReadFile(filepath.Join(target, SumsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{"bin/minibeast-linux", "config/default.yaml", "config/keys/customer.pub.pem",
+		"models/tiny.gguf", "models/tiny.gguf.sha256", "plugins/tag/plugin.yaml", "launch/Run-MiniBeast.sh"} {
+		if !strings.Contains(string(sums), "  "+p+"\n") {
+			t.Errorf("%s missing from %s:\n%s", p, SumsFile, sums)
+		}
+	}
+	if info, err := os.Stat(filepath.Join(target, "bin", "minibeast-linux")); err != nil || info.Mode().Perm()&0100 == 0 {
+		t.Errorf("binary not executable: %v", err)
+	}
+	if n, err := Verify(target, signer.PublicKey); err != nil || n != 9 {
+		t.Errorf("Verify = %d, %v", n, err)
+	}
+
+	// Rebuilding requires overwrite
+	if err := Build(context.Background(), m, target, nil, false, io.Discard); !errors.Is(err, ErrExists) {
+		t.Errorf("rebuild: err = %v, want ErrExists", err)
+	}
+
+	// Tampering and a foreign signing key are both caught
+	if err := os.WriteFile(filepath.Join(target, "bin", "minibeast-linux"), []byte("evil"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Verify(target, nil); err == nil || !strings.Contains(err.Error(), "bin/minibeast-linux modified") {
+		t.Errorf("tampered: err = %v", err)
+	}
+	other, _ := crypto.GenerateKeyPair()
+	if _, err := Verify(target, other.PublicKey); err == nil {
+		t.Error("signature verified against the wrong key")
+	}
+}
+
+func TestBuild_RejectsUnresolvableConfig(t *testing.T) {
+	tests := map[string]string{
+		"model not in bundle": "llm:\n  model_path: \"models/other.gguf\"\n",
+		"absolute path":       "llm:\n  model_path: \"/opt/models/tiny.gguf\"\n",
+		"key not in bundle":   "output:\n  encrypt: true\n  recipient_keys: [\"keys/soc.pub.pem\"]\nllm:\n  model_path: \"models/tiny.gguf\"\n",
+	}
+	for name, cfg := range tests {
+		t.Run(name, func(t *testing.T) {
+			m, err := LoadManifest(writeManifest(t, cfg))
+			if err != nil {
+				t.Fatal(err)
+			}
+			target := t.TempDir()
+			if err := Build(context.Background(), m, target, nil, false, io.Discard); err == nil {
+				t.Fatal("Build succeeded")
+			}
+			if _, err := os.Stat(filepath.Join(target, SumsFile)); !os.IsNotExist(err) {
+				t.Error("sums written for a failed build")
+			}
+		})
+	}
+}
+
+func TestLoadManifest_Rejects(t *testing.T) {
+	tests := map[string]string{
+		"unknown field":      "binaries: {a: a}\nconfig: c\nextra: 1\n",
+		"no config":          "binaries: {a: a}\n",
+		"no binaries":        "config: c\n",
+		"binary path":        "binaries: {bin/a: a}\nconfig: c\n",
+		"unpinned model":     "binaries: {a: a}\nconfig: c\nmodel: {source: m.gguf}\n",
+		"escaping file":      "binaries: {a: a}\nconfig: c\nfiles: {../etc/passwd: x}\n",
+		"absolute file":      "binaries: {a: a}\nconfig: c\nfiles: {/etc/passwd: x}\n",
+		"duplicate key name": "binaries: {a: a}\nconfig: c\npublic_keys: [x/k.pem, y/k.pem]\n",
+	}
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "provision.yaml")
+			if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadManifest(path); err == nil {
+				t.Error("manifest accepted")
+			}
+		})
+	}
+}
+
+func TestLoadManifest_Example(t *testing.T) {
+	m, err := LoadManifest(filepath.Join("..", "..", "..", "scripts", "provision.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Model.StickPath() != "models/tinyllama-1.1b-chat-v1.0.Q2_K.gguf" {
+		t.Errorf("model stick path = %s", m.Model.StickPath())
+	}
+}
@@ -0,0 +1,151 @@
+package provision
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest describes a stick layout for Build (e.g., provision.yaml)
+// Source paths are relative to the manifest's directory; destinations are
+// slash-separated paths relative to the stick root.
+type Manifest struct {
+	// Built agents installed under bin/ (file name -> source)
+	Binaries map[string]string `yaml:"binaries"`
+
+	// GGUF model installed under models/ (optional)
+	Model *ModelSource `yaml:"model"`
+
+	// Agent config installed as config/default.yaml
+	Config string `yaml:"config"`
+
+	// Public keys (recipient or verification) installed under config/keys/
+	PublicKeys []string `yaml:"public_keys"`
+
+	// Plugin directory copied to the config's plugins.directory (optional)
+	Plugins string `yaml:"plugins"`
+
+	// Other files such as launchers and docs (destination -> source)
+	Files map[string]string `yaml:"files"`
+}
+
+// ModelSource is where Build obtains the model
+type ModelSource struct {
+	Source string `yaml:"source"` // Local path or http(s) URL
+	SHA256 string `yaml:"sha256"` // Required; bundles are reproducible
+}
+
+// StickPath is where Build installs the model, relative to the stick root
+func (s *ModelSource) StickPath() string {
+	return path.Join(ModelsDir, modelName(s.Source))
+}
+
+// LoadManifest reads a manifest, resolving its sources against its directory
+// Complexity: O(|manifest|)
+func LoadManifest(manifestPath string) (*Manifest, error) {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	var m Manifest
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&m); err != nil {
+		return nil, fmt.Errorf("%s: %w", manifestPath, err)
+	}
+
+	base := filepath.Dir(manifestPath)
+	resolve := func(p string) string {
+		if p == "" || filepath.IsAbs(p) || isURL(p) {
+			return p
+		}
+		return filepath.Join(base, filepath.FromSlash(p))
+	}
+	for name, src := range m.Binaries {
+		m.Binaries[name] = resolve(src)
+	}
+	if m.Model != nil {
+		m.Model.Source = resolve(m.Model.Source)
+	}
+	m.Config = resolve(m.Config)
+	for i, k := range m.PublicKeys {
+		m.PublicKeys[i] = resolve(k)
+	}
+	m.Plugins = resolve(m.Plugins)
+	for dest, src := range m.Files {
+		m.Files[dest] = resolve(src)
+	}
+
+	if err := m.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", manifestPath, err)
+	}
+	return &m, nil
+}
+
+// validate checks required fields and that destinations stay on the stick
+func (m *Manifest) validate() error {
+	if m.Config == "" {
+		return fmt.Errorf("config is required")
+	}
+	if len(m.Binaries) == 0 {
+		return fmt.Errorf("at least one binary is required")
+	}
+	for name, src := range m.Binaries {
+		if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+			return fmt.Errorf("binary name %q must be a plain file name", name)
+		}
+		if src == "" {
+			return fmt.Errorf("binary %s has no source", name)
+		}
+	}
+	if m.Model != nil {
+		if m.Model.Source == "" {
+			return fmt.Errorf("model.source is required")
+		}
+		if m.Model.SHA256 == "" {
+			return fmt.Errorf("model.sha256 is required")
+		}
+		if err := checkDigest(m.Model.SHA256); err != nil {
+			return fmt.Errorf("model.sha256: %w", err)
+		}
+	}
+	seen := map[string]bool{}
+	for _, k := range m.PublicKeys {
+		name := filepath.Base(k)
+		if seen[name] {
+			return fmt.Errorf("public key %s listed twice", name)
+		}
+		seen[name] = true
+	}
+	for dest, src := range m.Files {
+		if !localPath(dest) {
+			return fmt.Errorf("file destination %q must be a relative path on the stick", dest)
+		}
+		if src == "" {
+			return fmt.Errorf("file %s has no source", dest)
+		}
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of a string map in order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// localPath reports whether p is a clean slash-separated path inside the stick
+func localPath(p string) bool {
+	return p != "" && !path.IsAbs(p) && !filepath.IsAbs(p) && !strings.Contains(p, `\`) &&
+		path.Clean(p) == p && p != "." && p != ".." && !strings.HasPrefix(p, "../")
+}
@@ -0,0 +1,365 @@
+// Package provision prepares a MiniBeast stick: config, keys and model
+// Ask walks an operator through the choices (the init wizard) and Apply
+// writes the result under the stick's root, replacing manual provisioning.
+package provision
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/crypto"
+)
+
+// Layout of a provisioned stick (relative to its root)
+const (
+	ConfigPath       = "config/default.yaml"
+	RecipientKeyPath = "config/keys/recipient.pub.pem"
+	ModelsDir        = "models"
+)
+
+// ErrExists is returned by Apply when the stick already has a config
+var ErrExists = errors.New("config already exists")
+
+// Profile is a collection preset
+type Profile string
+
+const (
+	ProfileMinimal  Profile = "minimal"  // System, network and OS facts only
+	ProfileStandard Profile = "standard" // Defaults, including users
+	ProfileFull     Profile = "full"     // Standard plus extended collection
+)
+
+// Profiles lists the presets in the order offered
+var Profiles = []Profile{ProfileMinimal, ProfileStandard, ProfileFull}
+
+// apply sets the collection options of p on cfg
+func (p Profile) apply(cfg *config.Config) {
+	switch p {
+	case ProfileMinimal:
+		cfg.PII = false
+		cfg.Collect.WiFiSSIDs = false
+		cfg.Collect.HardwareIDs = false
+	case ProfileFull:
+		cfg.Collect.Extended = true
+	}
+}
+
+// Plan is everything needed to provision a stick
+type Plan struct {
+	// Stick root; everything but PrivateKeyOut is written below it
+	Target string
+
+	// Collection preset
+	Profile Profile
+
+	// Collect personal data (users, home directories, email); false redacts it at the source
+	PII bool
+
+	// Artifact encodings (output.formats)
+	Formats []string
+
+	// Encrypt artifacts to a recipient key
+	Encrypt bool
+
+	// Existing X25519 recipient public key to install (empty = generate a keypair)
+	RecipientKey string
+
+	// Where a generated recipient private key is saved; must be off the stick
+	PrivateKeyOut string
+
+	// GGUF model: local path or http(s) URL (empty = summarization disabled)
+	Model string
+
+	// Expected SHA-256 of the model (hex, optional)
+	ModelSHA256 string
+
+	// Default operator for -assume-yes and unattended runs (consent.operator)
+	Operator string
+}
+
+// Defaults returns the plan offered when the operator accepts every default
+func Defaults() Plan {
+	cfg := config.Default()
+	return Plan{
+		Target:        ".",
+		Profile:       ProfileStandard,
+		PII:           cfg.PII,
+		Formats:       cfg.Output.Formats,
+		PrivateKeyOut: "minibeast-recipient.pem",
+	}
+}
+
+// Config builds the agent config for plan
+// Complexity: O(1)
+func (p *Plan) Config() (*config.Config, error) {
+	cfg := config.Default()
+	p.Profile.apply(cfg)
+	cfg.PII = p.PII
+	cfg.Output.Formats = append([]string(nil), p.Formats...)
+	cfg.Consent.Operator = p.Operator
+
+	cfg.Output.Encrypt = p.Encrypt
+	if p.Encrypt {
+		cfg.Output.RecipientKeys = []string{RecipientKeyPath}
+	}
+
+	cfg.LLM.Enabled = p.Model != ""
+	if p.Model != "" {
+		cfg.LLM.ModelPath = path.Join(ModelsDir, modelName(p.Model))
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Apply provisions plan.Target, reporting each step to log
+// Returns every file written. An existing config is only replaced when
+// overwrite is set; an existing private key never is.
+// Complexity: O(|model|) for the copy or download and its digest
+func Apply(ctx context.Context, plan *Plan, overwrite bool, log io.Writer) ([]string, error) {
+	cfg, err := plan.Config()
+	if err != nil {
+		return nil, err
+	}
+	root := plan.Target
+	configPath := filepath.Join(root, filepath.FromSlash(ConfigPath))
+	if _, err := os.Stat(configPath); err == nil && !overwrite {
+		return nil, fmt.Errorf("%w: %s", ErrExists, configPath)
+	}
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		return nil, err
+	}
+
+	written := []string{}
+	if plan.Encrypt {
+		paths, err := installRecipientKey(plan, log)
+		written = append(written, paths...)
+		if err != nil {
+			return written, err
+		}
+	}
+	if plan.Model != "" {
+		paths, err := installModel(ctx, plan, cfg.LLM.ModelPath, log)
+		written = append(written, paths...)
+		if err != nil {
+			return written, err
+		}
+	}
+
+	if err := config.Save(cfg, configPath); err != nil {
+		return written, err
+	}
+	fmt.Fprintf(log, "wrote config %s\n", configPath)
+	return append(written, configPath), nil
+}
+
+// installRecipientKey copies the given recipient key to the stick, or
+// generates a keypair with the private half kept off the stick
+func installRecipientKey(plan *Plan, log io.Writer) ([]string, error) {
+	pubPath := filepath.Join(plan.Target, filepath.FromSlash(RecipientKeyPath))
+	if err := os.MkdirAll(filepath.Dir(pubPath), 0755); err != nil {
+		return nil, err
+	}
+
+	if plan.RecipientKey != "" {
+		pub, err := crypto.LoadRecipientPublicKey(plan.RecipientKey)
+		if err != nil {
+			return nil, err
+		}
+		if err := crypto.SaveRecipientPublicKey(pub, pubPath); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(log, "installed recipient key %s (%s)\n", pubPath, crypto.RecipientKeyID(pub))
+		return []string{pubPath}, nil
+	}
+
+	inside, err := within(plan.Target, plan.PrivateKeyOut)
+	if err != nil {
+		return nil, err
+	}
+	if inside {
+		return nil, fmt.Errorf("private key %s must not be written to the stick", plan.PrivateKeyOut)
+	}
+	if _, err := os.Stat(plan.PrivateKeyOut); err == nil {
+		return nil, fmt.Errorf("%s already exists; refusing to overwrite a private key", plan.PrivateKeyOut)
+	}
+
+	key, err := crypto.GenerateRecipientKey()
+	if err != nil {
+		return nil, err
+	}
+	if err := crypto.SaveRecipientPrivateKey(key, plan.PrivateKeyOut); err != nil {
+		return nil, err
+	}
+	if err := crypto.SaveRecipientPublicKey(key.PublicKey(), pubPath); err != nil {
+		return []string{plan.PrivateKeyOut}, err
+	}
+	fmt.Fprintf(log, "generated recipient keypair %s: private key %s (keep it safe), public key %s\n",
+		crypto.RecipientKeyID(key.PublicKey()), plan.PrivateKeyOut, pubPath)
+	return []string{plan.PrivateKeyOut, pubPath}, nil
+}
+
+// installModel copies or downloads the model onto the stick, verifies the
+// expected digest and writes the "<model>.sha256" file doctor checks
+func installModel(ctx context.Context, plan *Plan, modelPath string, log io.Writer) ([]string, error) {
+	dest := filepath.Join(plan.Target, filepath.FromSlash(modelPath))
+	digest, err := fetchModel(ctx, plan.Model, dest, plan.ModelSHA256, log)
+	if err != nil {
+		return nil, err
+	}
+	sumPath, err := writeModelSum(dest, digest)
+	if err != nil {
+		return []string{dest}, err
+	}
+	fmt.Fprintf(log, "installed model %s (sha256 %s)\n", dest, digest)
+	return []string{dest, sumPath}, nil
+}
+
+// fetchModel copies or downloads source to dest and checks it against want
+// (hex, optional). Returns the model's SHA-256; a mismatched model is removed.
+func fetchModel(ctx context.Context, source, dest, want string, log io.Writer) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return "", err
+	}
+
+	var digest string
+	var err error
+	switch {
+	case isURL(source):
+		fmt.Fprintf(log, "downloading %s\n", source)
+		digest, err = download(ctx, source, dest)
+	case sameFile(source, dest):
+		fmt.Fprintf(log, "verifying %s\n", dest)
+		digest, err = copyFile(dest, "")
+	default:
+		fmt.Fprintf(log, "copying %s\n", source)
+		digest, err = copyFile(source, dest)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if want = strings.ToLower(want); want != "" && want != digest {
+		os.Remove(dest)
+		return "", fmt.Errorf("model checksum mismatch (got %s, want %s)", digest, want)
+	}
+	return digest, nil
+}
+
+// writeModelSum writes the "<model>.sha256" file doctor checks
+func writeModelSum(model, digest string) (string, error) {
+	sumPath := model + ".sha256"
+	return sumPath, os.WriteFile(sumPath, []byte(digest+"  "+filepath.Base(model)+"\n"), 0644)
+}
+
+// download fetches url to dest via a temporary file, returning its SHA-256
+func download(ctx context.Context, rawURL, dest string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("model download failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("model download failed: %s", resp.Status)
+	}
+	return writeHashed(resp.Body, dest)
+}
+
+// copyFile copies src to dest (dest "" only hashes src), returning the SHA-256
+func copyFile(src, dest string) (string, error) {
+	f, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if dest == "" {
+		h := sha256.New()
+		if _, err := io.Copy(h, f); err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+	return writeHashed(f, dest)
+}
+
+// writeHashed streams r into dest atomically, returning its SHA-256
+func writeHashed(r io.Reader, dest string) (string, error) {
+	tmp := dest + ".part"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(f, h), r)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, dest)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// modelName is the file name the model gets on the stick
+func modelName(model string) string {
+	if isURL(model) {
+		if u, err := url.Parse(model); err == nil && path.Base(u.Path) != "/" && path.Base(u.Path) != "." {
+			return path.Base(u.Path)
+		}
+		return "model.gguf"
+	}
+	return filepath.Base(model)
+}
+
+// isURL reports whether model is an http(s) URL
+func isURL(model string) bool {
+	return strings.HasPrefix(model, "https://") || strings.HasPrefix(model, "http://")
+}
+
+// sameFile reports whether a and b are the same existing file
+func sameFile(a, b string) bool {
+	ai, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	bi, err := os.Stat(b)
+	return err == nil && os.SameFile(ai, bi)
+}
+
+// within reports whether p lies inside dir
+func within(dir, p string) (bool, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return false, err
+	}
+	absP, err := filepath.Abs(p)
+	if err != nil {
+		return false, err
+	}
+	rel, err := filepath.Rel(absDir, absP)
+	if err != nil {
+		return false, nil // Different volume
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)), nil
+}
@@ -0,0 +1,192 @@
+package provision
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/crypto"
+)
+
+var model = []byte("GGUF\x03\x00\x00\x00weights")
+
+// writeModel creates a GGUF-looking model outside the stick
+func writeModel(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "tiny.gguf")
+	if err := os.WriteFile(path, model, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func modelDigest() string {
+	sum := sha256.Sum256(model)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestAsk_DefaultsAndValidation(t *testing.T) {
+	target := t.TempDir()
+	modelPath := writeModel(t)
+	keyOut := filepath.Join(t.TempDir(), "recipient.pem")
+	script := strings.Join([]string{
+		target,
+		"huge",    // Unknown profile, re-asked
+		"minimal", // Skips the personal data question
+		"json,bogus",
+		"json,csv",
+		"y",
+		"",                                   // Generate a keypair
+		filepath.Join(target, "private.pem"), // On the stick, re-asked
+		keyOut,
+		modelPath,
+		"abc", // Not a digest, re-asked
+		modelDigest(),
+		"JD",
+		"y",
+	}, "\n") + "\n"
+
+	var out strings.Builder
+	plan, err := Ask(strings.NewReader(script), &out, Defaults())
+	if err != nil {
+		t.Fatalf("Ask: %v\n%s", err, out.String())
+	}
+	if plan.Profile != ProfileMinimal || plan.PII {
+		t.Errorf("profile = %s, pii = %t", plan.Profile, plan.PII)
+	}
+	if strings.Join(plan.Formats, ",") != "json,csv" {
+		t.Errorf("formats = %v", plan.Formats)
+	}
+	if !plan.Encrypt || plan.RecipientKey != "" || plan.PrivateKeyOut != keyOut {
+		t.Errorf("keys = %+v", plan)
+	}
+	if plan.Model != modelPath || plan.ModelSHA256 != modelDigest() || plan.Operator != "JD" {
+		t.Errorf("plan = %+v", plan)
+	}
+	for _, msg := range []string{"choose one of", "unknown format", "must not be stored on the stick", "64 hex characters"} {
+		if !strings.Contains(out.String(), msg) {
+			t.Errorf("output missing %q", msg)
+		}
+	}
+}
+
+func TestAsk_Aborted(t *testing.T) {
+	// Declined at the confirmation
+	script := strings.Repeat("\n", 7) + "n\n"
+	if _, err := Ask(strings.NewReader(script), io.Discard, Defaults()); !errors.Is(err, ErrAborted) {
+		t.Errorf("declined: err = %v, want ErrAborted", err)
+	}
+	// Input ends early
+	if _, err := Ask(strings.NewReader("\n"), io.Discard, Defaults()); !errors.Is(err, ErrAborted) {
+		t.Errorf("EOF: err = %v, want ErrAborted", err)
+	}
+}
+
+func TestApply_GeneratesKeysAndCopiesModel(t *testing.T) {
+	target := t.TempDir()
+	keyOut := filepath.Join(t.TempDir(), "recipient.pem")
+	plan := &Plan{
+		Target:        target,
+		Profile:       ProfileFull,
+		PII:           true,
+		Formats:       []string{"json"},
+		Encrypt:       true,
+		PrivateKeyOut: keyOut,
+		Model:         writeModel(t),
+		ModelSHA256:   strings.ToUpper(modelDigest()),
+	}
+
+	if _, err := Apply(context.Background(), plan, false, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.Load(filepath.Join(target, ConfigPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Collect.Extended || !cfg.Output.Encrypt || cfg.Output.RecipientKeys[0] != RecipientKeyPath {
+		t.Errorf("config = %+v", cfg)
+	}
+	if !cfg.LLM.Enabled || cfg.LLM.ModelPath != "models/tiny.gguf" {
+		t.Errorf("llm = %+v", cfg.LLM)
+	}
+
+	priv, err := crypto.LoadRecipientPrivateKey(keyOut)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := crypto.LoadRecipientPublicKey(filepath.Join(target, RecipientKeyPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pub.Equal(priv.PublicKey()) {
+		t.Error("installed public key does not match the generated private key")
+	}
+
+	sum, err := os.ReadFile(filepath.Join(target, "models", "tiny.gguf.sha256"))
+	if err != nil || string(sum) != modelDigest()+"  tiny.gguf\n" {
+		t.Errorf("checksum file = %q, %v", sum, err)
+	}
+
+	// A second run must not clobber the stick
+	plan.PrivateKeyOut = filepath.Join(t.TempDir(), "other.pem")
+	if _, err := Apply(context.Background(), plan, false, io.Discard); !errors.Is(err, ErrExists) {
+		t.Errorf("second Apply: err = %v, want ErrExists", err)
+	}
+}
+
+func TestApply_DownloadChecksumMismatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(model)
+	}))
+	defer srv.Close()
+
+	target := t.TempDir()
+	plan := &Plan{
+		Target:      target,
+		Profile:     ProfileStandard,
+		Formats:     []string{"json"},
+		Model:       srv.URL + "/models/tiny.gguf",
+		ModelSHA256: strings.Repeat("0", 64),
+	}
+	if _, err := Apply(context.Background(), plan, false, io.Discard); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("err = %v, want checksum mismatch", err)
+	}
+	if _, err := os.Stat(filepath.Join(target, "models", "tiny.gguf")); !os.IsNotExist(err) {
+		t.Error("mismatched model left on the stick")
+	}
+	if _, err := os.Stat(filepath.Join(target, ConfigPath)); !os.IsNotExist(err) {
+		t.Error("config written despite the failed model install")
+	}
+
+	plan.ModelSHA256 = ""
+	if _, err := Apply(context.Background(), plan, false, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := os.ReadFile(filepath.Join(target, "models", "tiny.gguf")); err != nil || string(b) != string(model) {
+		t.Errorf("downloaded model = %q, %v", b, err)
+	}
+}
+
+func TestApply_PrivateKeyOnStickRefused(t *testing.T) {
+	target := t.TempDir()
+	plan := &Plan{
+		Target:        target,
+		Profile:       ProfileStandard,
+		Formats:       []string{"json"},
+		Encrypt:       true,
+		PrivateKeyOut: filepath.Join(target, "keys", "private.pem"),
+	}
+	if _, err := Apply(context.Background(), plan, false, io.Discard); err == nil {
+		t.Fatal("private key on the stick accepted")
+	}
+}
@@ -0,0 +1,221 @@
+// Package redact removes or masks Facts fields named by output.redact
+//
+// A selector is a dotted path of Facts JSON field names; "[]" after a list
+// field applies the rest of the path to every element:
+//
+//	wifi_known_ssids            remove the field (lists become empty)
+//	users[].full_name           remove full_name from every user
+//	mask:local_ips[].ip_address replace every string under the path with Mask
+//
+// Run metadata and the fields that identify the run (hostname,
+// hardware_uuid) cannot be redacted; pseudonymize those instead.
+package redact
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+)
+
+// Mask replaces masked strings (empty strings stay empty)
+const Mask = "[REDACTED]"
+
+// maskPrefix selects masking instead of removal
+const maskPrefix = "mask:"
+
+// protected lists top-level fields selectors may not touch
+var protected = map[string]string{
+	"timestamp":              "run metadata",
+	"collection_duration_ms": "run metadata",
+	"collector_version":      "run metadata",
+	"partial":                "run metadata",
+	"failed_categories":      "run metadata",
+	"run_id":                 "run metadata",
+	"session":                "run metadata",
+	"coverage_notes":         "run metadata",
+	"hostname":               "it identifies the run (use privacy.pseudonymize)",
+	"hardware_uuid":          "it identifies the run (use privacy.pseudonymize)",
+}
+
+// step is one resolved path segment
+type step struct {
+	field int  // Struct field index
+	each  bool // Descend into every element of the (slice) field
+}
+
+// rule is one compiled selector
+type rule struct {
+	steps []step
+	mask  bool
+}
+
+// Redactor applies compiled selectors to Facts
+type Redactor struct {
+	rules []rule
+}
+
+// New compiles selectors against the Facts layout
+// Unknown fields and malformed paths are errors, so a typo in the config
+// fails the run instead of leaking the field it meant to hide.
+// Complexity: O(Σ|selector|)
+func New(selectors []string) (*Redactor, error) {
+	r := &Redactor{}
+	for _, sel := range selectors {
+		rl, err := compile(sel)
+		if err != nil {
+			return nil, fmt.Errorf("output.redact %q: %w", sel, err)
+		}
+		r.rules = append(r.rules, rl)
+	}
+	return r, nil
+}
+
+// compile resolves one selector
+func compile(sel string) (rule, error) {
+	path, mask := strings.CutPrefix(strings.TrimSpace(sel), maskPrefix)
+	if path == "" {
+		return rule{}, fmt.Errorf("empty selector")
+	}
+	segments := strings.Split(path, ".")
+	if reason, ok := protected[strings.TrimSuffix(segments[0], "[]")]; ok {
+		return rule{}, fmt.Errorf("cannot redact %s: %s", segments[0], reason)
+	}
+
+	rl := rule{mask: mask}
+	t := reflect.TypeOf(collection.Facts{})
+	for i, seg := range segments {
+		name, each := strings.CutSuffix(seg, "[]")
+		last := i == len(segments)-1
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return rule{}, fmt.Errorf("%s has no fields", strings.Join(segments[:i], "."))
+		}
+		index, ft, ok := fieldByJSON(t, name)
+		if !ok {
+			return rule{}, fmt.Errorf("unknown field %s", name)
+		}
+		switch {
+		case each && last:
+			return rule{}, fmt.Errorf("%s[] selects elements, name a field after it or drop the []", name)
+		case each && ft.Kind() != reflect.Slice:
+			return rule{}, fmt.Errorf("%s is not a list", name)
+		case each:
+			ft = ft.Elem()
+		case !last && ft.Kind() == reflect.Slice:
+			return rule{}, fmt.Errorf("%s is a list, write %s[]", name, name)
+		}
+		rl.steps = append(rl.steps, step{field: index, each: each})
+		t = ft
+	}
+	return rl, nil
+}
+
+// fieldByJSON finds the field of struct t with JSON name name
+func fieldByJSON(t reflect.Type, name string) (int, reflect.Type, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if f.IsExported() && tag == name {
+			return i, f.Type, true
+		}
+	}
+	return 0, nil, false
+}
+
+// Facts applies every selector to f in order
+// Slices are copied before they are changed, so fields sharing a backing
+// array (the collector's local_ips and mac_addresses) are redacted
+// independently.
+// Complexity: O(|facts|) per selector
+func (r *Redactor) Facts(f *collection.Facts) {
+	for _, rl := range r.rules {
+		apply(reflect.ValueOf(f).Elem(), rl.steps, rl.mask)
+	}
+}
+
+// apply walks steps from struct v and redacts the field the last one names
+func apply(v reflect.Value, steps []step, mask bool) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	field := v.Field(steps[0].field)
+	switch {
+	case len(steps) == 1 && mask:
+		maskValue(field)
+	case len(steps) == 1:
+		remove(field)
+	case steps[0].each:
+		cloneSlice(field)
+		for i := 0; i < field.Len(); i++ {
+			apply(field.Index(i), steps[1:], mask)
+		}
+	default:
+		apply(field, steps[1:], mask)
+	}
+}
+
+// remove clears v; lists become empty rather than null
+func remove(v reflect.Value) {
+	if v.Kind() == reflect.Slice {
+		v.Set(reflect.MakeSlice(v.Type(), 0, 0))
+		return
+	}
+	v.Set(reflect.Zero(v.Type()))
+}
+
+// maskValue replaces every string under v with Mask and clears other values
+func maskValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		if v.Len() > 0 {
+			v.SetString(Mask)
+		}
+	case reflect.Slice:
+		cloneSlice(v)
+		for i := 0; i < v.Len(); i++ {
+			maskValue(v.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				maskValue(v.Field(i))
+			}
+		}
+	case reflect.Map:
+		if v.IsNil() || v.Type().Elem().Kind() != reflect.String {
+			v.Set(reflect.Zero(v.Type()))
+			return
+		}
+		masked := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			masked.SetMapIndex(iter.Key(), reflect.ValueOf(Mask).Convert(v.Type().Elem()))
+		}
+		v.Set(masked)
+	case reflect.Pointer:
+		if !v.IsNil() {
+			copied := reflect.New(v.Type().Elem())
+			copied.Elem().Set(v.Elem())
+			maskValue(copied.Elem())
+			v.Set(copied)
+		}
+	default:
+		v.Set(reflect.Zero(v.Type()))
+	}
+}
+
+// cloneSlice points v at a private copy of its elements
+func cloneSlice(v reflect.Value) {
+	if v.Len() == 0 {
+		return
+	}
+	copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+	reflect.Copy(copied, v)
+	v.Set(copied)
+}
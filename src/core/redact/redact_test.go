@@ -0,0 +1,94 @@
+package redact
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+)
+
+// testFacts returns facts carrying recognizable values
+func testFacts() *collection.Facts {
+	ifaces := []types.NetworkInterface{{Name: "eth0", IPAddress: "10.1.2.3", MACAddress: "aa:bb:cc:dd:ee:ff"}}
+	return &collection.Facts{
+		Hostname:      "ws-01",
+		HardwareUUID:  "uuid-1",
+		Users:         []types.User{{Username: "alice", FullName: "Alice Secretname", UID: "1000"}},
+		LoggedInUsers: []string{"alice"},
+		PrimaryEmail:  "alice@example.com",
+		LocalIPs:      ifaces,
+		MACAddresses:  ifaces, // Shared, as the collector does
+		WiFiSSIDs:     []string{"HomeNetwork-5G"},
+	}
+}
+
+// marshal returns the serialized facts
+func marshal(t *testing.T, f *collection.Facts) string {
+	t.Helper()
+	data, err := json.Marshal(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// TestFacts_OutputOmitsValues verifies removed and masked values never reach the serialized facts
+func TestFacts_OutputOmitsValues(t *testing.T) {
+	r, err := New([]string{"users[].full_name", "wifi_known_ssids", "mask:primary_user_email", "mask:local_ips[].ip_address"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := testFacts()
+	r.Facts(f)
+	out := marshal(t, f)
+
+	for _, secret := range []string{"Secretname", "HomeNetwork-5G", "alice@example.com"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("redacted output contains %q: %s", secret, out)
+		}
+	}
+	if !strings.Contains(out, `"wifi_known_ssids":[]`) {
+		t.Errorf("removed list is not empty: %s", out)
+	}
+	if f.PrimaryEmail != Mask || f.LocalIPs[0].IPAddress != Mask {
+		t.Errorf("masked fields = %q, %q", f.PrimaryEmail, f.LocalIPs[0].IPAddress)
+	}
+	if f.Users[0].Username != "alice" || f.MACAddresses[0].IPAddress != "10.1.2.3" {
+		t.Error("unselected fields changed")
+	}
+}
+
+// TestFacts_MaskWholeList verifies masking a list masks every string inside it
+func TestFacts_MaskWholeList(t *testing.T) {
+	r, err := New([]string{"mask:users"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := testFacts()
+	r.Facts(f)
+	if u := f.Users[0]; u.Username != Mask || u.FullName != Mask || u.UID != Mask {
+		t.Errorf("Users[0] = %+v", u)
+	}
+	if strings.Contains(marshal(t, f), "Secretname") {
+		t.Error("masked list leaks a full name")
+	}
+}
+
+// TestNew_RejectsBadSelectors verifies typos and protected fields fail loudly
+func TestNew_RejectsBadSelectors(t *testing.T) {
+	for _, sel := range []string{
+		"",
+		"users.full_name",  // List without []
+		"users[].fullname", // Unknown field
+		"wifi_known_ssids[]",
+		"primary_user_email[].x",
+		"hostname",
+		"mask:run_id",
+	} {
+		if _, err := New([]string{sel}); err == nil {
+			t.Errorf("New(%q) succeeded", sel)
+		}
+	}
+}
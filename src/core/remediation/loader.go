@@ -0,0 +1,104 @@
+package remediation
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalid marks a remediation file that exists but cannot be used
+var ErrInvalid = errors.New("invalid remediation knowledge base")
+
+// defaultYAML is the knowledge base shipped with the binary
+//
+//go:embed remediation.yaml
+var defaultYAML []byte
+
+// Parse decodes and validates a YAML knowledge base
+// Mathematical guarantee: Returns valid KnowledgeBase or error
+// Complexity: O(n) where n = len(data)
+func Parse(data []byte) (*KnowledgeBase, error) {
+	kb := &KnowledgeBase{}
+	if err := yaml.Unmarshal(data, kb); err != nil {
+		return nil, fmt.Errorf("failed to parse remediation YAML: %w", err)
+	}
+
+	if err := kb.Validate(); err != nil {
+		return nil, err
+	}
+
+	return kb, nil
+}
+
+// Load reads a knowledge base from a YAML file
+// Complexity: O(n) where n = file size
+func Load(path string) (*KnowledgeBase, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read remediation file: %w", err)
+	}
+
+	return Parse(data)
+}
+
+// Default returns the embedded knowledge base
+// Mathematical guarantee: Always returns a valid KnowledgeBase (never nil)
+func Default() *KnowledgeBase {
+	kb, err := Parse(defaultYAML)
+	if err != nil {
+		// The embedded file is validated by tests; this is unreachable
+		return &KnowledgeBase{Remediations: map[string]Entry{}}
+	}
+	return kb
+}
+
+// LoadOrDefault loads the knowledge base at path, falling back to the embedded
+// one only when path is empty or the file does not exist
+// A file that cannot be read, parsed or validated is an ErrInvalid error, so
+// a typo in a customized knowledge base is not silently ignored.
+// Complexity: O(n) where n = file size
+func LoadOrDefault(path string) (*KnowledgeBase, error) {
+	if path == "" {
+		return Default(), nil
+	}
+
+	kb, err := Load(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Default(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrInvalid, path, err)
+	}
+	return kb, nil
+}
+
+// Validate checks that every entry is usable
+// Complexity: O(|entries|)
+func (kb *KnowledgeBase) Validate() error {
+	for _, id := range kb.IDs() {
+		entry := kb.Remediations[id]
+		if entry.Title == "" {
+			return &ValidationError{ID: id, Reason: "title must not be empty"}
+		}
+		if len(entry.Steps) == 0 {
+			return &ValidationError{ID: id, Reason: "must have at least one step"}
+		}
+	}
+	return nil
+}
+
+// IDs returns the sorted finding IDs covered by the knowledge base
+// Complexity: O(n log n)
+func (kb *KnowledgeBase) IDs() []string {
+	ids := make([]string, 0, len(kb.Remediations))
+	for id := range kb.Remediations {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
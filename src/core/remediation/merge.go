@@ -0,0 +1,56 @@
+package remediation
+
+import "strings"
+
+// Lookup returns the curated remediation for a finding ID
+// Complexity: O(1)
+func (kb *KnowledgeBase) Lookup(id string) (Entry, bool) {
+	entry, ok := kb.Remediations[id]
+	return entry, ok
+}
+
+// MergeActions prepends curated remediations for findingIDs to model actions
+// Curated actions come first (in finding order), duplicates are removed,
+// and model-generated actions are kept after them.
+// Mathematical property: Same inputs → Same actions (deterministic)
+// Complexity: O(|findingIDs| + |actions|)
+func (kb *KnowledgeBase) MergeActions(actions []string, findingIDs []string) []string {
+	merged := []string{}
+	seen := make(map[string]bool)
+
+	add := func(action string) {
+		key := strings.ToLower(strings.TrimSpace(action))
+		if key == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		merged = append(merged, action)
+	}
+
+	for _, id := range findingIDs {
+		if entry, ok := kb.Lookup(id); ok {
+			add(FormatEntry(entry))
+		}
+	}
+
+	for _, action := range actions {
+		add(action)
+	}
+
+	return merged
+}
+
+// FormatEntry renders an entry as a single action bullet
+// Format: "Title: step 1; step 2 (See: ref1, ref2)"
+func FormatEntry(entry Entry) string {
+	var b strings.Builder
+	b.WriteString(entry.Title)
+	b.WriteString(": ")
+	b.WriteString(strings.Join(entry.Steps, "; "))
+	if len(entry.References) > 0 {
+		b.WriteString(" (See: ")
+		b.WriteString(strings.Join(entry.References, ", "))
+		b.WriteString(")")
+	}
+	return b.String()
+}
@@ -0,0 +1,100 @@
+package remediation_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/minibeast/usb-agent/src/core/remediation"
+)
+
+// TestDefault verifies the embedded knowledge base is valid
+func TestDefault(t *testing.T) {
+	kb := remediation.Default()
+
+	if len(kb.IDs()) == 0 {
+		t.Fatal("Default() returned empty knowledge base")
+	}
+	if err := kb.Validate(); err != nil {
+		t.Errorf("Embedded knowledge base invalid: %v", err)
+	}
+	if _, ok := kb.Lookup("MB-OS-EOL"); !ok {
+		t.Error("Expected MB-OS-EOL entry in default knowledge base")
+	}
+}
+
+// TestParse_Invalid verifies entries without steps are rejected
+func TestParse_Invalid(t *testing.T) {
+	data := []byte("remediations:\n  MB-X:\n    title: Something\n    steps: []\n")
+
+	if _, err := remediation.Parse(data); err == nil {
+		t.Error("Parse() should fail for entry without steps")
+	}
+}
+
+// TestLoadOrDefault_Fallback verifies graceful degradation
+func TestLoadOrDefault_Fallback(t *testing.T) {
+	kb, err := remediation.LoadOrDefault("/nonexistent/remediation.yaml")
+	if err != nil {
+		t.Fatalf("LoadOrDefault() failed: %v", err)
+	}
+
+	if len(kb.IDs()) != len(remediation.Default().IDs()) {
+		t.Error("LoadOrDefault() did not fall back to embedded knowledge base")
+	}
+}
+
+// TestLoadOrDefault_InvalidFile verifies a broken file is reported, not replaced
+func TestLoadOrDefault_InvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"malformed.yaml": "remediations: [not, a, map",
+		"invalid.yaml":   "remediations:\n  ORG-1:\n    title: Org fix\n",
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if kb, err := remediation.LoadOrDefault(path); !errors.Is(err, remediation.ErrInvalid) || kb != nil {
+			t.Errorf("%s: LoadOrDefault() = (%v, %v), want ErrInvalid", name, kb, err)
+		}
+	}
+}
+
+// TestLoad_Custom verifies custom knowledge bases are loaded from disk
+func TestLoad_Custom(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "remediation.yaml")
+	data := "remediations:\n  ORG-1:\n    title: Org fix\n    steps: [Do the thing]\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	kb, err := remediation.Load(path)
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if _, ok := kb.Lookup("ORG-1"); !ok {
+		t.Error("Custom entry not loaded")
+	}
+}
+
+// TestMergeActions verifies curated actions precede model actions without duplicates
+func TestMergeActions(t *testing.T) {
+	kb := remediation.Default()
+	modelActions := []string{"Apply pending kernel updates"}
+
+	merged := kb.MergeActions(modelActions, []string{"MB-DISK-UNENCRYPTED", "MB-UNKNOWN", "MB-DISK-UNENCRYPTED"})
+
+	if len(merged) != 2 {
+		t.Fatalf("MergeActions() returned %d actions, want 2: %v", len(merged), merged)
+	}
+	if !strings.HasPrefix(merged[0], "Enable full-disk encryption:") {
+		t.Errorf("Curated action not first: %q", merged[0])
+	}
+	if merged[1] != modelActions[0] {
+		t.Errorf("Model action not preserved: %q", merged[1])
+	}
+}
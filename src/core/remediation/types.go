@@ -0,0 +1,25 @@
+package remediation
+
+// Entry is a curated remediation for a single finding ID
+type Entry struct {
+	Title      string   `yaml:"title"`
+	Steps      []string `yaml:"steps"`
+	References []string `yaml:"references"`
+}
+
+// KnowledgeBase maps rule-engine finding IDs to curated remediations
+// Mathematical invariant: Every entry has a title and at least one step
+type KnowledgeBase struct {
+	Version      int              `yaml:"version"`
+	Remediations map[string]Entry `yaml:"remediations"`
+}
+
+// ValidationError represents a knowledge base validation failure
+type ValidationError struct {
+	ID     string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return "remediation validation failed: " + e.ID + " - " + e.Reason
+}
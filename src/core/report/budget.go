@@ -0,0 +1,136 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Fit returns a copy of the Report whose rendered text fits within maxBytes
+// Truncation order (least to most important):
+//  1. Appendix fact tables, starting with the last one: its trailing rows are
+//     dropped, and if the report is still too large without any of them the
+//     whole table goes and the table before it is trimmed next
+//  2. Risks beyond the topRisks highest-severity entries, lowest severity first
+//
+// The Summary is never truncated, so the result may still exceed maxBytes
+// when the summary alone is larger than the budget.
+// Mathematical guarantee: Fit(0, n) returns an unmodified copy
+// Complexity: O(t * log(r) * n) where t = tables, r = rows, n = rendered size
+func (r *Report) Fit(maxBytes, topRisks int) *Report {
+	out := r.clone()
+	if maxBytes <= 0 || len(out.RenderText()) <= maxBytes {
+		return out
+	}
+
+	droppedRows, droppedRisks := 0, 0
+	fits := func() bool {
+		out.Notice = truncationNotice(maxBytes, droppedRows, droppedRisks)
+		return len(out.RenderText()) <= maxBytes
+	}
+
+	// Step 1: Trim appendix tables from the end
+	for i := len(out.Appendix) - 1; i >= 0; i-- {
+		table := &out.Appendix[i]
+		rows := table.Rows
+		baseDropped := droppedRows
+
+		keep := func(k int) bool {
+			table.Rows = rows[:k]
+			table.Omitted = len(rows) - k
+			droppedRows = baseDropped + table.Omitted
+			return fits()
+		}
+
+		// Largest row count that fits (binary search, size is monotonic in k)
+		if keep(0) {
+			lo, hi := 0, len(rows)
+			for lo < hi {
+				mid := (lo + hi + 1) / 2
+				if keep(mid) {
+					lo = mid
+				} else {
+					hi = mid - 1
+				}
+			}
+			keep(lo)
+			return out
+		}
+
+		// Still over budget with zero rows here: drop the whole table (title
+		// and columns) and move on to the table before it
+		out.Appendix = out.Appendix[:i]
+		droppedRows = baseDropped + len(rows)
+	}
+
+	if fits() {
+		return out
+	}
+
+	// Step 2: Drop risks beyond the top-N by severity
+	if len(out.Risks) > topRisks {
+		order := make([]int, len(out.Risks))
+		for i := range order {
+			order[i] = i
+		}
+		// Lowest severity first; later bullets go before earlier ones
+		sort.SliceStable(order, func(a, b int) bool {
+			sa, sb := out.Risks[order[a]].Severity, out.Risks[order[b]].Severity
+			if sa != sb {
+				return sa < sb
+			}
+			return order[a] > order[b]
+		})
+
+		removed := make(map[int]bool)
+		all := out.Risks
+		for _, idx := range order[:len(all)-topRisks] {
+			removed[idx] = true
+			droppedRisks++
+
+			kept := make([]Risk, 0, len(all)-len(removed))
+			for i, risk := range all {
+				if !removed[i] {
+					kept = append(kept, risk)
+				}
+			}
+			out.Risks = kept
+
+			if fits() {
+				return out
+			}
+		}
+	}
+
+	fits() // Refresh notice with final counts
+	return out
+}
+
+// truncationNotice describes what was omitted to honor the size budget
+func truncationNotice(maxBytes, rows, risks int) string {
+	return fmt.Sprintf("report truncated to fit %d-byte limit (%d appendix rows, %d risks omitted)",
+		maxBytes, rows, risks)
+}
+
+// clone returns a deep copy of the Report
+func (r *Report) clone() *Report {
+	out := &Report{
+		Header:  append([]Field{}, r.Header...),
+		Summary: append([]string{}, r.Summary...),
+		Risks:   append([]Risk{}, r.Risks...),
+		Actions: append([]string{}, r.Actions...),
+		Notice:  r.Notice,
+	}
+	for _, t := range r.Appendix {
+		rows := make([][]string, len(t.Rows))
+		for i, row := range t.Rows {
+			rows[i] = append([]string{}, row...)
+		}
+		out.Appendix = append(out.Appendix, Table{
+			Title:   t.Title,
+			Columns: append([]string{}, t.Columns...),
+			Rows:    rows,
+			Omitted: t.Omitted,
+		})
+	}
+	return out
+}
@@ -0,0 +1,175 @@
+package report
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/inference"
+)
+
+// Build assembles a Report from Facts and parsed LLM output
+// Mathematical property: Same inputs → Same Report (deterministic)
+// Complexity: O(|Facts| + |parsed|)
+func Build(facts *collection.Facts, parsed *inference.ParsedOutput) *Report {
+	r := &Report{
+		Header: []Field{
+			{Label: "Collection Date", Value: facts.Timestamp.Format("2006-01-02 15:04:05 UTC")},
+			{Label: "Hostname", Value: facts.Hostname},
+			{Label: "Hardware UUID", Value: facts.HardwareUUID},
+			{Label: "OS", Value: facts.OSName + " " + facts.OSVersion},
+		},
+		Summary: append([]string{}, parsed.Summary...),
+		Risks:   []Risk{},
+		Actions: append([]string{}, parsed.Actions...),
+	}
+	if facts.LastUpdate != "" {
+		r.Header = append(r.Header, Field{Label: "Last Update", Value: facts.LastUpdate})
+	}
+	if facts.PendingUpdates != nil {
+		r.Header = append(r.Header, Field{Label: "Pending Updates", Value: strconv.Itoa(*facts.PendingUpdates)})
+	}
+	r.Header = append(r.Header, sessionHeader(facts)...)
+	if len(facts.CoverageNotes) > 0 {
+		r.Header = append(r.Header, Field{Label: "Coverage", Value: strings.Join(facts.CoverageNotes, "; ")})
+	}
+
+	for _, text := range parsed.Risks {
+		risk := Risk{Text: text, Severity: ClassifySeverity(text), Confidence: inference.ConfidenceInferred}
+		if finding, ok := parsed.FindingFor(text); ok {
+			risk.FindingID = finding.ID
+			if finding.Confidence != "" {
+				risk.Confidence = finding.Confidence
+			}
+		}
+		r.Risks = append(r.Risks, risk)
+	}
+
+	r.Appendix = buildAppendix(facts)
+
+	return r
+}
+
+// sessionHeader returns the run ID and engagement fields (omitted when unset)
+// Tags are rendered in key order.
+func sessionHeader(facts *collection.Facts) []Field {
+	var fields []Field
+	if facts.RunID != "" {
+		fields = append(fields, Field{Label: "Run ID", Value: facts.RunID})
+	}
+	s := facts.Session
+	if s == nil {
+		return fields
+	}
+	if s.Engagement != "" {
+		fields = append(fields, Field{Label: "Engagement", Value: s.Engagement})
+	}
+	if s.Operator != "" {
+		fields = append(fields, Field{Label: "Operator", Value: s.Operator})
+	}
+	if len(s.Tags) > 0 {
+		keys := make([]string, 0, len(s.Tags))
+		for k := range s.Tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		tags := make([]string, len(keys))
+		for i, k := range keys {
+			tags[i] = k + "=" + s.Tags[k]
+		}
+		fields = append(fields, Field{Label: "Tags", Value: strings.Join(tags, ", ")})
+	}
+	return fields
+}
+
+// buildAppendix renders the tabular Facts sections
+// Tables are ordered most to least important: Fit trims the last table first,
+// so the WiFi SSIDs go before the network interfaces.
+func buildAppendix(facts *collection.Facts) []Table {
+	users := Table{Title: "Local Users", Columns: []string{"Username", "Full Name", "UID"}}
+	for _, u := range facts.Users {
+		users.Rows = append(users.Rows, []string{u.Username, u.FullName, u.UID})
+	}
+
+	interfaces := Table{Title: "Network Interfaces", Columns: []string{"Name", "IP Address", "MAC Address"}}
+	for _, iface := range facts.LocalIPs {
+		interfaces.Rows = append(interfaces.Rows, []string{iface.Name, iface.IPAddress, iface.MACAddress})
+	}
+
+	wifi := Table{Title: "Known WiFi SSIDs", Columns: []string{"SSID"}}
+	for _, ssid := range facts.WiFiSSIDs {
+		wifi.Rows = append(wifi.Rows, []string{ssid})
+	}
+
+	volumes := Table{Title: "Volumes", Columns: []string{"Mount Point", "Filesystem", "Size", "Free", "Encryption"}}
+	for _, v := range facts.Volumes {
+		volumes.Rows = append(volumes.Rows, []string{v.MountPoint, v.FileSystem, formatGiB(v.TotalBytes), formatGiB(v.FreeBytes), v.Encryption})
+	}
+
+	ports := Table{Title: "Listening Ports", Columns: []string{"Protocol", "Address", "Port", "Process"}}
+	for _, p := range facts.ListeningPorts {
+		process := p.Process
+		if p.PID != 0 {
+			process += " (" + strconv.Itoa(p.PID) + ")"
+		}
+		ports.Rows = append(ports.Rows, []string{p.Protocol, p.Address, strconv.Itoa(p.Port), strings.TrimSpace(process)})
+	}
+
+	security := Table{Title: "Security Products", Columns: []string{"Name", "Kind", "Version", "Real-Time Protection"}}
+	for _, p := range facts.SecurityProducts {
+		security.Rows = append(security.Rows, []string{p.Name, p.Kind, p.Version, p.RealTime})
+	}
+
+	updates := Table{Title: "Installed Updates", Columns: []string{"ID", "Title", "Installed On"}}
+	for _, u := range facts.InstalledUpdates {
+		updates.Rows = append(updates.Rows, []string{u.ID, u.Title, u.InstalledOn})
+	}
+
+	return []Table{interfaces, security, updates, ports, volumes, users, wifi}
+}
+
+// formatGiB renders a byte count in GiB with one decimal
+func formatGiB(n int64) string {
+	return strconv.FormatFloat(float64(n)/(1<<30), 'f', 1, 64) + " GiB"
+}
+
+// severityKeywords maps lower-case keywords to severities (checked highest first)
+var severityKeywords = []struct {
+	severity Severity
+	words    []string
+}{
+	{SeverityCritical, []string{"critical", "compromise", "exploit", "malware"}},
+	{SeverityHigh, []string{"high", "admin", "unencrypted", "end-of-life", "eol", "unsupported"}},
+	{SeverityMedium, []string{"medium", "outdated", "missing", "update", "unknown"}},
+	{SeverityLow, []string{"low", "minor"}},
+}
+
+// ClassifySeverity assigns a severity to free-text risk bullets
+// Heuristic: explicit "[LEVEL]" prefixes win, otherwise keyword matching
+// Complexity: O(n) where n = len(text)
+func ClassifySeverity(text string) Severity {
+	lower := strings.ToLower(text)
+
+	// Negative statements ("No critical risks detected") carry no severity
+	if strings.HasPrefix(lower, "no ") {
+		return SeverityInfo
+	}
+
+	// Explicit tags emitted by rule-based analysis
+	for _, s := range []Severity{SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow, SeverityInfo} {
+		if strings.HasPrefix(lower, "["+strings.ToLower(s.String())+"]") {
+			return s
+		}
+	}
+
+	for _, entry := range severityKeywords {
+		for _, word := range entry.words {
+			if strings.Contains(lower, word) {
+				return entry.severity
+			}
+		}
+	}
+
+	return SeverityInfo
+}
@@ -0,0 +1,76 @@
+package report
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/inference"
+)
+
+//go:embed report.html.tmpl
+var htmlSource string
+
+// htmlTemplate renders a self-contained page: styling is inline and nothing
+// is loaded from the network, so the file opens offline or as an attachment
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"severityClass": func(s Severity) string { return strings.ToLower(s.String()) },
+}).Parse(htmlSource))
+
+// htmlPage is the data passed to htmlTemplate
+type htmlPage struct {
+	Hostname string
+	Report   *Report
+	Tables   []Table // Hardware first, then the appendix
+}
+
+// RenderHTML converts the Report to a standalone HTML page
+// The LLM summary comes first, then risks, actions, a hardware table built
+// from facts (omitted when facts is nil) and the appendix tables. Every value
+// is escaped by html/template.
+// Mathematical property: Same Report and Facts → Same bytes
+// Complexity: O(n) where n = total length of all sections
+func (r *Report) RenderHTML(facts *collection.Facts) ([]byte, error) {
+	page := htmlPage{Report: r}
+	if facts != nil {
+		page.Hostname = facts.Hostname
+		page.Tables = append(page.Tables, hardwareTable(facts))
+	}
+	page.Tables = append(page.Tables, r.Appendix...)
+
+	var b bytes.Buffer
+	if err := htmlTemplate.Execute(&b, page); err != nil {
+		return nil, fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	return b.Bytes(), nil
+}
+
+// BuildHTML builds the Report from Facts and parsed LLM output and renders it
+// as HTML
+// Complexity: O(|Facts| + |parsed|)
+func BuildHTML(facts *collection.Facts, parsed *inference.ParsedOutput) ([]byte, error) {
+	return Build(facts, parsed).RenderHTML(facts)
+}
+
+// hardwareTable lists the machine identity fields that are set
+func hardwareTable(facts *collection.Facts) Table {
+	t := Table{Title: "Hardware", Columns: []string{"Property", "Value"}}
+	for _, f := range []Field{
+		{Label: "Hostname", Value: facts.Hostname},
+		{Label: "Computer Name", Value: facts.ComputerName},
+		{Label: "Machine Owner", Value: facts.MachineOwner},
+		{Label: "Serial Number", Value: facts.SerialNumber},
+		{Label: "Hardware UUID", Value: facts.HardwareUUID},
+		{Label: "OS", Value: strings.TrimSpace(facts.OSName + " " + facts.OSVersion)},
+		{Label: "OS Build", Value: facts.OSBuild},
+		{Label: "Timezone", Value: facts.Timezone},
+	} {
+		if f.Value != "" {
+			t.Rows = append(t.Rows, []string{f.Label, f.Value})
+		}
+	}
+	return t
+}
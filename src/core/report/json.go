@@ -0,0 +1,17 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// RenderJSON converts the Report to report.json form
+// Mathematical property: Same Report → Same bytes (struct field order is fixed)
+// Complexity: O(n) where n = total length of all sections
+func (r *Report) RenderJSON() ([]byte, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal report: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,272 @@
+package report_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/inference"
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+	"github.com/minibeast/usb-agent/src/core/report"
+)
+
+// testFacts returns Facts with enough rows to exercise truncation
+func testFacts(users int) *collection.Facts {
+	facts := &collection.Facts{
+		Timestamp:    time.Date(2025, 11, 9, 12, 0, 0, 0, time.UTC),
+		Hostname:     "test-host",
+		HardwareUUID: "uuid-123",
+		OSName:       "Linux",
+		OSVersion:    "22.04",
+		LocalIPs: []types.NetworkInterface{
+			{Name: "eth0", IPAddress: "10.0.0.5", MACAddress: "aa:bb:cc:dd:ee:ff"},
+		},
+		WiFiSSIDs: []string{"corp", "guest"},
+	}
+	for i := 0; i < users; i++ {
+		facts.Users = append(facts.Users, types.User{
+			Username: fmt.Sprintf("user%03d", i),
+			FullName: fmt.Sprintf("Test User %d", i),
+			UID:      fmt.Sprintf("%d", 1000+i),
+		})
+	}
+	return facts
+}
+
+func testParsed() *inference.ParsedOutput {
+	return &inference.ParsedOutput{
+		Summary: []string{"Linux host test-host with 1 interface", "Many local users present", "Overall status acceptable"},
+		Risks: []string{
+			"Minor timezone mismatch detected",
+			"Critical: outdated kernel with known exploit",
+			"Multiple admin accounts present",
+		},
+		Actions: []string{"Apply pending kernel updates"},
+	}
+}
+
+// TestRenderText verifies all sections are rendered
+func TestRenderText(t *testing.T) {
+	text := report.Build(testFacts(2), testParsed()).RenderText()
+
+	for _, want := range []string{"SUMMARY:", "RISKS:", "RECOMMENDED ACTIONS:", "APPENDIX:", "Local Users:", "user001", "END OF REPORT"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("RenderText() missing %q", want)
+		}
+	}
+	if strings.Contains(text, "NOTICE:") {
+		t.Error("Untruncated report should not carry a notice")
+	}
+}
+
+// TestBuild_SessionHeader verifies run and engagement metadata head the report
+func TestBuild_SessionHeader(t *testing.T) {
+	facts := testFacts(0)
+	facts.RunID = "01JPAX1Z5C8K2M3N4P5Q6R7S8T"
+	facts.Session = &collection.Session{Engagement: "ACME-2025-014", Operator: "JD", Tags: map[string]string{"site": "berlin", "phase": "triage"}}
+	text := report.Build(facts, testParsed()).RenderText()
+
+	for _, want := range []string{
+		"Run ID: 01JPAX1Z5C8K2M3N4P5Q6R7S8T\n",
+		"Engagement: ACME-2025-014\n",
+		"Operator: JD\n",
+		"Tags: phase=triage, site=berlin\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("RenderText() missing %q", want)
+		}
+	}
+
+	if header := report.Build(testFacts(0), testParsed()).Header; len(header) != 4 {
+		t.Errorf("Header without a session has %d fields, want 4", len(header))
+	}
+}
+
+// TestBuild_CoverageHeader verifies privilege shortfalls are called out in the header
+func TestBuild_CoverageHeader(t *testing.T) {
+	facts := testFacts(0)
+	facts.CoverageNotes = []string{"hardware_info: serial number not collected"}
+	text := report.Build(facts, testParsed()).RenderText()
+
+	if want := "Coverage: hardware_info: serial number not collected\n"; !strings.Contains(text, want) {
+		t.Errorf("RenderText() missing %q", want)
+	}
+}
+
+// TestFit_Unlimited verifies a zero budget leaves the report untouched
+func TestFit_Unlimited(t *testing.T) {
+	rpt := report.Build(testFacts(50), testParsed())
+
+	if got, want := rpt.Fit(0, 1).RenderText(), rpt.RenderText(); got != want {
+		t.Error("Fit(0) modified the report")
+	}
+}
+
+// TestFit_TruncatesAppendixFirst verifies appendix rows go before risks
+func TestFit_TruncatesAppendixFirst(t *testing.T) {
+	rpt := report.Build(testFacts(200), testParsed())
+	full := rpt.RenderText()
+	budget := len(full) / 2
+
+	text := rpt.Fit(budget, 1).RenderText()
+
+	if len(text) > budget {
+		t.Errorf("Fitted report is %d bytes, budget %d", len(text), budget)
+	}
+	if !strings.Contains(text, "NOTICE: report truncated") {
+		t.Error("Truncated report missing notice")
+	}
+	if !strings.Contains(text, "rows omitted") {
+		t.Error("Expected omitted-rows marker in appendix")
+	}
+	for _, risk := range testParsed().Risks {
+		if !strings.Contains(text, risk) {
+			t.Errorf("Risk %q dropped while appendix could still shrink", risk)
+		}
+	}
+}
+
+// TestFit_KeepsTopRisksBySeverity verifies lowest-severity risks are dropped
+func TestFit_KeepsTopRisksBySeverity(t *testing.T) {
+	parsed := testParsed()
+	for i := 0; i < 20; i++ {
+		parsed.Risks = append(parsed.Risks, fmt.Sprintf("Low priority observation number %d about configuration", i))
+	}
+	rpt := report.Build(testFacts(0), parsed)
+
+	// Budget that fits the summary but not all risks
+	budget := len(report.Build(testFacts(0), testParsed()).RenderText()) + 200
+	fitted := rpt.Fit(budget, 1)
+	text := fitted.RenderText()
+
+	if !strings.Contains(text, "Critical: outdated kernel") {
+		t.Error("Highest-severity risk was dropped")
+	}
+	for _, line := range parsed.Summary {
+		if !strings.Contains(text, line) {
+			t.Errorf("Summary line %q was truncated", line)
+		}
+	}
+	if len(fitted.Risks) >= len(parsed.Risks) {
+		t.Error("Expected risks to be dropped")
+	}
+}
+
+// TestFit_NeverTruncatesSummary verifies the summary survives tiny budgets
+func TestFit_NeverTruncatesSummary(t *testing.T) {
+	text := report.Build(testFacts(10), testParsed()).Fit(10, 0).RenderText()
+
+	for _, line := range testParsed().Summary {
+		if !strings.Contains(text, line) {
+			t.Errorf("Summary line %q was truncated", line)
+		}
+	}
+	if !strings.Contains(text, "NOTICE: report truncated") {
+		t.Error("Truncated report missing notice")
+	}
+}
+
+// TestClassifySeverity verifies keyword and tag classification
+func TestClassifySeverity(t *testing.T) {
+	tests := []struct {
+		text   string
+		expect report.Severity
+	}{
+		{"[HIGH] Disk encryption disabled", report.SeverityHigh},
+		{"Critical vulnerability present", report.SeverityCritical},
+		{"No critical risks detected at this time", report.SeverityInfo},
+		{"Minor configuration drift", report.SeverityLow},
+		{"Routine observation", report.SeverityInfo},
+	}
+
+	for _, tt := range tests {
+		if got := report.ClassifySeverity(tt.text); got != tt.expect {
+			t.Errorf("ClassifySeverity(%q) = %v, want %v", tt.text, got, tt.expect)
+		}
+	}
+}
+
+// TestRenderJSON verifies report.json carries severity and confidence per risk
+func TestRenderJSON(t *testing.T) {
+	parsed := testParsed()
+	parsed.Findings = []inference.Finding{
+		{ID: "MB-ADMIN-EXCESS", Description: parsed.Risks[2], Confidence: inference.ConfidenceGrounded},
+	}
+
+	data, err := report.Build(testFacts(1), parsed).RenderJSON()
+	if err != nil {
+		t.Fatalf("RenderJSON() failed: %v", err)
+	}
+
+	var decoded report.Report
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("report.json does not round-trip: %v", err)
+	}
+
+	if got := decoded.Risks[2]; got.Confidence != inference.ConfidenceGrounded || got.FindingID != "MB-ADMIN-EXCESS" {
+		t.Errorf("Rule finding annotation lost: %+v", got)
+	}
+	if got := decoded.Risks[0]; got.Confidence != inference.ConfidenceInferred {
+		t.Errorf("Model risk confidence = %q, want inferred", got.Confidence)
+	}
+	if decoded.Risks[1].Severity != report.SeverityCritical {
+		t.Errorf("Severity = %v, want CRITICAL", decoded.Risks[1].Severity)
+	}
+	if !strings.Contains(string(data), `"severity": "CRITICAL"`) {
+		t.Error("Severity not encoded as label")
+	}
+}
+
+// TestRenderHTML verifies section order, escaping, the hardware table and
+// that the page loads nothing external
+func TestRenderHTML(t *testing.T) {
+	facts := testFacts(2)
+	facts.SerialNumber = "SN-<42>"
+	parsed := testParsed()
+	parsed.Risks = append(parsed.Risks, `Unknown <script>alert("x")</script> service`)
+
+	data, err := report.BuildHTML(facts, parsed)
+	if err != nil {
+		t.Fatalf("BuildHTML() failed: %v", err)
+	}
+	page := string(data)
+
+	last := -1
+	for _, want := range []string{"<h2>Summary</h2>", "Many local users present", "<h2>Risks</h2>", "<h2>Recommended Actions</h2>",
+		"<h3>Hardware</h3>", "<h3>Network Interfaces</h3>", "<h3>Local Users</h3>", "user001"} {
+		i := strings.Index(page, want)
+		if i < 0 {
+			t.Fatalf("RenderHTML() missing %q", want)
+		}
+		if i < last {
+			t.Errorf("%q out of order", want)
+		}
+		last = i
+	}
+	for _, want := range []string{`sev-critical">CRITICAL</span>Critical: outdated kernel`, "SN-&lt;42&gt;", "&lt;script&gt;", "<style>"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("RenderHTML() missing %q", want)
+		}
+	}
+	for _, unwanted := range []string{"<script>", "src=", "href=", "<h2>Notice</h2>"} {
+		if strings.Contains(page, unwanted) {
+			t.Errorf("RenderHTML() contains %q", unwanted)
+		}
+	}
+
+	again, _ := report.BuildHTML(facts, parsed)
+	if string(again) != page {
+		t.Error("RenderHTML() is not deterministic")
+	}
+
+	fitted, err := report.Build(testFacts(200), testParsed()).Fit(4096, 1).RenderHTML(nil)
+	if err != nil {
+		t.Fatalf("RenderHTML(nil) failed: %v", err)
+	}
+	if !strings.Contains(string(fitted), "more rows omitted") || !strings.Contains(string(fitted), "<h2>Notice</h2>") || strings.Contains(string(fitted), "<h3>Hardware</h3>") {
+		t.Error("Truncated report missing omitted rows or notice, or has a hardware table without facts")
+	}
+}
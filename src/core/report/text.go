@@ -0,0 +1,128 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RenderText converts the Report to human-readable report text
+// Complexity: O(n) where n = total length of all sections
+func (r *Report) RenderText() string {
+	var b strings.Builder
+
+	b.WriteString("===== MINIBEAST SYSTEM REPORT =====\n\n")
+
+	// Metadata header
+	if len(r.Header) > 0 {
+		for _, f := range r.Header {
+			fmt.Fprintf(&b, "%s: %s\n", f.Label, f.Value)
+		}
+		b.WriteString("\n")
+	}
+
+	// Summary section
+	b.WriteString("SUMMARY:\n")
+	writeBullets(&b, r.Summary)
+	b.WriteString("\n")
+
+	// Risks section
+	if len(r.Risks) > 0 {
+		b.WriteString("RISKS:\n")
+		for _, risk := range r.Risks {
+			b.WriteString("• ")
+			b.WriteString(risk.Text)
+			if risk.Confidence != "" {
+				b.WriteString(" [")
+				b.WriteString(string(risk.Confidence))
+				b.WriteString("]")
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
+	// Actions section
+	if len(r.Actions) > 0 {
+		b.WriteString("RECOMMENDED ACTIONS:\n")
+		writeBullets(&b, r.Actions)
+		b.WriteString("\n")
+	}
+
+	// Appendix fact tables
+	if len(r.Appendix) > 0 {
+		b.WriteString("APPENDIX:\n\n")
+		for _, t := range r.Appendix {
+			writeTable(&b, t)
+			b.WriteString("\n")
+		}
+	}
+
+	// Truncation notice
+	if r.Notice != "" {
+		b.WriteString("NOTICE: ")
+		b.WriteString(r.Notice)
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString("===== END OF REPORT =====\n")
+
+	return b.String()
+}
+
+// writeBullets writes one "• " prefixed line per item
+func writeBullets(b *strings.Builder, items []string) {
+	for _, item := range items {
+		b.WriteString("• ")
+		b.WriteString(item)
+		b.WriteString("\n")
+	}
+}
+
+// writeTable writes a column-aligned table with its title
+// Complexity: O(rows * columns)
+func writeTable(b *strings.Builder, t Table) {
+	b.WriteString(t.Title)
+	b.WriteString(":\n")
+
+	// Compute column widths
+	widths := make([]int, len(t.Columns))
+	for i, col := range t.Columns {
+		widths[i] = len(col)
+	}
+	for _, row := range t.Rows {
+		for i := 0; i < len(row) && i < len(widths); i++ {
+			if len(row[i]) > widths[i] {
+				widths[i] = len(row[i])
+			}
+		}
+	}
+
+	writeRow(b, t.Columns, widths)
+	for _, row := range t.Rows {
+		writeRow(b, row, widths)
+	}
+
+	if len(t.Rows) == 0 && t.Omitted == 0 {
+		b.WriteString("  (none)\n")
+	}
+	if t.Omitted > 0 {
+		fmt.Fprintf(b, "  (%d more rows omitted)\n", t.Omitted)
+	}
+}
+
+// writeRow writes a single padded table row
+func writeRow(b *strings.Builder, cells []string, widths []int) {
+	b.WriteString(" ")
+	for i, w := range widths {
+		cell := ""
+		if i < len(cells) {
+			cell = cells[i]
+		}
+		if i == len(widths)-1 {
+			fmt.Fprintf(b, " %s", cell)
+		} else {
+			fmt.Fprintf(b, " %-*s |", w, cell)
+		}
+	}
+	b.WriteString("\n")
+}
@@ -0,0 +1,84 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/minibeast/usb-agent/src/core/inference"
+)
+
+// Report is the renderable form of a summarization run
+// Mathematical invariant: Summary is never modified by truncation
+type Report struct {
+	Header   []Field  `json:"header"`           // Metadata lines (collection date, hostname, ...)
+	Summary  []string `json:"summary"`          // Summary lines (never truncated)
+	Risks    []Risk   `json:"risks"`            // Risk bullets in model order
+	Actions  []string `json:"actions"`          // Recommended actions
+	Appendix []Table  `json:"appendix"`         // Fact tables (first to go under a size budget)
+	Notice   string   `json:"notice,omitempty"` // Truncation notice (empty when complete)
+}
+
+// Field is a labelled header value
+type Field struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// Risk is a single risk bullet with its assessed severity and confidence
+type Risk struct {
+	Text       string               `json:"text"`
+	Severity   Severity             `json:"severity"`
+	Confidence inference.Confidence `json:"confidence"`           // Grounded in Facts or model-inferred
+	FindingID  string               `json:"finding_id,omitempty"` // Rule ID when backed by the rule engine
+}
+
+// Table is a titled fact table rendered in the appendix
+type Table struct {
+	Title   string     `json:"title"`
+	Columns []string   `json:"columns"`
+	Rows    [][]string `json:"rows"`
+	Omitted int        `json:"omitted,omitempty"` // Rows dropped by truncation
+}
+
+// Severity orders risks for truncation (higher is more important)
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+// String returns the upper-case severity label
+func (s Severity) String() string {
+	switch s {
+	case SeverityCritical:
+		return "CRITICAL"
+	case SeverityHigh:
+		return "HIGH"
+	case SeverityMedium:
+		return "MEDIUM"
+	case SeverityLow:
+		return "LOW"
+	default:
+		return "INFO"
+	}
+}
+
+// MarshalText encodes the severity as its label (used by report.json)
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a severity label
+func (s *Severity) UnmarshalText(text []byte) error {
+	for _, candidate := range []Severity{SeverityInfo, SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical} {
+		if strings.EqualFold(string(text), candidate.String()) {
+			*s = candidate
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown severity: %q", text)
+}
@@ -0,0 +1,800 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: minibeast/v1/agent.proto
+
+// MiniBeast agent service: drives collection, summarization and
+// signature verification from orchestration tooling.
+
+package agentpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Progress reports a pipeline stage transition
+type Progress struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Stage     string `protobuf:"bytes,1,opt,name=stage,proto3" json:"stage,omitempty"`                           // e.g. "collect", "summarize"
+	Message   string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`                       // Human-readable detail
+	ElapsedMs int64  `protobuf:"varint,3,opt,name=elapsed_ms,json=elapsedMs,proto3" json:"elapsed_ms,omitempty"` // Since the RPC started
+}
+
+func (x *Progress) Reset() {
+	*x = Progress{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_minibeast_v1_agent_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Progress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Progress) ProtoMessage() {}
+
+func (x *Progress) ProtoReflect() protoreflect.Message {
+	mi := &file_minibeast_v1_agent_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Progress.ProtoReflect.Descriptor instead.
+func (*Progress) Descriptor() ([]byte, []int) {
+	return file_minibeast_v1_agent_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Progress) GetStage() string {
+	if x != nil {
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *Progress) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Progress) GetElapsedMs() int64 {
+	if x != nil {
+		return x.ElapsedMs
+	}
+	return 0
+}
+
+type CollectRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *CollectRequest) Reset() {
+	*x = CollectRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_minibeast_v1_agent_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CollectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectRequest) ProtoMessage() {}
+
+func (x *CollectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_minibeast_v1_agent_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectRequest.ProtoReflect.Descriptor instead.
+func (*CollectRequest) Descriptor() ([]byte, []int) {
+	return file_minibeast_v1_agent_proto_rawDescGZIP(), []int{1}
+}
+
+type CollectResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FactsJson []byte `protobuf:"bytes,1,opt,name=facts_json,json=factsJson,proto3" json:"facts_json,omitempty"` // facts.json (indented, as written to disk)
+}
+
+func (x *CollectResult) Reset() {
+	*x = CollectResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_minibeast_v1_agent_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CollectResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectResult) ProtoMessage() {}
+
+func (x *CollectResult) ProtoReflect() protoreflect.Message {
+	mi := &file_minibeast_v1_agent_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectResult.ProtoReflect.Descriptor instead.
+func (*CollectResult) Descriptor() ([]byte, []int) {
+	return file_minibeast_v1_agent_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CollectResult) GetFactsJson() []byte {
+	if x != nil {
+		return x.FactsJson
+	}
+	return nil
+}
+
+type CollectEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Event:
+	//	*CollectEvent_Progress
+	//	*CollectEvent_Result
+	Event isCollectEvent_Event `protobuf_oneof:"event"`
+}
+
+func (x *CollectEvent) Reset() {
+	*x = CollectEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_minibeast_v1_agent_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CollectEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectEvent) ProtoMessage() {}
+
+func (x *CollectEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_minibeast_v1_agent_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectEvent.ProtoReflect.Descriptor instead.
+func (*CollectEvent) Descriptor() ([]byte, []int) {
+	return file_minibeast_v1_agent_proto_rawDescGZIP(), []int{3}
+}
+
+func (m *CollectEvent) GetEvent() isCollectEvent_Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (x *CollectEvent) GetProgress() *Progress {
+	if x, ok := x.GetEvent().(*CollectEvent_Progress); ok {
+		return x.Progress
+	}
+	return nil
+}
+
+func (x *CollectEvent) GetResult() *CollectResult {
+	if x, ok := x.GetEvent().(*CollectEvent_Result); ok {
+		return x.Result
+	}
+	return nil
+}
+
+type isCollectEvent_Event interface {
+	isCollectEvent_Event()
+}
+
+type CollectEvent_Progress struct {
+	Progress *Progress `protobuf:"bytes,1,opt,name=progress,proto3,oneof"`
+}
+
+type CollectEvent_Result struct {
+	Result *CollectResult `protobuf:"bytes,2,opt,name=result,proto3,oneof"`
+}
+
+func (*CollectEvent_Progress) isCollectEvent_Event() {}
+
+func (*CollectEvent_Result) isCollectEvent_Event() {}
+
+type SummarizeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FactsJson []byte `protobuf:"bytes,1,opt,name=facts_json,json=factsJson,proto3" json:"facts_json,omitempty"` // facts.json from Collect or disk
+}
+
+func (x *SummarizeRequest) Reset() {
+	*x = SummarizeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_minibeast_v1_agent_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SummarizeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SummarizeRequest) ProtoMessage() {}
+
+func (x *SummarizeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_minibeast_v1_agent_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SummarizeRequest.ProtoReflect.Descriptor instead.
+func (*SummarizeRequest) Descriptor() ([]byte, []int) {
+	return file_minibeast_v1_agent_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SummarizeRequest) GetFactsJson() []byte {
+	if x != nil {
+		return x.FactsJson
+	}
+	return nil
+}
+
+type SummarizeResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ReportJson []byte `protobuf:"bytes,1,opt,name=report_json,json=reportJson,proto3" json:"report_json,omitempty"` // report.json
+	ReportText string `protobuf:"bytes,2,opt,name=report_text,json=reportText,proto3" json:"report_text,omitempty"` // Plain-text report
+}
+
+func (x *SummarizeResult) Reset() {
+	*x = SummarizeResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_minibeast_v1_agent_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SummarizeResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SummarizeResult) ProtoMessage() {}
+
+func (x *SummarizeResult) ProtoReflect() protoreflect.Message {
+	mi := &file_minibeast_v1_agent_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SummarizeResult.ProtoReflect.Descriptor instead.
+func (*SummarizeResult) Descriptor() ([]byte, []int) {
+	return file_minibeast_v1_agent_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SummarizeResult) GetReportJson() []byte {
+	if x != nil {
+		return x.ReportJson
+	}
+	return nil
+}
+
+func (x *SummarizeResult) GetReportText() string {
+	if x != nil {
+		return x.ReportText
+	}
+	return ""
+}
+
+type SummarizeEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Event:
+	//	*SummarizeEvent_Progress
+	//	*SummarizeEvent_Result
+	Event isSummarizeEvent_Event `protobuf_oneof:"event"`
+}
+
+func (x *SummarizeEvent) Reset() {
+	*x = SummarizeEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_minibeast_v1_agent_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SummarizeEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SummarizeEvent) ProtoMessage() {}
+
+func (x *SummarizeEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_minibeast_v1_agent_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SummarizeEvent.ProtoReflect.Descriptor instead.
+func (*SummarizeEvent) Descriptor() ([]byte, []int) {
+	return file_minibeast_v1_agent_proto_rawDescGZIP(), []int{6}
+}
+
+func (m *SummarizeEvent) GetEvent() isSummarizeEvent_Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (x *SummarizeEvent) GetProgress() *Progress {
+	if x, ok := x.GetEvent().(*SummarizeEvent_Progress); ok {
+		return x.Progress
+	}
+	return nil
+}
+
+func (x *SummarizeEvent) GetResult() *SummarizeResult {
+	if x, ok := x.GetEvent().(*SummarizeEvent_Result); ok {
+		return x.Result
+	}
+	return nil
+}
+
+type isSummarizeEvent_Event interface {
+	isSummarizeEvent_Event()
+}
+
+type SummarizeEvent_Progress struct {
+	Progress *Progress `protobuf:"bytes,1,opt,name=progress,proto3,oneof"`
+}
+
+type SummarizeEvent_Result struct {
+	Result *SummarizeResult `protobuf:"bytes,2,opt,name=result,proto3,oneof"`
+}
+
+func (*SummarizeEvent_Progress) isSummarizeEvent_Event() {}
+
+func (*SummarizeEvent_Result) isSummarizeEvent_Event() {}
+
+type VerifyRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data      []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Signature []byte `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`                  // 64-byte Ed25519 signature over SHA-256(data)
+	PublicKey []byte `protobuf:"bytes,3,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"` // 32-byte Ed25519 public key
+}
+
+func (x *VerifyRequest) Reset() {
+	*x = VerifyRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_minibeast_v1_agent_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyRequest) ProtoMessage() {}
+
+func (x *VerifyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_minibeast_v1_agent_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyRequest.ProtoReflect.Descriptor instead.
+func (*VerifyRequest) Descriptor() ([]byte, []int) {
+	return file_minibeast_v1_agent_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *VerifyRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *VerifyRequest) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *VerifyRequest) GetPublicKey() []byte {
+	if x != nil {
+		return x.PublicKey
+	}
+	return nil
+}
+
+type VerifyResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Valid bool `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+}
+
+func (x *VerifyResponse) Reset() {
+	*x = VerifyResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_minibeast_v1_agent_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyResponse) ProtoMessage() {}
+
+func (x *VerifyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_minibeast_v1_agent_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyResponse.ProtoReflect.Descriptor instead.
+func (*VerifyResponse) Descriptor() ([]byte, []int) {
+	return file_minibeast_v1_agent_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *VerifyResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+var File_minibeast_v1_agent_proto protoreflect.FileDescriptor
+
+var file_minibeast_v1_agent_proto_rawDesc = []byte{
+	0x0a, 0x18, 0x6d, 0x69, 0x6e, 0x69, 0x62, 0x65, 0x61, 0x73, 0x74, 0x2f, 0x76, 0x31, 0x2f, 0x61,
+	0x67, 0x65, 0x6e, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0c, 0x6d, 0x69, 0x6e, 0x69,
+	0x62, 0x65, 0x61, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x22, 0x59, 0x0a, 0x08, 0x50, 0x72, 0x6f, 0x67,
+	0x72, 0x65, 0x73, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x67, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x6c, 0x61, 0x70, 0x73, 0x65, 0x64, 0x5f,
+	0x6d, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x65, 0x6c, 0x61, 0x70, 0x73, 0x65,
+	0x64, 0x4d, 0x73, 0x22, 0x10, 0x0a, 0x0e, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x2e, 0x0a, 0x0d, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x61, 0x63, 0x74, 0x73, 0x5f,
+	0x6a, 0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x66, 0x61, 0x63, 0x74,
+	0x73, 0x4a, 0x73, 0x6f, 0x6e, 0x22, 0x84, 0x01, 0x0a, 0x0c, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63,
+	0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x34, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65,
+	0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6d, 0x69, 0x6e, 0x69, 0x62,
+	0x65, 0x61, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73,
+	0x48, 0x00, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x35, 0x0a, 0x06,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6d,
+	0x69, 0x6e, 0x69, 0x62, 0x65, 0x61, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6c, 0x6c,
+	0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x48, 0x00, 0x52, 0x06, 0x72, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x42, 0x07, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x31, 0x0a, 0x10,
+	0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x61, 0x63, 0x74, 0x73, 0x5f, 0x6a, 0x73, 0x6f, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x66, 0x61, 0x63, 0x74, 0x73, 0x4a, 0x73, 0x6f, 0x6e, 0x22,
+	0x53, 0x0a, 0x0f, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x5f, 0x6a, 0x73, 0x6f,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x4a,
+	0x73, 0x6f, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x5f, 0x74, 0x65,
+	0x78, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74,
+	0x54, 0x65, 0x78, 0x74, 0x22, 0x88, 0x01, 0x0a, 0x0e, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x69,
+	0x7a, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x34, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72,
+	0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6d, 0x69, 0x6e, 0x69,
+	0x62, 0x65, 0x61, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73,
+	0x73, 0x48, 0x00, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x37, 0x0a,
+	0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e,
+	0x6d, 0x69, 0x6e, 0x69, 0x62, 0x65, 0x61, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x6d,
+	0x6d, 0x61, 0x72, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x48, 0x00, 0x52, 0x06,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x42, 0x07, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x22,
+	0x60, 0x0a, 0x0d, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04,
+	0x64, 0x61, 0x74, 0x61, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75,
+	0x72, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f, 0x6b, 0x65, 0x79,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65,
+	0x79, 0x22, 0x26, 0x0a, 0x0e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x32, 0xe0, 0x01, 0x0a, 0x05, 0x41, 0x67,
+	0x65, 0x6e, 0x74, 0x12, 0x45, 0x0a, 0x07, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x12, 0x1c,
+	0x2e, 0x6d, 0x69, 0x6e, 0x69, 0x62, 0x65, 0x61, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f,
+	0x6c, 0x6c, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x6d,
+	0x69, 0x6e, 0x69, 0x62, 0x65, 0x61, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6c, 0x6c,
+	0x65, 0x63, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x4b, 0x0a, 0x09, 0x53, 0x75,
+	0x6d, 0x6d, 0x61, 0x72, 0x69, 0x7a, 0x65, 0x12, 0x1e, 0x2e, 0x6d, 0x69, 0x6e, 0x69, 0x62, 0x65,
+	0x61, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x69, 0x7a, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x6d, 0x69, 0x6e, 0x69, 0x62, 0x65,
+	0x61, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x69, 0x7a, 0x65,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x43, 0x0a, 0x06, 0x56, 0x65, 0x72, 0x69, 0x66,
+	0x79, 0x12, 0x1b, 0x2e, 0x6d, 0x69, 0x6e, 0x69, 0x62, 0x65, 0x61, 0x73, 0x74, 0x2e, 0x76, 0x31,
+	0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c,
+	0x2e, 0x6d, 0x69, 0x6e, 0x69, 0x62, 0x65, 0x61, 0x73, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65,
+	0x72, 0x69, 0x66, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x35, 0x5a, 0x33,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x69, 0x6e, 0x69, 0x62,
+	0x65, 0x61, 0x73, 0x74, 0x2f, 0x75, 0x73, 0x62, 0x2d, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2f, 0x73,
+	0x72, 0x63, 0x2f, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_minibeast_v1_agent_proto_rawDescOnce sync.Once
+	file_minibeast_v1_agent_proto_rawDescData = file_minibeast_v1_agent_proto_rawDesc
+)
+
+func file_minibeast_v1_agent_proto_rawDescGZIP() []byte {
+	file_minibeast_v1_agent_proto_rawDescOnce.Do(func() {
+		file_minibeast_v1_agent_proto_rawDescData = protoimpl.X.CompressGZIP(file_minibeast_v1_agent_proto_rawDescData)
+	})
+	return file_minibeast_v1_agent_proto_rawDescData
+}
+
+var file_minibeast_v1_agent_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_minibeast_v1_agent_proto_goTypes = []any{
+	(*Progress)(nil),         // 0: minibeast.v1.Progress
+	(*CollectRequest)(nil),   // 1: minibeast.v1.CollectRequest
+	(*CollectResult)(nil),    // 2: minibeast.v1.CollectResult
+	(*CollectEvent)(nil),     // 3: minibeast.v1.CollectEvent
+	(*SummarizeRequest)(nil), // 4: minibeast.v1.SummarizeRequest
+	(*SummarizeResult)(nil),  // 5: minibeast.v1.SummarizeResult
+	(*SummarizeEvent)(nil),   // 6: minibeast.v1.SummarizeEvent
+	(*VerifyRequest)(nil),    // 7: minibeast.v1.VerifyRequest
+	(*VerifyResponse)(nil),   // 8: minibeast.v1.VerifyResponse
+}
+var file_minibeast_v1_agent_proto_depIdxs = []int32{
+	0, // 0: minibeast.v1.CollectEvent.progress:type_name -> minibeast.v1.Progress
+	2, // 1: minibeast.v1.CollectEvent.result:type_name -> minibeast.v1.CollectResult
+	0, // 2: minibeast.v1.SummarizeEvent.progress:type_name -> minibeast.v1.Progress
+	5, // 3: minibeast.v1.SummarizeEvent.result:type_name -> minibeast.v1.SummarizeResult
+	1, // 4: minibeast.v1.Agent.Collect:input_type -> minibeast.v1.CollectRequest
+	4, // 5: minibeast.v1.Agent.Summarize:input_type -> minibeast.v1.SummarizeRequest
+	7, // 6: minibeast.v1.Agent.Verify:input_type -> minibeast.v1.VerifyRequest
+	3, // 7: minibeast.v1.Agent.Collect:output_type -> minibeast.v1.CollectEvent
+	6, // 8: minibeast.v1.Agent.Summarize:output_type -> minibeast.v1.SummarizeEvent
+	8, // 9: minibeast.v1.Agent.Verify:output_type -> minibeast.v1.VerifyResponse
+	7, // [7:10] is the sub-list for method output_type
+	4, // [4:7] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_minibeast_v1_agent_proto_init() }
+func file_minibeast_v1_agent_proto_init() {
+	if File_minibeast_v1_agent_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_minibeast_v1_agent_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*Progress); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_minibeast_v1_agent_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*CollectRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_minibeast_v1_agent_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*CollectResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_minibeast_v1_agent_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*CollectEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_minibeast_v1_agent_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*SummarizeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_minibeast_v1_agent_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*SummarizeResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_minibeast_v1_agent_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*SummarizeEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_minibeast_v1_agent_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*VerifyRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_minibeast_v1_agent_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*VerifyResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_minibeast_v1_agent_proto_msgTypes[3].OneofWrappers = []any{
+		(*CollectEvent_Progress)(nil),
+		(*CollectEvent_Result)(nil),
+	}
+	file_minibeast_v1_agent_proto_msgTypes[6].OneofWrappers = []any{
+		(*SummarizeEvent_Progress)(nil),
+		(*SummarizeEvent_Result)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_minibeast_v1_agent_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_minibeast_v1_agent_proto_goTypes,
+		DependencyIndexes: file_minibeast_v1_agent_proto_depIdxs,
+		MessageInfos:      file_minibeast_v1_agent_proto_msgTypes,
+	}.Build()
+	File_minibeast_v1_agent_proto = out.File
+	file_minibeast_v1_agent_proto_rawDesc = nil
+	file_minibeast_v1_agent_proto_goTypes = nil
+	file_minibeast_v1_agent_proto_depIdxs = nil
+}
@@ -0,0 +1,114 @@
+package rpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/rpc/agentpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// NewGRPCServer builds a grpc.Server with TLS and token auth from cfg and registers srv
+// Complexity: O(1) (plus certificate parsing)
+func NewGRPCServer(cfg config.GRPCConfig, srv *Server) (*grpc.Server, error) {
+	opts := []grpc.ServerOption{}
+
+	if cfg.CertFile != "" {
+		tlsConfig, err := serverTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	if cfg.AuthToken != "" {
+		check := tokenChecker(cfg.AuthToken)
+		opts = append(opts,
+			grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+				if err := check(ctx); err != nil {
+					return nil, err
+				}
+				return handler(ctx, req)
+			}),
+			grpc.StreamInterceptor(func(s interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+				if err := check(ss.Context()); err != nil {
+					return err
+				}
+				return handler(s, ss)
+			}),
+		)
+	}
+
+	server := grpc.NewServer(opts...)
+	agentpb.RegisterAgentServer(server, srv)
+	return server, nil
+}
+
+// Serve listens on cfg.Address and serves until ctx is cancelled (graceful stop)
+// Complexity: O(1) setup, blocks for the server lifetime
+func Serve(ctx context.Context, cfg config.GRPCConfig, srv *Server) error {
+	server, err := NewGRPCServer(cfg, srv)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", cfg.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", cfg.Address, err)
+	}
+
+	stop := context.AfterFunc(ctx, server.GracefulStop)
+	defer stop()
+
+	if err := server.Serve(listener); err != nil {
+		return fmt.Errorf("gRPC server failed: %w", err)
+	}
+	return nil
+}
+
+// tokenChecker validates "authorization: Bearer <token>" metadata in constant time
+func tokenChecker(token string) func(ctx context.Context) error {
+	want := []byte("Bearer " + token)
+	return func(ctx context.Context) error {
+		md, _ := metadata.FromIncomingContext(ctx)
+		for _, got := range md.Get("authorization") {
+			if subtle.ConstantTimeCompare([]byte(got), want) == 1 {
+				return nil
+			}
+		}
+		return status.Error(codes.Unauthenticated, "invalid or missing bearer token")
+	}
+}
+
+// serverTLSConfig loads the server certificate and optional client CA (mutual TLS)
+func serverTLSConfig(cfg config.GRPCConfig) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server certificate: %w", err)
+	}
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+
+	if cfg.ClientCAFile != "" {
+		pemData, err := os.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pemData) {
+			return nil, fmt.Errorf("no certificates found in client CA file")
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
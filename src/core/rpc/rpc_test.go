@@ -0,0 +1,173 @@
+package rpc_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/inference"
+	"github.com/minibeast/usb-agent/src/core/rpc"
+	"github.com/minibeast/usb-agent/src/core/rpc/agentpb"
+	"github.com/minibeast/usb-agent/src/core/summarizer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeCollector returns fixed facts
+type fakeCollector struct{}
+
+func (fakeCollector) CollectAll(ctx context.Context) (*collection.Facts, error) {
+	return &collection.Facts{
+		Timestamp:    time.Date(2025, 11, 9, 12, 0, 0, 0, time.UTC),
+		Hostname:     "test-host",
+		HardwareUUID: "uuid-123",
+		OSName:       "Linux",
+		OSVersion:    "22.04",
+	}, nil
+}
+
+// dial starts an in-memory server and returns a connected client
+func dial(t *testing.T, cfg config.GRPCConfig) agentpb.AgentClient {
+	t.Helper()
+
+	s, err := summarizer.NewSummarizer(config.Default(), inference.NewFakeEngine())
+	if err != nil {
+		t.Fatalf("NewSummarizer() failed: %v", err)
+	}
+	server, err := rpc.NewGRPCServer(cfg, rpc.NewServer(fakeCollector{}, s))
+	if err != nil {
+		t.Fatalf("NewGRPCServer() failed: %v", err)
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient() failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return agentpb.NewAgentClient(conn)
+}
+
+// TestCollectThenSummarize verifies progress streaming and the Collect → Summarize round trip
+func TestCollectThenSummarize(t *testing.T) {
+	client := dial(t, config.Default().Service.GRPC)
+	ctx := context.Background()
+
+	stream, err := client.Collect(ctx, &agentpb.CollectRequest{})
+	if err != nil {
+		t.Fatalf("Collect() failed: %v", err)
+	}
+	var factsJSON []byte
+	progress := 0
+	for {
+		ev, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv() failed: %v", err)
+		}
+		if ev.GetProgress() != nil {
+			progress++
+		}
+		if r := ev.GetResult(); r != nil {
+			factsJSON = r.GetFactsJson()
+		}
+	}
+	if progress == 0 || factsJSON == nil {
+		t.Fatalf("Got %d progress events and facts %q", progress, factsJSON)
+	}
+
+	var facts collection.Facts
+	if err := json.Unmarshal(factsJSON, &facts); err != nil || facts.Hostname != "test-host" {
+		t.Fatalf("Unexpected facts: %v %+v", err, facts)
+	}
+
+	sum, err := client.Summarize(ctx, &agentpb.SummarizeRequest{FactsJson: factsJSON})
+	if err != nil {
+		t.Fatalf("Summarize() failed: %v", err)
+	}
+	var result *agentpb.SummarizeResult
+	for {
+		ev, err := sum.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv() failed: %v", err)
+		}
+		if r := ev.GetResult(); r != nil {
+			result = r
+		}
+	}
+	if result == nil || result.GetReportText() == "" || !json.Valid(result.GetReportJson()) {
+		t.Errorf("Unexpected summarize result: %v", result)
+	}
+}
+
+// TestSummarize_InvalidFacts verifies bad input maps to InvalidArgument
+func TestSummarize_InvalidFacts(t *testing.T) {
+	client := dial(t, config.Default().Service.GRPC)
+
+	stream, err := client.Summarize(context.Background(), &agentpb.SummarizeRequest{FactsJson: []byte("{}")})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Got %v, want InvalidArgument", err)
+	}
+}
+
+// TestVerify verifies valid and tampered signatures
+func TestVerify(t *testing.T) {
+	client := dial(t, config.Default().Service.GRPC)
+	publicKey, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	data := []byte("facts")
+	hash := sha256.Sum256(data)
+	signature := ed25519.Sign(privateKey, hash[:])
+
+	resp, err := client.Verify(context.Background(), &agentpb.VerifyRequest{Data: data, Signature: signature, PublicKey: publicKey})
+	if err != nil || !resp.GetValid() {
+		t.Errorf("Verify() = (%v, %v), want valid", resp, err)
+	}
+
+	resp, err = client.Verify(context.Background(), &agentpb.VerifyRequest{Data: []byte("tampered"), Signature: signature, PublicKey: publicKey})
+	if err != nil || resp.GetValid() {
+		t.Errorf("Verify() = (%v, %v), want invalid", resp, err)
+	}
+}
+
+// TestAuthToken verifies the bearer token interceptor
+func TestAuthToken(t *testing.T) {
+	cfg := config.Default().Service.GRPC
+	cfg.AuthToken = "secret"
+	client := dial(t, cfg)
+	req := &agentpb.VerifyRequest{PublicKey: make([]byte, ed25519.PublicKeySize)}
+
+	if _, err := client.Verify(context.Background(), req); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Got %v without token, want Unauthenticated", err)
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret")
+	if _, err := client.Verify(ctx, req); err != nil {
+		t.Errorf("Verify() with token failed: %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+// Package rpc exposes the agent pipeline as a gRPC service
+// (see proto/minibeast/v1/agent.proto; regenerate agentpb with `buf generate`).
+package rpc
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/crypto"
+	"github.com/minibeast/usb-agent/src/core/report"
+	"github.com/minibeast/usb-agent/src/core/rpc/agentpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrBusy is returned by a Collector whose runs are serialized elsewhere
+// (e.g., with the REST API) while another run is in progress
+var ErrBusy = errors.New("another run is in progress")
+
+// Collector runs Phase 1 (satisfied by *collection.Collector, or a full
+// pipeline run in serve mode)
+type Collector interface {
+	CollectAll(ctx context.Context) (*collection.Facts, error)
+}
+
+// ReportBuilder runs Phase 2 (satisfied by *summarizer.Summarizer)
+type ReportBuilder interface {
+	BuildReport(ctx context.Context, facts *collection.Facts) (*report.Report, error)
+}
+
+// Server implements agentpb.AgentServer on top of the core packages
+// Runs are serialized: a second Collect/Summarize while one is in flight
+// fails with Unavailable rather than competing for CPU and the model.
+type Server struct {
+	agentpb.UnimplementedAgentServer
+
+	collector Collector
+	builder   ReportBuilder
+	running   sync.Mutex
+}
+
+// NewServer creates a service backed by collector and builder
+// builder may be nil when the LLM phase is disabled (Summarize → Unavailable).
+// Complexity: O(1)
+func NewServer(collector Collector, builder ReportBuilder) *Server {
+	return &Server{collector: collector, builder: builder}
+}
+
+// Collect streams progress then the collected facts
+// Complexity: O(collection)
+func (s *Server) Collect(_ *agentpb.CollectRequest, stream agentpb.Agent_CollectServer) error {
+	if !s.running.TryLock() {
+		return status.Error(codes.Unavailable, "another run is in progress")
+	}
+	defer s.running.Unlock()
+
+	start := time.Now()
+	if err := stream.Send(collectProgress(start, "collect", "collection started")); err != nil {
+		return err
+	}
+
+	facts, err := s.collector.CollectAll(stream.Context())
+	if errors.Is(err, ErrBusy) {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	if err != nil {
+		return status.Errorf(codes.Internal, "collection failed: %v", err)
+	}
+
+	// Same bytes as facts.json on disk
+	data, err := facts.CanonicalJSON()
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to marshal facts: %v", err)
+	}
+
+	if err := stream.Send(collectProgress(start, "collect", "collection complete")); err != nil {
+		return err
+	}
+	return stream.Send(&agentpb.CollectEvent{
+		Event: &agentpb.CollectEvent_Result{Result: &agentpb.CollectResult{FactsJson: data}},
+	})
+}
+
+// Summarize streams progress then the report for the supplied facts
+// Complexity: O(inference)
+func (s *Server) Summarize(req *agentpb.SummarizeRequest, stream agentpb.Agent_SummarizeServer) error {
+	if s.builder == nil {
+		return status.Error(codes.Unavailable, "LLM phase is disabled")
+	}
+
+	var facts collection.Facts
+	if err := json.Unmarshal(req.GetFactsJson(), &facts); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid facts_json: %v", err)
+	}
+	if err := facts.Validate(); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid facts: %v", err)
+	}
+
+	if !s.running.TryLock() {
+		return status.Error(codes.Unavailable, "another run is in progress")
+	}
+	defer s.running.Unlock()
+
+	start := time.Now()
+	if err := stream.Send(summarizeProgress(start, "summarize", "inference started")); err != nil {
+		return err
+	}
+
+	rep, err := s.builder.BuildReport(stream.Context(), &facts)
+	if err != nil {
+		return status.Errorf(codes.Internal, "summarization failed: %v", err)
+	}
+	reportJSON, err := rep.RenderJSON()
+	if err != nil {
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+
+	if err := stream.Send(summarizeProgress(start, "summarize", "report complete")); err != nil {
+		return err
+	}
+	return stream.Send(&agentpb.SummarizeEvent{
+		Event: &agentpb.SummarizeEvent_Result{Result: &agentpb.SummarizeResult{
+			ReportJson: reportJSON,
+			ReportText: rep.RenderText(),
+		}},
+	})
+}
+
+// Verify checks an Ed25519 signature (crypto.Verify semantics)
+// Complexity: O(n) where n = len(data)
+func (s *Server) Verify(_ context.Context, req *agentpb.VerifyRequest) (*agentpb.VerifyResponse, error) {
+	if len(req.GetPublicKey()) != crypto.PublicKeySize {
+		return nil, status.Errorf(codes.InvalidArgument, "public_key must be %d bytes", crypto.PublicKeySize)
+	}
+	valid := crypto.Verify(ed25519.PublicKey(req.GetPublicKey()), req.GetData(), crypto.Signature(req.GetSignature()))
+	return &agentpb.VerifyResponse{Valid: valid}, nil
+}
+
+// progress builds a Progress message
+func progress(start time.Time, stage, message string) *agentpb.Progress {
+	return &agentpb.Progress{Stage: stage, Message: message, ElapsedMs: time.Since(start).Milliseconds()}
+}
+
+func collectProgress(start time.Time, stage, message string) *agentpb.CollectEvent {
+	return &agentpb.CollectEvent{Event: &agentpb.CollectEvent_Progress{Progress: progress(start, stage, message)}}
+}
+
+func summarizeProgress(start time.Time, stage, message string) *agentpb.SummarizeEvent {
+	return &agentpb.SummarizeEvent{Event: &agentpb.SummarizeEvent_Progress{Progress: progress(start, stage, message)}}
+}
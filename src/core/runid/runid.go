@@ -0,0 +1,88 @@
+// Package runid generates run identifiers
+// IDs are ULIDs: 48 bits of millisecond timestamp followed by 80 random
+// bits, written as 26 Crockford base32 characters. They sort by creation
+// time and can be correlated across hosts without coordination.
+package runid
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Length is the length of an encoded ID
+const Length = 26
+
+// alphabet is Crockford's base32 (no I, L, O or U)
+const alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// maxTime is the largest timestamp a ULID can carry (year 10889)
+const maxTime = 1<<48 - 1
+
+// New returns a fresh ID stamped with now
+// Complexity: O(1)
+func New(now time.Time) (string, error) {
+	return generate(now, rand.Reader)
+}
+
+// generate encodes now and 10 bytes from entropy
+func generate(now time.Time, entropy io.Reader) (string, error) {
+	ms := now.UnixMilli()
+	if ms < 0 || ms > maxTime {
+		return "", fmt.Errorf("time %s outside the run ID range", now.UTC().Format(time.RFC3339))
+	}
+	var id [16]byte
+	binary.BigEndian.PutUint16(id[0:2], uint16(ms>>32))
+	binary.BigEndian.PutUint32(id[2:6], uint32(ms))
+	if _, err := io.ReadFull(entropy, id[6:]); err != nil {
+		return "", fmt.Errorf("failed to generate run ID: %w", err)
+	}
+	return encode(id), nil
+}
+
+// encode writes 128 bits as 26 base32 characters (the first carries 3 bits)
+func encode(id [16]byte) string {
+	hi := binary.BigEndian.Uint64(id[0:8])
+	lo := binary.BigEndian.Uint64(id[8:16])
+	var out [Length]byte
+	for i := Length - 1; i >= 0; i-- {
+		out[i] = alphabet[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+// Parse checks s is a well-formed ID and returns its timestamp
+// Lowercase is accepted; Crockford's ambiguous letters are not.
+// Complexity: O(1)
+func Parse(s string) (time.Time, error) {
+	if len(s) != Length {
+		return time.Time{}, fmt.Errorf("run ID %q must be %d characters", s, Length)
+	}
+	var hi, lo uint64
+	for i := 0; i < Length; i++ {
+		v := strings.IndexByte(alphabet, upper(s[i]))
+		if v < 0 {
+			return time.Time{}, fmt.Errorf("run ID %q contains invalid character %q", s, s[i])
+		}
+		if i == 0 && v > 7 {
+			return time.Time{}, fmt.Errorf("run ID %q overflows 128 bits", s)
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(v)
+	}
+	ms := int64(hi >> 16)
+	return time.UnixMilli(ms).UTC(), nil
+}
+
+// upper maps ASCII lowercase to uppercase
+func upper(c byte) byte {
+	if 'a' <= c && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
@@ -0,0 +1,76 @@
+package runid
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerate_KnownTimestamp(t *testing.T) {
+	// Timestamp prefix from the ULID specification
+	now := time.UnixMilli(1469918176385)
+	id, err := generate(now, bytes.NewReader(make([]byte, 10)))
+	if err != nil {
+		t.Fatalf("generate() failed: %v", err)
+	}
+	if want := "01ARYZ6S41" + strings.Repeat("0", 16); id != want {
+		t.Errorf("generate() = %s, want %s", id, want)
+	}
+
+	id, err = generate(now, bytes.NewReader(bytes.Repeat([]byte{0xff}, 10)))
+	if err != nil {
+		t.Fatalf("generate() failed: %v", err)
+	}
+	if want := "01ARYZ6S41" + strings.Repeat("Z", 16); id != want {
+		t.Errorf("generate() = %s, want %s", id, want)
+	}
+}
+
+func TestNew_ParseRoundTrip(t *testing.T) {
+	now := time.Date(2025, 3, 14, 9, 26, 53, 589_000_000, time.UTC)
+	id, err := New(now)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if len(id) != Length {
+		t.Fatalf("len(%s) = %d, want %d", id, len(id), Length)
+	}
+
+	got, err := Parse(strings.ToLower(id))
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if !got.Equal(now) {
+		t.Errorf("Parse() = %v, want %v", got, now)
+	}
+}
+
+func TestNew_SortsByTime(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	first, _ := New(base)
+	second, _ := New(base.Add(time.Millisecond))
+	if first >= second {
+		t.Errorf("%s sorts after %s", first, second)
+	}
+}
+
+func TestParse_Rejects(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"01ARYZ6S41",                 // Too short
+		"01ARYZ6S41000000000000000I", // Ambiguous letter
+		"81ARYZ6S410000000000000000", // Overflows 128 bits
+		"01ARYZ6S41-000000000000000",
+	} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", s)
+		}
+	}
+}
+
+func TestNew_RejectsOutOfRangeTime(t *testing.T) {
+	if _, err := New(time.UnixMilli(-1)); err == nil {
+		t.Error("New() before the epoch succeeded, want error")
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !linux && !darwin && !windows
+
+package runlock
+
+import "os"
+
+// platformLockFile is unavailable; Acquire falls back to the recorded PID
+func platformLockFile(f *os.File) error {
+	return errUnsupported
+}
+
+// platformProcessAlive cannot probe processes here, so recorded owners count as stale
+func platformProcessAlive(pid int) bool {
+	return false
+}
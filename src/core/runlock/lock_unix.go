@@ -0,0 +1,30 @@
+//go:build linux || darwin
+
+package runlock
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// platformLockFile takes a non-blocking exclusive flock on f
+func platformLockFile(f *os.File) error {
+	err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, syscall.EWOULDBLOCK):
+		return errWouldBlock
+	case errors.Is(err, syscall.ENOLCK), errors.Is(err, syscall.ENOTSUP), errors.Is(err, syscall.EOPNOTSUPP):
+		return errUnsupported
+	default:
+		return err
+	}
+}
+
+// platformProcessAlive reports whether pid exists (signal 0 probes without signalling)
+func platformProcessAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
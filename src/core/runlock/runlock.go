@@ -0,0 +1,176 @@
+// Package runlock keeps two agent processes from running at once
+// A Lock is an OS file lock (flock on Unix, LockFileEx on Windows) on a small
+// file that also records the owner's PID, host and start time. The OS drops
+// the lock when its process dies, so a crashed run's file is simply taken
+// over; the recorded owner only makes the "already running" message useful.
+// Where file locks are unsupported (some network and FAT mounts), a live PID
+// recorded by this host is treated as the lock instead.
+package runlock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// HostFile is the per-host lock in the system temp directory
+const HostFile = "minibeast.lock"
+
+// DirFile is the per-output-directory lock
+const DirFile = ".minibeast.lock"
+
+// errWouldBlock and errUnsupported are returned by the platform lockFile
+var (
+	errWouldBlock  = errors.New("lock held by another process")
+	errUnsupported = errors.New("file locking unsupported")
+)
+
+// lockFile and processAlive are replaced in tests
+var (
+	lockFile     = platformLockFile
+	processAlive = platformProcessAlive
+)
+
+// Owner identifies the process holding a lock
+type Owner struct {
+	PID     int       `json:"pid"`
+	Host    string    `json:"host"`
+	Started time.Time `json:"started"`
+}
+
+// Lock is a held run lock
+type Lock struct {
+	path string
+	file *os.File
+
+	// Previous is the recorded owner of a stale lock this Lock took over
+	// (a run that crashed or was killed); nil when the lock was free
+	Previous *Owner
+}
+
+// HeldError reports that another live process holds the lock
+type HeldError struct {
+	Path  string
+	Owner *Owner // nil when the holder could not be identified
+}
+
+func (e *HeldError) Error() string {
+	if e.Owner == nil {
+		return fmt.Sprintf("another minibeast run holds %s", e.Path)
+	}
+	return fmt.Sprintf("another minibeast run (pid %d on %s, started %s) holds %s",
+		e.Owner.PID, e.Owner.Host, e.Owner.Started.Local().Format(time.RFC3339), e.Path)
+}
+
+// HostPath returns the per-host lock path
+func HostPath() string {
+	return filepath.Join(os.TempDir(), HostFile)
+}
+
+// DirPath returns the lock path for an output directory
+func DirPath(dir string) string {
+	return filepath.Join(dir, DirFile)
+}
+
+// Acquire takes the lock at path without waiting
+// Returns *HeldError when another live process holds it.
+// Complexity: O(1)
+func Acquire(path string) (*Lock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	writable := true
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if errors.Is(err, os.ErrPermission) {
+		// Another user's host lock: locking needs no write access
+		writable = false
+		f, err = os.Open(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock %s: %w", path, err)
+	}
+
+	prev := readOwner(f)
+	switch err := lockFile(f); {
+	case errors.Is(err, errWouldBlock):
+		f.Close()
+		return nil, &HeldError{Path: path, Owner: prev}
+	case errors.Is(err, errUnsupported):
+		if prev != nil && prev.PID != os.Getpid() && prev.Host == hostname() && processAlive(prev.PID) {
+			f.Close()
+			return nil, &HeldError{Path: path, Owner: prev}
+		}
+	case err != nil:
+		f.Close()
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+
+	l := &Lock{path: path, file: f, Previous: prev}
+	if writable {
+		if err := l.writeOwner(); err != nil {
+			l.Release()
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// Path returns the lock file path
+func (l *Lock) Path() string {
+	return l.path
+}
+
+// Release clears the recorded owner and unlocks
+// The file is kept: deleting it would let a waiting process lock an
+// unlinked inode while a third creates a fresh file.
+// Complexity: O(1)
+func (l *Lock) Release() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+	l.file.Truncate(0)    // Best-effort; a read-only handle cannot clear it
+	err := l.file.Close() // Closing the handle releases the OS lock
+	l.file = nil
+	return err
+}
+
+// writeOwner records this process as the owner
+func (l *Lock) writeOwner() error {
+	data, err := json.Marshal(Owner{PID: os.Getpid(), Host: hostname(), Started: time.Now().UTC()})
+	if err != nil {
+		return err
+	}
+	if err := l.file.Truncate(0); err != nil {
+		return fmt.Errorf("failed to write lock %s: %w", l.path, err)
+	}
+	if _, err := l.file.WriteAt(append(data, '\n'), 0); err != nil {
+		return fmt.Errorf("failed to write lock %s: %w", l.path, err)
+	}
+	return l.file.Sync()
+}
+
+// readOwner returns the recorded owner, or nil for an empty or foreign file
+func readOwner(f *os.File) *Owner {
+	data, err := io.ReadAll(io.LimitReader(f, 4096))
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+	var o Owner
+	if err := json.Unmarshal(data, &o); err != nil || o.PID <= 0 {
+		return nil
+	}
+	return &o
+}
+
+// hostname returns the host name recorded in owners
+func hostname() string {
+	name, err := os.Hostname()
+	if err != nil {
+		return "unknown"
+	}
+	return name
+}
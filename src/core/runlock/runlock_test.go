@@ -0,0 +1,90 @@
+package runlock
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAcquire_Exclusive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out", DirFile)
+	first, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("Acquire() failed: %v", err)
+	}
+	if first.Previous != nil {
+		t.Errorf("fresh lock reported a previous owner: %+v", first.Previous)
+	}
+
+	// OS file locks are per open file, so a second Acquire in-process contends
+	var held *HeldError
+	if _, err := Acquire(path); !errors.As(err, &held) {
+		t.Fatalf("second Acquire() error = %v, want *HeldError", err)
+	}
+	if held.Owner == nil || held.Owner.PID != os.Getpid() || !strings.Contains(held.Error(), "pid") {
+		t.Errorf("HeldError = %v (owner %+v)", held, held.Owner)
+	}
+
+	if err := first.Release(); err != nil {
+		t.Fatalf("Release() failed: %v", err)
+	}
+	again, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("Acquire() after Release() failed: %v", err)
+	}
+	defer again.Release()
+	if again.Previous != nil {
+		t.Errorf("cleanly released lock reported as stale: %+v", again.Previous)
+	}
+}
+
+// writeStaleOwner leaves an owner record behind, as a killed run would
+func writeStaleOwner(t *testing.T, path string, pid int) {
+	t.Helper()
+	data, _ := json.Marshal(Owner{PID: pid, Host: hostname(), Started: time.Now().Add(-time.Hour)})
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAcquire_TakesOverStaleLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DirFile)
+	writeStaleOwner(t, path, 999999)
+
+	l, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("Acquire() failed: %v", err)
+	}
+	defer l.Release()
+	if l.Previous == nil || l.Previous.PID != 999999 {
+		t.Errorf("Previous = %+v, want the stale owner", l.Previous)
+	}
+}
+
+func TestAcquire_FallbackWithoutFileLocks(t *testing.T) {
+	defer func(lf func(*os.File) error, alive func(int) bool) { lockFile, processAlive = lf, alive }(lockFile, processAlive)
+	lockFile = func(*os.File) error { return errUnsupported }
+
+	path := filepath.Join(t.TempDir(), DirFile)
+	writeStaleOwner(t, path, 4242)
+
+	processAlive = func(pid int) bool { return pid == 4242 }
+	var held *HeldError
+	if _, err := Acquire(path); !errors.As(err, &held) || held.Owner.PID != 4242 {
+		t.Fatalf("Acquire() with live recorded owner = %v, want *HeldError", err)
+	}
+
+	processAlive = func(int) bool { return false }
+	l, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("Acquire() with dead recorded owner failed: %v", err)
+	}
+	defer l.Release()
+	if l.Previous == nil || l.Previous.PID != 4242 {
+		t.Errorf("Previous = %+v", l.Previous)
+	}
+}
@@ -0,0 +1,145 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RunDirLayout names per-run output directories (UTC scheduled time)
+const RunDirLayout = "20060102T150405Z"
+
+// Run describes one scheduled activation
+type Run struct {
+	Scheduled time.Time // Activation time from the schedule (before jitter)
+	Started   time.Time // Actual start time
+	Dir       string    // Per-run output directory (created before the job starts)
+}
+
+// Result reports the outcome of one activation
+type Result struct {
+	Run      Run
+	Skipped  bool          // The previous run was still in progress
+	Duration time.Duration // Job wall time (0 when skipped)
+	Err      error
+}
+
+// Job executes one pipeline run; it must honor ctx cancellation
+type Job func(ctx context.Context, run Run) error
+
+// Options configures a Scheduler
+type Options struct {
+	// Maximum random delay added to each activation (spreads fleet load)
+	Jitter time.Duration
+
+	// Parent of the per-run output directories
+	RunsDir string
+
+	// Called after every activation (optional)
+	Report func(Result)
+}
+
+// Scheduler runs a Job on a Schedule with jitter and overlap protection
+// At most one job runs at a time: an activation that fires while the
+// previous run is still in progress is skipped, not queued. Missed
+// activations (e.g., the host was asleep) are not replayed.
+type Scheduler struct {
+	schedule Schedule
+	job      Job
+	opts     Options
+
+	running atomic.Bool
+	wg      sync.WaitGroup
+}
+
+// New creates a scheduler
+// Complexity: O(1)
+func New(schedule Schedule, job Job, opts Options) (*Scheduler, error) {
+	if schedule == nil {
+		return nil, fmt.Errorf("schedule cannot be nil")
+	}
+	if job == nil {
+		return nil, fmt.Errorf("job cannot be nil")
+	}
+	if opts.Jitter < 0 {
+		return nil, fmt.Errorf("jitter cannot be negative")
+	}
+	if opts.RunsDir == "" {
+		return nil, fmt.Errorf("runs directory cannot be empty")
+	}
+	return &Scheduler{schedule: schedule, job: job, opts: opts}, nil
+}
+
+// Run fires the job on schedule until ctx is cancelled, then waits for the in-flight run
+// Complexity: O(1) per activation
+func (s *Scheduler) Run(ctx context.Context) error {
+	defer s.wg.Wait()
+
+	prev := time.Now()
+	for {
+		next := s.schedule.Next(prev)
+		if next.IsZero() {
+			return fmt.Errorf("schedule has no further activations")
+		}
+		// Realign after a late wake-up instead of replaying missed activations
+		if now := time.Now(); next.Before(now) {
+			next = s.schedule.Next(now)
+		}
+		prev = next
+
+		fire := next
+		if s.opts.Jitter > 0 {
+			fire = fire.Add(rand.N(s.opts.Jitter))
+		}
+
+		timer := time.NewTimer(time.Until(fire))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+
+		s.fire(ctx, next)
+	}
+}
+
+// fire starts the job for one activation unless a run is already in progress
+func (s *Scheduler) fire(ctx context.Context, scheduled time.Time) {
+	run := Run{
+		Scheduled: scheduled,
+		Started:   time.Now(),
+		Dir:       filepath.Join(s.opts.RunsDir, scheduled.UTC().Format(RunDirLayout)),
+	}
+
+	if !s.running.CompareAndSwap(false, true) {
+		s.report(Result{Run: run, Skipped: true})
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer s.running.Store(false)
+
+		var err error
+		if err = os.MkdirAll(run.Dir, 0755); err != nil {
+			err = fmt.Errorf("failed to create run directory: %w", err)
+		} else {
+			err = s.job(ctx, run)
+		}
+		s.report(Result{Run: run, Duration: time.Since(run.Started), Err: err})
+	}()
+}
+
+// report forwards a result to the configured callback
+func (s *Scheduler) report(r Result) {
+	if s.opts.Report != nil {
+		s.opts.Report(r)
+	}
+}
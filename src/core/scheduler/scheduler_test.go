@@ -0,0 +1,113 @@
+package scheduler
+
+import (
+	"context"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestParse_Next verifies activation times for common expressions
+func TestParse_Next(t *testing.T) {
+	from := time.Date(2025, 11, 5, 10, 7, 30, 0, time.UTC) // Wednesday
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2025, 11, 5, 10, 15, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2025, 11, 6, 2, 0, 0, 0, time.UTC)},
+		{"30 9 * * mon-fri", time.Date(2025, 11, 6, 9, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2025, 11, 9, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 13 * fri", time.Date(2025, 11, 7, 0, 0, 0, 0, time.UTC)}, // Either day field matches
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, 11, 5, 11, 0, 0, 0, time.UTC)},
+		{"@every 6h", from.Add(6 * time.Hour)},
+	}
+
+	for _, tt := range tests {
+		s, err := Parse(tt.spec)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", tt.spec, err)
+			continue
+		}
+		if got := s.Next(from); !got.Equal(tt.want) {
+			t.Errorf("Parse(%q).Next() = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+// TestParse_Invalid verifies malformed and unsatisfiable expressions are rejected
+func TestParse_Invalid(t *testing.T) {
+	for _, spec := range []string{
+		"* * * *",
+		"60 * * * *",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"0 0 * * funday",
+		"0 0 30 2 *",
+		"@every 10s",
+		"@every soon",
+	} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) should fail", spec)
+		}
+	}
+}
+
+// TestScheduler_SkipsOverlap verifies slow runs never overlap and get their own directory
+func TestScheduler_SkipsOverlap(t *testing.T) {
+	var active, maxActive, ran, skipped atomic.Int32
+	var mu sync.Mutex
+	var dirErr error
+
+	job := func(ctx context.Context, run Run) error {
+		if _, err := os.Stat(run.Dir); err != nil {
+			mu.Lock()
+			dirErr = err
+			mu.Unlock()
+		}
+		n := active.Add(1)
+		if n > maxActive.Load() {
+			maxActive.Store(n)
+		}
+		time.Sleep(60 * time.Millisecond)
+		active.Add(-1)
+		ran.Add(1)
+		return nil
+	}
+
+	s, err := New(Interval(20*time.Millisecond), job, Options{
+		RunsDir: t.TempDir(),
+		Report: func(r Result) {
+			if r.Skipped {
+				skipped.Add(1)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	if err := s.Run(ctx); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+
+	if maxActive.Load() != 1 {
+		t.Errorf("Max concurrent runs = %d, want 1", maxActive.Load())
+	}
+	if ran.Load() < 2 || skipped.Load() == 0 {
+		t.Errorf("ran %d, skipped %d; want several runs and some skips", ran.Load(), skipped.Load())
+	}
+	if dirErr != nil {
+		t.Errorf("Run directory missing: %v", dirErr)
+	}
+	if active.Load() != 0 {
+		t.Errorf("Run() returned with a job in flight")
+	}
+}
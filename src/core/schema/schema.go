@@ -0,0 +1,242 @@
+// Package schema generates JSON Schemas for the published output formats
+// Schemas are derived from the Go types by reflection, so they cannot drift
+// from what the agent actually writes.
+package schema
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/bundle"
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/export"
+	"github.com/minibeast/usb-agent/src/core/inference"
+	coreio "github.com/minibeast/usb-agent/src/core/io"
+	"github.com/minibeast/usb-agent/src/core/report"
+)
+
+// SchemaVersion is the version of the published output contract (see
+// collection.SchemaVersion)
+const SchemaVersion = collection.SchemaVersion
+
+// draft is the JSON Schema dialect of every generated schema
+const draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document (the subset the generator emits)
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Version              string             `json:"x-schema-version,omitempty"`
+	Since                string             `json:"x-since,omitempty"` // Version that added the property
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// Document pairs a schema with its published file name
+type Document struct {
+	Name   string // e.g., "facts" → facts.schema.json
+	Schema *Schema
+}
+
+// FileName returns "<name>.schema.json"
+func (d Document) FileName() string {
+	return d.Name + ".schema.json"
+}
+
+// enums lists the allowed values of string-encoded enum types
+var enums = map[reflect.Type][]string{
+	reflect.TypeOf(report.Severity(0)): {"INFO", "LOW", "MEDIUM", "HIGH", "CRITICAL"},
+	reflect.TypeOf(inference.Confidence("")): {
+		string(inference.ConfidenceGrounded), string(inference.ConfidenceInferred),
+	},
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// All returns every published schema in a stable order
+// Complexity: O(total fields)
+func All() []Document {
+	docs := []Document{
+		{"facts", Generate("facts", "Collected system facts (facts.json)", collection.Facts{})},
+		{"report", Generate("report", "Structured report (report.json)", report.Report{})},
+		{"bundle-metadata", Generate("bundle-metadata", "Bundle metadata (metadata.json inside .mbz)", bundle.Metadata{})},
+		{"bundle-manifest", Generate("bundle-manifest", "Bundle manifest (manifest.json inside .mbz)", bundle.Manifest{})},
+		{"run-manifest", Generate("run-manifest", "Run manifest (<run>.manifest.json)", coreio.Manifest{})},
+	}
+	for _, table := range export.CSVTables {
+		name := "csv-" + strings.TrimSuffix(table.File, ".csv")
+		docs = append(docs, Document{name, CSVRow(name, table)})
+	}
+	return docs
+}
+
+// Lookup returns the schema published under name
+func Lookup(name string) (*Schema, error) {
+	for _, doc := range All() {
+		if doc.Name == name {
+			return doc.Schema, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown schema %q", name)
+}
+
+// Generate derives a root schema for v's type
+// Named struct types become $defs entries referenced by $ref.
+// Complexity: O(total fields)
+func Generate(name, description string, v any) *Schema {
+	g := &generator{defs: map[string]*Schema{}}
+	root := g.schemaFor(reflect.TypeOf(v), true)
+
+	root.Schema = draft
+	root.ID = id(name)
+	root.Title = name
+	root.Description = description
+	root.Version = SchemaVersion
+	if len(g.defs) > 0 {
+		root.Defs = g.defs
+	}
+	return root
+}
+
+// CSVRow describes one CSV table as an object of string columns
+// Column order is documented in the description (JSON objects are unordered).
+// Complexity: O(|columns|)
+func CSVRow(name string, table export.CSVTable) *Schema {
+	s := &Schema{
+		Schema:     draft,
+		ID:         id(name),
+		Title:      name,
+		Version:    SchemaVersion,
+		Type:       "object",
+		Properties: map[string]*Schema{},
+	}
+
+	columns := make([]string, 0, len(table.Columns))
+	for _, col := range table.Columns {
+		s.Properties[col.Name] = &Schema{Type: "string", Description: col.Description}
+		s.Required = append(s.Required, col.Name)
+		columns = append(columns, col.Name)
+	}
+	s.Description = fmt.Sprintf("%s: %s. Columns in order: %s", table.File, table.Description, strings.Join(columns, ", "))
+	return s
+}
+
+// id returns the schema URN for name at SchemaVersion
+func id(name string) string {
+	return "urn:minibeast:schema:" + SchemaVersion + ":" + name
+}
+
+// generator accumulates $defs while walking types
+type generator struct {
+	defs map[string]*Schema
+}
+
+// schemaFor maps a Go type to its JSON encoding's schema
+// root=true inlines the top-level struct instead of referencing it.
+func (g *generator) schemaFor(t reflect.Type, root bool) *Schema {
+	if values, ok := enums[t]; ok {
+		return &Schema{Type: "string", Enum: values}
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawMessageType:
+		return &Schema{}
+	case t.Implements(textMarshalerType):
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schemaFor(t.Elem(), root)
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"} // base64
+		}
+		return &Schema{Type: "array", Items: g.schemaFor(t.Elem(), false)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaFor(t.Elem(), false)}
+	case reflect.Struct:
+		if root {
+			return g.object(t)
+		}
+		if _, seen := g.defs[t.Name()]; !seen {
+			g.defs[t.Name()] = &Schema{} // Placeholder breaks recursion
+			g.defs[t.Name()] = g.object(t)
+		}
+		return &Schema{Ref: "#/$defs/" + t.Name()}
+	default:
+		return &Schema{}
+	}
+}
+
+// object builds the schema of a struct from its json tags
+// Fields without omitempty are required (the encoder always writes them);
+// a since tag records the version that added the field.
+func (g *generator) object(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := g.schemaFor(field.Type, false)
+		if since := field.Tag.Get("since"); since != "" {
+			copied := *prop
+			copied.Since = since
+			prop = &copied
+		}
+		s.Properties[name] = prop
+		if !strings.Contains(opts, "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+	sort.Strings(s.Required)
+	return s
+}
+
+// Marshal renders a schema as indented JSON
+func Marshal(s *Schema) ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal schema: %w", err)
+	}
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,141 @@
+package schema
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/bundle"
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/export"
+	"github.com/minibeast/usb-agent/src/core/inference"
+	"github.com/minibeast/usb-agent/src/core/platform/types"
+	"github.com/minibeast/usb-agent/src/core/report"
+	"github.com/minibeast/usb-agent/src/core/testsupport"
+)
+
+// checkObject verifies instance keys against an object schema (one level)
+func checkObject(t *testing.T, name string, s *Schema, v any) {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var instance map[string]any
+	if err := json.Unmarshal(data, &instance); err != nil {
+		t.Fatal(err)
+	}
+
+	for key := range instance {
+		if _, ok := s.Properties[key]; !ok {
+			t.Errorf("%s: encoded key %q missing from schema", name, key)
+		}
+	}
+	for _, key := range s.Required {
+		if _, ok := instance[key]; !ok {
+			t.Errorf("%s: required key %q missing from encoding", name, key)
+		}
+	}
+}
+
+func TestGenerate_MatchesEncoding(t *testing.T) {
+	facts := collection.Facts{
+		Timestamp:      time.Date(2025, 11, 9, 12, 0, 0, 0, time.UTC),
+		Hostname:       "test-host",
+		Users:          []types.User{{Username: "alice"}},
+		LoggedInUsers:  []string{},
+		HomeDirs:       []string{},
+		RecentProfiles: []types.UserProfile{},
+		LocalIPs:       []types.NetworkInterface{},
+		MACAddresses:   []types.NetworkInterface{},
+		WiFiSSIDs:      []string{},
+	}
+	factsSchema, err := Lookup("facts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkObject(t, "facts", factsSchema, facts)
+
+	if got := factsSchema.Properties["timestamp"]; got.Type != "string" || got.Format != "date-time" {
+		t.Errorf("timestamp schema = %+v", got)
+	}
+	users := factsSchema.Properties["users"]
+	if users.Type != "array" || users.Items.Ref != "#/$defs/User" || factsSchema.Defs["User"] == nil {
+		t.Errorf("users schema = %+v", users)
+	}
+
+	rpt := report.Report{Risks: []report.Risk{{Text: "x", Severity: report.SeverityHigh, Confidence: inference.ConfidenceGrounded}}}
+	reportSchema, _ := Lookup("report")
+	checkObject(t, "report", reportSchema, rpt)
+	severity := reportSchema.Defs["Risk"].Properties["severity"]
+	if severity.Type != "string" || len(severity.Enum) != 5 || severity.Enum[3] != report.SeverityHigh.String() {
+		t.Errorf("severity schema = %+v", severity)
+	}
+
+	metadataSchema, _ := Lookup("bundle-metadata")
+	checkObject(t, "bundle-metadata", metadataSchema, bundle.Metadata{Format: bundle.Format})
+}
+
+func TestAll_Versioned(t *testing.T) {
+	docs := All()
+	if len(docs) != 5+len(export.CSVTables) {
+		t.Fatalf("Got %d schemas", len(docs))
+	}
+
+	seen := map[string]bool{}
+	for _, doc := range docs {
+		if seen[doc.Name] {
+			t.Errorf("Duplicate schema %s", doc.Name)
+		}
+		seen[doc.Name] = true
+
+		if doc.Schema.Version != SchemaVersion || doc.Schema.ID != "urn:minibeast:schema:"+SchemaVersion+":"+doc.Name {
+			t.Errorf("%s: version %q id %q", doc.Name, doc.Schema.Version, doc.Schema.ID)
+		}
+		if _, err := Marshal(doc.Schema); err != nil {
+			t.Errorf("%s: %v", doc.Name, err)
+		}
+	}
+
+	users, err := Lookup("csv-users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users.Required) != len(export.CSVTables[0].Columns) || users.Properties["username"] == nil {
+		t.Errorf("csv-users schema = %+v", users)
+	}
+}
+
+// TestFactsSchema_Embedded verifies the facts schema embedded in collection is
+// the generated one (run with MINIBEAST_UPDATE_GOLDEN=1 to regenerate it)
+func TestFactsSchema_Embedded(t *testing.T) {
+	s, err := Lookup("facts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join("..", "collection", "facts.schema.json")
+	if os.Getenv(testsupport.UpdateEnv) == "1" {
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	if diff := testsupport.Diff(collection.FactsSchema(), data); diff != "" {
+		t.Errorf("embedded facts schema is stale (-embedded +generated):\n%s\nrun with %s=1 to regenerate", diff, testsupport.UpdateEnv)
+	}
+	if got := s.Properties["listening_ports"].Since; got != "1.3" {
+		t.Errorf("listening_ports x-since = %q, want 1.3", got)
+	}
+	if got := s.Properties["security_products"].Since; got != "1.4" {
+		t.Errorf("security_products x-since = %q, want 1.4", got)
+	}
+	if got := s.Properties["installed_updates"].Since; got != "1.4" {
+		t.Errorf("installed_updates x-since = %q, want 1.4", got)
+	}
+}
@@ -0,0 +1,189 @@
+// Package server runs minibeast as a resident agent for fleet monitoring
+// The pipeline runs on the daemon schedule (and on demand via POST /collect);
+// the latest facts and report are served over the local REST API, with the
+// gRPC service alongside when enabled. Each run writes its artifacts to its
+// own directory under service.daemon.runs_directory, exactly as daemon mode.
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/api"
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/export"
+	"github.com/minibeast/usb-agent/src/core/rpc"
+	"github.com/minibeast/usb-agent/src/core/scheduler"
+)
+
+// Job executes one pipeline run writing its artifacts to dir
+// A degraded run returns its payload along with the error; a nil payload
+// means nothing was collected.
+type Job func(ctx context.Context, dir string) (*export.Payload, error)
+
+// Options configures an Agent
+type Options struct {
+	// Backs the gRPC Summarize call (nil = Summarize unavailable); gRPC
+	// Collect runs the job like POST /collect
+	Builder rpc.ReportBuilder
+
+	// Called after every run, scheduled or on demand (optional)
+	Report func(scheduler.Result)
+}
+
+// Agent is the resident agent: scheduler, REST API and optional gRPC server
+type Agent struct {
+	cfg   config.ServiceConfig
+	job   Job
+	opts  Options
+	api   *api.Server
+	grpc  *rpc.Server // nil unless service.server.grpc
+	sched *scheduler.Scheduler
+	stop  context.Context // Cancelled when Run returns; ends on-demand runs
+}
+
+// New creates an agent running job on cfg.Daemon.Schedule
+// Complexity: O(1)
+func New(cfg config.ServiceConfig, job Job, opts Options) (*Agent, error) {
+	if job == nil {
+		return nil, fmt.Errorf("job cannot be nil")
+	}
+	schedule, err := scheduler.Parse(cfg.Daemon.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("service.daemon.schedule: %w", err)
+	}
+
+	a := &Agent{cfg: cfg, job: job, opts: opts, stop: context.Background()}
+	if a.api, err = api.NewRunServer(cfg.REST, a.collectNow); err != nil {
+		return nil, err
+	}
+	if cfg.Server.GRPC {
+		a.grpc = rpc.NewServer(runCollector{a}, opts.Builder)
+	}
+	a.sched, err = scheduler.New(schedule, a.scheduled, scheduler.Options{
+		Jitter:  time.Duration(cfg.Daemon.JitterMs) * time.Millisecond,
+		RunsDir: cfg.Daemon.RunsDirectory,
+		Report:  opts.Report,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Handler returns the REST handler (for embedding and tests)
+// Complexity: O(1)
+func (a *Agent) Handler() http.Handler {
+	return a.api.Handler()
+}
+
+// Run serves the APIs and runs the schedule until ctx is cancelled
+// A server that fails (e.g., its address is in use) stops the others, and
+// its error is returned.
+// Complexity: O(1) setup, blocks for the agent lifetime
+func (a *Agent) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	a.stop = ctx
+
+	services := []func(context.Context) error{
+		func(ctx context.Context) error { return api.Serve(ctx, a.cfg.REST, a.api) },
+		a.sched.Run,
+	}
+	if a.grpc != nil {
+		services = append(services, func(ctx context.Context) error { return rpc.Serve(ctx, a.cfg.GRPC, a.grpc) })
+	}
+	errs := make(chan error, len(services))
+	for _, serve := range services {
+		go func() {
+			errs <- serve(ctx)
+			cancel()
+		}()
+	}
+
+	started := make(chan struct{})
+	if a.cfg.Server.RunAtStart {
+		go func() {
+			defer close(started)
+			a.api.Do(ctx, a.collectNow) // Outcome goes to Options.Report
+		}()
+	} else {
+		close(started)
+	}
+
+	var failed []error
+	for range services {
+		if err := <-errs; err != nil {
+			failed = append(failed, err)
+		}
+	}
+	<-started
+	return errors.Join(failed...)
+}
+
+// scheduled is the scheduler job: one run published as the latest
+func (a *Agent) scheduled(ctx context.Context, run scheduler.Run) error {
+	_, err := a.api.Do(ctx, a.runIn(run.Dir))
+	return err
+}
+
+// collectNow runs outside the schedule (startup and POST /collect) in a
+// directory named for the current time
+func (a *Agent) collectNow(ctx context.Context) (*api.Run, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer context.AfterFunc(a.stop, cancel)()
+
+	now := time.Now()
+	run := scheduler.Run{
+		Scheduled: now,
+		Started:   now,
+		Dir:       filepath.Join(a.cfg.Daemon.RunsDirectory, now.UTC().Format(scheduler.RunDirLayout)),
+	}
+	var r *api.Run
+	err := os.MkdirAll(run.Dir, 0755)
+	if err != nil {
+		err = fmt.Errorf("failed to create run directory: %w", err)
+	} else {
+		r, err = a.runIn(run.Dir)(ctx)
+	}
+	if a.opts.Report != nil {
+		a.opts.Report(scheduler.Result{Run: run, Duration: time.Since(now), Err: err})
+	}
+	return r, err
+}
+
+// runCollector backs gRPC Collect with the same run as POST /collect, so
+// the run lock, consent, pseudonymization and redaction all apply and the
+// facts are published as the latest
+type runCollector struct{ a *Agent }
+
+// CollectAll performs one on-demand run and returns its facts
+// A degraded run still returns its facts (partial, failed_categories).
+func (c runCollector) CollectAll(ctx context.Context) (*collection.Facts, error) {
+	r, err := c.a.api.Do(ctx, c.a.collectNow)
+	if errors.Is(err, api.ErrBusy) {
+		return nil, rpc.ErrBusy
+	}
+	if r == nil {
+		return nil, err
+	}
+	return r.Facts, nil
+}
+
+// runIn adapts the job to the API's run function for one directory
+func (a *Agent) runIn(dir string) api.RunFunc {
+	return func(ctx context.Context) (*api.Run, error) {
+		payload, err := a.job(ctx, dir)
+		if payload == nil || payload.Facts == nil {
+			return nil, err
+		}
+		return &api.Run{Facts: payload.Facts, Report: payload.Report}, err
+	}
+}
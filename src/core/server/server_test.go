@@ -0,0 +1,250 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/export"
+	"github.com/minibeast/usb-agent/src/core/rpc/agentpb"
+	"github.com/minibeast/usb-agent/src/core/scheduler"
+	"github.com/minibeast/usb-agent/src/core/server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// serviceConfig returns agent settings with token "secret" and runs under a temp dir
+func serviceConfig(t *testing.T) config.ServiceConfig {
+	t.Helper()
+	cfg := config.Default().Service
+	cfg.REST.AuthToken = "secret"
+	cfg.Daemon.Schedule = "@every 1h"
+	cfg.Daemon.JitterMs = 0
+	cfg.Daemon.RunsDirectory = t.TempDir()
+	cfg.Server.RunAtStart = false
+	return cfg
+}
+
+// fakeJob returns fixed facts, recording the directories it ran in
+func fakeJob(dirs chan<- string) server.Job {
+	return func(ctx context.Context, dir string) (*export.Payload, error) {
+		dirs <- dir
+		return &export.Payload{Facts: &collection.Facts{Hostname: "kiosk-01"}}, nil
+	}
+}
+
+// get sends an authenticated request
+func get(t *testing.T, method, url string) (int, string) {
+	t.Helper()
+	req, _ := http.NewRequest(method, url, nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+// TestCollect_RunsPipelineInRunDir verifies POST /collect runs the job in a
+// new run directory and publishes its facts
+func TestCollect_RunsPipelineInRunDir(t *testing.T) {
+	cfg := serviceConfig(t)
+	dirs := make(chan string, 1)
+	agent, err := server.New(cfg, fakeJob(dirs), server.Options{})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	srv := httptest.NewServer(agent.Handler())
+	defer srv.Close()
+
+	if code, body := get(t, http.MethodPost, srv.URL+"/collect"); code != http.StatusOK {
+		t.Fatalf("/collect = %d: %s", code, body)
+	}
+	dir := <-dirs
+	if filepath.Dir(dir) != cfg.Daemon.RunsDirectory {
+		t.Errorf("run dir %s not under %s", dir, cfg.Daemon.RunsDirectory)
+	}
+	if _, err := os.Stat(dir); err != nil {
+		t.Errorf("run dir not created: %v", err)
+	}
+	if code, body := get(t, http.MethodGet, srv.URL+"/facts/latest"); code != http.StatusOK || !strings.Contains(body, "kiosk-01") {
+		t.Errorf("/facts/latest = %d: %s", code, body)
+	}
+}
+
+// TestCollect_DegradedRunPublished verifies a run with failed categories is
+// still served, with its error in the response
+func TestCollect_DegradedRunPublished(t *testing.T) {
+	job := func(ctx context.Context, dir string) (*export.Payload, error) {
+		return &export.Payload{Facts: &collection.Facts{Hostname: "kiosk-01"}}, errors.New("disk_info: permission denied")
+	}
+	agent, err := server.New(serviceConfig(t), job, server.Options{})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	srv := httptest.NewServer(agent.Handler())
+	defer srv.Close()
+
+	if code, body := get(t, http.MethodPost, srv.URL+"/collect"); code != http.StatusOK || !strings.Contains(body, `"run_error": "disk_info: permission denied"`) {
+		t.Errorf("/collect = %d: %s", code, body)
+	}
+	if code, _ := get(t, http.MethodGet, srv.URL+"/facts/latest"); code != http.StatusOK {
+		t.Errorf("/facts/latest = %d, want 200", code)
+	}
+}
+
+// TestRun_CollectsAtStartAndServes verifies run_at_start and the REST listener
+func TestRun_CollectsAtStartAndServes(t *testing.T) {
+	cfg := serviceConfig(t)
+	cfg.Server.RunAtStart = true
+	cfg.REST.Address = freeAddress(t)
+	dirs := make(chan string, 1)
+	done := make(chan scheduler.Result, 1)
+	agent, err := server.New(cfg, fakeJob(dirs), server.Options{Report: func(r scheduler.Result) { done <- r }})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+	go func() { stopped <- agent.Run(ctx) }()
+
+	select {
+	case r := <-done:
+		if r.Err != nil {
+			t.Errorf("startup run failed: %v", r.Err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no startup run")
+	}
+	<-dirs
+
+	// The listener may not be up, nor the run published, yet
+	req, _ := http.NewRequest(http.MethodGet, "http://"+cfg.REST.Address+"/facts/latest", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(20 * time.Millisecond) {
+		resp, err := http.DefaultClient.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				break
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("/facts/latest not served: %v", err)
+		}
+	}
+
+	cancel()
+	if err := <-stopped; err != nil {
+		t.Errorf("Run() = %v, want nil after cancel", err)
+	}
+}
+
+// TestRun_ListenFailureStopsAgent verifies a server that cannot start ends Run
+func TestRun_ListenFailureStopsAgent(t *testing.T) {
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer busy.Close()
+
+	cfg := serviceConfig(t)
+	cfg.REST.Address = busy.Addr().String()
+	agent, err := server.New(cfg, fakeJob(make(chan string, 1)), server.Options{})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	stopped := make(chan error, 1)
+	go func() { stopped <- agent.Run(context.Background()) }()
+	select {
+	case err := <-stopped:
+		if err == nil {
+			t.Error("Run() = nil, want listen error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() did not stop")
+	}
+}
+
+// TestRun_GRPCCollectRunsJob verifies gRPC Collect is an on-demand pipeline
+// run (run directory, published facts) rather than a raw collection
+func TestRun_GRPCCollectRunsJob(t *testing.T) {
+	cfg := serviceConfig(t)
+	cfg.REST.Address = freeAddress(t)
+	cfg.Server.GRPC = true
+	cfg.GRPC.Address = freeAddress(t)
+	cfg.GRPC.AuthToken = "grpc-secret"
+	dirs := make(chan string, 1)
+	agent, err := server.New(cfg, fakeJob(dirs), server.Options{})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+	go func() { stopped <- agent.Run(ctx) }()
+	defer func() {
+		cancel()
+		<-stopped
+	}()
+
+	conn, err := grpc.NewClient(cfg.GRPC.Address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	callCtx, callCancel := context.WithTimeout(metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer grpc-secret"), 5*time.Second)
+	defer callCancel()
+	stream, err := agentpb.NewAgentClient(conn).Collect(callCtx, &agentpb.CollectRequest{}, grpc.WaitForReady(true))
+	if err != nil {
+		t.Fatalf("Collect() failed: %v", err)
+	}
+	var factsJSON []byte
+	for {
+		ev, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv() failed: %v", err)
+		}
+		if r := ev.GetResult(); r != nil {
+			factsJSON = r.GetFactsJson()
+		}
+	}
+	if !strings.Contains(string(factsJSON), "kiosk-01") {
+		t.Errorf("facts = %s, want the job's facts", factsJSON)
+	}
+	select {
+	case dir := <-dirs:
+		if filepath.Dir(dir) != cfg.Daemon.RunsDirectory {
+			t.Errorf("run dir %s not under %s", dir, cfg.Daemon.RunsDirectory)
+		}
+	default:
+		t.Error("gRPC Collect did not run the job")
+	}
+}
+
+// freeAddress returns a loopback address nothing listens on
+func freeAddress(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
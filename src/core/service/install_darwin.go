@@ -0,0 +1,50 @@
+//go:build darwin
+
+package service
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// launchdDir holds system-wide launch daemons
+var launchdDir = "/Library/LaunchDaemons"
+
+// Install writes the launchd plist for s and bootstraps it into the system domain
+// With dryRun the plist and the launchctl command are printed to out and
+// nothing is changed.
+// Complexity: O(|spec|) plus the launchctl call
+func Install(s *Spec, out io.Writer, dryRun bool) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	path := filepath.Join(launchdDir, LaunchdLabel(s.Name)+".plist")
+	if !dryRun {
+		// launchd does not create the log directory
+		if err := os.MkdirAll(filepath.Join(s.WorkingDir, "out"), 0755); err != nil {
+			return fmt.Errorf("failed to create log directory: %w", err)
+		}
+	}
+	if err := writeDefinition(out, dryRun, path, LaunchdPlist(s)); err != nil {
+		return err
+	}
+	return runAll(out, dryRun, []string{"launchctl", "bootstrap", "system", path})
+}
+
+// Uninstall boots the named daemon out of launchd and removes its plist
+// Complexity: O(1) plus the launchctl call
+func Uninstall(name string, out io.Writer, dryRun bool) error {
+	if !validName(name) {
+		return fmt.Errorf("invalid service name %q", name)
+	}
+	path := filepath.Join(launchdDir, LaunchdLabel(name)+".plist")
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("%w: %s not found", ErrNotInstalled, path)
+	}
+	if err := runAll(out, dryRun, []string{"launchctl", "bootout", "system/" + LaunchdLabel(name)}); err != nil {
+		return err
+	}
+	return removeDefinition(out, dryRun, path)
+}
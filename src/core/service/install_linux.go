@@ -0,0 +1,70 @@
+//go:build linux
+
+package service
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// systemdDir holds system unit files; systemdRuntime exists while systemd is PID 1
+var (
+	systemdDir     = "/etc/systemd/system"
+	systemdRuntime = "/run/systemd/system"
+)
+
+// Install writes the systemd unit for s, then enables and starts it
+// With dryRun the unit and the systemctl commands are printed to out and
+// nothing is changed.
+// Complexity: O(|spec|) plus the systemctl calls
+func Install(s *Spec, out io.Writer, dryRun bool) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	if !dryRun {
+		if err := checkSystemd(); err != nil {
+			return err
+		}
+	}
+	unit := s.Name + ".service"
+	if err := writeDefinition(out, dryRun, filepath.Join(systemdDir, unit), SystemdUnit(s)); err != nil {
+		return err
+	}
+	return runAll(out, dryRun,
+		[]string{"systemctl", "daemon-reload"},
+		[]string{"systemctl", "enable", "--now", unit},
+	)
+}
+
+// Uninstall stops and disables the named unit, then removes it
+// Complexity: O(1) plus the systemctl calls
+func Uninstall(name string, out io.Writer, dryRun bool) error {
+	if !validName(name) {
+		return fmt.Errorf("invalid service name %q", name)
+	}
+	if err := checkSystemd(); err != nil {
+		return err
+	}
+	unit := name + ".service"
+	path := filepath.Join(systemdDir, unit)
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("%w: %s not found", ErrNotInstalled, path)
+	}
+	if err := runAll(out, dryRun, []string{"systemctl", "disable", "--now", unit}); err != nil {
+		return err
+	}
+	if err := removeDefinition(out, dryRun, path); err != nil {
+		return err
+	}
+	return runAll(out, dryRun, []string{"systemctl", "daemon-reload"})
+}
+
+// checkSystemd fails when systemd is not the init system
+func checkSystemd() error {
+	if _, err := os.Stat(systemdRuntime); err != nil {
+		return fmt.Errorf("%w: systemd is not running", ErrUnsupported)
+	}
+	return nil
+}
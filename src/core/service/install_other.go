@@ -0,0 +1,15 @@
+//go:build !linux && !darwin && !windows
+
+package service
+
+import "io"
+
+// Install is unsupported here
+func Install(s *Spec, out io.Writer, dryRun bool) error {
+	return ErrUnsupported
+}
+
+// Uninstall is unsupported here
+func Uninstall(name string, out io.Writer, dryRun bool) error {
+	return ErrUnsupported
+}
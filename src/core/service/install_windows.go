@@ -0,0 +1,179 @@
+//go:build windows
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// Install creates an auto-start SCM service for s with restart-on-failure
+// recovery actions, then starts it. The Service Control Manager has no
+// working directory setting, so s.Args must carry it (daemon -dir).
+// With dryRun the service settings are printed to out and nothing is changed.
+// Complexity: O(|spec|) plus the SCM calls
+func Install(s *Spec, out io.Writer, dryRun bool) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	if s.User != "" {
+		return fmt.Errorf("%w: -user is not supported for Windows services (they run as LocalSystem)", ErrUnsupported)
+	}
+	if dryRun {
+		fmt.Fprintf(out, "# service %s\n", s.Name)
+		fmt.Fprintf(out, "DisplayName=%s\n", s.DisplayName)
+		fmt.Fprintf(out, "BinaryPath=%s\n", windows.ComposeCommandLine(append([]string{s.Executable}, s.Args...)))
+		fmt.Fprintln(out, "StartType=automatic")
+		fmt.Fprintf(out, "Recovery=restart after %s, reset after 24h\n", RestartDelay)
+		return nil
+	}
+
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the service manager: %w", err)
+	}
+	defer m.Disconnect()
+
+	if existing, err := m.OpenService(s.Name); err == nil {
+		existing.Close()
+		return fmt.Errorf("%w: %s", ErrInstalled, s.Name)
+	}
+	svcHandle, err := m.CreateService(s.Name, s.Executable, mgr.Config{
+		DisplayName: s.DisplayName,
+		StartType:   mgr.StartAutomatic,
+	}, s.Args...)
+	if err != nil {
+		return fmt.Errorf("failed to create service %s: %w", s.Name, err)
+	}
+	defer svcHandle.Close()
+	fmt.Fprintf(out, "created service %s\n", s.Name)
+
+	restart := mgr.RecoveryAction{Type: mgr.ServiceRestart, Delay: RestartDelay}
+	if err := svcHandle.SetRecoveryActions([]mgr.RecoveryAction{restart, restart, restart}, uint32((24 * time.Hour).Seconds())); err != nil {
+		return fmt.Errorf("failed to set recovery actions: %w", err)
+	}
+	// Also restart when the daemon reports a failing exit instead of crashing
+	if err := svcHandle.SetRecoveryActionsOnNonCrashFailures(true); err != nil {
+		return fmt.Errorf("failed to set recovery actions: %w", err)
+	}
+	if err := svcHandle.Start(); err != nil {
+		return fmt.Errorf("failed to start service %s: %w", s.Name, err)
+	}
+	fmt.Fprintf(out, "started service %s\n", s.Name)
+	return nil
+}
+
+// Uninstall stops the named service if it is running and deletes it
+// Complexity: O(1) plus the SCM calls and up to stopTimeout of waiting
+func Uninstall(name string, out io.Writer, dryRun bool) error {
+	if !validName(name) {
+		return fmt.Errorf("invalid service name %q", name)
+	}
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the service manager: %w", err)
+	}
+	defer m.Disconnect()
+
+	svcHandle, err := m.OpenService(name)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrNotInstalled, name)
+	}
+	defer svcHandle.Close()
+	fmt.Fprintf(out, "stop and delete service %s\n", name)
+	if dryRun {
+		return nil
+	}
+	if err := stopService(svcHandle); err != nil {
+		return err
+	}
+	if err := svcHandle.Delete(); err != nil {
+		return fmt.Errorf("failed to delete service %s: %w", name, err)
+	}
+	return nil
+}
+
+// stopTimeout bounds the wait for the daemon to flush and exit
+const stopTimeout = 2 * time.Minute
+
+// stopService asks a running service to stop and waits for it to do so
+func stopService(s *mgr.Service) error {
+	status, err := s.Control(svc.Stop)
+	if err != nil {
+		// Not running is the common case when a crash loop was throttled
+		if errors.Is(err, windows.ERROR_SERVICE_NOT_ACTIVE) {
+			return nil
+		}
+		return fmt.Errorf("failed to stop service: %w", err)
+	}
+	deadline := time.Now().Add(stopTimeout)
+	for status.State != svc.Stopped {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("service did not stop within %s", stopTimeout)
+		}
+		time.Sleep(500 * time.Millisecond)
+		if status, err = s.Query(); err != nil {
+			return fmt.Errorf("failed to query service: %w", err)
+		}
+	}
+	return nil
+}
+
+// Run runs fn under the Service Control Manager when the process was
+// started as a Windows service, cancelling fn's context on Stop or Shutdown.
+// It reports false (without calling fn) for interactive processes.
+// Complexity: O(1) plus fn
+func Run(fn func(ctx context.Context) error) (bool, error) {
+	isService, err := svc.IsWindowsService()
+	if err != nil || !isService {
+		return false, err
+	}
+	h := &handler{fn: fn}
+	// The name is ignored for SERVICE_WIN32_OWN_PROCESS services
+	if err := svc.Run(DefaultName, h); err != nil {
+		return true, err
+	}
+	return true, h.err
+}
+
+// handler adapts fn to svc.Handler
+type handler struct {
+	fn  func(ctx context.Context) error
+	err error
+}
+
+// Execute reports the service running, then serves control requests until fn returns
+func (h *handler) Execute(args []string, requests <-chan svc.ChangeRequest, status chan<- svc.Status) (bool, uint32) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- h.fn(ctx) }()
+
+	accepts := svc.AcceptStop | svc.AcceptShutdown
+	status <- svc.Status{State: svc.Running, Accepts: accepts}
+	for {
+		select {
+		case h.err = <-done:
+			if h.err != nil && !errors.Is(h.err, context.Canceled) {
+				// A non-zero exit code makes the SCM apply the recovery actions
+				return true, 1
+			}
+			return false, 0
+		case r := <-requests:
+			switch r.Cmd {
+			case svc.Interrogate:
+				status <- r.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				status <- svc.Status{State: svc.StopPending, WaitHint: uint32(stopTimeout.Milliseconds())}
+				cancel()
+			}
+		}
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+
+package service
+
+import "context"
+
+// Run reports false: only Windows services need a control handler, the
+// other service managers stop the daemon with SIGTERM
+func Run(fn func(ctx context.Context) error) (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,220 @@
+// Package service registers the agent's daemon mode with the OS service
+// manager: a systemd unit on Linux, a launchd daemon on macOS and a Service
+// Control Manager entry on Windows. Each restarts the daemon after a failure
+// and starts it at boot. Registration needs root or Administrator rights.
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultName is the service name used unless -name is given
+const DefaultName = "minibeast"
+
+// RestartDelay is how long the service manager waits before restarting a
+// failed daemon (a crash loop then costs at most one attempt per delay)
+const RestartDelay = 30 * time.Second
+
+// ErrUnsupported is returned on platforms without a supported service manager
+var ErrUnsupported = errors.New("no supported service manager on this platform")
+
+// ErrInstalled is returned by Install when the service already exists
+var ErrInstalled = errors.New("service is already installed")
+
+// ErrNotInstalled is returned by Uninstall when the service does not exist
+var ErrNotInstalled = errors.New("service is not installed")
+
+// Spec describes the daemon service to register
+type Spec struct {
+	Name        string   // Service name (e.g., "minibeast"); [A-Za-z0-9_-]
+	DisplayName string   // Human-readable name (Windows and the unit description)
+	Executable  string   // Absolute path of the agent binary
+	Args        []string // Arguments after Executable (e.g., daemon -dir ... -config ...)
+	WorkingDir  string   // Absolute agent root; relative config paths resolve against it
+	User        string   // Account to run as (systemd and launchd; empty = root)
+}
+
+// command runs a service manager command (replaced in tests)
+var command = func(name string, args ...string) error {
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// runAll runs each command in order, printing it to out first
+// With dryRun the commands are only printed.
+func runAll(out io.Writer, dryRun bool, cmds ...[]string) error {
+	for _, c := range cmds {
+		fmt.Fprintf(out, "$ %s\n", strings.Join(c, " "))
+		if dryRun {
+			continue
+		}
+		if err := command(c[0], c[1:]...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeDefinition writes a unit or plist, refusing to replace an existing one
+// With dryRun the file is printed instead.
+func writeDefinition(out io.Writer, dryRun bool, path, content string) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%w: %s exists", ErrInstalled, path)
+	}
+	if dryRun {
+		fmt.Fprintf(out, "# %s\n%s\n", path, content)
+		return nil
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	fmt.Fprintf(out, "wrote %s\n", path)
+	return nil
+}
+
+// removeDefinition deletes a unit or plist (printing only with dryRun)
+func removeDefinition(out io.Writer, dryRun bool, path string) error {
+	fmt.Fprintf(out, "remove %s\n", path)
+	if dryRun {
+		return nil
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+	return nil
+}
+
+// Validate checks the spec can be written into every service definition
+// Complexity: O(|spec|)
+func (s *Spec) Validate() error {
+	if !validName(s.Name) {
+		return fmt.Errorf("service name %q must be 1-64 characters of letters, digits, '-' or '_'", s.Name)
+	}
+	if !filepath.IsAbs(s.Executable) {
+		return fmt.Errorf("executable %q must be an absolute path", s.Executable)
+	}
+	if !filepath.IsAbs(s.WorkingDir) {
+		return fmt.Errorf("working directory %q must be an absolute path", s.WorkingDir)
+	}
+	for _, v := range append([]string{s.DisplayName, s.Executable, s.WorkingDir, s.User}, s.Args...) {
+		if strings.ContainsAny(v, "\x00\r\n") {
+			return fmt.Errorf("service setting %q must be a single line", v)
+		}
+	}
+	return nil
+}
+
+// validName reports whether name is usable as a unit, label and SCM name
+func validName(name string) bool {
+	if name == "" || len(name) > 64 {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// SystemdUnit renders the systemd unit for s
+// The daemon restarts on failure (not after a clean stop) and gets SIGTERM,
+// which it handles by finishing the current run's partial output.
+// Complexity: O(|spec|)
+func SystemdUnit(s *Spec) string {
+	var b strings.Builder
+	b.WriteString("[Unit]\n")
+	fmt.Fprintf(&b, "Description=%s\n", s.DisplayName)
+	b.WriteString("After=network-online.target\n")
+	b.WriteString("Wants=network-online.target\n\n")
+
+	b.WriteString("[Service]\n")
+	b.WriteString("Type=simple\n")
+	fmt.Fprintf(&b, "ExecStart=%s\n", systemdCommand(append([]string{s.Executable}, s.Args...)))
+	fmt.Fprintf(&b, "WorkingDirectory=%s\n", systemdQuote(s.WorkingDir))
+	if s.User != "" {
+		fmt.Fprintf(&b, "User=%s\n", s.User)
+	}
+	b.WriteString("Restart=on-failure\n")
+	fmt.Fprintf(&b, "RestartSec=%d\n", int(RestartDelay.Seconds()))
+	b.WriteString("KillSignal=SIGTERM\n")
+	b.WriteString("TimeoutStopSec=120\n\n")
+
+	b.WriteString("[Install]\n")
+	b.WriteString("WantedBy=multi-user.target\n")
+	return b.String()
+}
+
+// systemdCommand joins argv as an ExecStart command line
+func systemdCommand(argv []string) string {
+	quoted := make([]string, len(argv))
+	for i, arg := range argv {
+		quoted[i] = systemdQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// systemdQuote quotes arg for a unit file, escaping specifiers and variables
+func systemdQuote(arg string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "%", "%%", "$", "$$").Replace(arg)
+	if escaped == arg && arg != "" && !strings.ContainsAny(arg, " \t'") {
+		return arg
+	}
+	return `"` + escaped + `"`
+}
+
+// LaunchdLabel is the launchd job label for a service name
+func LaunchdLabel(name string) string {
+	return "com.minibeast." + name
+}
+
+// LaunchdPlist renders the launchd daemon property list for s
+// KeepAlive restarts the daemon only after an unsuccessful exit, at most
+// once per RestartDelay; logs go to the working directory's out/ folder.
+// Complexity: O(|spec|)
+func LaunchdPlist(s *Spec) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	b.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+	b.WriteString(`<plist version="1.0">` + "\n<dict>\n")
+	plistString(&b, "Label", LaunchdLabel(s.Name))
+	b.WriteString("\t<key>ProgramArguments</key>\n\t<array>\n")
+	for _, arg := range append([]string{s.Executable}, s.Args...) {
+		fmt.Fprintf(&b, "\t\t<string>%s</string>\n", xmlEscape(arg))
+	}
+	b.WriteString("\t</array>\n")
+	plistString(&b, "WorkingDirectory", s.WorkingDir)
+	if s.User != "" {
+		plistString(&b, "UserName", s.User)
+	}
+	b.WriteString("\t<key>RunAtLoad</key>\n\t<true/>\n")
+	b.WriteString("\t<key>KeepAlive</key>\n\t<dict>\n\t\t<key>SuccessfulExit</key>\n\t\t<false/>\n\t</dict>\n")
+	fmt.Fprintf(&b, "\t<key>ThrottleInterval</key>\n\t<integer>%d</integer>\n", int(RestartDelay.Seconds()))
+	b.WriteString("\t<key>ExitTimeOut</key>\n\t<integer>120</integer>\n")
+	plistString(&b, "StandardOutPath", filepath.Join(s.WorkingDir, "out", s.Name+".log"))
+	plistString(&b, "StandardErrorPath", filepath.Join(s.WorkingDir, "out", s.Name+".log"))
+	b.WriteString("</dict>\n</plist>\n")
+	return b.String()
+}
+
+// plistString writes one key/string pair
+func plistString(b *strings.Builder, key, value string) {
+	fmt.Fprintf(b, "\t<key>%s</key>\n\t<string>%s</string>\n", key, xmlEscape(value))
+}
+
+// xmlEscape escapes text for an XML element
+func xmlEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;", "'", "&apos;").Replace(s)
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+// testSpec returns a valid spec with a path needing quoting
+func testSpec() *Spec {
+	return &Spec{
+		Name:        "minibeast",
+		DisplayName: "MiniBeast agent",
+		Executable:  "/opt/mini beast/minibeast",
+		Args:        []string{"daemon", "-dir", "/opt/mini beast", "-config", "config/default.yaml"},
+		WorkingDir:  "/opt/mini beast",
+	}
+}
+
+// TestSpec_Validate verifies names, paths and line breaks are checked
+func TestSpec_Validate(t *testing.T) {
+	if err := testSpec().Validate(); err != nil {
+		t.Fatalf("Validate() failed on a valid spec: %v", err)
+	}
+
+	tests := map[string]func(s *Spec){
+		"empty name":       func(s *Spec) { s.Name = "" },
+		"name with dot":    func(s *Spec) { s.Name = "mini.beast" },
+		"name with slash":  func(s *Spec) { s.Name = "../evil" },
+		"relative binary":  func(s *Spec) { s.Executable = "minibeast" },
+		"relative workdir": func(s *Spec) { s.WorkingDir = "." },
+		"newline in arg":   func(s *Spec) { s.Args = append(s.Args, "x\nExecStartPre=/bin/sh") },
+		"newline in user":  func(s *Spec) { s.User = "root\n" },
+		"overlong name":    func(s *Spec) { s.Name = strings.Repeat("a", 65) },
+	}
+	for name, mutate := range tests {
+		s := testSpec()
+		mutate(s)
+		if err := s.Validate(); err == nil {
+			t.Errorf("%s: Validate() accepted %+v", name, s)
+		}
+	}
+}
+
+// TestSystemdUnit verifies restart policy and argument quoting
+func TestSystemdUnit(t *testing.T) {
+	s := testSpec()
+	s.User = "minibeast"
+	s.Args = append(s.Args, "100%", "$HOME")
+	unit := SystemdUnit(s)
+
+	for _, want := range []string{
+		`ExecStart="/opt/mini beast/minibeast" daemon -dir "/opt/mini beast" -config config/default.yaml "100%%" "$$HOME"` + "\n",
+		`WorkingDirectory="/opt/mini beast"` + "\n",
+		"User=minibeast\n",
+		"Restart=on-failure\n",
+		"RestartSec=30\n",
+		"WantedBy=multi-user.target\n",
+	} {
+		if !strings.Contains(unit, want) {
+			t.Errorf("unit lacks %q:\n%s", want, unit)
+		}
+	}
+}
+
+// TestLaunchdPlist verifies the label, escaping and restart policy
+func TestLaunchdPlist(t *testing.T) {
+	s := testSpec()
+	s.Args = append(s.Args, "a<b&c")
+	plist := LaunchdPlist(s)
+
+	for _, want := range []string{
+		"<string>com.minibeast.minibeast</string>",
+		"<string>/opt/mini beast/minibeast</string>",
+		"<string>a&lt;b&amp;c</string>",
+		"<key>SuccessfulExit</key>\n\t\t<false/>",
+		"<key>ThrottleInterval</key>\n\t<integer>30</integer>",
+		"<string>/opt/mini beast/out/minibeast.log</string>",
+	} {
+		if !strings.Contains(plist, want) {
+			t.Errorf("plist lacks %q:\n%s", want, plist)
+		}
+	}
+	if strings.Contains(plist, "UserName") {
+		t.Error("plist sets UserName without a user")
+	}
+}
+
+// TestRunAll_DryRun verifies dry runs print commands without running them
+func TestRunAll_DryRun(t *testing.T) {
+	orig := command
+	defer func() { command = orig }()
+	ran := 0
+	command = func(name string, args ...string) error {
+		ran++
+		return nil
+	}
+
+	var out strings.Builder
+	if err := runAll(&out, true, []string{"systemctl", "daemon-reload"}); err != nil {
+		t.Fatalf("runAll() failed: %v", err)
+	}
+	if ran != 0 || out.String() != "$ systemctl daemon-reload\n" {
+		t.Errorf("dry run ran %d commands and printed %q", ran, out.String())
+	}
+
+	if err := runAll(&out, false, []string{"systemctl", "daemon-reload"}, []string{"systemctl", "enable", "--now", "x.service"}); err != nil {
+		t.Fatalf("runAll() failed: %v", err)
+	}
+	if ran != 2 {
+		t.Errorf("ran %d commands, want 2", ran)
+	}
+}
@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// LedgerEntry is one compact runs.ndjson record
+type LedgerEntry struct {
+	RunID        string    `json:"run_id"`
+	Timestamp    time.Time `json:"timestamp"`
+	Hostname     string    `json:"hostname"`
+	HardwareUUID string    `json:"hardware_uuid"`
+	FactsSHA256  string    `json:"facts_sha256"`            // SHA-256 of facts.json as written
+	ReportSHA256 string    `json:"report_sha256,omitempty"` // SHA-256 of report.json (empty without a report)
+	CollectionMs int64     `json:"collection_ms"`
+	InferenceMs  int64     `json:"inference_ms"`
+	TotalMs      int64     `json:"total_ms"`
+
+	// Session metadata (from facts; empty when none was configured)
+	Engagement string            `json:"engagement,omitempty"`
+	Operator   string            `json:"operator,omitempty"`
+	Tags       map[string]string `json:"tags,omitempty"`
+}
+
+// NewLedgerEntry derives a ledger record from a run and its phase durations
+// Complexity: O(|facts| + |report|)
+func NewLedgerEntry(run *Run, inference, total time.Duration) (*LedgerEntry, error) {
+	if run == nil || run.Facts == nil {
+		return nil, fmt.Errorf("run facts cannot be nil")
+	}
+
+	factsJSON, reportJSON, err := run.encode()
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &LedgerEntry{
+		RunID:        run.ID,
+		Timestamp:    run.Facts.Timestamp.UTC(),
+		Hostname:     run.Facts.Hostname,
+		HardwareUUID: run.Facts.HardwareUUID,
+		FactsSHA256:  hexSHA256(factsJSON),
+		CollectionMs: run.Facts.CollectionDurationMs,
+		InferenceMs:  inference.Milliseconds(),
+		TotalMs:      total.Milliseconds(),
+	}
+	if reportJSON != nil {
+		entry.ReportSHA256 = hexSHA256(reportJSON)
+	}
+	if s := run.Facts.Session; s != nil {
+		entry.Engagement, entry.Operator, entry.Tags = s.Engagement, s.Operator, s.Tags
+	}
+	return entry, nil
+}
+
+// Ledger is an append-only NDJSON index of every run on the stick
+// Mathematical guarantee: Existing lines are never rewritten; each Append
+// adds exactly one complete line (single write + fsync)
+type Ledger struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewLedger returns a ledger backed by path (created on first append)
+// Complexity: O(1)
+func NewLedger(path string) *Ledger {
+	return &Ledger{path: path}
+}
+
+// Path returns the ledger file path
+func (l *Ledger) Path() string {
+	return l.path
+}
+
+// Append writes one record as a single compact JSON line
+// Complexity: O(|entry|)
+func (l *Ledger) Append(entry *LedgerEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ledger entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("failed to create ledger directory: %w", err)
+	}
+	file, err := os.OpenFile(l.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open ledger: %w", err)
+	}
+
+	if _, err := file.Write(line); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to append ledger entry: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to sync ledger: %w", err)
+	}
+	return file.Close()
+}
+
+// Entries reads every record in append order
+// A torn final line (stick pulled mid-write) is skipped; corruption
+// anywhere else is reported. A missing ledger yields no entries.
+// Complexity: O(n) where n = ledger size
+func (l *Ledger) Entries() ([]LedgerEntry, error) {
+	file, err := os.Open(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []LedgerEntry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ledger: %w", err)
+	}
+	defer file.Close()
+
+	entries := []LedgerEntry{}
+	var pending error
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		if pending != nil {
+			return nil, pending
+		}
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry LedgerEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			pending = fmt.Errorf("ledger line %d: %w", lineNo, err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ledger: %w", err)
+	}
+	return entries, nil
+}
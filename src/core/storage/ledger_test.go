@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+)
+
+func TestLedger_AppendAndRead(t *testing.T) {
+	ledger := NewLedger(filepath.Join(t.TempDir(), "out", "runs.ndjson"))
+	ts := time.Date(2025, 11, 9, 12, 0, 0, 0, time.UTC)
+
+	withReport := testRun("run-1", "alpha", ts, "MB-OS-EOL")
+	withReport.Facts.HardwareUUID = "uuid-1"
+	withReport.Facts.CollectionDurationMs = 850
+	withReport.Facts.Session = &collection.Session{Engagement: "ACME-2025-014", Operator: "JD", Tags: map[string]string{"site": "berlin"}}
+	factsOnly := testRun("run-2", "beta", ts.Add(time.Hour))
+	factsOnly.Report = nil
+
+	for _, tc := range []struct {
+		run              *Run
+		inference, total time.Duration
+	}{
+		{withReport, 1200 * time.Millisecond, 2500 * time.Millisecond},
+		{factsOnly, 0, 900 * time.Millisecond},
+	} {
+		entry, err := NewLedgerEntry(tc.run, tc.inference, tc.total)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ledger.Append(entry); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(ledger.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"); len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+
+	entries, err := ledger.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	first := entries[0]
+	if first.RunID != "run-1" || first.HardwareUUID != "uuid-1" || first.CollectionMs != 850 ||
+		first.InferenceMs != 1200 || first.TotalMs != 2500 || !first.Timestamp.Equal(ts) {
+		t.Errorf("first entry = %+v", first)
+	}
+	if len(first.FactsSHA256) != 64 || len(first.ReportSHA256) != 64 {
+		t.Errorf("hashes = %q / %q", first.FactsSHA256, first.ReportSHA256)
+	}
+	if first.Engagement != "ACME-2025-014" || first.Operator != "JD" || first.Tags["site"] != "berlin" {
+		t.Errorf("first entry session = %+v", first)
+	}
+	if entries[1].ReportSHA256 != "" {
+		t.Errorf("facts-only run should have no report hash, got %q", entries[1].ReportSHA256)
+	}
+}
+
+func TestLedger_FactsHashMatchesStore(t *testing.T) {
+	run := testRun("run-1", "alpha", time.Now().UTC())
+	entry, err := NewLedgerEntry(run, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := openTestStore(t)
+	if err := store.SaveRun(context.Background(), run); err != nil {
+		t.Fatal(err)
+	}
+	runs, err := store.RunsByHostname(context.Background(), "alpha")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runs[0].FactsSHA256 != entry.FactsSHA256 {
+		t.Errorf("ledger hash %s != store hash %s", entry.FactsSHA256, runs[0].FactsSHA256)
+	}
+}
+
+func TestLedger_TornLastLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runs.ndjson")
+	content := `{"run_id":"run-1","hostname":"alpha"}` + "\n" + `{"run_id":"run-2","host`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := NewLedger(path).Entries()
+	if err != nil {
+		t.Fatalf("torn last line should be skipped: %v", err)
+	}
+	if len(entries) != 1 || entries[0].RunID != "run-1" {
+		t.Errorf("entries = %+v", entries)
+	}
+}
+
+func TestLedger_CorruptMiddleLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runs.ndjson")
+	content := `{"run_id":"run-1"}` + "\nnot json\n" + `{"run_id":"run-3"}` + "\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewLedger(path).Entries(); err == nil {
+		t.Error("expected error for corrupt middle line")
+	}
+}
+
+func TestLedger_Missing(t *testing.T) {
+	entries, err := NewLedger(filepath.Join(t.TempDir(), "runs.ndjson")).Entries()
+	if err != nil || len(entries) != 0 {
+		t.Errorf("missing ledger = %v, %v", entries, err)
+	}
+}
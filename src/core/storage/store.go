@@ -0,0 +1,321 @@
+// Package storage persists run history in a SQLite database on the USB stick
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/report"
+
+	_ "modernc.org/sqlite" // Pure Go driver (no cgo, cross-compiles for every stick target)
+)
+
+// ErrNotFound is returned when a run ID is not in the store
+var ErrNotFound = errors.New("run not found")
+
+// schemaVersion is stored in PRAGMA user_version and bumped with every migration
+const schemaVersion = 1
+
+// schema creates the version 1 tables
+// Timestamps are Unix nanoseconds (UTC) so range queries use the index directly.
+const schema = `
+CREATE TABLE IF NOT EXISTS runs (
+	run_id            TEXT PRIMARY KEY,
+	hostname          TEXT NOT NULL,
+	timestamp         INTEGER NOT NULL,
+	collector_version TEXT NOT NULL,
+	facts_sha256      TEXT NOT NULL,
+	facts_json        BLOB NOT NULL,
+	report_json       BLOB
+);
+CREATE INDEX IF NOT EXISTS runs_hostname ON runs(hostname, timestamp);
+CREATE INDEX IF NOT EXISTS runs_timestamp ON runs(timestamp);
+CREATE TABLE IF NOT EXISTS findings (
+	run_id     TEXT NOT NULL REFERENCES runs(run_id) ON DELETE CASCADE,
+	position   INTEGER NOT NULL,
+	finding_id TEXT NOT NULL,
+	severity   TEXT NOT NULL,
+	text       TEXT NOT NULL,
+	PRIMARY KEY (run_id, position)
+);
+CREATE INDEX IF NOT EXISTS findings_id ON findings(finding_id);
+`
+
+// Run is a single agent run to persist
+type Run struct {
+	ID     string            // Run identifier (unique per store)
+	Facts  *collection.Facts // Collected facts (required)
+	Report *report.Report    // Structured report (nil when the LLM phase did not run)
+}
+
+// encode returns facts.json and report.json bytes as written to the stick
+// reportJSON is nil when the run has no report
+// Complexity: O(|facts| + |report|)
+func (r *Run) encode() (factsJSON, reportJSON []byte, err error) {
+	factsJSON, err = r.Facts.CanonicalJSON()
+	if err != nil {
+		return nil, nil, err
+	}
+	if r.Report != nil {
+		if reportJSON, err = r.Report.RenderJSON(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return factsJSON, reportJSON, nil
+}
+
+// hexSHA256 returns the lowercase hex SHA-256 of data
+func hexSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// RunSummary is the metadata row returned by the query helpers
+type RunSummary struct {
+	ID               string
+	Hostname         string
+	Timestamp        time.Time
+	CollectorVersion string
+	FactsSHA256      string   // SHA-256 of facts.json as written
+	FindingIDs       []string // Rule-engine findings in report order
+}
+
+// Finding is a rule-engine backed risk recorded for a run
+type Finding struct {
+	RunID     string
+	FindingID string
+	Severity  string
+	Text      string
+}
+
+// Store is a run history database
+// Safe for concurrent use (database/sql pools connections)
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens (or creates) the history database at path and migrates it
+// Complexity: O(1)
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	dsn := "file:" + path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history database: %w", err)
+	}
+
+	s := &Store{db: db}
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// migrate brings the schema up to schemaVersion
+func (s *Store) migrate() error {
+	var version int
+	if err := s.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if version > schemaVersion {
+		return fmt.Errorf("history database schema v%d is newer than supported v%d", version, schemaVersion)
+	}
+	if version == schemaVersion {
+		return nil
+	}
+
+	if _, err := s.db.Exec(schema); err != nil {
+		return fmt.Errorf("failed to create history schema: %w", err)
+	}
+	if _, err := s.db.Exec(fmt.Sprintf("PRAGMA user_version = %d", schemaVersion)); err != nil {
+		return fmt.Errorf("failed to set schema version: %w", err)
+	}
+	return nil
+}
+
+// Close releases the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// SaveRun persists a run with its facts, report and findings in one transaction
+// Complexity: O(|facts| + |risks|)
+func (s *Store) SaveRun(ctx context.Context, run *Run) error {
+	if run == nil || run.Facts == nil {
+		return fmt.Errorf("run facts cannot be nil")
+	}
+	if run.ID == "" {
+		return fmt.Errorf("run ID cannot be empty")
+	}
+
+	factsJSON, reportJSON, err := run.encode()
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO runs (run_id, hostname, timestamp, collector_version, facts_sha256, facts_json, report_json)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		run.ID, run.Facts.Hostname, run.Facts.Timestamp.UTC().UnixNano(), run.Facts.CollectorVersion,
+		hexSHA256(factsJSON), factsJSON, reportJSON)
+	if err != nil {
+		return fmt.Errorf("failed to insert run %s: %w", run.ID, err)
+	}
+
+	if run.Report != nil {
+		for i, risk := range run.Report.Risks {
+			if risk.FindingID == "" {
+				continue
+			}
+			_, err = tx.ExecContext(ctx,
+				`INSERT INTO findings (run_id, position, finding_id, severity, text) VALUES (?, ?, ?, ?, ?)`,
+				run.ID, i, risk.FindingID, risk.Severity.String(), risk.Text)
+			if err != nil {
+				return fmt.Errorf("failed to insert finding %s: %w", risk.FindingID, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit run %s: %w", run.ID, err)
+	}
+	return nil
+}
+
+// Facts returns the stored facts of a run
+// Complexity: O(|facts|)
+func (s *Store) Facts(ctx context.Context, runID string) (*collection.Facts, error) {
+	var data []byte
+	err := s.db.QueryRowContext(ctx, `SELECT facts_json FROM runs WHERE run_id = ?`, runID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load facts for %s: %w", runID, err)
+	}
+
+	var facts collection.Facts
+	if err := json.Unmarshal(data, &facts); err != nil {
+		return nil, fmt.Errorf("failed to decode facts for %s: %w", runID, err)
+	}
+	return &facts, nil
+}
+
+// Report returns the stored report of a run (nil when none was produced)
+// Complexity: O(|report|)
+func (s *Store) Report(ctx context.Context, runID string) (*report.Report, error) {
+	var data []byte
+	err := s.db.QueryRowContext(ctx, `SELECT report_json FROM runs WHERE run_id = ?`, runID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load report for %s: %w", runID, err)
+	}
+	if data == nil {
+		return nil, nil
+	}
+
+	var rpt report.Report
+	if err := json.Unmarshal(data, &rpt); err != nil {
+		return nil, fmt.Errorf("failed to decode report for %s: %w", runID, err)
+	}
+	return &rpt, nil
+}
+
+// Findings returns the rule-engine findings of a run in report order
+// Complexity: O(|findings|)
+func (s *Store) Findings(ctx context.Context, runID string) ([]Finding, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT run_id, finding_id, severity, text FROM findings WHERE run_id = ? ORDER BY position`, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query findings: %w", err)
+	}
+	defer rows.Close()
+
+	var result []Finding
+	for rows.Next() {
+		var f Finding
+		if err := rows.Scan(&f.RunID, &f.FindingID, &f.Severity, &f.Text); err != nil {
+			return nil, fmt.Errorf("failed to scan finding: %w", err)
+		}
+		result = append(result, f)
+	}
+	return result, rows.Err()
+}
+
+// RunsByHostname returns every run of a machine, oldest first
+// Complexity: O(log n + k) via the hostname index
+func (s *Store) RunsByHostname(ctx context.Context, hostname string) ([]RunSummary, error) {
+	return s.queryRuns(ctx, `WHERE r.hostname = ?`, hostname)
+}
+
+// RunsBetween returns runs with from <= timestamp < to, oldest first
+// Complexity: O(log n + k) via the timestamp index
+func (s *Store) RunsBetween(ctx context.Context, from, to time.Time) ([]RunSummary, error) {
+	return s.queryRuns(ctx, `WHERE r.timestamp >= ? AND r.timestamp < ?`,
+		from.UTC().UnixNano(), to.UTC().UnixNano())
+}
+
+// RunsWithFinding returns runs that raised a finding (e.g., "MB-OS-EOL"), oldest first
+// Complexity: O(log n + k) via the finding index
+func (s *Store) RunsWithFinding(ctx context.Context, findingID string) ([]RunSummary, error) {
+	return s.queryRuns(ctx,
+		`WHERE r.run_id IN (SELECT run_id FROM findings WHERE finding_id = ?)`, findingID)
+}
+
+// queryRuns selects run summaries matching where, with their finding IDs
+func (s *Store) queryRuns(ctx context.Context, where string, args ...any) ([]RunSummary, error) {
+	query := `SELECT r.run_id, r.hostname, r.timestamp, r.collector_version, r.facts_sha256,
+	                 COALESCE((SELECT group_concat(finding_id, ',') FROM
+	                     (SELECT finding_id FROM findings f WHERE f.run_id = r.run_id ORDER BY position)), '')
+	          FROM runs r ` + where + ` ORDER BY r.timestamp, r.run_id`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query runs: %w", err)
+	}
+	defer rows.Close()
+
+	var result []RunSummary
+	for rows.Next() {
+		var rs RunSummary
+		var ts int64
+		var ids string
+		if err := rows.Scan(&rs.ID, &rs.Hostname, &ts, &rs.CollectorVersion, &rs.FactsSHA256, &ids); err != nil {
+			return nil, fmt.Errorf("failed to scan run: %w", err)
+		}
+		rs.Timestamp = time.Unix(0, ts).UTC()
+		rs.FindingIDs = splitIDs(ids)
+		result = append(result, rs)
+	}
+	return result, rows.Err()
+}
+
+// splitIDs splits a group_concat result (finding IDs never contain commas)
+func splitIDs(ids string) []string {
+	if ids == "" {
+		return []string{}
+	}
+	return strings.Split(ids, ",")
+}
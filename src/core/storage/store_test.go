@@ -0,0 +1,162 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/report"
+)
+
+// testRun builds a run for hostname at ts with the given finding IDs
+func testRun(id, hostname string, ts time.Time, findingIDs ...string) *Run {
+	rpt := &report.Report{Summary: []string{"ok"}}
+	for _, fid := range findingIDs {
+		rpt.Risks = append(rpt.Risks, report.Risk{Text: fid + " risk", Severity: report.SeverityHigh, FindingID: fid})
+	}
+	rpt.Risks = append(rpt.Risks, report.Risk{Text: "model-only risk", Severity: report.SeverityLow})
+	return &Run{
+		ID: id,
+		Facts: &collection.Facts{
+			Timestamp:        ts,
+			Hostname:         hostname,
+			CollectorVersion: "1.0.0",
+			OSName:           "Linux",
+		},
+		Report: rpt,
+	}
+}
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	store, err := Open(filepath.Join(t.TempDir(), "out", "history.db"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestStore_SaveAndQuery(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	day := time.Date(2025, 11, 9, 0, 0, 0, 0, time.UTC)
+
+	runs := []*Run{
+		testRun("run-1", "alpha", day.Add(1*time.Hour), "MB-OS-EOL", "MB-AV-MISSING"),
+		testRun("run-2", "beta", day.Add(2*time.Hour), "MB-AV-MISSING"),
+		testRun("run-3", "alpha", day.Add(26*time.Hour)),
+	}
+	for _, run := range runs {
+		if err := store.SaveRun(ctx, run); err != nil {
+			t.Fatalf("SaveRun %s failed: %v", run.ID, err)
+		}
+	}
+
+	byHost, err := store.RunsByHostname(ctx, "alpha")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(byHost) != 2 || byHost[0].ID != "run-1" || byHost[1].ID != "run-3" {
+		t.Fatalf("RunsByHostname = %+v", byHost)
+	}
+	if got := byHost[0].FindingIDs; len(got) != 2 || got[0] != "MB-OS-EOL" || got[1] != "MB-AV-MISSING" {
+		t.Errorf("FindingIDs = %v", got)
+	}
+	if len(byHost[0].FactsSHA256) != 64 {
+		t.Errorf("FactsSHA256 = %q", byHost[0].FactsSHA256)
+	}
+	if !byHost[0].Timestamp.Equal(day.Add(1 * time.Hour)) {
+		t.Errorf("Timestamp = %v", byHost[0].Timestamp)
+	}
+
+	byDate, err := store.RunsBetween(ctx, day, day.Add(24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(byDate) != 2 || byDate[0].ID != "run-1" || byDate[1].ID != "run-2" {
+		t.Fatalf("RunsBetween = %+v", byDate)
+	}
+
+	byFinding, err := store.RunsWithFinding(ctx, "MB-AV-MISSING")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(byFinding) != 2 || byFinding[0].ID != "run-1" || byFinding[1].ID != "run-2" {
+		t.Fatalf("RunsWithFinding = %+v", byFinding)
+	}
+
+	findings, err := store.Findings(ctx, "run-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 2 || findings[0].Severity != "HIGH" || findings[1].FindingID != "MB-AV-MISSING" {
+		t.Errorf("Findings = %+v", findings)
+	}
+}
+
+func TestStore_LoadFactsAndReport(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	run := testRun("run-1", "alpha", time.Date(2025, 11, 9, 12, 0, 0, 0, time.UTC), "MB-OS-EOL")
+	if err := store.SaveRun(ctx, run); err != nil {
+		t.Fatal(err)
+	}
+
+	facts, err := store.Facts(ctx, "run-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if facts.Hostname != "alpha" || facts.OSName != "Linux" {
+		t.Errorf("Facts = %+v", facts)
+	}
+
+	rpt, err := store.Report(ctx, "run-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rpt.Risks) != 2 || rpt.Risks[0].Severity != report.SeverityHigh {
+		t.Errorf("Report risks = %+v", rpt.Risks)
+	}
+
+	if _, err := store.Facts(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestStore_DuplicateRunRejected(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	run := testRun("run-1", "alpha", time.Now().UTC())
+	if err := store.SaveRun(ctx, run); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveRun(ctx, run); err == nil {
+		t.Error("expected duplicate run ID to be rejected")
+	}
+}
+
+func TestOpen_Reopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	store, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveRun(context.Background(), testRun("run-1", "alpha", time.Now().UTC())); err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	store, err = Open(path)
+	if err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	defer store.Close()
+	runs, err := store.RunsByHostname(context.Background(), "alpha")
+	if err != nil || len(runs) != 1 {
+		t.Fatalf("runs after reopen = %v, %v", runs, err)
+	}
+}
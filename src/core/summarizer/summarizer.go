@@ -2,162 +2,404 @@ package summarizer
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/minibeast/usb-agent/src/core/collection"
 	"github.com/minibeast/usb-agent/src/core/config"
 	"github.com/minibeast/usb-agent/src/core/inference"
+	"github.com/minibeast/usb-agent/src/core/logging"
+	"github.com/minibeast/usb-agent/src/core/progress"
+	"github.com/minibeast/usb-agent/src/core/remediation"
+	"github.com/minibeast/usb-agent/src/core/report"
+	"github.com/minibeast/usb-agent/src/core/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// Engine is the inference backend used by the Summarizer
+type Engine = inference.Backend
+
+// RuleSource contributes findings evaluated directly against Facts
+// Implemented by plugin.Rules.
+type RuleSource interface {
+	Findings(ctx context.Context, facts *collection.Facts) ([]inference.Finding, error)
+}
+
+// ErrModelUnavailable wraps a failure to load the model (missing GGUF file,
+// unreachable inference server)
+var ErrModelUnavailable = errors.New("model load failed")
+
+// Seeder is implemented by engines that accept a per-Facts deterministic seed
+type Seeder interface {
+	SetSeed(seed int64)
+}
+
+// SeededGenerator is implemented by engines that take the seed per call
+// Implemented by *inference.Engine, *inference.HTTPBackend and
+// *inference.FakeEngine; concurrent
+// reports then never share sampling state.
+type SeededGenerator interface {
+	GenerateSeeded(ctx context.Context, prompt string, seed int64) (*inference.InferenceResult, error)
+}
+
+// ConstrainedGenerator is implemented by engines that can restrict sampling
+// to a GBNF grammar (llm.grammar)
+// Implemented by *inference.Engine and *inference.FakeEngine.
+type ConstrainedGenerator interface {
+	GenerateConstrained(ctx context.Context, prompt, grammar string, seed int64) (*inference.InferenceResult, error)
+}
+
+// StreamingGenerator is implemented by engines that pass text to a callback
+// as it is sampled, optionally under a grammar ("" = none)
+// Implemented by *inference.Engine and *inference.FakeEngine.
+type StreamingGenerator interface {
+	GenerateConstrainedStream(ctx context.Context, prompt, grammar string, seed int64, fn inference.TokenFunc) (*inference.InferenceResult, error)
+}
+
+// SeededStreamGenerator is implemented by engines that stream text without
+// grammar support
+// Implemented by *inference.HTTPBackend.
+type SeededStreamGenerator interface {
+	GenerateSeededStream(ctx context.Context, prompt string, seed int64, fn inference.TokenFunc) (*inference.InferenceResult, error)
+}
+
 // Summarizer orchestrates LLM-based system analysis
 // Mathematical guarantee: Deterministic output for same Facts + config
 type Summarizer struct {
-	engine        *inference.Engine
+	engine        Engine
 	promptBuilder *inference.PromptBuilder
 	parser        *inference.Parser
+	remediation   *remediation.KnowledgeBase
+	rules         []RuleSource
 	config        *config.Config
+
+	seedMu sync.Mutex // Pairs SetSeed with Generate for Seeder-only engines
 }
 
-// NewSummarizer creates a new summarizer instance
+// NewSummarizer creates a new summarizer instance around an injected engine
 // Complexity: O(1) - lazy initialization
-func NewSummarizer(cfg *config.Config) (*Summarizer, error) {
+func NewSummarizer(cfg *config.Config, engine Engine) (*Summarizer, error) {
 	if cfg == nil {
 		return nil, fmt.Errorf("config cannot be nil")
 	}
-
-	// Create inference config from main config
-	inferenceConfig := &inference.InferenceConfig{
-		MaxTokens:   cfg.LLM.MaxTokens,
-		Temperature: cfg.LLM.Temperature,
-		ModelPath:   cfg.LLM.ModelPath,
+	if engine == nil {
+		return nil, fmt.Errorf("engine cannot be nil")
 	}
 
-	// Create engine (lazy loading)
-	engine, err := inference.NewEngine(inferenceConfig)
+	kb, err := remediation.LoadOrDefault(cfg.Output.RemediationPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create engine: %w", err)
+		return nil, fmt.Errorf("output.remediation_path: %w", err)
 	}
 
 	return &Summarizer{
 		engine:        engine,
 		promptBuilder: inference.NewPromptBuilder(),
 		parser:        inference.NewParser(),
+		remediation:   kb,
 		config:        cfg,
 	}, nil
 }
 
+// NewEngine creates the inference backend selected by llm.backend
+// Complexity: O(1) - lazy loading
+func NewEngine(cfg *config.Config) (Engine, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+
+	if cfg.LLM.Backend == config.LLMBackendOpenAI {
+		rc := cfg.LLM.Remote
+		backend, err := inference.NewHTTPBackend(&inference.HTTPConfig{
+			URL:         rc.URL,
+			Model:       rc.Model,
+			APIKey:      rc.APIKey,
+			CAFile:      rc.CAFile,
+			Timeout:     time.Duration(rc.TimeoutMs) * time.Millisecond,
+			MaxTokens:   cfg.LLM.MaxTokens,
+			Temperature: cfg.LLM.Temperature,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create engine: %w", err)
+		}
+		return backend, nil
+	}
+
+	engine, err := inference.NewEngine(&inference.InferenceConfig{
+		MaxTokens:   cfg.LLM.MaxTokens,
+		Temperature: cfg.LLM.Temperature,
+		ModelPath:   cfg.LLM.ModelPath,
+		Threads:     cfg.Resources.InferenceThreads(runtime.NumCPU()),
+		Sessions:    cfg.LLM.Sessions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create engine: %w", err)
+	}
+	return engine, nil
+}
+
+// AddRules registers rule sources whose findings join every report's risks
+// Complexity: O(1)
+func (s *Summarizer) AddRules(sources ...RuleSource) {
+	s.rules = append(s.rules, sources...)
+}
+
 // Summarize generates a human-readable report from Facts
 // Mathematical complexity: O(m) where m = maxTokens
 // Latency: L₂ = L_load + L_inference + L_parse
 func (s *Summarizer) Summarize(ctx context.Context, facts *collection.Facts) (string, error) {
+	rpt, err := s.BuildReport(ctx, facts)
+	if err != nil {
+		return "", err
+	}
+	return rpt.RenderText(), nil
+}
+
+// BuildReport runs the LLM phase and returns the structured report
+// The result is already fitted to output.max_report_bytes and can be
+// rendered as text (RenderText) or report.json (RenderJSON). Safe for
+// concurrent use; with llm.sessions > 1 reports generate in parallel.
+// Complexity: O(m) where m = maxTokens
+func (s *Summarizer) BuildReport(ctx context.Context, facts *collection.Facts) (*report.Report, error) {
 	if facts == nil {
-		return "", fmt.Errorf("facts cannot be nil")
+		return nil, fmt.Errorf("facts cannot be nil")
 	}
 
-	// Update inference config with facts metadata
-	s.engine = s.updateEngineWithFacts(facts)
+	ctx, span := telemetry.Tracer().Start(ctx, "summarize")
+	defer span.End()
+	ctx = logging.WithPhase(ctx, "summarize")
+	log := logging.From(ctx)
 
 	// Step 1: Load model (lazy, cached after first call)
-	if err := s.engine.Load(ctx); err != nil {
-		return "", fmt.Errorf("model load failed: %w", err)
+	loadCtx, loadSpan := telemetry.Tracer().Start(ctx, "inference.load")
+	loadStep := progress.Start(ctx, "inference.load")
+	err := s.engine.Load(loadCtx)
+	loadStep.End(err)
+	endSpan(loadSpan, err)
+	if err != nil {
+		return nil, fail(span, fmt.Errorf("%w: %w", ErrModelUnavailable, err))
 	}
 
 	// Step 2: Build deterministic prompt
+	_, promptSpan := telemetry.Tracer().Start(ctx, "inference.prompt")
 	prompt, err := s.promptBuilder.BuildPrompt(facts)
 	if err != nil {
-		return "", fmt.Errorf("prompt build failed: %w", err)
+		endSpan(promptSpan, err)
+		return nil, fail(span, fmt.Errorf("prompt build failed: %w", err))
 	}
 
 	// Step 3: Validate token count
 	if err := s.promptBuilder.ValidateTokenCount(prompt, s.config.LLM.MaxTokens); err != nil {
 		// Try truncating facts if prompt too large
+		promptSpan.SetAttributes(attribute.Bool("prompt.truncated", true))
+		log.Warn("prompt over token budget, facts truncated", "max_tokens", s.config.LLM.MaxTokens, "error", err)
 		truncatedFacts := s.promptBuilder.TruncateFacts(facts)
 		prompt, err = s.promptBuilder.BuildPrompt(truncatedFacts)
 		if err != nil {
-			return "", fmt.Errorf("prompt build failed after truncation: %w", err)
+			endSpan(promptSpan, err)
+			return nil, fail(span, fmt.Errorf("prompt build failed after truncation: %w", err))
 		}
 	}
+	promptSpan.SetAttributes(attribute.Int("prompt.bytes", len(prompt)))
+	promptSpan.End()
 
 	// Step 4: Generate summary using LLM
-	result, err := s.engine.Generate(ctx, prompt)
+	genCtx, genSpan := telemetry.Tracer().Start(ctx, "inference.generate")
+	genStep := progress.Start(ctx, "inference.generate")
+	// Seed sampling deterministically from facts metadata
+	seed := inference.DeterministicSeed(facts.HardwareUUID, facts.Timestamp)
+	tokens := 0
+	result, err := s.generate(genCtx, prompt, seed, func(string) error {
+		tokens++
+		genStep.Progress(tokens)
+		return nil
+	})
+	if err == nil {
+		genSpan.SetAttributes(attribute.Int("inference.tokens", result.TokenCount))
+		genStep.SetTokens(result.TokenCount)
+		log.Debug("model output generated", "tokens", result.TokenCount, "prompt_bytes", len(prompt))
+	}
+	genStep.End(err)
+	endSpan(genSpan, err)
 	if err != nil {
-		return "", fmt.Errorf("inference failed: %w", err)
+		return nil, fail(span, fmt.Errorf("inference failed: %w", err))
 	}
 
 	// Step 5: Clean output
+	_, parseSpan := telemetry.Tracer().Start(ctx, "inference.parse")
+	parseStep := progress.Start(ctx, "inference.parse")
 	cleanedOutput := s.parser.CleanOutput(result.Text)
 
 	// Step 6: Parse structured output
 	parsed, err := s.parser.Parse(cleanedOutput)
 	if err != nil {
-		return "", fmt.Errorf("parsing failed: %w", err)
+		parseStep.End(err)
+		endSpan(parseSpan, err)
+		return nil, fail(span, fmt.Errorf("parsing failed: %w", err))
 	}
 
 	// Step 7: Validate output quality
-	if err := s.parser.Validate(parsed); err != nil {
-		return "", fmt.Errorf("validation failed: %w", err)
+	err = s.parser.Validate(parsed)
+	parseStep.End(err)
+	endSpan(parseSpan, err)
+	if err != nil {
+		return nil, fail(span, fmt.Errorf("validation failed: %w", err))
 	}
 
-	// Step 8: Detect hallucinations (best-effort)
-	factsJSON := fmt.Sprintf("%+v", facts) // Simple representation
+	// Step 8: Detect hallucinations and annotate confidence (best-effort)
+	_, reportSpan := telemetry.Tracer().Start(ctx, "report.build")
+	defer reportSpan.End()
+	factsData, err := facts.AppendJSON(nil, "")
+	if err != nil {
+		endSpan(reportSpan, err)
+		return nil, fail(span, fmt.Errorf("failed to marshal facts: %w", err))
+	}
+	factsJSON := string(factsData)
+	s.parser.AnnotateConfidence(parsed, factsJSON)
 	hallucinations := s.parser.DetectHallucination(parsed, factsJSON)
 	if len(hallucinations) > 0 {
-		// Log warnings but don't fail (best-effort detection)
-		// In production, log to file or metrics
-		_ = hallucinations
-	}
-
-	// Step 9: Format final report
-	report := s.formatReport(facts, parsed, result)
-
-	return report, nil
-}
-
-// updateEngineWithFacts updates the engine with facts-specific seed data
-func (s *Summarizer) updateEngineWithFacts(facts *collection.Facts) *inference.Engine {
-	// Create new inference config with facts metadata
-	inferenceConfig := &inference.InferenceConfig{
-		MaxTokens:    s.config.LLM.MaxTokens,
-		Temperature:  s.config.LLM.Temperature,
-		HardwareUUID: facts.HardwareUUID,
-		Timestamp:    facts.Timestamp,
-		ModelPath:    s.config.LLM.ModelPath,
-	}
-
-	// Create new engine with deterministic seed
-	engine, _ := inference.NewEngine(inferenceConfig)
-	return engine
-}
-
-// formatReport creates the final human-readable report
-func (s *Summarizer) formatReport(facts *collection.Facts, parsed *inference.ParsedOutput, result *inference.InferenceResult) string {
-	// Add header with metadata
-	header := fmt.Sprintf(`===== MINIBEAST SYSTEM REPORT =====
-
-Collection Date: %s
-Hostname: %s
-Hardware UUID: %s
-OS: %s %s
-Collection Time: %dms
-Inference Time: %dms
-Tokens Generated: %d
-
-`,
-		facts.Timestamp.Format("2006-01-02 15:04:05 UTC"),
-		facts.Hostname,
-		facts.HardwareUUID,
-		facts.OSName,
-		facts.OSVersion,
-		facts.CollectionDurationMs,
-		result.InferenceTime.Milliseconds(),
-		result.TokenCount,
-	)
+		// Warn but don't fail (best-effort detection)
+		reportSpan.SetAttributes(attribute.Int("report.hallucinations", len(hallucinations)))
+		log.Warn("model output mentions values absent from the facts", "count", len(hallucinations))
+	}
+
+	// Step 9: Add findings from rule sources (best-effort; failures show as a failed stage)
+	if len(s.rules) > 0 {
+		rulesStep := progress.Start(ctx, "rules")
+		rulesStep.End(s.mergeRules(ctx, facts, parsed))
+	}
+
+	// Step 10: Merge curated remediation for rule-engine findings
+	parsed.Actions = s.remediation.MergeActions(parsed.Actions, parsed.FindingIDs())
+
+	// Step 11: Assemble final report
+	return s.formatReport(facts, parsed, result), nil
+}
+
+// generate runs the engine with seed, without racing concurrent reports
+// With llm.grammar, engines that support it sample under ReportGrammar.
+// Streaming engines pass each sampled piece to fn.
+// Engines lacking GenerateSeeded are seeded and run one report at a time.
+func (s *Summarizer) generate(ctx context.Context, prompt string, seed int64, fn inference.TokenFunc) (*inference.InferenceResult, error) {
+	if g, ok := s.engine.(StreamingGenerator); ok {
+		grammar := ""
+		if s.config.LLM.Grammar {
+			grammar = inference.ReportGrammar
+		}
+		return g.GenerateConstrainedStream(ctx, prompt, grammar, seed, fn)
+	}
+	if g, ok := s.engine.(ConstrainedGenerator); ok && s.config.LLM.Grammar {
+		return g.GenerateConstrained(ctx, prompt, inference.ReportGrammar, seed)
+	}
+	if g, ok := s.engine.(SeededStreamGenerator); ok {
+		return g.GenerateSeededStream(ctx, prompt, seed, fn)
+	}
+	if g, ok := s.engine.(SeededGenerator); ok {
+		return g.GenerateSeeded(ctx, prompt, seed)
+	}
+	if seeder, ok := s.engine.(Seeder); ok {
+		s.seedMu.Lock()
+		defer s.seedMu.Unlock()
+		seeder.SetSeed(seed)
+	}
+	return s.engine.Generate(ctx, prompt)
+}
+
+// mergeRules appends each rule source's findings to parsed as grounded risks
+// A finding whose text the model already reported is attached to that risk.
+// Complexity: O(|findings| · |risks|)
+func (s *Summarizer) mergeRules(ctx context.Context, facts *collection.Facts, parsed *inference.ParsedOutput) error {
+	ctx, span := telemetry.Tracer().Start(ctx, "rules")
+	defer span.End()
+
+	var errs []error
+	added := 0
+	for _, source := range s.rules {
+		findings, err := source.Findings(ctx, facts)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for _, f := range findings {
+			if _, ok := parsed.FindingFor(f.Description); !ok && !slices.Contains(parsed.Risks, f.Description) {
+				parsed.Risks = append(parsed.Risks, f.Description)
+			}
+			parsed.Findings = append(parsed.Findings, f)
+			added++
+		}
+	}
+	span.SetAttributes(attribute.Int("rules.findings", added))
+	err := errors.Join(errs...)
+	endSpan(span, err)
+	return err
+}
+
+// Ask answers a follow-up question about facts (interactive mode)
+// The answer is free text; it is not parsed into a report.
+// Complexity: O(m) where m = maxTokens
+func (s *Summarizer) Ask(ctx context.Context, facts *collection.Facts, question string) (string, error) {
+	ctx, span := telemetry.Tracer().Start(ctx, "inference.ask")
+	defer span.End()
 
-	// Use parser to format the structured output
-	body := s.parser.Format(parsed)
+	if err := s.engine.Load(ctx); err != nil {
+		return "", fail(span, fmt.Errorf("%w: %w", ErrModelUnavailable, err))
+	}
+
+	prompt, err := s.promptBuilder.BuildQuestionPrompt(facts, question)
+	if err != nil {
+		return "", fail(span, err)
+	}
+	if err := s.promptBuilder.ValidateTokenCount(prompt, s.config.LLM.MaxTokens); err != nil {
+		if prompt, err = s.promptBuilder.BuildQuestionPrompt(s.promptBuilder.TruncateFacts(facts), question); err != nil {
+			return "", fail(span, err)
+		}
+	}
+
+	step := progress.Start(ctx, "inference.generate")
+	result, err := s.engine.Generate(ctx, prompt)
+	if err == nil {
+		span.SetAttributes(attribute.Int("inference.tokens", result.TokenCount))
+		step.SetTokens(result.TokenCount)
+	}
+	step.End(err)
+	if err != nil {
+		return "", fail(span, fmt.Errorf("inference failed: %w", err))
+	}
+	return s.parser.CleanOutput(result.Text), nil
+}
+
+// endSpan closes a phase span, marking it failed when err is non-nil
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// fail marks the parent span failed and passes err through
+func fail(span trace.Span, err error) error {
+	span.SetStatus(codes.Error, err.Error())
+	return err
+}
+
+// formatReport creates the final report with inference metadata
+// Enforces output.max_report_bytes via report.Fit (summary is never truncated)
+func (s *Summarizer) formatReport(facts *collection.Facts, parsed *inference.ParsedOutput, result *inference.InferenceResult) *report.Report {
+	rpt := report.Build(facts, parsed)
+
+	// Add inference metadata to the header
+	rpt.Header = append(rpt.Header,
+		report.Field{Label: "Collection Time", Value: fmt.Sprintf("%dms", facts.CollectionDurationMs)},
+		report.Field{Label: "Inference Time", Value: fmt.Sprintf("%dms", result.InferenceTime.Milliseconds())},
+		report.Field{Label: "Tokens Generated", Value: fmt.Sprintf("%d", result.TokenCount)},
+	)
 
-	// Combine header and body
-	return header + body
+	return rpt.Fit(s.config.Output.MaxReportBytes, s.config.Output.ReportTopRisks)
 }
 
 // Close releases resources
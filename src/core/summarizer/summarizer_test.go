@@ -0,0 +1,355 @@
+package summarizer_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+	"github.com/minibeast/usb-agent/src/core/config"
+	"github.com/minibeast/usb-agent/src/core/inference"
+	"github.com/minibeast/usb-agent/src/core/progress"
+	"github.com/minibeast/usb-agent/src/core/summarizer"
+)
+
+func testFacts() *collection.Facts {
+	return &collection.Facts{
+		Timestamp:    time.Date(2025, 11, 9, 12, 0, 0, 0, time.UTC),
+		Hostname:     "test-host",
+		HardwareUUID: "uuid-123",
+		OSName:       "Linux",
+		OSVersion:    "22.04",
+	}
+}
+
+// TestNewSummarizer_NilArgs verifies constructor validation
+func TestNewSummarizer_NilArgs(t *testing.T) {
+	if _, err := summarizer.NewSummarizer(nil, inference.NewFakeEngine()); err == nil {
+		t.Error("NewSummarizer() should fail for nil config")
+	}
+	if _, err := summarizer.NewSummarizer(config.Default(), nil); err == nil {
+		t.Error("NewSummarizer() should fail for nil engine")
+	}
+}
+
+// TestSummarize verifies the full pipeline against a fake engine
+func TestSummarize(t *testing.T) {
+	engine := inference.NewFakeEngine()
+	s, err := summarizer.NewSummarizer(config.Default(), engine)
+	if err != nil {
+		t.Fatalf("NewSummarizer() failed: %v", err)
+	}
+	defer s.Close()
+
+	report, err := s.Summarize(context.Background(), testFacts())
+	if err != nil {
+		t.Fatalf("Summarize() failed: %v", err)
+	}
+
+	for _, want := range []string{"Hostname: test-host", "SUMMARY:", "System profile collected successfully"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Report missing %q", want)
+		}
+	}
+
+	prompts := engine.Prompts()
+	if len(prompts) != 1 || !strings.Contains(prompts[0], "uuid-123") {
+		t.Error("Engine did not receive a prompt containing the facts")
+	}
+}
+
+// TestBuildReport_Progress verifies stage events bracket each inference step
+// and generation reports tokens as they stream
+func TestBuildReport_Progress(t *testing.T) {
+	s, err := summarizer.NewSummarizer(config.Default(), inference.NewFakeEngine())
+	if err != nil {
+		t.Fatalf("NewSummarizer() failed: %v", err)
+	}
+
+	var events []progress.Event
+	running := 0
+	ctx := progress.WithReporter(context.Background(), func(ev progress.Event) {
+		if ev.State == progress.Running {
+			running = ev.Tokens // Streamed tokens so far
+			return
+		}
+		events = append(events, ev)
+	})
+	if _, err := s.BuildReport(ctx, testFacts()); err != nil {
+		t.Fatalf("BuildReport() failed: %v", err)
+	}
+	if running == 0 {
+		t.Error("inference.generate reported no streamed tokens")
+	}
+
+	want := []string{
+		"inference.load started", "inference.load done",
+		"inference.generate started", "inference.generate done",
+		"inference.parse started", "inference.parse done",
+	}
+	if len(events) != len(want) {
+		t.Fatalf("Got %d events, want %d: %v", len(events), len(want), events)
+	}
+	for i, ev := range events {
+		if got := ev.Stage + " " + ev.State.String(); got != want[i] {
+			t.Errorf("Event %d = %q, want %q", i, got, want[i])
+		}
+	}
+	if events[3].Tokens == 0 {
+		t.Error("inference.generate done event missing token count")
+	}
+}
+
+// TestSummarize_Deterministic verifies same Facts produce the same report
+func TestSummarize_Deterministic(t *testing.T) {
+	s, err := summarizer.NewSummarizer(config.Default(), inference.NewFakeEngine())
+	if err != nil {
+		t.Fatalf("NewSummarizer() failed: %v", err)
+	}
+
+	first, err := s.Summarize(context.Background(), testFacts())
+	if err != nil {
+		t.Fatalf("Summarize() failed: %v", err)
+	}
+	second, err := s.Summarize(context.Background(), testFacts())
+	if err != nil {
+		t.Fatalf("Summarize() failed: %v", err)
+	}
+
+	if first != second {
+		t.Error("Same Facts produced different reports")
+	}
+}
+
+// TestSummarize_EngineErrors verifies load and generation failures propagate
+func TestSummarize_EngineErrors(t *testing.T) {
+	loadFail := inference.NewFakeEngine()
+	loadFail.LoadErr = errors.New("model missing")
+
+	genFail := inference.NewFakeEngine()
+	genFail.GenerateErr = errors.New("decode failed")
+
+	for name, engine := range map[string]*inference.FakeEngine{"load": loadFail, "generate": genFail} {
+		s, err := summarizer.NewSummarizer(config.Default(), engine)
+		if err != nil {
+			t.Fatalf("NewSummarizer() failed: %v", err)
+		}
+		if _, err := s.Summarize(context.Background(), testFacts()); err == nil {
+			t.Errorf("Summarize() should fail on %s error", name)
+		}
+	}
+}
+
+// TestSummarize_UnparseableOutput verifies malformed model output is rejected
+func TestSummarize_UnparseableOutput(t *testing.T) {
+	engine := inference.NewFakeEngine()
+	engine.Response = "I am a helpful assistant."
+
+	s, err := summarizer.NewSummarizer(config.Default(), engine)
+	if err != nil {
+		t.Fatalf("NewSummarizer() failed: %v", err)
+	}
+	if _, err := s.Summarize(context.Background(), testFacts()); err == nil {
+		t.Error("Summarize() should fail when no summary is present")
+	}
+}
+
+// TestAsk verifies follow-up questions are answered from the facts prompt
+func TestAsk(t *testing.T) {
+	engine := inference.NewFakeEngine()
+	engine.Response = "Assistant: The host runs Linux 22.04."
+	s, err := summarizer.NewSummarizer(config.Default(), engine)
+	if err != nil {
+		t.Fatalf("NewSummarizer() failed: %v", err)
+	}
+
+	answer, err := s.Ask(context.Background(), testFacts(), "Which OS version is installed?")
+	if err != nil {
+		t.Fatalf("Ask() failed: %v", err)
+	}
+	if answer != "The host runs Linux 22.04." {
+		t.Errorf("Ask() = %q, want cleaned model output", answer)
+	}
+
+	prompts := engine.Prompts()
+	if len(prompts) != 1 || !strings.Contains(prompts[0], "QUESTION:\nWhich OS version is installed?") || !strings.Contains(prompts[0], `"hostname": "test-host"`) {
+		t.Errorf("prompt missing question or facts: %q", prompts)
+	}
+
+	if _, err := s.Ask(context.Background(), testFacts(), "   "); err == nil {
+		t.Error("Ask() should reject an empty question")
+	}
+}
+
+// staticRules is a RuleSource returning fixed findings
+type staticRules struct {
+	findings []inference.Finding
+	err      error
+}
+
+func (r staticRules) Findings(ctx context.Context, facts *collection.Facts) ([]inference.Finding, error) {
+	return r.findings, r.err
+}
+
+// TestBuildReport_Rules verifies rule findings join the risks as grounded
+// and that a failing source fails only the rules stage
+func TestBuildReport_Rules(t *testing.T) {
+	s, err := summarizer.NewSummarizer(config.Default(), inference.NewFakeEngine())
+	if err != nil {
+		t.Fatalf("NewSummarizer() failed: %v", err)
+	}
+	s.AddRules(
+		staticRules{findings: []inference.Finding{{ID: "ACME-001", Description: "Disk encryption is disabled", Confidence: inference.ConfidenceGrounded}}},
+		staticRules{err: errors.New("plugin crashed")},
+	)
+
+	var failed []string
+	ctx := progress.WithReporter(context.Background(), func(ev progress.Event) {
+		if ev.State == progress.Failed {
+			failed = append(failed, ev.Stage)
+		}
+	})
+	rpt, err := s.BuildReport(ctx, testFacts())
+	if err != nil {
+		t.Fatalf("BuildReport() failed: %v", err)
+	}
+	if len(failed) != 1 || failed[0] != "rules" {
+		t.Errorf("Failed stages = %v, want [rules]", failed)
+	}
+
+	var found bool
+	for _, risk := range rpt.Risks {
+		if risk.FindingID == "ACME-001" {
+			found = true
+			if risk.Text != "Disk encryption is disabled" || risk.Confidence != inference.ConfidenceGrounded {
+				t.Errorf("Unexpected rule risk: %+v", risk)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("Rule finding missing from risks: %+v", rpt.Risks)
+	}
+}
+
+// seedRecorder is a Seeder-only engine recording the seed each prompt
+// was generated with; Generate reads the last SetSeed after a delay.
+type seedRecorder struct {
+	fake  *inference.FakeEngine
+	mu    sync.Mutex
+	seed  int64
+	seeds map[string]int64 // prompt → seed
+}
+
+func (r *seedRecorder) Load(ctx context.Context) error { return r.fake.Load(ctx) }
+func (r *seedRecorder) Unload() error                  { return r.fake.Unload() }
+
+func (r *seedRecorder) SetSeed(seed int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seed = seed
+}
+
+func (r *seedRecorder) Generate(ctx context.Context, prompt string) (*inference.InferenceResult, error) {
+	time.Sleep(time.Millisecond) // Widen the SetSeed/Generate window
+	r.mu.Lock()
+	seed := r.seed
+	r.mu.Unlock()
+	return r.record(ctx, prompt, seed)
+}
+
+func (r *seedRecorder) record(ctx context.Context, prompt string, seed int64) (*inference.InferenceResult, error) {
+	r.mu.Lock()
+	r.seeds[prompt] = seed
+	r.mu.Unlock()
+	return r.fake.GenerateSeeded(ctx, prompt, seed)
+}
+
+// perCallRecorder adds GenerateSeeded to seedRecorder
+type perCallRecorder struct{ *seedRecorder }
+
+func (r perCallRecorder) GenerateSeeded(ctx context.Context, prompt string, seed int64) (*inference.InferenceResult, error) {
+	return r.record(ctx, prompt, seed)
+}
+
+// TestBuildReport_Concurrent verifies parallel reports each use their own facts' seed
+func TestBuildReport_Concurrent(t *testing.T) {
+	engines := map[string]func(r *seedRecorder) summarizer.Engine{
+		"seeded generator": func(r *seedRecorder) summarizer.Engine { return perCallRecorder{r} },
+		"seeder only":      func(r *seedRecorder) summarizer.Engine { return r },
+	}
+	for name, wrap := range engines {
+		t.Run(name, func(t *testing.T) {
+			rec := &seedRecorder{fake: inference.NewFakeEngine(), seeds: map[string]int64{}}
+			s, err := summarizer.NewSummarizer(config.Default(), wrap(rec))
+			if err != nil {
+				t.Fatalf("NewSummarizer() failed: %v", err)
+			}
+			defer s.Close()
+
+			const hosts = 8
+			var wg sync.WaitGroup
+			errs := make([]error, hosts)
+			for i := range hosts {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					facts := testFacts()
+					facts.HardwareUUID = fmt.Sprintf("uuid-%d", i)
+					_, errs[i] = s.BuildReport(context.Background(), facts)
+				}()
+			}
+			wg.Wait()
+
+			for i, err := range errs {
+				if err != nil {
+					t.Fatalf("BuildReport(host %d) failed: %v", i, err)
+				}
+			}
+			if len(rec.seeds) != hosts {
+				t.Fatalf("Engine saw %d distinct prompts, want %d", len(rec.seeds), hosts)
+			}
+			for prompt, seed := range rec.seeds {
+				for i := range hosts {
+					uuid := fmt.Sprintf("uuid-%d", i)
+					if strings.Contains(prompt, uuid) {
+						if want := inference.DeterministicSeed(uuid, testFacts().Timestamp); seed != want {
+							t.Errorf("%s generated with seed %d, want %d", uuid, seed, want)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestBuildReport_Grammar verifies reports sample under the report grammar
+// unless llm.grammar is off, and that follow-up answers stay unconstrained
+func TestBuildReport_Grammar(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		cfg := config.Default()
+		cfg.LLM.Grammar = enabled
+		engine := inference.NewFakeEngine()
+		s, err := summarizer.NewSummarizer(cfg, engine)
+		if err != nil {
+			t.Fatalf("NewSummarizer() failed: %v", err)
+		}
+		if _, err := s.BuildReport(context.Background(), testFacts()); err != nil {
+			t.Fatalf("BuildReport() failed: %v", err)
+		}
+		if _, err := s.Ask(context.Background(), testFacts(), "Which OS?"); err != nil {
+			t.Fatalf("Ask() failed: %v", err)
+		}
+
+		want := ""
+		if enabled {
+			want = inference.ReportGrammar
+		}
+		if grammars := engine.Grammars(); len(grammars) != 2 || grammars[0] != want || grammars[1] != "" {
+			t.Errorf("llm.grammar=%v: grammars = %q", enabled, grammars)
+		}
+	}
+}
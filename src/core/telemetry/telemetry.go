@@ -0,0 +1,91 @@
+// Package telemetry configures OpenTelemetry tracing for the agent pipeline
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName scopes every span emitted by the agent
+const instrumentationName = "github.com/minibeast/usb-agent"
+
+// ShutdownFunc flushes pending spans and releases the exporter
+type ShutdownFunc func(ctx context.Context) error
+
+// Tracer returns the agent tracer (no-op until Setup installs a provider)
+// Complexity: O(1)
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Setup installs the global tracer provider described by cfg
+// Exporter "none" leaves the no-op provider in place. Callers must invoke
+// the returned ShutdownFunc before exit so batched spans are flushed.
+// Complexity: O(1)
+func Setup(ctx context.Context, cfg config.TelemetryConfig, version string) (ShutdownFunc, error) {
+	var exporter sdktrace.SpanExporter
+	var closeFile func() error
+
+	switch cfg.Exporter {
+	case "", "none":
+		return func(context.Context) error { return nil }, nil
+
+	case "otlp":
+		exp, err := otlptracehttp.New(ctx,
+			otlptracehttp.WithEndpointURL(cfg.OTLPEndpoint),
+			otlptracehttp.WithHeaders(cfg.OTLPHeaders),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		}
+		exporter = exp
+
+	case "file":
+		if err := os.MkdirAll(filepath.Dir(cfg.FilePath), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create trace directory: %w", err)
+		}
+		file, err := os.OpenFile(cfg.FilePath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open trace file: %w", err)
+		}
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to create file exporter: %w", err)
+		}
+		exporter, closeFile = exp, file.Close
+
+	default:
+		return nil, fmt.Errorf("unknown telemetry exporter: %q", cfg.Exporter)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", "minibeast"),
+			attribute.String("service.version", version),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closeFile != nil {
+			if cerr := closeFile(); err == nil {
+				err = cerr
+			}
+		}
+		return err
+	}, nil
+}
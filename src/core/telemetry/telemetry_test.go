@@ -0,0 +1,65 @@
+package telemetry
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+	"go.opentelemetry.io/otel"
+)
+
+func TestSetup_FileExporter(t *testing.T) {
+	prev := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(prev)
+
+	path := filepath.Join(t.TempDir(), "out", "traces.jsonl")
+	shutdown, err := Setup(context.Background(), config.TelemetryConfig{
+		Exporter: "file",
+		FilePath: path,
+	}, "test")
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+
+	ctx, parent := Tracer().Start(context.Background(), "collect")
+	_, child := Tracer().Start(ctx, "collect.system_info")
+	child.End()
+	parent.End()
+
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("trace file missing: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(lines))
+	}
+	for _, name := range []string{`"Name":"collect"`, `"Name":"collect.system_info"`, `"minibeast"`} {
+		if !strings.Contains(string(data), name) {
+			t.Errorf("trace file missing %s", name)
+		}
+	}
+}
+
+func TestSetup_None(t *testing.T) {
+	shutdown, err := Setup(context.Background(), config.TelemetryConfig{Exporter: "none"}, "test")
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown failed: %v", err)
+	}
+}
+
+func TestSetup_UnknownExporter(t *testing.T) {
+	if _, err := Setup(context.Background(), config.TelemetryConfig{Exporter: "zipkin"}, "test"); err == nil {
+		t.Error("expected error for unknown exporter")
+	}
+}
@@ -0,0 +1,128 @@
+// Package testsupport provides canonical JSON and golden-file helpers
+// Tests across collectors, diffing and exporters serialize results with
+// Canonical (sorted keys, fixed indentation, volatile fields masked) and
+// compare them against files under testdata/ with Golden, so assertions do
+// not depend on map order, wall-clock time or run IDs.
+package testsupport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/minibeast/usb-agent/src/core/collection"
+)
+
+// Masked replaces the value of every ignored field that is present
+const Masked = "<ignored>"
+
+// Option adjusts canonicalization
+type Option func(*options)
+
+type options struct {
+	ignore [][]string // Split paths
+}
+
+// Ignore masks the fields at the given paths
+// A path is dot-separated object keys; "[]" applies the rest of the path to
+// every array element (e.g., "recent_profiles[].last_logon"). Masked fields
+// keep their key, so a field that disappears still fails the comparison.
+func Ignore(paths ...string) Option {
+	return func(o *options) {
+		for _, p := range paths {
+			p = strings.ReplaceAll(p, "[]", ".[]")
+			o.ignore = append(o.ignore, strings.Split(p, "."))
+		}
+	}
+}
+
+// IgnoreTimestamps masks the per-run fields of Facts: collection time and
+// duration, and the run ID
+func IgnoreTimestamps() Option {
+	return Ignore("timestamp", "collection_duration_ms", "run_id")
+}
+
+// Canonical returns v as deterministic JSON: object keys sorted, two-space
+// indentation, numbers kept verbatim and a trailing newline
+// Complexity: O(|v| + |ignore| * depth)
+func Canonical(v any, opts ...Option) ([]byte, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal: %w", err)
+	}
+	return canonicalize(raw, &o)
+}
+
+// CanonicalJSON is Canonical for bytes that are already JSON (e.g., an
+// exporter's request body or an artifact read back from disk)
+// Complexity: O(|data| + |ignore| * depth)
+func CanonicalJSON(data []byte, opts ...Option) ([]byte, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return canonicalize(data, &o)
+}
+
+// CanonicalFacts is Canonical for Facts
+// Complexity: O(|Facts|)
+func CanonicalFacts(f *collection.Facts, opts ...Option) ([]byte, error) {
+	return Canonical(f, opts...)
+}
+
+// canonicalize decodes data generically, masks ignored fields and re-encodes
+// it (encoding/json sorts map keys)
+func canonicalize(data []byte, o *options) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var tree any
+	if err := dec.Decode(&tree); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	for _, path := range o.ignore {
+		mask(tree, path)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(tree); err != nil {
+		return nil, fmt.Errorf("failed to encode: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// mask replaces the value at path within node, if present
+func mask(node any, path []string) {
+	if len(path) == 0 {
+		return
+	}
+	switch n := node.(type) {
+	case map[string]any:
+		child, ok := n[path[0]]
+		if !ok {
+			return
+		}
+		if len(path) == 1 {
+			n[path[0]] = Masked
+			return
+		}
+		mask(child, path[1:])
+	case []any:
+		if path[0] != "[]" {
+			return
+		}
+		for i := range n {
+			if len(path) == 1 {
+				n[i] = Masked
+			} else {
+				mask(n[i], path[1:])
+			}
+		}
+	}
+}
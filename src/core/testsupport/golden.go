@@ -0,0 +1,96 @@
+package testsupport
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// UpdateEnv rewrites golden files instead of comparing when set to "1"
+// (e.g., MINIBEAST_UPDATE_GOLDEN=1 go test ./src/core/export/)
+const UpdateEnv = "MINIBEAST_UPDATE_GOLDEN"
+
+// FixturePath returns the path of a fixture under the test's testdata/
+func FixturePath(name string) string {
+	return filepath.Join("testdata", filepath.FromSlash(name))
+}
+
+// LoadFixture reads testdata/<name>, failing the test if it is missing
+func LoadFixture(t testing.TB, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(FixturePath(name))
+	if err != nil {
+		t.Fatalf("failed to load fixture: %v", err)
+	}
+	return data
+}
+
+// Golden compares the canonical JSON of got with testdata/<name>
+// With UpdateEnv set the file is (re)written and the test passes.
+// Complexity: O(|got| + |golden|)
+func Golden(t testing.TB, name string, got any, opts ...Option) {
+	t.Helper()
+	data, err := Canonical(got, opts...)
+	if err != nil {
+		t.Fatalf("golden %s: %v", name, err)
+	}
+	GoldenBytes(t, name, data)
+}
+
+// GoldenBytes compares got with testdata/<name> byte for byte
+// Complexity: O(|got| + |golden|)
+func GoldenBytes(t testing.TB, name string, got []byte) {
+	t.Helper()
+	path := FixturePath(name)
+	if os.Getenv(UpdateEnv) == "1" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("golden %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("golden %s: %v", name, err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("golden %s: %v (run with %s=1 to create it)", name, err, UpdateEnv)
+	}
+	if diff := Diff(want, got); diff != "" {
+		t.Errorf("golden %s mismatch (-want +got):\n%s\nrun with %s=1 to accept", name, diff, UpdateEnv)
+	}
+}
+
+// Diff returns the differing lines of want and got, or "" when equal
+// Lines are compared position by position, which suits canonical JSON where
+// a changed value keeps every other line in place.
+// Complexity: O(|want| + |got|)
+func Diff(want, got []byte) string {
+	if bytes.Equal(want, got) {
+		return ""
+	}
+	wl := strings.Split(string(want), "\n")
+	gl := strings.Split(string(got), "\n")
+	var b strings.Builder
+	for i := 0; i < len(wl) || i < len(gl); i++ {
+		var w, g string
+		if i < len(wl) {
+			w = wl[i]
+		}
+		if i < len(gl) {
+			g = gl[i]
+		}
+		if w == g {
+			continue
+		}
+		if i < len(wl) {
+			fmt.Fprintf(&b, "%4d - %s\n", i+1, w)
+		}
+		if i < len(gl) {
+			fmt.Fprintf(&b, "%4d + %s\n", i+1, g)
+		}
+	}
+	return b.String()
+}
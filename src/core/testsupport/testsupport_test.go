@@ -0,0 +1,67 @@
+package testsupport
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestCanonicalJSON_SortsAndMasks verifies key order, masking and number fidelity
+func TestCanonicalJSON_SortsAndMasks(t *testing.T) {
+	in := `{"z":1,"timestamp":"2025-01-01T00:00:00Z","big":9007199254740993,
+		"profiles":[{"name":"a","last":"x"},{"name":"b","last":"y"}],"nested":{"b":true,"a":null}}`
+	got, err := CanonicalJSON([]byte(in), Ignore("timestamp", "profiles[].last", "missing.field"))
+	if err != nil {
+		t.Fatalf("CanonicalJSON() failed: %v", err)
+	}
+	want := `{
+  "big": 9007199254740993,
+  "nested": {
+    "a": null,
+    "b": true
+  },
+  "profiles": [
+    {
+      "last": "<ignored>",
+      "name": "a"
+    },
+    {
+      "last": "<ignored>",
+      "name": "b"
+    }
+  ],
+  "timestamp": "<ignored>",
+  "z": 1
+}
+`
+	if string(got) != want {
+		t.Errorf("CanonicalJSON() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+// TestCanonical_Stable verifies map order never changes the output
+func TestCanonical_Stable(t *testing.T) {
+	v := map[string]int{"c": 3, "a": 1, "b": 2}
+	first, err := Canonical(v)
+	if err != nil {
+		t.Fatalf("Canonical() failed: %v", err)
+	}
+	for i := 0; i < 20; i++ {
+		if again, _ := Canonical(v); string(again) != string(first) {
+			t.Fatalf("Canonical() changed between calls:\n%s\n%s", first, again)
+		}
+	}
+	if _, err := CanonicalJSON([]byte("{")); err == nil {
+		t.Error("CanonicalJSON() accepted invalid JSON")
+	}
+}
+
+// TestDiff verifies only differing lines are reported
+func TestDiff(t *testing.T) {
+	if d := Diff([]byte("a\nb\n"), []byte("a\nb\n")); d != "" {
+		t.Errorf("Diff() of equal input = %q", d)
+	}
+	d := Diff([]byte("a\nb\nc"), []byte("a\nB\nc\nd"))
+	if !strings.Contains(d, "2 - b") || !strings.Contains(d, "2 + B") || !strings.Contains(d, "4 + d") || strings.Contains(d, " a") {
+		t.Errorf("Diff() = %q", d)
+	}
+}
@@ -0,0 +1,139 @@
+// Package usagestats reports opt-in anonymous usage statistics
+// A record carries only operational facts: the agent version, OS and
+// architecture, the UTC day, per-category collection durations and the
+// run's failure class. Hostnames, identifiers, run IDs, engagement metadata
+// and collected values are never included, so a record cannot be tied back
+// to a machine or an engagement.
+package usagestats
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+)
+
+// sendTimeout bounds the endpoint POST so statistics never delay a run
+const sendTimeout = 5 * time.Second
+
+// Record is one run's statistics
+type Record struct {
+	Version     string     `json:"version"`    // Collector version
+	OS          string     `json:"os"`         // runtime.GOOS
+	Arch        string     `json:"arch"`       // runtime.GOARCH
+	Day         string     `json:"day"`        // UTC date of the run (YYYY-MM-DD), never a timestamp
+	Outcome     string     `json:"outcome"`    // "ok" or the failure class (e.g., "partial", "model")
+	Categories  []Category `json:"categories"` // Sorted by name
+	InferenceMs int64      `json:"inference_ms"`
+	TotalMs     int64      `json:"total_ms"`
+}
+
+// Category is one collection category's timing
+type Category struct {
+	Name       string `json:"name"` // e.g., "hardware_info"
+	DurationMs int64  `json:"duration_ms"`
+	Failed     bool   `json:"failed,omitempty"`
+}
+
+// NewRecord builds a record for a run on this platform at now
+// Complexity: O(c log c) where c = |categories|
+func NewRecord(version, outcome string, categories []Category, inference, total time.Duration, now time.Time) *Record {
+	cats := append([]Category{}, categories...)
+	sort.Slice(cats, func(i, j int) bool { return cats[i].Name < cats[j].Name })
+	return &Record{
+		Version:     version,
+		OS:          runtime.GOOS,
+		Arch:        runtime.GOARCH,
+		Day:         now.UTC().Format(time.DateOnly),
+		Outcome:     outcome,
+		Categories:  cats,
+		InferenceMs: inference.Milliseconds(),
+		TotalMs:     total.Milliseconds(),
+	}
+}
+
+// Reporter delivers records to the configured endpoint and file
+type Reporter struct {
+	cfg    config.UsageStatsConfig
+	client *http.Client
+}
+
+// New returns a reporter, or nil when usage statistics are disabled
+// Complexity: O(1)
+func New(cfg config.UsageStatsConfig) *Reporter {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &Reporter{cfg: cfg, client: &http.Client{Timeout: sendTimeout}}
+}
+
+// Send appends rec to the file and POSTs it to the endpoint (either may be unset)
+// Both are attempted; a nil reporter sends nothing.
+// Complexity: O(|rec|) plus one request
+func (r *Reporter) Send(ctx context.Context, rec *Record) error {
+	if r == nil {
+		return nil
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to encode usage record: %w", err)
+	}
+
+	var errs []error
+	if r.cfg.FilePath != "" {
+		if err := appendLine(r.cfg.FilePath, line); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if r.cfg.Endpoint != "" {
+		if err := r.post(ctx, line); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// post sends one record to the endpoint
+func (r *Reporter) post(ctx context.Context, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.cfg.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create usage request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send usage record: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("usage endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+// appendLine appends one JSON line to path, creating it if needed
+func appendLine(path string, line []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create usage directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open usage file: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write usage file: %w", err)
+	}
+	return nil
+}
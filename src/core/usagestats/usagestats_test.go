@@ -0,0 +1,102 @@
+package usagestats
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minibeast/usb-agent/src/core/config"
+)
+
+// testRecord returns a record with categories out of order
+func testRecord() *Record {
+	now := time.Date(2026, 3, 14, 23, 30, 0, 0, time.FixedZone("PDT", -7*3600))
+	return NewRecord("1.0.0", "partial", []Category{
+		{Name: "pii_info", DurationMs: 40},
+		{Name: "hardware_info", DurationMs: 12, Failed: true},
+	}, 2*time.Second, 3*time.Second, now)
+}
+
+// TestNewRecord verifies ordering and day-level time resolution
+func TestNewRecord(t *testing.T) {
+	rec := testRecord()
+	if rec.Day != "2026-03-15" {
+		t.Errorf("Day = %q, want the UTC date 2026-03-15", rec.Day)
+	}
+	if rec.Categories[0].Name != "hardware_info" {
+		t.Errorf("categories not sorted: %+v", rec.Categories)
+	}
+	if rec.InferenceMs != 2000 || rec.TotalMs != 3000 {
+		t.Errorf("durations = %d/%d ms", rec.InferenceMs, rec.TotalMs)
+	}
+}
+
+// TestNew_DisabledSendsNothing verifies the reporter is strictly opt-in
+func TestNew_DisabledSendsNothing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.jsonl")
+	r := New(config.UsageStatsConfig{FilePath: path})
+	if r != nil {
+		t.Fatal("New() returned a reporter with usage statistics disabled")
+	}
+	if err := r.Send(context.Background(), testRecord()); err != nil {
+		t.Fatalf("Send() on a nil reporter failed: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("disabled reporter wrote a file")
+	}
+}
+
+// TestSend verifies records reach both the file and the endpoint
+func TestSend(t *testing.T) {
+	var posted []byte
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		posted, _ = io.ReadAll(req.Body)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "out", "usage.jsonl")
+	r := New(config.UsageStatsConfig{Enabled: true, Endpoint: srv.URL, FilePath: path})
+	r.client = srv.Client()
+	for i := 0; i < 2; i++ {
+		if err := r.Send(context.Background(), testRecord()); err != nil {
+			t.Fatalf("Send() failed: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("file has %d records, want 2", len(lines))
+	}
+	var rec Record
+	if err := json.Unmarshal(posted, &rec); err != nil {
+		t.Fatalf("posted body is not a record: %v", err)
+	}
+	if rec.Outcome != "partial" || lines[1] != strings.TrimSpace(string(posted)) {
+		t.Errorf("posted %s, file has %s", posted, lines[1])
+	}
+}
+
+// TestSend_EndpointError verifies a rejected record is reported
+func TestSend_EndpointError(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	r := New(config.UsageStatsConfig{Enabled: true, Endpoint: srv.URL})
+	r.client = srv.Client()
+	if err := r.Send(context.Background(), testRecord()); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("Send() = %v, want the 503 status", err)
+	}
+}
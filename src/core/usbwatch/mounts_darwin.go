@@ -0,0 +1,42 @@
+//go:build darwin
+
+package usbwatch
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// volumesDir is where macOS mounts external volumes
+const volumesDir = "/Volumes"
+
+// Mounts returns the volumes under /Volumes (the boot volume symlink is skipped)
+// Complexity: O(|volumes|)
+func Mounts() ([]string, error) {
+	entries, err := os.ReadDir(volumesDir)
+	if err != nil {
+		return nil, err
+	}
+	points := []string{}
+	for _, entry := range entries {
+		if entry.Type()&os.ModeSymlink != 0 {
+			continue
+		}
+		points = append(points, filepath.Join(volumesDir, entry.Name()))
+	}
+	return points, nil
+}
+
+// IsRemovable reports whether path is on an external volume under /Volumes
+// Heuristic: macOS mounts every non-boot volume there (the boot volume is a
+// symlink to /); only volumes carrying the stick layout are acted on.
+func IsRemovable(path string) bool {
+	rel, ok := strings.CutPrefix(filepath.Clean(path), volumesDir+"/")
+	if !ok {
+		return false
+	}
+	volume := filepath.Join(volumesDir, strings.SplitN(rel, "/", 2)[0])
+	info, err := os.Lstat(volume)
+	return err == nil && info.Mode()&os.ModeSymlink == 0
+}
@@ -0,0 +1,118 @@
+//go:build linux
+
+package usbwatch
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// mountsFile lists the mounted filesystems of this process's namespace
+const mountsFile = "/proc/self/mounts"
+
+// Mounts returns the mount points backed by block devices
+// Complexity: O(|mounts|)
+func Mounts() ([]string, error) {
+	entries, err := blockMounts()
+	if err != nil {
+		return nil, err
+	}
+	points := make([]string, 0, len(entries))
+	for point := range entries {
+		points = append(points, point)
+	}
+	return points, nil
+}
+
+// IsRemovable reports whether path lives on removable or USB-attached media
+// The containing mount is the longest mount point prefixing path. A disk
+// counts when sysfs flags it removable or its device path is on a USB bus
+// (USB hard drives and many sticks report removable=0).
+func IsRemovable(path string) bool {
+	entries, err := blockMounts()
+	if err != nil {
+		return false
+	}
+	device := ""
+	longest := -1
+	for point, dev := range entries {
+		if within(path, point) && len(point) > longest {
+			device, longest = dev, len(point)
+		}
+	}
+	if device == "" {
+		return false
+	}
+
+	sysPath, err := filepath.EvalSymlinks(filepath.Join("/sys/class/block", filepath.Base(device)))
+	if err != nil {
+		return false
+	}
+	if _, err := os.Stat(filepath.Join(sysPath, "partition")); err == nil {
+		sysPath = filepath.Dir(sysPath) // Partition → whole disk
+	}
+	if strings.Contains(sysPath, "/usb") {
+		return true
+	}
+	flag, err := os.ReadFile(filepath.Join(sysPath, "removable"))
+	return err == nil && strings.TrimSpace(string(flag)) == "1"
+}
+
+// within reports whether path is mountPoint or below it
+func within(path, mountPoint string) bool {
+	rel, err := filepath.Rel(mountPoint, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, "../")
+}
+
+// blockMounts maps mount points to their /dev device
+func blockMounts() (map[string]string, error) {
+	f, err := os.Open(mountsFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	entries := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || !strings.HasPrefix(fields[0], "/dev/") {
+			continue
+		}
+		device, err := filepath.EvalSymlinks(fields[0])
+		if err != nil {
+			device = fields[0]
+		}
+		entries[unescapeMount(fields[1])] = device
+	}
+	return entries, scanner.Err()
+}
+
+// unescapeMount decodes the octal escapes (\040 for space) used in /proc/mounts
+func unescapeMount(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+4 <= len(s) && isOctal(s[i+1:i+4]) {
+			b.WriteByte((s[i+1]-'0')<<6 | (s[i+2]-'0')<<3 | (s[i+3] - '0'))
+			i += 3
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// isOctal reports whether s is three octal digits
+func isOctal(s string) bool {
+	for _, c := range []byte(s) {
+		if c < '0' || c > '7' {
+			return false
+		}
+	}
+	return len(s) == 3
+}
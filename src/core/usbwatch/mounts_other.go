@@ -0,0 +1,15 @@
+//go:build !linux && !windows && !darwin
+
+package usbwatch
+
+import "fmt"
+
+// Mounts is unavailable on this platform
+func Mounts() ([]string, error) {
+	return nil, fmt.Errorf("volume detection is not supported on this platform")
+}
+
+// IsRemovable always reports false on this platform
+func IsRemovable(path string) bool {
+	return false
+}
@@ -0,0 +1,40 @@
+//go:build windows
+
+package usbwatch
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// Mounts returns the root of every present drive letter (e.g., "E:\")
+// Complexity: O(26)
+func Mounts() ([]string, error) {
+	mask, err := windows.GetLogicalDrives()
+	if err != nil {
+		return nil, err
+	}
+	points := []string{}
+	for i := 0; i < 26; i++ {
+		if mask&(1<<uint(i)) != 0 {
+			points = append(points, string(rune('A'+i))+`:\`)
+		}
+	}
+	return points, nil
+}
+
+// IsRemovable reports whether the drive holding path is removable media
+func IsRemovable(path string) bool {
+	root, err := windows.UTF16PtrFromString(volumeRoot(path))
+	if err != nil {
+		return false
+	}
+	return windows.GetDriveType(root) == windows.DRIVE_REMOVABLE
+}
+
+// volumeRoot reduces a path to its drive root ("E:\tools" → "E:\")
+func volumeRoot(path string) string {
+	if len(path) >= 2 && path[1] == ':' {
+		return path[:2] + `\`
+	}
+	return path
+}
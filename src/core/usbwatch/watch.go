@@ -0,0 +1,153 @@
+// Package usbwatch starts collection when the agent's removable volume appears
+package usbwatch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	coreio "github.com/minibeast/usb-agent/src/core/io"
+)
+
+// Marker identifies a MiniBeast stick: the agent config at its root
+const Marker = "config/default.yaml"
+
+// IsAgentVolume reports whether root carries the MiniBeast stick layout
+// Complexity: O(1)
+func IsAgentVolume(root string) bool {
+	info, err := os.Stat(filepath.Join(root, filepath.FromSlash(Marker)))
+	return err == nil && info.Mode().IsRegular()
+}
+
+// StartedFromRemovable returns the stick root when the running binary lives on one
+// The root is the nearest ancestor of the executable carrying Marker.
+// Complexity: O(depth of the executable path)
+func StartedFromRemovable() (string, bool) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", false
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+
+	for dir := filepath.Dir(exe); ; {
+		if IsAgentVolume(dir) {
+			return dir, IsRemovable(dir)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// Handler runs the pipeline against a detected stick root
+type Handler func(ctx context.Context, root string) error
+
+// Watcher polls mounted volumes and runs a Handler once per stick insertion
+// A stick is handled when it is first seen (including sticks already mounted
+// at startup) and becomes eligible again after it is unmounted. Handlers run
+// sequentially on the polling goroutine.
+type Watcher struct {
+	interval time.Duration
+	handle   Handler
+	report   func(root string, err error)
+
+	// Platform hooks (replaced in tests)
+	mounts    func() ([]string, error)
+	removable func(path string) bool
+}
+
+// New creates a watcher; report (optional) receives every handler outcome
+// Complexity: O(1)
+func New(interval time.Duration, handle Handler, report func(root string, err error)) (*Watcher, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("poll interval must be positive")
+	}
+	if handle == nil {
+		return nil, fmt.Errorf("handler cannot be nil")
+	}
+	return &Watcher{interval: interval, handle: handle, report: report, mounts: Mounts, removable: IsRemovable}, nil
+}
+
+// Run polls until ctx is cancelled
+// Complexity: O(|mounts|) per poll
+func (w *Watcher) Run(ctx context.Context) error {
+	seen := map[string]bool{}
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.poll(ctx, seen); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll handles newly inserted sticks and forgets unmounted ones
+func (w *Watcher) poll(ctx context.Context, seen map[string]bool) error {
+	points, err := w.mounts()
+	if err != nil {
+		return fmt.Errorf("failed to list volumes: %w", err)
+	}
+	sort.Strings(points)
+
+	present := make(map[string]bool, len(points))
+	for _, root := range points {
+		present[root] = true
+		// Not marked seen until the layout is readable (mounts can lag the device)
+		if seen[root] || !IsAgentVolume(root) || !w.removable(root) {
+			continue
+		}
+		seen[root] = true
+
+		err := w.handle(ctx, root)
+		if w.report != nil {
+			w.report(root, err)
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+
+	for root := range seen {
+		if !present[root] {
+			delete(seen, root)
+		}
+	}
+	return nil
+}
+
+// ClearDone removes a completion marker left by a previous run
+func ClearDone(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// WriteDone atomically writes the completion marker the operator waits for
+// Format: "key: value" lines (status, finished, artifacts, error).
+// Complexity: O(1)
+func WriteDone(path string, finished time.Time, artifacts int, runErr error) error {
+	status := "ok"
+	if runErr != nil {
+		status = "failed"
+	}
+	content := fmt.Sprintf("MiniBeast run complete - safe to remove\nstatus: %s\nfinished: %s\nartifacts: %d\n",
+		status, finished.UTC().Format(time.RFC3339), artifacts)
+	if runErr != nil {
+		content += "error: " + runErr.Error() + "\n"
+	}
+	return coreio.NewWriter().WriteAtomic(path, []byte(content), 0644)
+}
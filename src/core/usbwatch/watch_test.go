@@ -0,0 +1,103 @@
+package usbwatch
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newStick creates a directory carrying the stick layout
+func newStick(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "config"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, filepath.FromSlash(Marker)), []byte("collect: {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+// TestWatcher_HandlesEachInsertionOnce verifies handling on insertion, not on every poll
+func TestWatcher_HandlesEachInsertionOnce(t *testing.T) {
+	stick := newStick(t)
+	other := t.TempDir() // Mounted, but not a MiniBeast stick
+	fixed := newStick(t) // Stick layout on a non-removable disk
+
+	mounted := []string{stick, other, fixed}
+	handled := []string{}
+
+	w, err := New(time.Second, func(ctx context.Context, root string) error {
+		handled = append(handled, root)
+		return nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	w.mounts = func() ([]string, error) { return mounted, nil }
+	w.removable = func(path string) bool { return path != fixed }
+
+	seen := map[string]bool{}
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if err := w.poll(ctx, seen); err != nil {
+			t.Fatalf("poll() failed: %v", err)
+		}
+	}
+	if len(handled) != 1 || handled[0] != stick {
+		t.Fatalf("handled = %v, want [%s] once", handled, stick)
+	}
+
+	// Unplug, then plug back in
+	mounted = []string{other, fixed}
+	w.poll(ctx, seen)
+	mounted = []string{stick, other, fixed}
+	w.poll(ctx, seen)
+	if len(handled) != 2 {
+		t.Errorf("Re-inserted stick handled %d times in total, want 2", len(handled))
+	}
+}
+
+// TestWatcher_ListError verifies volume listing failures stop the watcher
+func TestWatcher_ListError(t *testing.T) {
+	w, _ := New(time.Second, func(context.Context, string) error { return nil }, nil)
+	w.mounts = func() ([]string, error) { return nil, errors.New("boom") }
+
+	if err := w.Run(context.Background()); err == nil {
+		t.Error("Run() should fail when volumes cannot be listed")
+	}
+}
+
+// TestWriteDone verifies the completion marker records status and replaces stale markers
+func TestWriteDone(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "DONE")
+	finished := time.Date(2025, 11, 9, 12, 0, 0, 0, time.UTC)
+
+	if err := WriteDone(path, finished, 3, nil); err != nil {
+		t.Fatalf("WriteDone() failed: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "status: ok") || !strings.Contains(string(data), "finished: 2025-11-09T12:00:00Z") {
+		t.Errorf("Unexpected marker:\n%s", data)
+	}
+
+	if err := WriteDone(path, finished, 0, errors.New("collection failed")); err != nil {
+		t.Fatalf("WriteDone() failed: %v", err)
+	}
+	data, _ = os.ReadFile(path)
+	if !strings.Contains(string(data), "status: failed") || !strings.Contains(string(data), "error: collection failed") {
+		t.Errorf("Unexpected failure marker:\n%s", data)
+	}
+
+	if err := ClearDone(path); err != nil {
+		t.Fatalf("ClearDone() failed: %v", err)
+	}
+	if err := ClearDone(path); err != nil {
+		t.Errorf("ClearDone() on a missing marker failed: %v", err)
+	}
+}